@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// AuthTokensCmd represents the /1.0/auth/tokens API on MicroCloud.
+// API tokens can only be managed with mTLS, and never with another API token.
+var AuthTokensCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "auth/tokens",
+		Path: "auth/tokens",
+
+		Get:  rest.EndpointAction{Handler: authHandlerMTLS(sh, authTokensGet)},
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, authTokensPost)},
+	}
+}
+
+// AuthTokenCmd represents the /1.0/auth/tokens/{name} API on MicroCloud.
+var AuthTokenCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "auth/tokens/{name}",
+		Path: "auth/tokens/{name}",
+
+		Delete: rest.EndpointAction{Handler: authHandlerMTLS(sh, authTokenDelete)},
+	}
+}
+
+// authToken converts a stored API token to its API representation, without its hash.
+func authToken(record database.AuthToken) types.AuthToken {
+	return types.AuthToken{
+		Name:      record.Name,
+		Role:      record.Role,
+		CreatedAt: record.CreatedAt,
+		ExpiresAt: record.ExpiresAt,
+	}
+}
+
+// checkAuthToken checks the API token exists, hasn't expired, and allows the given role.
+func checkAuthToken(ctx context.Context, s state.State, token string, role string) error {
+	var record *database.AuthToken
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		record, err = database.GetAuthTokenByHash(ctx, tx, service.AuthTokenHash(token))
+
+		return err
+	})
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return api.StatusErrorf(http.StatusForbidden, "Invalid API token")
+		}
+
+		return err
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return api.StatusErrorf(http.StatusForbidden, "API token %q has expired", record.Name)
+	}
+
+	if !service.AuthTokenAllows(record.Role, role) {
+		return api.StatusErrorf(http.StatusForbidden, "API token %q with role %q is not allowed to run requests requiring role %q", record.Name, record.Role, role)
+	}
+
+	return nil
+}
+
+// authTokensGet returns the API tokens, without their secrets.
+func authTokensGet(s state.State, r *http.Request) response.Response {
+	var records []database.AuthToken
+	err := s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetAuthTokens(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	tokens := make([]types.AuthToken, 0, len(records))
+	for _, record := range records {
+		tokens = append(tokens, authToken(record))
+	}
+
+	return response.SyncResponse(true, tokens)
+}
+
+// authTokensPost creates an API token, and returns it with its secret.
+// The secret is only returned here, as only its hash is stored.
+func authTokensPost(s state.State, r *http.Request) response.Response {
+	args := types.AuthTokensPost{}
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = service.ValidateAuthTokenName(args.Name)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid API token name %q: %w", args.Name, err))
+	}
+
+	err = service.ValidateAuthTokenRole(args.Role)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	createdAt := time.Now().UTC()
+	expiresAt, err := service.AuthTokenExpiry(createdAt, args.Expiry)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	token, hash, err := service.NewAuthToken()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	record := database.AuthToken{Name: args.Name, Role: args.Role, TokenHash: hash, CreatedAt: createdAt, ExpiresAt: expiresAt}
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		records, err := database.GetAuthTokens(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, existing := range records {
+			if existing.Name == args.Name {
+				return api.StatusErrorf(http.StatusConflict, "API token %q already exists", args.Name)
+			}
+		}
+
+		return database.CreateAuthToken(ctx, tx, record)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	RecordEvent(r.Context(), s, types.EventAuthToken, fmt.Sprintf("Created API token %q with role %q, expiring at %s", args.Name, args.Role, expiresAt.Format(time.RFC3339)))
+
+	return response.SyncResponse(true, types.AuthTokenSecret{AuthToken: authToken(record), Token: token})
+}
+
+// authTokenDelete revokes the API token with the given name.
+func authTokenDelete(s state.State, r *http.Request) response.Response {
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		return database.DeleteAuthToken(ctx, tx, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	RecordEvent(r.Context(), s, types.EventAuthToken, fmt.Sprintf("Revoked API token %q", name))
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// DefaultBenchmarkRuntime is the duration of each fio test if none is requested.
+const DefaultBenchmarkRuntime = 10 * time.Second
+
+// maximumBenchmarkRuntime is the longest duration of each fio test accepted by the daemon.
+const maximumBenchmarkRuntime = 5 * time.Minute
+
+// BenchmarksCmd represents the /1.0/benchmarks API on MicroCloud.
+var BenchmarksCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "benchmarks",
+
+		Get:  rest.EndpointAction{Handler: authHandlerMTLS(sh, benchmarksGet)},
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, benchmarksPost(sh)), ProxyTarget: true},
+	}
+}
+
+// benchmarksGet returns the recorded storage performance baseline of all cluster members.
+func benchmarksGet(state state.State, r *http.Request) response.Response {
+	var records []database.Benchmark
+	err := state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetBenchmarks(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	benchmarks := make([]types.Benchmark, 0, len(records))
+	for _, record := range records {
+		benchmarks = append(benchmarks, types.Benchmark{
+			Member:    record.Member,
+			Pool:      record.Pool,
+			Test:      record.Test,
+			IOPS:      record.IOPS,
+			Bandwidth: record.Bandwidth,
+			Latency:   record.Latency,
+			CreatedAt: record.CreatedAt,
+		})
+	}
+
+	return response.SyncResponse(true, benchmarks)
+}
+
+// benchmarksPost benchmarks the storage pools of this cluster member, and optionally records the results as its new baseline.
+func benchmarksPost(sh *service.Handler) func(state state.State, r *http.Request) response.Response {
+	return func(state state.State, r *http.Request) response.Response {
+		args := types.BenchmarksPost{}
+		err := json.NewDecoder(r.Body).Decode(&args)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		runtime := DefaultBenchmarkRuntime
+		if args.Runtime != 0 {
+			runtime = time.Duration(args.Runtime) * time.Second
+		}
+
+		if runtime < time.Second || runtime > maximumBenchmarkRuntime {
+			return response.BadRequest(fmt.Errorf("Runtime must be between 1s and %s", maximumBenchmarkRuntime))
+		}
+
+		lxd, ok := sh.Services[types.LXD].(*service.LXDService)
+		if !ok {
+			return response.BadRequest(errors.New("LXD is not installed on this system"))
+		}
+
+		pools := args.Pools
+		if len(pools) == 0 {
+			pools, err = lxd.BenchmarkPools(r.Context())
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			if len(pools) == 0 {
+				return response.BadRequest(errors.New("No storage pools to benchmark"))
+			}
+		}
+
+		poolBenchmarks := make(map[string][]types.Benchmark, len(pools))
+		benchmarks := []types.Benchmark{}
+		for _, pool := range pools {
+			results, err := lxd.Benchmark(r.Context(), pool, runtime)
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			poolBenchmarks[pool] = results
+			benchmarks = append(benchmarks, results...)
+		}
+
+		if !args.Baseline {
+			return response.SyncResponse(true, benchmarks)
+		}
+
+		err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+			for pool, results := range poolBenchmarks {
+				records := make([]database.Benchmark, 0, len(results))
+				for _, result := range results {
+					records = append(records, database.Benchmark{
+						Test:      result.Test,
+						IOPS:      result.IOPS,
+						Bandwidth: result.Bandwidth,
+						Latency:   result.Latency,
+						CreatedAt: result.CreatedAt,
+					})
+				}
+
+				err := database.ReplaceBenchmarks(ctx, tx, state.Name(), pool, records)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, benchmarks)
+	}
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ClockCmd represents the /1.0/clock API on MicroCloud.
+var ClockCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Name:              "clock",
+		Path:              "clock",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, clockGet)},
+	}
+}
+
+// clockGet returns the clock of the local system, to measure the clock skew between systems.
+func clockGet(state state.State, r *http.Request) response.Response {
+	return response.SyncResponse(true, service.LocalClock())
+}
@@ -0,0 +1,266 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// minimumHeartbeatInterval is the lowest heartbeat interval accepted by the daemon.
+const minimumHeartbeatInterval = 200 * time.Millisecond
+
+// minimumOVNWatchdogInterval is the lowest interval between probes of the OVN underlay accepted by the daemon.
+const minimumOVNWatchdogInterval = 10 * time.Second
+
+// minimumVerifySchedule is the lowest interval between scheduled verifications accepted by the daemon.
+const minimumVerifySchedule = 5 * time.Minute
+
+// minimumEventsRetention is the lowest retention of the event history accepted by the daemon.
+const minimumEventsRetention = time.Hour
+
+// validateClockSkew validates a clock skew threshold or limit.
+func validateClockSkew(value string) error {
+	skew, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	if skew <= 0 {
+		return errors.New("Must be positive")
+	}
+
+	return nil
+}
+
+// validateMailAddresses validates a comma separated list of email addresses.
+func validateMailAddresses(value string) error {
+	_, err := mail.ParseAddressList(value)
+
+	return err
+}
+
+// validateRequestLimit validates a rate limit or quota of the requests to the MicroCloud API.
+func validateRequestLimit(value string) error {
+	_, _, err := service.ParseRequestLimit(value)
+
+	return err
+}
+
+// configValidators are the validation functions of the supported daemon configuration keys.
+var configValidators = map[string]func(value string) error{
+	types.ConfigHeartbeatInterval: func(value string) error {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		if interval < minimumHeartbeatInterval {
+			return fmt.Errorf("Must be at least %s", minimumHeartbeatInterval)
+		}
+
+		return nil
+	},
+	types.ConfigMetricsAddress: validate.IsListenAddress(true, true, true),
+	types.ConfigWebhookURLs:    validate.IsListOf(validate.IsRequestURL),
+	types.ConfigAlertRules: func(value string) error {
+		_, err := service.ParseAlertRules(value)
+
+		return err
+	},
+	types.ConfigAlertSMTPServer: service.ValidateAlertSMTPServer,
+	types.ConfigAlertSMTPFrom:   validateMailAddresses,
+	types.ConfigAlertSMTPTo:     validateMailAddresses,
+	types.ConfigReportsSchedule: validate.IsOneOf(service.ReportSchedules...),
+	types.ConfigReportsURL:      validate.IsRequestURL,
+	types.ConfigAPIRateLimit:    validateRequestLimit,
+	types.ConfigAPIQuota:        validateRequestLimit,
+	types.ConfigUpgradePolicy:   validate.IsOneOf(types.UpgradePolicies...),
+	types.ConfigMaintenanceWindows: func(value string) error {
+		_, err := service.ParseMaintenanceWindows(value)
+
+		return err
+	},
+	types.ConfigSnapRefreshHold: validate.IsBool,
+	types.ConfigOVNWatchdogInterval: func(value string) error {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		if interval < minimumOVNWatchdogInterval {
+			return fmt.Errorf("Must be at least %s", minimumOVNWatchdogInterval)
+		}
+
+		return nil
+	},
+	types.ConfigVerifySchedule: func(value string) error {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		if interval < minimumVerifySchedule {
+			return fmt.Errorf("Must be at least %s", minimumVerifySchedule)
+		}
+
+		return nil
+	},
+	types.ConfigEventsRetention: func(value string) error {
+		retention, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		if retention < minimumEventsRetention {
+			return fmt.Errorf("Must be at least %s", minimumEventsRetention)
+		}
+
+		return nil
+	},
+	types.ConfigLivenessThreshold: func(value string) error {
+		threshold, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+
+		if threshold < 1 {
+			return errors.New("Must be at least 1")
+		}
+
+		return nil
+	},
+	types.ConfigMembersExpected: func(value string) error {
+		_, err := service.ParseExpectedMembers(value)
+
+		return err
+	},
+	types.ConfigClockSkewThreshold: validateClockSkew,
+	types.ConfigClockSkewLimit:     validateClockSkew,
+	types.ConfigLocalPoolName:      service.ValidateStoragePoolName,
+	types.ConfigRemotePoolName:     service.ValidateStoragePoolName,
+	types.ConfigRemoteFSPoolName:   service.ValidateStoragePoolName,
+	types.ConfigFanNetworkName:     validate.IsInterfaceName,
+	types.ConfigOVNNetworkName:     validate.IsInterfaceName,
+	types.ConfigUplinkNetworkName:  validate.IsInterfaceName,
+	types.ConfigVolumesPool:        validate.IsOneOf(types.VolumesPools...),
+	types.ConfigVolumesImagesSize:  validate.IsSize,
+	types.ConfigVolumesBackupsSize: validate.IsSize,
+	types.ConfigLocalPoolLimit:     service.ValidatePoolSizeLimit,
+	types.ConfigCephTiers: func(value string) error {
+		_, err := service.ParseCephTiers(value)
+
+		return err
+	},
+	types.ConfigOVNEncapsulation: validate.IsOneOf(types.OVNEncapsulations...),
+}
+
+// configChangeMessage describes the given configuration changes for the event history.
+// The webhook URLs, the mail server and the reports URL are left out, as they may hold credentials.
+func configChangeMessage(config map[string]string) string {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	changes := make([]string, 0, len(keys))
+	for _, key := range keys {
+		switch {
+		case config[key] == "":
+			changes = append(changes, "unset "+key)
+		case key == types.ConfigWebhookURLs || key == types.ConfigAlertSMTPServer || key == types.ConfigReportsURL:
+			changes = append(changes, "set "+key)
+		default:
+			changes = append(changes, fmt.Sprintf("set %s=%s", key, config[key]))
+		}
+	}
+
+	return "Configuration changed: " + strings.Join(changes, ", ")
+}
+
+// ConfigCmd represents the /1.0/config API on MicroCloud.
+var ConfigCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "config",
+
+		Get:   rest.EndpointAction{Handler: authHandlerMTLS(sh, configGet)},
+		Patch: rest.EndpointAction{Handler: authHandlerMTLS(sh, configPatch)},
+	}
+}
+
+// configGet returns the cluster-wide MicroCloud daemon configuration.
+func configGet(state state.State, r *http.Request) response.Response {
+	var config map[string]string
+	err := state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = database.GetConfig(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, config)
+}
+
+// configPatch updates the given keys of the cluster-wide MicroCloud daemon configuration.
+func configPatch(state state.State, r *http.Request) response.Response {
+	args := types.ConfigPatch{}
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	for key, value := range args.Config {
+		validator, ok := configValidators[key]
+		if !ok {
+			return response.BadRequest(fmt.Errorf("Unknown configuration key %q", key))
+		}
+
+		if value == "" {
+			continue
+		}
+
+		err := validator(value)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid value for configuration key %q: %w", key, err))
+		}
+	}
+
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		err := database.UpdateConfig(ctx, tx, args.Config)
+		if err != nil {
+			return err
+		}
+
+		if len(args.Config) == 0 {
+			return nil
+		}
+
+		return database.CreateEvent(ctx, tx, database.Event{Type: types.EventConfig, Member: state.Name(), Message: configChangeMessage(args.Config), CreatedAt: time.Now().UTC()})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, nil)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// DisksWipeCmd represents the /1.0/disks/wipe API on MicroCloud.
+var DisksWipeCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "disks/wipe",
+
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, disksWipePost), ProxyTarget: true},
+	}
+}
+
+// DisksSystemCmd represents the /1.0/disks/system API on MicroCloud.
+var DisksSystemCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Path:              "disks/system",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, disksSystemGet)},
+	}
+}
+
+// disksSystemGet returns the disks holding the operating system of this system.
+func disksSystemGet(state state.State, r *http.Request) response.Response {
+	disks, err := service.LocalSystemDisks()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, disks)
+}
+
+// disksWipePost wipes the given disks of this cluster member.
+func disksWipePost(state state.State, r *http.Request) response.Response {
+	args := types.DisksWipe{}
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	for _, path := range args.Paths {
+		logger.Info("Wiping disk", logger.Ctx{"path": path})
+
+		err := service.WipeDisk(path)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	return response.EmptySyncResponse
+}
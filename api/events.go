@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// DefaultEventsRetention is how long events are kept in the event history unless configured otherwise.
+const DefaultEventsRetention = 30 * 24 * time.Hour
+
+// EventsCmd represents the /1.0/events API on MicroCloud.
+var EventsCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "events",
+
+		Get: rest.EndpointAction{Handler: eventsGet, AllowUntrusted: true, AccessHandler: allowAuthToken(sh, types.AuthTokenRoleReadOnly)},
+	}
+}
+
+// RecordEvent records an event of the local cluster member in the event history.
+// Failures are only logged, as the event history must not get in the way of the event itself.
+func RecordEvent(ctx context.Context, s state.State, eventType string, message string) {
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return database.CreateEvent(ctx, tx, database.Event{Type: eventType, Member: s.Name(), Message: message, CreatedAt: time.Now().UTC()})
+	})
+	if err != nil {
+		logger.Error("Failed to record event", logger.Ctx{"type": eventType, "message": message, "error": err})
+	}
+}
+
+// eventFilter returns the filter of the event history given in the request query.
+func eventFilter(r *http.Request) (database.EventFilter, error) {
+	list, err := listFilter(r)
+	if err != nil {
+		return database.EventFilter{}, err
+	}
+
+	filter := database.EventFilter{Type: r.FormValue("type"), Members: list.Members, Limit: list.Limit, Offset: list.Offset}
+	if filter.Type != "" && !slices.Contains(types.EventTypes, filter.Type) {
+		return filter, fmt.Errorf("Unknown event type %q", filter.Type)
+	}
+
+	for param, value := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if r.FormValue(param) == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, r.FormValue(param))
+		if err != nil {
+			return filter, fmt.Errorf("Invalid %q time: %w", param, err)
+		}
+
+		*value = t.UTC()
+	}
+
+	return filter, nil
+}
+
+// eventsGet returns the event history, filtered by the type, member, since and until query parameters, and paginated by the limit and offset query parameters.
+func eventsGet(state state.State, r *http.Request) response.Response {
+	filter, err := eventFilter(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var records []database.Event
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetEvents(ctx, tx, filter)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	events := make([]types.Event, 0, len(records))
+	for _, record := range records {
+		events = append(events, types.Event{
+			ID:        record.ID,
+			Type:      record.Type,
+			Member:    record.Member,
+			Message:   record.Message,
+			CreatedAt: record.CreatedAt,
+		})
+	}
+
+	return response.SyncResponse(true, events)
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/canonical/microcluster/v3/state"
+)
+
+// forwardedHeader marks the requests forwarded by another cluster member, with the name of that member.
+// Forwarded requests are always handled by the receiving member, so a request is never forwarded twice.
+const forwardedHeader = "X-MicroCloud-Forwarded-By"
+
+// ownerFunc returns the name of the cluster member which should handle the request.
+// An empty name means any cluster member can handle it.
+type ownerFunc func(s state.State, r *http.Request) (string, error)
+
+// forwardToOwner wraps the handler, so requests are forwarded to the cluster member owning the requested resource.
+// Requests owned by the local cluster member, or already forwarded by another member, are handled locally.
+//
+// Ownership applies to resources only one cluster member can act on, like the cluster-wide jobs run by the database leader.
+// The per-member endpoints, such as tuning, benchmarks and verify, act on the member given with "?target=",
+// so they set ProxyTarget on the endpoint action instead.
+func forwardToOwner(owner ownerFunc, handler endpointHandler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		member, err := forwardTarget(s, r, owner)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if member == "" {
+			return handler(s, r)
+		}
+
+		return ForwardRequest(s, r, member)
+	}
+}
+
+// forwardTarget returns the name of the cluster member the request must be forwarded to,
+// or an empty name if the local cluster member handles it.
+func forwardTarget(s state.State, r *http.Request, owner ownerFunc) (string, error) {
+	if r.Header.Get(forwardedHeader) != "" {
+		return "", nil
+	}
+
+	member, err := owner(s, r)
+	if err != nil {
+		return "", err
+	}
+
+	if member == s.Name() {
+		return "", nil
+	}
+
+	return member, nil
+}
+
+// ForwardRequest forwards the request to the MicroCloud API of the given cluster member, and returns its response.
+func ForwardRequest(s state.State, r *http.Request, member string) response.Response {
+	address, ok := s.Remotes().Addresses()[member]
+	if !ok {
+		return response.NotFound(fmt.Errorf("Cluster member %q not found", member))
+	}
+
+	cluster, err := s.Cluster(false)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed to get clients for the cluster members: %w", err))
+	}
+
+	for _, c := range cluster {
+		if c.URL().URL.Host != address.String() {
+			continue
+		}
+
+		return forwardRequest(r, c.URL().URL, s.Name(), member, c.Do)
+	}
+
+	return response.SmartError(fmt.Errorf("Cluster member %q is not reachable at %q", member, address.String()))
+}
+
+// forwardRequest sends the request to the given member at the target URL with do, marked as forwarded by the local member, and returns the response.
+func forwardRequest(r *http.Request, target url.URL, local string, member string, do func(*http.Request) (*http.Response, error)) response.Response {
+	// Must unset the RequestURI. It is an error to set this in a client request.
+	r.RequestURI = ""
+	r.URL.Scheme = target.Scheme
+	r.URL.Host = target.Host
+	r.Host = r.URL.Host
+	r.Header.Set(forwardedHeader, local)
+
+	// The target was resolved here, so don't let the member proxy the request any further.
+	query := r.URL.Query()
+	query.Del("target")
+	r.URL.RawQuery = query.Encode()
+
+	logger.Debug("Forwarding request to cluster member", logger.Ctx{"method": r.Method, "path": r.URL.Path, "member": member})
+
+	resp, err := do(r)
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed to forward request to cluster member %q: %w", member, err))
+	}
+
+	return NewResponse(resp)
+}
+
+// leaderOwner returns the name of the database leader, which owns the cluster-wide jobs such as the generation of health reports.
+func leaderOwner(s state.State, r *http.Request) (string, error) {
+	leaderClient, err := s.Database().Leader(r.Context())
+	if err != nil {
+		return "", fmt.Errorf("Failed to get database leader client: %w", err)
+	}
+
+	defer func() { _ = leaderClient.Close() }()
+
+	leaderInfo, err := leaderClient.Leader(r.Context())
+	if err != nil {
+		return "", fmt.Errorf("Failed to get database leader info: %w", err)
+	}
+
+	// Let the local member handle the request if the leader isn't known yet.
+	return memberAtAddress(s.Remotes().Addresses(), leaderInfo.Address), nil
+}
+
+// memberAtAddress returns the name of the cluster member with the given address, or an empty name if there is none.
+func memberAtAddress(addresses map[string]microTypes.AddrPort, address string) string {
+	for name, memberAddress := range addresses {
+		if memberAddress.String() == address {
+			return name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/canonical/microcluster/v3/state"
+	"github.com/stretchr/testify/suite"
+)
+
+type forwardSuite struct {
+	suite.Suite
+}
+
+func TestForwardSuite(t *testing.T) {
+	suite.Run(t, new(forwardSuite))
+}
+
+// testState is a cluster member state only knowing its name.
+type testState struct {
+	state.State
+
+	name string
+}
+
+func (t testState) Name() string {
+	return t.name
+}
+
+func (s *forwardSuite) Test_forwardTarget() {
+	cases := []struct {
+		desc      string
+		forwarded bool
+		owner     string
+		ownerErr  error
+		expected  string
+		expectErr bool
+	}{
+		{
+			desc:     "Owned by another member",
+			owner:    "micro02",
+			expected: "micro02",
+		},
+		{
+			desc:     "Owned by the local member",
+			owner:    "micro01",
+			expected: "",
+		},
+		{
+			desc:     "Owned by any member",
+			owner:    "",
+			expected: "",
+		},
+		{
+			desc:      "Forwarded requests are handled locally",
+			forwarded: true,
+			owner:     "micro02",
+			expected:  "",
+		},
+		{
+			desc:      "Owner lookup failure",
+			ownerErr:  errors.New("Leader unknown"),
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		r := httptest.NewRequest(http.MethodPost, "/1.0/reports", nil)
+		if c.forwarded {
+			r.Header.Set(forwardedHeader, "micro03")
+		}
+
+		ownerCalled := false
+		owner := func(s state.State, r *http.Request) (string, error) {
+			ownerCalled = true
+
+			return c.owner, c.ownerErr
+		}
+
+		member, err := forwardTarget(testState{name: "micro01"}, r, owner)
+		if c.expectErr {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.expected, member)
+
+		// The owner of forwarded requests isn't looked up again, so requests can't bounce between members.
+		s.Equal(!c.forwarded, ownerCalled)
+	}
+}
+
+func (s *forwardSuite) Test_forwardRequest() {
+	var received *http.Request
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ := io.ReadAll(r.Body)
+		receivedBody = string(body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(lxdAPI.ResponseRaw{Type: lxdAPI.SyncResponse, Status: "Success", StatusCode: http.StatusOK, Metadata: map[string]string{"member": "micro02"}})
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	s.Require().NoError(err)
+
+	r := httptest.NewRequest(http.MethodPost, "/1.0/reports?target=micro02&limit=1", strings.NewReader(`{"name":"report"}`))
+	resp := forwardRequest(r, *target, "micro01", "micro02", http.DefaultClient.Do)
+
+	// The request reaches the member with its path, body and remaining query, marked as forwarded.
+	s.Require().NotNil(received)
+	s.Equal(http.MethodPost, received.Method)
+	s.Equal("/1.0/reports", received.URL.Path)
+	s.Equal("limit=1", received.URL.RawQuery)
+	s.Equal("micro01", received.Header.Get(forwardedHeader))
+	s.Equal(`{"name":"report"}`, receivedBody)
+
+	// The response of the member is passed on.
+	w := httptest.NewRecorder()
+	s.NoError(resp.Render(w, r))
+	s.Equal(http.StatusAccepted, w.Code)
+
+	var raw lxdAPI.ResponseRaw
+	s.NoError(json.NewDecoder(w.Body).Decode(&raw))
+	s.Equal(map[string]any{"member": "micro02"}, raw.Metadata)
+
+	// Failing to reach the member is an error.
+	r = httptest.NewRequest(http.MethodPost, "/1.0/reports", nil)
+	resp = forwardRequest(r, *target, "micro01", "micro02", func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("Connection refused")
+	})
+
+	w = httptest.NewRecorder()
+	s.NoError(resp.Render(w, r))
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Contains(w.Body.String(), `Failed to forward request to cluster member \"micro02\"`)
+}
+
+func (s *forwardSuite) Test_memberAtAddress() {
+	addresses := map[string]microTypes.AddrPort{}
+	for name, address := range map[string]string{"micro01": "10.0.0.1:9443", "micro02": "10.0.0.2:9443", "micro03": "[fd42::3]:9443"} {
+		addrPort, err := microTypes.ParseAddrPort(address)
+		s.Require().NoError(err)
+		addresses[name] = addrPort
+	}
+
+	s.Equal("micro02", memberAtAddress(addresses, "10.0.0.2:9443"))
+	s.Equal("micro03", memberAtAddress(addresses, "[fd42::3]:9443"))
+
+	// The leader address must match including the port.
+	s.Equal("", memberAtAddress(addresses, "10.0.0.2:7443"))
+	s.Equal("", memberAtAddress(addresses, "10.0.0.4:9443"))
+	s.Equal("", memberAtAddress(nil, "10.0.0.1:9443"))
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// InspectCmd represents the /1.0/inspect API on MicroCloud.
+var InspectCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "inspect",
+		Path: "inspect",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, inspectGet(sh)), ProxyTarget: true},
+	}
+}
+
+// inspectGet returns the detailed state of the local cluster member.
+func inspectGet(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		return response.SyncResponse(true, sh.Inspect(r.Context(), s.Name()))
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// JoinStatesCmd represents the /1.0/join-states API on MicroCloud.
+var JoinStatesCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "join-states",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, joinStatesGet)},
+	}
+}
+
+// joinStatesGet returns the join states of the cluster member given in the member query parameter, or of all cluster members.
+func joinStatesGet(state state.State, r *http.Request) response.Response {
+	var records []database.JoinState
+	err := state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetJoinStates(ctx, tx, r.FormValue("member"))
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	joinStates := make([]types.JoinState, 0, len(records))
+	for _, record := range records {
+		joinState := types.JoinState{
+			Member:    record.Member,
+			Service:   types.ServiceType(record.Service),
+			Status:    record.Status,
+			Error:     record.Error,
+			UpdatedAt: record.UpdatedAt,
+		}
+
+		err := json.Unmarshal([]byte(record.Config), &joinState.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		joinStates = append(joinStates, joinState)
+	}
+
+	return response.SyncResponse(true, joinStates)
+}
+
+// recordJoinState records the state of the local cluster member joining the given service, so a partially failed join can be retried.
+// Nothing is recorded for MicroCloud itself, or while the local cluster member isn't part of the MicroCloud cluster yet.
+func recordJoinState(ctx context.Context, s state.State, serviceType types.ServiceType, config types.ServicesPut, status string, joinErr error) {
+	if serviceType == types.MicroCloud || s.Database().IsOpen(ctx) != nil {
+		return
+	}
+
+	// Tokens are single use, so they are left out.
+	config.Tokens = nil
+	data, err := json.Marshal(config)
+	if err != nil {
+		logger.Error("Failed to encode join configuration", logger.Ctx{"service": serviceType, "error": err})
+		return
+	}
+
+	joinState := database.JoinState{
+		Member:    s.Name(),
+		Service:   string(serviceType),
+		Status:    status,
+		Config:    string(data),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if joinErr != nil {
+		joinState.Error = joinErr.Error()
+	}
+
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		return database.UpdateJoinState(ctx, tx, joinState)
+	})
+	if err != nil {
+		logger.Error("Failed to record join state", logger.Ctx{"service": serviceType, "status": status, "error": err})
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// listServices are the services a listing can be filtered by.
+var listServices = []types.ServiceType{types.MicroCloud, types.LXD, types.MicroCeph, types.MicroOVN}
+
+// listFilter returns the filter and pagination of a listing given in the request query.
+// The member, service and status parameters filter the entries, and the limit and offset parameters select a page of the matching entries.
+func listFilter(r *http.Request) (types.ListFilter, error) {
+	filter := types.ListFilter{
+		Members: slices.DeleteFunc(shared.SplitNTrimSpace(r.FormValue("member"), ",", -1, true), func(name string) bool { return name == "" }),
+		Service: types.ServiceType(r.FormValue("service")),
+		Status:  strings.ToUpper(r.FormValue("status")),
+	}
+
+	if filter.Service != "" {
+		i := slices.IndexFunc(listServices, func(s types.ServiceType) bool { return strings.EqualFold(string(s), string(filter.Service)) })
+		if i < 0 {
+			return filter, fmt.Errorf("Unknown service %q", filter.Service)
+		}
+
+		filter.Service = listServices[i]
+	}
+
+	for param, value := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if r.FormValue(param) == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(r.FormValue(param))
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("Invalid %q value %q, must be zero or a positive integer", param, r.FormValue(param))
+		}
+
+		*value = n
+	}
+
+	return filter, nil
+}
+
+// matchMember returns whether the filter matches the cluster member with the given name.
+func matchMember(filter types.ListFilter, name string) bool {
+	return len(filter.Members) == 0 || slices.Contains(filter.Members, name)
+}
+
+// paginate returns the page of the entries selected by the limit and offset of the filter.
+func paginate[T any](entries []T, filter types.ListFilter) []T {
+	if filter.Offset >= len(entries) {
+		return []T{}
+	}
+
+	entries = entries[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(entries) {
+		entries = entries[:filter.Limit]
+	}
+
+	return entries
+}
+
+// filterStatuses returns the page of the statuses matching the filter, sorted by member name.
+// The MicroCloud status of each member is taken from the cluster members seen by the given local member.
+func filterStatuses(statuses []types.Status, local string, filter types.ListFilter) []types.Status {
+	memberStatus := map[string]string{}
+	for _, status := range statuses {
+		if status.Name != local {
+			continue
+		}
+
+		for _, member := range status.Clusters[types.MicroCloud] {
+			memberStatus[member.Name] = string(member.Status)
+		}
+	}
+
+	filtered := make([]types.Status, 0, len(statuses))
+	for _, status := range statuses {
+		if !matchMember(filter, status.Name) {
+			continue
+		}
+
+		if filter.Service != "" && len(status.Clusters[filter.Service]) == 0 {
+			continue
+		}
+
+		if filter.Status != "" && memberStatus[status.Name] != filter.Status {
+			continue
+		}
+
+		filtered = append(filtered, status)
+	}
+
+	slices.SortFunc(filtered, func(a types.Status, b types.Status) int { return strings.Compare(a.Name, b.Name) })
+
+	return paginate(filtered, filter)
+}
+
+// filterMembers returns the page of the cluster members matching the filter, sorted by name.
+// The service filter matches the members found in serviceMembers.
+func filterMembers(members []microTypes.ClusterMember, serviceMembers map[string]string, filter types.ListFilter) []microTypes.ClusterMember {
+	filtered := make([]microTypes.ClusterMember, 0, len(members))
+	for _, member := range members {
+		if !matchMember(filter, member.Name) {
+			continue
+		}
+
+		if filter.Service != "" && serviceMembers[member.Name] == "" {
+			continue
+		}
+
+		if filter.Status != "" && string(member.Status) != filter.Status {
+			continue
+		}
+
+		filtered = append(filtered, member)
+	}
+
+	slices.SortFunc(filtered, func(a microTypes.ClusterMember, b microTypes.ClusterMember) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return paginate(filtered, filter)
+}
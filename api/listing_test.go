@@ -0,0 +1,283 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type listingSuite struct {
+	suite.Suite
+}
+
+func TestListingSuite(t *testing.T) {
+	suite.Run(t, new(listingSuite))
+}
+
+func (s *listingSuite) Test_listFilter() {
+	cases := []struct {
+		desc      string
+		query     string
+		expected  types.ListFilter
+		expectErr bool
+	}{
+		{
+			desc:     "No filter",
+			query:    "",
+			expected: types.ListFilter{},
+		},
+		{
+			desc:     "Members are split and trimmed",
+			query:    "member=micro01,%20micro02,",
+			expected: types.ListFilter{Members: []string{"micro01", "micro02"}},
+		},
+		{
+			desc:     "Service is matched case-insensitively",
+			query:    "service=microceph",
+			expected: types.ListFilter{Service: types.MicroCeph},
+		},
+		{
+			desc:     "Status is upper cased",
+			query:    "status=online",
+			expected: types.ListFilter{Status: "ONLINE"},
+		},
+		{
+			desc:     "Limit and offset",
+			query:    "limit=20&offset=40",
+			expected: types.ListFilter{Limit: 20, Offset: 40},
+		},
+		{
+			desc:     "Zero limit and offset",
+			query:    "limit=0&offset=0",
+			expected: types.ListFilter{},
+		},
+		{
+			desc:      "Unknown service",
+			query:     "service=microk8s",
+			expectErr: true,
+		},
+		{
+			desc:      "Negative limit",
+			query:     "limit=-1",
+			expectErr: true,
+		},
+		{
+			desc:      "Invalid offset",
+			query:     "offset=ten",
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		filter, err := listFilter(httptest.NewRequest("GET", "/1.0/status?"+c.query, nil))
+		if c.expectErr {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.expected, filter)
+	}
+}
+
+func (s *listingSuite) Test_paginate() {
+	entries := []int{0, 1, 2, 3, 4}
+	cases := []struct {
+		desc     string
+		filter   types.ListFilter
+		expected []int
+	}{
+		{
+			desc:     "Zero limit returns all entries",
+			filter:   types.ListFilter{},
+			expected: []int{0, 1, 2, 3, 4},
+		},
+		{
+			desc:     "First page",
+			filter:   types.ListFilter{Limit: 2},
+			expected: []int{0, 1},
+		},
+		{
+			desc:     "Middle page",
+			filter:   types.ListFilter{Limit: 2, Offset: 2},
+			expected: []int{2, 3},
+		},
+		{
+			desc:     "Last page is shorter than the limit",
+			filter:   types.ListFilter{Limit: 2, Offset: 4},
+			expected: []int{4},
+		},
+		{
+			desc:     "Offset without a limit",
+			filter:   types.ListFilter{Offset: 3},
+			expected: []int{3, 4},
+		},
+		{
+			desc:     "Offset at the end",
+			filter:   types.ListFilter{Offset: 5},
+			expected: []int{},
+		},
+		{
+			desc:     "Offset past the end",
+			filter:   types.ListFilter{Limit: 2, Offset: 10},
+			expected: []int{},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.expected, paginate(entries, c.filter))
+	}
+}
+
+func (s *listingSuite) Test_filterStatuses() {
+	member := func(name string, status microTypes.MemberStatus) microTypes.ClusterMember {
+		return microTypes.ClusterMember{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: name}, Status: status}
+	}
+
+	// The statuses are reported in no particular order, and only the local member's view of MicroCloud is used.
+	statuses := []types.Status{
+		{
+			Name: "micro03",
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+				types.MicroCloud: {member("micro01", microTypes.MemberUnreachable)},
+				types.LXD:        {member("micro03", microTypes.MemberOnline)},
+			},
+		},
+		{
+			Name: "micro01",
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+				types.MicroCloud: {member("micro01", microTypes.MemberOnline), member("micro02", microTypes.MemberOnline), member("micro03", microTypes.MemberUnreachable)},
+				types.LXD:        {member("micro01", microTypes.MemberOnline)},
+				types.MicroCeph:  {member("micro01", microTypes.MemberOnline)},
+			},
+		},
+		{
+			Name: "micro02",
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+				types.LXD: {member("micro02", microTypes.MemberOnline)},
+			},
+		},
+	}
+
+	cases := []struct {
+		desc     string
+		filter   types.ListFilter
+		expected []string
+	}{
+		{
+			desc:     "No filter sorts by name",
+			expected: []string{"micro01", "micro02", "micro03"},
+		},
+		{
+			desc:     "Member filter",
+			filter:   types.ListFilter{Members: []string{"micro03", "micro02"}},
+			expected: []string{"micro02", "micro03"},
+		},
+		{
+			desc:     "Service filter",
+			filter:   types.ListFilter{Service: types.MicroCeph},
+			expected: []string{"micro01"},
+		},
+		{
+			desc:     "Status filter uses the local member's view",
+			filter:   types.ListFilter{Status: string(microTypes.MemberUnreachable)},
+			expected: []string{"micro03"},
+		},
+		{
+			desc:     "Filters combine",
+			filter:   types.ListFilter{Members: []string{"micro01", "micro03"}, Status: string(microTypes.MemberOnline)},
+			expected: []string{"micro01"},
+		},
+		{
+			desc:     "Pagination applies after filtering",
+			filter:   types.ListFilter{Status: string(microTypes.MemberOnline), Offset: 1, Limit: 5},
+			expected: []string{"micro02"},
+		},
+		{
+			desc:     "Offset past the end",
+			filter:   types.ListFilter{Offset: 3},
+			expected: []string{},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		names := []string{}
+		for _, status := range filterStatuses(statuses, "micro01", c.filter) {
+			names = append(names, status.Name)
+		}
+
+		s.Equal(c.expected, names)
+	}
+}
+
+func (s *listingSuite) Test_filterMembers() {
+	members := []microTypes.ClusterMember{
+		{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: "micro03"}, Status: microTypes.MemberUnreachable},
+		{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: "micro01"}, Status: microTypes.MemberOnline},
+		{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: "micro02"}, Status: microTypes.MemberOnline},
+	}
+
+	cases := []struct {
+		desc           string
+		filter         types.ListFilter
+		serviceMembers map[string]string
+		expected       []string
+	}{
+		{
+			desc:     "No filter sorts by name",
+			expected: []string{"micro01", "micro02", "micro03"},
+		},
+		{
+			desc:     "Member filter",
+			filter:   types.ListFilter{Members: []string{"micro02"}},
+			expected: []string{"micro02"},
+		},
+		{
+			desc:           "Service filter",
+			filter:         types.ListFilter{Service: types.MicroCeph},
+			serviceMembers: map[string]string{"micro01": "10.0.0.1", "micro03": "10.0.0.3"},
+			expected:       []string{"micro01", "micro03"},
+		},
+		{
+			desc:     "Service filter without members of the service",
+			filter:   types.ListFilter{Service: types.MicroOVN},
+			expected: []string{},
+		},
+		{
+			desc:     "Status filter",
+			filter:   types.ListFilter{Status: string(microTypes.MemberOnline)},
+			expected: []string{"micro01", "micro02"},
+		},
+		{
+			desc:     "Pagination applies after filtering",
+			filter:   types.ListFilter{Status: string(microTypes.MemberOnline), Limit: 1},
+			expected: []string{"micro01"},
+		},
+		{
+			desc:     "Offset past the end",
+			filter:   types.ListFilter{Offset: 4, Limit: 1},
+			expected: []string{},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		names := []string{}
+		for _, member := range filterMembers(members, c.serviceMembers, c.filter) {
+			names = append(names, member.Name)
+		}
+
+		s.Equal(c.expected, names)
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// MembersCmd represents the /1.0/members API on MicroCloud.
+var MembersCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "members",
+
+		Get: rest.EndpointAction{Handler: membersGet(sh), AllowUntrusted: true, AccessHandler: allowAuthToken(sh, types.AuthTokenRoleReadOnly)},
+	}
+}
+
+// membersGet returns the MicroCloud cluster members.
+// The members can be filtered and paginated with the member, service, status, limit and offset query parameters.
+func membersGet(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		filter, err := listFilter(r)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		cloud, ok := sh.Services[types.MicroCloud].(*service.CloudService)
+		if !ok {
+			return response.InternalError(fmt.Errorf("Failed to find %s service", types.MicroCloud))
+		}
+
+		c, err := cloud.Client()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		members, err := c.GetClusterMembers(r.Context())
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Only the members the service is set up on match the service filter.
+		serviceMembers := map[string]string{}
+		if filter.Service != "" && sh.Services[filter.Service] != nil {
+			serviceMembers, err = sh.Services[filter.Service].ClusterMembers(r.Context())
+			if err != nil {
+				return response.SmartError(fmt.Errorf("Failed to get %s cluster members: %w", filter.Service, err))
+			}
+		}
+
+		return response.SyncResponse(true, filterMembers(members, serviceMembers, filter))
+	}
+}
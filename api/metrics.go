@@ -0,0 +1,44 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// MetricsCmd represents the /1.0/metrics API on MicroCloud.
+var MetricsCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "metrics",
+		Path: "metrics",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, metricsGet(sh)), ProxyTarget: true},
+	}
+}
+
+// metricsGet returns the metrics of the local cluster member in the Prometheus text format.
+func metricsGet(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		var b strings.Builder
+		b.WriteString("# HELP microcloud_api_requests_rejected_total Requests to the MicroCloud API rejected by the rate limit or quota.\n")
+		b.WriteString("# TYPE microcloud_api_requests_rejected_total counter\n")
+		for _, rejected := range sh.RejectedRequests() {
+			fmt.Fprintf(&b, "microcloud_api_requests_rejected_total{member=%q,client=%q,reason=%q} %d\n", s.Name(), rejected.Client, rejected.Reason, rejected.Count)
+		}
+
+		return response.ManualResponse(func(w http.ResponseWriter) error {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			w.WriteHeader(http.StatusOK)
+
+			_, err := w.Write([]byte(b.String()))
+
+			return err
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// DefaultNetworkTestDuration is the duration of each bandwidth measurement if none is requested.
+const DefaultNetworkTestDuration = 5 * time.Second
+
+// maximumNetworkTestDuration is the longest bandwidth measurement accepted by the daemon.
+const maximumNetworkTestDuration = time.Minute
+
+// defaultNetworkTestServerTimeout is the time after which the network test server stops if none is requested.
+const defaultNetworkTestServerTimeout = 10 * time.Minute
+
+// maximumNetworkTestServerTimeout is the longest time the network test server may run.
+const maximumNetworkTestServerTimeout = time.Hour
+
+// NetworkTestServerCmd represents the /1.0/network-test/server API on MicroCloud.
+var NetworkTestServerCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "network-test/server",
+
+		Put:    rest.EndpointAction{Handler: authHandlerMTLS(sh, networkTestServerPut(sh)), ProxyTarget: true},
+		Delete: rest.EndpointAction{Handler: authHandlerMTLS(sh, networkTestServerDelete(sh)), ProxyTarget: true},
+	}
+}
+
+// NetworkTestCmd represents the /1.0/network-test API on MicroCloud.
+var NetworkTestCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "network-test",
+
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, networkTestPost(sh)), ProxyTarget: true},
+	}
+}
+
+// networkTestAddresses returns the addresses of this cluster member on each network it is part of.
+// Networks whose address can't be determined are skipped, as the services may not be set up on every member.
+func networkTestAddresses(ctx context.Context, s state.State, sh *service.Handler) (map[string]string, error) {
+	addr, _, err := net.SplitHostPort(s.Address().URL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("State address %q is invalid: %w", s.Address().String(), err)
+	}
+
+	addresses := map[string]string{types.NetworkTestManagement: addr}
+
+	ceph, ok := sh.Services[types.MicroCeph].(*service.CephService)
+	if ok {
+		config, err := ceph.ClusterConfig(ctx, "", nil)
+		if err != nil {
+			logger.Warn("Failed to get the Ceph networks", logger.Ctx{"error": err})
+		}
+
+		networks := map[string]string{types.NetworkTestCephPublic: "public_network", types.NetworkTestCephCluster: "cluster_network"}
+		for network, key := range networks {
+			if config[key] == "" {
+				continue
+			}
+
+			addr, err := service.AddressInSubnet(config[key])
+			if err != nil {
+				logger.Warn("Failed to get the address on the Ceph network", logger.Ctx{"network": network, "error": err})
+				continue
+			}
+
+			if addr != "" {
+				addresses[network] = addr
+			}
+		}
+	}
+
+	if sh.Services[types.MicroOVN] != nil {
+		addr, err := service.OVNEncapsulationAddress(ctx)
+		if err != nil {
+			logger.Warn("Failed to get the address on the OVN underlay network", logger.Ctx{"error": err})
+		} else if addr != "" {
+			addresses[types.NetworkTestOVNUnderlay] = addr
+		}
+	}
+
+	return addresses, nil
+}
+
+// networkTestServerPut starts the network test server of this cluster member, and returns its addresses on each network.
+func networkTestServerPut(sh *service.Handler) func(state state.State, r *http.Request) response.Response {
+	return func(state state.State, r *http.Request) response.Response {
+		args := types.NetworkTestServerPut{}
+		err := json.NewDecoder(r.Body).Decode(&args)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		if args.Token == "" {
+			return response.BadRequest(errors.New("No token provided"))
+		}
+
+		timeout := defaultNetworkTestServerTimeout
+		if args.Timeout != 0 {
+			timeout = time.Duration(args.Timeout) * time.Second
+		}
+
+		if timeout < time.Second || timeout > maximumNetworkTestServerTimeout {
+			return response.BadRequest(fmt.Errorf("Timeout must be between 1s and %s", maximumNetworkTestServerTimeout))
+		}
+
+		addresses, err := networkTestAddresses(r.Context(), state, sh)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = sh.StartNetworkTestServer(args.Token, timeout)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, types.NetworkTestServer{Addresses: addresses})
+	}
+}
+
+// networkTestServerDelete stops the network test server of this cluster member.
+func networkTestServerDelete(sh *service.Handler) func(state state.State, r *http.Request) response.Response {
+	return func(state state.State, r *http.Request) response.Response {
+		sh.StopNetworkTestServer()
+
+		return response.EmptySyncResponse
+	}
+}
+
+// networkTestPost measures the latency and bandwidth of the network links from this cluster member to the given peers.
+// The peers are measured one after the other so the measurements don't compete for bandwidth.
+// A failed measurement is reported in its result, so the remaining links are still measured.
+func networkTestPost(sh *service.Handler) func(state state.State, r *http.Request) response.Response {
+	return func(state state.State, r *http.Request) response.Response {
+		args := types.NetworkTestPost{}
+		err := json.NewDecoder(r.Body).Decode(&args)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		duration := DefaultNetworkTestDuration
+		if args.Duration != 0 {
+			duration = time.Duration(args.Duration) * time.Second
+		}
+
+		if duration < time.Second || duration > maximumNetworkTestDuration {
+			return response.BadRequest(fmt.Errorf("Duration must be between 1s and %s", maximumNetworkTestDuration))
+		}
+
+		results := make([]types.NetworkTestResult, 0, len(args.Peers))
+		for _, peer := range args.Peers {
+			if !slices.Contains(types.NetworkTestNetworks, peer.Network) {
+				return response.BadRequest(fmt.Errorf("Unknown network %q", peer.Network))
+			}
+
+			result := types.NetworkTestResult{From: state.Name(), To: peer.Name, Network: peer.Network, Address: peer.Address}
+
+			// Leave time to connect and for the server to confirm the received amount.
+			ctx, cancel := context.WithTimeout(r.Context(), duration+30*time.Second)
+			latency, err := service.MeasureLatency(ctx, peer.Address, args.Token)
+			if err == nil {
+				result.Latency = latency.Nanoseconds()
+				result.Bandwidth, err = service.MeasureBandwidth(ctx, peer.Address, args.Token, duration)
+			}
+
+			cancel()
+
+			if err != nil {
+				result.Error = err.Error()
+			}
+
+			results = append(results, result)
+		}
+
+		return response.SyncResponse(true, results)
+	}
+}
@@ -0,0 +1,381 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+	"github.com/canonical/microcloud/microcloud/version"
+)
+
+// openAPIParameter is a query or path parameter of an API endpoint.
+type openAPIParameter struct {
+	Name        string
+	In          string
+	Description string
+	Type        string
+}
+
+// openAPIRoute is a method of an API endpoint described in the OpenAPI document.
+type openAPIRoute struct {
+	Method     string
+	Path       string
+	ID         string
+	Summary    string
+	Parameters []openAPIParameter
+
+	// Request is a value of the type of the request body, nil if there is none.
+	Request any
+
+	// Response is a value of the type of the response metadata, nil if there is none.
+	Response any
+}
+
+// listParameters are the query parameters of the listings filtered by cluster member and paginated.
+var listParameters = []openAPIParameter{
+	{Name: "member", In: "query", Description: "Comma separated cluster members to return the entries of", Type: "string"},
+	{Name: "limit", In: "query", Description: "Maximum number of entries to return", Type: "integer"},
+	{Name: "offset", In: "query", Description: "Number of matching entries to skip", Type: "integer"},
+}
+
+// openAPIRoutes are the API endpoints described in the OpenAPI document.
+var openAPIRoutes = []openAPIRoute{
+	{
+		Method:  http.MethodGet,
+		Path:    "status",
+		ID:      "status_get",
+		Summary: "Get the status of the cluster members",
+		Parameters: append([]openAPIParameter{
+			{Name: "service", In: "query", Description: "Only return the cluster members the service is set up on", Type: "string"},
+			{Name: "status", In: "query", Description: "Only return the cluster members with this MicroCloud status", Type: "string"},
+		}, listParameters...),
+		Response: []types.Status{},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "events",
+		ID:      "events_get",
+		Summary: "Get the event history of the cluster",
+		Parameters: append([]openAPIParameter{
+			{Name: "type", In: "query", Description: "Only return the events of this type", Type: "string"},
+			{Name: "since", In: "query", Description: "Only return the events since this RFC 3339 time", Type: "string"},
+			{Name: "until", In: "query", Description: "Only return the events until this RFC 3339 time", Type: "string"},
+		}, listParameters...),
+		Response: []types.Event{},
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "members",
+		ID:      "members_get",
+		Summary: "Get the cluster members",
+		Parameters: append([]openAPIParameter{
+			{Name: "service", In: "query", Description: "Only return the cluster members the service is set up on", Type: "string"},
+			{Name: "status", In: "query", Description: "Only return the cluster members with this MicroCloud status", Type: "string"},
+		}, listParameters...),
+		Response: []microTypes.ClusterMember{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "operations",
+		ID:       "operations_get",
+		Summary:  "Get the cluster changes holding or waiting for the cluster-wide change lock",
+		Response: []types.Operation{},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "operations",
+		ID:       "operations_post",
+		Summary:  "Queue a cluster change for the cluster-wide change lock",
+		Request:  types.OperationsPost{},
+		Response: types.Operation{},
+	},
+	{
+		Method:     http.MethodPut,
+		Path:       "operations/{id}",
+		ID:         "operation_put",
+		Summary:    "Keep a queued cluster change alive, and get whether it holds the change lock",
+		Parameters: []openAPIParameter{{Name: "id", In: "path", Description: "ID of the cluster change", Type: "integer"}},
+		Response:   types.Operation{},
+	},
+	{
+		Method:     http.MethodDelete,
+		Path:       "operations/{id}",
+		ID:         "operation_delete",
+		Summary:    "Remove a cluster change from the queue, releasing the change lock if it held it",
+		Parameters: []openAPIParameter{{Name: "id", In: "path", Description: "ID of the cluster change", Type: "integer"}},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "verify",
+		ID:       "verify_post",
+		Summary:  "Run the lightweight checks of the cluster member",
+		Response: []types.VerifyCheck{},
+	},
+	{
+		Method:   http.MethodGet,
+		Path:     "auth/tokens",
+		ID:       "auth_tokens_get",
+		Summary:  "Get the API tokens, without their secrets",
+		Response: []types.AuthToken{},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "auth/tokens",
+		ID:       "auth_tokens_post",
+		Summary:  "Create an API token, and get its secret",
+		Request:  types.AuthTokensPost{},
+		Response: types.AuthTokenSecret{},
+	},
+	{
+		Method:     http.MethodDelete,
+		Path:       "auth/tokens/{name}",
+		ID:         "auth_token_delete",
+		Summary:    "Revoke an API token",
+		Parameters: []openAPIParameter{{Name: "name", In: "path", Description: "Name of the API token", Type: "string"}},
+	},
+	{
+		Method:   http.MethodPost,
+		Path:     "reachability",
+		ID:       "reachability_post",
+		Summary:  "Check that the system reaches the given addresses",
+		Request:  types.ReachabilityPost{},
+		Response: []types.Reachability{},
+	},
+}
+
+// DocCmd represents the /1.0/doc API on MicroCloud.
+var DocCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Name:              "doc",
+		Path:              "doc",
+
+		// The document only describes the API, so clients can be generated before being trusted.
+		Get: rest.EndpointAction{Handler: docGet(sh), AllowUntrusted: true},
+	}
+}
+
+// docGet returns the OpenAPI document of the MicroCloud API.
+func docGet(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		resp := limitRequests(sh, s, r)
+		if resp != nil {
+			return resp
+		}
+
+		return response.ManualResponse(func(w http.ResponseWriter) error {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+
+			return json.NewEncoder(w).Encode(OpenAPIDocument())
+		})
+	}
+}
+
+// OpenAPIDocument returns the OpenAPI 3.0 document of the MicroCloud API endpoints, with the schemas of their request and response types.
+func OpenAPIDocument() map[string]any {
+	schemas := openAPISchemas{schemas: map[string]any{}, names: map[reflect.Type]string{}}
+	schemas.schemas["Response"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"type":        map[string]any{"type": "string"},
+			"status":      map[string]any{"type": "string"},
+			"status_code": map[string]any{"type": "integer"},
+			"error_code":  map[string]any{"type": "integer"},
+			"error":       map[string]any{"type": "string"},
+			"metadata":    map[string]any{},
+		},
+	}
+
+	paths := map[string]map[string]any{}
+	for _, route := range openAPIRoutes {
+		operation := map[string]any{"operationId": route.ID, "summary": route.Summary}
+
+		parameters := make([]any, 0, len(route.Parameters))
+		for _, parameter := range route.Parameters {
+			parameters = append(parameters, map[string]any{
+				"name":        parameter.Name,
+				"in":          parameter.In,
+				"description": parameter.Description,
+				"required":    parameter.In == "path",
+				"schema":      map[string]any{"type": parameter.Type},
+			})
+		}
+
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+
+		if route.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": schemas.schema(reflect.TypeOf(route.Request))}},
+			}
+		}
+
+		// The responses wrap their metadata in the standard response of the API.
+		success := map[string]any{"$ref": "#/components/schemas/Response"}
+		if route.Response != nil {
+			success = map[string]any{"allOf": []any{success, map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"metadata": schemas.schema(reflect.TypeOf(route.Response))},
+			}}}
+		}
+
+		operation["responses"] = map[string]any{
+			"200":     map[string]any{"description": "Success", "content": map[string]any{"application/json": map[string]any{"schema": success}}},
+			"default": map[string]any{"description": "Error", "content": map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Response"}}}},
+		}
+
+		urlPath := "/" + path.Join(string(types.APIVersion), route.Path)
+		if paths[urlPath] == nil {
+			paths[urlPath] = map[string]any{}
+		}
+
+		paths[urlPath][strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi":    "3.0.3",
+		"info":       map[string]any{"title": "MicroCloud API", "version": version.RawVersion},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas.schemas},
+	}
+}
+
+// openAPISchemas builds the OpenAPI schemas of Go types from their JSON encoding.
+// Structs are added to the schemas by name, and referenced wherever they are used.
+type openAPISchemas struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schema returns the OpenAPI schema of the JSON encoding of the given type.
+func (s *openAPISchemas) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	pointer := reflect.PointerTo(t)
+	if t.Implements(textMarshalerType) || pointer.Implements(textMarshalerType) {
+		return map[string]any{"type": "string"}
+	}
+
+	if t.Implements(jsonMarshalerType) || pointer.Implements(jsonMarshalerType) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+
+		return map[string]any{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			properties := map[string]any{}
+			s.addProperties(t, properties)
+
+			return map[string]any{"type": "object", "properties": properties}
+		}
+
+		return map[string]any{"$ref": "#/components/schemas/" + s.structSchema(t)}
+	}
+
+	return map[string]any{}
+}
+
+// structSchema adds the schema of the given named struct if missing, and returns its name.
+func (s *openAPISchemas) structSchema(t reflect.Type) string {
+	name, ok := s.names[t]
+	if ok {
+		return name
+	}
+
+	// Types of different packages may share a name, so the package tells them apart.
+	name = strings.NewReplacer("[", "_", "]", "", "/", "_", ".", "_").Replace(t.Name())
+	_, taken := s.schemas[name]
+	if taken {
+		name = path.Base(t.PkgPath()) + "_" + name
+	}
+
+	// Register the struct before its fields, so that recursive types reference themselves.
+	s.names[t] = name
+	s.schemas[name] = map[string]any{}
+
+	properties := map[string]any{}
+	s.addProperties(t, properties)
+	s.schemas[name] = map[string]any{"type": "object", "properties": properties}
+
+	return name
+}
+
+// addProperties adds the JSON properties of the fields of the given struct, including those of its embedded structs.
+func (s *openAPISchemas) addProperties(t reflect.Type, properties map[string]any) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			s.addProperties(fieldType, properties)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if slices.Contains(strings.Split(options, ","), "string") {
+			properties[name] = map[string]any{"type": "string"}
+			continue
+		}
+
+		properties[name] = s.schema(field.Type)
+	}
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// OperationLease is the time after which a queued cluster change is dropped if its client stopped checking in.
+const OperationLease = 30 * time.Second
+
+// OperationsCmd represents the /1.0/operations API on MicroCloud.
+var OperationsCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "operations",
+
+		Get:  rest.EndpointAction{Handler: authHandlerMTLS(sh, operationsGet)},
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, operationsPost)},
+	}
+}
+
+// OperationCmd represents the /1.0/operations/{id} API on MicroCloud.
+var OperationCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "operations/{id}",
+
+		Put:    rest.EndpointAction{Handler: authHandlerMTLS(sh, operationPut)},
+		Delete: rest.EndpointAction{Handler: authHandlerMTLS(sh, operationDelete)},
+	}
+}
+
+// queuedOperations drops the stale cluster changes, and returns the remaining ones.
+// The oldest cluster change holds the change lock, and the others wait for it in turn.
+func queuedOperations(ctx context.Context, tx *sql.Tx) ([]types.Operation, error) {
+	err := database.DeleteStaleOperations(ctx, tx, time.Now().Add(-OperationLease))
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := database.GetOperations(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]types.Operation, 0, len(records))
+	for i, record := range records {
+		status := types.OperationPending
+		if i == 0 {
+			status = types.OperationRunning
+		}
+
+		operations = append(operations, types.Operation{
+			ID:          record.ID,
+			Type:        record.Type,
+			Member:      record.Member,
+			Description: record.Description,
+			Status:      status,
+			CreatedAt:   record.CreatedAt,
+			UpdatedAt:   record.UpdatedAt,
+		})
+	}
+
+	return operations, nil
+}
+
+// findOperation returns the queued cluster change with the given ID.
+func findOperation(operations []types.Operation, id int64) (*types.Operation, error) {
+	for _, op := range operations {
+		if op.ID == id {
+			return &op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Operation %d not found", id)
+}
+
+// operationID returns the ID of the cluster change in the request path.
+func operationID(r *http.Request) (int64, error) {
+	value, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// operationsGet returns the queued cluster changes.
+func operationsGet(state state.State, r *http.Request) response.Response {
+	var operations []types.Operation
+	err := state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		operations, err = queuedOperations(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, operations)
+}
+
+// operationsPost queues a cluster change for the change lock, and returns it.
+func operationsPost(state state.State, r *http.Request) response.Response {
+	args := types.OperationsPost{}
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if args.Type == "" {
+		return response.BadRequest(errors.New("No operation type provided"))
+	}
+
+	var operation *types.Operation
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		now := time.Now().UTC()
+		id, err := database.CreateOperation(ctx, tx, database.Operation{
+			Type:        args.Type,
+			Member:      state.Name(),
+			Description: args.Description,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return err
+		}
+
+		operations, err := queuedOperations(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		operation, err = findOperation(operations, id)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, operation)
+}
+
+// operationPut records that the client of the cluster change is still alive, and returns whether the change now holds the lock.
+func operationPut(state state.State, r *http.Request) response.Response {
+	id, err := operationID(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var operation *types.Operation
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		err := database.TouchOperation(ctx, tx, id, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		operations, err := queuedOperations(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		operation, err = findOperation(operations, id)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, operation)
+}
+
+// operationDelete removes the cluster change from the queue, releasing the change lock if it held it.
+func operationDelete(state state.State, r *http.Request) response.Response {
+	id, err := operationID(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		return database.DeleteOperation(ctx, tx, id)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// OVNUnderlayCmd represents the /1.0/ovn-underlay API on MicroCloud.
+var OVNUnderlayCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "ovn-underlay",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, ovnUnderlayGet(sh)), ProxyTarget: true},
+		Put: rest.EndpointAction{Handler: authHandlerMTLS(sh, ovnUnderlayPut(sh)), ProxyTarget: true},
+	}
+}
+
+// ovnUnderlayGet returns the address this cluster member uses for the OVN Geneve tunnels.
+func ovnUnderlayGet(sh *service.Handler) func(state state.State, r *http.Request) response.Response {
+	return func(state state.State, r *http.Request) response.Response {
+		if sh.Services[types.MicroOVN] == nil {
+			return response.BadRequest(errors.New("MicroOVN is not set up on this cluster member"))
+		}
+
+		address, err := service.OVNEncapsulationAddress(r.Context())
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		underlay := types.OVNUnderlay{Address: address}
+		if address != "" {
+			// The interface is left empty if the address is not configured on this system anymore.
+			underlay.Interface, _ = service.InterfaceWithAddress(address)
+		}
+
+		return response.SyncResponse(true, underlay)
+	}
+}
+
+// ovnUnderlayPut changes the address this cluster member uses for the OVN Geneve tunnels.
+func ovnUnderlayPut(sh *service.Handler) func(state state.State, r *http.Request) response.Response {
+	return func(state state.State, r *http.Request) response.Response {
+		args := types.OVNUnderlayPut{}
+		err := json.NewDecoder(r.Body).Decode(&args)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		if sh.Services[types.MicroOVN] == nil {
+			return response.BadRequest(errors.New("MicroOVN is not set up on this cluster member"))
+		}
+
+		iface, err := service.InterfaceWithAddress(args.Address)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		ip, _, _ := net.ParseCIDR(args.Address)
+		err = service.SetOVNEncapsulationAddress(r.Context(), ip.String())
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, types.OVNUnderlay{Address: ip.String(), Interface: iface})
+	}
+}
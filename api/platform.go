@@ -0,0 +1,27 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// PlatformCmd represents the /1.0/platform API on MicroCloud.
+var PlatformCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Name:              "platform",
+		Path:              "platform",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, platformGet)},
+	}
+}
+
+// platformGet returns the platform the local system runs in, such as a virtual machine on a cloud.
+func platformGet(state state.State, r *http.Request) response.Response {
+	return response.SyncResponse(true, service.LocalPlatform())
+}
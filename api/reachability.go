@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ReachabilityCmd represents the /1.0/reachability API on MicroCloud.
+var ReachabilityCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Name:              "reachability",
+		Path:              "reachability",
+
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, reachabilityPost)},
+	}
+}
+
+// reachabilityPost checks that the local system reaches the given addresses, so that systems on routed subnets can form a cluster.
+func reachabilityPost(state state.State, r *http.Request) response.Response {
+	args := types.ReachabilityPost{}
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	return response.SyncResponse(true, service.CheckReachability(r.Context(), args.Addresses, service.ReachabilityTimeout))
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ReportsCmd represents the /1.0/reports API on MicroCloud.
+var ReportsCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "reports",
+		Path: "reports",
+
+		Get:  rest.EndpointAction{Handler: reportsGet, AllowUntrusted: true, AccessHandler: allowAuthToken(sh, types.AuthTokenRoleReadOnly)},
+		Post: rest.EndpointAction{Handler: forwardToOwner(leaderOwner, reportsPost(sh)), AllowUntrusted: true, AccessHandler: allowAuthToken(sh, types.AuthTokenRoleOperator)},
+	}
+}
+
+// ReportCmd represents the /1.0/reports/{id} API on MicroCloud.
+var ReportCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "reports/{id}",
+		Path: "reports/{id}",
+
+		Get: rest.EndpointAction{Handler: reportGet, AllowUntrusted: true, AccessHandler: allowAuthToken(sh, types.AuthTokenRoleReadOnly)},
+	}
+}
+
+// GenerateHealthReport generates a health report of the cluster, and stores it in place of the oldest stored report once there are too many.
+func GenerateHealthReport(ctx context.Context, sh *service.Handler, s state.State) (*types.HealthReport, error) {
+	report, err := sh.HealthReport(ctx, s.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to encode health report: %w", err)
+	}
+
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		report.ID, err = database.CreateReport(ctx, tx, database.Report{Member: report.Member, Report: string(data), CreatedAt: report.CreatedAt})
+		if err != nil {
+			return err
+		}
+
+		return database.DeleteOldReports(ctx, tx, service.MaxHealthReports)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// reportID returns the ID of the health report in the request path.
+func reportID(r *http.Request) (int64, error) {
+	value, err := url.PathUnescape(mux.Vars(r)["id"])
+	if err != nil {
+		return -1, err
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// healthReport decodes a stored health report.
+func healthReport(record database.Report) (*types.HealthReport, error) {
+	report := types.HealthReport{}
+	err := json.Unmarshal([]byte(record.Report), &report)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode report %d: %w", record.ID, err)
+	}
+
+	report.ID = record.ID
+
+	return &report, nil
+}
+
+// reportsGet returns the stored health reports, oldest first.
+func reportsGet(state state.State, r *http.Request) response.Response {
+	var records []database.Report
+	err := state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetReports(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	reports := make([]types.HealthReport, 0, len(records))
+	for _, record := range records {
+		report, err := healthReport(record)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		reports = append(reports, *report)
+	}
+
+	return response.SyncResponse(true, reports)
+}
+
+// reportsPost generates and stores a health report of the cluster, and returns it.
+// Like the scheduled reports, the report is generated by the database leader.
+func reportsPost(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		report, err := GenerateHealthReport(r.Context(), sh, s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, report)
+	}
+}
+
+// reportGet returns the stored health report with the given ID.
+func reportGet(state state.State, r *http.Request) response.Response {
+	id, err := reportID(r)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var record *database.Report
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		record, err = database.GetReport(ctx, tx, id)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	report, err := healthReport(*record)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, report)
+}
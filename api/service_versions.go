@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ServiceVersionsCmd represents the /1.0/service-versions API on MicroCloud.
+var ServiceVersionsCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Name:              "service-versions",
+		Path:              "service-versions",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, serviceVersionsGet(sh))},
+	}
+}
+
+// serviceVersionsGet returns the version and API extensions of each service installed on the local system.
+func serviceVersionsGet(sh *service.Handler) endpointHandler {
+	return func(state state.State, r *http.Request) response.Response {
+		return response.SyncResponse(true, sh.ServiceVersions(r.Context()))
+	}
+}
@@ -23,53 +23,99 @@ var ServicesCmd = func(sh *service.Handler) rest.Endpoint {
 		Name:              "services",
 		Path:              "services",
 
-		Put: rest.EndpointAction{Handler: authHandlerMTLS(sh, servicesPut), ProxyTarget: true},
+		Put: rest.EndpointAction{Handler: authHandlerMTLS(sh, servicesPut(sh)), ProxyTarget: true},
 	}
 }
 
-// servicesPut updates the cluster status of the MicroCloud peer.
-func servicesPut(state state.State, r *http.Request) response.Response {
-	// Parse the request.
-	req := types.ServicesPut{}
+// JoinProgressCmd represents the /1.0/join-progress API on MicroCloud.
+var JoinProgressCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		AllowedBeforeInit: true,
+		Path:              "join-progress",
 
-	err := json.NewDecoder(r.Body).Decode(&req)
-	if err != nil {
-		return response.BadRequest(err)
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, joinProgressGet(sh))},
 	}
+}
 
-	joinConfigs := map[types.ServiceType]service.JoinConfig{}
-	services := make([]types.ServiceType, len(req.Tokens))
-	for i, cfg := range req.Tokens {
-		services[i] = types.ServiceType(cfg.Service)
-		joinConfigs[cfg.Service] = service.JoinConfig{Token: cfg.JoinToken, LXDConfig: req.LXDConfig, CephConfig: req.CephConfig, OVNConfig: req.OVNConfig}
+// joinProgressGet returns the progress of this system joining each service, so the joining system can follow it.
+func joinProgressGet(sh *service.Handler) endpointHandler {
+	return func(state state.State, r *http.Request) response.Response {
+		return response.SyncResponse(true, sh.JoinProgress())
 	}
+}
 
-	// Default to the first iface if none specified.
-	addr := util.NetworkInterfaceAddress()
-	if req.Address != "" {
-		addr = req.Address
-	}
+// servicesPut updates the cluster status of the MicroCloud peer.
+func servicesPut(daemon *service.Handler) endpointHandler {
+	return func(state state.State, r *http.Request) response.Response {
+		// Parse the request.
+		req := types.ServicesPut{}
 
-	sh, err := service.NewHandler(state.Name(), addr, state.FileSystem().StateDir(), services...)
-	if err != nil {
-		return response.SmartError(err)
-	}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		joinConfigs := map[types.ServiceType]service.JoinConfig{}
+		services := make([]types.ServiceType, len(req.Tokens))
+		for i, cfg := range req.Tokens {
+			services[i] = types.ServiceType(cfg.Service)
+			joinConfigs[cfg.Service] = service.JoinConfig{Token: cfg.JoinToken, LXDConfig: req.LXDConfig, CephConfig: req.CephConfig, OVNConfig: req.OVNConfig, LXDAddress: req.LXDAddress, LXDClusterAddress: req.LXDClusterAddress}
+		}
+
+		// Default to the first iface if none specified.
+		addr := util.NetworkInterfaceAddress()
+		if req.Address != "" {
+			addr = req.Address
+		}
+
+		sh, err := service.NewHandler(state.Name(), addr, state.FileSystem().StateDir(), services...)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Track each join, so that only the failed ones have to be retried.
+		for _, serviceType := range services {
+			recordJoinState(r.Context(), state, serviceType, req, types.JoinStatePending, nil)
+		}
+
+		daemon.StartJoinProgress(state.Name(), services)
 
-	err = sh.RunConcurrent(types.MicroCloud, types.LXD, func(s service.Service) error {
-		// set a 5 minute context for completing the join request in case the system is very slow.
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
-		defer cancel()
+		err = sh.RunConcurrent(types.MicroCloud, types.LXD, func(s service.Service) error {
+			// set a 5 minute context for completing the join request in case the system is very slow.
+			ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+			defer cancel()
 
-		err = s.Join(ctx, joinConfigs[s.Type()])
+			// LXD joins after MicroOVN, so the bridge can be created before LXD sets up the uplink network on it.
+			if s.Type() == types.LXD && req.NoUplink {
+				err := service.CreateNoUplinkBridge(ctx)
+				if err != nil {
+					recordJoinState(r.Context(), state, s.Type(), req, types.JoinStateFailed, err)
+					daemon.UpdateJoinProgress(s.Type(), types.JoinStateFailed, err)
+
+					return err
+				}
+			}
+
+			err := service.Retry(ctx, sh.RetryPolicy, func() error {
+				return s.Join(ctx, joinConfigs[s.Type()])
+			})
+			if err != nil {
+				err = fmt.Errorf("Failed to join %q cluster: %w", s.Type(), err)
+				recordJoinState(r.Context(), state, s.Type(), req, types.JoinStateFailed, err)
+				daemon.UpdateJoinProgress(s.Type(), types.JoinStateFailed, err)
+
+				return err
+			}
+
+			recordJoinState(r.Context(), state, s.Type(), req, types.JoinStateJoined, nil)
+			daemon.UpdateJoinProgress(s.Type(), types.JoinStateJoined, nil)
+
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("Failed to join %q cluster: %w", s.Type(), err)
+			return response.SmartError(err)
 		}
 
-		return nil
-	})
-	if err != nil {
-		return response.SmartError(err)
+		return response.EmptySyncResponse
 	}
-
-	return response.EmptySyncResponse
 }
@@ -3,9 +3,13 @@ package api
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/lxd/shared/trust"
 	"github.com/canonical/microcluster/v3/microcluster/rest/response"
@@ -17,6 +21,60 @@ import (
 // endpointHandler is just a convenience for writing clean return types.
 type endpointHandler func(state.State, *http.Request) response.Response
 
+// limitRequests rejects the requests of the clients exceeding the configured rate limit or quota of the MicroCloud API.
+// Clients are told apart by their address. Requests through the unix socket and from the other cluster members are never limited.
+func limitRequests(sh *service.Handler, s state.State, r *http.Request) response.Response {
+	if r.RemoteAddr == "@" {
+		return nil
+	}
+
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+
+	for _, address := range s.Remotes().Addresses() {
+		if address.Addr().String() == client {
+			return nil
+		}
+	}
+
+	reason := sh.AllowRequest(client, time.Now())
+	if reason == "" {
+		return nil
+	}
+
+	logger.Debug("Rejected request exceeding the request limits", logger.Ctx{"client": client, "reason": reason, "path": r.URL.Path})
+
+	return response.SmartError(api.StatusErrorf(http.StatusTooManyRequests, "Too many requests, exceeded the %s limit of the MicroCloud API", reason))
+}
+
+// trustedMTLS returns whether the request was authenticated using mTLS with the certificate of a cluster member or of a trusted client.
+func trustedMTLS(sh *service.Handler, s state.State, r *http.Request) bool {
+	// Use certificate based authentication between cluster members.
+	if r.TLS == nil {
+		return false
+	}
+
+	trustedCerts := s.Remotes().CertificatesNative()
+	for _, cert := range r.TLS.PeerCertificates {
+		// First evaluate the permanent turst store.
+		trusted, _ := util.CheckMutualTLS(*cert, trustedCerts)
+		if trusted {
+			return true
+		}
+
+		// Second evaluate the temporary trust store.
+		// This is the fallback during the forming of the cluster.
+		trusted, _ = util.CheckMutualTLS(*cert, sh.TemporaryTrustStore())
+		if trusted {
+			return true
+		}
+	}
+
+	return false
+}
+
 // authHandlerMTLS ensures a request has been authenticated using mTLS.
 func authHandlerMTLS(sh *service.Handler, f endpointHandler) endpointHandler {
 	return func(s state.State, r *http.Request) response.Response {
@@ -26,32 +84,62 @@ func authHandlerMTLS(sh *service.Handler, f endpointHandler) endpointHandler {
 			return f(s, r)
 		}
 
-		// Use certificate based authentication between cluster members.
-		if r.TLS != nil {
-			trustedCerts := s.Remotes().CertificatesNative()
-			for _, cert := range r.TLS.PeerCertificates {
-				// First evaluate the permanent turst store.
-				trusted, _ := util.CheckMutualTLS(*cert, trustedCerts)
-				if trusted {
-					return f(s, r)
-				}
+		resp := limitRequests(sh, s, r)
+		if resp != nil {
+			return resp
+		}
 
-				// Second evaluate the temporary trust store.
-				// This is the fallback during the forming of the cluster.
-				trusted, _ = util.CheckMutualTLS(*cert, sh.TemporaryTrustStore())
-				if trusted {
-					return f(s, r)
-				}
-			}
+		if trustedMTLS(sh, s, r) {
+			return f(s, r)
 		}
 
 		return response.Forbidden(errors.New("Failed to authenticate using mTLS"))
 	}
 }
 
+// allowAuthToken returns an access handler accepting the requests authenticated like with authHandlerMTLS,
+// or with an API token allowing the given role in the "Authorization: Bearer" header.
+// It must be used as the AccessHandler of an untrusted endpoint action, so it also runs before requests are proxied with "?target=".
+func allowAuthToken(sh *service.Handler, role string) func(state.State, *http.Request) (bool, response.Response) {
+	return func(s state.State, r *http.Request) (bool, response.Response) {
+		if r.RemoteAddr == "@" {
+			logger.Debug("Allowing unauthenticated request through unix socket")
+
+			return true, nil
+		}
+
+		resp := limitRequests(sh, s, r)
+		if resp != nil {
+			return false, resp
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			if trustedMTLS(sh, s, r) {
+				return true, nil
+			}
+
+			return false, response.Forbidden(errors.New("Failed to authenticate using mTLS or an API token"))
+		}
+
+		err := checkAuthToken(r.Context(), s, token, role)
+		if err != nil {
+			return false, response.SmartError(err)
+		}
+
+		return true, nil
+	}
+}
+
 // authHandlerHMAC ensures a request has been authenticated using the HMAC in the Authorization header.
 func authHandlerHMAC(sh *service.Handler, f endpointHandler) endpointHandler {
 	return func(s state.State, r *http.Request) response.Response {
+		// Limit the requests before checking the HMAC, as deriving its key is expensive.
+		resp := limitRequests(sh, s, r)
+		if resp != nil {
+			return resp
+		}
+
 		sessionFunc := func(session *service.Session) error {
 			h, err := trust.NewHMACArgon2([]byte(session.Passphrase()), nil, trust.NewDefaultHMACConf(HMACMicroCloud10))
 			if err != nil {
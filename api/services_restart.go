@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// serviceRestartTimeout is how long a service may take to restart and get ready again.
+const serviceRestartTimeout = 5 * time.Minute
+
+// serviceRefreshTimeout is how long a service may take to refresh and get ready again.
+const serviceRefreshTimeout = 20 * time.Minute
+
+// ServiceRestartCmd represents the /1.0/services/serviceType/restart API on MicroCloud.
+var ServiceRestartCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "services/{serviceType}/restart",
+		Path: "services/{serviceType}/restart",
+
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, serviceRestartPost(sh)), ProxyTarget: true},
+	}
+}
+
+// ServiceRefreshCmd represents the /1.0/services/serviceType/refresh API on MicroCloud.
+var ServiceRefreshCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "services/{serviceType}/refresh",
+		Path: "services/{serviceType}/refresh",
+
+		Post: rest.EndpointAction{Handler: authHandlerMTLS(sh, serviceRefreshPost(sh)), ProxyTarget: true},
+	}
+}
+
+// localService returns the local service given in the request path, along with the name of its snap.
+func localService(sh *service.Handler, r *http.Request) (service.Service, string, error) {
+	serviceType, err := url.PathUnescape(mux.Vars(r)["serviceType"])
+	if err != nil {
+		return nil, "", err
+	}
+
+	snap, ok := service.RestartableServices[types.ServiceType(serviceType)]
+	if !ok {
+		return nil, "", api.StatusErrorf(http.StatusBadRequest, "Service %q is not managed through its snap by MicroCloud", serviceType)
+	}
+
+	svc := sh.Services[types.ServiceType(serviceType)]
+	if svc == nil {
+		return nil, "", api.StatusErrorf(http.StatusNotFound, "Service %q is not installed", serviceType)
+	}
+
+	return svc, snap, nil
+}
+
+// serviceRestartPost restarts the snap of the given service on this cluster member, and waits for the service to get ready again.
+func serviceRestartPost(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		svc, snap, err := localService(sh, r)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), serviceRestartTimeout)
+		defer cancel()
+
+		err = service.RestartSnap(ctx, snap)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = service.WaitReady(ctx, svc)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+}
+
+// serviceRefreshPost refreshes the snap of the given service on this cluster member, and waits for the service to get ready again.
+func serviceRefreshPost(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		args := types.ServiceRefreshPost{}
+		err := json.NewDecoder(r.Body).Decode(&args)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		svc, snap, err := localService(sh, r)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), serviceRefreshTimeout)
+		defer cancel()
+
+		oldVersion, err := svc.GetVersion(ctx)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = service.RefreshSnap(ctx, snap, args.Channel)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = service.WaitReady(ctx, svc)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		newVersion, err := svc.GetVersion(ctx)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if newVersion != oldVersion {
+			RecordEvent(r.Context(), s, types.EventUpgrade, fmt.Sprintf("Refreshed %s from %s to %s", svc.Type(), oldVersion, newVersion))
+		}
+
+		return response.SyncResponse(true, newVersion)
+	}
+}
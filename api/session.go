@@ -29,6 +29,9 @@ import (
 // HMACMicroCloud10 is the HMAC format version used during trust establishment.
 const HMACMicroCloud10 trust.HMACVersion = "MicroCloud-1.0"
 
+// errSessionTimeout is the cause of a session ending because it ran out of time.
+var errSessionTimeout = errors.New("Session timeout exceeded")
+
 // SessionInitiatingCmd represents the /1.0/session/initiating API on MicroCloud.
 var SessionInitiatingCmd = func(sh *service.Handler) rest.Endpoint {
 	return rest.Endpoint{
@@ -54,8 +57,23 @@ var SessionJoiningCmd = func(sh *service.Handler) rest.Endpoint {
 // sessionGet returns a MicroCloud join session.
 func sessionGet(sh *service.Handler, sessionRole types.SessionRole) func(state state.State, r *http.Request) response.Response {
 	return func(state state.State, r *http.Request) response.Response {
-		if sh.ActiveSession() {
-			return response.BadRequest(errors.New("There already is an active session"))
+		attachID := r.URL.Query().Get("attach")
+		if attachID == "" && sh.ActiveSession() {
+			detached := false
+			_ = sh.SessionTransaction(true, func(session *service.Session) error {
+				detached = session.Detached()
+				return nil
+			})
+
+			if !detached {
+				return response.BadRequest(errors.New("There already is an active session"))
+			}
+
+			// A session which lost its client gets replaced by the new one.
+			err := sh.StopSession(errors.New("Replaced by a new session"))
+			if err != nil {
+				return response.SmartError(err)
+			}
 		}
 
 		sessionTimeoutStr := r.URL.Query().Get("timeout")
@@ -89,14 +107,19 @@ func sessionGet(sh *service.Handler, sessionRole types.SessionRole) func(state s
 				}
 			}()
 
-			sessionCtx, cancel := context.WithTimeoutCause(r.Context(), sessionTimeout, errors.New("Session timeout exceeded"))
+			sessionCtx, cancel := context.WithTimeoutCause(r.Context(), sessionTimeout, errSessionTimeout)
 			defer cancel()
 
 			gw := cloudClient.NewWebsocketGateway(sessionCtx, conn)
 
 			switch sessionRole {
 			case types.SessionInitiating:
-				err = handleInitiatingSession(state, sh, gw)
+				if attachID != "" {
+					err = handleAttachingSession(state, sh, gw, attachID)
+				} else {
+					err = handleInitiatingSession(state, sh, gw)
+				}
+
 			case types.SessionJoining:
 				err = handleJoiningSession(state, sh, gw)
 			}
@@ -118,6 +141,16 @@ func sessionGet(sh *service.Handler, sessionRole types.SessionRole) func(state s
 }
 
 func confirmedIntents(sh *service.Handler, gw *cloudClient.WebsocketGateway) ([]types.SessionJoinPost, error) {
+	// Forward the join intents which were already forwarded to a previous client of the session.
+	for _, intent := range sh.Session.ForwardedIntents() {
+		err := gw.Write(types.Session{
+			Intent: intent,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to forward join intent: %w", err)
+		}
+	}
+
 	for {
 		select {
 		case intent, ok := <-sh.Session.IntentCh():
@@ -126,6 +159,7 @@ func confirmedIntents(sh *service.Handler, gw *cloudClient.WebsocketGateway) ([]
 				continue
 			}
 
+			sh.Session.RecordIntent(intent)
 			err := gw.Write(types.Session{
 				Intent: intent,
 			})
@@ -159,29 +193,91 @@ func handleInitiatingSession(state state.State, sh *service.Handler, gw *cloudCl
 		return fmt.Errorf("Failed to start session: %w", err)
 	}
 
+	detached := false
 	defer func() {
+		if detached {
+			return
+		}
+
 		err := sh.StopSession(nil)
 		if err != nil {
 			logger.Error("Failed to stop session", logger.Ctx{"err": err})
 		}
 	}()
 
-	err = sh.Session.MulticastDiscovery(state.Name(), session.Address, session.Interface)
+	sh.Session.SetDetails(session)
+	err = sh.Session.MulticastDiscovery(state.Name(), session.Address, session.AltAddress, session.Interface)
 	if err != nil {
 		return fmt.Errorf("Failed to start multicast discovery: %w", err)
 	}
 
-	sessionPassphrase := sh.Session.Passphrase()
 	err = gw.Write(types.Session{
-		Passphrase: sessionPassphrase,
+		ID:         sh.Session.ID(),
+		Passphrase: sh.Session.Passphrase(),
 	})
 	if err != nil {
 		return fmt.Errorf("Failed to send session details: %w", err)
 	}
 
+	detached, err = confirmInitiatingSession(state, sh, gw)
+	return err
+}
+
+// handleAttachingSession hands the initiating session which lost its client over to a new client.
+func handleAttachingSession(state state.State, sh *service.Handler, gw *cloudClient.WebsocketGateway, id string) error {
+	err := sh.SessionTransaction(true, func(session *service.Session) error {
+		if session.Role() != types.SessionInitiating {
+			return fmt.Errorf("No detached session %q", id)
+		}
+
+		return session.Attach(id, gw)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to attach to session: %w", err)
+	}
+
+	detached := false
+	defer func() {
+		if detached {
+			return
+		}
+
+		err := sh.StopSession(nil)
+		if err != nil {
+			logger.Error("Failed to stop session", logger.Ctx{"err": err})
+		}
+	}()
+
+	// Send the details the session was started with, so the new client can continue where the previous one stopped.
+	session := sh.Session.Details()
+	session.ID = sh.Session.ID()
+	session.Passphrase = sh.Session.Passphrase()
+	err = gw.Write(session)
+	if err != nil {
+		return fmt.Errorf("Failed to send session details: %w", err)
+	}
+
+	detached, err = confirmInitiatingSession(state, sh, gw)
+	return err
+}
+
+// confirmInitiatingSession forwards the join intents to the client and confirms the ones selected by the client.
+// If the connection to the client got lost before any got selected, the session is detached instead and true is returned.
+func confirmInitiatingSession(state state.State, sh *service.Handler, gw *cloudClient.WebsocketGateway) (bool, error) {
+	session := sh.Session.Details()
+	sessionPassphrase := sh.Session.Passphrase()
+
 	confirmedIntents, err := confirmedIntents(sh, gw)
 	if err != nil {
-		return fmt.Errorf("Failed waiting for the confirmed intents: %w", err)
+		// Keep the session if only the connection to the client got lost, so another client can attach to it.
+		if gw.Context().Err() != nil && !errors.Is(context.Cause(gw.Context()), errSessionTimeout) && sh.ActiveSession() {
+			logger.Warn("Lost the client of the session, waiting for a client to attach", logger.Ctx{"session": sh.Session.ID(), "err": err})
+			sh.Session.Detach(service.DetachedSessionTimeout)
+
+			return true, nil
+		}
+
+		return false, fmt.Errorf("Failed waiting for the confirmed intents: %w", err)
 	}
 
 	g, ctx := errgroup.WithContext(context.Background())
@@ -190,7 +286,7 @@ func handleInitiatingSession(state state.State, sh *service.Handler, gw *cloudCl
 	for _, intent := range confirmedIntents {
 		remoteCert, err := shared.ParseCert([]byte(intent.Certificate))
 		if err != nil {
-			return fmt.Errorf("Failed to parse certificate of confirmed intent: %w", err)
+			return false, fmt.Errorf("Failed to parse certificate of confirmed intent: %w", err)
 		}
 
 		// Add system to temporary truststore.
@@ -199,25 +295,26 @@ func handleInitiatingSession(state state.State, sh *service.Handler, gw *cloudCl
 		cloud := sh.Services[types.MicroCloud].(*service.CloudService)
 		cert, err := cloud.ServerCert()
 		if err != nil {
-			return fmt.Errorf("Failed to get certificate of %q: %w", types.MicroCloud, err)
+			return false, fmt.Errorf("Failed to get certificate of %q: %w", types.MicroCloud, err)
 		}
 
 		joinIntent := types.SessionJoinPost{
-			Version:     multicast.Version,
-			Name:        state.Name(),
-			Address:     session.Address,
+			Version: multicast.Version,
+			Name:    state.Name(),
+			// On dual-stack systems, confirm with the address of the IP family the joiner reached us with.
+			Address:     multicast.PreferFamily(intent.Address, session.Address, session.AltAddress),
 			Certificate: string(cert.PublicKey()),
 			Services:    session.Services,
 		}
 
 		h, err := trust.NewHMACArgon2([]byte(sessionPassphrase), nil, trust.NewDefaultHMACConf(HMACMicroCloud10))
 		if err != nil {
-			return fmt.Errorf("Failed to create a new HMAC instance using argon2: %w", err)
+			return false, fmt.Errorf("Failed to create a new HMAC instance using argon2: %w", err)
 		}
 
 		header, err := trust.HMACAuthorizationHeader(h, joinIntent)
 		if err != nil {
-			return fmt.Errorf("Failed to create HMAC for join intent: %w", err)
+			return false, fmt.Errorf("Failed to create HMAC for join intent: %w", err)
 		}
 
 		// Confirm join intent.
@@ -240,17 +337,17 @@ func handleInitiatingSession(state state.State, sh *service.Handler, gw *cloudCl
 
 	err = g.Wait()
 	if err != nil {
-		return fmt.Errorf("Failed to confirm join intents: %w", err)
+		return false, fmt.Errorf("Failed to confirm join intents: %w", err)
 	}
 
 	err = gw.Write(types.Session{
 		Accepted: true,
 	})
 	if err != nil {
-		return fmt.Errorf("Failed to send confirmation: %w", err)
+		return false, fmt.Errorf("Failed to send confirmation: %w", err)
 	}
 
-	return nil
+	return false, nil
 }
 
 func handleJoiningSession(state state.State, sh *service.Handler, gw *cloudClient.WebsocketGateway) error {
@@ -283,9 +380,12 @@ func handleJoiningSession(state state.State, sh *service.Handler, gw *cloudClien
 			return fmt.Errorf("Failed to lookup eligible system: %w", err)
 		}
 
-		session.InitiatorAddress = peer.Address
+		session.InitiatorAddress = peer.ReachableAddress(lookupCtx, service.CloudPort)
 	}
 
+	// On dual-stack systems, use the address of the IP family the initiator is reached with.
+	session.Address = multicast.PreferFamily(session.InitiatorAddress, session.Address, session.AltAddress)
+
 	// Get the remotes name.
 	cloud := sh.Services[types.MicroCloud].(*service.CloudService)
 	cert, err := cloud.ServerCert()
@@ -340,6 +440,7 @@ func handleJoiningSession(state state.State, sh *service.Handler, gw *cloudClien
 
 	// Notify the client we have found an eligible system.
 	err = gw.Write(types.Session{
+		Address:              session.Address,
 		InitiatorName:        session.InitiatorName,
 		InitiatorAddress:     session.InitiatorAddress,
 		InitiatorFingerprint: session.InitiatorFingerprint,
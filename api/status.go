@@ -30,10 +30,13 @@ var StatusCmd = func(sh *service.Handler) rest.Endpoint {
 		Name: "status",
 		Path: "status",
 
-		Get: rest.EndpointAction{Handler: statusGet(sh), ProxyTarget: true},
+		Get: rest.EndpointAction{Handler: statusGet(sh), AllowUntrusted: true, AccessHandler: allowAuthToken(sh, types.AuthTokenRoleReadOnly), ProxyTarget: true},
 	}
 }
 
+// statusGet returns the status of the cluster members, as reported by each member.
+// The statuses can be filtered and paginated with the member, service, status, limit and offset query parameters.
+// Cluster members only return their own status when notified by another member.
 func statusGet(sh *service.Handler) endpointHandler {
 	// statusMu is used to synchronize map writes to the returned status information, as we populate cluster members for each service concurrently.
 	var statusMu sync.Mutex
@@ -41,13 +44,28 @@ func statusGet(sh *service.Handler) endpointHandler {
 	return func(s state.State, r *http.Request) response.Response {
 		statuses := []types.Status{}
 
+		filter, err := listFilter(r)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
 		if !microClient.IsNotification(r) {
 			cluster, err := s.Cluster(true)
 			if err != nil {
 				return response.SmartError(err)
 			}
 
+			// Only query the cluster members matching the member filter.
+			names := map[string]string{}
+			for name, address := range s.Remotes().Addresses() {
+				names[address.String()] = name
+			}
+
 			err = cluster.Query(r.Context(), true, func(ctx context.Context, c *microClient.Client) error {
+				if !matchMember(filter, names[c.URL().URL.Host]) {
+					return nil
+				}
+
 				memberStatuses, err := client.GetStatus(ctx, c)
 				if err != nil {
 					logger.Error("Failed to get status for cluster member", logger.Ctx{"error": err, "address": c.URL()})
@@ -76,13 +94,19 @@ func statusGet(sh *service.Handler) endpointHandler {
 			address = addrPort.Addr().String()
 		}
 
+		platform := service.LocalPlatform()
 		status := &types.Status{
-			Name:         s.Name(),
-			Address:      address,
-			Clusters:     make(map[types.ServiceType][]microTypes.ClusterMember, len(sh.Services)),
-			OSDs:         []cephTypes.Disk{},
-			CephServices: []cephTypes.Service{},
-			OVNServices:  []ovnTypes.Service{},
+			Name:          s.Name(),
+			Address:       address,
+			Clusters:      make(map[types.ServiceType][]microTypes.ClusterMember, len(sh.Services)),
+			OSDs:          []cephTypes.Disk{},
+			CephServices:  []cephTypes.Service{},
+			OVNServices:   []ovnTypes.Service{},
+			UnderlayPaths: sh.OVNUnderlayPaths(),
+			Liveness:      sh.MemberLiveness(),
+			Clocks:        sh.MemberClocks(),
+			Platform:      &platform,
+			Boot:          sh.BootProgress(),
 		}
 
 		err = sh.RunConcurrent("", "", func(s service.Service) error {
@@ -143,7 +167,11 @@ func statusGet(sh *service.Handler) endpointHandler {
 
 		statuses = append(statuses, *status)
 
-		return response.SyncResponse(true, statuses)
+		if microClient.IsNotification(r) {
+			return response.SyncResponse(true, statuses)
+		}
+
+		return response.SyncResponse(true, filterStatuses(statuses, s.Name(), filter))
 	}
 }
 
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// TuningCmd represents the /1.0/tuning API on MicroCloud.
+var TuningCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "tuning",
+
+		Get:    rest.EndpointAction{Handler: authHandlerMTLS(sh, tuningGet)},
+		Post:   rest.EndpointAction{Handler: authHandlerMTLS(sh, tuningPost), ProxyTarget: true},
+		Delete: rest.EndpointAction{Handler: authHandlerMTLS(sh, tuningDelete), ProxyTarget: true},
+	}
+}
+
+// tuningSettings converts the recorded kernel settings to their API representation.
+func tuningSettings(records []database.TuningSetting) []types.TuningSetting {
+	settings := make([]types.TuningSetting, 0, len(records))
+	for _, record := range records {
+		settings = append(settings, types.TuningSetting{
+			Member:    record.Member,
+			Key:       record.Key,
+			Previous:  record.Previous,
+			Value:     record.Value,
+			AppliedAt: record.AppliedAt,
+		})
+	}
+
+	return settings
+}
+
+// tuningGet returns the kernel settings changed on the cluster member given in the member query parameter, or on all cluster members.
+func tuningGet(state state.State, r *http.Request) response.Response {
+	var records []database.TuningSetting
+	err := state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetTuning(ctx, tx, r.FormValue("member"))
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, tuningSettings(records))
+}
+
+// tuningPost applies the recommended kernel settings on this cluster member, and records the changed settings.
+func tuningPost(state state.State, r *http.Request) response.Response {
+	args := types.TuningPost{}
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if args.Hugepages < 0 || args.Hugepages > service.MaximumHugepagesShare {
+		return response.BadRequest(fmt.Errorf("Hugepages must be between 0 and %d percent of the memory", service.MaximumHugepagesShare))
+	}
+
+	targets, err := service.TuningTargets(args.Hugepages)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	current, err := service.CurrentSysctls(targets)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	changes := service.PlanTuning(current, targets)
+	err = service.ApplyTuning(changes)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	now := time.Now().UTC()
+	records := make([]database.TuningSetting, 0, len(changes))
+	for _, change := range changes {
+		records = append(records, database.TuningSetting{Member: state.Name(), Key: change.Key, Previous: change.Previous, Value: change.Value, AppliedAt: now})
+	}
+
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		for _, record := range records {
+			err := database.RecordTuning(ctx, tx, record)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Don't leave changes behind which can't be reverted later.
+		for _, change := range changes {
+			_ = service.WriteSysctl(change.Key, change.Previous)
+		}
+
+		return response.SmartError(err)
+	}
+
+	if len(changes) > 0 {
+		RecordEvent(r.Context(), state, types.EventTuning, fmt.Sprintf("Applied %d kernel settings", len(changes)))
+	}
+
+	return response.SyncResponse(true, tuningSettings(records))
+}
+
+// tuningDelete restores the kernel settings changed on this cluster member to their values from before the tuning.
+func tuningDelete(state state.State, r *http.Request) response.Response {
+	var records []database.TuningSetting
+	err := state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetTuning(ctx, tx, state.Name())
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, record := range records {
+		err := service.WriteSysctl(record.Key, record.Previous)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = state.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+		return database.DeleteTuning(ctx, tx, state.Name())
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(records) > 0 {
+		RecordEvent(r.Context(), state, types.EventTuning, fmt.Sprintf("Reverted %d kernel settings", len(records)))
+	}
+
+	return response.SyncResponse(true, tuningSettings(records))
+}
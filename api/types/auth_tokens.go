@@ -0,0 +1,57 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	// AuthTokenRoleReadOnly is the role of the API tokens which can only query the cluster.
+	AuthTokenRoleReadOnly = "read-only"
+
+	// AuthTokenRoleOperator is the role of the API tokens which can also run the checks and reports of the cluster.
+	AuthTokenRoleOperator = "operator"
+)
+
+// AuthToken is a time-limited API token used by automation instead of a client certificate.
+type AuthToken struct {
+	// Name of the token
+	// Example: ci-pipeline
+	Name string `json:"name" yaml:"name"`
+
+	// Role of the token
+	// Example: read-only
+	Role string `json:"role" yaml:"role"`
+
+	// Time the token was created
+	// Example: 2024-01-01T00:00:00Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Time the token stops being accepted
+	// Example: 2024-01-31T00:00:00Z
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// AuthTokensPost represents a request to create an API token.
+type AuthTokensPost struct {
+	// Name of the token
+	// Example: ci-pipeline
+	Name string `json:"name" yaml:"name"`
+
+	// Role of the token
+	// Example: read-only
+	Role string `json:"role" yaml:"role"`
+
+	// Time after which the token expires, in the "<integer>(S|M|H|d|w|m|y)" format
+	// Example: 30d
+	Expiry string `json:"expiry" yaml:"expiry"`
+}
+
+// AuthTokenSecret is a newly created API token, along with its secret.
+// The secret is only returned once, as MicroCloud only stores its hash.
+type AuthTokenSecret struct {
+	AuthToken `yaml:",inline"`
+
+	// Secret of the token, to send in the "Authorization: Bearer" header
+	// Example: 2f0c3d...
+	Token string `json:"token" yaml:"token"`
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"time"
+)
+
+// Benchmark represents the result of a storage benchmark of a storage pool on a cluster member.
+type Benchmark struct {
+	// Name of the cluster member the benchmark ran on
+	// Example: micro01
+	Member string `json:"member" yaml:"member"`
+
+	// Name of the benchmarked storage pool
+	// Example: remote
+	Pool string `json:"pool" yaml:"pool"`
+
+	// Name of the fio test
+	// Example: randread
+	Test string `json:"test" yaml:"test"`
+
+	// Operations per second
+	// Example: 12034.5
+	IOPS float64 `json:"iops" yaml:"iops"`
+
+	// Bandwidth in bytes per second
+	// Example: 49293312
+	Bandwidth int64 `json:"bandwidth" yaml:"bandwidth"`
+
+	// Mean completion latency in nanoseconds
+	// Example: 2651422
+	Latency int64 `json:"latency" yaml:"latency"`
+
+	// Time the benchmark ran at
+	// Example: 2025-03-26T11:37:17.83536772Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// BenchmarksPost represents a request to benchmark the storage pools of a cluster member.
+type BenchmarksPost struct {
+	// Storage pools to benchmark. All supported storage pools are benchmarked if empty.
+	// Example: ["local", "remote"]
+	Pools []string `json:"pools" yaml:"pools"`
+
+	// Duration of each fio test in seconds
+	// Example: 10
+	Runtime int64 `json:"runtime" yaml:"runtime"`
+
+	// Whether to record the results as the new baseline of the cluster member
+	// Example: true
+	Baseline bool `json:"baseline" yaml:"baseline"`
+}
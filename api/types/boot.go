@@ -0,0 +1,35 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	// BootStepWaiting is the status of a boot step which MicroCloud is still waiting for.
+	BootStepWaiting = "waiting"
+
+	// BootStepReady is the status of a boot step which completed.
+	BootStepReady = "ready"
+
+	// BootStepFailed is the status of a boot step which didn't complete in time.
+	BootStepFailed = "failed"
+)
+
+// BootStep is the state of a step MicroCloud waits for in dependency order after the system started.
+type BootStep struct {
+	// Name of the step
+	// Example: MicroCeph
+	Name string `json:"name" yaml:"name"`
+
+	// Status of the step
+	// Example: ready
+	Status string `json:"status" yaml:"status"`
+
+	// Error of the last attempt, if any
+	// Example: Storage pool "remote" is unavailable
+	Error string `json:"error" yaml:"error"`
+
+	// Time the status last changed
+	// Example: 2025-03-26T11:37:17.83536772Z
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
+}
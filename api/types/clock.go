@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+)
+
+// Clock is the clock of a system, as reported by its MicroCloud daemon.
+type Clock struct {
+	// Time is the current time of the system.
+	Time time.Time `json:"time" yaml:"time"`
+
+	// Synchronized is whether the kernel considers the clock synchronized to a time source, such as NTP.
+	Synchronized bool `json:"synchronized" yaml:"synchronized"`
+}
@@ -0,0 +1,16 @@
+package types
+
+// ServiceVersion is the version and API extensions of a service installed on a system.
+type ServiceVersion struct {
+	// Version of the service, empty if it couldn't be determined
+	// Example: 5.21.3
+	Version string `json:"version" yaml:"version"`
+
+	// API extensions provided by the service
+	// Example: ["clustering_join_token"]
+	Extensions []string `json:"extensions" yaml:"extensions"`
+
+	// Error determining the version or API extensions, such as an unsupported version
+	// Example: LXD version "5.0.3" is not supported
+	Error string `json:"error" yaml:"error"`
+}
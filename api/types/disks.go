@@ -0,0 +1,19 @@
+package types
+
+// DisksWipe represents a request to wipe disks of a cluster member, so they can be set up again.
+type DisksWipe struct {
+	// Paths of the block devices to wipe
+	// Example: ["/dev/disk/by-id/nvme-disk1"]
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
+// SystemDisk is a disk holding the operating system of a cluster member, which is not offered for storage.
+type SystemDisk struct {
+	// ID of the disk, as reported in the LXD resources
+	// Example: sda
+	ID string `json:"id" yaml:"id"`
+
+	// Uses of the disk by the operating system, out of root, boot, efi and swap
+	// Example: ["root", "efi"]
+	Uses []string `json:"uses" yaml:"uses"`
+}
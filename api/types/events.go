@@ -0,0 +1,60 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	// EventMemberJoined is the type of events about a system joining the cluster, or bootstrapping it.
+	EventMemberJoined = "member-joined"
+
+	// EventMemberRemoved is the type of events about a cluster member being removed.
+	EventMemberRemoved = "member-removed"
+
+	// EventUpgrade is the type of events about a cluster member running a new version of MicroCloud.
+	EventUpgrade = "upgrade"
+
+	// EventHealth is the type of events about a health check changing state.
+	EventHealth = "health"
+
+	// EventConfig is the type of events about changes to the MicroCloud daemon configuration.
+	EventConfig = "config"
+
+	// EventTuning is the type of events about kernel tuning being applied or reverted on a cluster member.
+	EventTuning = "tuning"
+
+	// EventReport is the type of events about a scheduled health report being generated.
+	EventReport = "report"
+
+	// EventVerify is the type of events about a scheduled verification of a cluster member.
+	EventVerify = "verify"
+
+	// EventAuthToken is the type of events about API tokens being created or revoked.
+	EventAuthToken = "auth-token"
+)
+
+// EventTypes are the types of events recorded in the event history.
+var EventTypes = []string{EventMemberJoined, EventMemberRemoved, EventUpgrade, EventHealth, EventConfig, EventTuning, EventReport, EventVerify, EventAuthToken}
+
+// Event is a significant event in the history of the MicroCloud cluster.
+type Event struct {
+	// ID of the event
+	// Example: 12
+	ID int64 `json:"id" yaml:"id"`
+
+	// Type of the event
+	// Example: member-joined
+	Type string `json:"type" yaml:"type"`
+
+	// Name of the cluster member the event happened on
+	// Example: micro01
+	Member string `json:"member" yaml:"member"`
+
+	// Description of the event
+	// Example: Joined the cluster
+	Message string `json:"message" yaml:"message"`
+
+	// Time of the event
+	// Example: 2024-01-01T00:00:00Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"time"
+)
+
+// MemberInspection is the detailed state of a cluster member, as collected on the member itself.
+type MemberInspection struct {
+	// Name of the cluster member
+	// Example: micro01
+	Name string `json:"name" yaml:"name"`
+
+	// Services is the version and API extensions of each service installed on the member
+	Services map[ServiceType]ServiceVersion `json:"services" yaml:"services"`
+
+	// Certificates are the server certificates of the services on the member
+	Certificates []CertificateInfo `json:"certificates" yaml:"certificates"`
+
+	// OSDs are the states of the MicroCeph OSDs local to the member
+	OSDs []OSDState `json:"osds" yaml:"osds"`
+
+	// Instances is the number of LXD instances located on the member
+	// Example: 4
+	Instances int `json:"instances" yaml:"instances"`
+
+	// Resources is the resource usage of the member, as reported by LXD
+	Resources *MemberResources `json:"resources" yaml:"resources"`
+
+	// Errors are the parts of the member which couldn't be inspected
+	// Example: ["Failed to get LXD instances: not found"]
+	Errors []string `json:"errors" yaml:"errors"`
+}
+
+// CertificateInfo is the fingerprint and validity of a service's server certificate.
+type CertificateInfo struct {
+	// Service the certificate belongs to
+	// Example: LXD
+	Service ServiceType `json:"service" yaml:"service"`
+
+	// Fingerprint of the certificate
+	// Example: 7a6c8b2c3f...
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+
+	// NotAfter is the expiry date of the certificate
+	NotAfter time.Time `json:"not_after" yaml:"not_after"`
+}
+
+// OSDState is the state of a MicroCeph OSD on the member it is located on.
+type OSDState struct {
+	// OSD number
+	// Example: 1
+	OSD int64 `json:"osd" yaml:"osd"`
+
+	// Path of the disk backing the OSD
+	// Example: /dev/disk/by-id/nvme-Samsung_SSD_970_EVO_Plus_1TB_S4EWNX0N123456A
+	Path string `json:"path" yaml:"path"`
+
+	// Running is whether the OSD daemon is running on the member
+	// Example: true
+	Running bool `json:"running" yaml:"running"`
+}
+
+// MemberResources is the resource usage of a cluster member.
+type MemberResources struct {
+	// CPUs is the number of CPU threads
+	// Example: 16
+	CPUs uint64 `json:"cpus" yaml:"cpus"`
+
+	// MemoryTotal is the total memory in bytes
+	// Example: 34359738368
+	MemoryTotal uint64 `json:"memory_total" yaml:"memory_total"`
+
+	// MemoryUsed is the used memory in bytes
+	// Example: 8589934592
+	MemoryUsed uint64 `json:"memory_used" yaml:"memory_used"`
+}
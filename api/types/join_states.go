@@ -0,0 +1,42 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	// JoinStatePending is the status of a service the cluster member was asked to join, but didn't finish joining yet.
+	JoinStatePending = "pending"
+
+	// JoinStateJoined is the status of a service the cluster member joined.
+	JoinStateJoined = "joined"
+
+	// JoinStateFailed is the status of a service the cluster member failed to join.
+	JoinStateFailed = "failed"
+)
+
+// JoinState is the state of a cluster member joining the cluster of one of the services.
+type JoinState struct {
+	// Name of the cluster member
+	// Example: micro02
+	Member string `json:"member" yaml:"member"`
+
+	// Service the cluster member joins
+	// Example: MicroOVN
+	Service ServiceType `json:"service" yaml:"service"`
+
+	// Status of the join
+	// Example: failed
+	Status string `json:"status" yaml:"status"`
+
+	// Reason the join failed
+	// Example: Failed to join "MicroOVN" cluster: context deadline exceeded
+	Error string `json:"error" yaml:"error"`
+
+	// Join configuration of the cluster member, without the join tokens
+	Config ServicesPut `json:"config" yaml:"config"`
+
+	// Time of the last change of the join state
+	// Example: 2024-01-01T00:00:00Z
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package types
+
+// ListFilter is a server-side filter and pagination of a listing. Zero values match all entries.
+type ListFilter struct {
+	// Members only matches the entries of these cluster members
+	// Example: ["micro01", "micro02"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Service only matches the cluster members the service is set up on
+	// Example: MicroCeph
+	Service ServiceType `json:"service" yaml:"service"`
+
+	// Status only matches the cluster members with this MicroCloud status
+	// Example: ONLINE
+	Status string `json:"status" yaml:"status"`
+
+	// Limit is the maximum number of entries to return, or 0 for all of them
+	// Example: 20
+	Limit int `json:"limit" yaml:"limit"`
+
+	// Offset is the number of matching entries to skip
+	// Example: 40
+	Offset int `json:"offset" yaml:"offset"`
+}
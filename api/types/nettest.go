@@ -0,0 +1,96 @@
+package types
+
+const (
+	// NetworkTestManagement is the network the MicroCloud cluster members communicate over.
+	NetworkTestManagement = "management"
+
+	// NetworkTestCephPublic is the Ceph public network.
+	NetworkTestCephPublic = "ceph-public"
+
+	// NetworkTestCephCluster is the Ceph cluster network, used for replication between OSDs.
+	NetworkTestCephCluster = "ceph-cluster"
+
+	// NetworkTestOVNUnderlay is the network carrying the OVN Geneve tunnels.
+	NetworkTestOVNUnderlay = "ovn-underlay"
+)
+
+// NetworkTestNetworks are the networks which can be tested between cluster members.
+var NetworkTestNetworks = []string{NetworkTestManagement, NetworkTestCephPublic, NetworkTestCephCluster, NetworkTestOVNUnderlay}
+
+// NetworkTestServerPut represents a request to start the network test server of a cluster member.
+type NetworkTestServerPut struct {
+	// Token clients have to present to the test server
+	// Example: 5e2b9c6a0c3e4f1d
+	Token string `json:"token" yaml:"token"`
+
+	// Time in seconds after which the test server stops
+	// Example: 600
+	Timeout int64 `json:"timeout" yaml:"timeout"`
+}
+
+// NetworkTestServer represents the running network test server of a cluster member.
+type NetworkTestServer struct {
+	// Addresses of the cluster member on each network it is part of
+	// Example: {"management": "10.0.0.1", "ceph-cluster": "10.0.1.1"}
+	Addresses map[string]string `json:"addresses" yaml:"addresses"`
+}
+
+// NetworkTestPeer is a cluster member to measure the network link to.
+type NetworkTestPeer struct {
+	// Name of the cluster member
+	// Example: micro02
+	Name string `json:"name" yaml:"name"`
+
+	// Network to test
+	// Example: ceph-cluster
+	Network string `json:"network" yaml:"network"`
+
+	// Address of the cluster member on the network
+	// Example: 10.0.1.2
+	Address string `json:"address" yaml:"address"`
+}
+
+// NetworkTestPost represents a request to measure the network links from a cluster member to its peers.
+type NetworkTestPost struct {
+	// Token of the test servers of the peers
+	// Example: 5e2b9c6a0c3e4f1d
+	Token string `json:"token" yaml:"token"`
+
+	// Peers to measure the network links to
+	Peers []NetworkTestPeer `json:"peers" yaml:"peers"`
+
+	// Duration of each bandwidth measurement in seconds
+	// Example: 5
+	Duration int64 `json:"duration" yaml:"duration"`
+}
+
+// NetworkTestResult is the measurement of a network link between two cluster members.
+type NetworkTestResult struct {
+	// Name of the cluster member the measurement ran on
+	// Example: micro01
+	From string `json:"from" yaml:"from"`
+
+	// Name of the measured peer
+	// Example: micro02
+	To string `json:"to" yaml:"to"`
+
+	// Tested network
+	// Example: ceph-cluster
+	Network string `json:"network" yaml:"network"`
+
+	// Address of the peer on the network
+	// Example: 10.0.1.2
+	Address string `json:"address" yaml:"address"`
+
+	// Bandwidth in bits per second
+	// Example: 9412000000
+	Bandwidth int64 `json:"bandwidth" yaml:"bandwidth"`
+
+	// Mean round trip time in nanoseconds
+	// Example: 184000
+	Latency int64 `json:"latency" yaml:"latency"`
+
+	// Error preventing the measurement, if any
+	// Example: dial tcp 10.0.1.2:9445: connect: no route to host
+	Error string `json:"error" yaml:"error"`
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"time"
+)
+
+const (
+	// OperationRunning is the status of the cluster change holding the change lock.
+	OperationRunning = "running"
+
+	// OperationPending is the status of a cluster change waiting for the change lock.
+	OperationPending = "pending"
+)
+
+// Operation is a cluster change holding or waiting for the cluster-wide change lock.
+type Operation struct {
+	// ID of the operation
+	// Example: 3
+	ID int64 `json:"id" yaml:"id"`
+
+	// Type of the cluster change
+	// Example: add
+	Type string `json:"type" yaml:"type"`
+
+	// Name of the cluster member the change was started on
+	// Example: micro01
+	Member string `json:"member" yaml:"member"`
+
+	// Description of the cluster change
+	// Example: Adding new systems
+	Description string `json:"description" yaml:"description"`
+
+	// Whether the change holds the lock or waits for it
+	// Example: running
+	Status string `json:"status" yaml:"status"`
+
+	// Time the change was queued
+	// Example: 2024-01-01T00:00:00Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Time the client of the change last checked in
+	// Example: 2024-01-01T00:00:10Z
+	UpdatedAt time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// OperationsPost represents a request to queue a cluster change for the change lock.
+type OperationsPost struct {
+	// Type of the cluster change
+	// Example: add
+	Type string `json:"type" yaml:"type"`
+
+	// Description of the cluster change
+	// Example: Adding new systems
+	Description string `json:"description" yaml:"description"`
+}
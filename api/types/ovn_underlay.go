@@ -0,0 +1,19 @@
+package types
+
+// OVNUnderlay represents the address a cluster member uses for the OVN Geneve tunnels.
+type OVNUnderlay struct {
+	// Address carrying the Geneve tunnels of the member
+	// Example: 10.0.2.1
+	Address string `json:"address" yaml:"address"`
+
+	// Interface the address is configured on, if it is local to the member
+	// Example: enp7s0
+	Interface string `json:"interface" yaml:"interface"`
+}
+
+// OVNUnderlayPut represents a request to change the address a cluster member uses for the OVN Geneve tunnels.
+type OVNUnderlayPut struct {
+	// Address and prefix length, which must be configured on an interface of the member
+	// Example: 10.0.2.1/24
+	Address string `json:"address" yaml:"address"`
+}
@@ -0,0 +1,16 @@
+package types
+
+// Platform describes the environment a system runs in.
+type Platform struct {
+	// Whether the system is a virtual machine
+	// Example: true
+	Virtual bool `json:"virtual" yaml:"virtual"`
+
+	// Cloud the system runs on, if it could be identified
+	// Example: aws
+	Cloud string `json:"cloud" yaml:"cloud"`
+
+	// Whether KVM is available, so the system can run virtual machines itself
+	// Example: false
+	KVM bool `json:"kvm" yaml:"kvm"`
+}
@@ -0,0 +1,19 @@
+package types
+
+// ReachabilityPost represents a request to check that a system reaches the given MicroCloud addresses.
+type ReachabilityPost struct {
+	// Addresses to connect to, with their port
+	// Example: ["10.0.2.1:9443", "10.0.3.1:9443"]
+	Addresses []string `json:"addresses" yaml:"addresses"`
+}
+
+// Reachability is whether a system reaches an address.
+type Reachability struct {
+	// Address connected to
+	// Example: 10.0.2.1:9443
+	Address string `json:"address" yaml:"address"`
+
+	// Error preventing the connection, empty if the address is reachable
+	// Example: dial tcp 10.0.2.1:9443: i/o timeout
+	Error string `json:"error" yaml:"error"`
+}
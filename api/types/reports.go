@@ -0,0 +1,78 @@
+package types
+
+import (
+	"time"
+)
+
+// HealthReport is a snapshot of the health and capacity of the MicroCloud cluster.
+type HealthReport struct {
+	// ID of the report
+	// Example: 3
+	ID int64 `json:"id" yaml:"id"`
+
+	// Time the report was generated
+	// Example: 2024-01-01T00:00:00Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Member is the cluster member which generated the report
+	// Example: micro01
+	Member string `json:"member" yaml:"member"`
+
+	// Members are the health and capacity of each cluster member
+	Members []HealthReportMember `json:"members" yaml:"members"`
+
+	// StoragePools are the capacity of each LXD storage pool
+	StoragePools []HealthReportPool `json:"storage_pools" yaml:"storage_pools"`
+
+	// Warnings are the unacknowledged warnings raised by the services of the cluster
+	Warnings []ServiceWarning `json:"warnings" yaml:"warnings"`
+}
+
+// HealthReportMember is the health and capacity of a cluster member in a health report.
+type HealthReportMember struct {
+	// Name of the cluster member
+	// Example: micro01
+	Name string `json:"name" yaml:"name"`
+
+	// Status of the cluster member in MicroCloud
+	// Example: ONLINE
+	Status string `json:"status" yaml:"status"`
+
+	// Instances is the number of LXD instances located on the member
+	// Example: 4
+	Instances int `json:"instances" yaml:"instances"`
+
+	// Resources is the resource usage of the member, if it could be inspected
+	Resources *MemberResources `json:"resources" yaml:"resources"`
+
+	// OSDs is the number of MicroCeph OSDs on the member
+	// Example: 3
+	OSDs int `json:"osds" yaml:"osds"`
+
+	// OSDsDown is the number of MicroCeph OSDs on the member whose daemon isn't running
+	// Example: 0
+	OSDsDown int `json:"osds_down" yaml:"osds_down"`
+}
+
+// HealthReportPool is the capacity of an LXD storage pool in a health report.
+type HealthReportPool struct {
+	// Name of the storage pool
+	// Example: remote
+	Name string `json:"name" yaml:"name"`
+
+	// Driver of the storage pool
+	// Example: ceph
+	Driver string `json:"driver" yaml:"driver"`
+
+	// Member the capacity applies to, or empty for storage pools shared by the cluster
+	// Example: micro01
+	Member string `json:"member" yaml:"member"`
+
+	// Used is the used space in bytes
+	// Example: 10737418240
+	Used uint64 `json:"used" yaml:"used"`
+
+	// Total is the total space in bytes
+	// Example: 107374182400
+	Total uint64 `json:"total" yaml:"total"`
+}
@@ -30,6 +30,15 @@ type ServicesPut struct {
 	LXDConfig  []api.ClusterMemberConfigKey `json:"lxd_config" yaml:"lxd_config"`
 	CephConfig []types.DisksPost            `json:"ceph_config" yaml:"ceph_config"`
 	OVNConfig  map[string]string            `json:"ovn_config" yaml:"ovn_config"`
+
+	// LXDAddress is the address LXD listens on for its API, all addresses if empty.
+	LXDAddress string `json:"lxd_address" yaml:"lxd_address"`
+
+	// LXDClusterAddress is the address LXD listens on for the cluster traffic, the MicroCloud address if empty.
+	LXDClusterAddress string `json:"lxd_cluster_address" yaml:"lxd_cluster_address"`
+
+	// NoUplink indicates the joiner has no uplink connectivity, so the uplink network uses an isolated OVS bridge as parent.
+	NoUplink bool `json:"no_uplink" yaml:"no_uplink"`
 }
 
 // ServiceToken represents a join token for a service join request.
@@ -43,3 +52,10 @@ type ServiceTokensPost struct {
 	ClusterAddress string `json:"cluster_address" yaml:"cluster_address"`
 	JoinerName     string `json:"joiner_name"     yaml:"joiner_name"`
 }
+
+// ServiceRefreshPost represents a request to refresh the snap of a MicroCloud service on a cluster member.
+type ServiceRefreshPost struct {
+	// Channel to refresh the snap to. The tracked channel is kept if empty.
+	// Example: 24.03/stable
+	Channel string `json:"channel" yaml:"channel"`
+}
@@ -18,6 +18,7 @@ const (
 // Session represents the websocket protocol used during trust establishment between the client and server.
 // Empty fields are omitted to require sending only the necessary information.
 type Session struct {
+	ID                   string                 `json:"id,omitempty"`
 	Address              string                 `json:"address,omitempty"`
 	InitiatorAddress     string                 `json:"initiator_address,omitempty"`
 	InitiatorName        string                 `json:"initiator_name,omitempty"`
@@ -30,6 +31,9 @@ type Session struct {
 	Accepted             bool                   `json:"accepted,omitempty"`
 	LookupTimeout        time.Duration          `json:"lookup_timeout,omitempty"`
 	Error                string                 `json:"error,omitempty"`
+
+	// AltAddress is the address of the other IP family on dual-stack systems, advertised along with Address.
+	AltAddress string `json:"alt_address,omitempty"`
 }
 
 // SessionJoinPost represents a request made to join an active session.
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	cephTypes "github.com/canonical/microceph/microceph/api/types"
 	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
 	ovnTypes "github.com/canonical/microovn/microovn/api/types"
@@ -25,4 +27,85 @@ type Status struct {
 
 	// OVNServices is a list of all ovn services running on this member.
 	OVNServices ovnTypes.Services `json:"ovn_services" yaml:"ovn_services"`
+
+	// UnderlayPaths is the state of the OVN Geneve tunnels from the member, if the underlay watchdog is enabled.
+	UnderlayPaths []UnderlayPath `json:"underlay_paths" yaml:"underlay_paths"`
+
+	// Liveness is the state of the other cluster members, as seen by the heartbeats of the member's daemon.
+	Liveness []MemberLiveness `json:"liveness" yaml:"liveness"`
+
+	// Clocks are the clocks of the other cluster members compared to the member's own clock, as measured by the heartbeats of the member's daemon.
+	Clocks []MemberClock `json:"clocks" yaml:"clocks"`
+
+	// Platform is the environment the member runs in, such as a virtual machine.
+	Platform *Platform `json:"platform" yaml:"platform"`
+
+	// Boot is the state of the services the member's daemon waits for in dependency order since it started.
+	Boot []BootStep `json:"boot" yaml:"boot"`
+}
+
+// UnderlayPath is the state of an OVN Geneve tunnel to another chassis, as last probed by the underlay watchdog.
+type UnderlayPath struct {
+	// Name of the tunnel interface.
+	Name string `json:"name" yaml:"name"`
+
+	// LocalAddress is the tunnel endpoint of the member.
+	LocalAddress string `json:"local_address" yaml:"local_address"`
+
+	// RemoteAddress is the tunnel endpoint of the remote chassis.
+	RemoteAddress string `json:"remote_address" yaml:"remote_address"`
+
+	// Reachable is whether the remote tunnel endpoint could be reached.
+	Reachable bool `json:"reachable" yaml:"reachable"`
+
+	// Error is the reason the remote tunnel endpoint could not be reached.
+	Error string `json:"error" yaml:"error"`
+
+	// CheckedAt is the time of the last probe.
+	CheckedAt time.Time `json:"checked_at" yaml:"checked_at"`
+
+	// Since is the time the path last changed between reachable and unreachable.
+	Since time.Time `json:"since" yaml:"since"`
+}
+
+// MemberLiveness is the state of another cluster member, as seen by the heartbeats of the MicroCloud daemon.
+type MemberLiveness struct {
+	// Name of the cluster member.
+	Name string `json:"name" yaml:"name"`
+
+	// Address of the cluster member.
+	Address string `json:"address" yaml:"address"`
+
+	// Reachable is whether fewer consecutive heartbeats than the threshold were missed.
+	Reachable bool `json:"reachable" yaml:"reachable"`
+
+	// Misses is the number of consecutive missed heartbeats.
+	Misses int `json:"misses" yaml:"misses"`
+
+	// Error is the reason the last heartbeat was missed.
+	Error string `json:"error" yaml:"error"`
+
+	// LastSeen is the time of the last successful heartbeat, if any.
+	LastSeen time.Time `json:"last_seen" yaml:"last_seen"`
+
+	// Since is the time the member last changed between reachable and unreachable.
+	Since time.Time `json:"since" yaml:"since"`
+}
+
+// MemberClock is the clock of another cluster member compared to the own clock, as last measured by the heartbeats of the MicroCloud daemon.
+type MemberClock struct {
+	// Name of the cluster member.
+	Name string `json:"name" yaml:"name"`
+
+	// Skew is how far the clock of the cluster member is ahead of the own clock, or behind if negative.
+	Skew time.Duration `json:"skew" yaml:"skew"`
+
+	// Skewed is whether the skew exceeds the configured threshold.
+	Skewed bool `json:"skewed" yaml:"skewed"`
+
+	// Synchronized is whether the clock of the cluster member is synchronized to a time source, such as NTP.
+	Synchronized bool `json:"synchronized" yaml:"synchronized"`
+
+	// CheckedAt is the time of the last measurement.
+	CheckedAt time.Time `json:"checked_at" yaml:"checked_at"`
 }
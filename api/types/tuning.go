@@ -0,0 +1,35 @@
+package types
+
+import (
+	"time"
+)
+
+// TuningSetting represents a kernel setting changed by MicroCloud on a cluster member.
+type TuningSetting struct {
+	// Name of the cluster member the setting was changed on
+	// Example: micro01
+	Member string `json:"member" yaml:"member"`
+
+	// Name of the sysctl
+	// Example: fs.inotify.max_user_watches
+	Key string `json:"key" yaml:"key"`
+
+	// Value of the sysctl before MicroCloud changed it
+	// Example: 65536
+	Previous int64 `json:"previous" yaml:"previous"`
+
+	// Value of the sysctl set by MicroCloud
+	// Example: 1048576
+	Value int64 `json:"value" yaml:"value"`
+
+	// Time the setting was applied at
+	// Example: 2025-03-26T11:37:17.83536772Z
+	AppliedAt time.Time `json:"applied_at" yaml:"applied_at"`
+}
+
+// TuningPost represents a request to apply the recommended kernel settings on a cluster member.
+type TuningPost struct {
+	// Share of the memory in percent to reserve as hugepages for virtual machines. No hugepages are reserved if zero.
+	// Example: 25
+	Hugepages int `json:"hugepages" yaml:"hugepages"`
+}
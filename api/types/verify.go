@@ -0,0 +1,31 @@
+package types
+
+const (
+	// VerifyCheckNetwork is the check of the connection to another cluster member.
+	VerifyCheckNetwork = "network"
+
+	// VerifyCheckOVN is the check of an OVN Geneve tunnel to another chassis.
+	VerifyCheckOVN = "ovn"
+
+	// VerifyCheckStorage is the check of an LXD storage pool.
+	VerifyCheckStorage = "storage"
+)
+
+// VerifyCheck is the result of a lightweight check of a cluster member.
+type VerifyCheck struct {
+	// Member running the check
+	// Example: micro01
+	Member string `json:"member" yaml:"member"`
+
+	// Check is the kind of check (network, ovn or storage)
+	// Example: ovn
+	Check string `json:"check" yaml:"check"`
+
+	// Target checked, such as another cluster member, a tunnel endpoint or a storage pool
+	// Example: 10.0.2.2
+	Target string `json:"target" yaml:"target"`
+
+	// Error of a failed check, empty if the check passed
+	// Example: BFD session is down: Control Detection Time Expired
+	Error string `json:"error" yaml:"error"`
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"time"
+)
+
+// ServiceWarning is a warning raised by one of the services of the cluster, such as an LXD warning or a Ceph health check.
+type ServiceWarning struct {
+	// ID of the warning, prefixed with the source it was collected from
+	// Example: ceph:OSD_DOWN
+	ID string `json:"id" yaml:"id"`
+
+	// Service that raised the warning
+	// Example: MicroCeph
+	Service ServiceType `json:"service" yaml:"service"`
+
+	// Location is the cluster member the warning applies to, or empty if it applies to the whole cluster
+	// Example: micro01
+	Location string `json:"location" yaml:"location"`
+
+	// Severity of the warning
+	// Example: moderate
+	Severity string `json:"severity" yaml:"severity"`
+
+	// Type of the warning
+	// Example: OSD_DOWN
+	Type string `json:"type" yaml:"type"`
+
+	// Message describing the warning
+	// Example: 1 osds down
+	Message string `json:"message" yaml:"message"`
+
+	// LastSeen is the last time the warning was raised, if known
+	LastSeen time.Time `json:"last_seen" yaml:"last_seen"`
+
+	// Acknowledged is whether the warning was acknowledged, so it's only listed on request
+	// Example: false
+	Acknowledged bool `json:"acknowledged" yaml:"acknowledged"`
+}
+
+// ServiceWarningPut is the request to acknowledge a warning.
+type ServiceWarningPut struct {
+	// Acknowledged is whether the warning should be acknowledged, or acknowledged warnings shown again
+	// Example: true
+	Acknowledged bool `json:"acknowledged" yaml:"acknowledged"`
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// VerifyCmd represents the /1.0/verify API on MicroCloud.
+var VerifyCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Path: "verify",
+
+		Post: rest.EndpointAction{Handler: verifyPost(sh), AllowUntrusted: true, AccessHandler: allowAuthToken(sh, types.AuthTokenRoleOperator), ProxyTarget: true},
+	}
+}
+
+// verifyPost runs the lightweight checks of this cluster member, and returns their results.
+func verifyPost(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		return response.SyncResponse(true, sh.Verify(r.Context(), s.Name()))
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	microClient "github.com/canonical/microcluster/v3/client"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	"github.com/canonical/microcluster/v3/state"
+	"github.com/gorilla/mux"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// WarningsCmd represents the /1.0/warnings API on MicroCloud.
+var WarningsCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "warnings",
+		Path: "warnings",
+
+		Get: rest.EndpointAction{Handler: authHandlerMTLS(sh, warningsGet(sh))},
+	}
+}
+
+// WarningCmd represents the /1.0/warnings/{id} API on MicroCloud.
+var WarningCmd = func(sh *service.Handler) rest.Endpoint {
+	return rest.Endpoint{
+		Name: "warnings/{id}",
+		Path: "warnings/{id}",
+
+		Put: rest.EndpointAction{Handler: authHandlerMTLS(sh, warningPut(sh))},
+	}
+}
+
+// warningsGet returns the warnings raised by the services of all cluster members.
+// Cluster members only return their own warnings when notified by another member.
+func warningsGet(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		warnings, err := sh.Warnings(r.Context(), s.Name())
+		if err != nil {
+			logger.Warn("Failed to collect some warnings", logger.Ctx{"error": err})
+		}
+
+		if microClient.IsNotification(r) {
+			return response.SyncResponse(true, warnings)
+		}
+
+		var warningsMu sync.Mutex
+		cluster, err := s.Cluster(true)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = cluster.Query(r.Context(), true, func(ctx context.Context, c *microClient.Client) error {
+			memberWarnings, err := client.GetWarnings(ctx, c)
+			if err != nil {
+				logger.Error("Failed to get warnings of cluster member", logger.Ctx{"error": err, "address": c.URL()})
+
+				return nil
+			}
+
+			warningsMu.Lock()
+			warnings = append(warnings, memberWarnings...)
+			warningsMu.Unlock()
+
+			return nil
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		raised := make(map[string]bool, len(warnings))
+		for _, w := range warnings {
+			raised[w.ID] = true
+		}
+
+		var acknowledged map[string]bool
+		err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+			err := database.PruneWarningAcknowledgements(ctx, tx, raised)
+			if err != nil {
+				return err
+			}
+
+			acknowledged, err = database.GetWarningAcknowledgements(ctx, tx)
+
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.SyncResponse(true, service.MergeWarnings(warnings, acknowledged))
+	}
+}
+
+// warningPut acknowledges a warning, or shows an acknowledged warning again.
+// LXD warnings are acknowledged in LXD, the others in the MicroCloud database.
+func warningPut(sh *service.Handler) endpointHandler {
+	return func(s state.State, r *http.Request) response.Response {
+		id, err := url.PathUnescape(mux.Vars(r)["id"])
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		var req types.ServiceWarningPut
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		if strings.HasPrefix(id, service.WarningSourceLXD) {
+			lxd, ok := sh.Services[types.LXD].(*service.LXDService)
+			if !ok {
+				return response.SmartError(api.StatusErrorf(http.StatusNotFound, "LXD is not installed"))
+			}
+
+			lxdClient, err := lxd.Client(r.Context())
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			status := "new"
+			if req.Acknowledged {
+				status = "acknowledged"
+			}
+
+			err = lxdClient.UpdateWarning(strings.TrimPrefix(id, service.WarningSourceLXD), api.WarningPut{Status: status}, "")
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			return response.EmptySyncResponse
+		}
+
+		if !strings.HasPrefix(id, service.WarningSourceCeph) && !strings.HasPrefix(id, service.WarningSourceOVN) {
+			return response.SmartError(api.StatusErrorf(http.StatusNotFound, "Warning %q not found", id))
+		}
+
+		err = s.Database().Transaction(r.Context(), func(ctx context.Context, tx *sql.Tx) error {
+			if req.Acknowledged {
+				return database.AcknowledgeWarning(ctx, tx, id, time.Now().UTC())
+			}
+
+			return database.DeleteWarningAcknowledgement(ctx, tx, id)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.EmptySyncResponse
+	}
+}
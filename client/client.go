@@ -7,23 +7,56 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/microcluster/v3/client"
 	"github.com/canonical/microcluster/v3/microcluster/rest/response"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
 	"github.com/gorilla/websocket"
 
 	"github.com/canonical/microcloud/microcloud/api/types"
 )
 
+// withListFilter adds the query parameters of the list filter to the URL.
+func withListFilter(url *api.URL, filter types.ListFilter) *api.URL {
+	if len(filter.Members) > 0 {
+		url = url.WithQuery("member", strings.Join(filter.Members, ","))
+	}
+
+	if filter.Service != "" {
+		url = url.WithQuery("service", string(filter.Service))
+	}
+
+	if filter.Status != "" {
+		url = url.WithQuery("status", filter.Status)
+	}
+
+	if filter.Limit > 0 {
+		url = url.WithQuery("limit", strconv.Itoa(filter.Limit))
+	}
+
+	if filter.Offset > 0 {
+		url = url.WithQuery("offset", strconv.Itoa(filter.Offset))
+	}
+
+	return url
+}
+
 // GetStatus fetches a set of status information for the whole cluster.
 func GetStatus(ctx context.Context, c *client.Client) ([]types.Status, error) {
+	return GetFilteredStatus(ctx, c, types.ListFilter{})
+}
+
+// GetFilteredStatus fetches the status information of the cluster members matching the filter, sorted by member name.
+func GetFilteredStatus(ctx context.Context, c *client.Client, filter types.ListFilter) ([]types.Status, error) {
 	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
 	var statuses []types.Status
-	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("status").URL, nil, &statuses)
+	err := c.Query(queryCtx, "GET", types.APIVersion, &withListFilter(api.NewURL().Path("status"), filter).URL, nil, &statuses)
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +64,20 @@ func GetStatus(ctx context.Context, c *client.Client) ([]types.Status, error) {
 	return statuses, nil
 }
 
+// GetClusterMembers fetches the MicroCloud cluster members matching the filter, sorted by name.
+func GetClusterMembers(ctx context.Context, c *client.Client, filter types.ListFilter) ([]microTypes.ClusterMember, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	members := []microTypes.ClusterMember{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &withListFilter(api.NewURL().Path("members"), filter).URL, nil, &members)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get cluster members: %w", err)
+	}
+
+	return members, nil
+}
+
 // StartSession starts a new session and returns the underlying websocket connection.
 func StartSession(ctx context.Context, c *client.Client, role string, sessionTimeout time.Duration) (*websocket.Conn, error) {
 	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
@@ -45,6 +92,20 @@ func StartSession(ctx context.Context, c *client.Client, role string, sessionTim
 	return conn, nil
 }
 
+// AttachSession attaches to the initiating session with the given ID, which lost its client.
+func AttachSession(ctx context.Context, c *client.Client, id string, sessionTimeout time.Duration) (*websocket.Conn, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	url := api.NewURL().Path("session", string(types.SessionInitiating)).WithQuery("timeout", sessionTimeout.String()).WithQuery("attach", id)
+	conn, err := c.Websocket(queryCtx, types.APIVersion, &url.URL)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to attach to session websocket: %w", err)
+	}
+
+	return conn, nil
+}
+
 // StopSession is called from the initiator to stop a joiner session.
 func StopSession(ctx context.Context, c *client.Client, stopMsg string) error {
 	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
@@ -124,6 +185,33 @@ func RemoteIssueToken(ctx context.Context, c *client.Client, serviceType types.S
 	return token, nil
 }
 
+// RestartService restarts the given service on the cluster member the client targets, and waits for it to get ready again.
+func RestartService(ctx context.Context, c *client.Client, serviceType types.ServiceType) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("services", string(serviceType), "restart").URL, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to restart %s: %w", serviceType, err)
+	}
+
+	return nil
+}
+
+// RefreshService refreshes the snap of the given service on the cluster member the client targets, and returns the version of the service once it's ready again.
+func RefreshService(ctx context.Context, c *client.Client, serviceType types.ServiceType, args types.ServiceRefreshPost) (string, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	var version string
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("services", string(serviceType), "refresh").URL, args, &version)
+	if err != nil {
+		return "", fmt.Errorf("Failed to refresh %s: %w", serviceType, err)
+	}
+
+	return version, nil
+}
+
 // DeleteClusterMember removes the cluster member from any service that it is part of.
 func DeleteClusterMember(ctx context.Context, c *client.Client, memberName string, force bool) error {
 	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
@@ -136,3 +224,524 @@ func DeleteClusterMember(ctx context.Context, c *client.Client, memberName strin
 
 	return c.Query(queryCtx, "DELETE", types.APIVersion, &path.URL, nil, nil)
 }
+
+// GetConfig returns the cluster-wide MicroCloud daemon configuration.
+func GetConfig(ctx context.Context, c *client.Client) (map[string]string, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	config := map[string]string{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("config").URL, nil, &config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MicroCloud configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// UpdateConfig sets the given keys of the cluster-wide MicroCloud daemon configuration.
+// Keys with an empty value are unset.
+func UpdateConfig(ctx context.Context, c *client.Client, config map[string]string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	err := c.Query(queryCtx, "PATCH", types.APIVersion, &api.NewURL().Path("config").URL, types.ConfigPatch{Config: config}, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to update MicroCloud configuration: %w", err)
+	}
+
+	return nil
+}
+
+// GetBenchmarks returns the recorded storage performance baseline of all cluster members.
+func GetBenchmarks(ctx context.Context, c *client.Client) ([]types.Benchmark, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	benchmarks := []types.Benchmark{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("benchmarks").URL, nil, &benchmarks)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get storage benchmarks: %w", err)
+	}
+
+	return benchmarks, nil
+}
+
+// RunBenchmarks benchmarks the storage pools of the cluster member the client targets.
+func RunBenchmarks(ctx context.Context, c *client.Client, args types.BenchmarksPost) ([]types.Benchmark, error) {
+	// Each storage pool runs several fio tests, so leave plenty of time.
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	benchmarks := []types.Benchmark{}
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("benchmarks").URL, args, &benchmarks)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to run storage benchmarks: %w", err)
+	}
+
+	return benchmarks, nil
+}
+
+// GetTuning returns the kernel settings changed on the given cluster member, or on all cluster members if no member is given.
+func GetTuning(ctx context.Context, c *client.Client, member string) ([]types.TuningSetting, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	url := api.NewURL().Path("tuning")
+	if member != "" {
+		url = url.WithQuery("member", member)
+	}
+
+	settings := []types.TuningSetting{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &url.URL, nil, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get kernel tuning settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// ApplyTuning applies the recommended kernel settings on the cluster member the client targets, and returns the changed settings.
+func ApplyTuning(ctx context.Context, c *client.Client, args types.TuningPost) ([]types.TuningSetting, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	settings := []types.TuningSetting{}
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("tuning").URL, args, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to apply kernel tuning settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// RevertTuning restores the kernel settings changed on the cluster member the client targets, and returns the reverted settings.
+func RevertTuning(ctx context.Context, c *client.Client) ([]types.TuningSetting, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	settings := []types.TuningSetting{}
+	err := c.Query(queryCtx, "DELETE", types.APIVersion, &api.NewURL().Path("tuning").URL, nil, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to revert kernel tuning settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// StartNetworkTestServer starts the network test server of the cluster member the client targets, and returns its addresses on each network.
+func StartNetworkTestServer(ctx context.Context, c *client.Client, args types.NetworkTestServerPut) (*types.NetworkTestServer, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	server := types.NetworkTestServer{}
+	err := c.Query(queryCtx, "PUT", types.APIVersion, &api.NewURL().Path("network-test", "server").URL, args, &server)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to start network test server: %w", err)
+	}
+
+	return &server, nil
+}
+
+// StopNetworkTestServer stops the network test server of the cluster member the client targets.
+func StopNetworkTestServer(ctx context.Context, c *client.Client) error {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	err := c.Query(queryCtx, "DELETE", types.APIVersion, &api.NewURL().Path("network-test", "server").URL, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to stop network test server: %w", err)
+	}
+
+	return nil
+}
+
+// RunNetworkTest measures the network links from the cluster member the client targets to the given peers.
+func RunNetworkTest(ctx context.Context, c *client.Client, args types.NetworkTestPost) ([]types.NetworkTestResult, error) {
+	// Each peer is measured in turn, so leave plenty of time.
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	results := []types.NetworkTestResult{}
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("network-test").URL, args, &results)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to run network test: %w", err)
+	}
+
+	return results, nil
+}
+
+// Verify runs the lightweight checks of the cluster member the client targets, and returns their results.
+func Verify(ctx context.Context, c *client.Client) ([]types.VerifyCheck, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	checks := []types.VerifyCheck{}
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("verify").URL, nil, &checks)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to verify cluster member: %w", err)
+	}
+
+	return checks, nil
+}
+
+// GetOperations returns the cluster changes holding or waiting for the cluster-wide change lock.
+func GetOperations(ctx context.Context, c *client.Client) ([]types.Operation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	operations := []types.Operation{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("operations").URL, nil, &operations)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get operations: %w", err)
+	}
+
+	return operations, nil
+}
+
+// CreateOperation queues a cluster change for the cluster-wide change lock.
+func CreateOperation(ctx context.Context, c *client.Client, args types.OperationsPost) (*types.Operation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	operation := types.Operation{}
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("operations").URL, args, &operation)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create operation: %w", err)
+	}
+
+	return &operation, nil
+}
+
+// UpdateOperation keeps the queued cluster change alive, and returns whether it holds the change lock.
+func UpdateOperation(ctx context.Context, c *client.Client, id int64) (*types.Operation, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	operation := types.Operation{}
+	err := c.Query(queryCtx, "PUT", types.APIVersion, &api.NewURL().Path("operations", strconv.FormatInt(id, 10)).URL, nil, &operation)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update operation: %w", err)
+	}
+
+	return &operation, nil
+}
+
+// DeleteOperation removes the cluster change from the queue, releasing the change lock if it held it.
+func DeleteOperation(ctx context.Context, c *client.Client, id int64) error {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	err := c.Query(queryCtx, "DELETE", types.APIVersion, &api.NewURL().Path("operations", strconv.FormatInt(id, 10)).URL, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to delete operation: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvents returns the event history of the cluster, filtered by the given type and time range if set.
+// Only the cluster members and page selected by the list filter are returned.
+func GetEvents(ctx context.Context, c *client.Client, eventType string, since time.Time, until time.Time, filter types.ListFilter) ([]types.Event, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	url := withListFilter(api.NewURL().Path("events"), filter)
+	if eventType != "" {
+		url = url.WithQuery("type", eventType)
+	}
+
+	if !since.IsZero() {
+		url = url.WithQuery("since", since.UTC().Format(time.RFC3339))
+	}
+
+	if !until.IsZero() {
+		url = url.WithQuery("until", until.UTC().Format(time.RFC3339))
+	}
+
+	events := []types.Event{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &url.URL, nil, &events)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetReports returns the stored health reports of the cluster, oldest first.
+func GetReports(ctx context.Context, c *client.Client) ([]types.HealthReport, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	reports := []types.HealthReport{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("reports").URL, nil, &reports)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetReport returns the stored health report with the given ID.
+func GetReport(ctx context.Context, c *client.Client, id int64) (*types.HealthReport, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	report := types.HealthReport{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("reports", strconv.FormatInt(id, 10)).URL, nil, &report)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// GenerateReport generates and stores a health report of the cluster, and returns it.
+func GenerateReport(ctx context.Context, c *client.Client) (*types.HealthReport, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	report := types.HealthReport{}
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("reports").URL, nil, &report)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// GetAuthTokens returns the API tokens of the cluster, without their secrets.
+func GetAuthTokens(ctx context.Context, c *client.Client) ([]types.AuthToken, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	tokens := []types.AuthToken{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("auth", "tokens").URL, nil, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get API tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// CreateAuthToken creates an API token, and returns it with its secret.
+func CreateAuthToken(ctx context.Context, c *client.Client, args types.AuthTokensPost) (*types.AuthTokenSecret, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	token := types.AuthTokenSecret{}
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("auth", "tokens").URL, args, &token)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create API token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// DeleteAuthToken revokes the API token with the given name.
+func DeleteAuthToken(ctx context.Context, c *client.Client, name string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	err := c.Query(queryCtx, "DELETE", types.APIVersion, &api.NewURL().Path("auth", "tokens", name).URL, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to revoke API token: %w", err)
+	}
+
+	return nil
+}
+
+// Heartbeat checks that the MicroCloud daemon of the given cluster member responds.
+func Heartbeat(ctx context.Context, c *client.Client) error {
+	err := c.Query(ctx, "GET", microTypes.PublicEndpoint, &api.NewURL().URL, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to reach cluster member: %w", err)
+	}
+
+	return nil
+}
+
+// GetJoinStates returns the state of the given cluster member joining each service, or of all cluster members if no member is given.
+func GetJoinStates(ctx context.Context, c *client.Client, member string) ([]types.JoinState, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	url := api.NewURL().Path("join-states")
+	if member != "" {
+		url = url.WithQuery("member", member)
+	}
+
+	joinStates := []types.JoinState{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &url.URL, nil, &joinStates)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get join states: %w", err)
+	}
+
+	return joinStates, nil
+}
+
+// GetJoinProgress returns the progress of the system the client targets joining each service, since its last join started.
+func GetJoinProgress(ctx context.Context, c *client.Client) ([]types.JoinState, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	joinProgress := []types.JoinState{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("join-progress").URL, nil, &joinProgress)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get join progress: %w", err)
+	}
+
+	return joinProgress, nil
+}
+
+// GetClock returns the clock of the system the client targets.
+func GetClock(ctx context.Context, c *client.Client) (*types.Clock, error) {
+	clock := types.Clock{}
+	err := c.Query(ctx, "GET", types.APIVersion, &api.NewURL().Path("clock").URL, nil, &clock)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get clock: %w", err)
+	}
+
+	return &clock, nil
+}
+
+// CheckReachability returns whether the system the client targets reaches each of the given addresses.
+func CheckReachability(ctx context.Context, c *client.Client, args types.ReachabilityPost) ([]types.Reachability, error) {
+	reachability := []types.Reachability{}
+	err := c.Query(ctx, "POST", types.APIVersion, &api.NewURL().Path("reachability").URL, args, &reachability)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to check reachability: %w", err)
+	}
+
+	return reachability, nil
+}
+
+// GetPlatform returns the platform the system the client targets runs in.
+func GetPlatform(ctx context.Context, c *client.Client) (*types.Platform, error) {
+	platform := types.Platform{}
+	err := c.Query(ctx, "GET", types.APIVersion, &api.NewURL().Path("platform").URL, nil, &platform)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get platform: %w", err)
+	}
+
+	return &platform, nil
+}
+
+// GetSystemDisks returns the disks holding the operating system of the system the client targets.
+func GetSystemDisks(ctx context.Context, c *client.Client) ([]types.SystemDisk, error) {
+	disks := []types.SystemDisk{}
+	err := c.Query(ctx, "GET", types.APIVersion, &api.NewURL().Path("disks", "system").URL, nil, &disks)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get system disks: %w", err)
+	}
+
+	return disks, nil
+}
+
+// GetServiceVersions returns the version and API extensions of each service installed on the system the client targets.
+func GetServiceVersions(ctx context.Context, c *client.Client) (map[types.ServiceType]types.ServiceVersion, error) {
+	versions := map[types.ServiceType]types.ServiceVersion{}
+	err := c.Query(ctx, "GET", types.APIVersion, &api.NewURL().Path("service-versions").URL, nil, &versions)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get service versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetInspection returns the detailed state of the cluster member the client targets.
+func GetInspection(ctx context.Context, c *client.Client) (*types.MemberInspection, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	inspection := types.MemberInspection{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("inspect").URL, nil, &inspection)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to inspect the cluster member: %w", err)
+	}
+
+	return &inspection, nil
+}
+
+// GetWarnings returns the warnings raised by the services of the cluster.
+func GetWarnings(ctx context.Context, c *client.Client) ([]types.ServiceWarning, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	warnings := []types.ServiceWarning{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("warnings").URL, nil, &warnings)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get warnings: %w", err)
+	}
+
+	return warnings, nil
+}
+
+// AcknowledgeWarning acknowledges the warning with the given ID, or shows it again if acknowledged is false.
+func AcknowledgeWarning(ctx context.Context, c *client.Client, id string, acknowledged bool) error {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	err := c.Query(queryCtx, "PUT", types.APIVersion, &api.NewURL().Path("warnings", id).URL, types.ServiceWarningPut{Acknowledged: acknowledged}, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to update warning %q: %w", id, err)
+	}
+
+	return nil
+}
+
+// GetOVNUnderlay returns the address the cluster member the client targets uses for the OVN Geneve tunnels.
+func GetOVNUnderlay(ctx context.Context, c *client.Client) (*types.OVNUnderlay, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	underlay := types.OVNUnderlay{}
+	err := c.Query(queryCtx, "GET", types.APIVersion, &api.NewURL().Path("ovn-underlay").URL, nil, &underlay)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the OVN underlay address: %w", err)
+	}
+
+	return &underlay, nil
+}
+
+// SetOVNUnderlay changes the address the cluster member the client targets uses for the OVN Geneve tunnels.
+func SetOVNUnderlay(ctx context.Context, c *client.Client, args types.OVNUnderlayPut) (*types.OVNUnderlay, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	underlay := types.OVNUnderlay{}
+	err := c.Query(queryCtx, "PUT", types.APIVersion, &api.NewURL().Path("ovn-underlay").URL, args, &underlay)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to set the OVN underlay address: %w", err)
+	}
+
+	return &underlay, nil
+}
+
+// WipeDisks wipes the given disks of the cluster member the client targets.
+func WipeDisks(ctx context.Context, c *client.Client, args types.DisksWipe) error {
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	err := c.Query(queryCtx, "POST", types.APIVersion, &api.NewURL().Path("disks", "wipe").URL, args, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to wipe disks: %w", err)
+	}
+
+	return nil
+}
+
+// ResetClusterMember clears the state of the given cluster member of the microcluster service the client connects to, and restarts its daemon uninitialized.
+// Unlike removing it, this also works for the last member of the cluster.
+func ResetClusterMember(ctx context.Context, c *client.Client, name string) error {
+	queryCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	url := api.NewURL().Path("cluster", name).WithQuery("force", "1")
+	err := c.Query(queryCtx, "PUT", microTypes.InternalEndpoint, &url.URL, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to reset cluster member %q: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+// Package client provides the Go bindings of the MicroCloud API.
+//
+// Connect to the MicroCloud daemon with ConnectLocal or ConnectRemote, and pass the returned client to the functions of this package,
+// such as GetStatus, GetOperations or GetEvents. The request and response structs are those of the api/types package.
+// Use UseTarget on the client to send a request to another cluster member.
+package client
+
+import (
+	"fmt"
+
+	"github.com/canonical/microcluster/v3/client"
+	"github.com/canonical/microcluster/v3/microcluster"
+)
+
+// DefaultStateDir is the state directory of the MicroCloud snap.
+const DefaultStateDir = "/var/snap/microcloud/common/state"
+
+// defaultStateDir is the state directory ConnectLocal uses if none is given.
+var defaultStateDir = DefaultStateDir
+
+// ConnectLocal returns a client of the MicroCloud daemon running on this system, through the control socket in its state directory.
+// The state directory of the MicroCloud snap is used if empty.
+func ConnectLocal(stateDir string) (*client.Client, error) {
+	if stateDir == "" {
+		stateDir = defaultStateDir
+	}
+
+	app, err := microcluster.App(microcluster.Args{StateDir: stateDir})
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := app.LocalClient()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to the MicroCloud control socket: %w", err)
+	}
+
+	return c, nil
+}
+
+// ConnectRemote returns a client of the MicroCloud daemon listening on the given address and port.
+// The client authenticates with the server.crt and server.key certificate of the given directory, which must be trusted by MicroCloud,
+// and expects the daemon to present the cluster.crt certificate of the directory, if it exists.
+func ConnectRemote(certDir string, address string) (*client.Client, error) {
+	app, err := microcluster.App(microcluster.Args{StateDir: certDir})
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := app.RemoteClient(address)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create client of MicroCloud on %q: %w", address, err)
+	}
+
+	return c, nil
+}
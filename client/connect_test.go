@@ -0,0 +1,32 @@
+package client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type connectSuite struct {
+	suite.Suite
+}
+
+func TestConnectSuite(t *testing.T) {
+	suite.Run(t, new(connectSuite))
+}
+
+func (s *connectSuite) Test_ConnectLocal() {
+	// Setting up the client creates the layout of the state directory it uses.
+	stateDir := s.T().TempDir()
+	_, err := ConnectLocal(stateDir)
+	s.Require().NoError(err)
+	s.DirExists(filepath.Join(stateDir, "database"))
+
+	s.T().Log("Empty state directory")
+	defer func(dir string) { defaultStateDir = dir }(defaultStateDir)
+	defaultStateDir = s.T().TempDir()
+
+	_, err = ConnectLocal("")
+	s.Require().NoError(err)
+	s.DirExists(filepath.Join(defaultStateDir, "database"))
+}
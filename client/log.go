@@ -0,0 +1,106 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// debugLogging is whether the API requests and responses are logged.
+var debugLogging atomic.Bool
+
+// SetDebugLogging sets whether the API requests and responses are logged.
+// Bodies are only read and redacted while it is enabled, as the debug messages would be discarded otherwise.
+func SetDebugLogging(enabled bool) {
+	debugLogging.Store(enabled)
+}
+
+// secretRegex matches the values of JSON fields that may contain credentials, such as join tokens and passphrases.
+var secretRegex = regexp.MustCompile(`(?i)("[a-z_-]*(?:token|secret|password|passphrase|hmac|private_key)[a-z_-]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// RedactSecrets replaces the values of any credentials found in the given JSON text.
+func RedactSecrets(text string) string {
+	return secretRegex.ReplaceAllString(text, `$1"<redacted>"`)
+}
+
+// LogRequest logs the method, URL and JSON body of an outgoing API request at debug level, if debug logging is enabled.
+// Credentials in the body are redacted.
+func LogRequest(r *http.Request) {
+	if !debugLogging.Load() {
+		return
+	}
+
+	ctx := logger.Ctx{"method": r.Method, "url": r.URL.String()}
+	if r.GetBody != nil && strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body, err := r.GetBody()
+		if err == nil {
+			content, err := io.ReadAll(body)
+			if err == nil {
+				ctx["body"] = RedactSecrets(strings.TrimSpace(string(content)))
+			}
+
+			_ = body.Close()
+		}
+	}
+
+	logger.Debug("Sending API request", ctx)
+}
+
+// LogTransport wraps an HTTP transport and logs the status and JSON body of each API response at debug level.
+// Credentials in the body are redacted.
+type LogTransport struct {
+	transport *http.Transport
+}
+
+// NewLogTransport returns a LogTransport wrapping the given transport.
+// It can be used as the TransportWrapper of LXD clients.
+func NewLogTransport(t *http.Transport) *LogTransport {
+	return &LogTransport{transport: t}
+}
+
+// Transport returns the wrapped transport.
+func (t *LogTransport) Transport() *http.Transport {
+	return t.transport
+}
+
+// RoundTrip sends the request through the wrapped transport and logs the response.
+func (t *LogTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+
+	LogResponse(resp)
+
+	return resp, nil
+}
+
+// LogResponse logs the status and JSON body of an API response at debug level, if debug logging is enabled.
+// The body is restored so it can still be read by the caller. Credentials in the body are redacted.
+func LogResponse(resp *http.Response) {
+	if !debugLogging.Load() {
+		return
+	}
+
+	ctx := logger.Ctx{"status": resp.Status}
+	if resp.Request != nil {
+		ctx["method"] = resp.Request.Method
+		ctx["url"] = resp.Request.URL.String()
+	}
+
+	if resp.Body != nil && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		content, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(content))
+		if err == nil {
+			ctx["body"] = RedactSecrets(strings.TrimSpace(string(content)))
+		}
+	}
+
+	logger.Debug("Received API response", ctx)
+}
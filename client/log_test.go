@@ -0,0 +1,91 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/stretchr/testify/suite"
+)
+
+type logSuite struct {
+	suite.Suite
+}
+
+func TestLogSuite(t *testing.T) {
+	suite.Run(t, new(logSuite))
+}
+
+func (s *logSuite) Test_LogTransport() {
+	logFile := filepath.Join(s.T().TempDir(), "microcloud.log")
+	defer func(log logger.Logger) { logger.Log = log }(logger.Log)
+	s.Require().NoError(logger.InitLogger(logFile, "", false, true, nil))
+	SetDebugLogging(true)
+	defer SetDebugLogging(false)
+
+	body := `{"name":"micro01","join_token":"c2VjcmV0"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewLogTransport(&http.Transport{})}
+	resp, err := client.Get(server.URL + "/1.0/tokens")
+	s.Require().NoError(err)
+	defer resp.Body.Close()
+
+	// The caller still receives the full response body.
+	content, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.Equal(body, string(content))
+
+	log, err := os.ReadFile(logFile)
+	s.Require().NoError(err)
+	s.Contains(string(log), "Received API response")
+	s.Contains(string(log), "200 OK")
+	s.Contains(string(log), "micro01")
+	s.Contains(string(log), "<redacted>")
+	s.NotContains(string(log), "c2VjcmV0")
+}
+
+// trackedBody is a response body recording whether it was read.
+type trackedBody struct {
+	io.Reader
+
+	read bool
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	b.read = true
+
+	return b.Reader.Read(p)
+}
+
+func (b *trackedBody) Close() error {
+	return nil
+}
+
+func (s *logSuite) Test_LogResponseDebugDisabled() {
+	logFile := filepath.Join(s.T().TempDir(), "microcloud.log")
+	defer func(log logger.Logger) { logger.Log = log }(logger.Log)
+	s.Require().NoError(logger.InitLogger(logFile, "", false, true, nil))
+	SetDebugLogging(false)
+
+	body := &trackedBody{Reader: strings.NewReader(`{"name":"micro01"}`)}
+	resp := &http.Response{Status: "200 OK", Header: http.Header{"Content-Type": []string{"application/json"}}, Body: body}
+	LogResponse(resp)
+
+	// The body is neither read nor replaced, and nothing is logged.
+	s.False(body.read)
+	s.Same(body, resp.Body)
+
+	log, err := os.ReadFile(logFile)
+	s.Require().NoError(err)
+	s.NotContains(string(log), "Received API response")
+}
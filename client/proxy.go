@@ -68,6 +68,8 @@ func AuthProxy(hmac string, serviceType types.ServiceType) func(r *http.Request)
 			}
 		}
 
+		LogRequest(r)
+
 		return shared.ProxyFromEnvironment(r)
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/canonical/lxd/lxd/util"
@@ -16,6 +18,7 @@ import (
 	"github.com/canonical/microcloud/microcloud/api"
 	"github.com/canonical/microcloud/microcloud/api/types"
 	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
 	"github.com/canonical/microcloud/microcloud/multicast"
 	"github.com/canonical/microcloud/microcloud/service"
 )
@@ -24,6 +27,11 @@ type cmdAdd struct {
 	common *CmdControl
 
 	flagSessionTimeout int64
+	flagPreseed        bool
+	flagForce          bool
+	flagAnswers        []string
+
+	flagAllowSystemDisks bool
 }
 
 // command returns the subcommand to add new systems to MicroCloud.
@@ -31,10 +39,23 @@ func (c *cmdAdd) command() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add new systems to an existing MicroCloud cluster",
-		RunE:  c.run,
+		Long: `Add new systems to an existing MicroCloud cluster
+
+With --preseed, the new systems are added unattended from a preseed yaml read from stdin.
+The preseed lists only the new systems, along with their disks and interfaces,
+and uses this system as the initiator. Each new system joins with "microcloud preseed" and the same preseed.
+
+Example:
+  cat preseed.yaml | microcloud add --preseed`,
+		RunE: c.run,
 	}
 
 	cmd.Flags().Int64Var(&c.flagSessionTimeout, "session-timeout", 0, "Amount of seconds to wait for the trust establishment session. Defaults: 60m")
+	cmd.Flags().BoolVar(&c.flagPreseed, "preseed", false, "Add the systems listed in a preseed yaml read from stdin")
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, "Add the systems despite unsupported service versions or API extensions")
+	cmd.Flags().StringArrayVar(&c.flagAnswers, "answer", nil, "Answer the question with this key instead of asking it (key=value)"+"``")
+	cmd.Flags().BoolVar(&c.flagAllowSystemDisks, allowSystemDisksFlag, false, allowSystemDisksUsage)
+	cmd.MarkFlagsMutuallyExclusive("preseed", "answer")
 
 	return cmd
 }
@@ -45,8 +66,92 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	}
 
+	if c.flagPreseed {
+		return c.runPreseed()
+	}
+
+	answers, err := parseAnswers(c.flagAnswers)
+	if err != nil {
+		return err
+	}
+
+	return addSystems(c.common, c.flagSessionTimeout, nil, c.flagForce, answers, c.flagAllowSystemDisks)
+}
+
+// runPreseed adds the new systems listed in the preseed yaml from stdin to MicroCloud.
+func (c *cmdAdd) runPreseed() error {
+	if c.flagSessionTimeout > 0 {
+		return withExitCode(ExitCodeUsage, errors.New("Cannot use --session-timeout with --preseed, set session_timeout in the preseed instead"))
+	}
+
+	config, err := readPreseed()
+	if err != nil {
+		return err
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	err = validateAddPreseed(*config, hostname, status.Address.Addr().String())
+	if err != nil {
+		return err
+	}
+
+	cfg := initConfig{
+		common:  c.common,
+		systems: map[string]InitSystem{},
+		state:   map[string]service.SystemInformation{},
+		force:   c.flagForce,
+	}
+
+	cfg.allowSystemDisks(c.flagAllowSystemDisks)
+
+	return cfg.runPreseed(*config)
+}
+
+// validateAddPreseed checks that the preseed only lists the new systems, and that the system with the given name and address is its initiator.
+// Systems are only added from the existing cluster member initiating the preseed, the new systems join on their own.
+func validateAddPreseed(config Preseed, name string, address string) error {
+	if config.isBootstrap() {
+		return withExitCode(ExitCodeValidation, errors.New("The preseed must only list the new systems, and use an existing cluster member as the initiator"))
+	}
+
+	if !config.isInitiator(name, address) {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("System %q isn't the initiator of the preseed, run \"microcloud add --preseed\" on the initiator instead", name))
+	}
+
+	return nil
+}
+
+// addSystems runs the trust establishment session and sets up the services on the newly selected systems.
+// If expected systems are given, join intents of any other system are ignored.
+// If forced, unsupported service versions or API extensions only raise warnings.
+// The given answers replace the questions they answer.
+// If allowed, the disks holding the operating system of the new systems are offered for storage.
+func addSystems(common *CmdControl, sessionTimeout int64, expectedSystems []string, force bool, answers Answers, allowSystemDisks bool) error {
 	fmt.Println("Waiting for services to start ...")
-	err := checkInitialized(c.common.FlagMicroCloudDir, true, false)
+	err := checkInitialized(common.FlagMicroCloudDir, true, false)
 	if err != nil {
 		return err
 	}
@@ -54,18 +159,22 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 	cfg := initConfig{
 		bootstrap: false,
 		setupMany: true,
-		common:    c.common,
-		asker:     c.common.asker,
+		common:    common,
+		asker:     common.asker,
 		systems:   map[string]InitSystem{},
 		state:     map[string]service.SystemInformation{},
+		force:     force,
+		answers:   answers,
 	}
 
+	cfg.allowSystemDisks(allowSystemDisks)
+
 	cfg.sessionTimeout = DefaultSessionTimeout
-	if c.flagSessionTimeout > 0 {
-		cfg.sessionTimeout = time.Duration(c.flagSessionTimeout) * time.Second
+	if sessionTimeout > 0 {
+		cfg.sessionTimeout = time.Duration(sessionTimeout) * time.Second
 	}
 
-	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: common.FlagMicroCloudDir})
 	if err != nil {
 		return err
 	}
@@ -75,6 +184,19 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
 	}
 
+	client, err := cloudApp.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	// Serialize with other cluster changes, such as concurrent runs of "microcloud add".
+	release, err := lockClusterChanges(context.Background(), client, "add", "Add new systems")
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	cfg.name = status.Name
 	cfg.address = status.Address.Addr().String()
 	err = cfg.askAddress("")
@@ -93,23 +215,38 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	s, err := service.NewHandler(cfg.name, cfg.address, c.common.FlagMicroCloudDir, installedServices...)
+	s, err := service.NewHandler(cfg.name, cfg.address, common.FlagMicroCloudDir, installedServices...)
 	if err != nil {
 		return err
 	}
 
-	services := make(map[types.ServiceType]string, len(installedServices))
-	for _, s := range s.Services {
-		version, err := s.GetVersion(context.Background())
+	// Set up the new systems like the ones set up with MicroCloud.
+	err = cfg.loadSetupConfig(context.Background(), s)
+	if err != nil {
+		return err
+	}
+
+	// Accept the expected members which haven't joined yet as they reach out, unless adding specific systems.
+	if len(expectedSystems) == 0 {
+		expectedSystems, err = cfg.pendingExpectedMembers(context.Background(), s)
 		if err != nil {
 			return err
 		}
 
-		services[s.Type()] = version
+		if len(expectedSystems) > 0 {
+			cfg.acceptExpected = true
+			cfg.lookupTimeout = cfg.sessionTimeout
+			fmt.Println(tui.SummarizeResult("Waiting for the expected systems %s", strings.Join(expectedSystems, ", ")))
+		}
+	}
+
+	services, err := s.GetVersions(context.Background())
+	if err != nil {
+		return err
 	}
 
 	err = cfg.runSession(context.Background(), s, types.SessionInitiating, cfg.sessionTimeout, func(gw *cloudClient.WebsocketGateway) error {
-		return cfg.initiatingSession(gw, s, services, "", nil)
+		return cfg.initiatingSession(gw, s, services, "", expectedSystems)
 	})
 	if err != nil {
 		return err
@@ -149,7 +286,17 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 		}
 	})
 
-	state, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: cfg.name, Address: cfg.address, Services: services})
+	err = cfg.checkClockSkew(context.Background(), s)
+	if err != nil {
+		return err
+	}
+
+	err = cfg.checkReachability(context.Background(), s)
+	if err != nil {
+		return err
+	}
+
+	state, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: cfg.name, Address: cfg.address, Services: services}, cfg.collectOptions)
 	if err != nil {
 		return err
 	}
@@ -157,14 +304,24 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 	cfg.state[cfg.name] = *state
 
 	fmt.Println("Gathering system information...")
-	for peer, system := range cfg.systems {
+	peers := make([]multicast.ServerInfo, 0, len(cfg.systems))
+	for _, system := range cfg.systems {
 		if system.ServerInfo.Name == "" || system.ServerInfo.Name == cfg.name {
 			continue
 		}
 
-		state, err := s.CollectSystemInformation(context.Background(), system.ServerInfo)
-		if err != nil {
-			return err
+		peers = append(peers, system.ServerInfo)
+	}
+
+	peerStates, err := s.CollectSystemInformationConcurrent(context.Background(), peers, cfg.collectOptions)
+	if err != nil {
+		return err
+	}
+
+	for peer, system := range cfg.systems {
+		state, ok := peerStates[system.ServerInfo.Name]
+		if !ok || system.ServerInfo.Name == cfg.name {
+			continue
 		}
 
 		cfg.state[system.ServerInfo.Name] = *state
@@ -205,6 +362,7 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 	}
 
 	// Also populate system information for existing cluster members. This is so we can potentially set up storage and networks if they haven't been set up before.
+	existingPeers := []multicast.ServerInfo{}
 	for name, address := range state.ExistingServices[types.MicroCloud] {
 		_, ok := cfg.systems[name]
 		if ok {
@@ -223,11 +381,15 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		state, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: name, Address: address})
-		if err != nil {
-			return err
-		}
+		existingPeers = append(existingPeers, multicast.ServerInfo{Name: name, Address: address})
+	}
+
+	existingStates, err := s.CollectSystemInformationConcurrent(context.Background(), existingPeers, service.CollectOptions{})
+	if err != nil {
+		return err
+	}
 
+	for name, state := range existingStates {
 		// Populate MicroCloud Internal network also for existing systems.
 		system := cfg.systems[name]
 		err = populateMicroCloudNetworkFromState(state, name, &system, cfg.lookupSubnet)
@@ -238,10 +400,23 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 		cfg.state[name] = *state
 	}
 
+	cfg.warnPlatforms()
+	cfg.warnSystemDisks()
+
+	err = cfg.checkCompatibility(s)
+	if err != nil {
+		return err
+	}
+
 	microCloudNetworkFromStateSystem := cfg.systems[cfg.name]
 	microCloudNetworkFromStateSystem.MicroCloudInternalNetwork = &NetworkInterfaceInfo{Interface: *cfg.lookupIface, Subnet: cfg.lookupSubnet, IP: net.IP(cfg.address)}
 	cfg.systems[cfg.name] = microCloudNetworkFromStateSystem
 
+	err = cfg.askConflicts(s)
+	if err != nil {
+		return err
+	}
+
 	err = cfg.askDisks(s)
 	if err != nil {
 		return err
@@ -252,6 +427,11 @@ func (c *cmdAdd) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	err = cfg.askTuning()
+	if err != nil {
+		return err
+	}
+
 	err = cfg.setupCluster(s)
 	if err != nil {
 		return err
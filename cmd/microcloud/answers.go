@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/multicast"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// answerKeys are the questions of the interactive setup which can be answered with --answer, along with the validator of their answer.
+// The answer is validated again by the question when it would be asked.
+var answerKeys = map[string]func(string) error{
+	"address":                validate.IsListOf(isAddressOrSubnet),
+	"setup-many":             isBoolAnswer,
+	"local-storage":          isBoolAnswer,
+	"distributed-storage":    isBoolAnswer,
+	"encrypt-disks":          isBoolAnswer,
+	"cephfs":                 isBoolAnswer,
+	"ceph-dashboard":         isBoolAnswer,
+	"ceph-internal-network":  validate.Optional(validate.IsNetwork),
+	"ceph-public-network":    validate.Optional(validate.IsNetwork),
+	"distributed-networking": isBoolAnswer,
+	"ovn-ipv4-gateway":       validate.Optional(validate.IsNetworkAddressCIDRV4),
+	"ovn-ipv4-range-start":   validate.IsNetworkAddressV4,
+	"ovn-ipv4-range-end":     validate.IsNetworkAddressV4,
+	"ovn-ipv6-gateway":       validate.Optional(validate.IsNetworkAddressCIDRV6),
+	"ovn-ipv6-prefix":        validate.Optional(validate.IsNetworkV6),
+	"ovn-dns-servers":        validate.Optional(validate.IsListOf(validate.IsNetworkAddress)),
+	"ovn-dns-search":         validate.Optional(validate.IsListOf(service.ValidateDNSDomain)),
+}
+
+// Answers are the answers given with --answer, keyed by question.
+type Answers map[string]string
+
+// parseAnswers parses the key=value answers given with --answer.
+func parseAnswers(flags []string) (Answers, error) {
+	answers := make(Answers, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Invalid answer %q, expected key=value", flag))
+		}
+
+		validator, ok := answerKeys[key]
+		if !ok {
+			keys := make([]string, 0, len(answerKeys))
+			for key := range answerKeys {
+				keys = append(keys, key)
+			}
+
+			slices.Sort(keys)
+
+			return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Unknown answer key %q, must be one of %s", key, strings.Join(keys, ", ")))
+		}
+
+		_, ok = answers[key]
+		if ok {
+			return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Answer %q given more than once", key))
+		}
+
+		err := validator(value)
+		if err != nil {
+			return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Invalid answer %q: %w", key, err))
+		}
+
+		answers[key] = value
+	}
+
+	return answers, nil
+}
+
+// isBoolAnswer checks that the answer is one of the answers accepted by yes/no questions.
+func isBoolAnswer(value string) error {
+	return validate.IsOneOf("yes", "y", "no", "n")(strings.ToLower(value))
+}
+
+// isAddressOrSubnet checks that the value is an IP address or a subnet in CIDR notation.
+func isAddressOrSubnet(value string) error {
+	if validate.IsNetworkAddress(value) == nil || validate.IsNetwork(value) == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%q is neither an IP address nor a subnet", value)
+}
+
+// askBool returns the answer given for the key with --answer, or asks the question if there is none.
+func (c *initConfig) askBool(key string, question string, defaultAnswer bool) (bool, error) {
+	answer, ok := c.answers[key]
+	if !ok {
+		return c.asker.AskBool(question, defaultAnswer)
+	}
+
+	fmt.Println(tui.SummarizeResult("%s %s", question, answer))
+
+	return slices.Contains([]string{"yes", "y"}, strings.ToLower(answer)), nil
+}
+
+// askString returns the answer given for the key with --answer, or asks the question if there is none.
+// As when asked, an empty answer stands for the default answer.
+func (c *initConfig) askString(key string, question string, defaultAnswer string, validator func(string) error) (string, error) {
+	answer, ok := c.answers[key]
+	if !ok {
+		return c.asker.AskString(question, defaultAnswer, validator)
+	}
+
+	if answer == "" {
+		answer = defaultAnswer
+	}
+
+	err := validator(answer)
+	if err != nil {
+		return "", withExitCode(ExitCodeUsage, fmt.Errorf("Invalid answer %q: %w", key, err))
+	}
+
+	fmt.Println(tui.SummarizeResult("%s %s", question, answer))
+
+	return answer, nil
+}
+
+// answerAddresses returns the addresses of the networks matching the addresses and subnets of the address answer.
+// A subnet matches the addresses of the networks in it.
+func answerAddresses(answer string, info []multicast.NetworkInfo) ([]string, error) {
+	addresses := []string{}
+	for _, value := range strings.Split(answer, ",") {
+		value = strings.TrimSpace(value)
+		_, subnet, err := net.ParseCIDR(value)
+		if err != nil {
+			subnet = nil
+		}
+
+		matched := false
+		for _, network := range info {
+			if (subnet == nil && network.Address == value) || (subnet != nil && subnet.Contains(net.ParseIP(network.Address))) {
+				matched = true
+				if !slices.Contains(addresses, network.Address) {
+					addresses = append(addresses, network.Address)
+				}
+			}
+		}
+
+		if !matched {
+			return nil, fmt.Errorf("No address of this system matches %q", value)
+		}
+	}
+
+	return addresses, nil
+}
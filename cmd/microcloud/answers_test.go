@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/multicast"
+)
+
+type answersSuite struct {
+	suite.Suite
+}
+
+func TestAnswersSuite(t *testing.T) {
+	suite.Run(t, new(answersSuite))
+}
+
+func (s *answersSuite) Test_parseAnswers() {
+	cases := []struct {
+		desc    string
+		flags   []string
+		answers Answers
+		err     bool
+	}{
+		{
+			desc:    "No answers",
+			answers: Answers{},
+		},
+		{
+			desc:    "Valid answers",
+			flags:   []string{"address=10.0.1.0/24", "local-storage=no", "ovn-dns-servers=", "ceph-public-network=10.0.2.0/24"},
+			answers: Answers{"address": "10.0.1.0/24", "local-storage": "no", "ovn-dns-servers": "", "ceph-public-network": "10.0.2.0/24"},
+		},
+		{
+			desc:    "Several addresses",
+			flags:   []string{"address=10.0.1.11,fd42::11"},
+			answers: Answers{"address": "10.0.1.11,fd42::11"},
+		},
+		{
+			desc:  "Missing value",
+			flags: []string{"local-storage"},
+			err:   true,
+		},
+		{
+			desc:  "Unknown key",
+			flags: []string{"local-disk=/dev/sda"},
+			err:   true,
+		},
+		{
+			desc:  "Duplicate key",
+			flags: []string{"cephfs=yes", "cephfs=no"},
+			err:   true,
+		},
+		{
+			desc:  "Invalid boolean",
+			flags: []string{"cephfs=maybe"},
+			err:   true,
+		},
+		{
+			desc:  "Invalid address",
+			flags: []string{"address=micro01"},
+			err:   true,
+		},
+		{
+			desc:  "Gateway without prefix length",
+			flags: []string{"ovn-ipv4-gateway=10.0.3.1"},
+			err:   true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		answers, err := parseAnswers(c.flags)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.answers, answers)
+	}
+}
+
+func (s *answersSuite) Test_askAnswered() {
+	cfg := initConfig{answers: Answers{"cephfs": "Y", "ceph-dashboard": "no", "ceph-internal-network": "", "ceph-public-network": "10.0.2.0/24"}}
+
+	setupCephFS, err := cfg.askBool("cephfs", "Would you like to set up CephFS remote storage?", false)
+	s.NoError(err)
+	s.True(setupCephFS)
+
+	dashboard, err := cfg.askBool("ceph-dashboard", "Would you like to enable the Ceph dashboard and Prometheus metrics?", true)
+	s.NoError(err)
+	s.False(dashboard)
+
+	_, err = cfg.askString("ceph-public-network", "What subnet would you like your Ceph public traffic on?", "", func(string) error { return errors.New("Not a valid subnet") })
+	s.Error(err)
+
+	internal, err := cfg.askString("ceph-internal-network", "What subnet would you like your Ceph internal traffic on?", "10.0.1.0/24", func(string) error { return nil })
+	s.NoError(err)
+	s.Equal("10.0.1.0/24", internal)
+
+	public, err := cfg.askString("ceph-public-network", "What subnet would you like your Ceph public traffic on?", internal, func(string) error { return nil })
+	s.NoError(err)
+	s.Equal("10.0.2.0/24", public)
+}
+
+func (s *answersSuite) Test_answerAddresses() {
+	network := func(address string, cidr string) multicast.NetworkInfo {
+		_, subnet, err := net.ParseCIDR(cidr)
+		s.Require().NoError(err)
+
+		return multicast.NetworkInfo{Address: address, Subnet: subnet}
+	}
+
+	info := []multicast.NetworkInfo{
+		network("10.0.1.11", "10.0.1.0/24"),
+		network("10.0.2.11", "10.0.2.0/24"),
+		network("fd42::11", "fd42::/64"),
+	}
+
+	cases := []struct {
+		desc      string
+		answer    string
+		addresses []string
+		err       bool
+	}{
+		{
+			desc:      "Address",
+			answer:    "10.0.2.11",
+			addresses: []string{"10.0.2.11"},
+		},
+		{
+			desc:      "Subnet",
+			answer:    "10.0.1.0/24",
+			addresses: []string{"10.0.1.11"},
+		},
+		{
+			desc:      "Address and subnet of each IP family",
+			answer:    "fd42::/64, 10.0.1.11",
+			addresses: []string{"fd42::11", "10.0.1.11"},
+		},
+		{
+			desc:      "Subnet matching several addresses",
+			answer:    "10.0.0.0/16",
+			addresses: []string{"10.0.1.11", "10.0.2.11"},
+		},
+		{
+			desc:   "Unknown address",
+			answer: "10.0.1.12",
+			err:    true,
+		},
+		{
+			desc:   "Unknown subnet",
+			answer: "10.0.1.11,192.168.0.0/24",
+			err:    true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		addresses, err := answerAddresses(c.answer, info)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.addresses, addresses)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"slices"
 	"sort"
@@ -82,14 +83,14 @@ func checkInitialized(stateDir string, expectInitialized bool, preseed bool) err
 				errMsg = errMsg + ". Run 'microcloud init' first"
 			}
 
-			return fmt.Errorf("%s", errMsg)
+			return withExitCode(ExitCodeNotInitialized, fmt.Errorf("%s", errMsg))
 		} else if !expectInitialized && initialized {
 			errMsg := fmt.Sprintf("%s is already initialized", s.Type())
 			if s.Type() == types.MicroCloud && !preseed {
 				errMsg = errMsg + ". Use 'microcloud add' instead"
 			}
 
-			return fmt.Errorf("%s", errMsg)
+			return withExitCode(ExitCodeAlreadyInitialized, fmt.Errorf("%s", errMsg))
 		}
 
 		return nil
@@ -233,7 +234,25 @@ func (c *initConfig) askAddress(filterAddress string) error {
 		}
 
 		listenAddr = info[0].Address
-		if !c.autoSetup && len(info) > 1 {
+		answer, answered := c.answers["address"]
+		if answered {
+			selected, err := answerAddresses(answer, info)
+			if err != nil {
+				return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid answer %q: %w", "address", err))
+			}
+
+			listenAddr, c.altAddress, err = selectAddresses(selected, isDualStack(info))
+			if err != nil {
+				return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid answer %q: %w", "address", err))
+			}
+
+			if c.altAddress != "" {
+				fmt.Println(tui.SummarizeResult("Using addresses %s and %s for MicroCloud", listenAddr, c.altAddress))
+			} else {
+				fmt.Println(tui.SummarizeResult("Using address %s for MicroCloud", listenAddr))
+			}
+		} else if !c.autoSetup && len(info) > 1 {
+			dualStack := isDualStack(info)
 			data := make([][]string, 0, len(info))
 			for _, network := range info {
 				// Filter out addresses which are not in the same network as the filter address.
@@ -244,19 +263,33 @@ func (c *initConfig) askAddress(filterAddress string) error {
 				data = append(data, []string{network.Address, network.Interface.Name})
 			}
 
+			question := "Select an address for MicroCloud's internal traffic:"
+			if dualStack {
+				question = "Select an address for MicroCloud's internal traffic, or one IPv4 and one IPv6 address:"
+			}
+
 			err := c.askRetry("Retry selecting an address?", func() error {
 				table := tui.NewSelectableTable([]string{"ADDRESS", "IFACE"}, data)
-				answers, err := table.Render(context.Background(), c.asker, "Select an address for MicroCloud's internal traffic:")
+				answers, err := table.Render(context.Background(), c.asker, question)
 				if err != nil {
 					return err
 				}
 
-				if len(answers) != 1 {
-					return errors.New("You must select exactly one address")
+				selected := make([]string, 0, len(answers))
+				for _, answer := range answers {
+					selected = append(selected, answer["ADDRESS"])
 				}
 
-				listenAddr = answers[0]["ADDRESS"]
-				fmt.Printf("\n%s\n\n", tui.SummarizeResult("Using address %s for MicroCloud", listenAddr))
+				listenAddr, c.altAddress, err = selectAddresses(selected, dualStack)
+				if err != nil {
+					return err
+				}
+
+				if c.altAddress != "" {
+					fmt.Printf("\n%s\n\n", tui.SummarizeResult("Using addresses %s and %s for MicroCloud", listenAddr, c.altAddress))
+				} else {
+					fmt.Printf("\n%s\n\n", tui.SummarizeResult("Using address %s for MicroCloud", listenAddr))
+				}
 
 				return nil
 			})
@@ -268,32 +301,7 @@ func (c *initConfig) askAddress(filterAddress string) error {
 		}
 	}
 
-	var subnet *net.IPNet
-	var iface *net.Interface
-	for _, network := range info {
-		if network.Subnet.Contains(net.ParseIP(listenAddr)) {
-			subnet = network.Subnet
-			iface = &network.Interface
-			break
-		}
-	}
-
-	if subnet == nil {
-		return fmt.Errorf("Could not find valid subnet for address %q", listenAddr)
-	}
-
-	c.address = listenAddr
-	c.lookupIface = iface
-	c.lookupSubnet = subnet
-
-	bootstrapSystem, ok := c.systems[c.name]
-	if ok {
-		bootstrapSystem.ServerInfo.Address = listenAddr
-		bootstrapSystem.MicroCloudInternalNetwork = &NetworkInterfaceInfo{Interface: *iface, Subnet: c.lookupSubnet, IP: net.IP(listenAddr)}
-		c.systems[c.name] = bootstrapSystem
-	}
-
-	return nil
+	return c.useAddress(listenAddr, info)
 }
 
 func (c *initConfig) askDisks(sh *service.Handler) error {
@@ -310,6 +318,50 @@ func (c *initConfig) askDisks(sh *service.Handler) error {
 	return nil
 }
 
+// diskRows returns the rows of the disk selection table for the given disks of each system.
+func diskRows(availableDisks map[string]map[string]api.ResourcesStorageDisk) [][]string {
+	data := [][]string{}
+	for peer, disks := range availableDisks {
+		for _, disk := range disks {
+			data = append(data, []string{peer, disk.Model, units.GetByteSizeStringIEC(int64(disk.Size), 2), disk.Type, service.FormatDiskPath(disk)})
+		}
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	return data
+}
+
+// refreshAvailableDisks returns the given available disks of each system that are still unpartitioned in its LXD resources, with their current details.
+// With invalidate, the resources of the systems are fetched again first, so disks partitioned or removed since the last question are left out.
+func (c *initConfig) refreshAvailableDisks(sh *service.Handler, availableDisks map[string]map[string]api.ResourcesStorageDisk, invalidate bool) (map[string]map[string]api.ResourcesStorageDisk, error) {
+	refreshed := make(map[string]map[string]api.ResourcesStorageDisk, len(availableDisks))
+	for name, available := range availableDisks {
+		if invalidate {
+			c.invalidateSystemResources(name)
+		}
+
+		resources, err := c.systemResources(sh, name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get system resources of peer %q: %w", name, err)
+		}
+
+		disks := map[string]api.ResourcesStorageDisk{}
+		for _, disk := range resources.Storage.Disks {
+			_, ok := available[disk.ID]
+			if ok && len(disk.Partitions) == 0 {
+				disks[disk.ID] = disk
+			}
+		}
+
+		if len(disks) > 0 {
+			refreshed[name] = disks
+		}
+	}
+
+	return refreshed, nil
+}
+
 func (c *initConfig) askLocalPool(sh *service.Handler) error {
 	useJoinConfig := false
 	askSystems := map[string]bool{}
@@ -345,6 +397,11 @@ func (c *initConfig) askLocalPool(sh *service.Handler) error {
 		availableDisks[name] = state.AvailableDisks
 	}
 
+	availableDisks, err := c.refreshAvailableDisks(sh, availableDisks, false)
+	if err != nil {
+		return err
+	}
+
 	// Local storage is already set up on every system, or if not every system has a disk.
 	if len(askSystems) == 0 || len(availableDisks) != len(askSystems) {
 		tui.PrintWarning("No disks available for local storage. Skipping configuration")
@@ -352,25 +409,9 @@ func (c *initConfig) askLocalPool(sh *service.Handler) error {
 		return nil
 	}
 
-	data := [][]string{}
 	selectedDisks := map[string]string{}
-	for peer, disks := range availableDisks {
-		sortedDisks := []api.ResourcesStorageDisk{}
-		for _, disk := range disks {
-			sortedDisks = append(sortedDisks, disk)
-		}
-
-		sort.Slice(sortedDisks, func(i, j int) bool {
-			return service.FormatDiskPath(sortedDisks[i]) < service.FormatDiskPath(sortedDisks[j])
-		})
 
-		for _, disk := range sortedDisks {
-			devicePath := service.FormatDiskPath(disk)
-			data = append(data, []string{peer, disk.Model, units.GetByteSizeStringIEC(int64(disk.Size), 2), disk.Type, devicePath})
-		}
-	}
-
-	wantsDisks, err := c.asker.AskBool("Would you like to set up local storage?", true)
+	wantsDisks, err := c.askBool("local-storage", "Would you like to set up local storage?", true)
 	if err != nil {
 		return err
 	}
@@ -386,11 +427,20 @@ func (c *initConfig) askLocalPool(sh *service.Handler) error {
 		return fmt.Errorf("Failed to check for source.wipe extension: %w", err)
 	}
 
+	attempt := 0
 	err = c.askRetry("Retry selecting disks?", func() error {
+		// Look at the disks again when retrying, in case they were changed in the meantime.
+		if attempt > 0 {
+			availableDisks, err = c.refreshAvailableDisks(sh, availableDisks, true)
+			if err != nil {
+				return err
+			}
+		}
+
+		attempt++
 		selected := map[string]string{}
-		sort.Sort(cli.SortColumnsNaturally(data))
 		header := []string{"LOCATION", "MODEL", "CAPACITY", "TYPE", "PATH"}
-		table := tui.NewSelectableTable(header, data)
+		table := tui.NewSelectableTable(header, diskRows(availableDisks))
 		answers, err := table.Render(context.Background(), c.asker, "Select exactly one disk from each cluster member:")
 		if err != nil {
 			return err
@@ -738,10 +788,17 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 
 			if askSystemsRemote[name] {
 				availableDisks[name] = state.AvailableDisks
+			}
+		}
 
-				if len(state.AvailableDisks) > 0 {
-					availableDiskCount++
-				}
+		availableDisks, err := c.refreshAvailableDisks(sh, availableDisks, false)
+		if err != nil {
+			return err
+		}
+
+		for _, disks := range availableDisks {
+			if len(disks) > 0 {
+				availableDiskCount++
 			}
 		}
 
@@ -751,7 +808,7 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 			return nil
 		}
 
-		wantsDisks, err := c.asker.AskBool("Would you like to set up distributed storage?", true)
+		wantsDisks, err := c.askBool("distributed-storage", "Would you like to set up distributed storage?", true)
 		if err != nil {
 			return err
 		}
@@ -771,34 +828,25 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 		var insufficientDisks bool
 
 		if availableDiskCount > 0 && wantsDisks {
+			attempt := 0
 			err = c.askRetry("Change disk selection?", func() error {
-				selectedDisks = map[string][]string{}
-				wipeDisks = map[string]map[string]bool{}
-				header := []string{"LOCATION", "MODEL", "CAPACITY", "TYPE", "PATH"}
-				data := [][]string{}
-				for peer, disks := range availableDisks {
-					sortedDisks := []api.ResourcesStorageDisk{}
-					for _, disk := range disks {
-						sortedDisks = append(sortedDisks, disk)
-					}
-
-					// Ensure the list of disks is sorted by name.
-					sort.Slice(sortedDisks, func(i, j int) bool {
-						return service.FormatDiskPath(sortedDisks[i]) < service.FormatDiskPath(sortedDisks[j])
-					})
-
-					for _, disk := range sortedDisks {
-						// Skip any disks that have been reserved for the local storage pool.
-						devicePath := service.FormatDiskPath(disk)
-						data = append(data, []string{peer, disk.Model, units.GetByteSizeStringIEC(int64(disk.Size), 2), disk.Type, devicePath})
+				// Look at the disks again when retrying, in case they were changed in the meantime.
+				if attempt > 0 {
+					availableDisks, err = c.refreshAvailableDisks(sh, availableDisks, true)
+					if err != nil {
+						return err
 					}
 				}
 
+				attempt++
+				selectedDisks = map[string][]string{}
+				wipeDisks = map[string]map[string]bool{}
+				header := []string{"LOCATION", "MODEL", "CAPACITY", "TYPE", "PATH"}
+				data := diskRows(availableDisks)
 				if len(data) == 0 {
 					return errors.New("Invalid disk configuration. Found no available disks")
 				}
 
-				sort.Sort(cli.SortColumnsNaturally(data))
 				var toWipe []map[string]string
 				table := tui.NewSelectableTable(header, data)
 				selected, err := table.Render(context.Background(), c.asker, "Select from the available unpartitioned disks:")
@@ -912,16 +960,22 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 	encryptDisks := false
 	if len(selectedDisks) > 0 {
 		var err error
-		encryptDisks, err = c.asker.AskBool("Do you want to encrypt the selected disks?", false)
+		encryptDisks, err = c.askBool("encrypt-disks", "Do you want to encrypt the selected disks?", false)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Tiers can only be defined along with the remote storage pool.
+	lxd := sh.Services[types.LXD].(*service.LXDService)
+	diskTiers, err := c.askCephTiers(lxd.ResourceNames(), selectedDisks, c.bootstrap && !useJoinConfigRemote)
+	if err != nil {
+		return err
+	}
+
 	// If a cephfs pool has already been set up, we will extend it automatically, so no need to ask the question.
 	setupCephFS := useJoinConfigRemoteFS
 	if !useJoinConfigRemoteFS {
-		lxd := sh.Services[types.LXD].(*service.LXDService)
 		ext := "storage_cephfs_create_missing"
 		hasCephFS, err := lxd.HasExtension(context.Background(), lxd.Name(), lxd.Address(), nil, ext)
 		if err != nil {
@@ -929,15 +983,24 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 		}
 
 		if hasCephFS {
-			setupCephFS, err = c.asker.AskBool("Would you like to set up CephFS remote storage?", true)
+			setupCephFS, err = c.askBool("cephfs", "Would you like to set up CephFS remote storage?", true)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
+	// MicroCeph is only set up if the local system is part of it.
+	if c.bootstrap && len(selectedDisks) > 0 && sh.Services[types.MicroCeph] != nil {
+		var err error
+		c.cephDashboard, err = c.askBool("ceph-dashboard", "Would you like to enable the Ceph dashboard and Prometheus metrics?", false)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Ask ceph networking questions last.
-	err := c.askCephNetwork(sh)
+	err = c.askCephNetwork(sh)
 	if err != nil {
 		return err
 	}
@@ -956,7 +1019,6 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 	joinConfigs := map[string][]api.ClusterMemberConfigKey{}
 	finalConfigs := []api.StoragePoolsPost{}
 	targetConfigs := map[string][]api.StoragePoolsPost{}
-	lxd := sh.Services[types.LXD].(*service.LXDService)
 	if useJoinConfigRemote {
 		for target := range askSystemsRemote {
 			if joinConfigs[target] == nil {
@@ -1030,6 +1092,10 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 			system.MicroCephDisks = append(system.MicroCephDisks, osds[peer]...)
 		}
 
+		if diskTiers[peer] != nil {
+			system.CephDiskTiers = diskTiers[peer]
+		}
+
 		if peer == sh.Name && finalConfigs != nil {
 			system.StoragePools = append(system.StoragePools, finalConfigs...)
 		}
@@ -1037,6 +1103,8 @@ func (c *initConfig) askRemotePool(sh *service.Handler) error {
 		c.systems[peer] = system
 	}
 
+	c.addCephTierPools(lxd)
+
 	return nil
 }
 
@@ -1060,6 +1128,8 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 	useOVNJoinConfig := false
 	askSystems := map[string]bool{}
 	warningMessage := ""
+	hasUplinkInterfaces := false
+	missingUplinkInterfaces := ""
 	for _, state := range c.state {
 		hasOVN, supportsOVN := state.SupportsOVNNetwork()
 		if !supportsOVN {
@@ -1067,9 +1137,11 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 			break
 		}
 
+		// Systems without uplink interfaces can still be set up with no uplink connectivity, as long as another system provides it.
 		if len(state.AvailableUplinkInterfaces) == 0 {
-			warningMessage = fmt.Sprintf("System %q is ineligible for distributed networking. At least one interface in state UP with no IPs assigned or a bridge is required", state.ClusterName)
-			break
+			missingUplinkInterfaces = state.ClusterName
+		} else {
+			hasUplinkInterfaces = true
 		}
 
 		if hasOVN {
@@ -1079,6 +1151,10 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 		}
 	}
 
+	if warningMessage == "" && !hasUplinkInterfaces && missingUplinkInterfaces != "" {
+		warningMessage = fmt.Sprintf("System %q is ineligible for distributed networking. At least one interface in state UP with no IPs assigned or a bridge is required", missingUplinkInterfaces)
+	}
+
 	if len(askSystems) == 0 {
 		// In case we already have the networks on all systems, this indicates an error as we would want to ask for the to be used uplink interface.
 		// Check whether or not we already have a warning message set to not hide the underlying problem.
@@ -1112,7 +1188,7 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 		var err error
 
 		// Ask the user if they want OVN.
-		wantsOVN, err = c.asker.AskBool("Configure distributed networking?", true)
+		wantsOVN, err = c.askBool("distributed-networking", "Configure distributed networking?", true)
 		if err != nil {
 			return err
 		}
@@ -1123,7 +1199,7 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 	}
 
 	// Uplink selection table.
-	header := []string{"LOCATION", "IFACE", "TYPE"}
+	header := []string{"LOCATION", "IFACE", "TYPE", "SPEED"}
 	data := [][]string{}
 	for peer, state := range c.state {
 		if !askSystems[peer] {
@@ -1131,11 +1207,17 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 		}
 
 		for _, net := range state.AvailableUplinkInterfaces {
-			data = append(data, []string{peer, net.Name, net.Type})
+			speed, err := c.interfaceSpeed(sh, peer, net.Name)
+			if err != nil {
+				return err
+			}
+
+			data = append(data, []string{peer, net.Name, net.Type, speed})
 		}
 	}
 
 	var selectedIfaces map[string]string
+	var noUplinkSystems map[string]bool
 	err := c.askRetry("Retry selecting uplink interfaces?", func() error {
 		table := tui.NewSelectableTable(header, data)
 		answers, err := table.Render(context.Background(), c.asker, "Select an available interface per system to provide external connectivity for distributed network(s):")
@@ -1154,11 +1236,34 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 			selected[target] = iface
 		}
 
-		if len(selected) != len(askSystems) {
+		noUplink := map[string]bool{}
+		if len(selected) > 0 && len(selected) != len(askSystems) {
+			missing := []string{}
+			for peer := range askSystems {
+				if selected[peer] == "" {
+					missing = append(missing, peer)
+				}
+			}
+
+			sort.Strings(missing)
+			wantsNoUplink, err := c.asker.AskBool(fmt.Sprintf("Set up %s with no uplink connectivity, excluding them from the OVN gateway chassis?", strings.Join(missing, ", ")), false)
+			if err != nil {
+				return err
+			}
+
+			if wantsNoUplink {
+				for _, peer := range missing {
+					noUplink[peer] = true
+				}
+			}
+		}
+
+		if len(selected)+len(noUplink) != len(askSystems) {
 			return errors.New("Failed to add OVN uplink network: Some peers don't have a selected interface")
 		}
 
 		selectedIfaces = selected
+		noUplinkSystems = noUplink
 
 		return nil
 	})
@@ -1175,6 +1280,10 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 		fmt.Println(tui.SummarizeResult("Using %s on %s for OVN uplink", iface, peer))
 	}
 
+	for peer := range noUplinkSystems {
+		fmt.Println(tui.SummarizeResult("Using no OVN uplink on %s", peer))
+	}
+
 	// If we didn't select anything, then abort network setup.
 	if len(selectedIfaces) == 0 {
 		return nil
@@ -1185,6 +1294,8 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 
 	// Prepare the configuration.
 	var dnsAddresses string
+	var dnsSearch string
+	var ipv6Prefix string
 	ipConfig := map[string]string{}
 	if !useOVNJoinConfig {
 		for _, ip := range []string{"IPv4", "IPv6"} {
@@ -1210,7 +1321,7 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 			}
 
 			msg := fmt.Sprintf("Specify the %s gateway (CIDR) on the uplink network", ip)
-			gateway, err := c.asker.AskString(msg, "", validator)
+			gateway, err := c.askString("ovn-"+strings.ToLower(ip)+"-gateway", msg, "", validator)
 			if err != nil {
 				return err
 			}
@@ -1220,12 +1331,12 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 			}
 
 			if ip == "IPv4" {
-				rangeStart, err := c.asker.AskString(fmt.Sprintf("Specify the first %s address in the range to use on the uplink network", ip), "", validate.Required(validate.IsNetworkAddressV4))
+				rangeStart, err := c.askString("ovn-ipv4-range-start", fmt.Sprintf("Specify the first %s address in the range to use on the uplink network", ip), "", validate.Required(validate.IsNetworkAddressV4))
 				if err != nil {
 					return err
 				}
 
-				rangeEnd, err := c.asker.AskString(fmt.Sprintf("Specify the last %s address in the range to use on the uplink network", ip), "", validate.Required(validate.IsNetworkAddressV4))
+				rangeEnd, err := c.askString("ovn-ipv4-range-end", fmt.Sprintf("Specify the last %s address in the range to use on the uplink network", ip), "", validate.Required(validate.IsNetworkAddressV4))
 				if err != nil {
 					return err
 				}
@@ -1233,6 +1344,11 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 				ipConfig[gateway] = fmt.Sprintf("%s-%s", rangeStart, rangeEnd)
 			} else {
 				ipConfig[gateway] = ""
+
+				ipv6Prefix, err = c.askString("ovn-ipv6-prefix", "Specify the IPv6 prefix (CIDR) delegated to the uplink network for routed OVN subnets (empty to skip)", "", validate.Optional(validate.IsNetworkV6))
+				if err != nil {
+					return err
+				}
 			}
 		}
 
@@ -1251,22 +1367,58 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 		}
 
 		gatewayAddrs := strings.Join(gateways, ",")
-		dnsAddresses, err = c.asker.AskString("Specify the DNS addresses (comma-separated IPv4 / IPv6 addresses) for the distributed network", gatewayAddrs, validate.Optional(validate.IsListOf(validate.IsNetworkAddress)))
+		dnsAddresses, err = c.askString("ovn-dns-servers", "Specify the DNS addresses (comma-separated IPv4 / IPv6 addresses) for the distributed network", gatewayAddrs, validate.Optional(validate.IsListOf(validate.IsNetworkAddress)))
+		if err != nil {
+			return err
+		}
+
+		dnsSearch, err = c.askString("ovn-dns-search", "Specify the DNS search domains (comma-separated) for the distributed network (empty to skip)", "", validate.Optional(validate.IsListOf(service.ValidateDNSDomain)))
 		if err != nil {
 			return err
 		}
+
+		if c.bootstrap {
+			network := InitNetwork{IPv6Prefix: ipv6Prefix}
+			for gateway, ipRange := range ipConfig {
+				if ipRange != "" {
+					network.IPv4Gateway = gateway
+					network.IPv4Range = ipRange
+				}
+			}
+
+			err = c.askOVNNAT(network)
+			if err != nil {
+				return err
+			}
+
+			err = c.askOVNEncapsulation(sh)
+			if err != nil {
+				return err
+			}
+
+			err = c.askOVNCentral(sh)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	lxd := sh.Services[types.LXD].(*service.LXDService)
 	joinConfigs := map[string]api.ClusterMemberConfigKey{}
 	targetConfigs := map[string]api.NetworksPost{}
 	finalConfigs := []api.NetworksPost{}
+	// LXD requires a parent on every member, so the members with no uplink use an isolated bridge.
+	parents := maps.Clone(selectedIfaces)
+	for target := range noUplinkSystems {
+		parents[target] = service.NoUplinkBridge
+	}
+
 	if useOVNJoinConfig {
-		for target, parent := range selectedIfaces {
+		for target, parent := range parents {
 			joinConfigs[target] = lxd.DefaultOVNNetworkJoinConfig(parent)
 		}
 	} else {
-		for target, parent := range selectedIfaces {
+		for target, parent := range parents {
 			targetConfigs[target] = lxd.DefaultPendingOVNNetwork(parent)
 		}
 
@@ -1288,7 +1440,11 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 				}
 			}
 
-			uplink, ovn := lxd.DefaultOVNNetwork(ipv4Gateway, ipv4Ranges, ipv6Gateway, dnsAddresses)
+			uplink, ovn, err := c.ovnNAT.networks(lxd.DefaultOVNNetwork(ipv4Gateway, ipv4Ranges, ipv6Gateway, ipv6Prefix, dnsAddresses, dnsSearch))
+			if err != nil {
+				return err
+			}
+
 			finalConfigs = append(finalConfigs, uplink, ovn)
 		}
 	}
@@ -1405,6 +1561,7 @@ func (c *initConfig) askOVNNetwork(sh *service.Handler) error {
 			system.Networks = append(system.Networks, finalConfigs...)
 		}
 
+		system.NoUplink = noUplinkSystems[peer]
 		if ovnUnderlaySelectedNets != nil {
 			ovnUnderlayNet, ok := ovnUnderlaySelectedNets[peer]
 			if ok {
@@ -1424,13 +1581,14 @@ func (c *initConfig) askNetwork(sh *service.Handler) error {
 		return err
 	}
 
+	names := sh.Services[types.LXD].(*service.LXDService).ResourceNames()
 	for _, system := range c.systems {
 		if len(system.TargetNetworks) > 0 || len(system.Networks) > 0 {
 			return nil
 		}
 
 		for _, cfg := range system.JoinConfig {
-			if cfg.Name == service.DefaultOVNNetwork || cfg.Name == service.DefaultUplinkNetwork {
+			if cfg.Name == names.OVNNetwork || cfg.Name == names.UplinkNetwork {
 				return nil
 			}
 		}
@@ -1562,7 +1720,15 @@ func (c *initConfig) askCephNetwork(sh *service.Handler) error {
 	microCloudInternalNetworkAddr := c.lookupSubnet.IP.Mask(c.lookupSubnet.Mask)
 	ones, _ := c.lookupSubnet.Mask.Size()
 	microCloudInternalNetworkAddrCIDR := fmt.Sprintf("%s/%d", microCloudInternalNetworkAddr.String(), ones)
-	internalCephSubnet, err := c.asker.AskString("What subnet (IPv4/IPv6 CIDR) would you like your Ceph internal traffic on?", microCloudInternalNetworkAddrCIDR, validate.IsNetwork)
+
+	// Systems on routed subnets are outside the lookup subnet, so there is no default and the chosen subnets are validated on every system.
+	routed := c.routedSystems()
+	if len(routed) > 0 {
+		tui.PrintWarning(fmt.Sprintf("Systems %s are outside of %s. Choose Ceph subnets that all systems have an address in", strings.Join(routed, ", "), microCloudInternalNetworkAddrCIDR))
+		microCloudInternalNetworkAddrCIDR = ""
+	}
+
+	internalCephSubnet, err := c.askString("ceph-internal-network", "What subnet (IPv4/IPv6 CIDR) would you like your Ceph internal traffic on?", microCloudInternalNetworkAddrCIDR, validate.IsNetwork)
 	if err != nil {
 		return err
 	}
@@ -1595,7 +1761,7 @@ func (c *initConfig) askCephNetwork(sh *service.Handler) error {
 		}
 	}
 
-	publicCephSubnet, err := c.asker.AskString("What subnet (either IPv4 or IPv6 CIDR notation) would you like your Ceph public traffic on?", internalCephSubnet, validate.IsNetwork)
+	publicCephSubnet, err := c.askString("ceph-public-network", "What subnet (either IPv4 or IPv6 CIDR notation) would you like your Ceph public traffic on?", internalCephSubnet, validate.IsNetwork)
 	if err != nil {
 		return err
 	}
@@ -1730,6 +1896,24 @@ func (c *initConfig) askJoinIntents(gw *cloudClient.WebsocketGateway, expectedSy
 					break
 				}
 
+				// Skip systems other than the expected ones, when rejoining a cluster member.
+				if len(expectedSystems) > 0 && !slices.Contains(expectedSystems, session.Intent.Name) {
+					logger.Warn("Ignoring join intent of unexpected system", logger.Ctx{"name": session.Intent.Name, "address": session.Intent.Address})
+					break
+				}
+
+				// Skip systems which aren't candidate MAAS machines.
+				if !c.maasAllowsJoin(session.Intent) {
+					logger.Warn("Ignoring join intent of system not found in MAAS", logger.Ctx{"name": session.Intent.Name, "address": session.Intent.Address})
+					break
+				}
+
+				// Skip systems impersonating an expected member.
+				if !c.expectedMembersAllowJoin(session.Intent) {
+					logger.Warn("Ignoring join intent of system not matching the expected member", logger.Ctx{"name": session.Intent.Name, "address": session.Intent.Address})
+					break
+				}
+
 				joinIntents[session.Intent.Name] = session.Intent
 
 				remoteCert, err := shared.ParseCert([]byte(session.Intent.Certificate))
@@ -1761,8 +1945,8 @@ func (c *initConfig) askJoinIntents(gw *cloudClient.WebsocketGateway, expectedSy
 					break
 				}
 
-				// Skip systems which aren't listed in the preseed.
-				if !slices.Contains(expectedSystems, session.Intent.Name) {
+				// Skip systems which aren't listed in the preseed, or are impersonating an expected member.
+				if !slices.Contains(expectedSystems, session.Intent.Name) || !c.expectedMembersAllowJoin(session.Intent) {
 					continue
 				}
 
@@ -1778,7 +1962,7 @@ func (c *initConfig) askJoinIntents(gw *cloudClient.WebsocketGateway, expectedSy
 	}
 
 	var systems []types.SessionJoinPost
-	if !c.autoSetup {
+	if !c.autoSetup && !c.acceptExpected {
 		go renderIntentsInteractive()
 		var answers []map[string]string
 		err := c.askRetry("Retry selecting systems?", func() error {
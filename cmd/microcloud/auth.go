@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// authTokenRows returns the table rows of the API tokens, telling the expired ones apart.
+func authTokenRows(tokens []types.AuthToken, now time.Time) [][]string {
+	data := make([][]string, len(tokens))
+	for i, token := range tokens {
+		status := "valid"
+		if now.After(token.ExpiresAt) {
+			status = "expired"
+		}
+
+		data[i] = []string{token.Name, token.Role, token.CreatedAt.Local().Format("2006-01-02 15:04:05"), token.ExpiresAt.Local().Format("2006-01-02 15:04:05"), status}
+	}
+
+	return data
+}
+
+type cmdAuth struct {
+	common *CmdControl
+}
+
+// command returns the auth subcommand.
+func (c *cmdAuth) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the authentication of automation to the MicroCloud API",
+		RunE:  c.run,
+	}
+
+	var cmdToken = cmdAuthToken{common: c.common}
+	cmd.AddCommand(cmdToken.command())
+
+	return cmd
+}
+
+// run runs the auth subcommand.
+func (c *cmdAuth) run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+type cmdAuthToken struct {
+	common *CmdControl
+}
+
+// command returns the subcommand for managing API tokens.
+func (c *cmdAuthToken) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage time-limited API tokens for automation",
+		Long: `Manage time-limited API tokens for automation
+
+API tokens let automation such as CI pipelines use the MicroCloud API without a trusted client certificate.
+Send the token in the "Authorization: Bearer <token>" header of the requests.
+
+Tokens with the "read-only" role can get the status, events and health reports of the cluster.
+Tokens with the "operator" role can also run the checks of "microcloud verify" and generate health reports.`,
+		RunE: c.run,
+	}
+
+	var cmdCreate = cmdAuthTokenCreate{common: c.common}
+	cmd.AddCommand(cmdCreate.command())
+
+	var cmdList = cmdAuthTokenList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	var cmdRevoke = cmdAuthTokenRevoke{common: c.common}
+	cmd.AddCommand(cmdRevoke.command())
+
+	return cmd
+}
+
+// run runs the subcommand for managing API tokens.
+func (c *cmdAuthToken) run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+type cmdAuthTokenCreate struct {
+	common     *CmdControl
+	flagRole   string
+	flagExpiry string
+}
+
+// command returns the subcommand for creating an API token.
+func (c *cmdAuthTokenCreate) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create an API token, and print its secret",
+		Long: `Create an API token, and print its secret
+
+The secret is only printed once, as MicroCloud only stores its hash.
+The expiry is in the "<integer>(S|M|H|d|w|m|y)" format, for example "30d" or "1d 12H", and cannot exceed a year.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagRole, "role", types.AuthTokenRoleReadOnly, "Role of the token (read-only|operator)")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "30d", "Time after which the token expires")
+
+	return cmd
+}
+
+// run runs the subcommand for creating an API token.
+func (c *cmdAuthTokenCreate) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	err := service.ValidateAuthTokenName(args[0])
+	if err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid API token name %q: %w", args[0], err))
+	}
+
+	err = service.ValidateAuthTokenRole(c.flagRole)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	_, err = service.AuthTokenExpiry(time.Now(), c.flagExpiry)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	token, err := cloudClient.CreateAuthToken(cmd.Context(), client, types.AuthTokensPost{Name: args[0], Role: c.flagRole, Expiry: c.flagExpiry})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, tui.SummarizeResult("Created API token %s with role %s, expiring at %s", token.Name, token.Role, token.ExpiresAt.Local().Format("2006-01-02 15:04:05")))
+	fmt.Println(token.Token)
+
+	return nil
+}
+
+type cmdAuthTokenList struct {
+	common     *CmdControl
+	flagFormat string
+}
+
+// command returns the subcommand for listing API tokens.
+func (c *cmdAuthTokenList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the API tokens",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand for listing API tokens.
+func (c *cmdAuthTokenList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	tokens, err := cloudClient.GetAuthTokens(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	header := []string{"NAME", "ROLE", "CREATED", "EXPIRES", "STATUS"}
+	table, err := tui.FormatData(c.flagFormat, header, authTokenRows(tokens, time.Now()), tokens)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+type cmdAuthTokenRevoke struct {
+	common *CmdControl
+}
+
+// command returns the subcommand for revoking an API token.
+func (c *cmdAuthTokenRevoke) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke <name>",
+		Short: "Revoke an API token",
+		RunE:  c.run,
+	}
+
+	return cmd
+}
+
+// run runs the subcommand for revoking an API token.
+func (c *cmdAuthTokenRevoke) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	err = cloudClient.DeleteAuthToken(cmd.Context(), client, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(tui.SummarizeResult("Revoked API token %s", args[0]))
+
+	return nil
+}
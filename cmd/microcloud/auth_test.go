@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type authSuite struct {
+	suite.Suite
+}
+
+func TestAuthSuite(t *testing.T) {
+	suite.Run(t, new(authSuite))
+}
+
+func (s *authSuite) Test_authTokenRows() {
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	tokens := []types.AuthToken{
+		{Name: "ci", Role: types.AuthTokenRoleReadOnly, CreatedAt: now.AddDate(0, -1, 0), ExpiresAt: now.AddDate(0, 0, 1)},
+		{Name: "old", Role: types.AuthTokenRoleOperator, CreatedAt: now.AddDate(0, -2, 0), ExpiresAt: now.AddDate(0, 0, -1)},
+	}
+
+	rows := authTokenRows(tokens, now)
+	s.Len(rows, 2)
+	s.Equal([]string{"ci", "read-only"}, rows[0][:2])
+	s.Equal("valid", rows[0][4])
+	s.Equal([]string{"old", "operator"}, rows[1][:2])
+	s.Equal("expired", rows[1][4])
+}
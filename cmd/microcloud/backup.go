@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/service"
+	"github.com/canonical/microcloud/microcloud/version"
+)
+
+// backupCephConfigKeys are the Ceph configuration keys recorded in a backup.
+var backupCephConfigKeys = []string{"public_network", "cluster_network"}
+
+// Backup is the control plane of a MicroCloud, used to restore it onto new hardware.
+type Backup struct {
+	MicroCloudVersion string    `json:"microcloud_version"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// Config is the cluster-wide MicroCloud daemon configuration.
+	Config map[string]string `json:"config"`
+
+	// CephConfig is the Ceph network configuration.
+	CephConfig map[string]string `json:"ceph_config,omitempty"`
+
+	// Members are the cluster members, sorted by name.
+	Members []BackupMember `json:"members"`
+}
+
+// BackupMember is a cluster member recorded in a backup.
+type BackupMember struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+
+	// Services are the services the member is clustered in.
+	Services []types.ServiceType `json:"services"`
+
+	// OSDs are the Ceph OSDs of the member.
+	OSDs []BackupOSD `json:"osds,omitempty"`
+}
+
+// BackupOSD is a Ceph OSD recorded in a backup.
+type BackupOSD struct {
+	OSD  int64  `json:"osd"`
+	Path string `json:"path"`
+}
+
+// buildBackup returns the backup of the MicroCloud with the given member statuses and configuration.
+func buildBackup(statuses []types.Status, config map[string]string, cephConfig map[string]string) Backup {
+	backup := Backup{
+		MicroCloudVersion: version.Version(),
+		CreatedAt:         time.Now().UTC(),
+		Config:            config,
+		Members:           make([]BackupMember, 0, len(statuses)),
+	}
+
+	for _, key := range backupCephConfigKeys {
+		if cephConfig[key] == "" {
+			continue
+		}
+
+		if backup.CephConfig == nil {
+			backup.CephConfig = map[string]string{}
+		}
+
+		backup.CephConfig[key] = cephConfig[key]
+	}
+
+	for _, status := range statuses {
+		member := BackupMember{Name: status.Name, Address: status.Address, Services: []types.ServiceType{}}
+		for serviceType, members := range status.Clusters {
+			if slices.ContainsFunc(members, func(m microTypes.ClusterMember) bool { return m.Name == status.Name }) {
+				member.Services = append(member.Services, serviceType)
+			}
+		}
+
+		slices.Sort(member.Services)
+
+		for _, disk := range status.OSDs {
+			member.OSDs = append(member.OSDs, BackupOSD{OSD: disk.OSD, Path: disk.Path})
+		}
+
+		sort.Slice(member.OSDs, func(i, j int) bool { return member.OSDs[i].OSD < member.OSDs[j].OSD })
+
+		backup.Members = append(backup.Members, member)
+	}
+
+	sort.Slice(backup.Members, func(i, j int) bool { return backup.Members[i].Name < backup.Members[j].Name })
+
+	return backup
+}
+
+// readBackup reads a backup written by the backup command.
+func readBackup(path string) (*Backup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read backup: %w", err)
+	}
+
+	backup := &Backup{}
+	err = json.Unmarshal(data, backup)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse backup: %w", err)
+	}
+
+	if backup.MicroCloudVersion == "" || len(backup.Members) == 0 {
+		return nil, fmt.Errorf("%q is not a MicroCloud backup", path)
+	}
+
+	return backup, nil
+}
+
+// localCephConfig returns the Ceph configuration of the local MicroCeph, if it is installed and set up.
+func localCephConfig(ctx context.Context, stateDir string) (map[string]string, error) {
+	if !service.Exists(types.MicroCeph, addableServices[types.MicroCeph]) {
+		return nil, nil
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: stateDir})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := cloudApp.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), stateDir, types.MicroCeph)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := sh.Services[types.MicroCeph].(*service.CephService).ClusterConfig(ctx, "", nil)
+	if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+		return nil, fmt.Errorf("Failed to get the Ceph configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+type cmdBackup struct {
+	common *CmdControl
+
+	flagForce bool
+}
+
+// command returns the subcommand to back up the MicroCloud control plane.
+func (c *cmdBackup) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <file>",
+		Short: "Back up the MicroCloud control plane",
+		Long: `Back up the MicroCloud control plane
+
+The backup records the MicroCloud daemon configuration, the Ceph networks, and the cluster members with their services and OSDs.
+It can be restored onto new hardware with "microcloud restore --backup <file>".
+Instances and storage volumes are not part of the backup.`,
+		RunE: c.run,
+	}
+
+	addMaintenanceForceFlag(cmd, &c.flagForce)
+
+	return cmd
+}
+
+// run runs the subcommand to back up the MicroCloud control plane.
+func (c *cmdBackup) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	err = checkMaintenanceWindows(cmd.Context(), client, c.flagForce)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := cloudClient.GetStatus(cmd.Context(), client)
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	config, err := cloudClient.GetConfig(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	cephConfig, err := localCephConfig(cmd.Context(), c.common.FlagMicroCloudDir)
+	if err != nil {
+		return err
+	}
+
+	backup := buildBackup(statuses, config, cephConfig)
+	if len(backup.Members) == 0 {
+		return errors.New("No cluster member status could be retrieved")
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to encode backup: %w", err)
+	}
+
+	// The configuration may hold credentials, such as in webhook URLs.
+	err = os.WriteFile(args[0], append(data, '\n'), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write backup: %w", err)
+	}
+
+	fmt.Printf("Backed up %d cluster members to %q\n", len(backup.Members), args[0])
+
+	return nil
+}
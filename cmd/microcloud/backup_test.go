@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type backupSuite struct {
+	suite.Suite
+}
+
+func TestBackupSuite(t *testing.T) {
+	suite.Run(t, new(backupSuite))
+}
+
+// backupStatuses returns the statuses of a cluster with the given OSD IDs on each member.
+func backupStatuses(osds map[string][]int64) []types.Status {
+	members := []microTypes.ClusterMember{}
+	for name := range osds {
+		members = append(members, microTypes.ClusterMember{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: name}})
+	}
+
+	statuses := []types.Status{}
+	for name, ids := range osds {
+		status := types.Status{
+			Name:     name,
+			Address:  "10.0.0." + name[len(name)-1:],
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{types.MicroCloud: members, types.MicroCeph: members, types.MicroOVN: {}},
+		}
+
+		for _, id := range ids {
+			status.OSDs = append(status.OSDs, cephTypes.Disk{OSD: id, Path: "/dev/disk/by-id/disk" + name[len(name)-1:], Location: name})
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func (s *backupSuite) Test_buildBackup() {
+	statuses := backupStatuses(map[string][]int64{"micro02": {2, 1}, "micro01": {0}})
+
+	backup := buildBackup(statuses, map[string]string{"upgrade.policy": "patch"}, map[string]string{"public_network": "10.0.0.0/24", "cluster_network": "", "osd_pool_default_size": "3"})
+
+	s.NotEmpty(backup.MicroCloudVersion)
+	s.Equal(map[string]string{"upgrade.policy": "patch"}, backup.Config)
+	s.Equal(map[string]string{"public_network": "10.0.0.0/24"}, backup.CephConfig)
+	s.Len(backup.Members, 2)
+
+	s.Equal("micro01", backup.Members[0].Name)
+	s.Equal("10.0.0.1", backup.Members[0].Address)
+	s.Equal([]types.ServiceType{types.MicroCeph, types.MicroCloud}, backup.Members[0].Services)
+	s.Equal([]BackupOSD{{OSD: 0, Path: "/dev/disk/by-id/disk1"}}, backup.Members[0].OSDs)
+
+	s.Equal("micro02", backup.Members[1].Name)
+	s.Equal([]BackupOSD{{OSD: 1, Path: "/dev/disk/by-id/disk2"}, {OSD: 2, Path: "/dev/disk/by-id/disk2"}}, backup.Members[1].OSDs)
+
+	backup = buildBackup(statuses, map[string]string{}, nil)
+	s.Nil(backup.CephConfig)
+}
+
+func (s *backupSuite) Test_readBackup() {
+	dir := s.T().TempDir()
+
+	path := filepath.Join(dir, "backup.json")
+	err := os.WriteFile(path, []byte(`{"microcloud_version": "2.1.0", "config": {}, "members": [{"name": "micro01", "address": "10.0.0.1", "services": ["MicroCloud"]}]}`), 0600)
+	s.NoError(err)
+
+	backup, err := readBackup(path)
+	s.NoError(err)
+	s.Equal("micro01", backup.Members[0].Name)
+
+	err = os.WriteFile(path, []byte(`{"resources": []}`), 0600)
+	s.NoError(err)
+
+	_, err = readBackup(path)
+	s.Error(err)
+
+	_, err = readBackup(filepath.Join(dir, "missing.json"))
+	s.Error(err)
+}
+
+func (s *backupSuite) Test_backupConfigChanges() {
+	current := map[string]string{"upgrade.policy": "manual", "heartbeat.interval": "5s", "metrics.address": "[::]:9100"}
+	backup := map[string]string{"upgrade.policy": "patch", "metrics.address": "[::]:9100", "unknown.key": "value"}
+
+	changes, unsupported := backupConfigChanges(current, backup)
+	s.Equal(map[string]string{"upgrade.policy": "patch", "heartbeat.interval": ""}, changes)
+	s.Equal([]string{"unknown.key"}, unsupported)
+
+	changes, unsupported = backupConfigChanges(backup, map[string]string{"upgrade.policy": "patch", "metrics.address": "[::]:9100", "unknown.key": "value"})
+	s.Empty(changes)
+	s.Equal([]string{"unknown.key"}, unsupported)
+}
+
+func (s *backupSuite) Test_compareBackupMembers() {
+	backup := buildBackup(backupStatuses(map[string][]int64{"micro01": {0}, "micro02": {1}, "micro03": {2}}), nil, nil)
+
+	missing, unexpected := compareBackupMembers(backup, backupStatuses(map[string][]int64{"micro01": {}, "micro03": {}, "micro04": {}}))
+	s.Equal([]string{"micro02"}, missing)
+	s.Equal([]string{"micro04"}, unexpected)
+
+	missing, unexpected = compareBackupMembers(backup, backupStatuses(map[string][]int64{"micro01": {}, "micro02": {}, "micro03": {}}))
+	s.Empty(missing)
+	s.Empty(unexpected)
+}
+
+func (s *backupSuite) Test_backupOSDRows() {
+	backup := buildBackup(backupStatuses(map[string][]int64{"micro01": {0}, "micro02": {1, 2}}), nil, nil)
+
+	rows, missing := backupOSDRows(backup, backupStatuses(map[string][]int64{"micro01": {0}, "micro02": {2}}))
+	s.Equal(1, missing)
+	s.Equal([][]string{
+		{"micro01", "0", "/dev/disk/by-id/disk1", "attached"},
+		{"micro02", "1", "/dev/disk/by-id/disk2", "missing"},
+		{"micro02", "2", "/dev/disk/by-id/disk2", "attached"},
+	}, rows)
+
+	// OSDs attached to another member than in the backup aren't considered restored.
+	_, missing = backupOSDRows(backup, backupStatuses(map[string][]int64{"micro01": {0, 1, 2}}))
+	s.Equal(2, missing)
+}
@@ -0,0 +1,466 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/shared/units"
+	microClient "github.com/canonical/microcluster/v3/client"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// defaultBenchmarkThreshold is the default change in percent beyond which a benchmark result is reported as a regression.
+const defaultBenchmarkThreshold = 20
+
+// benchmarkHeader is the table header of benchmark results.
+var benchmarkHeader = []string{"MEMBER", "POOL", "TEST", "IOPS", "BANDWIDTH", "LATENCY"}
+
+// benchmarkRow returns the table row of the given benchmark result.
+func benchmarkRow(b types.Benchmark) []string {
+	return []string{b.Member, b.Pool, b.Test, formatIOPS(b.IOPS), formatBandwidth(b.Bandwidth), formatLatency(b.Latency)}
+}
+
+// formatIOPS returns the given operations per second without decimals.
+func formatIOPS(iops float64) string {
+	return strconv.FormatFloat(iops, 'f', 0, 64)
+}
+
+// formatBandwidth returns the given bandwidth in bytes per second in a human readable form.
+func formatBandwidth(bandwidth int64) string {
+	return units.GetByteSizeStringIEC(bandwidth, 1) + "/s"
+}
+
+// formatLatency returns the given latency in nanoseconds as a duration.
+func formatLatency(latency int64) string {
+	return time.Duration(latency).Round(time.Microsecond).String()
+}
+
+// BenchmarkComparison is the comparison of a benchmark result with the recorded baseline.
+type BenchmarkComparison struct {
+	Member        string          `json:"member"         yaml:"member"`
+	Pool          string          `json:"pool"           yaml:"pool"`
+	Test          string          `json:"test"           yaml:"test"`
+	Baseline      types.Benchmark `json:"baseline"       yaml:"baseline"`
+	Current       types.Benchmark `json:"current"        yaml:"current"`
+	IOPSChange    float64         `json:"iops_change"    yaml:"iops_change"`
+	LatencyChange float64         `json:"latency_change" yaml:"latency_change"`
+	Regressed     bool            `json:"regressed"      yaml:"regressed"`
+}
+
+// percentChange returns the change from the baseline to the current value in percent.
+func percentChange(baseline float64, current float64) float64 {
+	if baseline == 0 {
+		return 0
+	}
+
+	return (current - baseline) / baseline * 100
+}
+
+// compareBenchmarks compares the current benchmark results with the recorded baseline.
+// A result regressed if its IOPS dropped, or its latency grew, by more than the threshold in percent.
+// Results without a baseline are skipped.
+func compareBenchmarks(baseline []types.Benchmark, current []types.Benchmark, threshold float64) []BenchmarkComparison {
+	comparisons := []BenchmarkComparison{}
+	for _, result := range current {
+		idx := slices.IndexFunc(baseline, func(b types.Benchmark) bool {
+			return b.Member == result.Member && b.Pool == result.Pool && b.Test == result.Test
+		})
+
+		if idx < 0 {
+			continue
+		}
+
+		comparison := BenchmarkComparison{
+			Member:        result.Member,
+			Pool:          result.Pool,
+			Test:          result.Test,
+			Baseline:      baseline[idx],
+			Current:       result,
+			IOPSChange:    percentChange(baseline[idx].IOPS, result.IOPS),
+			LatencyChange: percentChange(float64(baseline[idx].Latency), float64(result.Latency)),
+		}
+
+		comparison.Regressed = comparison.IOPSChange < -threshold || comparison.LatencyChange > threshold
+		comparisons = append(comparisons, comparison)
+	}
+
+	return comparisons
+}
+
+// baselinePools returns the benchmarked storage pools of each cluster member in the given baseline.
+func baselinePools(baseline []types.Benchmark) map[string][]string {
+	pools := map[string][]string{}
+	for _, b := range baseline {
+		if !slices.Contains(pools[b.Member], b.Pool) {
+			pools[b.Member] = append(pools[b.Member], b.Pool)
+		}
+	}
+
+	return pools
+}
+
+// runBenchmarks benchmarks the given storage pools of each cluster member, one member at a time so the members don't compete for the shared storage.
+// An empty list of pools benchmarks all supported storage pools of the member.
+func runBenchmarks(ctx context.Context, client *microClient.Client, members map[string][]string, runtime time.Duration, baseline bool) ([]types.Benchmark, error) {
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	benchmarks := []types.Benchmark{}
+	for _, name := range names {
+		// Report progress on stderr, so it doesn't mix with machine readable output.
+		fmt.Fprintf(os.Stderr, "Benchmarking storage pools on %q ...\n", name)
+
+		args := types.BenchmarksPost{Pools: members[name], Runtime: int64(runtime.Seconds()), Baseline: baseline}
+		results, err := cloudClient.RunBenchmarks(ctx, client.UseTarget(name), args)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to benchmark %q: %w", name, err)
+		}
+
+		benchmarks = append(benchmarks, results...)
+	}
+
+	return benchmarks, nil
+}
+
+// benchmarkPools returns the storage pools set up on the given system that can be benchmarked.
+func benchmarkPools(system InitSystem, names service.ResourceNames) []string {
+	pools := []string{}
+	for _, pool := range memberStoragePools(system, names) {
+		if (pool == names.LocalPool || pool == names.RemotePool) && !slices.Contains(pools, pool) {
+			pools = append(pools, pool)
+		}
+	}
+
+	return pools
+}
+
+// askBenchmark asks whether to record a storage performance baseline once the storage pools are set up.
+func (c *initConfig) askBenchmark(sh *service.Handler) error {
+	if !c.bootstrap {
+		return nil
+	}
+
+	hasPools := false
+	for _, system := range c.systems {
+		if len(benchmarkPools(system, sh.Services[types.LXD].(*service.LXDService).ResourceNames())) > 0 {
+			hasPools = true
+			break
+		}
+	}
+
+	if !hasPools {
+		return nil
+	}
+
+	var err error
+	c.benchmark, err = c.asker.AskBool("Would you like to record a storage performance baseline? This runs short fio tests on the storage pools of each system", false)
+
+	return err
+}
+
+// setupBenchmark records the storage performance baseline of the set up systems if requested.
+// Failures are only reported as warnings as the cluster is already set up at this point.
+func (c *initConfig) setupBenchmark(s *service.Handler) {
+	if !c.benchmark {
+		return
+	}
+
+	members := map[string][]string{}
+	for name, system := range c.systems {
+		pools := benchmarkPools(system, s.Services[types.LXD].(*service.LXDService).ResourceNames())
+		if len(pools) > 0 {
+			members[name] = pools
+		}
+	}
+
+	client, err := s.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		tui.PrintWarning(fmt.Sprintf("Failed to record the storage performance baseline: %v", err))
+		return
+	}
+
+	benchmarks, err := runBenchmarks(context.Background(), client, members, api.DefaultBenchmarkRuntime, true)
+	if err != nil {
+		tui.PrintWarning(fmt.Sprintf("Failed to record the storage performance baseline: %v", err))
+		return
+	}
+
+	fmt.Println(tui.SummarizeResult("Recorded the storage performance baseline of %d systems. Run \"microcloud bench compare\" to check for regressions", len(baselinePools(benchmarks))))
+}
+
+type cmdBench struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to manage the storage performance baseline.
+func (c *cmdBench) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Manage the storage performance baseline",
+		Long: `Manage the storage performance baseline
+
+The baseline holds the results of short fio tests on the storage pools of each cluster member.
+It is stored in the MicroCloud database, so later runs can be compared against it to spot regressions.`,
+		RunE: func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdRun = cmdBenchRun{common: c.common}
+	cmd.AddCommand(cmdRun.command())
+
+	var cmdShow = cmdBenchShow{common: c.common}
+	cmd.AddCommand(cmdShow.command())
+
+	var cmdCompare = cmdBenchCompare{common: c.common}
+	cmd.AddCommand(cmdCompare.command())
+
+	return cmd
+}
+
+type cmdBenchRun struct {
+	common *CmdControl
+
+	flagTarget  string
+	flagPools   []string
+	flagRuntime time.Duration
+	flagForce   bool
+}
+
+// command returns the subcommand to record a new storage performance baseline.
+func (c *cmdBenchRun) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Record a new storage performance baseline",
+		Long: `Record a new storage performance baseline
+
+The storage pools of each cluster member are benchmarked in turn, replacing their recorded baseline.
+By default all ZFS and Ceph storage pools are benchmarked.`,
+		RunE: c.run,
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Cluster member to benchmark")
+	cmd.Flags().StringSliceVar(&c.flagPools, "pool", nil, "Storage pool to benchmark, can be repeated"+"``")
+	cmd.Flags().DurationVar(&c.flagRuntime, "runtime", api.DefaultBenchmarkRuntime, "Duration of each fio test"+"``")
+	addMaintenanceForceFlag(cmd, &c.flagForce)
+
+	return cmd
+}
+
+// run runs the subcommand to record a new storage performance baseline.
+func (c *cmdBenchRun) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	err = checkMaintenanceWindows(cmd.Context(), client, c.flagForce)
+	if err != nil {
+		return err
+	}
+
+	members := map[string][]string{}
+	if c.flagTarget != "" {
+		members[c.flagTarget] = c.flagPools
+	} else {
+		clusterMembers, err := client.GetClusterMembers(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+		}
+
+		for _, member := range clusterMembers {
+			members[member.Name] = c.flagPools
+		}
+	}
+
+	benchmarks, err := runBenchmarks(cmd.Context(), client, members, c.flagRuntime, true)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(benchmarks))
+	for _, b := range benchmarks {
+		rows = append(rows, benchmarkRow(b))
+	}
+
+	fmt.Println(tui.NewTable(benchmarkHeader, rows))
+
+	return nil
+}
+
+type cmdBenchShow struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to show the recorded storage performance baseline.
+func (c *cmdBenchShow) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the recorded storage performance baseline",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to show the recorded storage performance baseline.
+func (c *cmdBenchShow) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	baseline, err := cloudClient.GetBenchmarks(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(baseline))
+	for _, b := range baseline {
+		rows = append(rows, append(benchmarkRow(b), b.CreatedAt.Local().Format(time.DateTime)))
+	}
+
+	table, err := tui.FormatData(c.flagFormat, append(slices.Clone(benchmarkHeader), "RECORDED"), rows, baseline)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+type cmdBenchCompare struct {
+	common *CmdControl
+
+	flagTarget    string
+	flagRuntime   time.Duration
+	flagThreshold float64
+	flagFormat    string
+}
+
+// command returns the subcommand to compare the current storage performance with the recorded baseline.
+func (c *cmdBenchCompare) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare the current storage performance with the recorded baseline",
+		Long: `Compare the current storage performance with the recorded baseline
+
+The storage pools in the baseline are benchmarked again, without replacing the baseline.
+A result regressed if its IOPS dropped, or its latency grew, by more than the threshold.
+The command fails if any result regressed.`,
+		RunE: c.run,
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Cluster member to benchmark")
+	cmd.Flags().DurationVar(&c.flagRuntime, "runtime", api.DefaultBenchmarkRuntime, "Duration of each fio test"+"``")
+	cmd.Flags().Float64Var(&c.flagThreshold, "threshold", defaultBenchmarkThreshold, "Change in percent beyond which a result is a regression"+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to compare the current storage performance with the recorded baseline.
+func (c *cmdBenchCompare) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	if c.flagThreshold < 0 {
+		return withExitCode(ExitCodeUsage, errors.New("The threshold must not be negative"))
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	baseline, err := cloudClient.GetBenchmarks(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	members := baselinePools(baseline)
+	if c.flagTarget != "" {
+		pools, ok := members[c.flagTarget]
+		if !ok {
+			return fmt.Errorf("No storage performance baseline recorded for %q", c.flagTarget)
+		}
+
+		members = map[string][]string{c.flagTarget: pools}
+	}
+
+	if len(members) == 0 {
+		return errors.New("No storage performance baseline recorded, run \"microcloud bench run\" first")
+	}
+
+	current, err := runBenchmarks(cmd.Context(), client, members, c.flagRuntime, false)
+	if err != nil {
+		return err
+	}
+
+	comparisons := compareBenchmarks(baseline, current, c.flagThreshold)
+
+	regressions := 0
+	rows := make([][]string, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		status := "OK"
+		if comparison.Regressed {
+			status = "REGRESSED"
+			regressions++
+		}
+
+		rows = append(rows, []string{
+			comparison.Member,
+			comparison.Pool,
+			comparison.Test,
+			formatIOPS(comparison.Baseline.IOPS),
+			formatIOPS(comparison.Current.IOPS),
+			fmt.Sprintf("%+.1f%%", comparison.IOPSChange),
+			formatLatency(comparison.Baseline.Latency),
+			formatLatency(comparison.Current.Latency),
+			fmt.Sprintf("%+.1f%%", comparison.LatencyChange),
+			status,
+		})
+	}
+
+	header := []string{"MEMBER", "POOL", "TEST", "BASELINE IOPS", "IOPS", "IOPS CHANGE", "BASELINE LATENCY", "LATENCY", "LATENCY CHANGE", "STATUS"}
+	table, err := tui.FormatData(c.flagFormat, header, rows, comparisons)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	if regressions > 0 {
+		return fmt.Errorf("%d benchmark results regressed by more than %g%%", regressions, c.flagThreshold)
+	}
+
+	return nil
+}
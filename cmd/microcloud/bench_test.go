@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type benchSuite struct {
+	suite.Suite
+}
+
+func TestBenchSuite(t *testing.T) {
+	suite.Run(t, new(benchSuite))
+}
+
+func (s *benchSuite) Test_compareBenchmarks() {
+	baseline := []types.Benchmark{
+		{Member: "n1", Pool: "local", Test: "randread", IOPS: 1000, Latency: 1000},
+		{Member: "n1", Pool: "local", Test: "randwrite", IOPS: 1000, Latency: 1000},
+		{Member: "n1", Pool: "remote", Test: "randread", IOPS: 1000, Latency: 1000},
+		{Member: "n2", Pool: "remote", Test: "randread", IOPS: 0, Latency: 0},
+	}
+
+	current := []types.Benchmark{
+		{Member: "n1", Pool: "local", Test: "randread", IOPS: 900, Latency: 1100},
+		{Member: "n1", Pool: "local", Test: "randwrite", IOPS: 700, Latency: 1000},
+		{Member: "n1", Pool: "remote", Test: "randread", IOPS: 1000, Latency: 1500},
+		{Member: "n2", Pool: "remote", Test: "randread", IOPS: 500, Latency: 500},
+		{Member: "n3", Pool: "local", Test: "randread", IOPS: 500, Latency: 500},
+	}
+
+	comparisons := compareBenchmarks(baseline, current, 20)
+	s.Len(comparisons, 4)
+
+	s.InDelta(-10, comparisons[0].IOPSChange, 0.001)
+	s.InDelta(10, comparisons[0].LatencyChange, 0.001)
+	s.False(comparisons[0].Regressed)
+
+	s.InDelta(-30, comparisons[1].IOPSChange, 0.001)
+	s.True(comparisons[1].Regressed)
+
+	s.InDelta(50, comparisons[2].LatencyChange, 0.001)
+	s.True(comparisons[2].Regressed)
+
+	// Without a baseline value there is nothing to regress from.
+	s.Equal(float64(0), comparisons[3].IOPSChange)
+	s.False(comparisons[3].Regressed)
+}
+
+func (s *benchSuite) Test_baselinePools() {
+	baseline := []types.Benchmark{
+		{Member: "n1", Pool: "local", Test: "randread"},
+		{Member: "n1", Pool: "local", Test: "randwrite"},
+		{Member: "n1", Pool: "remote", Test: "randread"},
+		{Member: "n2", Pool: "remote", Test: "randread"},
+	}
+
+	s.Equal(map[string][]string{"n1": {"local", "remote"}, "n2": {"remote"}}, baselinePools(baseline))
+}
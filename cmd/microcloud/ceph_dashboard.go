@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"gopkg.in/yaml.v2"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// cephCommand is the Ceph CLI shipped by the MicroCeph snap.
+// The dashboard and metrics are managed by Ceph manager modules, which are not exposed by the MicroCeph API.
+const cephCommand = "microceph.ceph"
+
+// CephDashboardUser is the name of the Ceph dashboard administrator created by MicroCloud.
+const CephDashboardUser = "admin"
+
+// cephDashboardTimeout is the time to wait for the Ceph manager to serve the dashboard and metrics after enabling them.
+const cephDashboardTimeout = 2 * time.Minute
+
+// runCeph runs the Ceph CLI with the given arguments and returns its output.
+var runCeph = func(ctx context.Context, args ...string) (string, error) {
+	return shared.RunCommandContext(ctx, cephCommand, args...)
+}
+
+// CephDashboard holds the access details of the Ceph dashboard and metrics.
+type CephDashboard struct {
+	DashboardURL  string `yaml:"dashboard_url"`
+	PrometheusURL string `yaml:"prometheus_url"`
+	User          string `yaml:"user"`
+	Password      string `yaml:"password"`
+}
+
+// cephDashboardPath returns the file storing the access details of the Ceph dashboard, next to the CLI configuration.
+func cephDashboardPath() (string, error) {
+	path, err := cliConfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(path), "ceph-dashboard.yaml"), nil
+}
+
+// enableCephDashboard turns on the Ceph dashboard and Prometheus metrics of MicroCeph, and returns their access details.
+func enableCephDashboard(ctx context.Context) (*CephDashboard, error) {
+	for _, module := range []string{"prometheus", "dashboard"} {
+		_, err := runCeph(ctx, "mgr", "module", "enable", module)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to enable the Ceph %s module: %w", module, err)
+		}
+	}
+
+	_, err := runCeph(ctx, "dashboard", "create-self-signed-cert")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the Ceph dashboard certificate: %w", err)
+	}
+
+	password, err := service.GeneratePassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	// The Ceph CLI only reads the password from a file.
+	passwordFile, err := os.CreateTemp("", "microcloud-ceph-dashboard-")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the Ceph dashboard password file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(passwordFile.Name()) }()
+
+	_, err = passwordFile.WriteString(password)
+	_ = passwordFile.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to write the Ceph dashboard password file: %w", err)
+	}
+
+	_, err = runCeph(ctx, "dashboard", "ac-user-create", "--force-password", "-i", passwordFile.Name(), CephDashboardUser, "administrator")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the Ceph dashboard user: %w", err)
+	}
+
+	dashboard := &CephDashboard{User: CephDashboardUser, Password: password}
+
+	// The manager only reports the URLs once the modules are serving.
+	ctx, cancel := context.WithTimeout(ctx, cephDashboardTimeout)
+	defer cancel()
+	for {
+		out, err := runCeph(ctx, "mgr", "services", "--format", "json")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the Ceph manager services: %w", err)
+		}
+
+		services := map[string]string{}
+		err = json.Unmarshal([]byte(out), &services)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the Ceph manager services: %w", err)
+		}
+
+		dashboard.DashboardURL = services["dashboard"]
+		dashboard.PrometheusURL = services["prometheus"]
+		if dashboard.DashboardURL != "" && dashboard.PrometheusURL != "" {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("Timed out waiting for the Ceph dashboard and metrics to be served")
+		case <-time.After(time.Second):
+		}
+	}
+
+	return dashboard, nil
+}
+
+// setupCephDashboard enables the Ceph dashboard and metrics if requested, then prints and stores the access details.
+// Failures are only reported as warnings as the cluster is already set up at this point.
+func (c *initConfig) setupCephDashboard(ctx context.Context) {
+	if !c.cephDashboard {
+		return
+	}
+
+	fmt.Println("Enabling the Ceph dashboard and metrics ...")
+
+	dashboard, err := enableCephDashboard(ctx)
+	if err != nil {
+		tui.PrintWarning(err.Error())
+		return
+	}
+
+	fmt.Println(tui.SummarizeResult("Ceph dashboard available at %s (user %q, password %q)", dashboard.DashboardURL, dashboard.User, dashboard.Password))
+	fmt.Println(tui.SummarizeResult("Ceph metrics available at %s", dashboard.PrometheusURL))
+
+	path, err := cephDashboardPath()
+	if err != nil {
+		tui.PrintWarning(err.Error())
+		return
+	}
+
+	data, err := yaml.Marshal(dashboard)
+	if err != nil {
+		tui.PrintWarning(fmt.Sprintf("Failed to encode the Ceph dashboard access details: %v", err))
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err == nil {
+		err = os.WriteFile(path, data, 0600)
+	}
+
+	if err != nil {
+		tui.PrintWarning(fmt.Sprintf("Failed to store the Ceph dashboard access details: %v", err))
+		return
+	}
+
+	fmt.Println(tui.SummarizeResult("Stored the Ceph dashboard access details in %s", path))
+}
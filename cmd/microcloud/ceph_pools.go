@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+// cephCompressionModes are the BlueStore compression modes supported by Ceph OSD pools.
+var cephCompressionModes = []string{"none", "passive", "aggressive", "force"}
+
+// cephCompressionAlgorithms are the BlueStore compression algorithms supported by Ceph OSD pools.
+var cephCompressionAlgorithms = []string{"snappy", "zlib", "zstd", "lz4"}
+
+// rbdFeatures are the RBD image features supported by LXD.
+var rbdFeatures = []string{"layering", "striping", "exclusive-lock", "object-map", "fast-diff", "deep-flatten", "journaling"}
+
+// CephPoolOptions represents the advanced options of the Ceph OSD pools created by MicroCloud in the preseed yaml.
+type CephPoolOptions struct {
+	CompressionMode      string  `yaml:"compression_mode"`
+	CompressionAlgorithm string  `yaml:"compression_algorithm"`
+	TargetSizeRatio      float64 `yaml:"target_size_ratio"`
+
+	// RBDFeatures is the comma separated list of features of the RBD images of the remote storage pools.
+	RBDFeatures string `yaml:"rbd_features"`
+}
+
+// validate validates the Ceph pool options.
+func (o CephPoolOptions) validate() error {
+	err := validate.Optional(validate.IsOneOf(cephCompressionModes...))(o.CompressionMode)
+	if err != nil {
+		return fmt.Errorf("Invalid Ceph compression mode: %w", err)
+	}
+
+	err = validate.Optional(validate.IsOneOf(cephCompressionAlgorithms...))(o.CompressionAlgorithm)
+	if err != nil {
+		return fmt.Errorf("Invalid Ceph compression algorithm: %w", err)
+	}
+
+	if o.CompressionAlgorithm != "" && (o.CompressionMode == "" || o.CompressionMode == "none") {
+		return errors.New("A Ceph compression algorithm requires a compression mode")
+	}
+
+	if o.TargetSizeRatio < 0 {
+		return errors.New("Ceph target size ratio cannot be negative")
+	}
+
+	err = validate.Optional(validate.IsListOf(validate.IsOneOf(rbdFeatures...)))(o.RBDFeatures)
+	if err != nil {
+		return fmt.Errorf("Invalid RBD features: %w", err)
+	}
+
+	return nil
+}
+
+// osdPoolSettings returns the settings to apply to each OSD pool created by MicroCloud, keyed by Ceph pool variable.
+func (o CephPoolOptions) osdPoolSettings() map[string]string {
+	settings := nonEmptyConfig(map[string]string{
+		"compression_mode":      o.CompressionMode,
+		"compression_algorithm": o.CompressionAlgorithm,
+	})
+
+	if o.TargetSizeRatio > 0 {
+		settings["target_size_ratio"] = strconv.FormatFloat(o.TargetSizeRatio, 'f', -1, 64)
+	}
+
+	return settings
+}
+
+// storagePool returns the given storage pool with the RBD features applied, if it is a remote storage pool.
+func (o CephPoolOptions) storagePool(pool lxdAPI.StoragePoolsPost) lxdAPI.StoragePoolsPost {
+	if pool.Driver != "ceph" || o.RBDFeatures == "" {
+		return pool
+	}
+
+	config := make(map[string]string, len(pool.Config)+1)
+	for key, value := range pool.Config {
+		config[key] = value
+	}
+
+	config["ceph.rbd.features"] = o.RBDFeatures
+	pool.Config = config
+
+	return pool
+}
+
+// cephOSDPools returns the OSD pools backing the given Ceph storage pools.
+// The OSD pools of remote storage pools are taken from their pending configuration on the given targets,
+// and CephFS storage pools only report their data pool, which holds the bulk of their data.
+func cephOSDPools(pools []lxdAPI.StoragePoolsPost, targetPools []lxdAPI.StoragePoolsPost) []string {
+	osdPools := []string{}
+	for _, pool := range pools {
+		switch pool.Driver {
+		case "ceph":
+			for _, target := range targetPools {
+				if target.Name == pool.Name && target.Config["source"] != "" {
+					osdPools = append(osdPools, target.Config["source"])
+					break
+				}
+			}
+
+		case "cephfs":
+			if pool.Config["cephfs.data_pool"] != "" {
+				osdPools = append(osdPools, pool.Config["cephfs.data_pool"])
+			}
+		}
+	}
+
+	return osdPools
+}
+
+// setupCephPoolOptions applies the Ceph pool options to the OSD pools backing the given storage pools.
+func (c *initConfig) setupCephPoolOptions(ctx context.Context, pools []lxdAPI.StoragePoolsPost, targetPools []lxdAPI.StoragePoolsPost) error {
+	settings := c.cephPools.osdPoolSettings()
+	if len(settings) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+
+	// The compression mode is set before the algorithm it enables.
+	sort.Strings(keys)
+	slices.Reverse(keys)
+
+	for _, osdPool := range cephOSDPools(pools, targetPools) {
+		for _, key := range keys {
+			_, err := runCeph(ctx, "osd", "pool", "set", osdPool, key, settings[key])
+			if err != nil {
+				return fmt.Errorf("Failed to set %q of OSD pool %q: %w", key, osdPool, err)
+			}
+		}
+	}
+
+	return nil
+}
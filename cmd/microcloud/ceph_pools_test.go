@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type cephPoolsSuite struct {
+	suite.Suite
+}
+
+func TestCephPoolsSuite(t *testing.T) {
+	suite.Run(t, new(cephPoolsSuite))
+}
+
+func (s *cephPoolsSuite) Test_cephPoolOptionsValidate() {
+	cases := []struct {
+		desc    string
+		options CephPoolOptions
+		err     bool
+	}{
+		{desc: "No options", options: CephPoolOptions{}},
+		{desc: "Compression", options: CephPoolOptions{CompressionMode: "aggressive", CompressionAlgorithm: "zstd"}},
+		{desc: "Invalid compression mode", options: CephPoolOptions{CompressionMode: "always"}, err: true},
+		{desc: "Compression algorithm without mode", options: CephPoolOptions{CompressionAlgorithm: "lz4"}, err: true},
+		{desc: "Negative target size ratio", options: CephPoolOptions{TargetSizeRatio: -0.5}, err: true},
+		{desc: "RBD features", options: CephPoolOptions{RBDFeatures: "layering,exclusive-lock"}},
+		{desc: "Unknown RBD feature", options: CephPoolOptions{RBDFeatures: "layering,compression"}, err: true},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := c.options.validate()
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+		}
+	}
+}
+
+func (s *cephPoolsSuite) Test_cephPoolOptionsStoragePool() {
+	lxd := &service.LXDService{}
+	lxd.SetResourceNames(service.DefaultResourceNames())
+	options := CephPoolOptions{RBDFeatures: "layering"}
+
+	pool := options.storagePool(lxd.DefaultCephStoragePool())
+	s.Equal("layering", pool.Config["ceph.rbd.features"])
+	s.Equal("layering,striping,exclusive-lock,object-map,fast-diff,deep-flatten", lxd.DefaultCephStoragePool().Config["ceph.rbd.features"])
+
+	s.Equal(lxd.DefaultCephFSStoragePool(), options.storagePool(lxd.DefaultCephFSStoragePool()))
+	s.Equal(lxd.DefaultCephStoragePool(), CephPoolOptions{}.storagePool(lxd.DefaultCephStoragePool()))
+}
+
+func (s *cephPoolsSuite) Test_setupCephPoolOptions() {
+	commands := []string{}
+	defer func(run func(ctx context.Context, args ...string) (string, error)) { runCeph = run }(runCeph)
+	runCeph = func(ctx context.Context, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args, " "))
+		return "", nil
+	}
+
+	lxd := &service.LXDService{}
+	lxd.SetResourceNames(service.DefaultResourceNames())
+	pools := []lxdAPI.StoragePoolsPost{lxd.DefaultZFSStoragePool(), lxd.DefaultCephStoragePool(), lxd.DefaultCephFSStoragePool()}
+	targetPools := []lxdAPI.StoragePoolsPost{lxd.DefaultPendingCephStoragePool(), lxd.DefaultPendingCephFSStoragePool()}
+
+	cfg := initConfig{}
+	s.NoError(cfg.setupCephPoolOptions(context.Background(), pools, targetPools))
+	s.Empty(commands)
+
+	cfg.cephPools = CephPoolOptions{CompressionMode: "aggressive", CompressionAlgorithm: "zstd", TargetSizeRatio: 0.8}
+	s.NoError(cfg.setupCephPoolOptions(context.Background(), pools, targetPools))
+	s.Equal([]string{
+		"osd pool set lxd_remote target_size_ratio 0.8",
+		"osd pool set lxd_remote compression_mode aggressive",
+		"osd pool set lxd_remote compression_algorithm zstd",
+		"osd pool set lxd_cephfs_data target_size_ratio 0.8",
+		"osd pool set lxd_cephfs_data compression_mode aggressive",
+		"osd pool set lxd_cephfs_data compression_algorithm zstd",
+	}, commands)
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// cephTierNone keeps a disk out of the tiers, so its OSD keeps the device class detected by Ceph.
+const cephTierNone = "none"
+
+// diskDeviceClass returns the CRUSH device class Ceph detects for the OSD of the given disk.
+func diskDeviceClass(disk lxdAPI.ResourcesStorageDisk) string {
+	if disk.Type == "nvme" {
+		return "nvme"
+	}
+
+	if disk.RPM > 0 {
+		return "hdd"
+	}
+
+	return "ssd"
+}
+
+// defaultDiskTier returns the tier whose device class matches the one detected for the given disk, if any.
+func defaultDiskTier(tiers []service.CephTier, disk lxdAPI.ResourcesStorageDisk) string {
+	for _, tier := range tiers {
+		if tier.DeviceClass == diskDeviceClass(disk) {
+			return tier.Name
+		}
+	}
+
+	return cephTierNone
+}
+
+// askCephTierDefinitions asks for the performance tiers of the distributed storage.
+func (c *initConfig) askCephTierDefinitions(names service.ResourceNames) ([]service.CephTier, error) {
+	split, err := c.asker.AskBool("Would you like to split the distributed storage into performance tiers by device class?", false)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers := []service.CephTier{}
+	if !split {
+		return tiers, nil
+	}
+
+	usedNames := []string{names.LocalPool, names.RemotePool, names.RemoteFSPool}
+	for {
+		name, err := c.asker.AskString(fmt.Sprintf("What name would you like to use for the storage pool of tier %d? (empty to finish)", len(tiers)+1), "", validate.Optional(func(value string) error {
+			if slices.Contains(usedNames, value) {
+				return fmt.Errorf("Name %q is already used", value)
+			}
+
+			return service.ValidateStoragePoolName(value)
+		}))
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "" {
+			return tiers, nil
+		}
+
+		class, err := c.asker.AskString(fmt.Sprintf("Which device class should the disks of %q have? (e.g. nvme, ssd or hdd)", name), "", func(value string) error {
+			return service.ValidateCephTiers(append(slices.Clone(tiers), service.CephTier{Name: name, DeviceClass: value}), names)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		tiers = append(tiers, service.CephTier{Name: name, DeviceClass: class})
+		usedNames = append(usedNames, name)
+	}
+}
+
+// askCephTiers asks for the performance tiers of the distributed storage if they can be defined, then which tier each selected disk is part of.
+// Without questions, disks are part of the tier matching the device class Ceph detects for them.
+// Returns the tier of each selected disk, keyed by system and disk path.
+func (c *initConfig) askCephTiers(names service.ResourceNames, selectedDisks map[string][]string, defineTiers bool) (map[string]map[string]string, error) {
+	if len(selectedDisks) == 0 {
+		return nil, nil
+	}
+
+	if defineTiers && !c.autoSetup {
+		var err error
+		c.cephTiers, err = c.askCephTierDefinitions(names)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.cephTiers) == 0 {
+		return nil, nil
+	}
+
+	options := []string{}
+	for _, tier := range c.cephTiers {
+		options = append(options, tier.Name)
+	}
+
+	options = append(options, cephTierNone)
+
+	targets := make([]string, 0, len(selectedDisks))
+	for target := range selectedDisks {
+		targets = append(targets, target)
+	}
+
+	sort.Strings(targets)
+
+	diskTiers := map[string]map[string]string{}
+	for _, target := range targets {
+		paths := slices.Clone(selectedDisks[target])
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			tier := cephTierNone
+			for _, disk := range c.state[target].AvailableDisks {
+				if service.FormatDiskPath(disk) == path {
+					tier = defaultDiskTier(c.cephTiers, disk)
+					break
+				}
+			}
+
+			if !c.autoSetup {
+				var err error
+				tier, err = c.asker.AskString(fmt.Sprintf("Which tier should disk %s on %s be part of? (%s)", path, target, strings.Join(options, "/")), tier, validate.IsOneOf(options...))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if tier == cephTierNone {
+				continue
+			}
+
+			if diskTiers[target] == nil {
+				diskTiers[target] = map[string]string{}
+			}
+
+			diskTiers[target][path] = tier
+		}
+	}
+
+	return diskTiers, nil
+}
+
+// addCephTierPools sets up the storage pools of the tiers wherever the remote storage pool is set up, created or joined.
+func (c *initConfig) addCephTierPools(lxd *service.LXDService) {
+	if len(c.cephTiers) == 0 {
+		return
+	}
+
+	remotePool := lxd.ResourceNames().RemotePool
+	isRemotePool := func(pool lxdAPI.StoragePoolsPost) bool { return pool.Name == remotePool }
+	isRemoteJoinConfig := func(config lxdAPI.ClusterMemberConfigKey) bool {
+		return config.Entity == "storage-pool" && config.Name == remotePool && config.Key == "source"
+	}
+
+	for name, system := range c.systems {
+		hasTarget := slices.ContainsFunc(system.TargetStoragePools, isRemotePool)
+		hasPool := slices.ContainsFunc(system.StoragePools, isRemotePool)
+		hasJoinConfig := slices.ContainsFunc(system.JoinConfig, isRemoteJoinConfig)
+		for _, tier := range c.cephTiers {
+			if hasTarget {
+				system.TargetStoragePools = append(system.TargetStoragePools, lxd.PendingCephTierStoragePool(tier))
+			}
+
+			if hasPool {
+				system.StoragePools = append(system.StoragePools, lxd.CephTierStoragePool(tier))
+			}
+
+			if hasJoinConfig {
+				system.JoinConfig = append(system.JoinConfig, lxd.CephTierStoragePoolJoinConfig(tier))
+			}
+		}
+
+		c.systems[name] = system
+	}
+}
+
+// assignCephTierDisks sets the device class of the OSDs of the disks assigned to a tier.
+func (c *initConfig) assignCephTierDisks(ctx context.Context, cephService *service.CephService) error {
+	if len(c.cephTiers) == 0 {
+		return nil
+	}
+
+	disks, err := cephService.GetDisks(ctx, "", nil)
+	if err != nil {
+		return err
+	}
+
+	for _, disk := range disks {
+		name := c.systems[disk.Location].CephDiskTiers[disk.Path]
+		if name == "" {
+			continue
+		}
+
+		tier, err := service.FindCephTier(c.cephTiers, name)
+		if err != nil {
+			return err
+		}
+
+		// Ceph only sets the device class of OSDs without one, so the detected class has to be removed first.
+		osd := fmt.Sprintf("osd.%d", disk.OSD)
+		_, err = runCeph(ctx, "osd", "crush", "rm-device-class", osd)
+		if err == nil {
+			_, err = runCeph(ctx, "osd", "crush", "set-device-class", tier.DeviceClass, osd)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to set the device class of %s on %q: %w", osd, disk.Location, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Assigned disk %s on %s to the %s tier", disk.Path, disk.Location, tier.Name))
+	}
+
+	return nil
+}
+
+// setupCephTierRules creates the CRUSH rule of each tier with a storage pool among the given ones,
+// and makes the OSD pool of the tier only place data on the OSDs of its device class.
+func (c *initConfig) setupCephTierRules(ctx context.Context, pools []lxdAPI.StoragePoolsPost) error {
+	for _, tier := range c.cephTiers {
+		if !slices.ContainsFunc(pools, func(pool lxdAPI.StoragePoolsPost) bool { return pool.Name == tier.Name }) {
+			continue
+		}
+
+		_, err := runCeph(ctx, "osd", "crush", "rule", "create-replicated", tier.OSDPool(), "default", "host", tier.DeviceClass)
+		if err != nil {
+			return fmt.Errorf("Failed to create the CRUSH rule of tier %q, ensure disks of device class %q are available: %w", tier.Name, tier.DeviceClass, err)
+		}
+
+		_, err = runCeph(ctx, "osd", "pool", "set", tier.OSDPool(), "crush_rule", tier.OSDPool())
+		if err != nil {
+			return fmt.Errorf("Failed to apply the CRUSH rule of tier %q: %w", tier.Name, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Placed storage pool %s on the %s disks", tier.Name, tier.DeviceClass))
+	}
+
+	return nil
+}
+
+// validateCephTierDisks checks the disks of the given systems are only assigned to known tiers.
+func validateCephTierDisks(tiers []service.CephTier, systems map[string]InitSystem) error {
+	for name, system := range systems {
+		for path, tier := range system.CephDiskTiers {
+			_, err := service.FindCephTier(tiers, tier)
+			if err != nil {
+				return fmt.Errorf("Invalid tier of disk %q on %q: %w", path, name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type cephTiersSuite struct {
+	suite.Suite
+}
+
+func TestCephTiersSuite(t *testing.T) {
+	suite.Run(t, new(cephTiersSuite))
+}
+
+func (s *cephTiersSuite) Test_defaultDiskTier() {
+	tiers := []service.CephTier{{Name: "remote-fast", DeviceClass: "nvme"}, {Name: "remote-capacity", DeviceClass: "hdd"}}
+
+	cases := []struct {
+		desc string
+		disk lxdAPI.ResourcesStorageDisk
+		tier string
+	}{
+		{desc: "NVMe disk", disk: lxdAPI.ResourcesStorageDisk{Type: "nvme"}, tier: "remote-fast"},
+		{desc: "Spinning disk", disk: lxdAPI.ResourcesStorageDisk{Type: "scsi", RPM: 7200}, tier: "remote-capacity"},
+		{desc: "Solid state disk without tier", disk: lxdAPI.ResourcesStorageDisk{Type: "sata"}, tier: cephTierNone},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.tier, defaultDiskTier(tiers, c.disk))
+	}
+}
+
+func (s *cephTiersSuite) Test_addCephTierPools() {
+	lxd := &service.LXDService{}
+	lxd.SetResourceNames(service.DefaultResourceNames())
+	tier := service.CephTier{Name: "remote-fast", DeviceClass: "nvme"}
+
+	cfg := initConfig{
+		cephTiers: []service.CephTier{tier},
+		systems: map[string]InitSystem{
+			"micro01": {
+				TargetStoragePools: []lxdAPI.StoragePoolsPost{lxd.DefaultPendingCephStoragePool()},
+				StoragePools:       []lxdAPI.StoragePoolsPost{lxd.DefaultCephStoragePool()},
+			},
+			"micro02": {JoinConfig: []lxdAPI.ClusterMemberConfigKey{lxd.DefaultCephStoragePoolJoinConfig()}},
+			"micro03": {TargetStoragePools: []lxdAPI.StoragePoolsPost{lxd.DefaultPendingZFSStoragePool(false, "/dev/sdb")}},
+		},
+	}
+
+	cfg.addCephTierPools(lxd)
+
+	s.Equal([]lxdAPI.StoragePoolsPost{lxd.DefaultPendingCephStoragePool(), lxd.PendingCephTierStoragePool(tier)}, cfg.systems["micro01"].TargetStoragePools)
+	s.Equal([]lxdAPI.StoragePoolsPost{lxd.DefaultCephStoragePool(), lxd.CephTierStoragePool(tier)}, cfg.systems["micro01"].StoragePools)
+	s.Equal([]lxdAPI.ClusterMemberConfigKey{lxd.DefaultCephStoragePoolJoinConfig(), lxd.CephTierStoragePoolJoinConfig(tier)}, cfg.systems["micro02"].JoinConfig)
+	s.Len(cfg.systems["micro03"].TargetStoragePools, 1)
+}
+
+func (s *cephTiersSuite) Test_setupCephTierRules() {
+	commands := []string{}
+	defer func(run func(ctx context.Context, args ...string) (string, error)) { runCeph = run }(runCeph)
+	runCeph = func(ctx context.Context, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args, " "))
+		return "", nil
+	}
+
+	cfg := initConfig{cephTiers: []service.CephTier{{Name: "remote-fast", DeviceClass: "nvme"}, {Name: "remote-capacity", DeviceClass: "hdd"}}}
+	err := cfg.setupCephTierRules(context.Background(), []lxdAPI.StoragePoolsPost{{Name: "remote"}, {Name: "remote-fast"}})
+	s.NoError(err)
+	s.Equal([]string{
+		"osd crush rule create-replicated lxd_tier_remote-fast default host nvme",
+		"osd pool set lxd_tier_remote-fast crush_rule lxd_tier_remote-fast",
+	}, commands)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+// cliEnvPrefix is the prefix of the environment variables overriding the defaults of command line flags.
+const cliEnvPrefix = "MICROCLOUD_"
+
+// cliConfig is the configuration of the MicroCloud CLI, persisted in the user's configuration directory.
+type cliConfig struct {
+	// Defaults holds the default values of command line flags, keyed by flag name.
+	Defaults map[string]string `yaml:"defaults,omitempty"`
+
+	// Tables holds the display preferences of the interactive tables, keyed by table identifier.
+	Tables map[string]tui.TablePreferences `yaml:"tables,omitempty"`
+
+	// Contexts holds the remote MicroClouds managed from this CLI, keyed by context name.
+	Contexts map[string]cliContext `yaml:"contexts,omitempty"`
+
+	// CurrentContext is the name of the context commands run against by default.
+	// If empty, commands run against the local MicroCloud.
+	CurrentContext string `yaml:"current_context,omitempty"`
+
+	// path is the location of the configuration file.
+	path string
+}
+
+// cliConfigPath returns the path of the MicroCloud CLI configuration file.
+func cliConfigPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("Failed to find the user configuration directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "microcloud", "config.yaml"), nil
+}
+
+// loadCLIConfig reads the MicroCloud CLI configuration file.
+// If the file doesn't exist, an empty configuration is returned.
+func loadCLIConfig() (*cliConfig, error) {
+	path, err := cliConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &cliConfig{path: path}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return config, nil
+		}
+
+		return nil, fmt.Errorf("Failed to read CLI configuration %q: %w", path, err)
+	}
+
+	err = yaml.Unmarshal(content, config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse CLI configuration %q: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// save writes the MicroCloud CLI configuration file.
+func (c *cliConfig) save() error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("Failed to encode CLI configuration: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create CLI configuration directory: %w", err)
+	}
+
+	err = os.WriteFile(c.path, content, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write CLI configuration %q: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// TablePreferences returns the stored preferences of the table with the given identifier.
+func (c *cliConfig) TablePreferences(id string) tui.TablePreferences {
+	return c.Tables[id]
+}
+
+// SetTablePreferences stores the preferences of the table with the given identifier.
+func (c *cliConfig) SetTablePreferences(id string, prefs tui.TablePreferences) error {
+	if c.Tables == nil {
+		c.Tables = map[string]tui.TablePreferences{}
+	}
+
+	c.Tables[id] = prefs
+
+	return c.save()
+}
+
+// flagEnvName returns the name of the environment variable overriding the default of the given flag.
+func flagEnvName(name string) string {
+	return cliEnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyDefaults sets the flags of the command which weren't given on the command line
+// from the MICROCLOUD_* environment variables or the defaults of the configuration file.
+// Flags take precedence over environment variables, which take precedence over the configuration file.
+func (c *cliConfig) applyDefaults(cmd *cobra.Command) error {
+	var err error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Name == "help" || flag.Name == "version" {
+			return
+		}
+
+		source := flagEnvName(flag.Name)
+		value, ok := os.LookupEnv(source)
+		if !ok {
+			source = c.path
+			value, ok = c.Defaults[flag.Name]
+		}
+
+		if !ok {
+			return
+		}
+
+		setErr := flag.Value.Set(value)
+		if setErr != nil {
+			err = withExitCode(ExitCodeUsage, fmt.Errorf("Invalid value %q for flag %q from %q: %w", value, flag.Name, source, setErr))
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+type cliConfigSuite struct {
+	suite.Suite
+}
+
+func TestCLIConfigSuite(t *testing.T) {
+	suite.Run(t, new(cliConfigSuite))
+}
+
+func (s *cliConfigSuite) Test_applyDefaults() {
+	cases := []struct {
+		desc      string
+		args      []string
+		env       map[string]string
+		defaults  map[string]string
+		format    string
+		timeout   int
+		expectErr bool
+	}{
+		{
+			desc:    "Built-in defaults",
+			format:  "table",
+			timeout: 0,
+		},
+		{
+			desc:     "Configuration file defaults",
+			defaults: map[string]string{"format": "json", "timeout": "10"},
+			format:   "json",
+			timeout:  10,
+		},
+		{
+			desc:     "Environment overrides configuration file",
+			env:      map[string]string{"MICROCLOUD_FORMAT": "yaml"},
+			defaults: map[string]string{"format": "json", "timeout": "10"},
+			format:   "yaml",
+			timeout:  10,
+		},
+		{
+			desc:     "Flags override environment and configuration file",
+			args:     []string{"--format", "csv", "--timeout", "5"},
+			env:      map[string]string{"MICROCLOUD_FORMAT": "yaml"},
+			defaults: map[string]string{"timeout": "10"},
+			format:   "csv",
+			timeout:  5,
+		},
+		{
+			desc:      "Invalid value",
+			env:       map[string]string{"MICROCLOUD_TIMEOUT": "ten"},
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		for key, value := range c.env {
+			s.T().Setenv(key, value)
+		}
+
+		var format string
+		var timeout int
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&format, "format", "table", "")
+		cmd.Flags().IntVar(&timeout, "timeout", 0, "")
+		s.NoError(cmd.ParseFlags(c.args))
+
+		config := &cliConfig{Defaults: c.defaults}
+		err := config.applyDefaults(cmd)
+		if c.expectErr {
+			s.Error(err)
+			s.Equal(ExitCodeUsage, exitCode(err))
+		} else {
+			s.NoError(err)
+			s.Equal(c.format, format)
+			s.Equal(c.timeout, timeout)
+		}
+
+		for key := range c.env {
+			s.NoError(os.Unsetenv(key))
+		}
+	}
+}
+
+func (s *cliConfigSuite) Test_tablePreferences() {
+	home := s.T().TempDir()
+	s.T().Setenv("HOME", home)
+	s.T().Setenv("XDG_CONFIG_HOME", "")
+
+	config, err := loadCLIConfig()
+	s.Require().NoError(err)
+	s.Equal(tui.TablePreferences{}, config.TablePreferences("name,size"))
+
+	prefs := tui.TablePreferences{SortColumn: "SIZE", SortDescending: true, HiddenColumns: []string{"NAME"}}
+	s.Require().NoError(config.SetTablePreferences("name,size", prefs))
+	s.FileExists(filepath.Join(home, ".config", "microcloud", "config.yaml"))
+
+	// The preferences survive reloading the configuration file.
+	config, err = loadCLIConfig()
+	s.Require().NoError(err)
+	s.Equal(prefs, config.TablePreferences("name,size"))
+	s.Equal(tui.TablePreferences{}, config.TablePreferences("name,address"))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// clockSkewLimit returns the clock skew to a system above which it can't be set up.
+// When adding systems, the limit can be configured on the existing cluster.
+func (c *initConfig) clockSkewLimit(ctx context.Context, sh *service.Handler) (time.Duration, error) {
+	if c.bootstrap {
+		return service.DefaultClockSkewLimit, nil
+	}
+
+	microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return 0, err
+	}
+
+	config, err := cloudClient.GetConfig(ctx, microClient)
+	if err != nil {
+		return 0, err
+	}
+
+	if config[types.ConfigClockSkewLimit] == "" {
+		return service.DefaultClockSkewLimit, nil
+	}
+
+	return time.ParseDuration(config[types.ConfigClockSkewLimit])
+}
+
+// checkClockSkew measures the clock skew between the local system and the other systems being set up, and fails if any exceeds the limit.
+// Clocks which aren't synchronized to a time source only raise a warning.
+func (c *initConfig) checkClockSkew(ctx context.Context, sh *service.Handler) error {
+	limit, err := c.clockSkewLimit(ctx, sh)
+	if err != nil {
+		return fmt.Errorf("Failed to get the clock skew limit: %w", err)
+	}
+
+	if !service.LocalClock().Synchronized {
+		tui.PrintWarning(fmt.Sprintf("Clock of %q is not synchronized to a time source", c.name))
+	}
+
+	cloud := sh.Services[types.MicroCloud].(*service.CloudService)
+	names := make([]string, 0, len(c.systems))
+	for name := range c.systems {
+		if name != c.name {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	for _, name := range names {
+		system := c.systems[name]
+		remoteClient, err := cloud.RemoteClient(system.ServerInfo.Certificate, util.CanonicalNetworkAddress(system.ServerInfo.Address, service.CloudPort))
+		if err != nil {
+			return err
+		}
+
+		sent := time.Now()
+		clock, err := cloudClient.GetClock(ctx, remoteClient)
+		if err != nil {
+			// Systems running an older MicroCloud can't report their clock.
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				tui.PrintWarning(fmt.Sprintf("Unable to check the clock of %q", name))
+				continue
+			}
+
+			return err
+		}
+
+		skew := service.ClockSkew(sent, time.Now(), clock.Time)
+		if skew.Abs() > limit {
+			return withExitCode(ExitCodeValidation, fmt.Errorf("Clock of %q is off by %s, above the limit of %s. Synchronize the clocks of the systems before setting up MicroCloud", name, skew.Round(time.Millisecond), limit))
+		}
+
+		if !clock.Synchronized {
+			tui.PrintWarning(fmt.Sprintf("Clock of %q is not synchronized to a time source", name))
+		}
+	}
+
+	return nil
+}
@@ -3,12 +3,15 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/canonical/lxd/shared"
 	cli "github.com/canonical/lxd/shared/cmd"
@@ -20,7 +23,10 @@ import (
 	"golang.org/x/sys/unix"
 	"gopkg.in/yaml.v2"
 
+	cloudTypes "github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
 	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
 )
 
 const recoveryConfirmation = `You should only run this command if:
@@ -65,6 +71,15 @@ func (c *cmdClusterMembers) command() *cobra.Command {
 	var cmdEdit = cmdClusterRecover{common: c.common}
 	cmd.AddCommand(cmdEdit.command())
 
+	var cmdRole = cmdClusterMemberRole{common: c.common}
+	cmd.AddCommand(cmdRole.command())
+
+	var cmdRejoin = cmdClusterMemberRejoin{common: c.common}
+	cmd.AddCommand(cmdRejoin.command())
+
+	var cmdRetryJoin = cmdClusterMemberRetryJoin{common: c.common}
+	cmd.AddCommand(cmdRetryJoin.command())
+
 	return cmd
 }
 
@@ -76,8 +91,13 @@ func (c *cmdClusterMembers) run(cmd *cobra.Command, args []string) error {
 type cmdClusterMembersList struct {
 	common *CmdControl
 
-	flagFormat string
-	flagLocal  bool
+	flagFormat  string
+	flagLocal   bool
+	flagMember  []string
+	flagService string
+	flagStatus  string
+	flagLimit   int
+	flagOffset  int
 }
 
 // command returns the subcommand to list cluster members.
@@ -86,10 +106,17 @@ func (c *cmdClusterMembersList) command() *cobra.Command {
 		Use:   "list <address>",
 		Short: "List cluster members locally, or remotely if an address is specified",
 		RunE:  c.run,
+
+		Annotations: map[string]string{contextAnnotation: "true"},
 	}
 
 	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
 	cmd.Flags().BoolVarP(&c.flagLocal, "local", "l", false, "provide only the locally available cluster info (no database query)")
+	cmd.Flags().StringSliceVar(&c.flagMember, "member", nil, "Only list this cluster member, can be repeated"+"``")
+	cmd.Flags().StringVar(&c.flagService, "service", "", "Only list the cluster members this service is set up on"+"``")
+	cmd.Flags().StringVar(&c.flagStatus, "status", "", "Only list the cluster members with this status"+"``")
+	cmd.Flags().IntVar(&c.flagLimit, "limit", 0, "Maximum number of cluster members to list"+"``")
+	cmd.Flags().IntVar(&c.flagOffset, "offset", 0, "Number of matching cluster members to skip"+"``")
 
 	return cmd
 }
@@ -100,9 +127,20 @@ func (c *cmdClusterMembersList) run(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	}
 
+	if c.flagLocal && c.common.contextName() != "" {
+		return withExitCode(ExitCodeUsage, errors.New("The locally available cluster info can't be listed for remote contexts"))
+	}
+
+	if c.flagLocal && (len(c.flagMember) > 0 || c.flagService != "" || c.flagStatus != "" || c.flagLimit != 0 || c.flagOffset != 0) {
+		return withExitCode(ExitCodeUsage, errors.New("The locally available cluster info can't be filtered"))
+	}
+
+	if c.flagLimit < 0 || c.flagOffset < 0 {
+		return withExitCode(ExitCodeUsage, errors.New("The limit and offset must be zero or positive"))
+	}
+
 	// Get all state information.
-	options := microcluster.Args{StateDir: c.common.FlagMicroCloudDir}
-	m, err := microcluster.App(options)
+	m, err := c.common.app()
 	if err != nil {
 		return err
 	}
@@ -130,11 +168,20 @@ func (c *cmdClusterMembersList) run(cmd *cobra.Command, args []string) error {
 }
 
 func (c *cmdClusterMembersList) listClusterMembers(ctx context.Context, client *client.Client) error {
-	clusterMembers, err := client.GetClusterMembers(ctx)
+	filter := cloudTypes.ListFilter{
+		Members: c.flagMember,
+		Service: cloudTypes.ServiceType(c.flagService),
+		Status:  c.flagStatus,
+		Limit:   c.flagLimit,
+		Offset:  c.flagOffset,
+	}
+
+	clusterMembers, err := cloudClient.GetClusterMembers(ctx, client, filter)
 	if err != nil {
 		return err
 	}
 
+	roles := make([]string, 0, len(clusterMembers))
 	data := make([][]string, len(clusterMembers))
 	for i, clusterMember := range clusterMembers {
 		fingerprint, err := shared.CertFingerprintStr(clusterMember.Certificate.String())
@@ -142,10 +189,17 @@ func (c *cmdClusterMembersList) listClusterMembers(ctx context.Context, client *
 			continue
 		}
 
-		data[i] = []string{clusterMember.Name, clusterMember.Address.String(), clusterMember.Role, fingerprint, string(clusterMember.Status)}
+		heartbeat := "-"
+		if !clusterMember.LastHeartbeat.IsZero() {
+			heartbeat = clusterMember.LastHeartbeat.Format(time.RFC3339)
+		}
+
+		schema := fmt.Sprintf("%d/%d", clusterMember.SchemaInternalVersion, clusterMember.SchemaExternalVersion)
+		data[i] = []string{clusterMember.Name, clusterMember.Address.String(), clusterMember.Role, fingerprint, heartbeat, schema, string(clusterMember.Status)}
+		roles = append(roles, clusterMember.Role)
 	}
 
-	header := []string{"NAME", "ADDRESS", "ROLE", "FINGERPRINT", "STATUS"}
+	header := []string{"NAME", "ADDRESS", "ROLE", "FINGERPRINT", "LAST HEARTBEAT", "SCHEMA", "STATUS"}
 	sort.Sort(cli.SortColumnsNaturally(data))
 	table, err := tui.FormatData(c.flagFormat, header, data, clusterMembers)
 	if err != nil {
@@ -154,9 +208,41 @@ func (c *cmdClusterMembersList) listClusterMembers(ctx context.Context, client *
 
 	fmt.Println(table)
 
+	// Only append the role breakdown to human readable output.
+	if c.flagFormat == tui.TableFormatTable {
+		fmt.Println(roleBreakdown(roles))
+	}
+
 	return nil
 }
 
+// roleBreakdown returns a summary of how many cluster members have each dqlite role.
+func roleBreakdown(roles []string) string {
+	counts := map[string]int{}
+	for _, role := range roles {
+		counts[role]++
+	}
+
+	breakdown := []string{}
+	for _, role := range []string{"voter", "stand-by", "spare"} {
+		breakdown = append(breakdown, fmt.Sprintf("%s: %d", role, counts[role]))
+		delete(counts, role)
+	}
+
+	// Include any other roles reported by dqlite, such as PENDING.
+	others := make([]string, 0, len(counts))
+	for role := range counts {
+		others = append(others, role)
+	}
+
+	sort.Strings(others)
+	for _, role := range others {
+		breakdown = append(breakdown, fmt.Sprintf("%s: %d", role, counts[role]))
+	}
+
+	return "Roles: " + strings.Join(breakdown, ", ")
+}
+
 func (c *cmdClusterMembersList) listLocalClusterMembers(m *microcluster.MicroCluster) error {
 	members, err := m.GetDqliteClusterMembers()
 	if err != nil {
@@ -193,6 +279,8 @@ func (c *cmdClusterMemberRemove) command() *cobra.Command {
 		Use:   "remove <name>",
 		Short: "Remove the specified cluster member",
 		RunE:  c.run,
+
+		ValidArgsFunction: c.common.completeMemberNames,
 	}
 
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, "Forcibly remove the cluster member")
@@ -225,6 +313,116 @@ func (c *cmdClusterMemberRemove) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// lxdDatabaseClientRole is the LXD cluster member role that prevents a member from becoming a database voter.
+const lxdDatabaseClientRole = "database-client"
+
+type cmdClusterMemberRole struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to set the LXD database role preference of a cluster member.
+func (c *cmdClusterMemberRole) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lxd-role <name> <voter|client>",
+		Short: "Set whether the specified cluster member may act as an LXD database voter",
+		Long: `Set whether the specified cluster member may act as an LXD database voter
+
+A member with the "voter" preference is eligible to be promoted to an LXD database voter.
+A member with the "client" preference is given the LXD "database-client" role, which keeps it out of the set of LXD database voters, so that voters land on the remaining members.
+
+Only the LXD database is affected. The database roles of MicroCloud, MicroCeph and MicroOVN are still assigned automatically, and can't be set with this command.`,
+		RunE: c.run,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 1 {
+				return []string{"voter", "client"}, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return c.common.completeMemberNames(cmd, args, toComplete)
+		},
+	}
+
+	return cmd
+}
+
+// run runs the subcommand to set the LXD database role preference of a cluster member.
+func (c *cmdClusterMemberRole) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Help()
+	}
+
+	name := args[0]
+	preference := args[1]
+	if !slices.Contains([]string{"voter", "client"}, preference) {
+		return fmt.Errorf("Invalid role preference %q: Must be one of \"voter\" or \"client\"", preference)
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	s, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, cloudTypes.LXD)
+	if err != nil {
+		return err
+	}
+
+	lxdClient, err := s.Services[cloudTypes.LXD].(*service.LXDService).Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	member, etag, err := lxdClient.GetClusterMember(name)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD cluster member %q: %w", name, err)
+	}
+
+	newMember := member.Writable()
+	roles, changed := lxdRolesWithPreference(newMember.Roles, preference)
+	if !changed {
+		fmt.Printf("LXD cluster member %q already has the %q role preference\n", name, preference)
+		return nil
+	}
+
+	newMember.Roles = roles
+	err = lxdClient.UpdateClusterMember(name, newMember, etag)
+	if err != nil {
+		return fmt.Errorf("Failed to update roles of LXD cluster member %q: %w", name, err)
+	}
+
+	fmt.Printf("Set the %q role preference for LXD cluster member %q\n", preference, name)
+
+	return nil
+}
+
+// lxdRolesWithPreference returns the LXD cluster member roles with the given database role preference applied,
+// and whether they differ from the current roles.
+func lxdRolesWithPreference(roles []string, preference string) ([]string, bool) {
+	hasClientRole := slices.Contains(roles, lxdDatabaseClientRole)
+	if preference == "client" && !hasClientRole {
+		return append(slices.Clone(roles), lxdDatabaseClientRole), true
+	}
+
+	if preference == "voter" && hasClientRole {
+		return slices.DeleteFunc(slices.Clone(roles), func(role string) bool { return role == lxdDatabaseClientRole }), true
+	}
+
+	return roles, false
+}
+
 type cmdClusterRecover struct {
 	common *CmdControl
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type clusterMembersSuite struct {
+	suite.Suite
+}
+
+func TestClusterMembersSuite(t *testing.T) {
+	suite.Run(t, new(clusterMembersSuite))
+}
+
+func (s *clusterMembersSuite) Test_lxdRolesWithPreference() {
+	cases := []struct {
+		desc          string
+		roles         []string
+		preference    string
+		expectedRoles []string
+		changed       bool
+	}{
+		{
+			desc:          "Client preference adds the database-client role",
+			roles:         []string{"database"},
+			preference:    "client",
+			expectedRoles: []string{"database", "database-client"},
+			changed:       true,
+		},
+		{
+			desc:          "Client preference on a member without roles",
+			roles:         nil,
+			preference:    "client",
+			expectedRoles: []string{"database-client"},
+			changed:       true,
+		},
+		{
+			desc:          "Client preference is already set",
+			roles:         []string{"database-client", "event-hub"},
+			preference:    "client",
+			expectedRoles: []string{"database-client", "event-hub"},
+			changed:       false,
+		},
+		{
+			desc:          "Voter preference removes the database-client role",
+			roles:         []string{"event-hub", "database-client", "ovn-chassis"},
+			preference:    "voter",
+			expectedRoles: []string{"event-hub", "ovn-chassis"},
+			changed:       true,
+		},
+		{
+			desc:          "Voter preference is already set",
+			roles:         []string{"database-standby"},
+			preference:    "voter",
+			expectedRoles: []string{"database-standby"},
+			changed:       false,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		original := append([]string(nil), c.roles...)
+		roles, changed := lxdRolesWithPreference(c.roles, c.preference)
+		s.Equal(c.changed, changed)
+		s.Equal(c.expectedRoles, roles)
+
+		// The current roles are left untouched.
+		s.Equal(original, c.roles)
+	}
+}
+
+func (s *clusterMembersSuite) Test_roleBreakdown() {
+	s.Equal("Roles: voter: 0, stand-by: 0, spare: 0", roleBreakdown(nil))
+	s.Equal("Roles: voter: 3, stand-by: 1, spare: 2, PENDING: 1", roleBreakdown([]string{"voter", "spare", "PENDING", "voter", "stand-by", "spare", "voter"}))
+}
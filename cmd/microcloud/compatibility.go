@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// checkCompatibility compares the versions and API extensions of the services being set up on each system against the compatibility matrix of this MicroCloud.
+// Unsupported combinations fail the setup before any system joins, unless forced, in which case they only raise warnings.
+func (c *initConfig) checkCompatibility(sh *service.Handler) error {
+	systems := make(map[string]map[types.ServiceType]types.ServiceVersion, len(c.state))
+	unknown := []string{}
+	for name, state := range c.state {
+		// Systems running an older MicroCloud can't report their service versions.
+		if state.ServiceVersions == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		// Only the services being set up matter, other installed services are left alone.
+		systems[name] = make(map[types.ServiceType]types.ServiceVersion, len(sh.Services))
+		for serviceType := range sh.Services {
+			version, ok := state.ServiceVersions[serviceType]
+			if ok {
+				systems[name][serviceType] = version
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		tui.PrintWarning(fmt.Sprintf("Unable to check the service versions of %s", strings.Join(unknown, ", ")))
+	}
+
+	issues := service.CheckCompatibility(systems)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		if c.force {
+			tui.PrintWarning(issue.Message)
+		}
+
+		messages = append(messages, issue.Message)
+	}
+
+	if c.force {
+		return nil
+	}
+
+	return withExitCode(ExitCodeValidation, fmt.Errorf("%w. Use --force to set up the systems anyway", errors.New("Unsupported service versions or API extensions:\n  - "+strings.Join(messages, "\n  - "))))
+}
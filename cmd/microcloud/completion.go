@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// completionTimeout is the maximum time spent querying the services for shell completion suggestions.
+// Disks are not completed, as no flag selects them: they are picked from the interactive tables or with the disk filters of the preseed.
+// Completing disks from the LXD resources API is left to the request adding such a flag.
+const completionTimeout = 5 * time.Second
+
+// addableServices are the optional services which can be added to an existing MicroCloud, with their state directories.
+var addableServices = map[types.ServiceType]string{
+	types.MicroCeph: api.MicroCephDir,
+	types.MicroOVN:  api.MicroOVNDir,
+}
+
+// addTargetFlag adds the --target flag selecting the cluster member to run the command against, with completion of the member names.
+func (c *CmdControl) addTargetFlag(cmd *cobra.Command, target *string, usage string) {
+	cmd.Flags().StringVar(target, "target", "", usage+"``")
+
+	_ = cmd.RegisterFlagCompletionFunc("target", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return c.completeMemberNames(cmd, nil, toComplete)
+	})
+}
+
+// completeMemberNames suggests the names of the current MicroCloud cluster members as the first argument.
+func (c *CmdControl) completeMemberNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	m, err := c.app()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	client, err := m.LocalClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	members, err := client.GetClusterMembers(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		if strings.HasPrefix(member.Name, toComplete) {
+			names = append(names, member.Name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAddableServices suggests the installed optional services which are not yet set up on this system.
+func (c *CmdControl) completeAddableServices(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	installed := []types.ServiceType{}
+	for serviceType, stateDir := range addableServices {
+		name := strings.ToLower(string(serviceType))
+		if service.Exists(serviceType, stateDir) && !slices.Contains(args, name) && strings.HasPrefix(name, toComplete) {
+			installed = append(installed, serviceType)
+		}
+	}
+
+	if len(installed) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	s, err := service.NewHandler("", "", c.FlagMicroCloudDir, installed...)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	names := []string{}
+	for _, s := range s.Services {
+		initialized, err := s.IsInitialized(ctx)
+		if err != nil || initialized {
+			continue
+		}
+
+		names = append(names, strings.ToLower(string(s.Type())))
+	}
+
+	slices.Sort(names)
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
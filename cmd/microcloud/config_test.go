@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type configSuite struct {
+	suite.Suite
+}
+
+func TestConfigSuite(t *testing.T) {
+	suite.Run(t, new(configSuite))
+}
+
+func (s *configSuite) Test_parseConfigArgs() {
+	cases := []struct {
+		desc   string
+		args   []string
+		config map[string]string
+		err    bool
+	}{
+		{desc: "Key and value", args: []string{"upgrade.policy", "patch"}, config: map[string]string{"upgrade.policy": "patch"}},
+		{desc: "Key=value pairs", args: []string{"upgrade.policy=patch", "metrics.address=[::]:9100"}, config: map[string]string{"upgrade.policy": "patch", "metrics.address": "[::]:9100"}},
+		{desc: "Value containing =", args: []string{"webhook.urls=https://example.com/?a=b"}, config: map[string]string{"webhook.urls": "https://example.com/?a=b"}},
+		{desc: "Unknown key", args: []string{"unknown.key=value"}, err: true},
+		{desc: "Missing value", args: []string{"upgrade.policy"}, err: true},
+		{desc: "Empty value", args: []string{"upgrade.policy="}, err: true},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		config, err := parseConfigArgs(c.args)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.config, config)
+	}
+}
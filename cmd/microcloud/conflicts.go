@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/multicast"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+const (
+	// conflictRename renames the conflicting network out of the way, so MicroCloud can set up its own.
+	conflictRename = "rename"
+
+	// conflictReuse keeps the conflicting storage pool or network, and skips setting it up.
+	conflictReuse = "reuse"
+
+	// conflictAbort aborts the setup.
+	conflictAbort = "abort"
+)
+
+// conflictResolutions are the supported ways to resolve a conflicting storage pool or network.
+var conflictResolutions = []string{conflictRename, conflictReuse, conflictAbort}
+
+// systemConflict is a conflicting storage pool or network on a system.
+type systemConflict struct {
+	system string
+	service.Conflict
+}
+
+// systemConflicts returns the conflicting storage pools and networks of the systems, sorted by system and name.
+// Systems already clustered in LXD are skipped, as well as resources of services which aren't set up.
+func systemConflicts(sh *service.Handler, states map[string]service.SystemInformation) []systemConflict {
+	names := sh.Services[types.LXD].(*service.LXDService).ResourceNames()
+	conflicts := []systemConflict{}
+	for name, state := range states {
+		if state.ServiceClustered(types.LXD) {
+			continue
+		}
+
+		for _, conflict := range state.Conflicts() {
+			if sh.Services[types.MicroCeph] == nil && (conflict.Name == names.RemotePool || conflict.Name == names.RemoteFSPool) {
+				continue
+			}
+
+			if sh.Services[types.MicroOVN] == nil && (conflict.Name == names.OVNNetwork || conflict.Name == names.UplinkNetwork) {
+				continue
+			}
+
+			conflicts = append(conflicts, systemConflict{system: name, Conflict: conflict})
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].system != conflicts[j].system {
+			return conflicts[i].system < conflicts[j].system
+		}
+
+		return conflicts[i].Name < conflicts[j].Name
+	})
+
+	return conflicts
+}
+
+// conflictOptions returns the resolutions supported by the given conflict.
+func conflictOptions(conflict service.Conflict) []string {
+	if conflict.Renamable() {
+		return conflictResolutions
+	}
+
+	return []string{conflictReuse, conflictAbort}
+}
+
+// resolveConflicts resolves the storage pools and networks of the systems which conflict with the ones MicroCloud sets up.
+// Resolutions are taken from the given map of resource names, and otherwise default to the given resolution, or are asked for if there is no default.
+// Returns the systems with renamed networks, and the names of the reused resources which MicroCloud must not set up.
+func (c *initConfig) resolveConflicts(sh *service.Handler, resolutions map[string]string, defaultResolution string) (renamed []string, reused map[string]bool, err error) {
+	reused = map[string]bool{}
+	lxd := sh.Services[types.LXD].(*service.LXDService)
+	for _, conflict := range systemConflicts(sh, c.state) {
+		entity := strings.ReplaceAll(conflict.Entity, "-", " ")
+		options := conflictOptions(conflict.Conflict)
+
+		resolution, ok := resolutions[conflict.Name]
+		if !ok && defaultResolution != "" {
+			resolution = defaultResolution
+		} else if !ok {
+			question := fmt.Sprintf("The %s %q on %q can't be used by MicroCloud, as %s. How to proceed? (%s)", entity, conflict.Name, conflict.system, conflict.Reason, strings.Join(options, "/"))
+			resolution, err = c.asker.AskString(question, conflictAbort, validate.IsOneOf(options...))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if !slices.Contains(options, resolution) {
+			return nil, nil, fmt.Errorf("Cannot %s the %s %q on %q", resolution, entity, conflict.Name, conflict.system)
+		}
+
+		switch resolution {
+		case conflictRename:
+			state := c.state[conflict.system]
+			newName := conflict.Name + "-old"
+			err = lxd.RenameNetwork(context.Background(), conflict.system, state.ClusterAddress, c.systems[conflict.system].ServerInfo.Certificate, conflict.Name, newName)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed to rename the %s %q on %q: %w", entity, conflict.Name, conflict.system, err)
+			}
+
+			fmt.Println(tui.SummarizeResult("Renamed the %s %s on %s to %s", entity, conflict.Name, conflict.system, newName))
+
+			if !slices.Contains(renamed, conflict.system) {
+				renamed = append(renamed, conflict.system)
+			}
+
+		case conflictReuse:
+			reused[conflict.Name] = true
+			tui.PrintWarning(fmt.Sprintf("Reusing the %s %q on %q. MicroCloud won't set it up", entity, conflict.Name, conflict.system))
+
+		default:
+			return nil, nil, fmt.Errorf("The %s %q on %q can't be used by MicroCloud, as %s. Rename or remove it before setting up MicroCloud", entity, conflict.Name, conflict.system, conflict.Reason)
+		}
+	}
+
+	return renamed, reused, nil
+}
+
+// askConflicts resolves the conflicting storage pools and networks of the systems, and refreshes the information of the systems with renamed networks.
+// Without questions, the conflicting resources are reused, which skips setting them up.
+func (c *initConfig) askConflicts(sh *service.Handler) error {
+	defaultResolution := ""
+	if c.autoSetup {
+		defaultResolution = conflictReuse
+	}
+
+	renamed, _, err := c.resolveConflicts(sh, nil, defaultResolution)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range renamed {
+		system := c.systems[name]
+		state, err := sh.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: name, Address: c.state[name].ClusterAddress, Certificate: system.ServerInfo.Certificate, Services: system.ServerInfo.Services}, c.collectOptions)
+		if err != nil {
+			return err
+		}
+
+		c.state[name] = *state
+	}
+
+	return nil
+}
+
+// dropReusedResources removes the storage pools and networks reused from the systems from their configuration, so MicroCloud doesn't set them up.
+func dropReusedResources(systems map[string]InitSystem, reused map[string]bool) {
+	if len(reused) == 0 {
+		return
+	}
+
+	for name, system := range systems {
+		system.TargetNetworks = slices.DeleteFunc(system.TargetNetworks, func(network lxdAPI.NetworksPost) bool { return reused[network.Name] })
+		system.Networks = slices.DeleteFunc(system.Networks, func(network lxdAPI.NetworksPost) bool { return reused[network.Name] })
+		system.TargetStoragePools = slices.DeleteFunc(system.TargetStoragePools, func(pool lxdAPI.StoragePoolsPost) bool { return reused[pool.Name] })
+		system.StoragePools = slices.DeleteFunc(system.StoragePools, func(pool lxdAPI.StoragePoolsPost) bool { return reused[pool.Name] })
+		system.JoinConfig = slices.DeleteFunc(system.JoinConfig, func(config lxdAPI.ClusterMemberConfigKey) bool { return reused[config.Name] })
+
+		systems[name] = system
+	}
+}
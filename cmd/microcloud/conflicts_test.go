@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type conflictsSuite struct {
+	suite.Suite
+}
+
+func TestConflictsSuite(t *testing.T) {
+	suite.Run(t, new(conflictsSuite))
+}
+
+func (s *conflictsSuite) Test_conflictOptions() {
+	s.Equal([]string{conflictRename, conflictReuse, conflictAbort}, conflictOptions(service.Conflict{Entity: "network", Name: service.DefaultFANNetwork}))
+	s.Equal([]string{conflictReuse, conflictAbort}, conflictOptions(service.Conflict{Entity: "storage-pool", Name: service.DefaultZFSPool}))
+}
+
+func (s *conflictsSuite) Test_dropReusedResources() {
+	systems := map[string]InitSystem{
+		"micro01": {
+			TargetNetworks:     []lxdAPI.NetworksPost{{Name: service.DefaultFANNetwork}},
+			Networks:           []lxdAPI.NetworksPost{{Name: service.DefaultFANNetwork}},
+			TargetStoragePools: []lxdAPI.StoragePoolsPost{{Name: service.DefaultZFSPool}, {Name: service.DefaultCephPool}},
+			StoragePools:       []lxdAPI.StoragePoolsPost{{Name: service.DefaultZFSPool}, {Name: service.DefaultCephPool}},
+		},
+		"micro02": {
+			JoinConfig: []lxdAPI.ClusterMemberConfigKey{
+				{Entity: "storage-pool", Name: service.DefaultZFSPool, Key: "source"},
+				{Entity: "storage-pool", Name: service.DefaultCephPool, Key: "source"},
+			},
+		},
+	}
+
+	dropReusedResources(systems, map[string]bool{service.DefaultZFSPool: true, service.DefaultFANNetwork: true})
+
+	s.Empty(systems["micro01"].TargetNetworks)
+	s.Empty(systems["micro01"].Networks)
+	s.Equal([]lxdAPI.StoragePoolsPost{{Name: service.DefaultCephPool}}, systems["micro01"].TargetStoragePools)
+	s.Equal([]lxdAPI.StoragePoolsPost{{Name: service.DefaultCephPool}}, systems["micro01"].StoragePools)
+	s.Equal([]lxdAPI.ClusterMemberConfigKey{{Entity: "storage-pool", Name: service.DefaultCephPool, Key: "source"}}, systems["micro02"].JoinConfig)
+}
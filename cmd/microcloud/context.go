@@ -0,0 +1,382 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// LocalContext is the name of the context of the MicroCloud running on this system.
+const LocalContext = "local"
+
+// contextAnnotation marks the commands which can run against the MicroCloud of a remote context.
+// Subcommands of a marked command support contexts too.
+const contextAnnotation = "microcloud.context"
+
+// cliContext is a MicroCloud managed from this CLI through the API of one of its cluster members.
+type cliContext struct {
+	// Address is the address of the cluster member to connect to.
+	Address string `yaml:"address"`
+}
+
+// contextDir returns the directory holding the certificates of the given context.
+func (c *cliConfig) contextDir(name string) string {
+	return filepath.Join(filepath.Dir(c.path), "contexts", name)
+}
+
+// contextName returns the name of the selected remote context, or an empty string for the local MicroCloud.
+// The --context flag takes precedence over the current context of the configuration file.
+func (c *CmdControl) contextName() string {
+	name := c.FlagContext
+	if name == "" && c.config != nil {
+		name = c.config.CurrentContext
+	}
+
+	if name == LocalContext {
+		return ""
+	}
+
+	return name
+}
+
+// supportsContext returns whether the given command can run against the MicroCloud of a remote context.
+// Help and shell completion always run locally, with completion suggestions queried from the selected context.
+func supportsContext(cmd *cobra.Command) bool {
+	if slices.Contains([]string{"help", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}, cmd.Name()) {
+		return true
+	}
+
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[contextAnnotation] != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// app returns the MicroCloud app to run the command against.
+// For remote contexts, the clients of the app connect to the context's cluster member.
+func (c *CmdControl) app() (*microcluster.MicroCluster, error) {
+	name := c.contextName()
+	if name == "" {
+		return microcluster.App(microcluster.Args{StateDir: c.FlagMicroCloudDir})
+	}
+
+	context, ok := c.config.Contexts[name]
+	if !ok {
+		return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Context %q doesn't exist", name))
+	}
+
+	// Log all requests for debugging, like the clients of the MicroCloud service.
+	proxy := func(r *http.Request) (*url.URL, error) {
+		cloudClient.LogRequest(r)
+
+		return shared.ProxyFromEnvironment(r)
+	}
+
+	// The context directory is laid out like a state directory, so the context's certificates are used by the client.
+	args := microcluster.Args{StateDir: c.config.contextDir(name), Proxy: proxy}
+	remoteApp, err := microcluster.App(args)
+	if err != nil {
+		return nil, err
+	}
+
+	args.Client, err = remoteApp.RemoteClient(util.CanonicalNetworkAddress(context.Address, service.CloudPort))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create client for context %q: %w", name, err)
+	}
+
+	return microcluster.App(args)
+}
+
+// completeContextNames suggests the names of the configured contexts.
+func (c *CmdControl) completeContextNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := []string{LocalContext}
+	for name := range c.config.Contexts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+type cmdContext struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to manage the MicroClouds managed from this CLI.
+func (c *cmdContext) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Manage the MicroClouds to run commands against",
+		Long: `Manage the MicroClouds to run commands against
+
+A context connects to the API of a cluster member of another MicroCloud, so it can be managed from this system.
+Select a context with "microcloud context use <name>" or for a single command with --context <name>.
+The "local" context is the MicroCloud running on this system.
+
+Only some commands, such as "status" and "cluster list", support remote contexts.`,
+		Annotations: map[string]string{contextAnnotation: "true"},
+		RunE:        func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdAdd = cmdContextAdd{common: c.common}
+	cmd.AddCommand(cmdAdd.command())
+
+	var cmdList = cmdContextList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	var cmdUse = cmdContextUse{common: c.common}
+	cmd.AddCommand(cmdUse.command())
+
+	var cmdRemove = cmdContextRemove{common: c.common}
+	cmd.AddCommand(cmdRemove.command())
+
+	return cmd
+}
+
+type cmdContextAdd struct {
+	common *CmdControl
+
+	flagCert        string
+	flagKey         string
+	flagClusterCert string
+}
+
+// command returns the subcommand to add a context.
+func (c *cmdContextAdd) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name> <address>",
+		Short: "Add a context for a remote MicroCloud",
+		Long: `Add a context for a remote MicroCloud
+
+The client certificate has to be trusted by the remote MicroCloud, which currently only trusts its cluster members.
+Use the server certificate and key of a cluster member (server.crt and server.key in its MicroCloud state directory),
+and the cluster certificate (cluster.crt) to verify the remote cluster member.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagCert, "cert", "", "Client certificate file"+"``")
+	cmd.Flags().StringVar(&c.flagKey, "key", "", "Client key file"+"``")
+	cmd.Flags().StringVar(&c.flagClusterCert, "cluster-cert", "", "Cluster certificate file of the remote MicroCloud"+"``")
+	_ = cmd.MarkFlagRequired("cert")
+	_ = cmd.MarkFlagRequired("key")
+	_ = cmd.MarkFlagRequired("cluster-cert")
+
+	return cmd
+}
+
+// run runs the subcommand to add a context.
+func (c *cmdContextAdd) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Help()
+	}
+
+	name := args[0]
+	if name == LocalContext || name == "" || filepath.Base(name) != name {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid context name %q", name))
+	}
+
+	var err error
+	config := c.common.config
+	_, ok := config.Contexts[name]
+	if ok {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("Context %q already exists", name))
+	}
+
+	files := map[string][]byte{}
+	for file, path := range map[string]string{"server.crt": c.flagCert, "server.key": c.flagKey, "cluster.crt": c.flagClusterCert} {
+		files[file], err = os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("Failed to read %q: %w", path, err)
+		}
+	}
+
+	_, err = shared.KeyPairFromRaw(files["server.crt"], files["server.key"])
+	if err != nil {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("Invalid client certificate: %w", err))
+	}
+
+	_, err = shared.ParseCert(files["cluster.crt"])
+	if err != nil {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("Invalid cluster certificate: %w", err))
+	}
+
+	dir := config.contextDir(name)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create context directory: %w", err)
+	}
+
+	for file, content := range files {
+		err = os.WriteFile(filepath.Join(dir, file), content, 0600)
+		if err != nil {
+			return fmt.Errorf("Failed to write %q: %w", file, err)
+		}
+	}
+
+	if config.Contexts == nil {
+		config.Contexts = map[string]cliContext{}
+	}
+
+	config.Contexts[name] = cliContext{Address: args[1]}
+
+	return config.save()
+}
+
+type cmdContextList struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to list the contexts.
+func (c *cmdContextList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the contexts",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to list the contexts.
+func (c *cmdContextList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	current := c.common.contextName()
+	if current == "" {
+		current = LocalContext
+	}
+
+	rows := [][]string{{LocalContext, "unix socket", ""}}
+	for name, context := range c.common.config.Contexts {
+		rows = append(rows, []string{name, context.Address, ""})
+	}
+
+	for _, row := range rows {
+		if row[0] == current {
+			row[2] = "yes"
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+
+	table, err := tui.FormatData(c.flagFormat, []string{"NAME", "ADDRESS", "CURRENT"}, rows, c.common.config.Contexts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+type cmdContextUse struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to select the current context.
+func (c *cmdContextUse) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "use <name>",
+		Short:             "Select the context to run commands against",
+		RunE:              c.run,
+		ValidArgsFunction: c.common.completeContextNames,
+	}
+
+	return cmd
+}
+
+// run runs the subcommand to select the current context.
+func (c *cmdContextUse) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	config := c.common.config
+	_, ok := config.Contexts[args[0]]
+	if !ok && args[0] != LocalContext {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Context %q doesn't exist", args[0]))
+	}
+
+	config.CurrentContext = args[0]
+	if args[0] == LocalContext {
+		config.CurrentContext = ""
+	}
+
+	return config.save()
+}
+
+type cmdContextRemove struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to remove a context.
+func (c *cmdContextRemove) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove <name>",
+		Short:             "Remove a context",
+		RunE:              c.run,
+		ValidArgsFunction: c.common.completeContextNames,
+	}
+
+	return cmd
+}
+
+// run runs the subcommand to remove a context.
+func (c *cmdContextRemove) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	name := args[0]
+	config := c.common.config
+	_, ok := config.Contexts[name]
+	if !ok {
+		if name == LocalContext {
+			return withExitCode(ExitCodeUsage, errors.New("The local context cannot be removed"))
+		}
+
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Context %q doesn't exist", name))
+	}
+
+	err := os.RemoveAll(config.contextDir(name))
+	if err != nil {
+		return fmt.Errorf("Failed to remove context directory: %w", err)
+	}
+
+	delete(config.Contexts, name)
+	if config.CurrentContext == name {
+		config.CurrentContext = ""
+	}
+
+	return config.save()
+}
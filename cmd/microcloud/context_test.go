@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+)
+
+type contextSuite struct {
+	suite.Suite
+}
+
+func TestContextSuite(t *testing.T) {
+	suite.Run(t, new(contextSuite))
+}
+
+func (s *contextSuite) Test_contextName() {
+	cases := []struct {
+		desc    string
+		flag    string
+		current string
+		name    string
+	}{
+		{desc: "No context selected"},
+		{desc: "Current context", current: "site-a", name: "site-a"},
+		{desc: "Flag overrides the current context", flag: "site-b", current: "site-a", name: "site-b"},
+		{desc: "Local context flag overrides the current context", flag: LocalContext, current: "site-a"},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		common := CmdControl{FlagContext: c.flag, config: &cliConfig{CurrentContext: c.current}}
+		s.Equal(c.name, common.contextName())
+	}
+}
+
+func (s *contextSuite) Test_supportsContext() {
+	root := &cobra.Command{Use: "microcloud"}
+	status := &cobra.Command{Use: "status", Annotations: map[string]string{contextAnnotation: "true"}}
+	tokens := &cobra.Command{Use: "tokens", Annotations: map[string]string{contextAnnotation: "true"}}
+	tokensList := &cobra.Command{Use: "list"}
+	initCmd := &cobra.Command{Use: "init"}
+	complete := &cobra.Command{Use: cobra.ShellCompRequestCmd}
+
+	tokens.AddCommand(tokensList)
+	root.AddCommand(status, tokens, initCmd, complete)
+
+	s.True(supportsContext(status))
+	s.True(supportsContext(tokensList))
+	s.True(supportsContext(complete))
+	s.False(supportsContext(initCmd))
+	s.False(supportsContext(root))
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// drainAntiAffinityKey is the instance configuration key naming a group of instances of a project which must run on different cluster members.
+const drainAntiAffinityKey = "user.microcloud.anti-affinity"
+
+const (
+	// drainLiveMigrate moves a running instance without stopping it.
+	drainLiveMigrate = "live-migrate"
+
+	// drainMove moves a stopped instance.
+	drainMove = "move"
+
+	// drainStopAndMove stops a running instance, moves it and starts it again.
+	drainStopAndMove = "stop-and-move"
+
+	// drainSkip leaves an instance which can't be moved on its cluster member.
+	drainSkip = "skip"
+)
+
+// drainLocalDeviceTypes are the LXD device types tied to the hardware of a cluster member.
+var drainLocalDeviceTypes = []string{"gpu", "usb", "pci", "unix-char", "unix-block", "unix-hotplug"}
+
+// drainStep is how an instance is moved off a drained cluster member.
+type drainStep struct {
+	Name    string `json:"name" yaml:"name"`
+	Project string `json:"project" yaml:"project"`
+	Type    string `json:"type" yaml:"type"`
+	Running bool   `json:"running" yaml:"running"`
+	Action  string `json:"action" yaml:"action"`
+	Target  string `json:"target" yaml:"target"`
+	Reason  string `json:"reason" yaml:"reason"`
+}
+
+// drainBlocker returns why the instance must stay on its cluster member, or an empty string if it can move.
+func drainBlocker(instance lxdAPI.Instance) string {
+	if instance.ExpandedConfig["cluster.evacuate"] == "stop" {
+		return "Configured to stay on its cluster member (cluster.evacuate=stop)"
+	}
+
+	names := make([]string, 0, len(instance.ExpandedDevices))
+	for name := range instance.ExpandedDevices {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		device := instance.ExpandedDevices[name]
+		if slices.Contains(drainLocalDeviceTypes, device["type"]) {
+			return fmt.Sprintf("Uses the %s device %q of its cluster member", device["type"], name)
+		}
+
+		if device["type"] == "disk" && device["pool"] == "" && strings.HasPrefix(device["source"], "/") {
+			return fmt.Sprintf("Uses the path %q of its cluster member in disk device %q", device["source"], name)
+		}
+	}
+
+	return ""
+}
+
+// drainAction returns how the instance is moved, or why it can't be moved without stopping it.
+func drainAction(instance lxdAPI.Instance, stop bool) (string, string) {
+	if instance.Status != "Running" {
+		return drainMove, ""
+	}
+
+	if instance.Type == string(lxdAPI.InstanceTypeVM) && shared.IsTrue(instance.ExpandedConfig["migration.stateful"]) {
+		return drainLiveMigrate, ""
+	}
+
+	if stop {
+		return drainStopAndMove, ""
+	}
+
+	return drainSkip, "Can't be live-migrated, use --stop to stop, move and restart it"
+}
+
+// drainTarget returns the cluster member to move the instance to, or why there is none.
+// The instance stays within its cluster group, and away from the members running an instance of its anti-affinity group.
+// The member running the fewest instances is preferred.
+func drainTarget(instance lxdAPI.Instance, drained string, members []lxdAPI.ClusterMember, load map[string]int, antiAffinity map[string]map[string]bool) (string, string) {
+	group := instance.Config["volatile.cluster.group"]
+	candidates := []string{}
+	for _, member := range members {
+		if member.ServerName == drained || member.Status != "Online" {
+			continue
+		}
+
+		if group != "" && !slices.Contains(member.Groups, group) {
+			continue
+		}
+
+		candidates = append(candidates, member.ServerName)
+	}
+
+	if len(candidates) == 0 {
+		if group != "" {
+			return "", fmt.Sprintf("No other online cluster member in cluster group %q", group)
+		}
+
+		return "", "No other online cluster member"
+	}
+
+	key := instance.ExpandedConfig[drainAntiAffinityKey]
+	if key != "" {
+		candidates = slices.DeleteFunc(candidates, func(name string) bool { return antiAffinity[name][instance.Project+"/"+key] })
+		if len(candidates) == 0 {
+			return "", fmt.Sprintf("All other candidate cluster members run an instance of anti-affinity group %q", key)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if load[candidates[i]] != load[candidates[j]] {
+			return load[candidates[i]] < load[candidates[j]]
+		}
+
+		return candidates[i] < candidates[j]
+	})
+
+	return candidates[0], ""
+}
+
+// drainPlan returns how each instance located on the drained cluster member is moved off it, sorted by project and name.
+func drainPlan(drained string, members []lxdAPI.ClusterMember, instances []lxdAPI.Instance, stop bool) []drainStep {
+	load := map[string]int{}
+	antiAffinity := map[string]map[string]bool{}
+	addInstance := func(member string, instance lxdAPI.Instance) {
+		load[member]++
+		key := instance.ExpandedConfig[drainAntiAffinityKey]
+		if key == "" {
+			return
+		}
+
+		if antiAffinity[member] == nil {
+			antiAffinity[member] = map[string]bool{}
+		}
+
+		antiAffinity[member][instance.Project+"/"+key] = true
+	}
+
+	drainedInstances := []lxdAPI.Instance{}
+	for _, instance := range instances {
+		if instance.Location == drained {
+			drainedInstances = append(drainedInstances, instance)
+		} else {
+			addInstance(instance.Location, instance)
+		}
+	}
+
+	sort.Slice(drainedInstances, func(i, j int) bool {
+		if drainedInstances[i].Project != drainedInstances[j].Project {
+			return drainedInstances[i].Project < drainedInstances[j].Project
+		}
+
+		return drainedInstances[i].Name < drainedInstances[j].Name
+	})
+
+	steps := make([]drainStep, 0, len(drainedInstances))
+	for _, instance := range drainedInstances {
+		step := drainStep{Name: instance.Name, Project: instance.Project, Type: instance.Type, Running: instance.Status == "Running", Action: drainSkip}
+		step.Reason = drainBlocker(instance)
+		if step.Reason == "" {
+			step.Action, step.Reason = drainAction(instance, stop)
+		}
+
+		if step.Reason == "" {
+			step.Target, step.Reason = drainTarget(instance, drained, members, load, antiAffinity)
+			if step.Reason != "" {
+				step.Action = drainSkip
+			} else {
+				addInstance(step.Target, instance)
+			}
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps
+}
+
+// waitOperation waits for an LXD operation to finish, if it could be started.
+func waitOperation(op lxd.Operation, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// drainInstance moves the instance off its cluster member as planned.
+func drainInstance(client lxd.InstanceServer, step drainStep) error {
+	client = client.UseProject(step.Project)
+	if step.Action == drainStopAndMove {
+		err := waitOperation(client.UpdateInstanceState(step.Name, lxdAPI.InstanceStatePut{Action: "stop", Timeout: -1}, ""))
+		if err != nil {
+			return fmt.Errorf("Failed to stop the instance: %w", err)
+		}
+	}
+
+	err := waitOperation(client.UseTarget(step.Target).MigrateInstance(step.Name, lxdAPI.InstancePost{Name: step.Name, Migration: true, Live: step.Action == drainLiveMigrate}))
+	if err != nil {
+		return fmt.Errorf("Failed to move the instance: %w", err)
+	}
+
+	if step.Action == drainStopAndMove {
+		err := waitOperation(client.UpdateInstanceState(step.Name, lxdAPI.InstanceStatePut{Action: "start", Timeout: -1}, ""))
+		if err != nil {
+			return fmt.Errorf("Failed to start the instance: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type cmdDrain struct {
+	common *CmdControl
+
+	flagStop   bool
+	flagDryRun bool
+}
+
+// command returns the subcommand to move the instances off a cluster member.
+func (c *cmdDrain) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drain <member>",
+		Short: "Move the instances off a cluster member",
+		Long: `Move the instances off a cluster member
+
+Running virtual machines with migration.stateful enabled are live-migrated, and stopped instances are moved.
+Other running instances can't be live-migrated, and are only stopped, moved and restarted with --stop.
+
+Each instance moves to the online cluster member running the fewest instances, within its cluster group if it was placed in one.
+Instances of the same project sharing a ` + drainAntiAffinityKey + ` value are never moved to a member already running one of them.
+Instances using devices of the cluster member, or configured with cluster.evacuate=stop, stay on it.
+The instances which can't move are listed with the reason.
+
+Once no running instance is left, the cluster member is evacuated in LXD, so that no new instance is placed on it.
+Run "lxc cluster restore <member>" to bring it back into service.`,
+		RunE:              c.run,
+		ValidArgsFunction: c.common.completeMemberNames,
+	}
+
+	cmd.Flags().BoolVar(&c.flagStop, "stop", false, "Stop, move and restart the running instances which can't be live-migrated")
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, "Only show how each instance would move")
+
+	return cmd
+}
+
+// run runs the subcommand to move the instances off a cluster member.
+func (c *cmdDrain) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	drained := args[0]
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.MicroCloud, types.LXD)
+	if err != nil {
+		return err
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	members, err := lxdClient.GetClusterMembers()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD cluster members: %w", err)
+	}
+
+	index := slices.IndexFunc(members, func(m lxdAPI.ClusterMember) bool { return m.ServerName == drained })
+	if index < 0 {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Unknown cluster member %q", drained))
+	}
+
+	instances, err := lxdClient.GetInstancesAllProjects(lxdAPI.InstanceTypeAny)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD instances: %w", err)
+	}
+
+	steps := drainPlan(drained, members, instances, c.flagStop)
+	if len(steps) > 0 {
+		rows := make([][]string, 0, len(steps))
+		for _, step := range steps {
+			rows = append(rows, []string{step.Name, step.Project, step.Type, step.Action, step.Target, step.Reason})
+		}
+
+		fmt.Println(tui.NewTable([]string{"INSTANCE", "PROJECT", "TYPE", "ACTION", "TARGET", "REASON"}, rows))
+	} else {
+		fmt.Printf("No instances on %q\n", drained)
+	}
+
+	if c.flagDryRun {
+		return nil
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	// Serialize with other cluster changes, such as a rolling restart of LXD.
+	release, err := lockClusterChanges(cmd.Context(), client, "drain", "Drain "+drained)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	left := []drainStep{}
+	for _, step := range steps {
+		if step.Action == drainSkip {
+			left = append(left, step)
+			continue
+		}
+
+		fmt.Printf("Moving %q of project %q to %q (%s) ...\n", step.Name, step.Project, step.Target, step.Action)
+		err := drainInstance(lxdClient, step)
+		if err != nil {
+			step.Reason = err.Error()
+			left = append(left, step)
+			tui.PrintWarning(fmt.Sprintf("Failed to move %q of project %q: %v", step.Name, step.Project, err))
+			continue
+		}
+
+		fmt.Println(tui.SummarizeResult("Moved %s of project %s to %s", step.Name, step.Project, step.Target))
+	}
+
+	running := slices.ContainsFunc(left, func(step drainStep) bool { return step.Running })
+	if running {
+		tui.PrintWarning(fmt.Sprintf("Instances still run on %q, so it isn't evacuated", drained))
+	} else if members[index].Status != "Evacuated" {
+		op, err := lxdClient.UpdateClusterMemberState(drained, lxdAPI.ClusterMemberStatePost{Action: "evacuate", Mode: "stop"})
+		err = waitOperation(op, err)
+		if err != nil {
+			return fmt.Errorf("Failed to evacuate %q: %w", drained, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Evacuated %s, run \"lxc cluster restore %s\" to bring it back into service", drained, drained))
+	}
+
+	if len(left) > 0 {
+		return fmt.Errorf("%d instances couldn't be moved off %q", len(left), drained)
+	}
+
+	return nil
+}
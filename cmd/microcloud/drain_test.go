@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type drainSuite struct {
+	suite.Suite
+}
+
+func TestDrainSuite(t *testing.T) {
+	suite.Run(t, new(drainSuite))
+}
+
+func (s *drainSuite) Test_drainPlan() {
+	members := []lxdAPI.ClusterMember{
+		{ServerName: "micro01", Status: "Online", Groups: []string{"default"}},
+		{ServerName: "micro02", Status: "Online", Groups: []string{"default", "gpu"}},
+		{ServerName: "micro03", Status: "Online", Groups: []string{"default"}},
+		{ServerName: "micro04", Status: "Offline", Groups: []string{"default", "gpu"}},
+	}
+
+	vm := func(name string, status string, config map[string]string) lxdAPI.Instance {
+		return lxdAPI.Instance{Name: name, Project: "default", Location: "micro01", Status: status, Type: "virtual-machine", ExpandedConfig: config}
+	}
+
+	instances := []lxdAPI.Instance{
+		// Other members run one instance each, of anti-affinity group db on micro02.
+		{Name: "db1", Project: "default", Location: "micro02", Status: "Running", ExpandedConfig: map[string]string{drainAntiAffinityKey: "db"}},
+		{Name: "web1", Project: "default", Location: "micro03", Status: "Running"},
+		vm("a-live", "Running", map[string]string{"migration.stateful": "true"}),
+		vm("b-stopped", "Stopped", nil),
+		{Name: "c-container", Project: "default", Location: "micro01", Status: "Running", Type: "container"},
+		vm("d-db", "Running", map[string]string{"migration.stateful": "true", drainAntiAffinityKey: "db"}),
+		vm("e-db", "Stopped", map[string]string{drainAntiAffinityKey: "db"}),
+		{Name: "f-gpu", Project: "default", Location: "micro01", Status: "Stopped", Type: "virtual-machine", Config: map[string]string{"volatile.cluster.group": "gpu"}},
+		{Name: "g-passthrough", Project: "default", Location: "micro01", Status: "Stopped", ExpandedDevices: map[string]map[string]string{"gpu0": {"type": "gpu"}}},
+		vm("h-pinned", "Stopped", map[string]string{"cluster.evacuate": "stop"}),
+		{Name: "i-hostpath", Project: "default", Location: "micro01", Status: "Stopped", ExpandedDevices: map[string]map[string]string{"data": {"type": "disk", "source": "/srv/data", "path": "/data"}, "root": {"type": "disk", "pool": "remote", "path": "/"}}},
+		{Name: "j-other", Project: "other", Location: "micro01", Status: "Stopped", Config: map[string]string{"volatile.cluster.group": "fast"}},
+	}
+
+	steps := drainPlan("micro01", members, instances, false)
+	s.Equal([]drainStep{
+		{Name: "a-live", Project: "default", Type: "virtual-machine", Running: true, Action: drainLiveMigrate, Target: "micro02"},
+		{Name: "b-stopped", Project: "default", Type: "virtual-machine", Action: drainMove, Target: "micro03"},
+		{Name: "c-container", Project: "default", Type: "container", Running: true, Action: drainSkip, Reason: "Can't be live-migrated, use --stop to stop, move and restart it"},
+		{Name: "d-db", Project: "default", Type: "virtual-machine", Running: true, Action: drainLiveMigrate, Target: "micro03"},
+		{Name: "e-db", Project: "default", Type: "virtual-machine", Action: drainSkip, Reason: `All other candidate cluster members run an instance of anti-affinity group "db"`},
+		{Name: "f-gpu", Project: "default", Type: "virtual-machine", Action: drainMove, Target: "micro02"},
+		{Name: "g-passthrough", Project: "default", Action: drainSkip, Reason: `Uses the gpu device "gpu0" of its cluster member`},
+		{Name: "h-pinned", Project: "default", Type: "virtual-machine", Action: drainSkip, Reason: "Configured to stay on its cluster member (cluster.evacuate=stop)"},
+		{Name: "i-hostpath", Project: "default", Action: drainSkip, Reason: `Uses the path "/srv/data" of its cluster member in disk device "data"`},
+		{Name: "j-other", Project: "other", Action: drainSkip, Reason: `No other online cluster member in cluster group "fast"`},
+	}, steps)
+
+	steps = drainPlan("micro01", members, instances[:5], true)
+	s.Equal(drainStep{Name: "c-container", Project: "default", Type: "container", Running: true, Action: drainStopAndMove, Target: "micro02"}, steps[2])
+
+	s.Empty(drainPlan("micro03", members[:1], nil, false))
+}
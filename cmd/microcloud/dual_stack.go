@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/multicast"
+)
+
+// isDualStack returns whether the networks have addresses of both IP families.
+func isDualStack(info []multicast.NetworkInfo) bool {
+	hasIPv4 := false
+	hasIPv6 := false
+	for _, network := range info {
+		ip := net.ParseIP(network.Address)
+		if ip == nil {
+			continue
+		}
+
+		if ip.To4() != nil {
+			hasIPv4 = true
+		} else {
+			hasIPv6 = true
+		}
+	}
+
+	return hasIPv4 && hasIPv6
+}
+
+// selectAddresses returns the address and the address of the other IP family out of the selected addresses.
+// On dual-stack systems, one address of each IP family can be selected, and the first one is the cluster address.
+func selectAddresses(selected []string, dualStack bool) (address string, altAddress string, err error) {
+	if !dualStack {
+		if len(selected) != 1 {
+			return "", "", errors.New("You must select exactly one address")
+		}
+
+		return selected[0], "", nil
+	}
+
+	if len(selected) == 0 || len(selected) > 2 {
+		return "", "", errors.New("You must select one address, or one IPv4 and one IPv6 address")
+	}
+
+	if len(selected) == 2 {
+		if multicast.SameFamily(selected[0], selected[1]) {
+			return "", "", errors.New("You must select one address, or one IPv4 and one IPv6 address")
+		}
+
+		return selected[0], selected[1], nil
+	}
+
+	return selected[0], "", nil
+}
+
+// useAddress sets the cluster address of the local system, along with the interface and subnet of that address.
+func (c *initConfig) useAddress(address string, info []multicast.NetworkInfo) error {
+	var subnet *net.IPNet
+	var iface *net.Interface
+	for _, network := range info {
+		if network.Subnet.Contains(net.ParseIP(address)) {
+			subnet = network.Subnet
+			iface = &network.Interface
+			break
+		}
+	}
+
+	if subnet == nil {
+		return fmt.Errorf("Could not find valid subnet for address %q", address)
+	}
+
+	c.address = address
+	c.lookupIface = iface
+	c.lookupSubnet = subnet
+
+	bootstrapSystem, ok := c.systems[c.name]
+	if ok {
+		bootstrapSystem.ServerInfo.Address = address
+		bootstrapSystem.MicroCloudInternalNetwork = &NetworkInterfaceInfo{Interface: *iface, Subnet: c.lookupSubnet, IP: net.IP(address)}
+		c.systems[c.name] = bootstrapSystem
+	}
+
+	return nil
+}
+
+// preferReachableFamily switches the cluster address to the address of the other IP family,
+// if all the joining systems reached the local system with that family.
+// It returns whether the cluster address was switched.
+func (c *initConfig) preferReachableFamily() (bool, error) {
+	if c.altAddress == "" {
+		return false, nil
+	}
+
+	joiners := 0
+	altJoiners := []string{}
+	for name, system := range c.systems {
+		if name == c.name {
+			continue
+		}
+
+		joiners++
+		if multicast.SameFamily(system.ServerInfo.Address, c.altAddress) {
+			altJoiners = append(altJoiners, name)
+		}
+	}
+
+	if len(altJoiners) == 0 {
+		return false, nil
+	}
+
+	if len(altJoiners) < joiners {
+		slices.Sort(altJoiners)
+		tui.PrintWarning(fmt.Sprintf("Systems %s are only reachable with the IP family of %s, but MicroCloud uses %s", strings.Join(altJoiners, ", "), c.altAddress, c.address))
+
+		return false, nil
+	}
+
+	info, err := multicast.GetNetworkInfo()
+	if err != nil {
+		return false, fmt.Errorf("Failed to find network interfaces: %w", err)
+	}
+
+	address := c.address
+	err = c.useAddress(c.altAddress, info)
+	if err != nil {
+		return false, err
+	}
+
+	c.altAddress = address
+	fmt.Println(tui.SummarizeResult("Using address %s for MicroCloud, as the other systems are reachable with its IP family", c.address))
+
+	return true, nil
+}
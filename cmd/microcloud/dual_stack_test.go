@@ -0,0 +1,136 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/multicast"
+)
+
+type dualStackSuite struct {
+	suite.Suite
+}
+
+func TestDualStackSuite(t *testing.T) {
+	suite.Run(t, new(dualStackSuite))
+}
+
+func (s *dualStackSuite) networkInfo(addresses ...string) []multicast.NetworkInfo {
+	info := make([]multicast.NetworkInfo, 0, len(addresses))
+	for _, address := range addresses {
+		_, subnet, err := net.ParseCIDR(address)
+		s.Require().NoError(err)
+
+		ip, _, _ := net.ParseCIDR(address)
+		info = append(info, multicast.NetworkInfo{Interface: net.Interface{Name: "eth0"}, Address: ip.String(), Subnet: subnet})
+	}
+
+	return info
+}
+
+func (s *dualStackSuite) Test_isDualStack() {
+	s.False(isDualStack(s.networkInfo("10.0.0.1/24", "10.0.1.1/24")))
+	s.False(isDualStack(s.networkInfo("fd00::1/64")))
+	s.True(isDualStack(s.networkInfo("10.0.0.1/24", "fd00::1/64")))
+}
+
+func (s *dualStackSuite) Test_selectAddresses() {
+	cases := []struct {
+		desc       string
+		selected   []string
+		dualStack  bool
+		address    string
+		altAddress string
+		err        bool
+	}{
+		{
+			desc:     "Single address",
+			selected: []string{"10.0.0.1"},
+			address:  "10.0.0.1",
+		},
+		{
+			desc:     "Two addresses on a single-stack system",
+			selected: []string{"10.0.0.1", "10.0.1.1"},
+			err:      true,
+		},
+		{
+			desc:      "Single address on a dual-stack system",
+			selected:  []string{"fd00::1"},
+			dualStack: true,
+			address:   "fd00::1",
+		},
+		{
+			desc:       "One address of each family",
+			selected:   []string{"fd00::1", "10.0.0.1"},
+			dualStack:  true,
+			address:    "fd00::1",
+			altAddress: "10.0.0.1",
+		},
+		{
+			desc:      "Two addresses of the same family",
+			selected:  []string{"10.0.0.1", "10.0.1.1"},
+			dualStack: true,
+			err:       true,
+		},
+		{
+			desc:      "No address",
+			dualStack: true,
+			err:       true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		address, altAddress, err := selectAddresses(c.selected, c.dualStack)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.address, address)
+		s.Equal(c.altAddress, altAddress)
+	}
+}
+
+func (s *dualStackSuite) Test_useAddress() {
+	cfg := initConfig{name: "micro01", systems: map[string]InitSystem{"micro01": {}}}
+	info := s.networkInfo("10.0.0.1/24", "fd00::1/64")
+
+	s.NoError(cfg.useAddress("fd00::1", info))
+	s.Equal("fd00::1", cfg.address)
+	s.Equal("fd00::/64", cfg.lookupSubnet.String())
+	s.Equal("fd00::1", cfg.systems["micro01"].ServerInfo.Address)
+
+	s.Error(cfg.useAddress("10.0.2.1", info))
+}
+
+func (s *dualStackSuite) Test_preferReachableFamily() {
+	cfg := initConfig{name: "micro01", address: "10.0.0.1", systems: map[string]InitSystem{
+		"micro01": {ServerInfo: multicast.ServerInfo{Name: "micro01", Address: "10.0.0.1"}},
+		"micro02": {ServerInfo: multicast.ServerInfo{Name: "micro02", Address: "fd00::2"}},
+		"micro03": {ServerInfo: multicast.ServerInfo{Name: "micro03", Address: "10.0.0.3"}},
+	}}
+
+	s.T().Log("Single-stack system")
+	switched, err := cfg.preferReachableFamily()
+	s.NoError(err)
+	s.False(switched)
+
+	s.T().Log("Joiners reachable with both families")
+	cfg.altAddress = "fd00::1"
+	switched, err = cfg.preferReachableFamily()
+	s.NoError(err)
+	s.False(switched)
+	s.Equal("10.0.0.1", cfg.address)
+
+	s.T().Log("Joiners reachable with the family of the cluster address")
+	delete(cfg.systems, "micro02")
+	switched, err = cfg.preferReachableFamily()
+	s.NoError(err)
+	s.False(switched)
+	s.Equal("10.0.0.1", cfg.address)
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// osdKeyPrefix is the prefix MicroCeph stores the keys of encrypted OSDs under in the Ceph config-key store.
+const osdKeyPrefix = "microceph:osd"
+
+// escrowTimeout is the time to wait for a single key to be escrowed.
+const escrowTimeout = 30 * time.Second
+
+// OSDKey is the encryption key of an OSD, which recovers the OSD if the Ceph monitors are lost.
+type OSDKey struct {
+	OSD    int64  `json:"osd"    yaml:"osd"`
+	Member string `json:"member" yaml:"member"`
+	Path   string `json:"path"   yaml:"path"`
+	Name   string `json:"name"   yaml:"name"`
+	Key    string `json:"key"    yaml:"key"`
+}
+
+// osdKeyID returns the OSD number of the given Ceph config-key, and whether it holds an OSD encryption key.
+// MicroCeph names the keys "microceph:osd.<id>/key", with an optional suffix after "osd" for WAL and DB devices.
+func osdKeyID(name string) (int64, bool) {
+	rest, ok := strings.CutPrefix(name, osdKeyPrefix)
+	if !ok {
+		return 0, false
+	}
+
+	rest, ok = strings.CutSuffix(rest, "/key")
+	if !ok {
+		return 0, false
+	}
+
+	_, id, ok := strings.Cut(rest, ".")
+	if !ok {
+		return 0, false
+	}
+
+	osd, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return osd, true
+}
+
+// getOSDKeys returns the encryption keys of all encrypted OSDs of the MicroCloud.
+func (c *CmdControl) getOSDKeys(ctx context.Context) ([]OSDKey, error) {
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.FlagMicroCloudDir})
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := cloudApp.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return nil, withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.FlagMicroCloudDir, types.MicroCeph)
+	if err != nil {
+		return nil, err
+	}
+
+	disks, err := sh.Services[types.MicroCeph].(*service.CephService).GetDisks(ctx, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the MicroCeph disks: %w", err)
+	}
+
+	out, err := runCeph(ctx, "config-key", "ls")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list the Ceph config-keys: %w", err)
+	}
+
+	names := []string{}
+	err = json.Unmarshal([]byte(out), &names)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the Ceph config-keys: %w", err)
+	}
+
+	keys := []OSDKey{}
+	for _, name := range names {
+		osd, ok := osdKeyID(name)
+		if !ok {
+			continue
+		}
+
+		key, err := runCeph(ctx, "config-key", "get", name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the encryption key of OSD %d: %w", osd, err)
+		}
+
+		osdKey := OSDKey{OSD: osd, Name: name, Key: strings.TrimSpace(key)}
+		for _, disk := range disks {
+			if disk.OSD == osd {
+				osdKey.Member = disk.Location
+				osdKey.Path = disk.Path
+			}
+		}
+
+		keys = append(keys, osdKey)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].OSD != keys[j].OSD {
+			return keys[i].OSD < keys[j].OSD
+		}
+
+		return keys[i].Name < keys[j].Name
+	})
+
+	if len(keys) == 0 {
+		return nil, errors.New("No encrypted OSDs found")
+	}
+
+	return keys, nil
+}
+
+type cmdEncryption struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to manage the encryption keys of OSDs.
+func (c *cmdEncryption) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encryption",
+		Short: "Manage the encryption keys of OSDs",
+		Long: `Manage the encryption keys of OSDs
+
+MicroCeph stores the keys of encrypted OSDs in the Ceph config-key store, so they are lost along with the Ceph monitors.
+Export the keys as recovery material, or escrow them to an external key management system.`,
+		RunE: func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdList = cmdEncryptionList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	var cmdExport = cmdEncryptionExport{common: c.common}
+	cmd.AddCommand(cmdExport.command())
+
+	var cmdEscrow = cmdEncryptionEscrow{common: c.common}
+	cmd.AddCommand(cmdEscrow.command())
+
+	return cmd
+}
+
+type cmdEncryptionList struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to list the encrypted OSDs.
+func (c *cmdEncryptionList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the encrypted OSDs",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to list the encrypted OSDs.
+func (c *cmdEncryptionList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	keys, err := c.common.getOSDKeys(context.Background())
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(keys))
+	for i, key := range keys {
+		rows = append(rows, []string{strconv.FormatInt(key.OSD, 10), key.Member, key.Path, key.Name})
+
+		// Never print the key material in listings.
+		keys[i].Key = ""
+	}
+
+	table, err := tui.FormatData(c.flagFormat, []string{"OSD", "MEMBER", "PATH", "KEY NAME"}, rows, keys)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+type cmdEncryptionExport struct {
+	common *CmdControl
+
+	flagOutput string
+}
+
+// command returns the subcommand to export the encryption keys of OSDs as recovery material.
+func (c *cmdEncryptionExport) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the encryption keys of OSDs as recovery material",
+		Long: `Export the encryption keys of OSDs as recovery material
+
+The keys are printed, or written to the given file, in YAML.
+Store the recovery material offline as it unlocks the OSDs.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagOutput, "output", "o", "", "File to write the recovery material to"+"``")
+
+	return cmd
+}
+
+// run runs the subcommand to export the encryption keys of OSDs as recovery material.
+func (c *cmdEncryptionExport) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	keys, err := c.common.getOSDKeys(context.Background())
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("Failed to encode the recovery material: %w", err)
+	}
+
+	if c.flagOutput == "" {
+		fmt.Print(string(data))
+
+		return nil
+	}
+
+	// Refuse to overwrite existing recovery material.
+	f, err := os.OpenFile(c.flagOutput, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to create %q: %w", c.flagOutput, err)
+	}
+
+	_, err = f.Write(data)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to write %q: %w", c.flagOutput, err)
+	}
+
+	fmt.Println(tui.SummarizeResult("Wrote the recovery material of %d OSD keys to %s", len(keys), c.flagOutput))
+
+	return nil
+}
+
+type cmdEncryptionEscrow struct {
+	common *CmdControl
+
+	flagVaultAddress string
+	flagVaultMount   string
+	flagVaultPath    string
+	flagCommand      string
+}
+
+// command returns the subcommand to escrow the encryption keys of OSDs to an external key management system.
+func (c *cmdEncryptionEscrow) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "escrow",
+		Short: "Escrow the encryption keys of OSDs to an external key management system",
+		Long: `Escrow the encryption keys of OSDs to an external key management system
+
+With --vault-address, each key is stored as a secret of a HashiCorp Vault KV version 2 secrets engine,
+at <mount>/<path>/osd.<id>. The Vault token is read from the VAULT_TOKEN environment variable.
+
+With --command, the command is run once per key, for example to wrap the key with a PKCS#11 token.
+The key is passed on standard input, and the OSD details in the MICROCLOUD_OSD, MICROCLOUD_OSD_MEMBER
+and MICROCLOUD_OSD_KEY_NAME environment variables.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagVaultAddress, "vault-address", "", "Address of the Vault server"+"``")
+	cmd.Flags().StringVar(&c.flagVaultMount, "vault-mount", "secret", "Mount of the Vault KV version 2 secrets engine"+"``")
+	cmd.Flags().StringVar(&c.flagVaultPath, "vault-path", "microcloud", "Path of the secrets in the Vault secrets engine"+"``")
+	cmd.Flags().StringVar(&c.flagCommand, "command", "", "Command to escrow each key with"+"``")
+	cmd.MarkFlagsMutuallyExclusive("vault-address", "command")
+	cmd.MarkFlagsOneRequired("vault-address", "command")
+
+	return cmd
+}
+
+// run runs the subcommand to escrow the encryption keys of OSDs to an external key management system.
+func (c *cmdEncryptionEscrow) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	var escrow func(ctx context.Context, key OSDKey) error
+	if c.flagVaultAddress != "" {
+		err := validate.IsRequestURL(c.flagVaultAddress)
+		if err != nil {
+			return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid Vault address %q: %w", c.flagVaultAddress, err))
+		}
+
+		token := os.Getenv("VAULT_TOKEN")
+		if token == "" {
+			return withExitCode(ExitCodeUsage, errors.New("The VAULT_TOKEN environment variable must be set to escrow keys to Vault"))
+		}
+
+		escrow = func(ctx context.Context, key OSDKey) error {
+			return escrowVault(ctx, c.flagVaultAddress, token, c.flagVaultMount, c.flagVaultPath, key)
+		}
+	} else {
+		escrow = func(ctx context.Context, key OSDKey) error {
+			return escrowCommand(ctx, c.flagCommand, key)
+		}
+	}
+
+	keys, err := c.common.getOSDKeys(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		ctx, cancel := context.WithTimeout(context.Background(), escrowTimeout)
+		err := escrow(ctx, key)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("Failed to escrow the key of OSD %d: %w", key.OSD, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Escrowed the key of OSD %d on %s", key.OSD, key.Member))
+	}
+
+	return nil
+}
+
+// vaultSecretURL returns the URL of the Vault KV version 2 secret holding the given key.
+func vaultSecretURL(address string, mount string, path string, key OSDKey) (string, error) {
+	secretURL, err := url.Parse(address)
+	if err != nil {
+		return "", err
+	}
+
+	// Keys of WAL and DB devices share the OSD number, so they are stored under their own key name.
+	secret := strings.TrimPrefix(strings.TrimSuffix(key.Name, "/key"), "microceph:")
+
+	return secretURL.JoinPath("v1", mount, "data", path, secret).String(), nil
+}
+
+// escrowVault stores the given key as a secret of the Vault KV version 2 secrets engine at the given mount.
+func escrowVault(ctx context.Context, address string, token string, mount string, path string, key OSDKey) error {
+	secretURL, err := vaultSecretURL(address, mount, path, key)
+	if err != nil {
+		return fmt.Errorf("Invalid Vault address %q: %w", address, err)
+	}
+
+	body, err := json.Marshal(map[string]any{"data": key})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, secretURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("Vault returned %q", resp.Status)
+	}
+
+	return nil
+}
+
+// escrowCommand runs the given command with the key on standard input.
+func escrowCommand(ctx context.Context, command string, key OSDKey) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = strings.NewReader(key.Key)
+	cmd.Env = append(os.Environ(),
+		"MICROCLOUD_OSD="+strconv.FormatInt(key.OSD, 10),
+		"MICROCLOUD_OSD_MEMBER="+key.Member,
+		"MICROCLOUD_OSD_KEY_NAME="+key.Name,
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// hasEncryptedDisks returns whether any of the disks added to MicroCeph are encrypted.
+func (c *initConfig) hasEncryptedDisks() bool {
+	for _, system := range c.systems {
+		for _, disk := range system.MicroCephDisks {
+			if disk.Encrypt {
+				return true
+			}
+		}
+	}
+
+	return false
+}
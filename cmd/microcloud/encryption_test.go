@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type encryptionSuite struct {
+	suite.Suite
+}
+
+func TestEncryptionSuite(t *testing.T) {
+	suite.Run(t, new(encryptionSuite))
+}
+
+func (s *encryptionSuite) Test_osdKeyID() {
+	cases := []struct {
+		desc string
+		name string
+		osd  int64
+		ok   bool
+	}{
+		{desc: "OSD key", name: "microceph:osd.3/key", osd: 3, ok: true},
+		{desc: "WAL key", name: "microceph:osdwal.12/key", osd: 12, ok: true},
+		{desc: "Other MicroCeph key", name: "microceph:rgw/key", ok: false},
+		{desc: "Key without suffix", name: "microceph:osd.3", ok: false},
+		{desc: "Non-numeric OSD", name: "microceph:osd.x/key", ok: false},
+		{desc: "Unrelated key", name: "mgr/dashboard/crt", ok: false},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		osd, ok := osdKeyID(c.name)
+		s.Equal(c.ok, ok)
+		s.Equal(c.osd, osd)
+	}
+}
+
+func (s *encryptionSuite) Test_vaultSecretURL() {
+	secretURL, err := vaultSecretURL("https://vault.example.com:8200/", "secret", "microcloud/site1", OSDKey{OSD: 3, Name: "microceph:osddb.3/key"})
+	s.NoError(err)
+	s.Equal("https://vault.example.com:8200/v1/secret/data/microcloud/site1/osddb.3", secretURL)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+// parseEventTime parses a time filter of the event history.
+// The value is either a duration before now (e.g. 24h), an RFC3339 timestamp or a date.
+func parseEventTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		return now.Add(-duration), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid time %q, must be a duration (e.g. 24h), a timestamp (e.g. 2024-01-01T12:00:00Z) or a date (e.g. 2024-01-01)", value)
+}
+
+type cmdEvents struct {
+	common *CmdControl
+}
+
+// command returns the events subcommand.
+func (c *cmdEvents) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Review the event history of the cluster",
+		RunE:  c.run,
+	}
+
+	var cmdList = cmdEventsList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	return cmd
+}
+
+// run runs the events subcommand.
+func (c *cmdEvents) run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+type cmdEventsList struct {
+	common *CmdControl
+
+	flagType   string
+	flagMember []string
+	flagSince  string
+	flagUntil  string
+	flagFormat string
+}
+
+// command returns the subcommand for listing events.
+func (c *cmdEventsList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the events recorded in the event history",
+		Long: `List the events recorded in the event history
+
+Joins, removals, upgrades, health check transitions and configuration changes are recorded, oldest first.
+Events are kept for the duration set by the "events.retention" configuration key.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagType, "type", "", "Only list events of this type ("+strings.Join(types.EventTypes, "|")+")"+"``")
+	cmd.Flags().StringSliceVar(&c.flagMember, "member", nil, "Only list events of this cluster member, can be repeated"+"``")
+	cmd.Flags().StringVar(&c.flagSince, "since", "", "Only list events since this duration ago, timestamp or date"+"``")
+	cmd.Flags().StringVar(&c.flagUntil, "until", "", "Only list events until this duration ago, timestamp or date"+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand for listing events.
+func (c *cmdEventsList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	if c.flagType != "" && !slices.Contains(types.EventTypes, c.flagType) {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Unknown event type %q, must be one of %s", c.flagType, strings.Join(types.EventTypes, ", ")))
+	}
+
+	now := time.Now()
+	since, err := parseEventTime(c.flagSince, now)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	until, err := parseEventTime(c.flagUntil, now)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	events, err := cloudClient.GetEvents(cmd.Context(), client, c.flagType, since, until, types.ListFilter{Members: c.flagMember})
+	if err != nil {
+		return err
+	}
+
+	data := make([][]string, len(events))
+	for i, event := range events {
+		data[i] = []string{event.CreatedAt.Local().Format("2006-01-02 15:04:05"), event.Type, event.Member, event.Message}
+	}
+
+	header := []string{"TIME", "TYPE", "MEMBER", "MESSAGE"}
+	table, err := tui.FormatData(c.flagFormat, header, data, events)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
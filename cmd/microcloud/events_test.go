@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type eventsSuite struct {
+	suite.Suite
+}
+
+func TestEventsSuite(t *testing.T) {
+	suite.Run(t, new(eventsSuite))
+}
+
+func (s *eventsSuite) Test_parseEventTime() {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc      string
+		value     string
+		expectErr bool
+		expected  time.Time
+	}{
+		{
+			desc:     "Unset",
+			value:    "",
+			expected: time.Time{},
+		},
+		{
+			desc:     "Duration",
+			value:    "36h",
+			expected: time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "Timestamp",
+			value:    "2024-05-01T08:30:00Z",
+			expected: time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			desc:     "Date",
+			value:    "2024-05-01",
+			expected: time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local),
+		},
+		{
+			desc:      "Invalid",
+			value:     "yesterday",
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		t, err := parseEventTime(c.value, now)
+		if c.expectErr {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.True(c.expected.Equal(t), "expected %s, got %s", c.expected, t)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+// Exit codes returned by the microcloud command, see the exit codes reference in the documentation.
+const (
+	// ExitCodeError is returned for any failure without a more specific exit code.
+	ExitCodeError = 1
+
+	// ExitCodeUsage is returned if the command line arguments or flags are invalid.
+	ExitCodeUsage = 2
+
+	// ExitCodeValidation is returned if the supplied configuration, such as a preseed file, is invalid.
+	ExitCodeValidation = 3
+
+	// ExitCodeNotInitialized is returned if MicroCloud or one of its services is not yet initialized.
+	ExitCodeNotInitialized = 4
+
+	// ExitCodeAlreadyInitialized is returned if MicroCloud or one of its services is already initialized.
+	ExitCodeAlreadyInitialized = 5
+
+	// ExitCodeUnreachable is returned if a cluster member or service could not be reached.
+	ExitCodeUnreachable = 6
+
+	// ExitCodeTimeout is returned if an operation didn't complete in time.
+	ExitCodeTimeout = 7
+
+	// ExitCodeCancelled is returned if the command was interrupted by the user.
+	ExitCodeCancelled = 130
+)
+
+// exitCodeError is an error that makes the command exit with a specific exit code.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+// Error returns the error's message.
+func (e exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode associates the given exit code with the error.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return exitCodeError{code: code, err: err}
+}
+
+// exitCode returns the exit code matching the class of the given error.
+// Errors explicitly tagged with an exit code take precedence over the detected class.
+func exitCode(err error) int {
+	var codeErr exitCodeError
+	if errors.As(err, &codeErr) {
+		return codeErr.code
+	}
+
+	if errors.Is(err, tui.ContextError) || errors.Is(err, context.Canceled) {
+		return ExitCodeCancelled
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ExitCodeTimeout
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EHOSTUNREACH) || errors.Is(err, syscall.ENETUNREACH) {
+		return ExitCodeUnreachable
+	}
+
+	if api.StatusErrorCheck(err, http.StatusBadRequest) {
+		return ExitCodeValidation
+	}
+
+	return ExitCodeError
+}
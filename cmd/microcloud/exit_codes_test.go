@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+type exitCodesSuite struct {
+	suite.Suite
+}
+
+func TestExitCodesSuite(t *testing.T) {
+	suite.Run(t, new(exitCodesSuite))
+}
+
+func (s *exitCodesSuite) Test_exitCode() {
+	cases := []struct {
+		desc string
+		err  error
+		code int
+	}{
+		{
+			desc: "Generic error",
+			err:  errors.New("Something failed"),
+			code: ExitCodeError,
+		},
+		{
+			desc: "Tagged error wrapped in another error",
+			err:  fmt.Errorf("Failed to initialize: %w", withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is not initialized"))),
+			code: ExitCodeNotInitialized,
+		},
+		{
+			desc: "Tagged error takes precedence over detected class",
+			err:  withExitCode(ExitCodeValidation, context.DeadlineExceeded),
+			code: ExitCodeValidation,
+		},
+		{
+			desc: "Interrupted prompt",
+			err:  fmt.Errorf("Failed to ask: %w", tui.ContextError),
+			code: ExitCodeCancelled,
+		},
+		{
+			desc: "Deadline exceeded",
+			err:  fmt.Errorf("Failed to wait: %w", context.DeadlineExceeded),
+			code: ExitCodeTimeout,
+		},
+		{
+			desc: "Connection refused",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			code: ExitCodeUnreachable,
+		},
+		{
+			desc: "Bad request from the API",
+			err:  api.StatusErrorf(http.StatusBadRequest, "Invalid config"),
+			code: ExitCodeValidation,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.code, exitCode(c.err))
+	}
+
+	s.NoError(withExitCode(ExitCodeError, nil))
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"slices"
+
+	"github.com/canonical/lxd/shared"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// expectedMembersAllowJoin returns whether the joining system matches the address or certificate fingerprint
+// of the expected member with the same name, if any. Systems which aren't expected members are allowed to join.
+func (c *initConfig) expectedMembersAllowJoin(intent types.SessionJoinPost) bool {
+	index := slices.IndexFunc(c.expectedMembers, func(m service.ExpectedMember) bool { return m.Name == intent.Name })
+	if index < 0 {
+		return true
+	}
+
+	cert, err := shared.ParseCert([]byte(intent.Certificate))
+	if err != nil {
+		return false
+	}
+
+	return c.expectedMembers[index].Matches(intent.Name, intent.Address, shared.CertFingerprint(cert))
+}
+
+// pendingExpectedMembers returns the names of the expected members which haven't joined MicroCloud yet.
+func (c *initConfig) pendingExpectedMembers(ctx context.Context, sh *service.Handler) ([]string, error) {
+	if len(c.expectedMembers) == 0 {
+		return nil, nil
+	}
+
+	members, err := service.RetryValue(ctx, sh.RetryPolicy, func() (map[string]string, error) {
+		return sh.Services[types.MicroCloud].ClusterMembers(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+
+	return service.MissingMembers(c.expectedMembers, names), nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	lxcConfig "github.com/canonical/lxd/lxc/config"
+	"github.com/canonical/lxd/shared/validate"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultImageMirrorName is the name of the image remote of the mirror added in interactive mode.
+const DefaultImageMirrorName = "mirror"
+
+// lxcGlobalConfDir is the directory of the system-wide configuration of the LXD snap's client.
+// Remotes in there are available to all users of the system.
+const lxcGlobalConfDir = "/var/snap/lxd/common/global-conf"
+
+// ImageOptions represents the structure of the image options in the preseed yaml.
+type ImageOptions struct {
+	AutoUpdateInterval *int64        `yaml:"auto_update_interval"`
+	AutoUpdateCached   *bool         `yaml:"auto_update_cached"`
+	RemoteCacheExpiry  *int64        `yaml:"remote_cache_expiry"`
+	Remotes            []ImageRemote `yaml:"remotes"`
+}
+
+// ImageRemote represents the structure of an image remote in the preseed yaml.
+type ImageRemote struct {
+	Name     string `yaml:"name"`
+	URL      string `yaml:"url"`
+	Protocol string `yaml:"protocol"`
+}
+
+// validate validates the image options.
+func (o ImageOptions) validate() error {
+	if o.AutoUpdateInterval != nil && *o.AutoUpdateInterval < 0 {
+		return errors.New("Image auto update interval cannot be negative")
+	}
+
+	if o.RemoteCacheExpiry != nil && *o.RemoteCacheExpiry < 0 {
+		return errors.New("Image remote cache expiry cannot be negative")
+	}
+
+	names := make(map[string]bool, len(o.Remotes))
+	for _, remote := range o.Remotes {
+		if remote.Name == "" || strings.ContainsAny(remote.Name, ":/") {
+			return fmt.Errorf("Invalid image remote name %q", remote.Name)
+		}
+
+		_, ok := lxcConfig.StaticRemotes[remote.Name]
+		if ok {
+			return fmt.Errorf("Image remote %q is built into LXD and cannot be overridden", remote.Name)
+		}
+
+		if names[remote.Name] {
+			return fmt.Errorf("Image remote %q is specified more than once", remote.Name)
+		}
+
+		names[remote.Name] = true
+
+		err := validate.IsRequestURL(remote.URL)
+		if err != nil {
+			return fmt.Errorf("Invalid URL of image remote %q: %w", remote.Name, err)
+		}
+
+		if remote.Protocol != "" {
+			err = validate.IsOneOf("simplestreams", "lxd")(remote.Protocol)
+			if err != nil {
+				return fmt.Errorf("Invalid protocol of image remote %q: %w", remote.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// serverConfig returns the LXD server configuration for the image options.
+// Only the options which have been set are included, so LXD's defaults apply otherwise.
+func (o ImageOptions) serverConfig() map[string]string {
+	config := map[string]string{}
+	if o.AutoUpdateInterval != nil {
+		config["images.auto_update_interval"] = strconv.FormatInt(*o.AutoUpdateInterval, 10)
+	}
+
+	if o.AutoUpdateCached != nil {
+		config["images.auto_update_cached"] = strconv.FormatBool(*o.AutoUpdateCached)
+	}
+
+	if o.RemoteCacheExpiry != nil {
+		config["images.remote_cache_expiry"] = strconv.FormatInt(*o.RemoteCacheExpiry, 10)
+	}
+
+	return config
+}
+
+// lxcGlobalConfPath returns the path of the system-wide configuration file of the LXD client.
+func lxcGlobalConfPath() string {
+	dir := os.Getenv("LXD_GLOBAL_CONF")
+	if dir == "" {
+		dir = lxcGlobalConfDir
+	}
+
+	return filepath.Join(dir, "config.yml")
+}
+
+// writeImageRemotes adds the given image remotes to the system-wide configuration of the LXD client.
+// Existing remotes with the same name are replaced, other settings of the file are kept.
+func writeImageRemotes(remotes []ImageRemote) error {
+	if len(remotes) == 0 {
+		return nil
+	}
+
+	path := lxcGlobalConfPath()
+	conf := lxcConfig.Config{}
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("Failed to read LXD client configuration %q: %w", path, err)
+	}
+
+	err = yaml.Unmarshal(content, &conf)
+	if err != nil {
+		return fmt.Errorf("Failed to parse LXD client configuration %q: %w", path, err)
+	}
+
+	if conf.Remotes == nil {
+		conf.Remotes = map[string]lxcConfig.Remote{}
+	}
+
+	for _, remote := range remotes {
+		protocol := remote.Protocol
+		if protocol == "" {
+			protocol = "simplestreams"
+		}
+
+		conf.Remotes[remote.Name] = lxcConfig.Remote{Addr: remote.URL, Protocol: protocol, Public: true}
+	}
+
+	content, err = yaml.Marshal(conf)
+	if err != nil {
+		return fmt.Errorf("Failed to encode LXD client configuration: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return fmt.Errorf("Failed to create LXD client configuration directory: %w", err)
+	}
+
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to write LXD client configuration %q: %w", path, err)
+	}
+
+	return nil
+}
+
+// askImages asks whether to use an internal simplestreams mirror for images, for example on air-gapped sites.
+func (c *initConfig) askImages() error {
+	if !c.bootstrap {
+		return nil
+	}
+
+	wantsMirror, err := c.asker.AskBool("Would you like to use an internal image mirror?", false)
+	if err != nil {
+		return err
+	}
+
+	if !wantsMirror {
+		return nil
+	}
+
+	mirrorURL, err := c.asker.AskString("Specify the URL of the simplestreams image mirror", "", validate.IsRequestURL)
+	if err != nil {
+		return err
+	}
+
+	autoUpdate, err := c.asker.AskBool("Automatically update cached images from their image servers?", true)
+	if err != nil {
+		return err
+	}
+
+	c.images = ImageOptions{
+		AutoUpdateCached: &autoUpdate,
+		Remotes:          []ImageRemote{{Name: DefaultImageMirrorName, URL: mirrorURL, Protocol: "simplestreams"}},
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/units"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+// memberServiceReport is the state of a service on a single cluster member.
+type memberServiceReport struct {
+	Version string `json:"version" yaml:"version"`
+	Role    string `json:"role" yaml:"role"`
+	Status  string `json:"status" yaml:"status"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// memberOVNReport is the OVN chassis and gateway state of a cluster member.
+type memberOVNReport struct {
+	Units   []string `json:"units" yaml:"units"`
+	Chassis bool     `json:"chassis" yaml:"chassis"`
+
+	// Gateway is whether the member can host OVN gateways. MicroOVN enables every chassis as a gateway candidate.
+	Gateway bool `json:"gateway" yaml:"gateway"`
+}
+
+// memberReport is the detailed view of a single cluster member.
+type memberReport struct {
+	Name         string                                    `json:"name" yaml:"name"`
+	Address      string                                    `json:"address" yaml:"address"`
+	Platform     *types.Platform                           `json:"platform" yaml:"platform"`
+	Services     map[types.ServiceType]memberServiceReport `json:"services" yaml:"services"`
+	OSDs         []types.OSDState                          `json:"osds" yaml:"osds"`
+	CephUnits    []string                                  `json:"ceph_units" yaml:"ceph_units"`
+	OVN          memberOVNReport                           `json:"ovn" yaml:"ovn"`
+	Instances    int                                       `json:"instances" yaml:"instances"`
+	Resources    *types.MemberResources                    `json:"resources" yaml:"resources"`
+	Certificates []types.CertificateInfo                   `json:"certificates" yaml:"certificates"`
+	Errors       []string                                  `json:"errors" yaml:"errors"`
+}
+
+type cmdInspect struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to print a detailed report of a cluster member.
+func (c *cmdInspect) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect <name>",
+		Short: "Print a detailed report of a cluster member",
+		Long: `Print a detailed report of a cluster member
+
+Shows the role and version of each service on the member, its MicroCeph OSDs and units,
+its OVN chassis and gateway state, the number of LXD instances located on it,
+its resource usage and the fingerprints and expiry dates of its certificates.`,
+		RunE: c.run,
+
+		Annotations: map[string]string{contextAnnotation: "true"},
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (json|table|yaml)")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{tui.TableFormatJSON, tui.TableFormatTable, tui.TableFormatYAML}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// run runs the subcommand to print a detailed report of a cluster member.
+func (c *cmdInspect) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	if !slices.Contains([]string{tui.TableFormatJSON, tui.TableFormatTable, tui.TableFormatYAML}, c.flagFormat) {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid format (%s)", c.flagFormat))
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	statuses, err := cloudClient.GetStatus(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	var status *types.Status
+	for _, s := range statuses {
+		if s.Name == args[0] {
+			status = &s
+			break
+		}
+	}
+
+	if status == nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Cluster member %q not found", args[0]))
+	}
+
+	inspection, err := cloudClient.GetInspection(cmd.Context(), client.UseTarget(args[0]))
+	if err != nil {
+		return err
+	}
+
+	report := newMemberReport(*status, *inspection)
+	if c.flagFormat != tui.TableFormatTable {
+		out, err := tui.FormatData(c.flagFormat, nil, nil, report)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(out)
+
+		return nil
+	}
+
+	fmt.Print(report.render())
+
+	return nil
+}
+
+// newMemberReport combines the status of a cluster member with the inspection collected on the member itself.
+func newMemberReport(status types.Status, inspection types.MemberInspection) memberReport {
+	report := memberReport{
+		Name:         status.Name,
+		Address:      status.Address,
+		Platform:     status.Platform,
+		Services:     make(map[types.ServiceType]memberServiceReport, len(inspection.Services)),
+		OSDs:         inspection.OSDs,
+		CephUnits:    []string{},
+		OVN:          memberOVNReport{Units: []string{}},
+		Instances:    inspection.Instances,
+		Resources:    inspection.Resources,
+		Certificates: inspection.Certificates,
+		Errors:       inspection.Errors,
+	}
+
+	for serviceType, version := range inspection.Services {
+		report.Services[serviceType] = memberServiceReport{Version: version.Version, Error: version.Error}
+	}
+
+	for serviceType, members := range status.Clusters {
+		for _, member := range members {
+			if member.Name != status.Name {
+				continue
+			}
+
+			service := report.Services[serviceType]
+			service.Role = member.Role
+			service.Status = string(member.Status)
+			report.Services[serviceType] = service
+		}
+	}
+
+	for _, unit := range status.CephServices {
+		report.CephUnits = append(report.CephUnits, unit.Service)
+	}
+
+	for _, unit := range status.OVNServices {
+		report.OVN.Units = append(report.OVN.Units, unit.Service)
+		if unit.Service == "chassis" {
+			report.OVN.Chassis = true
+			report.OVN.Gateway = true
+		}
+	}
+
+	sort.Strings(report.CephUnits)
+	sort.Strings(report.OVN.Units)
+	sort.Slice(report.OSDs, func(i, j int) bool { return report.OSDs[i].OSD < report.OSDs[j].OSD })
+
+	return report
+}
+
+// render returns the report as a set of tables.
+func (r memberReport) render() string {
+	var b strings.Builder
+
+	yesNo := func(v bool) string {
+		if v {
+			return "yes"
+		}
+
+		return "no"
+	}
+
+	memory := "-"
+	cpus := "-"
+	if r.Resources != nil {
+		cpus = strconv.FormatUint(r.Resources.CPUs, 10)
+		memory = units.GetByteSizeStringIEC(int64(r.Resources.MemoryUsed), 2) + " / " + units.GetByteSizeStringIEC(int64(r.Resources.MemoryTotal), 2)
+	}
+
+	fmt.Fprintf(&b, " %s %s (%s)\n", tui.SetColor(tui.Bright, "Member:", true), r.Name, r.Address)
+	b.WriteString(tui.NewTable([]string{"Platform", "Instances", "CPUs", "Memory", "MicroCeph Units", "OVN Chassis", "OVN Gateway"}, [][]string{{formatPlatform(r.Platform), strconv.Itoa(r.Instances), cpus, memory, strings.Join(r.CephUnits, ","), yesNo(r.OVN.Chassis), yesNo(r.OVN.Gateway)}}))
+	b.WriteString("\n")
+
+	serviceTypes := make([]types.ServiceType, 0, len(r.Services))
+	for serviceType := range r.Services {
+		serviceTypes = append(serviceTypes, serviceType)
+	}
+
+	slices.Sort(serviceTypes)
+	serviceRows := make([][]string, 0, len(serviceTypes))
+	for _, serviceType := range serviceTypes {
+		service := r.Services[serviceType]
+		serviceRows = append(serviceRows, []string{string(serviceType), service.Version, service.Role, service.Status})
+	}
+
+	b.WriteString(tui.NewTable([]string{"Service", "Version", "Role", "Status"}, serviceRows))
+	b.WriteString("\n")
+
+	if len(r.OSDs) > 0 {
+		osdRows := make([][]string, 0, len(r.OSDs))
+		for _, osd := range r.OSDs {
+			state := tui.SuccessColor("running", true)
+			if !osd.Running {
+				state = tui.ErrorColor("down", true)
+			}
+
+			osdRows = append(osdRows, []string{strconv.FormatInt(osd.OSD, 10), osd.Path, state})
+		}
+
+		b.WriteString(tui.NewTable([]string{"OSD", "Path", "State"}, osdRows))
+		b.WriteString("\n")
+	}
+
+	if len(r.Certificates) > 0 {
+		certRows := make([][]string, 0, len(r.Certificates))
+		for _, cert := range r.Certificates {
+			certRows = append(certRows, []string{string(cert.Service), cert.Fingerprint[:min(len(cert.Fingerprint), 12)], cert.NotAfter.Format(time.DateOnly)})
+		}
+
+		b.WriteString(tui.NewTable([]string{"Certificate", "Fingerprint", "Expires"}, certRows))
+		b.WriteString("\n")
+	}
+
+	for _, e := range r.Errors {
+		fmt.Fprintf(&b, " %s %s\n", tui.WarningSymbol(), e)
+	}
+
+	return b.String()
+}
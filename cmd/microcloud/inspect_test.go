@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	ovnTypes "github.com/canonical/microovn/microovn/api/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type inspectSuite struct {
+	suite.Suite
+}
+
+func TestInspectSuite(t *testing.T) {
+	suite.Run(t, new(inspectSuite))
+}
+
+func (s *inspectSuite) Test_newMemberReport() {
+	genMember := func(name string, role string, status microTypes.MemberStatus) microTypes.ClusterMember {
+		return microTypes.ClusterMember{
+			ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: name},
+			Role:               role,
+			Status:             status,
+		}
+	}
+
+	status := types.Status{
+		Name:    "micro01",
+		Address: "10.0.0.1",
+		Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+			types.MicroCloud: {genMember("micro01", "voter", microTypes.MemberOnline), genMember("micro02", "spare", microTypes.MemberOnline)},
+			types.MicroCeph:  {genMember("micro02", "voter", microTypes.MemberOnline), genMember("micro01", "stand-by", microTypes.MemberUnreachable)},
+		},
+		CephServices: cephTypes.Services{{Service: "osd", Location: "micro01"}, {Service: "mon", Location: "micro01"}},
+		OVNServices:  ovnTypes.Services{{Service: "switch", Location: "micro01"}, {Service: "chassis", Location: "micro01"}},
+	}
+
+	inspection := types.MemberInspection{
+		Name: "micro01",
+		Services: map[types.ServiceType]types.ServiceVersion{
+			types.MicroCloud: {Version: "2.1.0"},
+			types.MicroCeph:  {Version: "19.2.0"},
+		},
+		OSDs:      []types.OSDState{{OSD: 4, Running: true}, {OSD: 1}},
+		Instances: 3,
+	}
+
+	report := newMemberReport(status, inspection)
+	s.Equal(memberServiceReport{Version: "2.1.0", Role: "voter", Status: string(microTypes.MemberOnline)}, report.Services[types.MicroCloud])
+	s.Equal(memberServiceReport{Version: "19.2.0", Role: "stand-by", Status: string(microTypes.MemberUnreachable)}, report.Services[types.MicroCeph])
+	s.Equal([]string{"mon", "osd"}, report.CephUnits)
+	s.Equal(memberOVNReport{Units: []string{"chassis", "switch"}, Chassis: true, Gateway: true}, report.OVN)
+	s.Equal(int64(1), report.OSDs[0].OSD)
+	s.Equal(3, report.Instances)
+
+	s.T().Log("A member without an OVN chassis can't host gateways")
+	status.OVNServices = ovnTypes.Services{{Service: "central", Location: "micro01"}}
+	report = newMemberReport(status, inspection)
+	s.False(report.OVN.Gateway)
+	s.NotEmpty(report.render())
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// instanceCounts is the number of instances in each state.
+type instanceCounts struct {
+	Running int `json:"running" yaml:"running"`
+	Stopped int `json:"stopped" yaml:"stopped"`
+	Other   int `json:"other" yaml:"other"`
+	Total   int `json:"total" yaml:"total"`
+}
+
+// add counts an instance with the given status.
+func (c *instanceCounts) add(status string) {
+	switch status {
+	case "Running":
+		c.Running++
+	case "Stopped":
+		c.Stopped++
+	default:
+		c.Other++
+	}
+
+	c.Total++
+}
+
+// row returns the counts as table cells.
+func (c instanceCounts) row() []string {
+	return []string{strconv.Itoa(c.Running), strconv.Itoa(c.Stopped), strconv.Itoa(c.Other), strconv.Itoa(c.Total)}
+}
+
+// instanceMemberSummary is the number of instances located on a cluster member.
+type instanceMemberSummary struct {
+	instanceCounts `yaml:",inline"`
+
+	// Status of the LXD cluster member, such as "Evacuated".
+	Status string `json:"status" yaml:"status"`
+}
+
+// strayInstance is a running instance located on an evacuated cluster member.
+type strayInstance struct {
+	Name     string `json:"name" yaml:"name"`
+	Project  string `json:"project" yaml:"project"`
+	Location string `json:"location" yaml:"location"`
+}
+
+// instanceSummary is the cluster-wide overview of the LXD instances.
+type instanceSummary struct {
+	Members  map[string]instanceMemberSummary `json:"members" yaml:"members"`
+	Projects map[string]instanceCounts        `json:"projects" yaml:"projects"`
+	Stray    []strayInstance                  `json:"stray" yaml:"stray"`
+	Total    instanceCounts                   `json:"total" yaml:"total"`
+}
+
+type cmdInstances struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to print an overview of the instances across the cluster.
+func (c *cmdInstances) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instances",
+		Short: "Print an overview of the instances across the cluster",
+		Long: `Print an overview of the instances across the cluster
+
+Counts the LXD instances of all projects per cluster member, per project and per state.
+Instances which are still running on an evacuated cluster member are listed as stray.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (json|table|yaml)")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{tui.TableFormatJSON, tui.TableFormatTable, tui.TableFormatYAML}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// run runs the subcommand to print an overview of the instances across the cluster.
+func (c *cmdInstances) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	if !slices.Contains([]string{tui.TableFormatJSON, tui.TableFormatTable, tui.TableFormatYAML}, c.flagFormat) {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid format (%s)", c.flagFormat))
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.MicroCloud, types.LXD)
+	if err != nil {
+		return err
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(context.Background())
+	if err != nil {
+		return err
+	}
+
+	members, err := lxdClient.GetClusterMembers()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD cluster members: %w", err)
+	}
+
+	instances, err := lxdClient.GetInstancesAllProjects(lxdAPI.InstanceTypeAny)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD instances: %w", err)
+	}
+
+	summary := summarizeInstances(members, instances)
+	if c.flagFormat != tui.TableFormatTable {
+		out, err := tui.FormatData(c.flagFormat, nil, nil, summary)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(out)
+
+		return nil
+	}
+
+	fmt.Print(summary.render())
+
+	return nil
+}
+
+// summarizeInstances counts the instances per cluster member and project, and finds the running instances on evacuated members.
+func summarizeInstances(members []lxdAPI.ClusterMember, instances []lxdAPI.Instance) instanceSummary {
+	summary := instanceSummary{
+		Members:  make(map[string]instanceMemberSummary, len(members)),
+		Projects: map[string]instanceCounts{},
+		Stray:    []strayInstance{},
+	}
+
+	for _, member := range members {
+		summary.Members[member.ServerName] = instanceMemberSummary{Status: member.Status}
+	}
+
+	for _, instance := range instances {
+		member := summary.Members[instance.Location]
+		member.add(instance.Status)
+		summary.Members[instance.Location] = member
+
+		project := summary.Projects[instance.Project]
+		project.add(instance.Status)
+		summary.Projects[instance.Project] = project
+
+		summary.Total.add(instance.Status)
+
+		if member.Status == "Evacuated" && instance.Status == "Running" {
+			summary.Stray = append(summary.Stray, strayInstance{Name: instance.Name, Project: instance.Project, Location: instance.Location})
+		}
+	}
+
+	sort.Slice(summary.Stray, func(i, j int) bool {
+		if summary.Stray[i].Location != summary.Stray[j].Location {
+			return summary.Stray[i].Location < summary.Stray[j].Location
+		}
+
+		if summary.Stray[i].Project != summary.Stray[j].Project {
+			return summary.Stray[i].Project < summary.Stray[j].Project
+		}
+
+		return summary.Stray[i].Name < summary.Stray[j].Name
+	})
+
+	return summary
+}
+
+// render returns the summary as tables, followed by a warning for each stray instance.
+func (s instanceSummary) render() string {
+	var b strings.Builder
+
+	memberNames := make([]string, 0, len(s.Members))
+	for name := range s.Members {
+		memberNames = append(memberNames, name)
+	}
+
+	sort.Strings(memberNames)
+	memberRows := make([][]string, 0, len(memberNames)+1)
+	for _, name := range memberNames {
+		member := s.Members[name]
+		memberRows = append(memberRows, append([]string{name, member.Status}, member.row()...))
+	}
+
+	memberRows = append(memberRows, append([]string{"TOTAL", ""}, s.Total.row()...))
+	b.WriteString(tui.NewTable([]string{"Member", "Status", "Running", "Stopped", "Other", "Total"}, memberRows))
+	b.WriteString("\n")
+
+	projectNames := make([]string, 0, len(s.Projects))
+	for name := range s.Projects {
+		projectNames = append(projectNames, name)
+	}
+
+	sort.Strings(projectNames)
+	projectRows := make([][]string, 0, len(projectNames))
+	for _, name := range projectNames {
+		projectRows = append(projectRows, append([]string{name}, s.Projects[name].row()...))
+	}
+
+	b.WriteString(tui.NewTable([]string{"Project", "Running", "Stopped", "Other", "Total"}, projectRows))
+	b.WriteString("\n")
+
+	for _, stray := range s.Stray {
+		fmt.Fprintf(&b, " %s Instance %q of project %q is still running on evacuated member %q\n", tui.WarningSymbol(), stray.Name, stray.Project, stray.Location)
+	}
+
+	return b.String()
+}
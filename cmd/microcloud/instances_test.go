@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type instancesSuite struct {
+	suite.Suite
+}
+
+func TestInstancesSuite(t *testing.T) {
+	suite.Run(t, new(instancesSuite))
+}
+
+func (s *instancesSuite) Test_summarizeInstances() {
+	members := []lxdAPI.ClusterMember{
+		{ServerName: "micro01", Status: "Online"},
+		{ServerName: "micro02", Status: "Evacuated"},
+		{ServerName: "micro03", Status: "Online"},
+	}
+
+	instances := []lxdAPI.Instance{
+		{Name: "c1", Project: "default", Location: "micro01", Status: "Running"},
+		{Name: "c2", Project: "default", Location: "micro01", Status: "Stopped"},
+		{Name: "v1", Project: "prod", Location: "micro02", Status: "Running"},
+		{Name: "v2", Project: "prod", Location: "micro02", Status: "Stopped"},
+		{Name: "c3", Project: "prod", Location: "micro01", Status: "Frozen"},
+	}
+
+	summary := summarizeInstances(members, instances)
+	s.Equal(instanceMemberSummary{Status: "Online", instanceCounts: instanceCounts{Running: 1, Stopped: 1, Other: 1, Total: 3}}, summary.Members["micro01"])
+	s.Equal(instanceMemberSummary{Status: "Evacuated", instanceCounts: instanceCounts{Running: 1, Stopped: 1, Total: 2}}, summary.Members["micro02"])
+	s.Equal(instanceMemberSummary{Status: "Online"}, summary.Members["micro03"])
+	s.Equal(instanceCounts{Running: 1, Stopped: 1, Total: 2}, summary.Projects["default"])
+	s.Equal(instanceCounts{Running: 1, Stopped: 1, Other: 1, Total: 3}, summary.Projects["prod"])
+	s.Equal(instanceCounts{Running: 2, Stopped: 2, Other: 1, Total: 5}, summary.Total)
+
+	s.T().Log("Only running instances on evacuated members are stray")
+	s.Equal([]strayInstance{{Name: "v1", Project: "prod", Location: "micro02"}}, summary.Stray)
+	s.Contains(summary.render(), "evacuated member \"micro02\"")
+}
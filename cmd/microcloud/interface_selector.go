@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+)
+
+// InterfaceSelector selects an interface of a system by its hardware rather than by its name,
+// as the predictable names of the interfaces differ across hardware generations.
+// An interface must match all the set criteria.
+type InterfaceSelector struct {
+	// MAC is the MAC address of the interface.
+	MAC string `yaml:"mac"`
+
+	// PCIAddress is the PCI address of the card of the interface, such as 0000:03:00.0.
+	PCIAddress string `yaml:"pci_address"`
+
+	// Driver is the kernel driver of the card of the interface, such as ixgbe.
+	Driver string `yaml:"driver"`
+
+	// MinSpeed is the minimum link speed of the interface in Mbit/s, such as 10000 for 10G.
+	MinSpeed uint64 `yaml:"min_speed"`
+
+	// Fastest selects the interface with the highest link speed when several interfaces match.
+	Fastest bool `yaml:"fastest"`
+}
+
+// validate returns an error if the selector sets no criteria or an invalid one.
+func (s InterfaceSelector) validate() error {
+	if s.MAC == "" && s.PCIAddress == "" && s.Driver == "" && s.MinSpeed == 0 && !s.Fastest {
+		return errors.New("Must set at least one of mac, pci_address, driver, min_speed or fastest")
+	}
+
+	if s.MAC != "" {
+		_, err := net.ParseMAC(s.MAC)
+		if err != nil {
+			return fmt.Errorf("Invalid MAC address %q", s.MAC)
+		}
+	}
+
+	return nil
+}
+
+// String returns the set criteria of the selector.
+func (s InterfaceSelector) String() string {
+	criteria := []string{}
+	if s.MAC != "" {
+		criteria = append(criteria, "mac="+s.MAC)
+	}
+
+	if s.PCIAddress != "" {
+		criteria = append(criteria, "pci_address="+s.PCIAddress)
+	}
+
+	if s.Driver != "" {
+		criteria = append(criteria, "driver="+s.Driver)
+	}
+
+	if s.MinSpeed > 0 {
+		criteria = append(criteria, fmt.Sprintf("min_speed=%d", s.MinSpeed))
+	}
+
+	if s.Fastest {
+		criteria = append(criteria, "fastest")
+	}
+
+	return strings.Join(criteria, ", ")
+}
+
+// matches returns whether the port of the given card matches the criteria of the selector.
+func (s InterfaceSelector) matches(card lxdAPI.ResourcesNetworkCard, port lxdAPI.ResourcesNetworkCardPort) bool {
+	if s.MAC != "" {
+		mac, _ := net.ParseMAC(s.MAC)
+		portMAC, err := net.ParseMAC(port.Address)
+		if err != nil || mac.String() != portMAC.String() {
+			return false
+		}
+	}
+
+	if s.PCIAddress != "" && !strings.EqualFold(s.PCIAddress, card.PCIAddress) {
+		return false
+	}
+
+	if s.Driver != "" && s.Driver != card.Driver {
+		return false
+	}
+
+	return port.LinkSpeed >= s.MinSpeed
+}
+
+// selectInterface returns the name of the interface matching the selector among the given available interfaces of a system.
+// Several matching interfaces are an error, unless the fastest one is selected, in which case ties go to the first name.
+func (s InterfaceSelector) selectInterface(resources *lxdAPI.Resources, available map[string]lxdAPI.Network) (string, error) {
+	if resources == nil {
+		return "", errors.New("The network cards of the system are unknown")
+	}
+
+	matched := []lxdAPI.ResourcesNetworkCardPort{}
+	for _, card := range resources.Network.Cards {
+		for _, port := range card.Ports {
+			_, ok := available[port.ID]
+			if ok && s.matches(card, port) {
+				matched = append(matched, port)
+			}
+		}
+	}
+
+	if len(matched) == 0 {
+		return "", fmt.Errorf("No available interface matches selector %q", s)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if s.Fastest && matched[i].LinkSpeed != matched[j].LinkSpeed {
+			return matched[i].LinkSpeed > matched[j].LinkSpeed
+		}
+
+		return matched[i].ID < matched[j].ID
+	})
+
+	if len(matched) > 1 && !s.Fastest {
+		names := make([]string, 0, len(matched))
+		for _, port := range matched {
+			names = append(names, port.ID)
+		}
+
+		return "", fmt.Errorf("Several available interfaces match selector %q (%s), narrow it down or select the fastest", s, strings.Join(names, ", "))
+	}
+
+	return matched[0].ID, nil
+}
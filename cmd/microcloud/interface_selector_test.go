@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type interfaceSelectorSuite struct {
+	suite.Suite
+}
+
+func TestInterfaceSelectorSuite(t *testing.T) {
+	suite.Run(t, new(interfaceSelectorSuite))
+}
+
+func (s *interfaceSelectorSuite) Test_validate() {
+	s.Error(InterfaceSelector{}.validate())
+	s.Error(InterfaceSelector{MAC: "00:16:3e"}.validate())
+	s.NoError(InterfaceSelector{MAC: "00:16:3E:00:00:01"}.validate())
+	s.NoError(InterfaceSelector{Fastest: true}.validate())
+}
+
+func (s *interfaceSelectorSuite) Test_selectInterface() {
+	resources := &lxdAPI.Resources{
+		Network: lxdAPI.ResourcesNetwork{
+			Cards: []lxdAPI.ResourcesNetworkCard{
+				{
+					Driver:     "igb",
+					PCIAddress: "0000:01:00.0",
+					Ports: []lxdAPI.ResourcesNetworkCardPort{
+						{ID: "eno1", Address: "00:16:3e:00:00:01", LinkSpeed: 1000},
+						{ID: "eno2", Address: "00:16:3e:00:00:02", LinkSpeed: 1000},
+					},
+				},
+				{
+					Driver:     "ixgbe",
+					PCIAddress: "0000:03:00.0",
+					Ports: []lxdAPI.ResourcesNetworkCardPort{
+						{ID: "enp3s0f0", Address: "00:16:3e:00:00:03", LinkSpeed: 10000},
+						{ID: "enp3s0f1", Address: "00:16:3e:00:00:04", LinkSpeed: 10000},
+					},
+				},
+				{
+					Driver:     "mlx5_core",
+					PCIAddress: "0000:04:00.0",
+					Ports: []lxdAPI.ResourcesNetworkCardPort{
+						{ID: "enp4s0np0", Address: "00:16:3e:00:00:05", LinkSpeed: 25000},
+					},
+				},
+			},
+		},
+	}
+
+	// The first port of each card is used by the system.
+	available := map[string]lxdAPI.Network{"eno2": {}, "enp3s0f1": {}, "enp4s0np0": {}}
+
+	cases := []struct {
+		desc     string
+		selector InterfaceSelector
+		iface    string
+		err      bool
+	}{
+		{
+			desc:     "MAC address",
+			selector: InterfaceSelector{MAC: "00:16:3E:00:00:02"},
+			iface:    "eno2",
+		},
+		{
+			desc:     "PCI address",
+			selector: InterfaceSelector{PCIAddress: "0000:03:00.0"},
+			iface:    "enp3s0f1",
+		},
+		{
+			desc:     "Driver",
+			selector: InterfaceSelector{Driver: "mlx5_core"},
+			iface:    "enp4s0np0",
+		},
+		{
+			desc:     "Fastest 10G+ interface",
+			selector: InterfaceSelector{MinSpeed: 10000, Fastest: true},
+			iface:    "enp4s0np0",
+		},
+		{
+			desc:     "Fastest interface of a driver",
+			selector: InterfaceSelector{Driver: "igb", Fastest: true},
+			iface:    "eno2",
+		},
+		{
+			desc:     "Several matching interfaces",
+			selector: InterfaceSelector{MinSpeed: 10000},
+			err:      true,
+		},
+		{
+			desc:     "Unavailable interface",
+			selector: InterfaceSelector{MAC: "00:16:3e:00:00:01"},
+			err:      true,
+		},
+		{
+			desc:     "No interface fast enough",
+			selector: InterfaceSelector{Driver: "igb", MinSpeed: 10000},
+			err:      true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		iface, err := c.selector.selectInterface(resources, available)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.iface, iface)
+	}
+
+	_, err := InterfaceSelector{Fastest: true}.selectInterface(nil, available)
+	s.Error(err)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// InventoryFormatAnsible is the Ansible dynamic inventory format of the inventory command.
+const InventoryFormatAnsible = "ansible"
+
+// inventoryGroupRegex matches the characters which are not allowed in Ansible group names.
+var inventoryGroupRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+type cmdInventory struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// ansibleGroup is a group of hosts in an Ansible dynamic inventory.
+type ansibleGroup struct {
+	Hosts    []string `json:"hosts,omitempty"`
+	Children []string `json:"children,omitempty"`
+}
+
+// command returns the subcommand to print the inventory of the MicroCloud cluster.
+func (c *cmdInventory) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Print the cluster members for configuration management tools",
+		Long: `Print the cluster members for configuration management tools
+
+The "ansible" format prints an Ansible dynamic inventory in JSON.
+Hosts are grouped by service (e.g. "microceph"), by service role (e.g. "microcloud_voter", "lxd_database_leader",
+"microceph_mon" or "microovn_central"), by LXD cluster group (e.g. "lxd_group_default")
+and by LXD failure domain (e.g. "zone_default").`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", InventoryFormatAnsible, "Format (ansible)")
+
+	return cmd
+}
+
+// run runs the subcommand to print the inventory of the MicroCloud cluster.
+func (c *cmdInventory) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	if c.flagFormat != InventoryFormatAnsible {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid format (%s)", c.flagFormat))
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.MicroCloud, types.LXD)
+	if err != nil {
+		return err
+	}
+
+	microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	statuses, err := cloudClient.GetStatus(context.Background(), microClient)
+	if err != nil {
+		return err
+	}
+
+	lxd := sh.Services[types.LXD].(*service.LXDService)
+	lxdClient, err := lxd.Client(context.Background())
+	if err != nil {
+		return err
+	}
+
+	lxdMembers, err := lxdClient.GetClusterMembers()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD cluster members: %w", err)
+	}
+
+	inventory := ansibleInventory(status.Name, statuses, lxdMembers)
+	out, err := json.MarshalIndent(inventory, "", "    ")
+	if err != nil {
+		return fmt.Errorf("Failed to format the inventory: %w", err)
+	}
+
+	fmt.Println(string(out))
+
+	return nil
+}
+
+// inventoryGroupName returns a valid Ansible group name made of the given parts.
+func inventoryGroupName(parts ...string) string {
+	name := strings.ToLower(strings.Join(parts, "_"))
+
+	return strings.Trim(inventoryGroupRegex.ReplaceAllString(name, "_"), "_")
+}
+
+// ansibleInventory returns the Ansible dynamic inventory of the cluster from the status of the local member
+// named localName, which also contains the status of all other members, and the LXD cluster members.
+func ansibleInventory(localName string, statuses []types.Status, lxdMembers []lxdAPI.ClusterMember) map[string]any {
+	groups := map[string]*ansibleGroup{}
+	hostVars := map[string]map[string]any{}
+
+	addHost := func(group string, host string) {
+		g, ok := groups[group]
+		if !ok {
+			g = &ansibleGroup{}
+			groups[group] = g
+		}
+
+		if !slices.Contains(g.Hosts, host) {
+			g.Hosts = append(g.Hosts, host)
+		}
+	}
+
+	vars := func(host string) map[string]any {
+		v, ok := hostVars[host]
+		if !ok {
+			v = map[string]any{}
+			hostVars[host] = v
+		}
+
+		return v
+	}
+
+	for _, status := range statuses {
+		if status.Name == localName {
+			// The cluster members of the local status are shared by all members, except for LXD which is handled below.
+			for serviceType, members := range status.Clusters {
+				if serviceType == types.LXD {
+					continue
+				}
+
+				serviceName := strings.ToLower(string(serviceType))
+				for _, member := range members {
+					addHost(serviceName, member.Name)
+					if member.Role != "" {
+						addHost(inventoryGroupName(serviceName, member.Role), member.Name)
+						vars(member.Name)[serviceName+"_role"] = member.Role
+					}
+
+					if serviceType == types.MicroCloud {
+						vars(member.Name)["ansible_host"] = member.Address.Addr().String()
+					}
+				}
+			}
+		}
+
+		cephServices := make([]string, 0, len(status.CephServices))
+		for _, s := range status.CephServices {
+			addHost(inventoryGroupName("microceph", s.Service), status.Name)
+			cephServices = append(cephServices, s.Service)
+		}
+
+		if len(cephServices) > 0 {
+			sort.Strings(cephServices)
+			vars(status.Name)["microceph_services"] = cephServices
+		}
+
+		ovnServices := make([]string, 0, len(status.OVNServices))
+		for _, s := range status.OVNServices {
+			addHost(inventoryGroupName("microovn", string(s.Service)), status.Name)
+			ovnServices = append(ovnServices, string(s.Service))
+		}
+
+		if len(ovnServices) > 0 {
+			sort.Strings(ovnServices)
+			vars(status.Name)["microovn_services"] = ovnServices
+		}
+	}
+
+	for _, member := range lxdMembers {
+		addHost("lxd", member.ServerName)
+		for _, role := range member.Roles {
+			addHost(inventoryGroupName("lxd", role), member.ServerName)
+		}
+
+		for _, group := range member.Groups {
+			addHost(inventoryGroupName("lxd_group", group), member.ServerName)
+		}
+
+		if member.FailureDomain != "" {
+			addHost(inventoryGroupName("zone", member.FailureDomain), member.ServerName)
+		}
+
+		v := vars(member.ServerName)
+		v["lxd_roles"] = member.Roles
+		v["lxd_groups"] = member.Groups
+		v["lxd_failure_domain"] = member.FailureDomain
+	}
+
+	inventory := map[string]any{
+		"_meta": map[string]any{"hostvars": hostVars},
+	}
+
+	children := make([]string, 0, len(groups))
+	for name, group := range groups {
+		sort.Strings(group.Hosts)
+		inventory[name] = group
+		children = append(children, name)
+	}
+
+	sort.Strings(children)
+	inventory["all"] = ansibleGroup{Children: children}
+
+	return inventory
+}
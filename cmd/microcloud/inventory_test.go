@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type inventorySuite struct {
+	suite.Suite
+}
+
+func TestInventorySuite(t *testing.T) {
+	suite.Run(t, new(inventorySuite))
+}
+
+func (s *inventorySuite) Test_ansibleInventory() {
+	genMember := func(name string, address string, role string) microTypes.ClusterMember {
+		addrPort, err := microTypes.ParseAddrPort(address)
+		s.Require().NoError(err)
+
+		return microTypes.ClusterMember{
+			ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: name, Address: addrPort},
+			Role:               role,
+		}
+	}
+
+	statuses := []types.Status{
+		{
+			Name: "micro01",
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+				types.MicroCloud: {genMember("micro01", "10.0.0.1:9443", "voter"), genMember("micro02", "10.0.0.2:9443", "spare")},
+				types.MicroCeph:  {genMember("micro01", "10.0.0.1:7443", "voter")},
+				types.LXD:        {genMember("micro01", "10.0.0.1:8443", "database")},
+			},
+			CephServices: cephTypes.Services{{Service: "osd", Location: "micro01"}, {Service: "mon", Location: "micro01"}},
+		},
+		{
+			Name: "micro02",
+		},
+	}
+
+	lxdMembers := []lxdAPI.ClusterMember{
+		{ServerName: "micro01", Roles: []string{"database-leader", "database"}, FailureDomain: "rack-1", Groups: []string{"default"}},
+		{ServerName: "micro02", FailureDomain: "default", Groups: []string{"default"}},
+	}
+
+	inventory := ansibleInventory("micro01", statuses, lxdMembers)
+
+	expectedGroups := map[string][]string{
+		"microcloud":          {"micro01", "micro02"},
+		"microcloud_voter":    {"micro01"},
+		"microcloud_spare":    {"micro02"},
+		"microceph":           {"micro01"},
+		"microceph_voter":     {"micro01"},
+		"microceph_osd":       {"micro01"},
+		"microceph_mon":       {"micro01"},
+		"lxd":                 {"micro01", "micro02"},
+		"lxd_database_leader": {"micro01"},
+		"lxd_database":        {"micro01"},
+		"lxd_group_default":   {"micro01", "micro02"},
+		"zone_rack_1":         {"micro01"},
+		"zone_default":        {"micro02"},
+	}
+
+	children := []string{}
+	for name, hosts := range expectedGroups {
+		group, ok := inventory[name].(*ansibleGroup)
+		s.Require().True(ok, name)
+		s.Equal(hosts, group.Hosts, name)
+		children = append(children, name)
+	}
+
+	s.ElementsMatch(children, inventory["all"].(ansibleGroup).Children)
+
+	hostVars := inventory["_meta"].(map[string]any)["hostvars"].(map[string]map[string]any)
+	s.Equal("10.0.0.1", hostVars["micro01"]["ansible_host"])
+	s.Equal("10.0.0.2", hostVars["micro02"]["ansible_host"])
+	s.Equal("voter", hostVars["micro01"]["microcloud_role"])
+	s.Equal([]string{"mon", "osd"}, hostVars["micro01"]["microceph_services"])
+	s.Equal("rack-1", hostVars["micro01"]["lxd_failure_domain"])
+}
@@ -2,24 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/spf13/cobra"
 
 	"github.com/canonical/microcloud/microcloud/api"
 	"github.com/canonical/microcloud/microcloud/api/types"
 	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
 	"github.com/canonical/microcloud/microcloud/service"
 )
 
+// followJoinInterval is the interval between checks of the join progress when following it.
+const followJoinInterval = time.Second
+
 type cmdJoin struct {
 	common *CmdControl
 
 	flagLookupTimeout    int64
 	flagSessionTimeout   int64
 	flagInitiatorAddress string
+	flagFollow           bool
+	flagAbort            bool
 }
 
 // command returns the subcommand for joining a MicroCloud.
@@ -27,12 +37,21 @@ func (c *cmdJoin) command() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "join",
 		Short: "Join an existing MicroCloud cluster",
-		RunE:  c.run,
+		Long: `Join an existing MicroCloud cluster
+
+If the join was interrupted, for example because the initiator stopped while setting up this system,
+run "microcloud join --abort" to remove this system from the services it partially joined, so it can join again.
+The abort is refused unless this system has a pending or failed join, and asks for confirmation.
+It resets MicroCeph, MicroOVN and MicroCloud on this system, which clears the trust they got from the cluster,
+but doesn't revoke the join tokens the initiator issued for this system.`,
+		RunE: c.run,
 	}
 
 	cmd.Flags().Int64Var(&c.flagLookupTimeout, "lookup-timeout", 0, "Amount of seconds to wait when finding systems on the network. Defaults: 60s")
 	cmd.Flags().Int64Var(&c.flagSessionTimeout, "session-timeout", 0, "Amount of seconds to wait for the trust establishment session. Defaults: 10m")
 	cmd.Flags().StringVar(&c.flagInitiatorAddress, "initiator-address", "", "Address of the trust establishment session's initiator")
+	cmd.Flags().BoolVar(&c.flagFollow, "follow", false, "Follow the progress of joining each service until this system joined all of them")
+	cmd.Flags().BoolVar(&c.flagAbort, "abort", false, "Abort an interrupted join, removing this system from the services it partially joined")
 
 	return cmd
 }
@@ -43,6 +62,10 @@ func (c *cmdJoin) run(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	}
 
+	if c.flagAbort {
+		return c.abort(cmd.Context())
+	}
+
 	fmt.Println("Waiting for services to start ...")
 	err := checkInitialized(c.common.FlagMicroCloudDir, false, false)
 	if err != nil {
@@ -98,14 +121,9 @@ func (c *cmdJoin) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	services := make(map[types.ServiceType]string, len(installedServices))
-	for _, s := range s.Services {
-		version, err := s.GetVersion(context.Background())
-		if err != nil {
-			return err
-		}
-
-		services[s.Type()] = version
+	services, err := s.GetVersions(context.Background())
+	if err != nil {
+		return err
 	}
 
 	passphrase, err := cfg.askPassphrase()
@@ -113,7 +131,238 @@ func (c *cmdJoin) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return cfg.runSession(context.Background(), s, types.SessionJoining, cfg.sessionTimeout, func(gw *cloudClient.WebsocketGateway) error {
+	err = cfg.runSession(context.Background(), s, types.SessionJoining, cfg.sessionTimeout, func(gw *cloudClient.WebsocketGateway) error {
 		return cfg.joiningSession(gw, s, services, c.flagInitiatorAddress, passphrase)
 	})
+	if err != nil || !c.flagFollow {
+		return err
+	}
+
+	return followJoin(context.Background(), s)
+}
+
+// abortableJoins returns the services this system is known not to have finished joining,
+// from the join progress of its daemon and the join states recorded in the MicroCloud database.
+// If both know of a service, the most recent state is used.
+func abortableJoins(progress []types.JoinState, joinStates []types.JoinState) []types.JoinState {
+	latest := map[types.ServiceType]types.JoinState{}
+	for _, state := range append(slices.Clone(joinStates), progress...) {
+		known, ok := latest[state.Service]
+		if !ok || !state.UpdatedAt.Before(known.UpdatedAt) {
+			latest[state.Service] = state
+		}
+	}
+
+	states := make([]types.JoinState, 0, len(latest))
+	for _, state := range latest {
+		states = append(states, state)
+	}
+
+	return unfinishedJoins(states)
+}
+
+// abort removes this system from the services it partially joined, in the reverse order of the joins,
+// stops any trust establishment session, and resets the services on this system, so it can join again.
+// It refuses to run unless the join progress or the recorded join states show a pending or failed join.
+func (c *cmdJoin) abort(ctx context.Context) error {
+	name, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve system hostname: %w", err)
+	}
+
+	installedServices := []types.ServiceType{types.MicroCloud, types.LXD}
+	for serviceType, stateDir := range addableServices {
+		if service.Exists(serviceType, stateDir) {
+			installedServices = append(installedServices, serviceType)
+		}
+	}
+
+	sh, err := service.NewHandler(name, "", c.common.FlagMicroCloudDir, installedServices...)
+	if err != nil {
+		return err
+	}
+
+	client, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	// The join progress is only known by the daemon until it restarts, and the join states are only recorded once this system joined MicroCloud.
+	progress, err := cloudClient.GetJoinProgress(ctx, client)
+	if err != nil {
+		logger.Debug("Failed to get the join progress", logger.Ctx{"err": err})
+	}
+
+	joinStates, err := cloudClient.GetJoinStates(ctx, client, name)
+	if err != nil {
+		logger.Debug("Failed to get the join states", logger.Ctx{"err": err})
+	}
+
+	unfinished := abortableJoins(progress, joinStates)
+	if len(unfinished) == 0 {
+		return withExitCode(ExitCodeValidation, errors.New(`There is no unfinished join of this system to abort, remove it with "microcloud remove" on a cluster member instead`))
+	}
+
+	joined := []types.ServiceType{}
+	for _, serviceType := range []types.ServiceType{types.LXD, types.MicroOVN, types.MicroCeph, types.MicroCloud} {
+		s := sh.Services[serviceType]
+		if s == nil {
+			continue
+		}
+
+		// Services which aren't set up on this system can't list their members.
+		members, err := s.ClusterMembers(ctx)
+		if err != nil || members[name] == "" {
+			continue
+		}
+
+		joined = append(joined, serviceType)
+	}
+
+	pending := make([]string, 0, len(unfinished))
+	for _, state := range unfinished {
+		pending = append(pending, fmt.Sprintf("%s (%s)", state.Service, state.Status))
+	}
+
+	fmt.Printf("This system didn't finish joining %s.\n", strings.Join(pending, ", "))
+	question := "Reset the services on this system so it can join again?"
+	if len(joined) > 0 {
+		question = fmt.Sprintf("Remove this system from %s and reset the services on it so it can join again?", joinServiceNames(joined))
+	}
+
+	confirm, err := c.common.asker.AskBool(question, false)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		return withExitCode(ExitCodeCancelled, errors.New("Join abort cancelled"))
+	}
+
+	// The session is not running anymore if the initiator stopped it.
+	_ = cloudClient.StopSession(ctx, client, "Join aborted on "+name)
+
+	errs := []error{}
+	for _, serviceType := range joined {
+		err = sh.Services[serviceType].DeleteClusterMember(ctx, name, true)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed to remove this system from %s: %w", serviceType, err))
+			continue
+		}
+
+		fmt.Println(tui.SummarizeResult("Removed this system from %s", serviceType))
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// Resetting the services clears the trust stores and certificates this system got from the cluster.
+	for _, serviceType := range []types.ServiceType{types.MicroCeph, types.MicroOVN, types.MicroCloud} {
+		var reset func(ctx context.Context) error
+		switch s := sh.Services[serviceType].(type) {
+		case *service.CephService:
+			reset = s.ResetClusterMember
+		case *service.OVNService:
+			reset = s.ResetClusterMember
+		case *service.CloudService:
+			reset = s.ResetClusterMember
+		default:
+			continue
+		}
+
+		initialized, err := sh.Services[serviceType].IsInitialized(ctx)
+		if err != nil || !initialized {
+			continue
+		}
+
+		err = reset(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to reset %s: %w", serviceType, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Reset %s", serviceType))
+	}
+
+	fmt.Println(tui.SummarizeResult("Aborted the join of %s, it can join again", name))
+
+	return nil
+}
+
+// joinServiceNames returns the names of the given services as a comma separated list.
+func joinServiceNames(services []types.ServiceType) string {
+	names := make([]string, 0, len(services))
+	for _, serviceType := range services {
+		names = append(names, string(serviceType))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// followJoin prints the progress of this system joining each service, until it joined all of them or failed to join one.
+func followJoin(ctx context.Context, sh *service.Handler) error {
+	client, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Waiting for the initiator to add this system to the services ...")
+
+	started := time.Now().UTC()
+	printed := map[types.ServiceType]string{}
+	for {
+		progress, err := cloudClient.GetJoinProgress(ctx, client)
+		if err != nil {
+			logger.Debug("Failed to get the join progress", logger.Ctx{"err": err})
+		} else {
+			lines, done, err := joinProgressUpdates(progress, printed, started)
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+
+			if err != nil || done {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(followJoinInterval):
+		}
+	}
+}
+
+// joinProgressUpdates returns the lines describing the changes of the join progress since the given printed statuses, and records them as printed.
+// It returns whether all services are joined, or an error if any of them failed to join.
+// A finished join from before the given start time is ignored, as the initiator hasn't asked this system to join yet.
+func joinProgressUpdates(progress []types.JoinState, printed map[types.ServiceType]string, started time.Time) ([]string, bool, error) {
+	if len(progress) == 0 {
+		return nil, false, nil
+	}
+
+	finished := !slices.ContainsFunc(progress, func(state types.JoinState) bool { return state.Status == types.JoinStatePending })
+	stale := !slices.ContainsFunc(progress, func(state types.JoinState) bool { return !state.UpdatedAt.Before(started) })
+	if finished && stale {
+		return nil, false, nil
+	}
+
+	lines := []string{}
+	for _, state := range progress {
+		if printed[state.Service] == state.Status {
+			continue
+		}
+
+		printed[state.Service] = state.Status
+		switch state.Status {
+		case types.JoinStatePending:
+			lines = append(lines, fmt.Sprintf("Joining %s ...", state.Service))
+		case types.JoinStateJoined:
+			lines = append(lines, tui.SummarizeResult("Joined %s", state.Service))
+		case types.JoinStateFailed:
+			return lines, false, fmt.Errorf("Failed to join %s: %s", state.Service, state.Error)
+		}
+	}
+
+	return lines, finished, nil
 }
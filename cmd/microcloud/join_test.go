@@ -0,0 +1,128 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type joinSuite struct {
+	suite.Suite
+}
+
+func TestJoinSuite(t *testing.T) {
+	suite.Run(t, new(joinSuite))
+}
+
+func (s *joinSuite) Test_joinProgressUpdates() {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := started.Add(-time.Hour)
+	after := started.Add(time.Minute)
+
+	state := func(service types.ServiceType, status string, updatedAt time.Time) types.JoinState {
+		return types.JoinState{Service: service, Status: status, UpdatedAt: updatedAt}
+	}
+
+	s.T().Log("No join yet")
+	printed := map[types.ServiceType]string{}
+	lines, done, err := joinProgressUpdates(nil, printed, started)
+	s.NoError(err)
+	s.False(done)
+	s.Empty(lines)
+
+	s.T().Log("Finished join from before following")
+	lines, done, err = joinProgressUpdates([]types.JoinState{state(types.LXD, types.JoinStateJoined, before)}, printed, started)
+	s.NoError(err)
+	s.False(done)
+	s.Empty(lines)
+
+	s.T().Log("Join in progress")
+	lines, done, err = joinProgressUpdates([]types.JoinState{state(types.MicroCloud, types.JoinStateJoined, after), state(types.LXD, types.JoinStatePending, before)}, printed, started)
+	s.NoError(err)
+	s.False(done)
+	s.Len(lines, 2)
+
+	s.T().Log("Unchanged join progress")
+	lines, done, err = joinProgressUpdates([]types.JoinState{state(types.MicroCloud, types.JoinStateJoined, after), state(types.LXD, types.JoinStatePending, before)}, printed, started)
+	s.NoError(err)
+	s.False(done)
+	s.Empty(lines)
+
+	s.T().Log("All services joined")
+	lines, done, err = joinProgressUpdates([]types.JoinState{state(types.MicroCloud, types.JoinStateJoined, after), state(types.LXD, types.JoinStateJoined, after)}, printed, started)
+	s.NoError(err)
+	s.True(done)
+	s.Len(lines, 1)
+
+	s.T().Log("Failed join")
+	_, done, err = joinProgressUpdates([]types.JoinState{state(types.MicroOVN, types.JoinStateFailed, after)}, map[types.ServiceType]string{}, started)
+	s.Error(err)
+	s.False(done)
+}
+
+func (s *joinSuite) Test_abortableJoins() {
+	earlier := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+
+	state := func(service types.ServiceType, status string, updatedAt time.Time) types.JoinState {
+		return types.JoinState{Service: service, Status: status, UpdatedAt: updatedAt}
+	}
+
+	cases := []struct {
+		desc       string
+		progress   []types.JoinState
+		joinStates []types.JoinState
+		expected   []types.ServiceType
+	}{
+		{
+			desc:     "Restarted daemon or initiator without recorded join states",
+			expected: []types.ServiceType{},
+		},
+		{
+			desc:     "All services joined",
+			progress: []types.JoinState{state(types.MicroCloud, types.JoinStateJoined, earlier), state(types.LXD, types.JoinStateJoined, later)},
+			expected: []types.ServiceType{},
+		},
+		{
+			desc:     "Pending join",
+			progress: []types.JoinState{state(types.MicroCloud, types.JoinStateJoined, earlier), state(types.LXD, types.JoinStatePending, earlier)},
+			expected: []types.ServiceType{types.LXD},
+		},
+		{
+			desc:     "Failed join",
+			progress: []types.JoinState{state(types.MicroCeph, types.JoinStateFailed, later), state(types.LXD, types.JoinStateJoined, later)},
+			expected: []types.ServiceType{types.MicroCeph},
+		},
+		{
+			desc:       "Failed join recorded before the daemon restarted",
+			joinStates: []types.JoinState{state(types.LXD, types.JoinStateJoined, earlier), state(types.MicroOVN, types.JoinStateFailed, earlier)},
+			expected:   []types.ServiceType{types.MicroOVN},
+		},
+		{
+			desc:       "Failed join retried since",
+			progress:   []types.JoinState{state(types.MicroOVN, types.JoinStateJoined, later)},
+			joinStates: []types.JoinState{state(types.MicroOVN, types.JoinStateFailed, earlier)},
+			expected:   []types.ServiceType{},
+		},
+		{
+			desc:       "Join failed again since it was recorded",
+			progress:   []types.JoinState{state(types.MicroOVN, types.JoinStateFailed, later)},
+			joinStates: []types.JoinState{state(types.MicroOVN, types.JoinStateJoined, earlier)},
+			expected:   []types.ServiceType{types.MicroOVN},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		services := []types.ServiceType{}
+		for _, joinState := range abortableJoins(c.progress, c.joinStates) {
+			services = append(services, joinState.Service)
+		}
+
+		s.Equal(c.expected, services)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// LimitsOptions represents the structure of the limits config in the preseed yaml.
+type LimitsOptions struct {
+	Project  ProjectLimits  `yaml:"project"`
+	Instance InstanceLimits `yaml:"instance"`
+	Storage  StorageLimits  `yaml:"storage"`
+}
+
+// ProjectLimits represents the limits of the default project in the preseed yaml.
+type ProjectLimits struct {
+	Instances string `yaml:"instances"`
+	CPU       string `yaml:"cpu"`
+	Memory    string `yaml:"memory"`
+	Disk      string `yaml:"disk"`
+}
+
+// InstanceLimits represents the default instance size in the preseed yaml.
+type InstanceLimits struct {
+	CPU    string `yaml:"cpu"`
+	Memory string `yaml:"memory"`
+}
+
+// validate validates the limits config.
+func (l LimitsOptions) validate() error {
+	validators := []struct {
+		name      string
+		value     string
+		validator func(string) error
+	}{
+		{name: "project instances", value: l.Project.Instances, validator: validate.IsUint32},
+		{name: "project CPU", value: l.Project.CPU, validator: validate.IsUint32},
+		{name: "project memory", value: l.Project.Memory, validator: validate.IsSize},
+		{name: "project disk", value: l.Project.Disk, validator: validate.IsSize},
+		{name: "instance CPU", value: l.Instance.CPU, validator: validate.IsUint32},
+		{name: "instance memory", value: l.Instance.Memory, validator: validate.IsSize},
+		{name: "local storage pool size", value: l.Storage.Local, validator: service.ValidatePoolSizeLimit},
+		{name: "remote storage pool size", value: l.Storage.Remote, validator: service.ValidatePoolSizeLimit},
+		{name: "remote-fs storage pool size", value: l.Storage.RemoteFS, validator: service.ValidatePoolSizeLimit},
+	}
+
+	for _, v := range validators {
+		err := validate.Optional(v.validator)(v.value)
+		if err != nil {
+			return fmt.Errorf("Invalid %s limit: %w", v.name, err)
+		}
+	}
+
+	// LXD refuses instances without their own limit once the project limits the aggregate.
+	if l.Project.CPU != "" && l.Instance.CPU == "" {
+		return errors.New("A project CPU limit requires a default instance CPU limit")
+	}
+
+	if l.Project.Memory != "" && l.Instance.Memory == "" {
+		return errors.New("A project memory limit requires a default instance memory limit")
+	}
+
+	return nil
+}
+
+// projectConfig returns the configuration of the default project for the limits.
+func (l LimitsOptions) projectConfig() map[string]string {
+	return nonEmptyConfig(map[string]string{
+		"limits.instances": l.Project.Instances,
+		"limits.cpu":       l.Project.CPU,
+		"limits.memory":    l.Project.Memory,
+		"limits.disk":      l.Project.Disk,
+	})
+}
+
+// profileConfig returns the configuration of the default profile for the default instance size.
+func (l LimitsOptions) profileConfig() map[string]string {
+	return nonEmptyConfig(map[string]string{
+		"limits.cpu":    l.Instance.CPU,
+		"limits.memory": l.Instance.Memory,
+	})
+}
+
+// nonEmptyConfig returns the given config without its unset keys.
+func nonEmptyConfig(config map[string]string) map[string]string {
+	for k, v := range config {
+		if v == "" {
+			delete(config, k)
+		}
+	}
+
+	return config
+}
+
+// setupProjectLimits applies the project limits to the default project.
+func (c *initConfig) setupProjectLimits(lxdClient lxd.InstanceServer) error {
+	config := c.limits.projectConfig()
+	if len(config) == 0 {
+		return nil
+	}
+
+	project, etag, err := lxdClient.GetProject("default")
+	if err != nil {
+		return fmt.Errorf("Failed to get the default project: %w", err)
+	}
+
+	newProject := project.Writable()
+	for k, v := range config {
+		newProject.Config[k] = v
+	}
+
+	err = lxdClient.UpdateProject("default", newProject, etag)
+	if err != nil {
+		return fmt.Errorf("Failed to set the limits of the default project: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/sirupsen/logrus"
+	lWriter "github.com/sirupsen/logrus/hooks/writer"
+
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+)
+
+// secretFieldRegex matches the names of log fields that may contain credentials.
+var secretFieldRegex = regexp.MustCompile(`(?i)(token|secret|password|passphrase|hmac|private_key|authorization)`)
+
+// redactHook is a logrus hook that removes credentials from log entries before they are written.
+type redactHook struct{}
+
+// Levels returns the log levels the hook applies to.
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redacts the message and fields of the log entry.
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = cloudClient.RedactSecrets(entry.Message)
+	for key, value := range entry.Data {
+		if secretFieldRegex.MatchString(key) {
+			entry.Data[key] = "<redacted>"
+			continue
+		}
+
+		str, ok := value.(string)
+		if ok {
+			entry.Data[key] = cloudClient.RedactSecrets(str)
+		}
+	}
+
+	return nil
+}
+
+// cliLogger implements the LXD logger interface on top of a logrus entry.
+type cliLogger struct {
+	target *logrus.Entry
+}
+
+// ctxLogger returns the logrus entry with all the given context applied.
+func (l *cliLogger) ctxLogger(ctx ...logger.Ctx) *logrus.Entry {
+	entry := l.target
+	for _, c := range ctx {
+		entry = entry.WithFields(logrus.Fields(c))
+	}
+
+	return entry
+}
+
+// Panic logs a panic level error message.
+func (l *cliLogger) Panic(msg string, ctx ...logger.Ctx) {
+	l.ctxLogger(ctx...).Panic(msg)
+}
+
+// Fatal logs a fatal error message.
+func (l *cliLogger) Fatal(msg string, ctx ...logger.Ctx) {
+	l.ctxLogger(ctx...).Fatal(msg)
+}
+
+// Error logs an error message.
+func (l *cliLogger) Error(msg string, ctx ...logger.Ctx) {
+	l.ctxLogger(ctx...).Error(msg)
+}
+
+// Warn logs a warning message.
+func (l *cliLogger) Warn(msg string, ctx ...logger.Ctx) {
+	l.ctxLogger(ctx...).Warn(msg)
+}
+
+// Info logs an informational message.
+func (l *cliLogger) Info(msg string, ctx ...logger.Ctx) {
+	l.ctxLogger(ctx...).Info(msg)
+}
+
+// Debug logs a debug message.
+func (l *cliLogger) Debug(msg string, ctx ...logger.Ctx) {
+	l.ctxLogger(ctx...).Debug(msg)
+}
+
+// Trace logs a trace message.
+func (l *cliLogger) Trace(msg string, ctx ...logger.Ctx) {
+	l.ctxLogger(ctx...).Trace(msg)
+}
+
+// AddContext returns a new Logger with the provided context added.
+func (l *cliLogger) AddContext(ctx logger.Ctx) logger.Logger {
+	return &cliLogger{target: l.ctxLogger(ctx)}
+}
+
+// defaultDebugLogPath returns the path of the log file used by --debug if no other file was requested.
+func defaultDebugLogPath() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("microcloud-%s.log", time.Now().Format("20060102-150405")))
+}
+
+// setupLogging configures the global logger for the verbosity requested on the command line.
+// Errors are always printed, warnings unless quiet is set, and informational messages if verbose or debug is set.
+// Debug messages, including every API request, are only written to the log file.
+func (c *CmdControl) setupLogging() error {
+	levels := []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+	if !c.FlagQuiet {
+		levels = append(levels, logrus.WarnLevel)
+	}
+
+	if c.FlagVerbose || c.FlagDebug {
+		levels = append(levels, logrus.InfoLevel)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	log.Level = logrus.DebugLevel
+	log.Formatter = &logrus.TextFormatter{PadLevelText: true, FullTimestamp: true, DisableColors: true}
+
+	// Redact the entries before any of the writers format them.
+	log.AddHook(redactHook{})
+	log.AddHook(&lWriter.Hook{Writer: os.Stderr, LogLevels: levels})
+
+	logFile := c.FlagLogFile
+	if c.FlagDebug && logFile == "" {
+		logFile = defaultDebugLogPath()
+	}
+
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("Failed to open log file %q: %w", logFile, err)
+		}
+
+		fileLevels := levels
+		if c.FlagDebug {
+			fileLevels = append(fileLevels, logrus.DebugLevel)
+		}
+
+		log.AddHook(&lWriter.Hook{Writer: f, LogLevels: fileLevels})
+
+		if c.FlagDebug {
+			fmt.Fprintf(os.Stderr, "Writing debug log to %q\n", logFile)
+		}
+	}
+
+	logger.Log = &cliLogger{target: logrus.NewEntry(log)}
+	cloudClient.SetDebugLogging(c.FlagDebug)
+
+	return nil
+}
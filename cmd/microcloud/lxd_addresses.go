@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// validateLXDAddress checks the address LXD listens on for its API is an IP address or a wildcard address, with an optional port.
+func validateLXDAddress(value string) error {
+	return validate.IsListenAddress(false, true, false)(value)
+}
+
+// validateLXDClusterAddress checks the address LXD listens on for the cluster traffic is an IP address, with an optional port.
+func validateLXDClusterAddress(value string) error {
+	return validate.IsListenAddress(false, false, false)(value)
+}
+
+// lxdAddressConfig returns the LXD addresses given for the system, to bootstrap LXD with.
+func lxdAddressConfig(system InitSystem) map[string]string {
+	return nonEmptyConfig(map[string]string{
+		service.LXDAddressConfig:        system.LXDAddress,
+		service.LXDClusterAddressConfig: system.LXDClusterAddress,
+	})
+}
+
+// lxdJoiners returns the systems which aren't yet part of the LXD cluster, and set up LXD with the addresses chosen by MicroCloud.
+func (c *initConfig) lxdJoiners() []string {
+	joiners := []string{}
+	for name := range c.systems {
+		if c.state[name].ExistingServices[types.LXD][name] == "" {
+			joiners = append(joiners, name)
+		}
+	}
+
+	slices.Sort(joiners)
+
+	return joiners
+}
+
+// lxdAddressMismatches compares the addresses in the configuration of LXD with the expected ones.
+func lxdAddressMismatches(coreAddress string, clusterAddress string, config map[string]any) []string {
+	mismatches := []string{}
+	for _, key := range []string{"core.https_address", "cluster.https_address"} {
+		expected := coreAddress
+		if key == "cluster.https_address" {
+			expected = clusterAddress
+		}
+
+		actual, _ := config[key].(string)
+		if actual != expected {
+			mismatches = append(mismatches, fmt.Sprintf("%s is %q instead of %q", key, actual, expected))
+		}
+	}
+
+	return mismatches
+}
+
+// verifyLXDAddresses checks LXD is configured with the expected addresses on each of the given systems which just set up LXD,
+// and that the local system reaches LXD on their cluster addresses.
+// This catches systems with several network interfaces having LXD set up on another one than MicroCloud.
+func (c *initConfig) verifyLXDAddresses(sh *service.Handler, names []string) error {
+	lxd := sh.Services[types.LXD].(*service.LXDService)
+	lxdClient, err := lxd.Client(context.Background())
+	if err != nil {
+		return err
+	}
+
+	problems := []string{}
+	coreAddresses := make([]string, 0, len(names))
+	clusterAddresses := make([]string, 0, len(names))
+	for _, name := range names {
+		system := c.systems[name]
+		coreAddress, clusterAddress := service.LXDAddresses(system.ServerInfo.Address, system.LXDAddress, system.LXDClusterAddress)
+		coreAddresses = append(coreAddresses, coreAddress)
+		clusterAddresses = append(clusterAddresses, clusterAddress)
+
+		server, _, err := lxdClient.UseTarget(name).GetServer()
+		if err != nil {
+			return fmt.Errorf("Failed to get LXD configuration of %q: %w", name, err)
+		}
+
+		for _, mismatch := range lxdAddressMismatches(coreAddress, clusterAddress, server.Config) {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, mismatch))
+		}
+	}
+
+	for i, result := range service.CheckReachability(context.Background(), clusterAddresses, service.ReachabilityTimeout) {
+		if result.Error != "" {
+			problems = append(problems, fmt.Sprintf("%s: Cluster address %q is unreachable: %s", names[i], result.Address, result.Error))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("LXD doesn't listen on the expected addresses:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	for i, name := range names {
+		fmt.Println(tui.SummarizeResult("LXD on %s listens on %s for its API and on %s for the cluster", name, coreAddresses[i], clusterAddresses[i]))
+	}
+
+	return nil
+}
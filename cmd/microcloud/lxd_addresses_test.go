@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type lxdAddressesSuite struct {
+	suite.Suite
+}
+
+func TestLXDAddressesSuite(t *testing.T) {
+	suite.Run(t, new(lxdAddressesSuite))
+}
+
+func (s *lxdAddressesSuite) Test_validateLXDAddresses() {
+	for _, value := range []string{"10.0.0.1", "10.0.0.1:8443", "[fd42::1]:8443", "fd42::1"} {
+		s.NoError(validateLXDAddress(value), value)
+		s.NoError(validateLXDClusterAddress(value), value)
+	}
+
+	for _, value := range []string{"::", "[::]:8443", "0.0.0.0"} {
+		s.NoError(validateLXDAddress(value), value)
+		s.Error(validateLXDClusterAddress(value), value)
+	}
+
+	s.Error(validateLXDAddress("lxd.example.com"))
+}
+
+func (s *lxdAddressesSuite) Test_lxdAddressConfig() {
+	s.Empty(lxdAddressConfig(InitSystem{}))
+	s.Equal(map[string]string{"cluster.https_address": "10.1.0.1"}, lxdAddressConfig(InitSystem{LXDClusterAddress: "10.1.0.1"}))
+}
+
+func (s *lxdAddressesSuite) Test_lxdAddressMismatches() {
+	config := map[string]any{"core.https_address": "[::]:8443", "cluster.https_address": "10.0.0.1:8443"}
+	s.Empty(lxdAddressMismatches("[::]:8443", "10.0.0.1:8443", config))
+
+	s.T().Log("LXD set up on another interface")
+	s.Equal([]string{`cluster.https_address is "10.0.0.1:8443" instead of "10.1.0.1:8443"`}, lxdAddressMismatches("[::]:8443", "10.1.0.1:8443", config))
+
+	s.T().Log("LXD without addresses")
+	s.Equal([]string{
+		`core.https_address is "" instead of "[::]:8443"`,
+		`cluster.https_address is "" instead of "10.0.0.1:8443"`,
+	}, lxdAddressMismatches("[::]:8443", "10.0.0.1:8443", map[string]any{}))
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/maas"
+)
+
+// MAASResultTag is the MAAS tag added to the machines which were set up by MicroCloud.
+const MAASResultTag = "microcloud"
+
+// maasConfig holds the MAAS integration used while setting up MicroCloud.
+type maasConfig struct {
+	client *maas.Client
+
+	// tag restricts the candidate machines to those with this MAAS tag, if set.
+	tag string
+
+	// machines are the candidate MAAS machines, keyed by hostname.
+	machines map[string]maas.Machine
+}
+
+// newMAASConfig returns the MAAS integration for the given flags, or nil if MAAS mode is not used.
+func newMAASConfig(apiURL string, apiKey string, tag string) (*maasConfig, error) {
+	if apiURL == "" {
+		if apiKey != "" || tag != "" {
+			return nil, withExitCode(ExitCodeUsage, errors.New("The MAAS API key and tag require the MAAS URL"))
+		}
+
+		return nil, nil
+	}
+
+	if apiKey == "" {
+		return nil, withExitCode(ExitCodeUsage, errors.New("Missing the MAAS API key"))
+	}
+
+	client, err := maas.NewClient(apiURL, apiKey)
+	if err != nil {
+		return nil, withExitCode(ExitCodeUsage, err)
+	}
+
+	return &maasConfig{client: client, tag: tag, machines: map[string]maas.Machine{}}, nil
+}
+
+// loadMAASMachines fetches the candidate machines from MAAS.
+func (c *initConfig) loadMAASMachines(ctx context.Context) error {
+	machines, err := c.maas.client.Machines(ctx, c.maas.tag)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(machines))
+	for _, machine := range machines {
+		c.maas.machines[machine.Hostname] = machine
+		names = append(names, machine.Hostname)
+	}
+
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return errors.New("No candidate machines found in MAAS")
+	}
+
+	fmt.Println(tui.SummarizeResult("Found %d MAAS machines (%s)", len(names), strings.Join(names, ", ")))
+
+	return nil
+}
+
+// maasAllowsJoin returns whether the joining system is a candidate MAAS machine.
+// Without MAAS, any system is allowed to join.
+func (c *initConfig) maasAllowsJoin(intent types.SessionJoinPost) bool {
+	if c.maas == nil {
+		return true
+	}
+
+	machine, ok := c.maas.machines[intent.Name]
+	if !ok {
+		return false
+	}
+
+	return machine.HasAddress(intent.Address)
+}
+
+// filterMAASDisks removes the disks which MAAS doesn't report as unused from the available disks of each system.
+func (c *initConfig) filterMAASDisks() {
+	if c.maas == nil {
+		return
+	}
+
+	for name, state := range c.state {
+		machine, ok := c.maas.machines[name]
+		if !ok {
+			continue
+		}
+
+		for id, disk := range state.AvailableDisks {
+			if machine.UnusedBlockDevice(disk.ID, disk.Serial) == nil {
+				logger.Debug("Skipping disk in use according to MAAS", logger.Ctx{"system": name, "disk": id})
+				delete(state.AvailableDisks, id)
+			}
+		}
+	}
+}
+
+// maasDescription returns the MAAS machine notes describing the MicroCloud setup of a system with the given services.
+func (c *initConfig) maasDescription(systemServices map[types.ServiceType]string, setupTime time.Time) string {
+	services := make([]string, 0, len(systemServices))
+	for serviceType := range systemServices {
+		services = append(services, string(serviceType))
+	}
+
+	sort.Strings(services)
+
+	return fmt.Sprintf("MicroCloud member set up from %q on %s with services: %s", c.name, setupTime.UTC().Format(time.RFC3339), strings.Join(services, ", "))
+}
+
+// recordMAASResults tags the MAAS machines which were set up by MicroCloud and records the setup in their notes.
+// The local services are recorded for the systems which didn't report their own, like the local system.
+// Failures are only reported as warnings as the cluster is already set up at this point.
+func (c *initConfig) recordMAASResults(ctx context.Context, localServices map[types.ServiceType]string) {
+	if c.maas == nil {
+		return
+	}
+
+	now := time.Now()
+	systemIDs := []string{}
+	for name, system := range c.systems {
+		machine, ok := c.maas.machines[name]
+		if !ok {
+			continue
+		}
+
+		services := system.ServerInfo.Services
+		if len(services) == 0 {
+			services = localServices
+		}
+
+		systemIDs = append(systemIDs, machine.SystemID)
+		err := c.maas.client.SetDescription(ctx, machine.SystemID, c.maasDescription(services, now))
+		if err != nil {
+			tui.PrintWarning(err.Error())
+		}
+	}
+
+	if len(systemIDs) == 0 {
+		return
+	}
+
+	sort.Strings(systemIDs)
+	err := c.maas.client.AddTag(ctx, MAASResultTag, systemIDs...)
+	if err != nil {
+		tui.PrintWarning(err.Error())
+	}
+}
@@ -3,12 +3,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/canonical/lxd/shared/termios"
 	"github.com/spf13/cobra"
+	"golang.org/x/sys/unix"
 
+	"github.com/canonical/microcloud/microcloud/api/types"
 	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
 	"github.com/canonical/microcloud/microcloud/version"
 )
 
@@ -21,8 +26,25 @@ type CmdControl struct {
 	FlagVersion       bool
 	FlagMicroCloudDir string
 	FlagNoColor       bool
+	FlagPlain         bool
+	FlagQuiet         bool
+	FlagVerbose       bool
+	FlagDebug         bool
+	FlagLogFile       string
+	FlagContext       string
+	FlagRetries       int
+
+	asker  *tui.InputHandler
+	config *cliConfig
+}
+
+// limitedTerminal returns whether the CLI is not attached to a terminal capable of rendering interactive selectors.
+func limitedTerminal() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return true
+	}
 
-	asker *tui.InputHandler
+	return !termios.IsTerminal(unix.Stdin) || !termios.IsTerminal(unix.Stdout)
 }
 
 func main() {
@@ -41,17 +63,48 @@ func main() {
 		os.Exit(1)
 	}
 
-	commonCmd := CmdControl{asker: asker}
+	// The CLI configuration is optional, so don't fail if it can't be loaded.
+	config, err := loadCLIConfig()
+	if err != nil {
+		tui.PrintWarning(err.Error())
+		config = &cliConfig{}
+	} else {
+		asker.SetTablePreferenceStore(config)
+	}
+
+	commonCmd := CmdControl{asker: asker, config: config}
 	app := &cobra.Command{
 		Use:               "microcloud",
 		Short:             "Command for managing the MicroCloud daemon",
 		Version:           version.Version(),
 		SilenceUsage:      true,
 		CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			err := config.applyDefaults(cmd)
+			if err != nil {
+				return err
+			}
+
+			if commonCmd.contextName() != "" && !supportsContext(cmd) {
+				return withExitCode(ExitCodeUsage, fmt.Errorf("Command %q doesn't support remote contexts, run it on a cluster member or with --context %s", cmd.CommandPath(), LocalContext))
+			}
+
+			if commonCmd.FlagRetries < 0 {
+				return withExitCode(ExitCodeUsage, errors.New("The number of retries must not be negative"))
+			}
+
+			service.DefaultRetryPolicy.Attempts = commonCmd.FlagRetries + 1
+
 			if commonCmd.FlagNoColor {
 				tui.DisableColors()
 			}
+
+			// The test console drives the interactive selectors itself, so never switch it to plain prompts.
+			if commonCmd.FlagPlain || (!asker.IsTestMode() && limitedTerminal()) {
+				asker.SetPlain(true)
+			}
+
+			return commonCmd.setupLogging()
 		},
 	}
 
@@ -59,8 +112,24 @@ func main() {
 	app.PersistentFlags().BoolVarP(&commonCmd.FlagHelp, "help", "h", false, "Print help")
 	app.PersistentFlags().BoolVar(&commonCmd.FlagVersion, "version", false, "Print version number")
 	app.PersistentFlags().BoolVar(&commonCmd.FlagNoColor, "no-color", false, "Disable colorization of the CLI")
+	app.PersistentFlags().BoolVar(&commonCmd.FlagPlain, "plain", false, "Use simple numbered prompts instead of interactive selectors")
+	app.PersistentFlags().BoolVarP(&commonCmd.FlagQuiet, "quiet", "q", false, "Only print errors")
+	app.PersistentFlags().BoolVarP(&commonCmd.FlagVerbose, "verbose", "v", false, "Show all information messages")
+	app.PersistentFlags().BoolVarP(&commonCmd.FlagDebug, "debug", "d", false, "Write debug messages and all API requests to the log file")
+	app.PersistentFlags().StringVar(&commonCmd.FlagLogFile, "log-file", "", "Path to the log file (defaults to a new file in the temporary directory with --debug)"+"``")
+	app.PersistentFlags().StringVar(&commonCmd.FlagContext, "context", "", "Name of the context of the MicroCloud to run the command against"+"``")
+	app.PersistentFlags().IntVar(&commonCmd.FlagRetries, "retries", service.DefaultRetryPolicy.Attempts-1, "Number of times to retry the service API calls failing with transient errors, such as while a snap restarts"+"``")
+	_ = app.RegisterFlagCompletionFunc("context", commonCmd.completeContextNames)
+	app.MarkFlagsMutuallyExclusive("quiet", "verbose")
+	app.MarkFlagsMutuallyExclusive("quiet", "debug")
+
+	// Run the root hooks for every subcommand, even those defining their own.
+	cobra.EnableTraverseRunHooks = true
 
 	app.SetVersionTemplate("{{.Version}}\n")
+	app.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return withExitCode(ExitCodeUsage, err)
+	})
 
 	var cmdInit = cmdInit{common: &commonCmd}
 	app.AddCommand(cmdInit.command())
@@ -101,12 +170,84 @@ func main() {
 	var cmdClusterManager = cmdClusterManager{common: &commonCmd}
 	app.AddCommand(cmdClusterManager.command())
 
+	var cmdInventory = cmdInventory{common: &commonCmd}
+	app.AddCommand(cmdInventory.command())
+
+	var cmdInspect = cmdInspect{common: &commonCmd}
+	app.AddCommand(cmdInspect.command())
+
+	var cmdInstances = cmdInstances{common: &commonCmd}
+	app.AddCommand(cmdInstances.command())
+
+	var cmdDrain = cmdDrain{common: &commonCmd}
+	app.AddCommand(cmdDrain.command())
+
+	var cmdTopology = cmdTopology{common: &commonCmd}
+	app.AddCommand(cmdTopology.command())
+
+	var cmdStorage = cmdStorage{common: &commonCmd}
+	app.AddCommand(cmdStorage.command())
+
+	var cmdMetricsCertificate = cmdMetricsCertificate{common: &commonCmd}
+	app.AddCommand(cmdMetricsCertificate.command())
+
+	var cmdEncryption = cmdEncryption{common: &commonCmd}
+	app.AddCommand(cmdEncryption.command())
+
+	var cmdBench = cmdBench{common: &commonCmd}
+	app.AddCommand(cmdBench.command())
+
+	var cmdTuning = cmdTuning{common: &commonCmd}
+	app.AddCommand(cmdTuning.command())
+
+	var cmdNetwork = cmdNetwork{common: &commonCmd}
+	app.AddCommand(cmdNetwork.command())
+
+	var cmdBackup = cmdBackup{common: &commonCmd}
+	app.AddCommand(cmdBackup.command())
+
+	var cmdRestore = cmdRestore{common: &commonCmd}
+	app.AddCommand(cmdRestore.command())
+
+	var cmdOperations = cmdOperations{common: &commonCmd}
+	app.AddCommand(cmdOperations.command())
+
+	var cmdEvents = cmdEvents{common: &commonCmd}
+	app.AddCommand(cmdEvents.command())
+
+	var cmdReports = cmdReports{common: &commonCmd}
+	app.AddCommand(cmdReports.command())
+
+	var cmdVerify = cmdVerify{common: &commonCmd}
+	app.AddCommand(cmdVerify.command())
+
+	var cmdAuth = cmdAuth{common: &commonCmd}
+	app.AddCommand(cmdAuth.command())
+
+	var cmdSeed = cmdSeed{common: &commonCmd}
+	app.AddCommand(cmdSeed.command())
+
+	var cmdConfig = cmdConfig{common: &commonCmd}
+	app.AddCommand(cmdConfig.command())
+
+	var cmdContext = cmdContext{common: &commonCmd}
+	app.AddCommand(cmdContext.command())
+
+	var cmdTeardown = cmdTeardown{common: &commonCmd}
+	app.AddCommand(cmdTeardown.command())
+
+	var cmdCeph = cmdServicePassthrough{common: &commonCmd, serviceType: types.MicroCeph}
+	app.AddCommand(cmdCeph.command())
+
+	var cmdOVN = cmdServicePassthrough{common: &commonCmd, serviceType: types.MicroOVN}
+	app.AddCommand(cmdOVN.command())
+
 	app.InitDefaultHelpCmd()
 
 	app.SetErr(&tui.ColorErr{})
 
 	err = app.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
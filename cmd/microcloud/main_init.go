@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"crypto/x509"
+	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"os"
 	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -37,6 +40,12 @@ const DefaultLookupTimeout time.Duration = time.Minute
 // RecommendedOSDHosts is the minimum number of OSD hosts recommended for a new cluster for fault-tolerance.
 const RecommendedOSDHosts = 3
 
+// MaxConcurrentDiskHosts is the maximum number of cluster members on which disks are added to MicroCeph at the same time.
+const MaxConcurrentDiskHosts = 5
+
+// MaxConcurrentMembers is the maximum number of cluster members configured through LXD at the same time.
+const MaxConcurrentMembers = 10
+
 // DefaultAutoSessionTimeout is the default time limit for an automatic trust establishment session.
 const DefaultAutoSessionTimeout time.Duration = 10 * time.Minute
 
@@ -54,6 +63,8 @@ type InitSystem struct {
 	AvailableDisks []lxdAPI.ResourcesStorageDisk
 	// MicroCephDisks contains the disks intended to be passed to MicroCeph.
 	MicroCephDisks []cephTypes.DisksPost
+	// CephDiskTiers maps the paths of the disks passed to MicroCeph to the performance tier they are part of, if any.
+	CephDiskTiers map[string]string
 	// MicroCephPublicNetwork specifies the optional public network configuration for Ceph.
 	// Includes the subnet (IPv4/IPv6 CIDR), network interface name, and IP address within the subnet.
 	MicroCephPublicNetwork *NetworkInterfaceInfo
@@ -69,6 +80,8 @@ type InitSystem struct {
 	TargetStoragePools []lxdAPI.StoragePoolsPost
 	// Networks is the cluster-wide network configuration.
 	Networks []lxdAPI.NetworksPost
+	// NoUplink indicates the system has no uplink connectivity, so it only carries east-west traffic and is excluded from the OVN gateway chassis.
+	NoUplink bool
 	// OVNGeneveNetwork specifies the configuration for the OVN Geneve tunnel.
 	// Includes the IP address, network interface name, and subnet to use for Geneve traffic.
 	// If left empty, Geneve traffic will be routed through the management network.
@@ -79,6 +92,10 @@ type InitSystem struct {
 	StorageVolumes map[string][]lxdAPI.StorageVolumesPost
 	// JoinConfig is the LXD configuration for joining members.
 	JoinConfig []lxdAPI.ClusterMemberConfigKey
+	// LXDAddress is the address LXD listens on for its API, all addresses if empty.
+	LXDAddress string
+	// LXDClusterAddress is the address LXD listens on for the cluster traffic, the MicroCloud address if empty.
+	LXDClusterAddress string
 }
 
 // initConfig holds the configuration for cluster formation based on the initial flags and answers provided to MicroCloud.
@@ -92,6 +109,10 @@ type initConfig struct {
 	// address is the cluster address of the local system.
 	address string
 
+	// altAddress is the address of the local system of the other IP family, on dual-stack systems.
+	// It is advertised along with the address, and used as the cluster address if the other systems are only reachable with its IP family.
+	altAddress string
+
 	// name is the cluster name for the local system.
 	name string
 
@@ -122,12 +143,93 @@ type initConfig struct {
 
 	// state is the current state information for each system.
 	state map[string]service.SystemInformation
+
+	// collectOptions limits the state information collected from each system to what the services being set up need.
+	collectOptions service.CollectOptions
+
+	// manifestPath is the file to write the manifest of the set up resources to, if any.
+	manifestPath string
+
+	// force sets up the systems despite unsupported service versions or API extensions.
+	force bool
+
+	// maas is the MAAS integration used to find the candidate systems, if any.
+	maas *maasConfig
+
+	// cephDashboard indicates whether to enable the Ceph dashboard and metrics after setting up MicroCeph.
+	cephDashboard bool
+
+	// images holds the image server settings and remotes to configure on the LXD cluster.
+	images ImageOptions
+
+	// limits holds the limits of the default project and the default instance size.
+	limits LimitsOptions
+
+	// benchmark indicates whether to record a storage performance baseline after setting up the storage pools.
+	benchmark bool
+
+	// tuning holds the kernel tuning to apply on each system after setting up the cluster.
+	tuning TuningOptions
+
+	// volumes holds the images and backups volumes to set up on each system.
+	volumes VolumeOptions
+
+	// snapshots holds the default snapshot schedule of instances.
+	snapshots SnapshotOptions
+
+	// projects holds the LXD projects seeded for tenants.
+	projects []ProjectOptions
+
+	// cephTiers are the performance tiers of the distributed storage, each with its own storage pool.
+	cephTiers []service.CephTier
+
+	// cephPools holds the advanced options of the Ceph OSD pools created by MicroCloud.
+	cephPools CephPoolOptions
+
+	// ovnEncapsulation is the encapsulation of the OVN tunnels, MicroOVN uses Geneve if unset.
+	ovnEncapsulation string
+
+	// ovnCentral are the systems running the OVN central services, MicroOVN picks them if nil.
+	ovnCentral []string
+
+	// ovnNAT is the NAT policy of the default OVN network.
+	ovnNAT OVNNATOptions
+
+	// expectedMembers is the expected membership of MicroCloud from the daemon configuration.
+	expectedMembers []service.ExpectedMember
+
+	// acceptExpected accepts the join intents of the expected systems as they reach out, instead of asking.
+	acceptExpected bool
+
+	// attachSession is the ID of the initiating session which lost its client to continue, if any.
+	attachSession string
+
+	// onlyServices are the names of the services to set up, or all installed services if empty.
+	onlyServices []string
+
+	// excludedServices are the names of the optional services not to set up, even if installed.
+	excludedServices []string
+
+	// answers are the answers given with --answer, which replace the questions they answer.
+	answers Answers
 }
 
 type cmdInit struct {
 	common *CmdControl
 
 	flagSessionTimeout int64
+	flagAttach         string
+	flagManifest       string
+	flagMAASURL        string
+	flagMAASAPIKey     string
+	flagMAASTag        string
+	flagForce          bool
+	flagOnly           []string
+	flagExclude        []string
+	flagSimulate       int
+	flagAnswers        []string
+
+	flagAllowSystemDisks bool
 }
 
 // command returns the subcommand for initializing a MicroCloud.
@@ -136,10 +238,34 @@ func (c *cmdInit) command() *cobra.Command {
 		Use:     "init",
 		Aliases: []string{"bootstrap"},
 		Short:   "Initialize MicroCloud and create a new cluster",
-		RunE:    c.run,
+		Long: `Initialize MicroCloud and create a new cluster
+
+If the terminal running the command is lost while waiting for systems to join, the trust establishment
+session is kept for a while. Use --attach with the session ID shown when the session started
+to continue the session from another terminal.
+
+Use --answer to answer some of the questions in advance, while the other questions are asked as usual.
+For example, "--answer address=10.0.1.0/24 --answer distributed-networking=no" uses the address
+of this system in 10.0.1.0/24 for MicroCloud and doesn't set up distributed networking.`,
+		RunE: c.run,
 	}
 
 	cmd.Flags().Int64Var(&c.flagSessionTimeout, "session-timeout", 0, "Amount of seconds to wait for the trust establishment session. Defaults: 60m")
+	cmd.Flags().StringVar(&c.flagAttach, "attach", "", "Continue the trust establishment session with this ID, which lost its terminal"+"``")
+	cmd.Flags().StringVar(&c.flagManifest, "manifest", "", "Write a JSON manifest of the set up resources to this file"+"``")
+	cmd.Flags().StringVar(&c.flagMAASURL, "maas-url", "", "URL of the MAAS API to find the candidate systems and their disks in"+"``")
+	cmd.Flags().StringVar(&c.flagMAASAPIKey, "maas-api-key", "", "MAAS API key"+"``")
+	cmd.Flags().StringVar(&c.flagMAASTag, "maas-tag", "", "Only use the MAAS machines with this tag"+"``")
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, "Set up the systems despite unsupported service versions or API extensions")
+	cmd.Flags().StringSliceVar(&c.flagOnly, "only", nil, "Only set up these services along with MicroCloud (lxd, microceph, microovn)"+"``")
+	cmd.Flags().StringSliceVar(&c.flagExclude, "exclude", nil, "Don't set up these optional services, even if installed (microceph, microovn)"+"``")
+	cmd.Flags().IntVar(&c.flagSimulate, "simulate", 0, "Ask the questions for this number of simulated systems, without setting anything up"+"``")
+	cmd.Flags().StringArrayVar(&c.flagAnswers, "answer", nil, "Answer the question with this key instead of asking it (key=value)"+"``")
+	cmd.Flags().BoolVar(&c.flagAllowSystemDisks, allowSystemDisksFlag, false, allowSystemDisksUsage)
+	cmd.MarkFlagsMutuallyExclusive("only", "exclude")
+	cmd.MarkFlagsMutuallyExclusive("simulate", "attach")
+	cmd.MarkFlagsMutuallyExclusive("simulate", "maas-url")
+	cmd.MarkFlagsMutuallyExclusive("simulate", "manifest")
 
 	return cmd
 }
@@ -157,6 +283,37 @@ func (c *cmdInit) run(cmd *cobra.Command, args []string) error {
 		asker:     c.common.asker,
 		systems:   map[string]InitSystem{},
 		state:     map[string]service.SystemInformation{},
+
+		manifestPath:     c.flagManifest,
+		attachSession:    c.flagAttach,
+		force:            c.flagForce,
+		onlyServices:     c.flagOnly,
+		excludedServices: c.flagExclude,
+	}
+
+	if c.flagAttach != "" && (len(c.flagOnly) > 0 || len(c.flagExclude) > 0) {
+		return withExitCode(ExitCodeUsage, errors.New("Cannot use --only or --exclude with --attach, the services of the session are used"))
+	}
+
+	// Reject invalid service names and answers before any question is asked.
+	_, err := selectServices(nil, c.flagOnly, c.flagExclude)
+	if err != nil {
+		return err
+	}
+
+	cfg.answers, err = parseAnswers(c.flagAnswers)
+	if err != nil {
+		return err
+	}
+
+	if c.flagSimulate != 0 {
+		return cfg.runSimulation(c.flagSimulate)
+	}
+
+	cfg.allowSystemDisks(c.flagAllowSystemDisks)
+	cfg.maas, err = newMAASConfig(c.flagMAASURL, c.flagMAASAPIKey, c.flagMAASTag)
+	if err != nil {
+		return err
 	}
 
 	cfg.sessionTimeout = DefaultSessionTimeout
@@ -167,6 +324,25 @@ func (c *cmdInit) run(cmd *cobra.Command, args []string) error {
 	return cfg.runInteractive(cmd, args)
 }
 
+// selectedServices returns the optional services to set up, as selected with --only and --exclude.
+// The services explicitly selected with --only must be installed.
+func (c *initConfig) selectedServices(optionalServices map[types.ServiceType]string) (map[types.ServiceType]string, error) {
+	selected, err := selectServices(optionalServices, c.onlyServices, c.excludedServices)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.onlyServices) > 0 {
+		for serviceType, stateDir := range selected {
+			if !service.Exists(serviceType, stateDir) {
+				return nil, fmt.Errorf("%s is not installed", serviceType)
+			}
+		}
+	}
+
+	return selected, nil
+}
+
 // runInteractive runs the interactive subcommand for initializing a MicroCloud.
 func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 	fmt.Println("Waiting for services to start ...")
@@ -175,9 +351,12 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	c.setupMany, err = c.common.asker.AskBool("Do you want to set up more than one cluster member?", true)
-	if err != nil {
-		return err
+	// Only sessions setting up more than one cluster member can be attached to.
+	if c.attachSession == "" {
+		c.setupMany, err = c.askBool("setup-many", "Do you want to set up more than one cluster member?", true)
+		if err != nil {
+			return err
+		}
 	}
 
 	c.name, err = os.Hostname()
@@ -192,6 +371,21 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		},
 	}
 
+	var attachedGW *cloudClient.WebsocketGateway
+	var attached types.Session
+	if c.attachSession != "" {
+		var closeSession func()
+		attachedGW, attached, closeSession, err = c.attachInitiatingSession(context.Background())
+		if err != nil {
+			return err
+		}
+
+		defer closeSession()
+
+		c.address = attached.Address
+		c.altAddress = attached.AltAddress
+	}
+
 	err = c.askAddress("")
 	if err != nil {
 		return err
@@ -203,9 +397,24 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		types.MicroOVN:  api.MicroOVNDir,
 	}
 
-	installedServices, err = c.askMissingServices(installedServices, optionalServices)
-	if err != nil {
-		return err
+	if c.attachSession != "" {
+		// Use the services the session was started with, as the join intents were validated against them.
+		for _, serviceType := range slices.Sorted(maps.Keys(optionalServices)) {
+			_, ok := attached.Services[serviceType]
+			if ok {
+				installedServices = append(installedServices, serviceType)
+			}
+		}
+	} else {
+		optionalServices, err = c.selectedServices(optionalServices)
+		if err != nil {
+			return err
+		}
+
+		installedServices, err = c.askMissingServices(installedServices, optionalServices)
+		if err != nil {
+			return err
+		}
 	}
 
 	// check the API for service versions.
@@ -214,25 +423,57 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	services := make(map[types.ServiceType]string, len(installedServices))
-	for _, s := range s.Services {
-		version, err := s.GetVersion(context.Background())
+	services, err := s.GetVersions(context.Background())
+	if err != nil {
+		return err
+	}
+
+	err = c.askResourceNames(s)
+	if err != nil {
+		return err
+	}
+
+	if c.maas != nil {
+		if !c.setupMany {
+			return withExitCode(ExitCodeUsage, errors.New("MAAS machines can only be used when setting up more than one cluster member"))
+		}
+
+		err = c.loadMAASMachines(context.Background())
 		if err != nil {
 			return err
 		}
-
-		services[s.Type()] = version
 	}
 
 	var reverter *revert.Reverter
 	if c.setupMany {
-		err = c.runSession(context.Background(), s, types.SessionInitiating, c.sessionTimeout, func(gw *cloudClient.WebsocketGateway) error {
-			return c.initiatingSession(gw, s, services, "", nil)
-		})
+		if attachedGW != nil {
+			err = c.initiatingSession(attachedGW, s, services, attached.Passphrase, nil)
+		} else {
+			err = c.runSession(context.Background(), s, types.SessionInitiating, c.sessionTimeout, func(gw *cloudClient.WebsocketGateway) error {
+				return c.initiatingSession(gw, s, services, "", nil)
+			})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switched, err := c.preferReachableFamily()
 		if err != nil {
 			return err
 		}
 
+		// The services connect to each other with the cluster address, so recreate them with the new one.
+		if switched {
+			names := s.Services[types.LXD].(*service.LXDService).ResourceNames()
+			s, err = service.NewHandler(c.name, c.address, c.common.FlagMicroCloudDir, installedServices...)
+			if err != nil {
+				return err
+			}
+
+			s.Services[types.LXD].(*service.LXDService).SetResourceNames(names)
+		}
+
 		reverter = revert.New()
 		defer reverter.Fail()
 
@@ -263,21 +504,41 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		})
 	}
 
-	state, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: c.name, Address: c.address, Services: services})
+	err = c.checkClockSkew(context.Background(), s)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkReachability(context.Background(), s)
+	if err != nil {
+		return err
+	}
+
+	state, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: c.name, Address: c.address, Services: services}, c.collectOptions)
 	if err != nil {
 		return err
 	}
 
 	c.state[c.name] = *state
 	fmt.Println("Gathering system information ...")
-	for peer, system := range c.systems {
+	peers := make([]multicast.ServerInfo, 0, len(c.systems))
+	for _, system := range c.systems {
 		if system.ServerInfo.Name == "" || system.ServerInfo.Name == c.name {
 			continue
 		}
 
-		state, err := s.CollectSystemInformation(context.Background(), system.ServerInfo)
-		if err != nil {
-			return err
+		peers = append(peers, system.ServerInfo)
+	}
+
+	peerStates, err := s.CollectSystemInformationConcurrent(context.Background(), peers, c.collectOptions)
+	if err != nil {
+		return err
+	}
+
+	for peer, system := range c.systems {
+		state, ok := peerStates[system.ServerInfo.Name]
+		if !ok || system.ServerInfo.Name == c.name {
+			continue
 		}
 
 		c.state[system.ServerInfo.Name] = *state
@@ -289,6 +550,14 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	c.warnPlatforms()
+	c.warnSystemDisks()
+
+	err = c.checkCompatibility(s)
+	if err != nil {
+		return err
+	}
+
 	// Ensure LXD is not already clustered if we are running `microcloud init`.
 	for _, info := range c.state {
 		if info.ServiceClustered(types.LXD) {
@@ -308,6 +577,13 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	c.filterMAASDisks()
+
+	err = c.askConflicts(s)
+	if err != nil {
+		return err
+	}
+
 	err = c.askDisks(s)
 	if err != nil {
 		return err
@@ -318,6 +594,31 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	err = c.askImages()
+	if err != nil {
+		return err
+	}
+
+	err = c.askVolumes(s)
+	if err != nil {
+		return err
+	}
+
+	err = c.askSnapshots()
+	if err != nil {
+		return err
+	}
+
+	err = c.askBenchmark(s)
+	if err != nil {
+		return err
+	}
+
+	err = c.askTuning()
+	if err != nil {
+		return err
+	}
+
 	err = c.validateSystems(s)
 	if err != nil {
 		return err
@@ -332,6 +633,8 @@ func (c *initConfig) runInteractive(cmd *cobra.Command, args []string) error {
 		reverter.Success()
 	}
 
+	c.recordMAASResults(context.Background(), services)
+
 	return nil
 }
 
@@ -359,6 +662,29 @@ microCloudPeerIfaceFound:
 		}
 	}
 
+	// Systems on a routed subnet have no address in the lookup subnet, so use the interface holding their MicroCloud address instead.
+	if system.MicroCloudInternalNetwork == nil && system.ServerInfo.Address != "" {
+	routedIfaceFound:
+		for iface, network := range state.AvailableMicroCloudInterfaces {
+			for _, addr := range network.Addresses {
+				ip, subnet, err := net.ParseCIDR(addr)
+				if err != nil {
+					return fmt.Errorf("Failed to parse available network interface CIDR address: %q: %w", addr, err)
+				}
+
+				if ip.Equal(net.ParseIP(system.ServerInfo.Address)) {
+					system.MicroCloudInternalNetwork = &NetworkInterfaceInfo{
+						Interface: net.Interface{Name: iface},
+						Subnet:    subnet,
+						IP:        ip,
+					}
+
+					break routedIfaceFound
+				}
+			}
+		}
+	}
+
 	if system.MicroCloudInternalNetwork == nil {
 		return fmt.Errorf("Failed to initialize a suitable network interface for MicroCloud on %q", peer)
 	}
@@ -366,12 +692,206 @@ microCloudPeerIfaceFound:
 	return nil
 }
 
+// addCephDisks wipes and adds the selected disks of each MicroCeph cluster member as OSDs.
+// Cluster members are set up concurrently, while the disks of a single member are added one after another.
+func (c *initConfig) addCephDisks(s *service.Handler, peer string) error {
+	cephService := s.Services[types.MicroCeph].(*service.CephService)
+
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	pool := make(chan struct{}, MaxConcurrentDiskHosts)
+	errs := map[string]error{}
+	for name := range c.state[peer].ExistingServices[types.MicroCeph] {
+		system := c.systems[name]
+
+		// There may be existing cluster members that are not a part of MicroCloud, so ignore those.
+		if system.ServerInfo.Name == "" || len(system.MicroCephDisks) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, disks []cephTypes.DisksPost) {
+			defer wg.Done()
+
+			pool <- struct{}{}
+			defer func() { <-pool }()
+
+			for _, disk := range disks {
+				logger.Debug("Adding disk to MicroCeph", logger.Ctx{"name": name, "disk": disk.Path})
+				resp, err := cephService.AddDisk(context.Background(), disk, name)
+				if err == nil {
+					err = diskAddError(resp)
+				}
+
+				mu.Lock()
+				if err != nil {
+					errs[name] = err
+					mu.Unlock()
+					return
+				}
+
+				fmt.Println(tui.SummarizeResult("Added disk %s on %s to MicroCeph", strings.Join(disk.Path, ", "), name))
+				mu.Unlock()
+			}
+		}(name, system.MicroCephDisks)
+	}
+
+	wg.Wait()
+
+	// The disks of the systems have changed, so their resources need to be fetched again.
+	for name := range c.state[peer].ExistingServices[types.MicroCeph] {
+		c.invalidateSystemResources(name)
+	}
+
+	return joinMemberErrors("Failed to add disk to MicroCeph", errs)
+}
+
+// runConcurrentMembers runs the given function for each system, with at most MaxConcurrentMembers running at the same time.
+// If first is not empty, the function is run for that system before any of the others.
+func (c *initConfig) runConcurrentMembers(first string, f func(name string, system InitSystem) error) error {
+	system, ok := c.systems[first]
+	if ok {
+		err := f(first, system)
+		if err != nil {
+			return joinMemberErrors("Failed to configure cluster member", map[string]error{first: err})
+		}
+	}
+
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	pool := make(chan struct{}, MaxConcurrentMembers)
+	errs := map[string]error{}
+	for name, system := range c.systems {
+		if name == first {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, system InitSystem) {
+			defer wg.Done()
+
+			pool <- struct{}{}
+			defer func() { <-pool }()
+
+			err := f(name, system)
+			if err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, system)
+	}
+
+	wg.Wait()
+
+	return joinMemberErrors("Failed to configure cluster member", errs)
+}
+
+// joinMemberErrors combines the given errors, keyed by cluster member name, into a single error ordered by name.
+func joinMemberErrors(prefix string, errs map[string]error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	errList := make([]error, 0, len(names))
+	for _, name := range names {
+		errList = append(errList, fmt.Errorf("%s %q: %w", prefix, name, errs[name]))
+	}
+
+	return errors.Join(errList...)
+}
+
+// diskAddError returns an error combining all the failures in the given MicroCeph disk addition reports.
+func diskAddError(resp cephTypes.DiskAddResponse) error {
+	var diskErr string
+	for _, report := range resp.Reports {
+		if report.Error != "" {
+			if diskErr == "" {
+				diskErr = report.Error
+			} else {
+				// Populate errors backwards, as the latest error is at the end of the list.
+				diskErr = fmt.Sprintf("%s: %s", report.Error, diskErr)
+			}
+		}
+	}
+
+	if diskErr != "" {
+		return errors.New(diskErr)
+	}
+
+	return nil
+}
+
+// systemResources returns the LXD resources of the given system.
+// The resources are fetched at most once per command run and reused from the system information,
+// until they are invalidated with invalidateSystemResources.
+func (c *initConfig) systemResources(sh *service.Handler, name string) (*lxdAPI.Resources, error) {
+	state := c.state[name]
+	if state.Resources != nil {
+		return state.Resources, nil
+	}
+
+	system, ok := c.systems[name]
+	if !ok {
+		return nil, fmt.Errorf("Failed to find system %q", name)
+	}
+
+	resources, err := sh.Services[types.LXD].(*service.LXDService).GetResources(context.Background(), name, system.ServerInfo.Address, system.ServerInfo.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Resources = resources
+	c.state[name] = state
+
+	return resources, nil
+}
+
+// interfaceSpeed returns the link speed of the given interface of the system from its LXD resources,
+// or an empty string if the interface isn't a port of a network card or its speed is unknown.
+func (c *initConfig) interfaceSpeed(sh *service.Handler, name string, iface string) (string, error) {
+	resources, err := c.systemResources(sh, name)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get system resources of peer %q: %w", name, err)
+	}
+
+	for _, card := range resources.Network.Cards {
+		for _, port := range card.Ports {
+			if port.ID == iface && port.LinkSpeed > 0 {
+				return fmt.Sprintf("%dMbit/s", port.LinkSpeed), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// invalidateSystemResources drops the cached LXD resources of the given system,
+// so that they are fetched again on next use.
+func (c *initConfig) invalidateSystemResources(name string) {
+	state, ok := c.state[name]
+	if !ok {
+		return
+	}
+
+	state.Resources = nil
+	c.state[name] = state
+}
+
 // waitForJoin requests a system to join each service's respective cluster,
 // and then waits for the request to either complete or time out.
 // If the request was successful, it additionally waits until the cluster appears in the database.
 func waitForJoin(sh *service.Handler, clusterSizes map[types.ServiceType]int, peer string, cert *x509.Certificate, cfg types.ServicesPut) error {
 	cloud := sh.Services[types.MicroCloud].(*service.CloudService)
-	err := cloud.RequestJoin(context.Background(), peer, cert, cfg)
+	err := service.Retry(context.Background(), sh.RetryPolicy, func() error {
+		return cloud.RequestJoin(context.Background(), peer, cert, cfg)
+	})
 	if err != nil {
 		return fmt.Errorf("System %q failed to join the cluster: %w", peer, err)
 	}
@@ -389,8 +909,10 @@ func waitForJoin(sh *service.Handler, clusterSizes map[types.ServiceType]int, pe
 		}
 
 		// Check the size of the cluster for each service.
-		for service := range clustered {
-			systems, err := sh.Services[service].ClusterMembers(context.Background())
+		for serviceType := range clustered {
+			systems, err := service.RetryValue(context.Background(), sh.RetryPolicy, func() (map[string]string, error) {
+				return sh.Services[serviceType].ClusterMembers(context.Background())
+			})
 			if err != nil {
 				return err
 			}
@@ -398,9 +920,9 @@ func waitForJoin(sh *service.Handler, clusterSizes map[types.ServiceType]int, pe
 			// If the size of the cluster has been incremented by 1 from its initial value,
 			// then we don't need to check the corresponding service anymore.
 			// So remove the service from consideration and update the current cluster size for the next node.
-			if len(systems) == clusterSizes[service]+1 {
-				delete(clustered, service)
-				clusterSizes[service] = clusterSizes[service] + 1
+			if len(systems) == clusterSizes[serviceType]+1 {
+				delete(clustered, serviceType)
+				clusterSizes[serviceType] = clusterSizes[serviceType] + 1
 			}
 		}
 	}
@@ -433,13 +955,15 @@ func (c *initConfig) addPeers(sh *service.Handler) (revert.Hook, error) {
 			Address:    info.ServerInfo.Address,
 			LXDConfig:  info.JoinConfig,
 			CephConfig: info.MicroCephDisks,
-		}
+			NoUplink:   info.NoUplink,
 
-		if info.OVNGeneveNetwork != nil {
-			p := joinConfig[peer]
-			p.OVNConfig = map[string]string{"ovn-encap-ip": info.OVNGeneveNetwork.IP.String()}
-			joinConfig[peer] = p
+			LXDAddress:        info.LXDAddress,
+			LXDClusterAddress: info.LXDClusterAddress,
 		}
+
+		p := joinConfig[peer]
+		p.OVNConfig = c.ovnConfig(info)
+		joinConfig[peer] = p
 	}
 
 	clusterSize := map[types.ServiceType]int{}
@@ -601,9 +1125,11 @@ func (c *initConfig) validateSystems(s *service.Handler) (err error) {
 	// the gateways from the current node's UPLINK to verify against the other
 	// systems' management addrs
 	var ip4OVNRanges, ip6OVNRanges []*shared.IPRange
+	subnets := c.systemSubnets()
 
+	uplinkNetwork := handlerResourceNames(s).UplinkNetwork
 	for _, network := range c.systems[s.Name].Networks {
-		if network.Type == "physical" && network.Name == service.DefaultUplinkNetwork {
+		if network.Type == "physical" && network.Name == uplinkNetwork {
 			ip4OVNRanges, err = validateGatewayNet(network.Config, "ipv4", validate.IsNetworkAddressCIDRV4)
 			if err != nil {
 				return err
@@ -631,10 +1157,51 @@ func (c *initConfig) validateSystems(s *service.Handler) (err error) {
 				}
 			}
 
+			// The delegated prefix is routed to the uplink, so it can't be part of the on-link subnet of the gateway.
+			routes, hasRoutes := network.Config["ipv6.routes"]
+			if hasRoutes {
+				_, routesNet, err := net.ParseCIDR(routes)
+				if err != nil {
+					return fmt.Errorf("Invalid ipv6.routes %q: %w", routes, err)
+				}
+
+				_, gatewayNet, err := net.ParseCIDR(network.Config["ipv6.gateway"])
+				if err != nil {
+					return fmt.Errorf("%s ipv6.routes requires an IPv6 gateway", uplinkNetwork)
+				}
+
+				if gatewayNet.Contains(routesNet.IP) || routesNet.Contains(gatewayNet.IP) {
+					return fmt.Errorf("%s ipv6.routes %q must not overlap the gateway subnet %q", uplinkNetwork, routes, gatewayNet.String())
+				}
+			}
+
+			uplinkSubnets, err := uplinkSubnets(uplinkNetwork, network.Config)
+			if err != nil {
+				return err
+			}
+
+			subnets = append(subnets, uplinkSubnets...)
+
 			break
 		}
 	}
 
+	// Cross-check all the subnets before anything is configured.
+	err = validateSubnets(subnets)
+	if err != nil {
+		return err
+	}
+
+	ovnNetwork := handlerResourceNames(s).OVNNetwork
+	for _, network := range c.systems[s.Name].Networks {
+		if network.Type == "ovn" && network.Name == ovnNetwork && network.Config["dns.search"] != "" {
+			err = validate.IsListOf(service.ValidateDNSDomain)(network.Config["dns.search"])
+			if err != nil {
+				return fmt.Errorf("Invalid dns.search: %w", err)
+			}
+		}
+	}
+
 	// Ensure that no system's management address falls within the OVN ranges
 	// to prevent OVN from allocating an IP that's already in use.
 	for systemName, system := range c.systems {
@@ -651,13 +1218,13 @@ func (c *initConfig) validateSystems(s *service.Handler) (err error) {
 
 		for _, ipRange := range ip4OVNRanges {
 			if ipRange.ContainsIP(systemAddr) {
-				return fmt.Errorf("%s ipv4.ovn.ranges must not include system address %q", service.DefaultUplinkNetwork, systemAddr)
+				return fmt.Errorf("%s ipv4.ovn.ranges must not include system address %q", uplinkNetwork, systemAddr)
 			}
 		}
 
 		for _, ipRange := range ip6OVNRanges {
 			if ipRange.ContainsIP(systemAddr) {
-				return fmt.Errorf("%s ipv6.ovn.ranges must not include system address %q", service.DefaultUplinkNetwork, systemAddr)
+				return fmt.Errorf("%s ipv6.ovn.ranges must not include system address %q", uplinkNetwork, systemAddr)
 			}
 		}
 	}
@@ -665,12 +1232,40 @@ func (c *initConfig) validateSystems(s *service.Handler) (err error) {
 	return nil
 }
 
+// memberStoragePools returns the names of the storage pools which are either set up or grown on the given system.
+func memberStoragePools(system InitSystem, names service.ResourceNames) []string {
+	poolNames := []string{}
+
+	// In case any storage pools are marked for initial setup,
+	// add them to the list of available storage pool names.
+	for _, pool := range system.TargetStoragePools {
+		poolNames = append(poolNames, pool.Name)
+	}
+
+	// When joining the selected system, it can grow either the local or remote storage pool.
+	// In this case add the pool's name to the list of available storage pools.
+	for _, cfg := range system.JoinConfig {
+		if cfg.Name == names.LocalPool || cfg.Name == names.RemotePool {
+			if cfg.Entity == "storage-pool" && cfg.Key == "source" {
+				poolNames = append(poolNames, cfg.Name)
+			}
+		}
+	}
+
+	return poolNames
+}
+
 // setupCluster Bootstraps the cluster if necessary, adds all peers to the cluster, and completes any post cluster
 // configuration.
 func (c *initConfig) setupCluster(s *service.Handler) error {
 	reverter := revert.New()
 	defer reverter.Fail()
 
+	err := c.validateOVNEncapsulation(context.Background(), s)
+	if err != nil {
+		return err
+	}
+
 	lxd := s.Services[types.LXD].(*service.LXDService)
 	lxdClient, err := lxd.Client(context.Background())
 	if err != nil {
@@ -686,7 +1281,7 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 	}
 
 	for _, network := range system.Networks {
-		if network.Name == service.DefaultOVNNetwork || profile.Devices["eth0"] == nil {
+		if network.Name == lxd.ResourceNames().OVNNetwork || profile.Devices["eth0"] == nil {
 			profile.Devices["eth0"] = map[string]string{"name": "eth0", "network": network.Name, "type": "nic"}
 		}
 	}
@@ -697,6 +1292,12 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 		}
 	}
 
+	profileConfig := c.limits.profileConfig()
+	maps.Copy(profileConfig, c.snapshots.profileConfig())
+	if len(profileConfig) > 0 {
+		profile.Config = profileConfig
+	}
+
 	newProfile, err := c.askUpdateProfile(profile, profiles, lxdClient)
 	if err != nil {
 		return err
@@ -715,6 +1316,9 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 		}
 	}
 
+	// The systems already part of the LXD cluster keep their addresses.
+	lxdJoiners := c.lxdJoiners()
+
 	fmt.Println("Initializing new services ...")
 	mu := sync.Mutex{}
 	err = s.RunConcurrent(types.MicroCloud, types.LXD, func(s service.Service) error {
@@ -739,16 +1343,19 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 		}
 
 		if s.Type() == types.MicroOVN {
-			microOvnBootstrapConf := make(map[string]string)
-			if bootstrapSystem.OVNGeneveNetwork != nil {
-				microOvnBootstrapConf["ovn-encap-ip"] = bootstrapSystem.OVNGeneveNetwork.IP.String()
-			}
-
+			microOvnBootstrapConf := c.ovnConfig(bootstrapSystem)
 			if len(microOvnBootstrapConf) > 0 {
 				s.SetConfig(microOvnBootstrapConf)
 			}
 		}
 
+		if s.Type() == types.LXD {
+			lxdBootstrapConf := lxdAddressConfig(bootstrapSystem)
+			if len(lxdBootstrapConf) > 0 {
+				s.SetConfig(lxdBootstrapConf)
+			}
+		}
+
 		// set a 2 minute timeout to bootstrap a service in case the node is slow.
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 		defer cancel()
@@ -784,6 +1391,11 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 
 	reverter.Add(cleanup)
 
+	err = c.verifyLXDAddresses(s, lxdJoiners)
+	if err != nil {
+		return err
+	}
+
 	peer := s.Name
 	microCeph := initializedServices[types.MicroCeph]
 	if microCeph != "" {
@@ -791,49 +1403,25 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 	}
 
 	if s.Services[types.MicroCeph] != nil {
-		for name := range c.state[peer].ExistingServices[types.MicroCeph] {
-			// There may be existing cluster members that are not a part of MicroCloud, so ignore those.
-			if c.systems[name].ServerInfo.Name == "" {
-				continue
-			}
-
-			for _, disk := range c.systems[name].MicroCephDisks {
-				logger.Debug("Adding disk to MicroCeph", logger.Ctx{"name": name, "disk": disk.Path})
-				resp, err := s.Services[types.MicroCeph].(*service.CephService).AddDisk(context.Background(), disk, name)
-				if err != nil {
-					return err
-				}
-
-				var diskErr string
-				for _, report := range resp.Reports {
-					if report.Error != "" {
-						if diskErr == "" {
-							diskErr = report.Error
-						} else {
-							// Populate errors backwards, as the latest error is at the end of the list.
-							diskErr = fmt.Sprintf("%s: %s", report.Error, diskErr)
-						}
-					}
-				}
-
-				if diskErr != "" {
-					return fmt.Errorf("Failed to add disk to MicroCeph: %s", diskErr)
-				}
-			}
+		err := c.addCephDisks(s, peer)
+		if err != nil {
+			return err
 		}
 
 		cephService := s.Services[types.MicroCeph].(*service.CephService)
 
+		err = c.assignCephTierDisks(context.Background(), cephService)
+		if err != nil {
+			return err
+		}
+
 		allDisks, err := cephService.GetDisks(context.Background(), "", nil)
 		if err != nil {
 			return err
 		}
 
 		if len(allDisks) > 0 {
-			defaultPoolSize := len(allDisks)
-			if defaultPoolSize > RecommendedOSDHosts {
-				defaultPoolSize = RecommendedOSDHosts
-			}
+			defaultPoolSize := c.defaultOSDPoolSize(len(allDisks))
 
 			pools, err := cephService.GetPools(context.Background(), s.Name)
 			if err != nil {
@@ -873,32 +1461,22 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 	if s.Services[types.MicroOVN] != nil {
 		serviceOVN := s.Services[types.MicroOVN].(*service.OVNService)
 
-		services, err := serviceOVN.GetServices(context.Background())
-		if err != nil {
-			return err
-		}
-
 		clusterMap := map[string]string{}
 		for peer, system := range c.systems {
 			clusterMap[peer] = system.ServerInfo.Address
 		}
 
-		conns := []string{}
-		for _, service := range services {
-			if service.Service == "central" {
-				addr := s.Address()
-				if service.Location != s.Name {
-					addr = clusterMap[service.Location]
-				}
-
-				conns = append(conns, "ssl:"+util.CanonicalNetworkAddress(addr, 6641))
-			}
+		ovnConfig, err = serviceOVN.NorthboundConnection(context.Background(), clusterMap)
+		if err != nil {
+			return err
 		}
-
-		ovnConfig = strings.Join(conns, ",")
 	}
 
 	config := map[string]string{"network.ovn.northbound_connection": ovnConfig}
+	for k, v := range c.images.serverConfig() {
+		config[k] = v
+	}
+
 	// Update LXD's global config.
 	server, _, err := lxdClient.GetServer()
 	if err != nil {
@@ -944,8 +1522,17 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 		}
 	})
 
+	// The joiners create the bridge themselves before joining LXD.
+	if system.NoUplink {
+		err = service.CreateNoUplinkBridge(context.Background())
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create preliminary networks & storage pools on each target.
-	for name, system := range c.systems {
+	// The local system goes first so the pending entities exist before the remaining targets are added concurrently.
+	err = c.runConcurrentMembers(s.Name, func(name string, system InitSystem) error {
 		lxdClient, err := lxd.Client(context.Background())
 		if err != nil {
 			return err
@@ -966,6 +1553,11 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 				return err
 			}
 		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	cephFSPool := lxdAPI.StoragePoolsPost{}
@@ -976,7 +1568,7 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 			continue
 		}
 
-		err = lxdClient.CreateStoragePool(pool)
+		err = lxdClient.CreateStoragePool(c.cephPools.storagePool(pool))
 		if err != nil {
 			return err
 		}
@@ -989,6 +1581,21 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 		}
 	}
 
+	err = c.setupCephTierRules(context.Background(), system.StoragePools)
+	if err != nil {
+		return err
+	}
+
+	err = c.setupCephPoolOptions(context.Background(), system.StoragePools, system.TargetStoragePools)
+	if err != nil {
+		return err
+	}
+
+	err = c.setupCephPoolQuotas(context.Background(), system.StoragePools, system.TargetStoragePools)
+	if err != nil {
+		return err
+	}
+
 	for _, network := range system.Networks {
 		err = lxdClient.CreateNetwork(network)
 		if err != nil {
@@ -996,6 +1603,11 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 		}
 	}
 
+	err = c.setupOVNChassisRoles(lxdClient)
+	if err != nil {
+		return err
+	}
+
 	if !slices.Contains(profiles, profile.Name) {
 		err = lxdClient.CreateProfile(profile)
 		if err != nil {
@@ -1013,89 +1625,121 @@ func (c *initConfig) setupCluster(s *service.Handler) error {
 		}
 	}
 
-	// With storage pools set up, add some volumes for images & backups.
-	for name, system := range c.systems {
+	// The default profile carries the instance limits required by the project limits, so it is set up first.
+	err = c.setupProjectLimits(lxdClient)
+	if err != nil {
+		return err
+	}
+
+	err = c.setupProjects(lxdClient, system, lxd.ResourceNames().UplinkNetwork, profile.ProfilePut, reverter)
+	if err != nil {
+		return err
+	}
+
+	// With storage pools set up, reserve the space of the local pools beyond their maximum size, and add some volumes for images & backups.
+	// The reverter is shared between the targets, so guard it while they are set up concurrently.
+	names := lxd.ResourceNames()
+	reverterMu := sync.Mutex{}
+	addRevert := func(f func()) {
+		reverterMu.Lock()
+		reverter.Add(f)
+		reverterMu.Unlock()
+	}
+
+	err = c.runConcurrentMembers("", func(name string, system InitSystem) error {
 		lxdClient, err := lxd.Client(context.Background())
 		if err != nil {
 			return err
 		}
 
-		poolNames := []string{}
+		targetClient := lxdClient.UseTarget(name)
+		err = c.reserveLocalPoolSpace(targetClient, system, names, addRevert)
+		if err != nil {
+			return err
+		}
 
-		// In case any storage pools are marked for initial setup,
-		// add them to the list of available storage pool names.
-		for _, pool := range system.TargetStoragePools {
-			poolNames = append(poolNames, pool.Name)
+		pool := c.volumes.memberPool(system, names)
+		if pool == "" {
+			return nil
 		}
 
-		// When joining the selected system, it can grow either the local or remote storage pool.
-		// In this case add the pool's name to the list of available storage pools.
-		for _, cfg := range system.JoinConfig {
-			if cfg.Name == "local" || cfg.Name == "remote" {
-				if cfg.Entity == "storage-pool" && cfg.Key == "source" {
-					poolNames = append(poolNames, cfg.Name)
-				}
-			}
+		server, _, err := targetClient.GetServer()
+		if err != nil {
+			return err
 		}
 
-		targetClient := lxdClient.UseTarget(name)
-		for _, pool := range poolNames {
-			if pool == "local" {
-				server, _, err := targetClient.GetServer()
-				if err != nil {
-					return err
-				}
+		addRevert(func() {
+			_ = targetClient.UpdateServer(server.Writable(), "")
+		})
 
-				reverter.Add(func() {
-					_ = targetClient.UpdateServer(server.Writable(), "")
-				})
+		newServer := server.Writable()
+		for _, volumeType := range volumeTypes {
+			volume := lxdAPI.StorageVolumesPost{Name: c.volumes.volumeName(volumeType, name), Type: "custom"}
+			size := c.volumes.volumeSize(volumeType)
+			if size != "" {
+				volume.Config = map[string]string{"size": size}
+			}
 
-				op, err := targetClient.CreateStoragePoolVolume("local", lxdAPI.StorageVolumesPost{Name: "images", Type: "custom"})
-				if err != nil {
-					return fmt.Errorf("Failed to create volume %q on pool %q: %w", "images", "local", err)
-				}
+			op, err := targetClient.CreateStoragePoolVolume(pool, volume)
+			if err != nil {
+				return fmt.Errorf("Failed to create volume %q on pool %q: %w", volume.Name, pool, err)
+			}
 
-				err = op.Wait()
-				if err != nil {
-					return fmt.Errorf("Failed to wait for volume %q on pool %q: %w", "images", "local", err)
+			err = op.Wait()
+			if err != nil {
+				return fmt.Errorf("Failed to wait for volume %q on pool %q: %w", volume.Name, pool, err)
+			}
+
+			addRevert(func() {
+				op, err := targetClient.DeleteStoragePoolVolume(pool, "custom", volume.Name)
+				if err == nil {
+					_ = op.Wait()
 				}
+			})
 
-				reverter.Add(func() {
-					op, err := targetClient.DeleteStoragePoolVolume("local", "custom", "images")
-					if err == nil {
-						_ = op.Wait()
-					}
-				})
+			newServer.Config["storage."+volumeType+"_volume"] = pool + "/" + volume.Name
+		}
 
-				op, err = targetClient.CreateStoragePoolVolume("local", lxdAPI.StorageVolumesPost{Name: "backups", Type: "custom"})
-				if err != nil {
-					return fmt.Errorf("Failed to create volume %q on pool %q: %w", "backups", "local", err)
-				}
+		err = targetClient.UpdateServer(newServer, "")
+		if err != nil {
+			return err
+		}
 
-				err = op.Wait()
-				if err != nil {
-					return fmt.Errorf("Failed to wait for volume %q on pool %q: %w", "backups", "local", err)
-				}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-				reverter.Add(func() {
-					op, err = targetClient.DeleteStoragePoolVolume("local", "custom", "backups")
-					if err == nil {
-						_ = op.Wait()
-					}
-				})
+	reverter.Success()
 
-				newServer := server.Writable()
-				newServer.Config["storage.backups_volume"] = "local/backups"
-				newServer.Config["storage.images_volume"] = "local/images"
-				err = targetClient.UpdateServer(newServer, "")
-				if err != nil {
-					return err
-				}
-			}
+	err = writeImageRemotes(c.images.Remotes)
+	if err != nil {
+		tui.PrintWarning(err.Error())
+	}
+
+	if c.bootstrap {
+		err = c.saveSetupConfig(context.Background(), s)
+		if err != nil {
+			tui.PrintWarning(err.Error())
 		}
 	}
 
-	reverter.Success()
+	c.setupCephDashboard(context.Background())
+
+	if c.hasEncryptedDisks() {
+		tui.PrintWarning("OSD encryption keys are only stored in Ceph. Run \"microcloud encryption export\" or \"microcloud encryption escrow\" to keep recovery material")
+	}
+
+	c.setupBenchmark(s)
+	c.setupTuning(s)
+
+	if c.manifestPath != "" {
+		err = c.writeManifest(s, profile)
+		if err != nil {
+			return err
+		}
+	}
 
 	fmt.Println(tui.SuccessColor("MicroCloud is ready", true))
 
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"slices"
 	"testing"
 
 	lxdAPI "github.com/canonical/lxd/shared/api"
 
+	"github.com/canonical/microcloud/microcloud/api/types"
 	"github.com/canonical/microcloud/microcloud/multicast"
 	"github.com/canonical/microcloud/microcloud/service"
 )
@@ -150,6 +152,10 @@ func TestValidateSystemsIP6(t *testing.T) {
 			"ipv6.gateway":    "fc00:bad:feed::1/64",
 			"ipv6.ovn.ranges": "fc00:bad:feed::f-fc00:bad:feed::fffe",
 		}),
+		"delegatedPrefix": newSystemWithUplinkNetConfig(address, map[string]string{
+			"ipv6.gateway": "fc00:bad:feed::1/64",
+			"ipv6.routes":  "fc00:bad:f00d::/56",
+		}),
 	}
 
 	ensureValidateSystemsPasses(handler, validSystems, t)
@@ -178,6 +184,13 @@ func TestValidateSystemsIP6(t *testing.T) {
 			"ipv6.gateway":    "fc00:feed:beef::1/64",
 			"ipv6.ovn.ranges": "fc00:feed:beef::bed1-fc00:feed:beef::bedf",
 		}),
+		"prefixWithoutGateway": newSystemWithUplinkNetConfig(address, map[string]string{
+			"ipv6.routes": "fc00:bad:f00d::/56",
+		}),
+		"prefixOverlapsGateway": newSystemWithUplinkNetConfig(address, map[string]string{
+			"ipv6.gateway": "fc00:feed:f000::1/64",
+			"ipv6.routes":  "fc00:feed:f000::/48",
+		}),
 	}
 
 	ensureValidateSystemsFails(handler, invalidSystems, t)
@@ -226,3 +239,62 @@ func TestValidateSystemsMultiSystem(t *testing.T) {
 		t.Fatalf("sys4 with conflicting management IP and ipv6.ovn.ranges passed validation")
 	}
 }
+
+func TestSystemResourcesDisks(t *testing.T) {
+	handler, err := service.NewSimulatedHandler("micro01", "10.0.1.11", types.MicroCloud, types.LXD)
+	if err != nil {
+		t.Fatalf("Failed to create simulated service handler: %s", err)
+	}
+
+	states := service.SimulatedSystems([]string{"micro01"})
+	state := states["micro01"]
+
+	// The cached resources show sda partitioned since the system information was collected.
+	resources := *state.Resources
+	resources.Storage.Disks = slices.Clone(resources.Storage.Disks)
+	resources.Storage.Disks[1].Partitions = []lxdAPI.ResourcesStorageDiskPartition{{ID: "sda1"}}
+	state.Resources = &resources
+
+	cfg := initConfig{state: map[string]service.SystemInformation{"micro01": state}, systems: map[string]InitSystem{"micro01": {}}}
+	available := map[string]map[string]lxdAPI.ResourcesStorageDisk{"micro01": state.AvailableDisks}
+
+	disks, err := cfg.refreshAvailableDisks(handler, available, false)
+	if err != nil {
+		t.Fatalf("Failed to refresh the available disks: %s", err)
+	}
+
+	if len(disks["micro01"]) != 2 || disks["micro01"]["sda"].ID != "" {
+		t.Fatalf("Partitioned disk in the cached resources is still available: %v", disks)
+	}
+
+	// Invalidating the resources fetches them again, without the partition.
+	disks, err = cfg.refreshAvailableDisks(handler, available, true)
+	if err != nil {
+		t.Fatalf("Failed to refresh the available disks: %s", err)
+	}
+
+	if len(disks["micro01"]) != 3 {
+		t.Fatalf("Expected 3 available disks after invalidating the resources, got %v", disks)
+	}
+
+	rows := diskRows(disks)
+	paths := make([]string, 0, len(rows))
+	for _, row := range rows {
+		paths = append(paths, row[4])
+	}
+
+	expected := []string{"/dev/disk/by-id/nvme-Simulated_NVMe_micro01", "/dev/disk/by-id/scsi-Simulated_SSD_micro01_1", "/dev/disk/by-id/scsi-Simulated_SSD_micro01_2"}
+	if !slices.Equal(expected, paths) {
+		t.Fatalf("Expected disk rows %v, got %v", expected, paths)
+	}
+
+	speed, err := cfg.interfaceSpeed(handler, "micro01", "enp7s0")
+	if err != nil || speed != "10000Mbit/s" {
+		t.Fatalf("Expected the link speed of enp7s0, got %q: %v", speed, err)
+	}
+
+	speed, err = cfg.interfaceSpeed(handler, "micro01", "br0")
+	if err != nil || speed != "" {
+		t.Fatalf("Expected no link speed for a bridge, got %q: %v", speed, err)
+	}
+}
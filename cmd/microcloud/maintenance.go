@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	microClient "github.com/canonical/microcluster/v3/client"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// addMaintenanceForceFlag adds the --force flag running an automated operation outside of the maintenance windows.
+func addMaintenanceForceFlag(cmd *cobra.Command, force *bool) {
+	cmd.Flags().BoolVar(force, "force", false, "Run even outside of the maintenance windows")
+}
+
+// checkMaintenanceWindows returns an error if the current time is outside of the configured maintenance windows, unless forced.
+func checkMaintenanceWindows(ctx context.Context, client *microClient.Client, force bool) error {
+	if force {
+		return nil
+	}
+
+	config, err := cloudClient.GetConfig(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	return maintenanceWindowsError(config[types.ConfigMaintenanceWindows], time.Now())
+}
+
+// maintenanceWindowsError returns an error if the given time is outside of the maintenance windows.
+func maintenanceWindowsError(windows string, now time.Time) error {
+	open, err := service.InMaintenanceWindow(windows, now)
+	if err != nil {
+		return err
+	}
+
+	if !open {
+		return fmt.Errorf("%s is outside of the maintenance windows (%s), use --force to run anyway", now.UTC().Format("Mon 15:04 MST"), windows)
+	}
+
+	return nil
+}
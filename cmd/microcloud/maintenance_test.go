@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type maintenanceSuite struct {
+	suite.Suite
+}
+
+func TestMaintenanceSuite(t *testing.T) {
+	suite.Run(t, new(maintenanceSuite))
+}
+
+func (s *maintenanceSuite) Test_maintenanceWindowsError() {
+	// 2024-01-06 is a Saturday.
+	now := time.Date(2024, 1, 6, 10, 30, 0, 0, time.UTC)
+
+	s.NoError(maintenanceWindowsError("", now))
+	s.NoError(maintenanceWindowsError("sat 10:00-12:00", now))
+	s.EqualError(maintenanceWindowsError("sun 10:00-12:00", now), "Sat 10:30 UTC is outside of the maintenance windows (sun 10:00-12:00), use --force to run anyway")
+	s.Error(maintenanceWindowsError("10:00", now))
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+	"github.com/canonical/microcloud/microcloud/version"
+)
+
+// Manifest resource types, named after the matching Terraform/OpenTofu resources.
+const (
+	ManifestStoragePool   = "lxd_storage_pool"
+	ManifestNetwork       = "lxd_network"
+	ManifestProfile       = "lxd_profile"
+	ManifestStorageVolume = "lxd_volume"
+	ManifestCephPool      = "microceph_pool"
+)
+
+// Manifest lists the resources set up by MicroCloud, for import by infrastructure-as-code tooling.
+type Manifest struct {
+	MicroCloudVersion string             `json:"microcloud_version"`
+	Resources         []ManifestResource `json:"resources"`
+}
+
+// ManifestResource is a single resource set up by MicroCloud.
+type ManifestResource struct {
+	// ID is a stable identifier of the resource, made of its type and name.
+	ID string `json:"id"`
+
+	// Type is the type of the resource.
+	Type string `json:"type"`
+
+	// Name is the name of the resource.
+	Name string `json:"name"`
+
+	// Pool is the storage pool of a storage volume.
+	Pool string `json:"pool,omitempty"`
+
+	// Target is the cluster member of a member specific resource.
+	Target string `json:"target,omitempty"`
+
+	// Driver is the storage driver or network type of the resource.
+	Driver string `json:"driver,omitempty"`
+
+	// Config is the cluster-wide configuration of the resource.
+	Config map[string]string `json:"config,omitempty"`
+
+	// MemberConfig is the member specific configuration of the resource, keyed by cluster member.
+	MemberConfig map[string]map[string]string `json:"member_config,omitempty"`
+
+	// Devices are the devices of a profile.
+	Devices map[string]map[string]string `json:"devices,omitempty"`
+}
+
+// manifestID returns the stable identifier of a resource.
+func manifestID(resourceType string, parts ...string) string {
+	return resourceType + "/" + strings.Join(parts, "/")
+}
+
+// buildManifest returns the manifest of the resources set up on the given systems, with bootstrapName being the
+// system which set up the cluster-wide resources.
+func buildManifest(bootstrapName string, systems map[string]InitSystem, profile lxdAPI.ProfilesPost, cephPools []string, resourceNames service.ResourceNames, volumes VolumeOptions) Manifest {
+	manifest := Manifest{MicroCloudVersion: version.Version(), Resources: []ManifestResource{}}
+	bootstrapSystem := systems[bootstrapName]
+
+	names := make([]string, 0, len(systems))
+	for name := range systems {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	memberConfig := func(entity string, resource string) map[string]map[string]string {
+		config := map[string]map[string]string{}
+		for _, name := range names {
+			system := systems[name]
+			if entity == "storage-pool" {
+				for _, pool := range system.TargetStoragePools {
+					if pool.Name == resource && len(pool.Config) > 0 {
+						config[name] = pool.Config
+					}
+				}
+			} else {
+				for _, network := range system.TargetNetworks {
+					if network.Name == resource && len(network.Config) > 0 {
+						config[name] = network.Config
+					}
+				}
+			}
+
+			for _, cfg := range system.JoinConfig {
+				if cfg.Entity == entity && cfg.Name == resource {
+					if config[name] == nil {
+						config[name] = map[string]string{}
+					}
+
+					config[name][cfg.Key] = cfg.Value
+				}
+			}
+		}
+
+		if len(config) == 0 {
+			return nil
+		}
+
+		return config
+	}
+
+	for _, pool := range bootstrapSystem.StoragePools {
+		manifest.Resources = append(manifest.Resources, ManifestResource{
+			ID:           manifestID(ManifestStoragePool, pool.Name),
+			Type:         ManifestStoragePool,
+			Name:         pool.Name,
+			Driver:       pool.Driver,
+			Config:       pool.Config,
+			MemberConfig: memberConfig("storage-pool", pool.Name),
+		})
+	}
+
+	for _, network := range bootstrapSystem.Networks {
+		manifest.Resources = append(manifest.Resources, ManifestResource{
+			ID:           manifestID(ManifestNetwork, network.Name),
+			Type:         ManifestNetwork,
+			Name:         network.Name,
+			Driver:       network.Type,
+			Config:       network.Config,
+			MemberConfig: memberConfig("network", network.Name),
+		})
+	}
+
+	if len(bootstrapSystem.StoragePools) > 0 || len(bootstrapSystem.Networks) > 0 {
+		manifest.Resources = append(manifest.Resources, ManifestResource{
+			ID:      manifestID(ManifestProfile, profile.Name),
+			Type:    ManifestProfile,
+			Name:    profile.Name,
+			Config:  profile.Config,
+			Devices: profile.Devices,
+		})
+	}
+
+	for _, name := range names {
+		pool := volumes.memberPool(systems[name], resourceNames)
+		if pool == "" {
+			continue
+		}
+
+		for _, volumeType := range []string{"backups", "images"} {
+			volume := volumes.volumeName(volumeType, name)
+			manifest.Resources = append(manifest.Resources, ManifestResource{
+				ID:     manifestID(ManifestStorageVolume, pool, volume, name),
+				Type:   ManifestStorageVolume,
+				Name:   volume,
+				Pool:   pool,
+				Target: name,
+			})
+		}
+	}
+
+	for _, pool := range cephPools {
+		manifest.Resources = append(manifest.Resources, ManifestResource{
+			ID:   manifestID(ManifestCephPool, pool),
+			Type: ManifestCephPool,
+			Name: pool,
+		})
+	}
+
+	return manifest
+}
+
+// writeManifest writes the manifest of the resources set up by MicroCloud to the manifest path.
+func (c *initConfig) writeManifest(s *service.Handler, profile lxdAPI.ProfilesPost) error {
+	cephPools := []string{}
+	if s.Services[types.MicroCeph] != nil {
+		pools, err := s.Services[types.MicroCeph].(*service.CephService).GetPools(context.Background(), s.Name)
+		if err != nil {
+			return fmt.Errorf("Failed to get the MicroCeph pools: %w", err)
+		}
+
+		for _, pool := range pools {
+			cephPools = append(cephPools, pool.Pool)
+		}
+
+		sort.Strings(cephPools)
+	}
+
+	manifest := buildManifest(s.Name, c.systems, profile, cephPools, s.Services[types.LXD].(*service.LXDService).ResourceNames(), c.volumes)
+	data, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return fmt.Errorf("Failed to format the manifest: %w", err)
+	}
+
+	err = os.WriteFile(c.manifestPath, append(data, '\n'), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write the manifest to %q: %w", c.manifestPath, err)
+	}
+
+	fmt.Printf("Wrote the manifest of the set up resources to %q\n", c.manifestPath)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type manifestSuite struct {
+	suite.Suite
+}
+
+func TestManifestSuite(t *testing.T) {
+	suite.Run(t, new(manifestSuite))
+}
+
+func (s *manifestSuite) Test_buildManifest() {
+	systems := map[string]InitSystem{
+		"micro01": {
+			TargetStoragePools: []lxdAPI.StoragePoolsPost{{Name: "local", Driver: "zfs", StoragePoolPut: lxdAPI.StoragePoolPut{Config: map[string]string{"source": "/dev/sdb"}}}},
+			TargetNetworks:     []lxdAPI.NetworksPost{{Name: "UPLINK", Type: "physical", NetworkPut: lxdAPI.NetworkPut{Config: map[string]string{"parent": "eth1"}}}},
+			StoragePools:       []lxdAPI.StoragePoolsPost{{Name: "local", Driver: "zfs"}, {Name: "remote", Driver: "ceph", StoragePoolPut: lxdAPI.StoragePoolPut{Config: map[string]string{"ceph.osd.pg_num": "32"}}}},
+			Networks:           []lxdAPI.NetworksPost{{Name: "UPLINK", Type: "physical"}, {Name: "default", Type: "ovn", NetworkPut: lxdAPI.NetworkPut{Config: map[string]string{"network": "UPLINK"}}}},
+		},
+		"micro02": {
+			JoinConfig: []lxdAPI.ClusterMemberConfigKey{
+				{Entity: "storage-pool", Name: "local", Key: "source", Value: "/dev/sdc"},
+				{Entity: "network", Name: "UPLINK", Key: "parent", Value: "eth2"},
+			},
+		},
+		"micro03": {},
+	}
+
+	profile := lxdAPI.ProfilesPost{Name: "default", ProfilePut: lxdAPI.ProfilePut{Devices: map[string]map[string]string{"root": {"path": "/", "pool": "remote", "type": "disk"}}}}
+
+	manifest := buildManifest("micro01", systems, profile, []string{".mgr", "lxd_remote"}, service.DefaultResourceNames(), VolumeOptions{})
+
+	ids := []string{}
+	resources := map[string]ManifestResource{}
+	for _, resource := range manifest.Resources {
+		ids = append(ids, resource.ID)
+		resources[resource.ID] = resource
+	}
+
+	s.Equal([]string{
+		"lxd_storage_pool/local",
+		"lxd_storage_pool/remote",
+		"lxd_network/UPLINK",
+		"lxd_network/default",
+		"lxd_profile/default",
+		"lxd_volume/local/backups/micro01",
+		"lxd_volume/local/images/micro01",
+		"lxd_volume/local/backups/micro02",
+		"lxd_volume/local/images/micro02",
+		"microceph_pool/.mgr",
+		"microceph_pool/lxd_remote",
+	}, ids)
+
+	s.Equal(map[string]map[string]string{"micro01": {"source": "/dev/sdb"}, "micro02": {"source": "/dev/sdc"}}, resources["lxd_storage_pool/local"].MemberConfig)
+	s.Equal(map[string]map[string]string{"micro01": {"parent": "eth1"}, "micro02": {"parent": "eth2"}}, resources["lxd_network/UPLINK"].MemberConfig)
+	s.Nil(resources["lxd_storage_pool/remote"].MemberConfig)
+	s.Equal("ovn", resources["lxd_network/default"].Driver)
+	s.Equal("micro02", resources["lxd_volume/local/images/micro02"].Target)
+	s.Equal(profile.Devices, resources["lxd_profile/default"].Devices)
+}
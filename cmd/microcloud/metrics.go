@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// Files written by the metrics-certificate command.
+const (
+	metricsCertFile = "metrics.crt"
+	metricsKeyFile  = "metrics.key"
+	metricsCAFile   = "lxd.crt"
+)
+
+type cmdMetricsCertificate struct {
+	common *CmdControl
+
+	flagName string
+	flagDir  string
+}
+
+// prometheusConfig is the subset of the Prometheus configuration file used for scraping LXD.
+type prometheusConfig struct {
+	ScrapeConfigs []prometheusScrapeConfig `yaml:"scrape_configs"`
+}
+
+// prometheusScrapeConfig is a Prometheus scrape job.
+type prometheusScrapeConfig struct {
+	JobName       string                   `yaml:"job_name"`
+	MetricsPath   string                   `yaml:"metrics_path"`
+	Scheme        string                   `yaml:"scheme"`
+	StaticConfigs []prometheusStaticConfig `yaml:"static_configs"`
+	TLSConfig     prometheusTLSConfig      `yaml:"tls_config"`
+}
+
+// prometheusStaticConfig is a statically configured Prometheus target.
+type prometheusStaticConfig struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+}
+
+// prometheusTLSConfig is the TLS configuration of a Prometheus scrape job.
+type prometheusTLSConfig struct {
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name,omitempty"`
+}
+
+// command returns the subcommand to set up a metrics certificate for an external Prometheus.
+func (c *cmdMetricsCertificate) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics-certificate",
+		Short: "Create a metrics certificate and print a Prometheus scrape config for LXD",
+		Long: `Create a metrics certificate and print a Prometheus scrape config for LXD
+
+A new client certificate is generated and trusted by LXD for the metrics endpoint only.
+The certificate, its key and the LXD server certificate are written to the given directory,
+and a Prometheus scrape config covering all cluster members is printed.
+
+Copy the files to the Prometheus server, and adjust the paths in the scrape config if needed.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagName, "name", "microcloud-metrics", "Name of the certificate in the LXD trust store"+"``")
+	cmd.Flags().StringVar(&c.flagDir, "dir", ".", "Directory to write the certificate files to"+"``")
+
+	return cmd
+}
+
+// run runs the subcommand to set up a metrics certificate for an external Prometheus.
+func (c *cmdMetricsCertificate) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.LXD)
+	if err != nil {
+		return err
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(context.Background())
+	if err != nil {
+		return err
+	}
+
+	server, _, err := lxdClient.GetServer()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD server: %w", err)
+	}
+
+	members, err := lxdClient.GetClusterMembers()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD cluster members: %w", err)
+	}
+
+	targets := make(map[string]string, len(members))
+	for _, member := range members {
+		memberServer, _, err := lxdClient.UseTarget(member.ServerName).GetServer()
+		if err != nil {
+			return fmt.Errorf("Failed to get LXD server of %q: %w", member.ServerName, err)
+		}
+
+		metricsListenAddress, _ := memberServer.Config["core.metrics_address"].(string)
+		targets[member.ServerName], err = metricsAddress(member, metricsListenAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	serverName, err := certServerName(server.Environment.Certificate)
+	if err != nil {
+		return err
+	}
+
+	dir, err := filepath.Abs(c.flagDir)
+	if err != nil {
+		return fmt.Errorf("Failed to resolve %q: %w", c.flagDir, err)
+	}
+
+	for _, file := range []string{metricsCertFile, metricsKeyFile} {
+		if shared.PathExists(filepath.Join(dir, file)) {
+			return withExitCode(ExitCodeValidation, fmt.Errorf("%q already exists", filepath.Join(dir, file)))
+		}
+	}
+
+	cert, key, err := shared.GenerateMemCert(true, shared.CertOptions{CommonName: c.flagName})
+	if err != nil {
+		return fmt.Errorf("Failed to generate the metrics certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return errors.New("Failed to decode the metrics certificate")
+	}
+
+	// The LXD trust store is shared by all cluster members, so the certificate is trusted on each of them.
+	err = lxdClient.CreateCertificate(lxdAPI.CertificatesPost{
+		Name:        c.flagName,
+		Type:        lxdAPI.CertificateTypeMetrics,
+		Certificate: base64.StdEncoding.EncodeToString(block.Bytes),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to trust the metrics certificate: %w", err)
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create %q: %w", dir, err)
+	}
+
+	files := map[string][]byte{metricsCertFile: cert, metricsKeyFile: key, metricsCAFile: []byte(server.Environment.Certificate)}
+	for file, content := range files {
+		err = os.WriteFile(filepath.Join(dir, file), content, 0600)
+		if err != nil {
+			return fmt.Errorf("Failed to write %q: %w", file, err)
+		}
+	}
+
+	config := buildPrometheusConfig(targets, serverName, dir)
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("Failed to encode the Prometheus scrape config: %w", err)
+	}
+
+	fmt.Print(string(data))
+
+	return nil
+}
+
+// metricsAddress returns the address serving the metrics of the given LXD cluster member.
+// The dedicated metrics address is used if configured, otherwise the metrics are scraped from the cluster address.
+func metricsAddress(member lxdAPI.ClusterMember, metricsListenAddress string) (string, error) {
+	memberURL, err := url.Parse(member.URL)
+	if err != nil {
+		return "", fmt.Errorf("Invalid URL of LXD cluster member %q: %w", member.ServerName, err)
+	}
+
+	if metricsListenAddress == "" {
+		return memberURL.Host, nil
+	}
+
+	address := util.CanonicalNetworkAddress(metricsListenAddress, shared.HTTPSMetricsDefaultPort)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("Invalid metrics address of LXD cluster member %q: %w", member.ServerName, err)
+	}
+
+	// A wildcard listen address is reachable on the cluster address of the member.
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsUnspecified() {
+		host = memberURL.Hostname()
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// certServerName returns the name to verify the given LXD server certificate against.
+func certServerName(certPEM string) (string, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return "", errors.New("Failed to decode the LXD server certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse the LXD server certificate: %w", err)
+	}
+
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0], nil
+	}
+
+	return cert.Subject.CommonName, nil
+}
+
+// buildPrometheusConfig returns the Prometheus scrape config for the metrics of the given LXD cluster members, keyed by name.
+// The certificate files are expected in the given directory.
+func buildPrometheusConfig(targets map[string]string, serverName string, dir string) prometheusConfig {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	staticConfigs := make([]prometheusStaticConfig, 0, len(names))
+	for _, name := range names {
+		staticConfigs = append(staticConfigs, prometheusStaticConfig{
+			Targets: []string{targets[name]},
+			Labels:  map[string]string{"member": name},
+		})
+	}
+
+	return prometheusConfig{
+		ScrapeConfigs: []prometheusScrapeConfig{{
+			JobName:       "lxd",
+			MetricsPath:   "/1.0/metrics",
+			Scheme:        "https",
+			StaticConfigs: staticConfigs,
+			TLSConfig: prometheusTLSConfig{
+				CAFile:     filepath.Join(dir, metricsCAFile),
+				CertFile:   filepath.Join(dir, metricsCertFile),
+				KeyFile:    filepath.Join(dir, metricsKeyFile),
+				ServerName: serverName,
+			},
+		}},
+	}
+}
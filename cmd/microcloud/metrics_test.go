@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type metricsSuite struct {
+	suite.Suite
+}
+
+func TestMetricsSuite(t *testing.T) {
+	suite.Run(t, new(metricsSuite))
+}
+
+func (s *metricsSuite) Test_metricsAddress() {
+	cases := []struct {
+		desc           string
+		metricsAddress string
+		address        string
+	}{
+		{desc: "Cluster address", address: "10.0.0.1:8443"},
+		{desc: "Dedicated metrics address", metricsAddress: "10.0.1.1:9200", address: "10.0.1.1:9200"},
+		{desc: "Dedicated metrics address without port", metricsAddress: "10.0.1.1", address: "10.0.1.1:9100"},
+		{desc: "Wildcard metrics address", metricsAddress: "[::]:9100", address: "10.0.0.1:9100"},
+	}
+
+	member := lxdAPI.ClusterMember{ServerName: "micro01", URL: "https://10.0.0.1:8443"}
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		address, err := metricsAddress(member, c.metricsAddress)
+		s.NoError(err)
+		s.Equal(c.address, address)
+	}
+}
+
+func (s *metricsSuite) Test_buildPrometheusConfig() {
+	config := buildPrometheusConfig(map[string]string{"micro02": "10.0.0.2:8443", "micro01": "10.0.0.1:8443"}, "lxd.local", "/etc/prometheus/tls")
+
+	s.Require().Len(config.ScrapeConfigs, 1)
+	job := config.ScrapeConfigs[0]
+	s.Equal("/1.0/metrics", job.MetricsPath)
+	s.Equal([]prometheusStaticConfig{
+		{Targets: []string{"10.0.0.1:8443"}, Labels: map[string]string{"member": "micro01"}},
+		{Targets: []string{"10.0.0.2:8443"}, Labels: map[string]string{"member": "micro02"}},
+	}, job.StaticConfigs)
+	s.Equal(prometheusTLSConfig{
+		CAFile:     "/etc/prometheus/tls/lxd.crt",
+		CertFile:   "/etc/prometheus/tls/metrics.crt",
+		KeyFile:    "/etc/prometheus/tls/metrics.key",
+		ServerName: "lxd.local",
+	}, job.TLSConfig)
+}
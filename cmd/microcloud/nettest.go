@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/units"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+const (
+	// networkTestOK is the status of a link within the thresholds.
+	networkTestOK = "OK"
+
+	// networkTestSlow is the status of a link below the bandwidth threshold or above the latency threshold.
+	networkTestSlow = "SLOW"
+
+	// networkTestFailed is the status of a link which couldn't be measured.
+	networkTestFailed = "FAILED"
+)
+
+// networkTestPeers returns the peers each cluster member measures, given the addresses of every member on each network.
+// A link is only measured if both members have an address on the network.
+func networkTestPeers(addresses map[string]map[string]string, networks []string) map[string][]types.NetworkTestPeer {
+	members := make([]string, 0, len(addresses))
+	for name := range addresses {
+		members = append(members, name)
+	}
+
+	sort.Strings(members)
+
+	peers := map[string][]types.NetworkTestPeer{}
+	for _, from := range members {
+		for _, network := range networks {
+			if addresses[from][network] == "" {
+				continue
+			}
+
+			for _, to := range members {
+				address := addresses[to][network]
+				if to == from || address == "" {
+					continue
+				}
+
+				peers[from] = append(peers[from], types.NetworkTestPeer{Name: to, Network: network, Address: address})
+			}
+		}
+	}
+
+	return peers
+}
+
+// networkTestStatus returns whether the measured link failed, or is below the minimum bandwidth or above the maximum latency.
+func networkTestStatus(result types.NetworkTestResult, minBandwidth int64, maxLatency time.Duration) string {
+	if result.Error != "" {
+		return networkTestFailed
+	}
+
+	if result.Bandwidth < minBandwidth || time.Duration(result.Latency) > maxLatency {
+		return networkTestSlow
+	}
+
+	return networkTestOK
+}
+
+// formatBitrate returns the given bandwidth in bits per second in a human readable form.
+func formatBitrate(bandwidth int64) string {
+	value := float64(bandwidth)
+	for _, unit := range []string{"bit/s", "kbit/s", "Mbit/s", "Gbit/s"} {
+		if value < 1000 {
+			return fmt.Sprintf("%.1f%s", value, unit)
+		}
+
+		value /= 1000
+	}
+
+	return fmt.Sprintf("%.1fTbit/s", value)
+}
+
+type cmdNetwork struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to inspect the networks between cluster members.
+func (c *cmdNetwork) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Inspect the networks between cluster members",
+		RunE:  func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdList = cmdNetworkList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	var cmdCreate = cmdNetworkCreate{common: c.common}
+	cmd.AddCommand(cmdCreate.command())
+
+	var cmdEditUplink = cmdNetworkEditUplink{common: c.common}
+	cmd.AddCommand(cmdEditUplink.command())
+
+	var cmdSetUnderlay = cmdNetworkSetUnderlay{common: c.common}
+	cmd.AddCommand(cmdSetUnderlay.command())
+
+	var cmdRotateOVNCertificates = cmdNetworkRotateOVNCertificates{common: c.common}
+	cmd.AddCommand(cmdRotateOVNCertificates.command())
+
+	var cmdTest = cmdNetworkTest{common: c.common}
+	cmd.AddCommand(cmdTest.command())
+
+	return cmd
+}
+
+type cmdNetworkTest struct {
+	common *CmdControl
+
+	flagNetworks     []string
+	flagDuration     time.Duration
+	flagMinBandwidth string
+	flagMaxLatency   time.Duration
+	flagFormat       string
+}
+
+// command returns the subcommand to measure the bandwidth and latency between cluster members.
+func (c *cmdNetworkTest) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Measure the bandwidth and latency between cluster members",
+		Long: fmt.Sprintf(`Measure the bandwidth and latency between cluster members
+
+Each cluster member runs a built-in test server on port %d for the duration of the test.
+The links between every pair of members are then measured in turn, over each network both members are part of.
+
+Supported networks:
+  management    Network the MicroCloud cluster members communicate over
+  ceph-public   Ceph public network
+  ceph-cluster  Ceph cluster network, used for replication between OSDs
+  ovn-underlay  Network carrying the OVN Geneve tunnels
+
+Links below the minimum bandwidth or above the maximum latency are highlighted, and make the command fail.`, service.NetworkTestPort),
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringSliceVar(&c.flagNetworks, "network", types.NetworkTestNetworks, "Network to test, can be repeated"+"``")
+	cmd.Flags().DurationVar(&c.flagDuration, "duration", api.DefaultNetworkTestDuration, "Duration of each bandwidth measurement"+"``")
+	cmd.Flags().StringVar(&c.flagMinBandwidth, "min-bandwidth", "1Gbit", "Minimum expected bandwidth of each link"+"``")
+	cmd.Flags().DurationVar(&c.flagMaxLatency, "max-latency", 5*time.Millisecond, "Maximum expected round trip time of each link"+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	_ = cmd.RegisterFlagCompletionFunc("network", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return types.NetworkTestNetworks, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// run runs the subcommand to measure the bandwidth and latency between cluster members.
+func (c *cmdNetworkTest) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	for _, network := range c.flagNetworks {
+		if !slices.Contains(types.NetworkTestNetworks, network) {
+			return withExitCode(ExitCodeUsage, fmt.Errorf("Unknown network %q: Must be one of %s", network, strings.Join(types.NetworkTestNetworks, ", ")))
+		}
+	}
+
+	minBandwidth, err := units.ParseBitSizeString(c.flagMinBandwidth)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid minimum bandwidth %q: %w", c.flagMinBandwidth, err))
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	members, err := client.GetClusterMembers(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	if len(members) < 2 {
+		return errors.New("Testing the network requires at least two cluster members")
+	}
+
+	token, err := shared.RandomCryptoString()
+	if err != nil {
+		return fmt.Errorf("Failed to generate the network test token: %w", err)
+	}
+
+	// The test servers run until every member measured its links, with some room for slow connections.
+	timeout := time.Duration(len(members)*len(members)*len(c.flagNetworks))*(c.flagDuration+5*time.Second) + time.Minute
+
+	addresses := map[string]map[string]string{}
+	for _, member := range members {
+		targetClient := client.UseTarget(member.Name)
+
+		// Stop the test servers even if a later member fails to start its own.
+		defer func() { _ = cloudClient.StopNetworkTestServer(context.Background(), targetClient) }()
+
+		server, err := cloudClient.StartNetworkTestServer(cmd.Context(), targetClient, types.NetworkTestServerPut{Token: token, Timeout: int64(timeout.Seconds())})
+		if err != nil {
+			return fmt.Errorf("Failed to start the network test server on %q: %w", member.Name, err)
+		}
+
+		addresses[member.Name] = server.Addresses
+	}
+
+	peers := networkTestPeers(addresses, c.flagNetworks)
+	if len(peers) == 0 {
+		return errors.New("No cluster members share any of the selected networks")
+	}
+
+	sources := make([]string, 0, len(peers))
+	for name := range peers {
+		sources = append(sources, name)
+	}
+
+	sort.Strings(sources)
+
+	results := []types.NetworkTestResult{}
+	for _, name := range sources {
+		// Report progress on stderr, so it doesn't mix with machine readable output.
+		fmt.Fprintf(os.Stderr, "Measuring the network links from %q ...\n", name)
+
+		args := types.NetworkTestPost{Token: token, Peers: peers[name], Duration: int64(c.flagDuration.Seconds())}
+		memberResults, err := cloudClient.RunNetworkTest(cmd.Context(), client.UseTarget(name), args)
+		if err != nil {
+			return fmt.Errorf("Failed to measure the network links from %q: %w", name, err)
+		}
+
+		results = append(results, memberResults...)
+	}
+
+	// Only highlight the status in human readable output.
+	highlight := c.flagFormat == tui.TableFormatTable || c.flagFormat == tui.TableFormatCompact
+
+	issues := 0
+	rows := make([][]string, 0, len(results))
+	for _, result := range results {
+		status := networkTestStatus(result, minBandwidth, c.flagMaxLatency)
+		if status != networkTestOK {
+			issues++
+		}
+
+		bandwidth := "-"
+		latency := "-"
+		if result.Error == "" {
+			bandwidth = formatBitrate(result.Bandwidth)
+			latency = formatLatency(result.Latency)
+		}
+
+		if highlight {
+			switch status {
+			case networkTestSlow:
+				status = tui.WarningColor(status, true)
+			case networkTestFailed:
+				status = tui.ErrorColor(status, true)
+			}
+		}
+
+		rows = append(rows, []string{result.From, result.To, result.Network, result.Address, bandwidth, latency, status})
+	}
+
+	header := []string{"FROM", "TO", "NETWORK", "ADDRESS", "BANDWIDTH", "LATENCY", "STATUS"}
+	table, err := tui.FormatData(c.flagFormat, header, rows, results)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	for _, result := range results {
+		if result.Error != "" && highlight {
+			tui.PrintWarning(fmt.Sprintf("Failed to measure the %s link from %q to %q: %s", result.Network, result.From, result.To, result.Error))
+		}
+	}
+
+	if issues > 0 {
+		return fmt.Errorf("%d network links failed or are below the thresholds", issues)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type networkTestSuite struct {
+	suite.Suite
+}
+
+func TestNetworkTestSuite(t *testing.T) {
+	suite.Run(t, new(networkTestSuite))
+}
+
+func (s *networkTestSuite) Test_networkTestPeers() {
+	addresses := map[string]map[string]string{
+		"n1": {types.NetworkTestManagement: "10.0.0.1", types.NetworkTestCephCluster: "10.0.1.1"},
+		"n2": {types.NetworkTestManagement: "10.0.0.2", types.NetworkTestCephCluster: "10.0.1.2"},
+		"n3": {types.NetworkTestManagement: "10.0.0.3"},
+	}
+
+	peers := networkTestPeers(addresses, []string{types.NetworkTestManagement, types.NetworkTestCephCluster})
+
+	s.Equal([]types.NetworkTestPeer{
+		{Name: "n2", Network: types.NetworkTestManagement, Address: "10.0.0.2"},
+		{Name: "n3", Network: types.NetworkTestManagement, Address: "10.0.0.3"},
+		{Name: "n2", Network: types.NetworkTestCephCluster, Address: "10.0.1.2"},
+	}, peers["n1"])
+
+	s.Equal([]types.NetworkTestPeer{
+		{Name: "n1", Network: types.NetworkTestManagement, Address: "10.0.0.1"},
+		{Name: "n2", Network: types.NetworkTestManagement, Address: "10.0.0.2"},
+	}, peers["n3"])
+
+	peers = networkTestPeers(addresses, []string{types.NetworkTestOVNUnderlay})
+	s.Empty(peers)
+}
+
+func (s *networkTestSuite) Test_networkTestStatus() {
+	cases := []struct {
+		desc   string
+		result types.NetworkTestResult
+		status string
+	}{
+		{
+			desc:   "Link within the thresholds",
+			result: types.NetworkTestResult{Bandwidth: 10_000_000_000, Latency: int64(time.Millisecond)},
+			status: networkTestOK,
+		},
+		{
+			desc:   "Link below the minimum bandwidth",
+			result: types.NetworkTestResult{Bandwidth: 100_000_000, Latency: int64(time.Millisecond)},
+			status: networkTestSlow,
+		},
+		{
+			desc:   "Link above the maximum latency",
+			result: types.NetworkTestResult{Bandwidth: 10_000_000_000, Latency: int64(10 * time.Millisecond)},
+			status: networkTestSlow,
+		},
+		{
+			desc:   "Failed measurement",
+			result: types.NetworkTestResult{Error: "connection refused"},
+			status: networkTestFailed,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.status, networkTestStatus(c.result, 1_000_000_000, 5*time.Millisecond))
+	}
+}
+
+func (s *networkTestSuite) Test_formatBitrate() {
+	s.Equal("512.0bit/s", formatBitrate(512))
+	s.Equal("9.4Gbit/s", formatBitrate(9_412_000_000))
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/canonical/lxd/shared"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ovnNetworkOptions are the choices of the OVN network created on an uplink network.
+// Empty values are filled in from the configuration of the uplink network.
+type ovnNetworkOptions struct {
+	Description string
+	IPv4Address string
+	IPv6Address string
+	DNSSearch   string
+
+	// IPv6Routed gives the network the next free routed /64 subnet of the prefix delegated to the uplink network, instead of NAT.
+	IPv6Routed bool
+}
+
+type cmdNetworkCreate struct {
+	common *CmdControl
+
+	flagProject     string
+	flagUplink      string
+	flagDescription string
+	flagIPv4Address string
+	flagIPv6Address string
+	flagIPv6Routed  bool
+	flagDNSSearch   string
+}
+
+// command returns the subcommand to create an OVN network on the uplink network.
+func (c *cmdNetworkCreate) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <network>",
+		Short: "Create an OVN network for a project on the uplink network",
+		Long: `Create an OVN network for a project on the uplink network
+
+The network is created on the uplink network set up by MicroCloud, unless another one is given.
+Its IPv4 and IPv6 subnets are picked by LXD if the uplink network has a gateway of the same family, and disabled otherwise.
+With --routed-ipv6, the network gets the next free /64 subnet of the IPv6 prefix delegated to the uplink network instead of NAT.
+The DNS search domains of the default OVN network are used unless others are given.
+
+The project must have networks of its own (features.networks), and be allowed to use the uplink network if restricted.`,
+		Example: `  microcloud network create tenant1 --project tenant1
+  microcloud network create tenant1 --project tenant1 --ipv4-address 10.10.1.1/24 --routed-ipv6
+  microcloud network create internal --ipv4-address 10.20.0.1/24 --ipv6-address none`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagProject, "project", "default", "Project to create the network in"+"``")
+	cmd.Flags().StringVar(&c.flagUplink, "uplink", "", "Uplink network of the network, the one set up by MicroCloud by default"+"``")
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", "Description of the network"+"``")
+	cmd.Flags().StringVar(&c.flagIPv4Address, "ipv4-address", "", "IPv4 address (CIDR) of the network, or auto or none"+"``")
+	cmd.Flags().StringVar(&c.flagIPv6Address, "ipv6-address", "", "IPv6 address (CIDR) of the network, or auto or none"+"``")
+	cmd.Flags().BoolVar(&c.flagIPv6Routed, "routed-ipv6", false, "Use a routed subnet of the IPv6 prefix of the uplink network instead of NAT")
+	cmd.Flags().StringVar(&c.flagDNSSearch, "dns-search", "", "Comma separated DNS search domains of the network"+"``")
+
+	return cmd
+}
+
+// run runs the subcommand to create an OVN network on the uplink network.
+func (c *cmdNetworkCreate) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	name := args[0]
+	err := validate.IsInterfaceName(name)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid network name %q: %w", name, err))
+	}
+
+	opts := ovnNetworkOptions{
+		Description: c.flagDescription,
+		IPv4Address: c.flagIPv4Address,
+		IPv6Address: c.flagIPv6Address,
+		DNSSearch:   c.flagDNSSearch,
+		IPv6Routed:  c.flagIPv6Routed,
+	}
+
+	err = opts.validate()
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.MicroCloud, types.LXD)
+	if err != nil {
+		return err
+	}
+
+	microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	config, err := cloudClient.GetConfig(context.Background(), microClient)
+	if err != nil {
+		return err
+	}
+
+	names := service.ResourceNamesFromConfig(config)
+	uplinkName := c.flagUplink
+	if uplinkName == "" {
+		uplinkName = names.UplinkNetwork
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(context.Background())
+	if err != nil {
+		return err
+	}
+
+	uplink, _, err := lxdClient.GetNetwork(uplinkName)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD network %q: %w", uplinkName, err)
+	}
+
+	if uplink.Type != "physical" {
+		return fmt.Errorf("Network %q is not an uplink network", uplinkName)
+	}
+
+	project, _, err := lxdClient.GetProject(c.flagProject)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD project %q: %w", c.flagProject, err)
+	}
+
+	err = checkNetworkProject(*project, uplinkName)
+	if err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+
+	// The default OVN network lives in the default project, and gives its DNS search domains to the new network.
+	defaultNetwork, _, err := lxdClient.GetNetwork(names.OVNNetwork)
+	if err == nil && defaultNetwork.Type == "ovn" && opts.DNSSearch == "" {
+		opts.DNSSearch = defaultNetwork.Config["dns.search"]
+	}
+
+	networks, err := lxdClient.GetNetworksAllProjects()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD networks: %w", err)
+	}
+
+	networkConfig, err := opts.networkConfig(*uplink, networks)
+	if err != nil {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("Cannot create network %q on %q: %w", name, uplinkName, err))
+	}
+
+	description := opts.Description
+	if description == "" {
+		description = fmt.Sprintf("OVN network of project %q", project.Name)
+	}
+
+	err = lxdClient.UseProject(project.Name).CreateNetwork(lxdAPI.NetworksPost{
+		NetworkPut: lxdAPI.NetworkPut{Config: networkConfig, Description: description},
+		Name:       name,
+		Type:       "ovn",
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to create LXD network %q in project %q: %w", name, project.Name, err)
+	}
+
+	fmt.Println(tui.SummarizeResult("Created OVN network %s in project %s on %s", name, project.Name, uplinkName))
+
+	return nil
+}
+
+// validate checks the addresses and DNS search domains of the network.
+func (o ovnNetworkOptions) validate() error {
+	// Besides a subnet, LXD takes auto to pick one, and none to disable the IP family.
+	isAddress := func(validator func(string) error) func(string) error {
+		return func(value string) error {
+			if value == "auto" || value == "none" {
+				return nil
+			}
+
+			return validator(value)
+		}
+	}
+
+	validators := []struct {
+		name      string
+		value     string
+		validator func(string) error
+	}{
+		{name: "IPv4 address", value: o.IPv4Address, validator: isAddress(validate.IsNetworkAddressCIDRV4)},
+		{name: "IPv6 address", value: o.IPv6Address, validator: isAddress(validate.IsNetworkAddressCIDRV6)},
+		{name: "DNS search domains", value: o.DNSSearch, validator: validate.IsListOf(service.ValidateDNSDomain)},
+	}
+
+	for _, v := range validators {
+		err := validate.Optional(v.validator)(v.value)
+		if err != nil {
+			return fmt.Errorf("Invalid %s %q: %w", v.name, v.value, err)
+		}
+	}
+
+	if o.IPv6Routed && o.IPv6Address != "" {
+		return errors.New("A routed IPv6 subnet cannot be used along with an IPv6 address")
+	}
+
+	return nil
+}
+
+// networkConfig returns the configuration of the OVN network on the given uplink network.
+// The subnets of an IP family are disabled when the uplink network has no gateway of that family, unless given.
+// The routed IPv6 subnet is the first /64 subnet of the IPv6 routes of the uplink network not used by another network.
+func (o ovnNetworkOptions) networkConfig(uplink lxdAPI.Network, networks []lxdAPI.Network) (map[string]string, error) {
+	config := map[string]string{"network": uplink.Name}
+	if o.DNSSearch != "" {
+		config["dns.search"] = o.DNSSearch
+	}
+
+	if o.IPv4Address != "" {
+		config["ipv4.address"] = o.IPv4Address
+	} else if uplink.Config["ipv4.gateway"] == "" {
+		config["ipv4.address"] = "none"
+	}
+
+	if o.IPv6Address != "" {
+		config["ipv6.address"] = o.IPv6Address
+	} else if uplink.Config["ipv6.gateway"] == "" && !o.IPv6Routed {
+		config["ipv6.address"] = "none"
+	}
+
+	if o.IPv6Routed {
+		if uplink.Config["ipv6.routes"] == "" {
+			return nil, errors.New("Cannot route IPv6 without IPv6 routes on the uplink network")
+		}
+
+		used := []string{}
+		for _, network := range networks {
+			if network.Type == "ovn" && network.Config["ipv6.address"] != "" {
+				used = append(used, network.Config["ipv6.address"])
+			}
+		}
+
+		address, err := nextRoutedIPv6Address(uplink.Config["ipv6.routes"], used)
+		if err != nil {
+			return nil, err
+		}
+
+		config["ipv6.address"] = address
+		config["ipv6.nat"] = strconv.FormatBool(false)
+	}
+
+	return config, nil
+}
+
+// nextRoutedIPv6Address returns the address of an OVN network in the first /64 subnet of the given comma separated prefixes
+// which isn't used by any of the given IPv6 network addresses.
+func nextRoutedIPv6Address(routes string, used []string) (string, error) {
+	usedNets := []*net.IPNet{}
+	for _, address := range used {
+		_, subnet, err := net.ParseCIDR(address)
+		if err == nil {
+			usedNets = append(usedNets, subnet)
+		}
+	}
+
+	for _, prefix := range shared.SplitNTrimSpace(routes, ",", -1, true) {
+		_, prefixNet, err := net.ParseCIDR(prefix)
+		if err != nil || prefixNet.IP.To4() != nil {
+			continue
+		}
+
+		ones, _ := prefixNet.Mask.Size()
+		if ones > 64 {
+			continue
+		}
+
+		// Only look at the first subnets of large prefixes.
+		count := uint64(1) << min(64-ones, 16)
+		start := new(big.Int).SetBytes(prefixNet.IP.To16())
+		step := new(big.Int).Lsh(big.NewInt(1), 64)
+		for i := range count {
+			ip := make(net.IP, net.IPv6len)
+			new(big.Int).Add(start, new(big.Int).Mul(step, new(big.Int).SetUint64(i))).FillBytes(ip)
+			subnet := &net.IPNet{IP: ip, Mask: net.CIDRMask(64, 128)}
+			if slices.ContainsFunc(usedNets, func(other *net.IPNet) bool { return other.Contains(subnet.IP) || subnet.Contains(other.IP) }) {
+				continue
+			}
+
+			ip[len(ip)-1] = 1
+
+			return ip.String() + "/64", nil
+		}
+	}
+
+	return "", fmt.Errorf("No free /64 subnet left in IPv6 routes %q", routes)
+}
+
+// checkNetworkProject checks the OVN network can be created in the project on the given uplink network.
+func checkNetworkProject(project lxdAPI.Project, uplink string) error {
+	if project.Name != "default" && shared.IsFalseOrEmpty(project.Config["features.networks"]) {
+		return fmt.Errorf("Project %q uses the networks of the default project, it needs features.networks to have networks of its own", project.Name)
+	}
+
+	if shared.IsTrue(project.Config["restricted"]) && !slices.Contains(shared.SplitNTrimSpace(project.Config["restricted.networks.uplinks"], ",", -1, true), uplink) {
+		return fmt.Errorf("Project %q is restricted and not allowed to use uplink network %q, add it to restricted.networks.uplinks", project.Name, uplink)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type networkCreateSuite struct {
+	suite.Suite
+}
+
+func TestNetworkCreateSuite(t *testing.T) {
+	suite.Run(t, new(networkCreateSuite))
+}
+
+func (s *networkCreateSuite) Test_ovnNetworkOptionsValidate() {
+	cases := []struct {
+		desc    string
+		options ovnNetworkOptions
+		err     bool
+	}{
+		{desc: "No options", options: ovnNetworkOptions{}},
+		{desc: "Addresses", options: ovnNetworkOptions{IPv4Address: "10.10.0.1/24", IPv6Address: "fd42::1/64", DNSSearch: "example.com"}},
+		{desc: "Automatic and disabled addresses", options: ovnNetworkOptions{IPv4Address: "auto", IPv6Address: "none"}},
+		{desc: "IPv4 address without prefix length", options: ovnNetworkOptions{IPv4Address: "10.10.0.1"}, err: true},
+		{desc: "IPv6 address as IPv4 address", options: ovnNetworkOptions{IPv4Address: "fd42::1/64"}, err: true},
+		{desc: "Invalid DNS search domain", options: ovnNetworkOptions{DNSSearch: "example..com"}, err: true},
+		{desc: "Routed IPv6 with an IPv6 address", options: ovnNetworkOptions{IPv6Address: "fd42::1/64", IPv6Routed: true}, err: true},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := c.options.validate()
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+		}
+	}
+}
+
+func (s *networkCreateSuite) Test_ovnNetworkOptionsNetworkConfig() {
+	uplink := lxdAPI.Network{Name: "UPLINK", Config: map[string]string{"ipv4.gateway": "10.0.0.1/24", "ipv6.gateway": "fd42::1/64", "ipv6.routes": "fd43::/56"}}
+	networks := []lxdAPI.Network{
+		{Name: "default", Type: "ovn", Config: map[string]string{"ipv6.address": "fd43::1/64"}},
+		{Name: "lxdbr0", Type: "bridge", Config: map[string]string{"ipv6.address": "fd43:0:0:1::1/64"}},
+	}
+
+	s.T().Log("Subnets picked by LXD")
+	config, err := ovnNetworkOptions{DNSSearch: "example.com"}.networkConfig(uplink, networks)
+	s.NoError(err)
+	s.Equal(map[string]string{"network": "UPLINK", "dns.search": "example.com"}, config)
+
+	s.T().Log("Next free routed IPv6 subnet")
+	config, err = ovnNetworkOptions{IPv4Address: "10.10.0.1/24", IPv6Routed: true}.networkConfig(uplink, networks)
+	s.NoError(err)
+	s.Equal(map[string]string{"network": "UPLINK", "ipv4.address": "10.10.0.1/24", "ipv6.address": "fd43:0:0:1::1/64", "ipv6.nat": "false"}, config)
+
+	s.T().Log("IP families without gateway on the uplink network are disabled")
+	ipv4Uplink := lxdAPI.Network{Name: "UPLINK", Config: map[string]string{"ipv4.gateway": "10.0.0.1/24"}}
+	config, err = ovnNetworkOptions{}.networkConfig(ipv4Uplink, networks)
+	s.NoError(err)
+	s.Equal(map[string]string{"network": "UPLINK", "ipv6.address": "none"}, config)
+
+	s.T().Log("Routed IPv6 without IPv6 routes on the uplink network")
+	_, err = ovnNetworkOptions{IPv6Routed: true}.networkConfig(ipv4Uplink, networks)
+	s.Error(err)
+}
+
+func (s *networkCreateSuite) Test_nextRoutedIPv6Address() {
+	address, err := nextRoutedIPv6Address("fd43::/62", nil)
+	s.NoError(err)
+	s.Equal("fd43::1/64", address)
+
+	address, err = nextRoutedIPv6Address("fd43::/62", []string{"fd43::1/64", "fd43:0:0:2::1/64", "10.0.0.1/24"})
+	s.NoError(err)
+	s.Equal("fd43:0:0:1::1/64", address)
+
+	s.T().Log("Subnets used by a larger network")
+	address, err = nextRoutedIPv6Address("fd43::/62, fd44::/64", []string{"fd43::1/62"})
+	s.NoError(err)
+	s.Equal("fd44::1/64", address)
+
+	_, err = nextRoutedIPv6Address("fd43::/64", []string{"fd43::1/64"})
+	s.Error(err)
+}
+
+func (s *networkCreateSuite) Test_checkNetworkProject() {
+	cases := []struct {
+		desc    string
+		project lxdAPI.Project
+		err     bool
+	}{
+		{
+			desc:    "Default project",
+			project: lxdAPI.Project{Name: "default"},
+		},
+		{
+			desc:    "Project with its own networks",
+			project: lxdAPI.Project{Name: "tenant1", Config: map[string]string{"features.networks": "true"}},
+		},
+		{
+			desc:    "Project using the networks of the default project",
+			project: lxdAPI.Project{Name: "tenant1"},
+			err:     true,
+		},
+		{
+			desc:    "Restricted project allowed to use the uplink network",
+			project: lxdAPI.Project{Name: "tenant1", Config: map[string]string{"features.networks": "true", "restricted": "true", "restricted.networks.uplinks": "other, UPLINK"}},
+		},
+		{
+			desc:    "Restricted project not allowed to use the uplink network",
+			project: lxdAPI.Project{Name: "tenant1", Config: map[string]string{"features.networks": "true", "restricted": "true"}},
+			err:     true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := checkNetworkProject(c.project, "UPLINK")
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+		}
+	}
+}
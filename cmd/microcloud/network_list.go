@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// UplinkNetworkSummary is an OVN uplink network, merging the information of LXD and the MicroCloud cluster members.
+type UplinkNetworkSummary struct {
+	Name        string `json:"name" yaml:"name"`
+	IPv4Gateway string `json:"ipv4_gateway" yaml:"ipv4_gateway"`
+	IPv4Ranges  string `json:"ipv4_ranges" yaml:"ipv4_ranges"`
+	IPv6Gateway string `json:"ipv6_gateway" yaml:"ipv6_gateway"`
+	IPv6Ranges  string `json:"ipv6_ranges" yaml:"ipv6_ranges"`
+
+	// UsedIPs and FreeIPs are the number of addresses of the IPv4 ranges allocated to OVN networks and still available.
+	UsedIPs uint64 `json:"used_ips" yaml:"used_ips"`
+	FreeIPs uint64 `json:"free_ips" yaml:"free_ips"`
+
+	// Networks are the OVN networks using the uplink network.
+	Networks []string `json:"networks" yaml:"networks"`
+
+	// GatewayChassis are the chassis currently hosting the router of each OVN network, keyed by OVN network.
+	GatewayChassis map[string]string `json:"gateway_chassis" yaml:"gateway_chassis"`
+
+	Members []UplinkMemberSummary `json:"members" yaml:"members"`
+}
+
+// UplinkMemberSummary is the connection of a cluster member to an uplink network.
+type UplinkMemberSummary struct {
+	Name string `json:"name" yaml:"name"`
+
+	// Parent is the interface of the member connected to the uplink network.
+	Parent string `json:"parent" yaml:"parent"`
+
+	// UnderlayAddress is the address of the member carrying the OVN Geneve tunnels, if the underlay watchdog is enabled.
+	UnderlayAddress string `json:"underlay_address" yaml:"underlay_address"`
+}
+
+// networkListInput is the information gathered from LXD and the MicroCloud cluster members to summarize the uplink networks.
+type networkListInput struct {
+	Names service.ResourceNames
+
+	Statuses []types.Status
+	Networks []lxdAPI.Network
+
+	// MemberConfig is the member specific configuration of each uplink network, keyed by network and member.
+	MemberConfig map[string]map[string]map[string]string
+
+	Allocations []lxdAPI.NetworkAllocations
+
+	// Chassis is the chassis hosting the router of each OVN network.
+	Chassis map[string]string
+}
+
+type cmdNetworkList struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to list the OVN uplink networks.
+func (c *cmdNetworkList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the OVN uplink networks and their state",
+		Long: `List the OVN uplink networks and their state
+
+The used and free IPs are counted over the IPv4 ranges of the uplink network handed out to OVN networks.
+The gateway chassis is the cluster member currently hosting the router of each OVN network using the uplink network.
+The underlay address of each member is only known if the underlay watchdog is enabled.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to list the OVN uplink networks.
+func (c *cmdNetworkList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.MicroCloud, types.LXD)
+	if err != nil {
+		return err
+	}
+
+	input, err := gatherNetworkListInput(context.Background(), sh)
+	if err != nil {
+		return err
+	}
+
+	summaries, err := buildUplinkSummaries(input)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(summaries))
+	for _, summary := range summaries {
+		chassis := make([]string, 0, len(summary.Networks))
+		for _, network := range summary.Networks {
+			chassis = append(chassis, network+": "+valueOr(summary.GatewayChassis[network], "-"))
+		}
+
+		members := make([]string, 0, len(summary.Members))
+		for _, member := range summary.Members {
+			members = append(members, fmt.Sprintf("%s: %s (%s)", member.Name, valueOr(member.Parent, "-"), valueOr(member.UnderlayAddress, "-")))
+		}
+
+		used, free := "-", "-"
+		if summary.IPv4Ranges != "" {
+			used = strconv.FormatUint(summary.UsedIPs, 10)
+			free = strconv.FormatUint(summary.FreeIPs, 10)
+		}
+
+		rows = append(rows, []string{
+			summary.Name,
+			valueOr(summary.IPv4Gateway, "-"),
+			valueOr(summary.IPv4Ranges, "-"),
+			valueOr(summary.IPv6Gateway, "-"),
+			used,
+			free,
+			strings.Join(chassis, "\n"),
+			strings.Join(members, "\n"),
+		})
+	}
+
+	table, err := tui.FormatData(c.flagFormat, []string{"NAME", "IPV4 GATEWAY", "IPV4 RANGES", "IPV6 GATEWAY", "USED IPS", "FREE IPS", "GATEWAY CHASSIS", "MEMBERS (PARENT, UNDERLAY)"}, rows, summaries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+// valueOr returns the given value, or the fallback if it is empty.
+func valueOr(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// gatherNetworkListInput collects the uplink and OVN networks from LXD, and the underlay paths from the MicroCloud cluster members.
+func gatherNetworkListInput(ctx context.Context, sh *service.Handler) (networkListInput, error) {
+	input := networkListInput{MemberConfig: map[string]map[string]map[string]string{}, Chassis: map[string]string{}}
+
+	microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return input, err
+	}
+
+	config, err := cloudClient.GetConfig(ctx, microClient)
+	if err != nil {
+		return input, err
+	}
+
+	input.Names = service.ResourceNamesFromConfig(config)
+	input.Statuses, err = cloudClient.GetStatus(ctx, microClient)
+	if err != nil {
+		return input, err
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(ctx)
+	if err != nil {
+		return input, err
+	}
+
+	networks, err := lxdClient.GetNetworks()
+	if err != nil {
+		return input, fmt.Errorf("Failed to get LXD networks: %w", err)
+	}
+
+	members, err := lxdClient.GetClusterMembers()
+	if err != nil {
+		return input, fmt.Errorf("Failed to get LXD cluster members: %w", err)
+	}
+
+	for _, network := range networks {
+		if !network.Managed {
+			continue
+		}
+
+		input.Networks = append(input.Networks, network)
+		switch network.Type {
+		case "physical":
+			input.MemberConfig[network.Name] = map[string]map[string]string{}
+			for _, member := range members {
+				memberNetwork, _, err := lxdClient.UseTarget(member.ServerName).GetNetwork(network.Name)
+				if err != nil {
+					return input, fmt.Errorf("Failed to get LXD network %q on %q: %w", network.Name, member.ServerName, err)
+				}
+
+				input.MemberConfig[network.Name][member.ServerName] = memberNetwork.Config
+			}
+
+		case "ovn":
+			state, err := lxdClient.GetNetworkState(network.Name)
+			if err != nil {
+				return input, fmt.Errorf("Failed to get the state of LXD network %q: %w", network.Name, err)
+			}
+
+			if state.OVN != nil {
+				input.Chassis[network.Name] = state.OVN.Chassis
+			}
+		}
+	}
+
+	input.Allocations, err = lxdClient.GetNetworkAllocations(true)
+	if err != nil {
+		return input, fmt.Errorf("Failed to get LXD network allocations: %w", err)
+	}
+
+	return input, nil
+}
+
+// ipv4RangeSize returns the number of addresses in the given IPv4 range.
+func ipv4RangeSize(ipRange *shared.IPRange) uint64 {
+	if ipRange.End == nil {
+		return 1
+	}
+
+	start := ipRange.Start.To4()
+	end := ipRange.End.To4()
+	if start == nil || end == nil {
+		return 0
+	}
+
+	return uint64(binary.BigEndian.Uint32(end)) - uint64(binary.BigEndian.Uint32(start)) + 1
+}
+
+// buildUplinkSummaries returns the summaries of the uplink networks described by the input, sorted by name.
+// Uplink networks are the default one set up by MicroCloud, and any physical network used by an OVN network.
+func buildUplinkSummaries(input networkListInput) ([]UplinkNetworkSummary, error) {
+	uplinks := map[string][]string{}
+	for _, network := range input.Networks {
+		_, ok := uplinks[network.Name]
+		if network.Type == "physical" && network.Name == input.Names.UplinkNetwork && !ok {
+			uplinks[network.Name] = []string{}
+		}
+
+		if network.Type == "ovn" && network.Config["network"] != "" {
+			uplinks[network.Config["network"]] = append(uplinks[network.Config["network"]], network.Name)
+		}
+	}
+
+	underlay := map[string]string{}
+	for _, status := range input.Statuses {
+		for _, path := range status.UnderlayPaths {
+			if path.LocalAddress != "" {
+				underlay[status.Name] = path.LocalAddress
+				break
+			}
+		}
+	}
+
+	summaries := []UplinkNetworkSummary{}
+	for _, network := range input.Networks {
+		ovnNetworks, ok := uplinks[network.Name]
+		if !ok || network.Type != "physical" {
+			continue
+		}
+
+		sort.Strings(ovnNetworks)
+		summary := UplinkNetworkSummary{
+			Name:           network.Name,
+			IPv4Gateway:    network.Config["ipv4.gateway"],
+			IPv4Ranges:     network.Config["ipv4.ovn.ranges"],
+			IPv6Gateway:    network.Config["ipv6.gateway"],
+			IPv6Ranges:     network.Config["ipv6.ovn.ranges"],
+			Networks:       ovnNetworks,
+			GatewayChassis: map[string]string{},
+			Members:        []UplinkMemberSummary{},
+		}
+
+		for _, ovnNetwork := range ovnNetworks {
+			if input.Chassis[ovnNetwork] != "" {
+				summary.GatewayChassis[ovnNetwork] = input.Chassis[ovnNetwork]
+			}
+		}
+
+		if summary.IPv4Ranges != "" {
+			ranges, err := shared.ParseIPRanges(summary.IPv4Ranges)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid IPv4 ranges of uplink network %q: %w", network.Name, err)
+			}
+
+			var size uint64
+			for _, ipRange := range ranges {
+				size += ipv4RangeSize(ipRange)
+			}
+
+			used := []string{}
+			for _, allocation := range input.Allocations {
+				ip, _, err := net.ParseCIDR(allocation.Address)
+				if err != nil || ip.To4() == nil || slices.Contains(used, ip.String()) {
+					continue
+				}
+
+				if slices.ContainsFunc(ranges, func(ipRange *shared.IPRange) bool { return ipRange.ContainsIP(ip) }) {
+					used = append(used, ip.String())
+				}
+			}
+
+			summary.UsedIPs = uint64(len(used))
+			if size > summary.UsedIPs {
+				summary.FreeIPs = size - summary.UsedIPs
+			}
+		}
+
+		for member, config := range input.MemberConfig[network.Name] {
+			summary.Members = append(summary.Members, UplinkMemberSummary{Name: member, Parent: config["parent"], UnderlayAddress: underlay[member]})
+		}
+
+		sort.Slice(summary.Members, func(i, j int) bool { return summary.Members[i].Name < summary.Members[j].Name })
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+
+	return summaries, nil
+}
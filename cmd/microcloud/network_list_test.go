@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type networkListSuite struct {
+	suite.Suite
+}
+
+func TestNetworkListSuite(t *testing.T) {
+	suite.Run(t, new(networkListSuite))
+}
+
+func (s *networkListSuite) Test_buildUplinkSummaries() {
+	network := func(name string, networkType string, config map[string]string) lxdAPI.Network {
+		return lxdAPI.Network{Name: name, Type: networkType, Managed: true, Config: config}
+	}
+
+	input := networkListInput{
+		Names: service.DefaultResourceNames(),
+		Statuses: []types.Status{
+			{Name: "micro01", UnderlayPaths: []types.UnderlayPath{{LocalAddress: "10.1.0.1", RemoteAddress: "10.1.0.2"}}},
+			{Name: "micro02"},
+		},
+		Networks: []lxdAPI.Network{
+			network("default", "ovn", map[string]string{"network": "UPLINK"}),
+			network("UPLINK", "physical", map[string]string{"ipv4.gateway": "10.0.0.1/24", "ipv4.ovn.ranges": "10.0.0.100-10.0.0.109,10.0.0.200-10.0.0.200", "ipv6.gateway": "fd42::1/64"}),
+			network("public", "physical", map[string]string{}),
+			network("lxdbr0", "bridge", map[string]string{"ipv4.address": "10.2.0.1/24"}),
+			network("tenant", "ovn", map[string]string{"network": "UPLINK"}),
+		},
+		MemberConfig: map[string]map[string]map[string]string{
+			"UPLINK": {"micro02": {"parent": "eth1"}, "micro01": {"parent": "eth1"}},
+			"public": {"micro01": {"parent": "eth2"}},
+		},
+		Allocations: []lxdAPI.NetworkAllocations{
+			{Address: "10.0.0.100/32", Network: "default"},
+			{Address: "10.0.0.200/32", Network: "tenant"},
+			{Address: "10.0.0.200/32", Network: "tenant"},
+			{Address: "10.0.0.50/32", Network: "UPLINK"},
+			{Address: "10.2.0.5/32", Network: "lxdbr0"},
+			{Address: "fd42::100/128", Network: "default"},
+		},
+		Chassis: map[string]string{"default": "micro02"},
+	}
+
+	summaries, err := buildUplinkSummaries(input)
+	s.NoError(err)
+	s.Equal([]UplinkNetworkSummary{
+		{
+			Name:           "UPLINK",
+			IPv4Gateway:    "10.0.0.1/24",
+			IPv4Ranges:     "10.0.0.100-10.0.0.109,10.0.0.200-10.0.0.200",
+			IPv6Gateway:    "fd42::1/64",
+			UsedIPs:        2,
+			FreeIPs:        9,
+			Networks:       []string{"default", "tenant"},
+			GatewayChassis: map[string]string{"default": "micro02"},
+			Members: []UplinkMemberSummary{
+				{Name: "micro01", Parent: "eth1", UnderlayAddress: "10.1.0.1"},
+				{Name: "micro02", Parent: "eth1"},
+			},
+		},
+	}, summaries)
+
+	input.Networks[1].Config["ipv4.ovn.ranges"] = "invalid"
+	_, err = buildUplinkSummaries(input)
+	s.Error(err)
+}
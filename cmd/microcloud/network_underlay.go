@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+type cmdNetworkSetUnderlay struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to change the address a cluster member uses for the OVN Geneve tunnels.
+func (c *cmdNetworkSetUnderlay) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-underlay <member> <cidr>",
+		Short: "Change the address a cluster member uses for the OVN Geneve tunnels",
+		Long: `Change the address a cluster member uses for the OVN Geneve tunnels
+
+The address and prefix length must be configured on an interface of the member.
+The other cluster members re-establish their tunnels to the new address once the OVN chassis of the member is updated,
+so the OVN networks of LXD keep working without any change to their configuration.`,
+		Example: `  microcloud network set-underlay micro01 10.0.2.1/24`,
+		RunE:    c.run,
+	}
+
+	return cmd
+}
+
+// run runs the subcommand to change the address a cluster member uses for the OVN Geneve tunnels.
+func (c *cmdNetworkSetUnderlay) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return cmd.Help()
+	}
+
+	name := args[0]
+	ip, subnet, err := net.ParseCIDR(args[1])
+	if err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid address %q, must be of the form <ip>/<prefix length>: %w", args[1], err))
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	members, err := client.GetClusterMembers(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	found := false
+	addresses := map[string]string{}
+	for _, member := range members {
+		if member.Name == name {
+			found = true
+			continue
+		}
+
+		// Members without MicroOVN have no tunnels to re-establish.
+		underlay, err := cloudClient.GetOVNUnderlay(cmd.Context(), client.UseTarget(member.Name))
+		if err == nil && underlay.Address != "" {
+			addresses[member.Name] = underlay.Address
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("Cluster member %q not found", name)
+	}
+
+	routed := routedUnderlayPeers(subnet, addresses)
+	if len(routed) > 0 {
+		tui.PrintWarning(fmt.Sprintf("The OVN underlay addresses of %s are outside of %s, so the Geneve tunnels to %s have to be routed", strings.Join(routed, ", "), subnet.String(), name))
+	}
+
+	underlay, err := cloudClient.SetOVNUnderlay(cmd.Context(), client.UseTarget(name), types.OVNUnderlayPut{Address: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if underlay.Address != ip.String() {
+		return errors.New("The OVN underlay address was not updated")
+	}
+
+	fmt.Println(tui.SummarizeResult("Using %s on %s for the OVN underlay of %s", underlay.Address, underlay.Interface, name))
+
+	return nil
+}
+
+// routedUnderlayPeers returns the cluster members whose OVN underlay address is outside of the given subnet, sorted by name.
+func routedUnderlayPeers(subnet *net.IPNet, addresses map[string]string) []string {
+	routed := []string{}
+	for name, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil || !subnet.Contains(ip) {
+			routed = append(routed, name)
+		}
+	}
+
+	sort.Strings(routed)
+
+	return routed
+}
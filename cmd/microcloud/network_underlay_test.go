@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type networkUnderlaySuite struct {
+	suite.Suite
+}
+
+func TestNetworkUnderlaySuite(t *testing.T) {
+	suite.Run(t, new(networkUnderlaySuite))
+}
+
+func (s *networkUnderlaySuite) Test_routedUnderlayPeers() {
+	_, subnet, err := net.ParseCIDR("10.0.2.1/24")
+	s.Require().NoError(err)
+
+	s.Equal([]string{}, routedUnderlayPeers(subnet, map[string]string{}))
+	s.Equal([]string{"micro02", "micro04"}, routedUnderlayPeers(subnet, map[string]string{
+		"micro04": "invalid",
+		"micro03": "10.0.2.3",
+		"micro02": "10.0.3.2",
+	}))
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// uplinkEdit is a change to the configuration of an uplink network.
+// Empty values keep the current configuration.
+type uplinkEdit struct {
+	IPv4Gateway string
+	IPv6Gateway string
+	DNSServers  string
+
+	// IPv4Ranges and IPv6Ranges replace the ranges of the uplink network, before AddIPv4Ranges and AddIPv6Ranges are appended.
+	IPv4Ranges    string
+	IPv6Ranges    string
+	AddIPv4Ranges []string
+	AddIPv6Ranges []string
+}
+
+type cmdNetworkEditUplink struct {
+	common *CmdControl
+
+	flagIPv4Gateway   string
+	flagIPv4Ranges    string
+	flagAddIPv4Ranges []string
+	flagIPv6Gateway   string
+	flagIPv6Ranges    string
+	flagAddIPv6Ranges []string
+	flagDNSServers    string
+}
+
+// command returns the subcommand to modify the gateways, ranges and DNS servers of an uplink network.
+func (c *cmdNetworkEditUplink) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit-uplink [<network>]",
+		Short: "Modify the gateways, ranges and DNS servers of an OVN uplink network",
+		Long: `Modify the gateways, ranges and DNS servers of an OVN uplink network
+
+The uplink network set up by MicroCloud is modified if no network is given.
+Ranges are comma separated lists of <ip>-<ip> ranges or single addresses, which must be within the subnet of the gateway.
+The addresses already allocated to OVN networks from the current ranges must remain within the new ones.`,
+		Example: `  microcloud network edit-uplink --add-ipv4-range 10.0.0.200-10.0.0.250
+  microcloud network edit-uplink --ipv4-gateway 10.0.0.1/23 --ipv4-ranges 10.0.0.100-10.0.1.250
+  microcloud network edit-uplink --dns 10.0.0.1,1.1.1.1`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagIPv4Gateway, "ipv4-gateway", "", "IPv4 gateway (CIDR) of the uplink network"+"``")
+	cmd.Flags().StringVar(&c.flagIPv4Ranges, "ipv4-ranges", "", "IPv4 ranges replacing the current ones"+"``")
+	cmd.Flags().StringSliceVar(&c.flagAddIPv4Ranges, "add-ipv4-range", nil, "IPv4 range to add to the current ones, can be repeated"+"``")
+	cmd.Flags().StringVar(&c.flagIPv6Gateway, "ipv6-gateway", "", "IPv6 gateway (CIDR) of the uplink network"+"``")
+	cmd.Flags().StringVar(&c.flagIPv6Ranges, "ipv6-ranges", "", "IPv6 ranges replacing the current ones"+"``")
+	cmd.Flags().StringSliceVar(&c.flagAddIPv6Ranges, "add-ipv6-range", nil, "IPv6 range to add to the current ones, can be repeated"+"``")
+	cmd.Flags().StringVar(&c.flagDNSServers, "dns", "", "Comma separated DNS servers of the uplink network"+"``")
+
+	return cmd
+}
+
+// run runs the subcommand to modify the gateways, ranges and DNS servers of an uplink network.
+func (c *cmdNetworkEditUplink) run(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return cmd.Help()
+	}
+
+	edit := uplinkEdit{
+		IPv4Gateway:   c.flagIPv4Gateway,
+		IPv6Gateway:   c.flagIPv6Gateway,
+		DNSServers:    c.flagDNSServers,
+		IPv4Ranges:    c.flagIPv4Ranges,
+		IPv6Ranges:    c.flagIPv6Ranges,
+		AddIPv4Ranges: c.flagAddIPv4Ranges,
+		AddIPv6Ranges: c.flagAddIPv6Ranges,
+	}
+
+	if edit.IPv4Gateway == "" && edit.IPv6Gateway == "" && edit.DNSServers == "" && edit.IPv4Ranges == "" && edit.IPv6Ranges == "" && len(edit.AddIPv4Ranges) == 0 && len(edit.AddIPv6Ranges) == 0 {
+		return withExitCode(ExitCodeUsage, errors.New("Nothing to modify, at least one gateway, range or DNS flag is required"))
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.MicroCloud, types.LXD)
+	if err != nil {
+		return err
+	}
+
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
+	} else {
+		microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+		if err != nil {
+			return err
+		}
+
+		config, err := cloudClient.GetConfig(context.Background(), microClient)
+		if err != nil {
+			return err
+		}
+
+		name = service.ResourceNamesFromConfig(config).UplinkNetwork
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(context.Background())
+	if err != nil {
+		return err
+	}
+
+	network, etag, err := lxdClient.GetNetwork(name)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD network %q: %w", name, err)
+	}
+
+	if network.Type != "physical" {
+		return fmt.Errorf("Network %q is not an uplink network", name)
+	}
+
+	allocations, err := lxdClient.GetNetworkAllocations(true)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD network allocations: %w", err)
+	}
+
+	config, err := editUplinkConfig(network.Config, edit, allocations)
+	if err != nil {
+		return fmt.Errorf("Invalid configuration of uplink network %q: %w", name, err)
+	}
+
+	put := network.Writable()
+	put.Config = config
+	err = lxdClient.UpdateNetwork(name, put, etag)
+	if err != nil {
+		return fmt.Errorf("Failed to update LXD network %q: %w", name, err)
+	}
+
+	for _, key := range []string{"ipv4.gateway", "ipv4.ovn.ranges", "ipv6.gateway", "ipv6.ovn.ranges", "dns.nameservers"} {
+		if config[key] != network.Config[key] {
+			fmt.Println(tui.SummarizeResult("Set %s of %s to %s", key, name, valueOr(config[key], "none")))
+		}
+	}
+
+	return nil
+}
+
+// uplinkRanges returns the given ranges, replaced and extended as requested.
+func uplinkRanges(current string, replace string, add []string) string {
+	ranges := []string{}
+	if replace != "" {
+		current = replace
+	}
+
+	for _, ipRange := range strings.Split(current, ",") {
+		ipRange = strings.TrimSpace(ipRange)
+		if ipRange != "" && !slices.Contains(ranges, ipRange) {
+			ranges = append(ranges, ipRange)
+		}
+	}
+
+	for _, ipRange := range add {
+		ipRange = strings.TrimSpace(ipRange)
+		if ipRange != "" && !slices.Contains(ranges, ipRange) {
+			ranges = append(ranges, ipRange)
+		}
+	}
+
+	return strings.Join(ranges, ",")
+}
+
+// validateUplinkRanges checks the ranges are within the subnet of the gateway, of the given IP family, don't overlap each other, and don't contain the gateway.
+func validateUplinkRanges(ranges string, gateway string, ipv4 bool) ([]*shared.IPRange, error) {
+	if ranges == "" {
+		return nil, nil
+	}
+
+	if gateway == "" {
+		return nil, errors.New("Ranges require a gateway")
+	}
+
+	gatewayIP, subnet, err := net.ParseCIDR(gateway)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid gateway %q: %w", gateway, err)
+	}
+
+	parsed, err := shared.ParseIPRanges(ranges, subnet)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %q: %w", ranges, err)
+	}
+
+	for i, ipRange := range parsed {
+		if (ipRange.Start.To4() != nil) != ipv4 || (ipRange.End != nil && (ipRange.End.To4() != nil) != ipv4) {
+			return nil, fmt.Errorf("Range %q is not of the IP family of the gateway", ipRange.String())
+		}
+
+		if ipRange.ContainsIP(gatewayIP) {
+			return nil, fmt.Errorf("Range %q contains the gateway", ipRange.String())
+		}
+
+		for _, other := range parsed[i+1:] {
+			if ipRange.Overlaps(other) || other.Overlaps(ipRange) {
+				return nil, fmt.Errorf("Range %q overlaps with range %q", ipRange.String(), other.String())
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+// editUplinkConfig returns the configuration of an uplink network with the given change applied.
+// The addresses allocated from the current ranges must remain within the new ranges, and the new ranges must not contain addresses allocated elsewhere.
+func editUplinkConfig(config map[string]string, edit uplinkEdit, allocations []lxdAPI.NetworkAllocations) (map[string]string, error) {
+	newConfig := make(map[string]string, len(config))
+	for key, value := range config {
+		newConfig[key] = value
+	}
+
+	if edit.IPv4Gateway != "" {
+		newConfig["ipv4.gateway"] = edit.IPv4Gateway
+	}
+
+	if edit.IPv6Gateway != "" {
+		newConfig["ipv6.gateway"] = edit.IPv6Gateway
+	}
+
+	if edit.DNSServers != "" {
+		err := validate.IsListOf(validate.IsNetworkAddress)(edit.DNSServers)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid DNS servers %q: %w", edit.DNSServers, err)
+		}
+
+		newConfig["dns.nameservers"] = edit.DNSServers
+	}
+
+	newConfig["ipv4.ovn.ranges"] = uplinkRanges(config["ipv4.ovn.ranges"], edit.IPv4Ranges, edit.AddIPv4Ranges)
+	newConfig["ipv6.ovn.ranges"] = uplinkRanges(config["ipv6.ovn.ranges"], edit.IPv6Ranges, edit.AddIPv6Ranges)
+
+	inRanges := func(ranges []*shared.IPRange, ip net.IP) bool {
+		return slices.ContainsFunc(ranges, func(ipRange *shared.IPRange) bool { return ipRange.ContainsIP(ip) })
+	}
+
+	for family, name := range map[string]string{"ipv4": "IPv4", "ipv6": "IPv6"} {
+		gateway := newConfig[family+".gateway"]
+		if gateway != "" {
+			ip, _, err := net.ParseCIDR(gateway)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s gateway %q: %w", name, gateway, err)
+			}
+
+			if (ip.To4() != nil) != (family == "ipv4") {
+				return nil, fmt.Errorf("Gateway %q is not an %s address", gateway, name)
+			}
+		}
+
+		newRanges, err := validateUplinkRanges(newConfig[family+".ovn.ranges"], gateway, family == "ipv4")
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s ranges: %w", name, err)
+		}
+
+		// The current ranges were accepted by LXD, so are only used to tell which allocations come from them.
+		var oldRanges []*shared.IPRange
+		if config[family+".ovn.ranges"] != "" {
+			oldRanges, _ = shared.ParseIPRanges(config[family+".ovn.ranges"])
+		}
+
+		for _, allocation := range allocations {
+			ip, _, err := net.ParseCIDR(allocation.Address)
+			if err != nil {
+				continue
+			}
+
+			inOld := inRanges(oldRanges, ip)
+			inNew := inRanges(newRanges, ip)
+			if inOld && !inNew {
+				return nil, fmt.Errorf("Address %s used by %q is outside of the new ranges", ip.String(), allocation.UsedBy)
+			}
+
+			if !inOld && inNew {
+				return nil, fmt.Errorf("Address %s in the new ranges is already used by %q", ip.String(), allocation.UsedBy)
+			}
+		}
+
+		if newConfig[family+".ovn.ranges"] == "" {
+			delete(newConfig, family+".ovn.ranges")
+		}
+	}
+
+	return newConfig, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type networkUplinkSuite struct {
+	suite.Suite
+}
+
+func TestNetworkUplinkSuite(t *testing.T) {
+	suite.Run(t, new(networkUplinkSuite))
+}
+
+func (s *networkUplinkSuite) Test_editUplinkConfig() {
+	config := map[string]string{
+		"ipv4.gateway":    "10.0.0.1/24",
+		"ipv4.ovn.ranges": "10.0.0.100-10.0.0.109",
+		"ipv6.gateway":    "fd42::1/64",
+		"dns.nameservers": "10.0.0.1",
+	}
+
+	allocations := []lxdAPI.NetworkAllocations{
+		{Address: "10.0.0.100/32", UsedBy: "/1.0/networks/default"},
+		{Address: "10.0.0.150/32", UsedBy: "/1.0/instances/c1"},
+		{Address: "fd42::216:3eff:fe00:1/128", UsedBy: "/1.0/networks/default"},
+	}
+
+	cases := []struct {
+		desc     string
+		edit     uplinkEdit
+		expected map[string]string
+		err      bool
+	}{
+		{
+			desc:     "Add IPv4 range",
+			edit:     uplinkEdit{AddIPv4Ranges: []string{"10.0.0.200-10.0.0.250"}},
+			expected: map[string]string{"ipv4.ovn.ranges": "10.0.0.100-10.0.0.109,10.0.0.200-10.0.0.250"},
+		},
+		{
+			desc:     "Replace IPv4 range and gateway",
+			edit:     uplinkEdit{IPv4Gateway: "10.0.0.1/23", IPv4Ranges: "10.0.0.100-10.0.0.120,10.0.1.10-10.0.1.250"},
+			expected: map[string]string{"ipv4.gateway": "10.0.0.1/23", "ipv4.ovn.ranges": "10.0.0.100-10.0.0.120,10.0.1.10-10.0.1.250"},
+		},
+		{
+			desc:     "Set IPv6 ranges and DNS servers",
+			edit:     uplinkEdit{IPv6Ranges: "fd42::100-fd42::1ff", DNSServers: "10.0.0.1,fd42::1"},
+			expected: map[string]string{"ipv6.ovn.ranges": "fd42::100-fd42::1ff", "dns.nameservers": "10.0.0.1,fd42::1"},
+		},
+		{
+			desc: "Allocated address outside of the new ranges",
+			edit: uplinkEdit{IPv4Ranges: "10.0.0.101-10.0.0.109"},
+			err:  true,
+		},
+		{
+			desc: "New range containing an address used elsewhere",
+			edit: uplinkEdit{AddIPv4Ranges: []string{"10.0.0.140-10.0.0.160"}},
+			err:  true,
+		},
+		{
+			desc: "Range outside of the gateway subnet",
+			edit: uplinkEdit{AddIPv4Ranges: []string{"10.0.1.10-10.0.1.20"}},
+			err:  true,
+		},
+		{
+			desc: "Range containing the gateway",
+			edit: uplinkEdit{IPv4Ranges: "10.0.0.1-10.0.0.109"},
+			err:  true,
+		},
+		{
+			desc: "Overlapping ranges",
+			edit: uplinkEdit{AddIPv4Ranges: []string{"10.0.0.105-10.0.0.120"}},
+			err:  true,
+		},
+		{
+			desc: "IPv6 gateway of the wrong family",
+			edit: uplinkEdit{IPv6Gateway: "10.0.0.1/24"},
+			err:  true,
+		},
+		{
+			desc: "Invalid DNS servers",
+			edit: uplinkEdit{DNSServers: "dns.example.com"},
+			err:  true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		newConfig, err := editUplinkConfig(config, c.edit, allocations)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+
+		expected := map[string]string{}
+		for key, value := range config {
+			expected[key] = value
+		}
+
+		for key, value := range c.expected {
+			expected[key] = value
+		}
+
+		s.Equal(expected, newConfig)
+	}
+}
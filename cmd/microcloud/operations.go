@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	microClient "github.com/canonical/microcluster/v3/client"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+// operationPollInterval is how often a queued cluster change checks whether it holds the change lock.
+const operationPollInterval = 2 * time.Second
+
+// operationHolder returns the cluster change holding the change lock, if it isn't the one with the given ID.
+func operationHolder(operations []types.Operation, id int64) *types.Operation {
+	for _, op := range operations {
+		if op.Status == types.OperationRunning && op.ID != id {
+			return &op
+		}
+	}
+
+	return nil
+}
+
+// lockClusterChanges takes the cluster-wide change lock, waiting for conflicting cluster changes to finish first.
+// The returned function releases the lock, and must be called once the cluster change is done.
+func lockClusterChanges(ctx context.Context, client *microClient.Client, opType string, description string) (func(), error) {
+	op, err := cloudClient.CreateOperation(ctx, client, types.OperationsPost{Type: opType, Description: description})
+	if err != nil {
+		return nil, err
+	}
+
+	release := func() {
+		err := cloudClient.DeleteOperation(context.Background(), client, op.ID)
+		if err != nil {
+			logger.Error("Failed to release the cluster change lock", logger.Ctx{"operation": op.ID, "error": err})
+		}
+	}
+
+	var waitingFor int64
+	for op.Status != types.OperationRunning {
+		operations, err := cloudClient.GetOperations(ctx, client)
+		if err != nil {
+			release()
+			return nil, err
+		}
+
+		holder := operationHolder(operations, op.ID)
+		if holder != nil && holder.ID != waitingFor {
+			waitingFor = holder.ID
+			fmt.Fprintf(os.Stderr, "Waiting for %q started on %q at %s to finish ...\n", holder.Type, holder.Member, holder.CreatedAt.Local().Format("15:04:05"))
+		}
+
+		select {
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		case <-time.After(operationPollInterval):
+		}
+
+		op, err = cloudClient.UpdateOperation(ctx, client, op.ID)
+		if err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	// Keep the lock for as long as the cluster change runs.
+	renewCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(api.OperationLease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				_, err := cloudClient.UpdateOperation(renewCtx, client, op.ID)
+				if err != nil && renewCtx.Err() == nil {
+					logger.Warn("Failed to renew the cluster change lock", logger.Ctx{"operation": op.ID, "error": err})
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		release()
+	}, nil
+}
+
+type cmdOperations struct {
+	common *CmdControl
+}
+
+// command returns the operations subcommand.
+func (c *cmdOperations) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "operations",
+		Short: "Manage cluster changes holding or waiting for the cluster-wide change lock",
+		RunE:  c.run,
+	}
+
+	var cmdList = cmdOperationsList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	return cmd
+}
+
+// run runs the operations subcommand.
+func (c *cmdOperations) run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+type cmdOperationsList struct {
+	common     *CmdControl
+	flagFormat string
+}
+
+// command returns the subcommand for listing cluster changes.
+func (c *cmdOperationsList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the running and pending cluster changes",
+		Long: `List the running and pending cluster changes
+
+Cluster changes such as "microcloud add" and "microcloud service add" are serialized with a cluster-wide change lock.
+The running change holds the lock, and the pending changes wait for it in the listed order.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand for listing cluster changes.
+func (c *cmdOperationsList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	operations, err := cloudClient.GetOperations(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	data := make([][]string, len(operations))
+	for i, op := range operations {
+		data[i] = []string{strconv.FormatInt(op.ID, 10), op.Type, op.Member, op.Description, op.Status, op.CreatedAt.Local().Format("2006-01-02 15:04:05")}
+	}
+
+	header := []string{"ID", "TYPE", "MEMBER", "DESCRIPTION", "STATUS", "CREATED"}
+	table, err := tui.FormatData(c.flagFormat, header, data, operations)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type operationsSuite struct {
+	suite.Suite
+}
+
+func TestOperationsSuite(t *testing.T) {
+	suite.Run(t, new(operationsSuite))
+}
+
+func (s *operationsSuite) Test_operationHolder() {
+	cases := []struct {
+		desc       string
+		operations []types.Operation
+		id         int64
+		holder     int64
+	}{
+		{
+			desc:   "No operations",
+			id:     1,
+			holder: -1,
+		},
+		{
+			desc: "Operation holds the lock",
+			operations: []types.Operation{
+				{ID: 1, Status: types.OperationRunning},
+				{ID: 2, Status: types.OperationPending},
+			},
+			id:     1,
+			holder: -1,
+		},
+		{
+			desc: "Operation waits for another one",
+			operations: []types.Operation{
+				{ID: 1, Status: types.OperationRunning},
+				{ID: 2, Status: types.OperationPending},
+				{ID: 3, Status: types.OperationPending},
+			},
+			id:     3,
+			holder: 1,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		holder := operationHolder(c.operations, c.id)
+		if c.holder < 0 {
+			s.Nil(holder)
+			continue
+		}
+
+		s.Require().NotNil(holder)
+		s.Equal(c.holder, holder.ID)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ovnCentralCount is the number of cluster members MicroOVN runs the OVN central services on by default.
+const ovnCentralCount = 3
+
+// ovnServices returns the MicroOVN services of the given system, or an empty string to let MicroOVN decide.
+func (c *initConfig) ovnServices(name string) string {
+	if c.ovnCentral == nil {
+		return ""
+	}
+
+	if slices.Contains(c.ovnCentral, name) {
+		return "central,chassis,switch"
+	}
+
+	return "chassis,switch"
+}
+
+// validateOVNCentral checks the systems chosen to run the OVN central services are part of the cluster, include the system bootstrapping MicroOVN,
+// and are enough to keep the OVN databases available.
+func validateOVNCentral(central []string, systems []string, bootstrap string) error {
+	for _, name := range central {
+		if !slices.Contains(systems, name) {
+			return fmt.Errorf("Unknown system %q", name)
+		}
+	}
+
+	if slices.Contains(systems, bootstrap) && !slices.Contains(central, bootstrap) {
+		return fmt.Errorf("The OVN central services must run on %q, which sets up MicroOVN", bootstrap)
+	}
+
+	minimum := min(ovnCentralCount, len(systems))
+	if len(central) < minimum {
+		return fmt.Errorf("The OVN central services must run on at least %d systems, %d selected", minimum, len(central))
+	}
+
+	return nil
+}
+
+// warnOVNCentral warns when an even number of systems run the OVN central services,
+// as the OVN databases then tolerate no more failures than with one system less.
+func warnOVNCentral(central []string) {
+	if len(central)%2 == 0 {
+		tui.PrintWarning(fmt.Sprintf("The OVN central services run on an even number of systems (%d), which tolerates no more failures than %d systems", len(central), len(central)-1))
+	}
+}
+
+// askOVNCentral asks which systems run the OVN central services when setting up MicroOVN on more systems than MicroOVN picks by default.
+func (c *initConfig) askOVNCentral(sh *service.Handler) error {
+	if sh.Services[types.MicroOVN] == nil || len(c.systems) <= ovnCentralCount {
+		return nil
+	}
+
+	for _, state := range c.state {
+		if state.ExistingServices[types.MicroOVN] != nil {
+			return nil
+		}
+	}
+
+	choose, err := c.asker.AskBool(fmt.Sprintf("Choose the systems running the OVN central services? (MicroOVN picks %d otherwise)", ovnCentralCount), false)
+	if err != nil {
+		return err
+	}
+
+	if !choose {
+		return nil
+	}
+
+	names := slices.Sorted(maps.Keys(c.systems))
+	data := make([][]string, 0, len(names))
+	for _, name := range names {
+		data = append(data, []string{name, c.systems[name].ServerInfo.Address})
+	}
+
+	err = c.askRetry("Retry selecting the systems running the OVN central services?", func() error {
+		table := tui.NewSelectableTable([]string{"LOCATION", "ADDRESS"}, data)
+		answers, err := table.Render(context.Background(), c.asker, "Select the systems to run the OVN central services:")
+		if err != nil {
+			return err
+		}
+
+		if len(answers) == 0 {
+			return errors.New("No systems selected")
+		}
+
+		central := make([]string, 0, len(answers))
+		for _, answer := range answers {
+			central = append(central, answer["LOCATION"])
+		}
+
+		err = validateOVNCentral(central, names, c.name)
+		if err != nil {
+			return err
+		}
+
+		c.ovnCentral = central
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s\n\n", tui.SummarizeResult("Running the OVN central services on %s", strings.Join(c.ovnCentral, ", ")))
+	warnOVNCentral(c.ovnCentral)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ovnCentralSuite struct {
+	suite.Suite
+}
+
+func TestOVNCentralSuite(t *testing.T) {
+	suite.Run(t, new(ovnCentralSuite))
+}
+
+func (s *ovnCentralSuite) Test_validateOVNCentral() {
+	systems := []string{"micro01", "micro02", "micro03", "micro04", "micro05"}
+
+	cases := []struct {
+		desc    string
+		central []string
+		systems []string
+		err     bool
+	}{
+		{
+			desc:    "Three of five systems",
+			central: []string{"micro01", "micro03", "micro05"},
+			systems: systems,
+		},
+		{
+			desc:    "All systems",
+			central: systems,
+			systems: systems,
+		},
+		{
+			desc:    "Both systems of a two system cluster",
+			central: []string{"micro01", "micro02"},
+			systems: []string{"micro01", "micro02"},
+		},
+		{
+			desc:    "Too few systems",
+			central: []string{"micro01", "micro02"},
+			systems: systems,
+			err:     true,
+		},
+		{
+			desc:    "Without the bootstrapping system",
+			central: []string{"micro02", "micro03", "micro04"},
+			systems: systems,
+			err:     true,
+		},
+		{
+			desc:    "Unknown system",
+			central: []string{"micro01", "micro02", "micro06"},
+			systems: systems,
+			err:     true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := validateOVNCentral(c.central, c.systems, "micro01")
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+		}
+	}
+}
+
+func (s *ovnCentralSuite) Test_ovnServices() {
+	c := initConfig{}
+	s.Empty(c.ovnServices("micro01"))
+
+	c.ovnCentral = []string{"micro01", "micro02", "micro03"}
+	s.Equal("central,chassis,switch", c.ovnServices("micro01"))
+	s.Equal("chassis,switch", c.ovnServices("micro04"))
+}
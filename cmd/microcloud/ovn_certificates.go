@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	lxd "github.com/canonical/lxd/client"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ovnNorthboundConnectionKey is the LXD server configuration key of the connection to the OVN northbound database.
+const ovnNorthboundConnectionKey = "network.ovn.northbound_connection"
+
+// ovnCertificateKeys are the LXD server configuration keys overriding the OVN certificates shared by MicroOVN with LXD.
+var ovnCertificateKeys = []string{"network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key"}
+
+// ovnVerifyAttempts is the number of times the connection of LXD to the OVN northbound database is checked on each cluster member,
+// as the OVN services take a moment to restart with their new certificates.
+const ovnVerifyAttempts = 15
+
+type cmdNetworkRotateOVNCertificates struct {
+	common *CmdControl
+
+	flagCA bool
+}
+
+// command returns the subcommand to rotate the certificates LXD uses to connect to the OVN northbound database.
+func (c *cmdNetworkRotateOVNCertificates) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-ovn-certificates",
+		Short: "Rotate the certificates LXD uses to connect to the OVN northbound database",
+		Long: `Rotate the certificates LXD uses to connect to the OVN northbound database
+
+MicroOVN issues a new client certificate on each cluster member, which LXD picks up on its next connection to OVN.
+With --ca, MicroOVN also issues a new CA certificate, along with new certificates for all OVN services on all cluster members.
+
+The LXD connection to the OVN northbound database is first set to the central OVN services over SSL, if it isn't already.
+Once the certificates are rotated, the connection of LXD on each cluster member is verified through the state of an OVN network.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().BoolVar(&c.flagCA, "ca", false, "Also issue a new OVN CA certificate, and new certificates for all OVN services")
+
+	return cmd
+}
+
+// run runs the subcommand to rotate the certificates LXD uses to connect to the OVN northbound database.
+func (c *cmdNetworkRotateOVNCertificates) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, types.MicroCloud, types.LXD, types.MicroOVN)
+	if err != nil {
+		return err
+	}
+
+	ovn := sh.Services[types.MicroOVN].(*service.OVNService)
+	ovnMembers, err := ovn.ClusterMembers(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroOVN cluster members, is MicroOVN set up? %w", err)
+	}
+
+	cloudMembers, err := sh.Services[types.MicroCloud].(*service.CloudService).ClusterMembers(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	// The MicroCloud addresses carry the MicroCloud port, which is replaced for OVN.
+	hosts := make(map[string]string, len(cloudMembers))
+	for name, address := range cloudMembers {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return fmt.Errorf("Invalid address %q of cluster member %q: %w", address, name, err)
+		}
+
+		hosts[name] = host
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	server, etag, err := lxdClient.GetServer()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD server: %w", err)
+	}
+
+	pinned := pinnedOVNCertificateKeys(server.Config)
+	if len(pinned) > 0 {
+		return fmt.Errorf("LXD uses its own OVN certificates rather than those of MicroOVN, unset %s to rotate them with MicroCloud", strings.Join(pinned, ", "))
+	}
+
+	connection, err := ovn.NorthboundConnection(cmd.Context(), hosts)
+	if err != nil {
+		return err
+	}
+
+	current, _ := server.Config[ovnNorthboundConnectionKey].(string)
+	if current != connection {
+		put := server.Writable()
+		put.Config[ovnNorthboundConnectionKey] = connection
+		err = lxdClient.UpdateServer(put, etag)
+		if err != nil {
+			return fmt.Errorf("Failed to update the LXD connection to the OVN northbound database: %w", err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Set %s to %s", ovnNorthboundConnectionKey, connection))
+	}
+
+	if c.flagCA {
+		err = ovn.RegenerateCA(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(tui.SummarizeResult("Issued a new OVN CA certificate and new OVN certificates on %s", strings.Join(slices.Sorted(maps.Keys(ovnMembers)), ", ")))
+	} else {
+		for _, name := range slices.Sorted(maps.Keys(ovnMembers)) {
+			address := ""
+			if name != status.Name {
+				address = cloudMembers[name]
+			}
+
+			err = ovn.ReissueClientCertificate(cmd.Context(), address)
+			if err != nil {
+				return fmt.Errorf("Failed to rotate the OVN client certificate of %q: %w", name, err)
+			}
+
+			fmt.Println(tui.SummarizeResult("Issued a new OVN client certificate on %s", name))
+		}
+	}
+
+	networks, err := lxdClient.GetNetworksAllProjects()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD networks: %w", err)
+	}
+
+	microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	config, err := cloudClient.GetConfig(cmd.Context(), microClient)
+	if err != nil {
+		return err
+	}
+
+	network := ovnVerificationNetwork(networks, service.ResourceNamesFromConfig(config).OVNNetwork)
+	if network == nil {
+		tui.PrintWarning("No OVN network to verify the connection of LXD to the OVN northbound database with")
+		return nil
+	}
+
+	members, err := lxdClient.GetClusterMembers()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD cluster members: %w", err)
+	}
+
+	for _, member := range members {
+		err = verifyOVNConnection(cmd.Context(), lxdClient.UseProject(network.Project).UseTarget(member.ServerName), network.Name)
+		if err != nil {
+			return fmt.Errorf("LXD on %q failed to reach the OVN northbound database: %w", member.ServerName, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("LXD on %s reaches the OVN northbound database", member.ServerName))
+	}
+
+	return nil
+}
+
+// pinnedOVNCertificateKeys returns the keys of the LXD server configuration which override the OVN certificates shared by MicroOVN.
+func pinnedOVNCertificateKeys(config map[string]any) []string {
+	pinned := []string{}
+	for _, key := range ovnCertificateKeys {
+		value, _ := config[key].(string)
+		if value != "" {
+			pinned = append(pinned, key)
+		}
+	}
+
+	return pinned
+}
+
+// ovnVerificationNetwork returns the OVN network whose state verifies the connection of LXD to the OVN northbound database,
+// preferring the given network of the default project. Nil if there is no OVN network.
+func ovnVerificationNetwork(networks []lxdAPI.Network, preferred string) *lxdAPI.Network {
+	var found *lxdAPI.Network
+	for i, network := range networks {
+		if network.Type != "ovn" {
+			continue
+		}
+
+		if network.Name == preferred && (network.Project == "" || network.Project == "default") {
+			return &networks[i]
+		}
+
+		if found == nil {
+			found = &networks[i]
+		}
+	}
+
+	return found
+}
+
+// verifyOVNConnection checks that LXD reaches the OVN northbound database by getting the state of the given OVN network,
+// waiting for the OVN services to restart with their new certificates.
+func verifyOVNConnection(ctx context.Context, lxdClient lxd.InstanceServer, network string) error {
+	var err error
+	for attempt := 1; attempt <= ovnVerifyAttempts; attempt++ {
+		_, err = lxdClient.GetNetworkState(network)
+		if err == nil || attempt == ovnVerifyAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type ovnCertificatesSuite struct {
+	suite.Suite
+}
+
+func TestOVNCertificatesSuite(t *testing.T) {
+	suite.Run(t, new(ovnCertificatesSuite))
+}
+
+func (s *ovnCertificatesSuite) Test_pinnedOVNCertificateKeys() {
+	s.Empty(pinnedOVNCertificateKeys(map[string]any{"network.ovn.northbound_connection": "ssl:10.0.1.11:6641"}))
+	s.Empty(pinnedOVNCertificateKeys(map[string]any{"network.ovn.client_cert": ""}))
+	s.Equal([]string{"network.ovn.ca_cert", "network.ovn.client_key"}, pinnedOVNCertificateKeys(map[string]any{"network.ovn.client_key": "key", "network.ovn.ca_cert": "cert"}))
+}
+
+func (s *ovnCertificatesSuite) Test_ovnVerificationNetwork() {
+	cases := []struct {
+		desc     string
+		networks []lxdAPI.Network
+		network  string
+		project  string
+	}{
+		{
+			desc:     "No OVN network",
+			networks: []lxdAPI.Network{{Name: "UPLINK", Type: "physical"}, {Name: "lxdbr0", Type: "bridge"}},
+		},
+		{
+			desc:     "Preferred network",
+			networks: []lxdAPI.Network{{Name: "other", Type: "ovn", Project: "default"}, {Name: "default", Type: "ovn", Project: "default"}},
+			network:  "default",
+			project:  "default",
+		},
+		{
+			desc:     "Preferred name in another project",
+			networks: []lxdAPI.Network{{Name: "default", Type: "ovn", Project: "dev"}, {Name: "other", Type: "ovn", Project: "default"}},
+			network:  "default",
+			project:  "dev",
+		},
+		{
+			desc:     "First OVN network",
+			networks: []lxdAPI.Network{{Name: "UPLINK", Type: "physical"}, {Name: "web", Type: "ovn", Project: "prod"}},
+			network:  "web",
+			project:  "prod",
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		network := ovnVerificationNetwork(c.networks, "default")
+		if c.network == "" {
+			s.Nil(network)
+			continue
+		}
+
+		s.Require().NotNil(network)
+		s.Equal(c.network, network.Name)
+		s.Equal(c.project, network.Project)
+	}
+}
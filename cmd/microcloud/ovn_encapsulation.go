@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ovnConfig returns the MicroOVN configuration of the given system when bootstrapping or joining MicroOVN.
+func (c *initConfig) ovnConfig(system InitSystem) map[string]string {
+	config := map[string]string{}
+	if system.OVNGeneveNetwork != nil {
+		config["ovn-encap-ip"] = system.OVNGeneveNetwork.IP.String()
+	}
+
+	if c.ovnEncapsulation != "" && c.ovnEncapsulation != types.OVNEncapsulationGeneve {
+		config["ovn-encap-type"] = c.ovnEncapsulation
+	}
+
+	services := c.ovnServices(system.ServerInfo.Name)
+	if services != "" {
+		config["ovn-services"] = services
+	}
+
+	if len(config) == 0 {
+		return nil
+	}
+
+	return config
+}
+
+// supportsOVNEncapsulation returns whether MicroOVN lets the encapsulation of the OVN tunnels be chosen.
+func supportsOVNEncapsulation(ctx context.Context, sh *service.Handler) (bool, error) {
+	ovn, ok := sh.Services[types.MicroOVN].(*service.OVNService)
+	if !ok {
+		return false, nil
+	}
+
+	return ovn.SupportsFeature(ctx, service.OVNEncapsulationFeature)
+}
+
+// askOVNEncapsulation asks for the encapsulation of the OVN tunnels, if MicroOVN lets it be chosen.
+func (c *initConfig) askOVNEncapsulation(sh *service.Handler) error {
+	supported, err := supportsOVNEncapsulation(context.Background(), sh)
+	if err != nil || !supported {
+		return err
+	}
+
+	c.ovnEncapsulation, err = c.asker.AskString(fmt.Sprintf("Which encapsulation should the OVN tunnels use? (%s)", strings.Join(types.OVNEncapsulations, "/")), types.OVNEncapsulationGeneve, validate.IsOneOf(types.OVNEncapsulations...))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateOVNEncapsulation checks MicroOVN supports the chosen encapsulation of the OVN tunnels.
+func (c *initConfig) validateOVNEncapsulation(ctx context.Context, sh *service.Handler) error {
+	if c.ovnEncapsulation == "" || c.ovnEncapsulation == types.OVNEncapsulationGeneve || sh.Services[types.MicroOVN] == nil {
+		return nil
+	}
+
+	supported, err := supportsOVNEncapsulation(ctx, sh)
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		return fmt.Errorf("MicroOVN does not support the %q encapsulation of the OVN tunnels, upgrade MicroOVN or use %q", c.ovnEncapsulation, types.OVNEncapsulationGeneve)
+	}
+
+	return nil
+}
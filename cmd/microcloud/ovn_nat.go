@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"strconv"
+
+	"github.com/canonical/lxd/shared"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+// OVNNATOptions represents the NAT policy of the default OVN network in the preseed yaml.
+type OVNNATOptions struct {
+	// IPv4 is whether the IPv4 traffic leaving the OVN network is NATed, which LXD does by default.
+	IPv4 *bool `yaml:"ipv4"`
+
+	// IPv4Address is the IPv4 SNAT address of the OVN network on the uplink network, instead of the address of the OVN router.
+	// LXD requires the uplink network to route the address to the OVN router, so it can't be part of the IPv4 range of the uplink.
+	IPv4Address string `yaml:"ipv4_address"`
+
+	// IPv6Routed gives the OVN network a routed IPv6 subnet from the prefix delegated to the uplink network, instead of NAT.
+	IPv6Routed bool `yaml:"ipv6_routed"`
+}
+
+// ipv4NAT returns whether the IPv4 traffic leaving the OVN network is NATed.
+func (o OVNNATOptions) ipv4NAT() bool {
+	return o.IPv4 == nil || *o.IPv4
+}
+
+// validate validates the NAT policy against the configuration of the uplink network.
+func (o OVNNATOptions) validate(network InitNetwork) error {
+	if o.IPv4Address != "" {
+		if !o.ipv4NAT() {
+			return errors.New("Cannot set the IPv4 SNAT address when IPv4 NAT is disabled")
+		}
+
+		if network.IPv4Gateway == "" {
+			return errors.New("Cannot set the IPv4 SNAT address without IPv4 gateway")
+		}
+
+		err := validate.IsNetworkAddressV4(o.IPv4Address)
+		if err != nil {
+			return fmt.Errorf("Invalid IPv4 SNAT address: %w", err)
+		}
+
+		ranges, err := shared.ParseIPRanges(network.IPv4Range)
+		if err != nil {
+			return fmt.Errorf("Invalid IPv4 range: %w", err)
+		}
+
+		for _, ipRange := range ranges {
+			if ipRange.ContainsIP(net.ParseIP(o.IPv4Address)) {
+				return fmt.Errorf("IPv4 SNAT address %q must not be within the IPv4 range of the uplink network", o.IPv4Address)
+			}
+		}
+	}
+
+	if o.IPv6Routed {
+		if network.IPv6Prefix == "" {
+			return errors.New("Cannot route IPv6 without IPv6 prefix")
+		}
+
+		_, err := routedIPv6Address(network.IPv6Prefix)
+		if err != nil {
+			return fmt.Errorf("Cannot route IPv6: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// routedIPv6Address returns the address of the OVN network in the first /64 subnet of the given delegated prefix.
+func routedIPv6Address(prefix string) (string, error) {
+	_, prefixNet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+
+	ones, _ := prefixNet.Mask.Size()
+	if ones > 64 {
+		return "", fmt.Errorf("IPv6 prefix %q is smaller than a /64 subnet", prefix)
+	}
+
+	ip := prefixNet.IP.To16()
+	ip[len(ip)-1] = 1
+
+	return ip.String() + "/64", nil
+}
+
+// networks returns the given uplink and OVN networks with the NAT policy applied.
+// The routed IPv6 subnet is taken from the IPv6 routes of the uplink network.
+func (o OVNNATOptions) networks(uplink lxdAPI.NetworksPost, ovn lxdAPI.NetworksPost) (lxdAPI.NetworksPost, lxdAPI.NetworksPost, error) {
+	uplink.Config = maps.Clone(uplink.Config)
+	ovn.Config = maps.Clone(ovn.Config)
+
+	if !o.ipv4NAT() {
+		ovn.Config["ipv4.nat"] = strconv.FormatBool(false)
+	}
+
+	// LXD only accepts SNAT addresses routed to the OVN networks by the uplink network.
+	if o.IPv4Address != "" {
+		ovn.Config["ipv4.nat.address"] = o.IPv4Address
+		uplink.Config["ovn.ingress_mode"] = "routed"
+		uplink.Config["ipv4.routes"] = o.IPv4Address + "/32"
+	}
+
+	if o.IPv6Routed {
+		address, err := routedIPv6Address(uplink.Config["ipv6.routes"])
+		if err != nil {
+			return uplink, ovn, fmt.Errorf("Failed to route IPv6 on network %q: %w", ovn.Name, err)
+		}
+
+		ovn.Config["ipv6.address"] = address
+		ovn.Config["ipv6.nat"] = strconv.FormatBool(false)
+	}
+
+	return uplink, ovn, nil
+}
+
+// askOVNNAT asks for the NAT policy of the default OVN network, given the configuration of the uplink network.
+func (c *initConfig) askOVNNAT(network InitNetwork) error {
+	wantsNAT, err := c.asker.AskBool("Configure the NAT policy of the default OVN network?", false)
+	if err != nil || !wantsNAT {
+		return err
+	}
+
+	if network.IPv4Gateway != "" {
+		ipv4NAT, err := c.asker.AskBool("Should the IPv4 traffic leaving the OVN network use NAT?", true)
+		if err != nil {
+			return err
+		}
+
+		c.ovnNAT.IPv4 = &ipv4NAT
+		if ipv4NAT {
+			c.ovnNAT.IPv4Address, err = c.asker.AskString("Specify the IPv4 SNAT address on the uplink network, outside of its IPv4 range (empty to use the address of the OVN router)", "", validate.Optional(func(value string) error {
+				return OVNNATOptions{IPv4Address: value}.validate(network)
+			}))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if network.IPv6Prefix != "" {
+		c.ovnNAT.IPv6Routed, err = c.asker.AskBool("Should the OVN network use a routed subnet of the delegated IPv6 prefix instead of NAT?", true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type ovnNATSuite struct {
+	suite.Suite
+}
+
+func TestOVNNATSuite(t *testing.T) {
+	suite.Run(t, new(ovnNATSuite))
+}
+
+func (s *ovnNATSuite) Test_ovnNATOptionsValidate() {
+	disabled := false
+	network := InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254", IPv6Gateway: "fd42::1/64", IPv6Prefix: "fd43::/56"}
+
+	cases := []struct {
+		desc    string
+		options OVNNATOptions
+		network InitNetwork
+		err     bool
+	}{
+		{desc: "No options", options: OVNNATOptions{}, network: network},
+		{desc: "IPv4 NAT disabled", options: OVNNATOptions{IPv4: &disabled}, network: network},
+		{desc: "IPv4 SNAT address", options: OVNNATOptions{IPv4Address: "10.0.0.99"}, network: network},
+		{desc: "IPv4 SNAT address with NAT disabled", options: OVNNATOptions{IPv4: &disabled, IPv4Address: "10.0.0.99"}, network: network, err: true},
+		{desc: "IPv4 SNAT address within the range", options: OVNNATOptions{IPv4Address: "10.0.0.100"}, network: network, err: true},
+		{desc: "IPv4 SNAT address without IPv4 gateway", options: OVNNATOptions{IPv4Address: "10.0.0.99"}, network: InitNetwork{IPv6Gateway: "fd42::1/64"}, err: true},
+		{desc: "Invalid IPv4 SNAT address", options: OVNNATOptions{IPv4Address: "fd42::99"}, network: network, err: true},
+		{desc: "Routed IPv6", options: OVNNATOptions{IPv6Routed: true}, network: network},
+		{desc: "Routed IPv6 without prefix", options: OVNNATOptions{IPv6Routed: true}, network: InitNetwork{IPv6Gateway: "fd42::1/64"}, err: true},
+		{desc: "Routed IPv6 with a small prefix", options: OVNNATOptions{IPv6Routed: true}, network: InitNetwork{IPv6Gateway: "fd42::1/64", IPv6Prefix: "fd43::/96"}, err: true},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := c.options.validate(c.network)
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+		}
+	}
+}
+
+func (s *ovnNATSuite) Test_ovnNATOptionsNetworks() {
+	disabled := false
+	uplink := lxdAPI.NetworksPost{Name: "UPLINK", NetworkPut: lxdAPI.NetworkPut{Config: map[string]string{"ipv4.gateway": "10.0.0.1/24", "ipv6.routes": "fd43::/56"}}}
+	ovn := lxdAPI.NetworksPost{Name: "default", NetworkPut: lxdAPI.NetworkPut{Config: map[string]string{"network": "UPLINK"}}}
+
+	s.T().Log("Default NAT policy")
+	newUplink, newOVN, err := OVNNATOptions{}.networks(uplink, ovn)
+	s.NoError(err)
+	s.Equal(uplink, newUplink)
+	s.Equal(ovn, newOVN)
+
+	s.T().Log("IPv4 NAT disabled and routed IPv6")
+	newUplink, newOVN, err = OVNNATOptions{IPv4: &disabled, IPv6Routed: true}.networks(uplink, ovn)
+	s.NoError(err)
+	s.Equal(uplink.Config, newUplink.Config)
+	s.Equal(map[string]string{"network": "UPLINK", "ipv4.nat": "false", "ipv6.address": "fd43::1/64", "ipv6.nat": "false"}, newOVN.Config)
+
+	s.T().Log("IPv4 SNAT address")
+	newUplink, newOVN, err = OVNNATOptions{IPv4Address: "10.0.0.99"}.networks(uplink, ovn)
+	s.NoError(err)
+	s.Equal(map[string]string{"ipv4.gateway": "10.0.0.1/24", "ipv6.routes": "fd43::/56", "ovn.ingress_mode": "routed", "ipv4.routes": "10.0.0.99/32"}, newUplink.Config)
+	s.Equal(map[string]string{"network": "UPLINK", "ipv4.nat.address": "10.0.0.99"}, newOVN.Config)
+	s.Len(uplink.Config, 2)
+}
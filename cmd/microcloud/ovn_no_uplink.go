@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	lxd "github.com/canonical/lxd/client"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ovnChassisMembers returns the LXD cluster members missing the role which makes them OVN gateway chassis candidates.
+// As every member is a candidate while none has the role, it is only given out once some members have no uplink connectivity.
+// Afterwards, the existing members which aren't set up by this run are left alone, as they don't have the role on purpose.
+func ovnChassisMembers(members []lxdAPI.ClusterMember, systems map[string]InitSystem) []string {
+	rolesInUse := slices.ContainsFunc(members, func(member lxdAPI.ClusterMember) bool {
+		return slices.Contains(member.Roles, service.OVNChassisRole)
+	})
+
+	noUplink := false
+	for _, system := range systems {
+		if system.NoUplink {
+			noUplink = true
+			break
+		}
+	}
+
+	if !rolesInUse && !noUplink {
+		return nil
+	}
+
+	names := []string{}
+	for _, member := range members {
+		if slices.Contains(member.Roles, service.OVNChassisRole) {
+			continue
+		}
+
+		system, ok := systems[member.ServerName]
+		if (rolesInUse && !ok) || system.NoUplink {
+			continue
+		}
+
+		names = append(names, member.ServerName)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// setupOVNChassisRoles restricts the OVN gateway chassis to the cluster members with uplink connectivity.
+func (c *initConfig) setupOVNChassisRoles(lxdClient lxd.InstanceServer) error {
+	members, err := lxdClient.GetClusterMembers()
+	if err != nil {
+		return fmt.Errorf("Failed to get the LXD cluster members: %w", err)
+	}
+
+	for _, name := range ovnChassisMembers(members, c.systems) {
+		member, etag, err := lxdClient.GetClusterMember(name)
+		if err != nil {
+			return fmt.Errorf("Failed to get LXD cluster member %q: %w", name, err)
+		}
+
+		newMember := member.Writable()
+		newMember.Roles = append(newMember.Roles, service.OVNChassisRole)
+		err = lxdClient.UpdateClusterMember(name, newMember, etag)
+		if err != nil {
+			return fmt.Errorf("Failed to add the %q role to LXD cluster member %q: %w", service.OVNChassisRole, name, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Made %s an OVN gateway chassis candidate", name))
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type ovnNoUplinkSuite struct {
+	suite.Suite
+}
+
+func TestOVNNoUplinkSuite(t *testing.T) {
+	suite.Run(t, new(ovnNoUplinkSuite))
+}
+
+func (s *ovnNoUplinkSuite) Test_ovnChassisMembers() {
+	member := func(name string, roles ...string) lxdAPI.ClusterMember {
+		return lxdAPI.ClusterMember{ServerName: name, Roles: roles}
+	}
+
+	cases := []struct {
+		desc    string
+		members []lxdAPI.ClusterMember
+		systems map[string]InitSystem
+		result  []string
+	}{
+		{
+			desc:    "All systems have an uplink",
+			members: []lxdAPI.ClusterMember{member("n1"), member("n2")},
+			systems: map[string]InitSystem{"n1": {}, "n2": {}},
+		},
+		{
+			desc:    "New system with no uplink",
+			members: []lxdAPI.ClusterMember{member("n1"), member("n2"), member("n3")},
+			systems: map[string]InitSystem{"n1": {}, "n2": {}, "n3": {NoUplink: true}},
+			result:  []string{"n1", "n2"},
+		},
+		{
+			desc:    "Existing members are candidates once a system has no uplink",
+			members: []lxdAPI.ClusterMember{member("n1"), member("n2"), member("n3")},
+			systems: map[string]InitSystem{"n3": {NoUplink: true}},
+			result:  []string{"n1", "n2"},
+		},
+		{
+			desc:    "New system with an uplink when the roles are in use",
+			members: []lxdAPI.ClusterMember{member("n1", service.OVNChassisRole), member("n2"), member("n3")},
+			systems: map[string]InitSystem{"n3": {}},
+			result:  []string{"n3"},
+		},
+		{
+			desc:    "New system with no uplink when the roles are in use",
+			members: []lxdAPI.ClusterMember{member("n1", service.OVNChassisRole), member("n2"), member("n3")},
+			systems: map[string]InitSystem{"n3": {NoUplink: true}},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.ElementsMatch(c.result, ovnChassisMembers(c.members, c.systems))
+	}
+}
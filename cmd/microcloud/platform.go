@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// formatPlatform returns the platform a system runs in, as shown in the status table.
+func formatPlatform(platform *types.Platform) string {
+	if platform == nil {
+		return "-"
+	}
+
+	if !platform.Virtual {
+		return "physical"
+	}
+
+	if platform.Cloud != "" {
+		return "vm (" + platform.Cloud + ")"
+	}
+
+	return "vm"
+}
+
+// virtualSystems returns the names of the systems being set up which run as virtual machines, and of those without KVM.
+func (c *initConfig) virtualSystems() (virtual []string, noKVM []string) {
+	for name := range c.systems {
+		platform := c.state[name].Platform
+		if platform == nil || !platform.Virtual {
+			continue
+		}
+
+		virtual = append(virtual, name)
+		if !platform.KVM {
+			noKVM = append(noKVM, name)
+		}
+	}
+
+	sort.Strings(virtual)
+	sort.Strings(noKVM)
+
+	return virtual, noKVM
+}
+
+// warnPlatforms reports the systems which run as virtual machines, and warns about those which can't run virtual machines themselves.
+func (c *initConfig) warnPlatforms() {
+	virtual, noKVM := c.virtualSystems()
+	if len(virtual) == 0 {
+		return
+	}
+
+	if len(virtual) == len(c.systems) {
+		fmt.Println(tui.SummarizeResult("All systems are virtual machines. Ceph pools default to %d replicas", service.VirtualOSDPoolSize))
+	}
+
+	if len(noKVM) > 0 {
+		tui.PrintWarning(fmt.Sprintf("Nested KVM is not available on %s. Only containers can run on these systems", strings.Join(noKVM, ", ")))
+	}
+}
+
+// defaultOSDPoolSize returns the default replication factor of the OSD pools for the given number of disks.
+// When all systems being set up run as virtual machines, fewer replicas are used.
+func (c *initConfig) defaultOSDPoolSize(disks int) int {
+	size := min(disks, RecommendedOSDHosts)
+
+	virtual, _ := c.virtualSystems()
+	if len(virtual) > 0 && len(virtual) == len(c.systems) {
+		size = min(size, service.VirtualOSDPoolSize)
+	}
+
+	return size
+}
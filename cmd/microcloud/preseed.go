@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"os"
 	"slices"
@@ -31,16 +32,55 @@ import (
 
 // Preseed represents the structure of the supported preseed yaml.
 type Preseed struct {
-	LookupSubnet      string        `yaml:"lookup_subnet"`
-	LookupTimeout     int64         `yaml:"lookup_timeout"`
-	SessionPassphrase string        `yaml:"session_passphrase"`
-	SessionTimeout    int64         `yaml:"session_timeout"`
-	Initiator         string        `yaml:"initiator"`
-	InitiatorAddress  string        `yaml:"initiator_address"`
-	Systems           []System      `yaml:"systems"`
-	OVN               InitNetwork   `yaml:"ovn"`
-	Ceph              CephOptions   `yaml:"ceph"`
-	Storage           StorageFilter `yaml:"storage"`
+	LookupSubnet      string           `yaml:"lookup_subnet"`
+	LookupTimeout     int64            `yaml:"lookup_timeout"`
+	SessionPassphrase string           `yaml:"session_passphrase"`
+	SessionTimeout    int64            `yaml:"session_timeout"`
+	Initiator         string           `yaml:"initiator"`
+	InitiatorAddress  string           `yaml:"initiator_address"`
+	Systems           []System         `yaml:"systems"`
+	OVN               InitNetwork      `yaml:"ovn"`
+	Ceph              CephOptions      `yaml:"ceph"`
+	Storage           StorageFilter    `yaml:"storage"`
+	Images            ImageOptions     `yaml:"images"`
+	Limits            LimitsOptions    `yaml:"limits"`
+	Snapshots         SnapshotOptions  `yaml:"snapshots"`
+	Projects          []ProjectOptions `yaml:"projects"`
+	Benchmark         bool             `yaml:"benchmark"`
+	Tuning            TuningOptions    `yaml:"tuning"`
+
+	// Names are the custom names of the storage pools and networks set up by MicroCloud.
+	Names NamesOptions `yaml:"names"`
+
+	// Volumes are the images and backups volumes set up on each system.
+	Volumes VolumeOptions `yaml:"volumes"`
+
+	// Conflicts maps the names of existing storage pools and networks which can't be used by MicroCloud to how to resolve them.
+	Conflicts map[string]string `yaml:"conflicts"`
+
+	// Services selects the optional services to set up.
+	Services ServiceOptions `yaml:"services"`
+}
+
+// ServiceOptions selects the optional services set up from the preseed yaml.
+// Each service is set up if installed, unless set to false.
+type ServiceOptions struct {
+	MicroCeph *bool `yaml:"microceph"`
+	MicroOVN  *bool `yaml:"microovn"`
+}
+
+// excluded returns the names of the optional services which must not be set up.
+func (o ServiceOptions) excluded() []string {
+	excluded := []string{}
+	if o.MicroCeph != nil && !*o.MicroCeph {
+		excluded = append(excluded, string(types.MicroCeph))
+	}
+
+	if o.MicroOVN != nil && !*o.MicroOVN {
+		excluded = append(excluded, string(types.MicroOVN))
+	}
+
+	return excluded
 }
 
 // System represents the structure of the systems we expect to find in the preseed yaml.
@@ -48,8 +88,18 @@ type System struct {
 	Name            string      `yaml:"name"`
 	Address         string      `yaml:"address"`
 	UplinkInterface string      `yaml:"ovn_uplink_interface"`
+	NoUplink        bool        `yaml:"ovn_no_uplink"`
 	UnderlayIP      string      `yaml:"ovn_underlay_ip"`
 	Storage         InitStorage `yaml:"storage"`
+
+	// LXDAddress is the address LXD listens on for its API, all addresses if empty.
+	LXDAddress string `yaml:"lxd_address"`
+
+	// LXDClusterAddress is the address LXD listens on for the cluster traffic, the MicroCloud address if empty.
+	LXDClusterAddress string `yaml:"lxd_cluster_address"`
+
+	// UplinkSelector selects the uplink interface by its hardware, instead of by its name.
+	UplinkSelector *InterfaceSelector `yaml:"ovn_uplink_selector"`
 }
 
 // InitStorage separates the direct paths used for local and ceph disks.
@@ -63,6 +113,9 @@ type DirectStorage struct {
 	Path    string `yaml:"path"`
 	Wipe    bool   `yaml:"wipe"`
 	Encrypt bool   `yaml:"encrypt"`
+
+	// Tier is the performance tier the Ceph disk is part of, if any.
+	Tier string `yaml:"tier"`
 }
 
 // InitNetwork represents the structure of the network config in the preseed yaml.
@@ -71,6 +124,21 @@ type InitNetwork struct {
 	IPv4Range   string `yaml:"ipv4_range"`
 	IPv6Gateway string `yaml:"ipv6_gateway"`
 	DNSServers  string `yaml:"dns_servers"`
+
+	// IPv6Prefix is the IPv6 prefix delegated to the uplink network, from which OVN networks can be given routed subnets.
+	IPv6Prefix string `yaml:"ipv6_prefix"`
+
+	// DNSSearch is the comma separated list of DNS search domains of the instances on the OVN network.
+	DNSSearch string `yaml:"dns_search"`
+
+	// Encapsulation is the encapsulation of the OVN tunnels, either geneve or vxlan.
+	Encapsulation string `yaml:"encapsulation"`
+
+	// Central are the systems running the OVN central services, MicroOVN picks them if empty.
+	Central []string `yaml:"central"`
+
+	// NAT is the NAT policy of the default OVN network.
+	NAT OVNNATOptions `yaml:"nat"`
 }
 
 // CephOptions represents the structure of the ceph options in the preseed yaml.
@@ -78,6 +146,13 @@ type CephOptions struct {
 	PublicNetwork   string `yaml:"public_network"`
 	InternalNetwork string `yaml:"internal_network"`
 	CephFS          bool   `yaml:"cephfs"`
+	Dashboard       bool   `yaml:"dashboard"`
+
+	// Tiers are the performance tiers of the distributed storage, each with its own storage pool.
+	Tiers []service.CephTier `yaml:"tiers"`
+
+	// Pools are the advanced options of the Ceph OSD pools created by MicroCloud.
+	Pools CephPoolOptions `yaml:"pools"`
 }
 
 // StorageFilter separates the filters used for local and ceph disks.
@@ -93,6 +168,9 @@ type DiskFilter struct {
 	FindMax int    `yaml:"find_max"`
 	Wipe    bool   `yaml:"wipe"`
 	Encrypt bool   `yaml:"encrypt"`
+
+	// Tier is the performance tier the matched Ceph disks are part of, if any.
+	Tier string `yaml:"tier"`
 }
 
 // DiskOperatorSet is the set of operators supported for filtering disks.
@@ -114,6 +192,12 @@ func DiskOperatorSet() filter.OperatorSet {
 
 type cmdPreseed struct {
 	common *CmdControl
+
+	flagManifest string
+	flagForce    bool
+	flagSimulate bool
+
+	flagAllowSystemDisks bool
 }
 
 // command returns the subcommand for unattended cluster initialization.
@@ -124,6 +208,12 @@ func (c *cmdPreseed) command() *cobra.Command {
 		RunE:  c.run,
 	}
 
+	cmd.Flags().StringVar(&c.flagManifest, "manifest", "", "Write a JSON manifest of the set up resources to this file"+"``")
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, "Set up the systems despite unsupported service versions or API extensions")
+	cmd.Flags().BoolVar(&c.flagSimulate, "simulate", false, "Validate the preseed and show the disks it selects on simulated systems, without setting anything up")
+	cmd.Flags().BoolVar(&c.flagAllowSystemDisks, allowSystemDisksFlag, false, allowSystemDisksUsage)
+	cmd.MarkFlagsMutuallyExclusive("simulate", "manifest")
+
 	return cmd
 }
 
@@ -137,26 +227,55 @@ func (c *cmdPreseed) run(cmd *cobra.Command, args []string) error {
 		common:  c.common,
 		systems: map[string]InitSystem{},
 		state:   map[string]service.SystemInformation{},
+
+		manifestPath: c.flagManifest,
+		force:        c.flagForce,
 	}
 
+	if c.flagSimulate {
+		config, err := readPreseed()
+		if err != nil {
+			return err
+		}
+
+		return cfg.simulatePreseed(*config)
+	}
+
+	cfg.allowSystemDisks(c.flagAllowSystemDisks)
+
 	return cfg.RunPreseed(cmd)
 }
 
-// RunPreseed initializes MicroCloud from a preseed yaml filepath input.
-func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
-	c.autoSetup = true
-
+// readPreseed reads the preseed yaml from stdin.
+func readPreseed() (*Preseed, error) {
 	bytes, err := io.ReadAll(os.Stdin)
 	if err != nil {
-		return fmt.Errorf("Failed to read from stdin: %w", err)
+		return nil, fmt.Errorf("Failed to read from stdin: %w", err)
 	}
 
 	config := Preseed{}
 	err = yaml.Unmarshal(bytes, &config)
 	if err != nil {
-		return fmt.Errorf("Failed to parse the preseed yaml: %w", err)
+		return nil, withExitCode(ExitCodeValidation, fmt.Errorf("Failed to parse the preseed yaml: %w", err))
+	}
+
+	return &config, nil
+}
+
+// RunPreseed initializes MicroCloud from a preseed yaml filepath input.
+func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
+	config, err := readPreseed()
+	if err != nil {
+		return err
 	}
 
+	return c.runPreseed(*config)
+}
+
+// runPreseed initializes or extends MicroCloud from the given preseed.
+func (c *initConfig) runPreseed(config Preseed) error {
+	c.autoSetup = true
+
 	hostname, err := os.Hostname()
 	if err != nil {
 		return err
@@ -177,13 +296,31 @@ func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
 		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
 	}
 
+	// Applying the same preseed again skips the systems which are already set up, and only sets up the remaining ones.
+	if status.Ready {
+		var done bool
+		config, done, err = c.skipClusteredSystems(context.Background(), cloudApp, config, hostname, status.Address.Addr().String())
+		if err != nil || done {
+			return err
+		}
+	}
+
 	c.bootstrap = config.isBootstrap()
 
 	err = config.validate(hostname, c.bootstrap)
 	if err != nil {
-		return err
+		return withExitCode(ExitCodeValidation, err)
 	}
 
+	c.excludedServices = config.Services.excluded()
+	c.cephDashboard = config.Ceph.Dashboard
+	c.images = config.Images
+	c.limits = config.Limits
+	c.snapshots = config.Snapshots
+	c.projects = config.Projects
+	c.benchmark = config.Benchmark
+	c.tuning = config.Tuning
+
 	var listenAddr string
 	if status.Ready {
 		// If the cluster is already bootstrapped use its address.
@@ -228,6 +365,11 @@ func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
 		types.MicroOVN:  api.MicroOVNDir,
 	}
 
+	optionalServices, err = c.selectedServices(optionalServices)
+	if err != nil {
+		return err
+	}
+
 	installedServices, err = c.askMissingServices(installedServices, optionalServices)
 	if err != nil {
 		return err
@@ -238,14 +380,28 @@ func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
 		return err
 	}
 
-	services := make(map[types.ServiceType]string, len(installedServices))
-	for _, s := range s.Services {
-		version, err := s.GetVersion(context.Background())
+	// Set up the new systems like the ones set up with MicroCloud.
+	if c.bootstrap {
+		s.Services[types.LXD].(*service.LXDService).SetResourceNames(config.Names.resourceNames())
+		c.volumes = config.Volumes
+		c.cephTiers = config.Ceph.Tiers
+		c.cephPools = config.Ceph.Pools
+		c.ovnEncapsulation = config.OVN.Encapsulation
+		if len(config.OVN.Central) > 0 {
+			c.ovnCentral = config.OVN.Central
+			warnOVNCentral(c.ovnCentral)
+		}
+		c.ovnNAT = config.OVN.NAT
+	} else {
+		err = c.loadSetupConfig(context.Background(), s)
 		if err != nil {
 			return err
 		}
+	}
 
-		services[s.Type()] = version
+	services, err := s.GetVersions(context.Background())
+	if err != nil {
+		return err
 	}
 
 	if !status.Ready && !c.bootstrap && initiator {
@@ -263,12 +419,15 @@ func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
 
 	// Exit in case of join.
 	// Only the initiator has to continue.
+	// The image remotes are configured on each system as they are local to the LXD client.
 	if systems == nil {
-		return nil
+		return writeImageRemotes(c.images.Remotes)
 	}
 
 	if !c.bootstrap {
-		peers, err := s.Services[types.MicroCloud].ClusterMembers(context.Background())
+		peers, err := service.RetryValue(context.Background(), s.RetryPolicy, func() (map[string]string, error) {
+			return s.Services[types.MicroCloud].ClusterMembers(context.Background())
+		})
 		if err != nil {
 			return err
 		}
@@ -288,7 +447,7 @@ func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
 
 	err = c.validateSystems(s)
 	if err != nil {
-		return err
+		return withExitCode(ExitCodeValidation, err)
 	}
 
 	if !c.bootstrap {
@@ -320,77 +479,205 @@ func (c *initConfig) RunPreseed(cmd *cobra.Command) error {
 	return c.setupCluster(s)
 }
 
+// skipClusteredSystems removes the systems which are already part of MicroCloud from the preseed, so only the remaining systems are set up.
+// It returns true if this system has nothing left to set up.
+func (c *initConfig) skipClusteredSystems(ctx context.Context, cloudApp *microcluster.MicroCluster, config Preseed, name string, address string) (Preseed, bool, error) {
+	client, err := cloudApp.LocalClient()
+	if err != nil {
+		return config, false, err
+	}
+
+	members, err := client.GetClusterMembers(ctx)
+	if err != nil {
+		return config, false, fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	clustered := make(map[string]string, len(members))
+	for _, member := range members {
+		clustered[member.Name] = member.Address.Addr().String()
+	}
+
+	if !config.isInitiator(name, address) {
+		// A system set up separately from the initiator still can't join it.
+		if !config.initiatorIn(clustered) {
+			return config, false, nil
+		}
+
+		fmt.Println(tui.SummarizeResult("System %s is already part of MicroCloud", name))
+
+		return config, true, writeImageRemotes(config.Images.Remotes)
+	}
+
+	pending, skipped := config.withoutClusteredSystems(clustered)
+	if len(skipped) > 0 {
+		fmt.Println(tui.SummarizeResult("Skipping the systems already part of MicroCloud: %s", strings.Join(skipped, ", ")))
+	}
+
+	if len(pending.Systems) == 0 {
+		fmt.Println(tui.SummarizeResult("All systems of the preseed are already part of MicroCloud"))
+		return pending, true, nil
+	}
+
+	return pending, false, nil
+}
+
+// initiatorIn returns whether the initiator is one of the given cluster members, keyed by name with their address as value.
+func (p *Preseed) initiatorIn(members map[string]string) bool {
+	for name, address := range members {
+		if p.isInitiator(name, address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withoutClusteredSystems returns the preseed without the systems which are one of the given cluster members, along with the names of the removed systems.
+// If the initiator is removed, the options which only apply when setting up a new MicroCloud are dropped too, as they were applied when it was set up.
+func (p *Preseed) withoutClusteredSystems(members map[string]string) (Preseed, []string) {
+	pending := *p
+	pending.Systems = make([]System, 0, len(p.Systems))
+	skipped := []string{}
+	for _, system := range p.Systems {
+		clustered := members[system.Name] != ""
+		for _, address := range members {
+			if system.Address != "" && system.Address == address {
+				clustered = true
+			}
+		}
+
+		if clustered {
+			skipped = append(skipped, system.Name)
+			continue
+		}
+
+		pending.Systems = append(pending.Systems, system)
+	}
+
+	if p.isBootstrap() && !pending.isBootstrap() {
+		pending.Ceph.Dashboard = false
+		pending.Ceph.Tiers = nil
+		pending.Ceph.Pools = CephPoolOptions{}
+		pending.OVN.Encapsulation = ""
+		pending.OVN.Central = nil
+		pending.OVN.NAT = OVNNATOptions{}
+		pending.Names = NamesOptions{}
+		pending.Volumes = VolumeOptions{}
+		pending.Limits = LimitsOptions{}
+		pending.Snapshots = SnapshotOptions{}
+		pending.Projects = nil
+	}
+
+	return pending, skipped
+}
+
 // validate validates the unmarshaled preseed input.
+// All the violated constraints are returned at once as PreseedErrors, each with the YAML path of the offending value.
 func (p *Preseed) validate(name string, bootstrap bool) error {
+	errs := PreseedErrors{}
 	uplinkCount := 0
+	noUplinkCount := 0
 	underlayCount := 0
 	directCephCount := 0
 	directLocalCount := 0
 	localInit := false
 
 	if len(p.Systems) < 1 {
-		return errors.New("No systems given")
+		errs.add("systems", nil, "Must contain at least one system")
 	}
 
 	if p.Initiator == "" && p.InitiatorAddress == "" {
-		return errors.New("Missing initiator's name or address")
+		errs.add("initiator", nil, "Either the initiator's name or address must be set")
 	}
 
 	if p.Initiator != "" && p.InitiatorAddress != "" {
-		return errors.New("Cannot provide both the initiator's name and address")
+		errs.add("initiator_address", p.InitiatorAddress, "Cannot be set along with the initiator's name")
 	}
 
 	if p.Initiator != "" && p.LookupSubnet == "" {
-		return errors.New("Missing lookup subnet")
+		errs.add("lookup_subnet", nil, "Must be set along with the initiator's name")
 	}
 
 	if p.InitiatorAddress != "" && p.LookupSubnet != "" {
-		return errors.New("Cannot provide both the initiator's address and lookup subnet")
+		errs.add("lookup_subnet", p.LookupSubnet, "Cannot be set along with the initiator's address")
 	}
 
 	if len(p.Systems) > 1 && p.SessionPassphrase == "" {
-		return errors.New("Missing session passphrase")
+		errs.add("session_passphrase", nil, "Must be set when there is more than one system")
 	}
 
 	systemNames := make([]string, 0, len(p.Systems))
-	for _, system := range p.Systems {
+	for i, system := range p.Systems {
+		path := fmt.Sprintf("systems[%d]", i)
 		if system.Name == "" {
-			return errors.New("Missing system name")
+			errs.add(path+".name", nil, "Must be set")
+		} else if slices.Contains(systemNames, system.Name) {
+			errs.add(path+".name", system.Name, "Must be unique among the systems")
 		}
 
 		if system.Address != "" && p.LookupSubnet != "" {
-			return fmt.Errorf("Cannot provide both the address for system %q and the lookup subnet", system.Name)
+			errs.add(path+".address", system.Address, "Cannot be set along with the lookup subnet")
 		}
 
 		if system.Address == "" && p.InitiatorAddress != "" {
-			return fmt.Errorf("Missing address for system %q when the initiator's address is set", system.Name)
+			errs.add(path+".address", nil, "Must be set when the initiator's address is set")
 		}
 
 		if system.Address != "" && p.InitiatorAddress == "" {
-			return fmt.Errorf("Missing the initiator's address as system %q has an address", system.Name)
+			errs.add(path+".address", system.Address, "Cannot be set without the initiator's address")
 		}
 
 		if system.Name == name {
 			localInit = true
 		}
 
-		if system.UplinkInterface != "" {
-			uplinkCount++
+		if system.NoUplink {
+			if system.UplinkInterface != "" {
+				errs.add(path+".ovn_uplink_interface", system.UplinkInterface, "Cannot be set when the system is set up with no uplink")
+			}
 
-			if p.OVN.IPv4Gateway == "" && p.OVN.IPv6Gateway == "" && bootstrap {
-				return errors.New("Either the IPv4 or IPv6 gateway has to be set on the uplink network")
+			noUplinkCount++
+		}
+
+		if system.UplinkSelector != nil {
+			if system.UplinkInterface != "" {
+				errs.add(path+".ovn_uplink_selector", nil, "Cannot be set along with the uplink interface")
 			}
+
+			if system.NoUplink {
+				errs.add(path+".ovn_uplink_selector", nil, "Cannot be set when the system is set up with no uplink")
+			}
+
+			errs.addErr(path+".ovn_uplink_selector", system.UplinkSelector.validate())
+		}
+
+		if system.UplinkInterface != "" || system.UplinkSelector != nil {
+			uplinkCount++
+		}
+
+		if system.LXDAddress != "" && validateLXDAddress(system.LXDAddress) != nil {
+			errs.add(path+".lxd_address", system.LXDAddress, "Must be an IP address or a wildcard address, with an optional port")
+		}
+
+		if system.LXDClusterAddress != "" && validateLXDClusterAddress(system.LXDClusterAddress) != nil {
+			errs.add(path+".lxd_cluster_address", system.LXDClusterAddress, "Must be an IP address, with an optional port")
 		}
 
 		if system.UnderlayIP != "" {
 			ip := net.ParseIP(system.UnderlayIP)
 			if ip == nil {
-				return fmt.Errorf("Invalid underlay IP %q", system.UnderlayIP)
+				errs.add(path+".ovn_underlay_ip", system.UnderlayIP, "Must be an IP address")
 			}
 
 			underlayCount++
 		}
 
+		for j, disk := range system.Storage.Ceph {
+			if disk.Path == "" {
+				errs.add(fmt.Sprintf("%s.storage.ceph[%d].path", path, j), nil, "Must be set")
+			}
+		}
+
 		if len(system.Storage.Ceph) > 0 {
 			directCephCount++
 		}
@@ -399,115 +686,262 @@ func (p *Preseed) validate(name string, bootstrap bool) error {
 			directLocalCount++
 		}
 
-		if slices.Contains(systemNames, system.Name) {
-			return fmt.Errorf("Duplicate system name %q", system.Name)
-		}
-
 		systemNames = append(systemNames, system.Name)
 	}
 
 	if bootstrap && !localInit {
-		return errors.New("Local MicroCloud must be included in the list of systems when initializing")
+		errs.add("systems", nil, "Must include the local system %q when setting up a new MicroCloud", name)
 	}
 
-	containsUplinks := false
-	containsLocalStorage := false
-	containsCephStorage := false
-	containsUplinks = uplinkCount > 0
-	if containsUplinks && uplinkCount < len(p.Systems) {
-		return errors.New("Some systems are missing an uplink interface")
+	containsUplinks := uplinkCount > 0
+	containsUnderlay := underlayCount > 0
+	containsLocalStorage := directLocalCount > 0
+	containsCephStorage := directCephCount > 0 || len(p.Storage.Ceph) > 0
+
+	// Point at each of the systems missing what the other systems have.
+	for i, system := range p.Systems {
+		path := fmt.Sprintf("systems[%d]", i)
+		if containsUplinks && system.UplinkInterface == "" && system.UplinkSelector == nil && !system.NoUplink {
+			errs.add(path+".ovn_uplink_interface", nil, "Must be set when other systems have an uplink interface, unless the system is set up with no uplink")
+		}
+
+		if containsUnderlay && system.UnderlayIP == "" {
+			errs.add(path+".ovn_underlay_ip", nil, "Must be set when other systems have an underlay IP")
+		}
+
+		if containsLocalStorage && system.Storage.Local.Path == "" && len(p.Storage.Local) == 0 {
+			errs.add(path+".storage.local.path", nil, "Must be set when other systems have a local disk, unless local disk filters are set")
+		}
 	}
 
-	containsUnderlay := underlayCount > 0
-	if containsUnderlay && underlayCount < len(p.Systems) {
-		return errors.New("Some systems are missing an underlay interface")
+	if containsUplinks && bootstrap && p.OVN.IPv4Gateway == "" && p.OVN.IPv6Gateway == "" {
+		errs.add("ovn", nil, "Either the IPv4 or IPv6 gateway must be set when systems have an uplink interface")
 	}
 
-	containsLocalStorage = directLocalCount > 0
-	if containsLocalStorage && directLocalCount < len(p.Systems) && len(p.Storage.Local) == 0 {
-		return errors.New("Some systems are missing local storage disks")
+	if noUplinkCount > 0 && !containsUplinks {
+		errs.add("systems", nil, "At least one system must have an uplink interface when others are set up with no uplink")
 	}
 
-	containsCephStorage = directCephCount > 0 || len(p.Storage.Ceph) > 0
-	usingCephPublicNetwork := p.Ceph.PublicNetwork != ""
-	if !containsCephStorage && usingCephPublicNetwork {
-		return errors.New("Cannot specify a Ceph public network without Ceph storage disks")
+	if containsCephStorage && slices.Contains(p.Services.excluded(), string(types.MicroCeph)) {
+		errs.add("services.microceph", false, "Cannot be disabled when Ceph storage disks are set up")
 	}
 
-	if usingCephPublicNetwork {
+	if (containsUplinks || noUplinkCount > 0 || containsUnderlay) && slices.Contains(p.Services.excluded(), string(types.MicroOVN)) {
+		errs.add("services.microovn", false, "Cannot be disabled when OVN uplink or underlay interfaces are set up")
+	}
+
+	if p.Ceph.PublicNetwork != "" {
+		if !containsCephStorage {
+			errs.add("ceph.public_network", p.Ceph.PublicNetwork, "Cannot be set without Ceph storage disks")
+		}
+
 		err := validate.IsNetwork(p.Ceph.PublicNetwork)
 		if err != nil {
-			return fmt.Errorf("Invalid Ceph public network subnet: %v", err)
+			errs.add("ceph.public_network", p.Ceph.PublicNetwork, "Must be a subnet")
 		}
 	}
 
-	usingCephInternalNetwork := p.Ceph.InternalNetwork != ""
-	if !containsCephStorage && usingCephInternalNetwork {
-		return errors.New("Cannot specify a Ceph internal network without Ceph storage disks")
-	}
+	if p.Ceph.InternalNetwork != "" {
+		if !containsCephStorage {
+			errs.add("ceph.internal_network", p.Ceph.InternalNetwork, "Cannot be set without Ceph storage disks")
+		}
 
-	if usingCephInternalNetwork {
 		err := validate.IsNetwork(p.Ceph.InternalNetwork)
 		if err != nil {
-			return fmt.Errorf("Invalid Ceph internal network subnet: %v", err)
+			errs.add("ceph.internal_network", p.Ceph.InternalNetwork, "Must be a subnet")
+		}
+	}
+
+	if p.Ceph.Dashboard {
+		if !containsCephStorage {
+			errs.add("ceph.dashboard", true, "Cannot be enabled without Ceph storage disks")
+		}
+
+		if !bootstrap {
+			errs.add("ceph.dashboard", true, "Can only be enabled when setting up a new MicroCloud")
 		}
 	}
 
+	if len(p.Ceph.Tiers) > 0 {
+		if !containsCephStorage {
+			errs.add("ceph.tiers", nil, "Cannot be set without Ceph storage disks")
+		}
+
+		if !bootstrap {
+			errs.add("ceph.tiers", nil, "Can only be set when setting up a new MicroCloud")
+		}
+
+		errs.addErr("ceph.tiers", service.ValidateCephTiers(p.Ceph.Tiers, p.Names.resourceNames()))
+	}
+
+	if p.Ceph.Pools != (CephPoolOptions{}) {
+		if !containsCephStorage {
+			errs.add("ceph.pools", nil, "Cannot be set without Ceph storage disks")
+		}
+
+		if !bootstrap {
+			errs.add("ceph.pools", nil, "Can only be set when setting up a new MicroCloud")
+		}
+
+		errs.addErr("ceph.pools", p.Ceph.Pools.validate())
+	}
+
+	if p.Names.isSet() {
+		if !bootstrap {
+			errs.add("names", nil, "Can only be set when setting up a new MicroCloud")
+		}
+
+		errs.addErr("names", p.Names.resourceNames().Validate())
+	}
+
+	if p.Volumes != (VolumeOptions{}) {
+		if !bootstrap {
+			errs.add("volumes", nil, "Can only be set when setting up a new MicroCloud")
+		}
+
+		errs.addErr("volumes", p.Volumes.validate())
+	}
+
+	for _, conflict := range slices.Sorted(maps.Keys(p.Conflicts)) {
+		resolution := p.Conflicts[conflict]
+		if !slices.Contains(conflictResolutions, resolution) {
+			errs.add("conflicts."+conflict, resolution, "Must be one of %s", strings.Join(conflictResolutions, ", "))
+		}
+	}
+
+	errs.addErr("images", p.Images.validate())
+
+	if !bootstrap && p.Limits != (LimitsOptions{}) {
+		errs.add("limits", nil, "Can only be set when setting up a new MicroCloud")
+	}
+
+	errs.addErr("limits", p.Limits.validate())
+
+	if !bootstrap && p.Snapshots != (SnapshotOptions{}) {
+		errs.add("snapshots", nil, "Can only be set when setting up a new MicroCloud")
+	}
+
+	errs.addErr("snapshots", p.Snapshots.validate())
+
+	if !bootstrap && len(p.Projects) > 0 {
+		errs.add("projects", nil, "Can only be set when setting up a new MicroCloud")
+	}
+
+	projectNames := make([]string, 0, len(p.Projects))
+	for i, project := range p.Projects {
+		path := fmt.Sprintf("projects[%d]", i)
+		if slices.Contains(projectNames, project.Name) {
+			errs.add(path+".name", project.Name, "Project is seeded more than once")
+		}
+
+		projectNames = append(projectNames, project.Name)
+		errs.addErr(path, project.validate())
+	}
+	errs.addErr("tuning", p.Tuning.validate())
+
+	if p.Benchmark && !containsLocalStorage && len(p.Storage.Local) == 0 && !containsCephStorage {
+		errs.add("benchmark", true, "Cannot be enabled without storage disks")
+	}
+
 	if p.OVN.IPv4Gateway == "" && p.OVN.IPv4Range != "" {
-		return errors.New("Cannot specify IPv4 range without IPv4 gateway")
+		errs.add("ovn.ipv4_range", p.OVN.IPv4Range, "Cannot be set without the IPv4 gateway")
 	}
 
 	if p.OVN.IPv4Gateway != "" {
 		_, _, err := net.ParseCIDR(p.OVN.IPv4Gateway)
 		if err != nil {
-			return err
+			errs.add("ovn.ipv4_gateway", p.OVN.IPv4Gateway, "Must be an address in CIDR notation")
 		}
 
 		if p.OVN.IPv4Range == "" {
-			return errors.New("Cannot specify IPv4 range without IPv4 gateway")
-		}
-
-		start, end, ok := strings.Cut(p.OVN.IPv4Range, "-")
-		startIP := net.ParseIP(start)
-		endIP := net.ParseIP(end)
-		if !ok || startIP == nil || endIP == nil {
-			return errors.New("Invalid IPv4 range (must be of the form <ip>-<ip>)")
+			errs.add("ovn.ipv4_range", nil, "Must be set along with the IPv4 gateway")
+		} else {
+			start, end, ok := strings.Cut(p.OVN.IPv4Range, "-")
+			startIP := net.ParseIP(start)
+			endIP := net.ParseIP(end)
+			if !ok || startIP == nil || endIP == nil {
+				errs.add("ovn.ipv4_range", p.OVN.IPv4Range, "Must be of the form <ip>-<ip>")
+			}
 		}
 	}
 
 	if p.OVN.IPv6Gateway != "" {
 		_, _, err := net.ParseCIDR(p.OVN.IPv6Gateway)
 		if err != nil {
-			return err
+			errs.add("ovn.ipv6_gateway", p.OVN.IPv6Gateway, "Must be an address in CIDR notation")
 		}
 	}
 
-	for _, filter := range p.Storage.Ceph {
+	if p.OVN.IPv6Prefix != "" {
+		if p.OVN.IPv6Gateway == "" {
+			errs.add("ovn.ipv6_prefix", p.OVN.IPv6Prefix, "Cannot be set without the IPv6 gateway")
+		}
+
+		err := validate.IsNetworkV6(p.OVN.IPv6Prefix)
+		if err != nil {
+			errs.add("ovn.ipv6_prefix", p.OVN.IPv6Prefix, "Must be an IPv6 subnet")
+		}
+	}
+
+	if p.OVN.DNSSearch != "" {
+		errs.addErr("ovn.dns_search", validate.IsListOf(service.ValidateDNSDomain)(p.OVN.DNSSearch))
+	}
+
+	if p.OVN.Encapsulation != "" {
+		if !bootstrap {
+			errs.add("ovn.encapsulation", p.OVN.Encapsulation, "Can only be set when setting up a new MicroCloud")
+		}
+
+		if !slices.Contains(types.OVNEncapsulations, p.OVN.Encapsulation) {
+			errs.add("ovn.encapsulation", p.OVN.Encapsulation, "Must be one of %s", strings.Join(types.OVNEncapsulations, ", "))
+		}
+	}
+
+	if len(p.OVN.Central) > 0 {
+		if !bootstrap {
+			errs.add("ovn.central", nil, "Can only be set when setting up a new MicroCloud")
+		}
+
+		systems := make([]string, 0, len(p.Systems))
+		for _, system := range p.Systems {
+			systems = append(systems, system.Name)
+		}
+
+		errs.addErr("ovn.central", validateOVNCentral(p.OVN.Central, systems, name))
+	}
+
+	if p.OVN.NAT != (OVNNATOptions{}) {
+		if !bootstrap {
+			errs.add("ovn.nat", nil, "Can only be set when setting up a new MicroCloud")
+		}
+
+		errs.addErr("ovn.nat", p.OVN.NAT.validate(p.OVN))
+	}
+
+	for i, filter := range p.Storage.Ceph {
+		path := fmt.Sprintf("storage.ceph[%d]", i)
 		if filter.Find == "" {
-			return errors.New("Received empty remote disk filter")
+			errs.add(path+".find", nil, "Must be set")
 		}
 
-		if filter.FindMax > 0 {
-			if filter.FindMax < filter.FindMin {
-				return fmt.Errorf("Invalid remote storage filter constraints find_max (%d) must be larger than find_min (%d)", filter.FindMax, filter.FindMin)
-			}
+		if filter.FindMax > 0 && filter.FindMax < filter.FindMin {
+			errs.add(path+".find_max", filter.FindMax, "Must be at least find_min (%d)", filter.FindMin)
 		}
 
 		// For distributed storage, the minimum match count must be defined so that we don't have a default configuration that can be non-HA.
 		if filter.FindMin < 1 {
-			return errors.New("Remote storage filter cannot be defined with find_min less than 1")
+			errs.add(path+".find_min", filter.FindMin, "Must be at least 1 for distributed storage")
 		}
 	}
 
 	for i, filter := range p.Storage.Local {
+		path := fmt.Sprintf("storage.local[%d]", i)
 		if filter.Find == "" {
-			return errors.New("Received empty local disk filter")
+			errs.add(path+".find", nil, "Must be set")
 		}
 
-		if filter.FindMax > 0 {
-			if filter.FindMax < filter.FindMin {
-				return fmt.Errorf("Invalid local storage filter constraints find_max (%d) larger than find_min (%d)", filter.FindMax, filter.FindMin)
-			}
+		if filter.FindMax > 0 && filter.FindMax < filter.FindMin {
+			errs.add(path+".find_max", filter.FindMax, "Must be at least find_min (%d)", filter.FindMin)
 		}
 
 		// For local storage, we can set a default minimum match count because we require at least 1 disk per system.
@@ -517,7 +951,7 @@ func (p *Preseed) validate(name string, bootstrap bool) error {
 		}
 	}
 
-	return nil
+	return errs.err()
 }
 
 // isInitiator returns true if the current host is marked as being the initiator.
@@ -715,6 +1149,16 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 		}
 	}
 
+	err = c.checkClockSkew(context.Background(), s)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.checkReachability(context.Background(), s)
+	if err != nil {
+		return nil, err
+	}
+
 	for peer, system := range c.systems {
 		existingClusters, err := s.GetExistingClusters(context.Background(), system.ServerInfo)
 		if err != nil {
@@ -765,12 +1209,83 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 			ifaceByPeer[cfg.Name] = cfg.UplinkInterface
 		}
 
+		// LXD requires a parent on every member, so the systems with no uplink use an isolated bridge.
+		if cfg.NoUplink {
+			ifaceByPeer[cfg.Name] = service.NoUplinkBridge
+		}
+
 		if cfg.UnderlayIP != "" {
 			ovnUnderlayNeeded = true
 		}
+
+		system, ok := c.systems[cfg.Name]
+		if ok {
+			system.LXDAddress = cfg.LXDAddress
+			system.LXDClusterAddress = cfg.LXDClusterAddress
+			c.systems[cfg.Name] = system
+		}
 	}
 
-	localInfo, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: c.name, Address: c.address})
+	// Resolve the existing storage pools and networks which can't be used by MicroCloud, before looking at the local system.
+	peers := make([]multicast.ServerInfo, 0, len(c.systems))
+	for name, system := range c.systems {
+		if name != c.name {
+			peers = append(peers, system.ServerInfo)
+		}
+	}
+
+	peerStates, err := s.CollectSystemInformationConcurrent(context.Background(), peers, c.collectOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, state := range peerStates {
+		c.state[name] = *state
+	}
+
+	if c.bootstrap {
+		localState, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: c.name, Address: c.address}, c.collectOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		c.state[c.name] = *localState
+	}
+
+	c.warnPlatforms()
+	c.warnSystemDisks()
+
+	err = c.checkCompatibility(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the uplink selectors now that the network cards of each system are known.
+	for _, cfg := range p.Systems {
+		if cfg.UplinkSelector == nil {
+			continue
+		}
+
+		resources, err := c.systemResources(s, cfg.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get system resources of peer %q: %w", cfg.Name, err)
+		}
+
+		iface, err := cfg.UplinkSelector.selectInterface(resources, c.state[cfg.Name].AvailableUplinkInterfaces)
+		if err != nil {
+			return nil, withExitCode(ExitCodeValidation, fmt.Errorf("Failed to select the uplink interface of %q: %w", cfg.Name, err))
+		}
+
+		ifaceByPeer[cfg.Name] = iface
+		fmt.Println(tui.SummarizeResult("Selected uplink interface %s on %s", iface, cfg.Name))
+	}
+
+	_, reused, err := c.resolveConflicts(s, p.Conflicts, conflictAbort)
+	if err != nil {
+		return nil, err
+	}
+
+	localInfo, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: c.name, Address: c.address}, c.collectOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -813,10 +1328,15 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 	if usingOVN {
 		for peer, iface := range ifaceByPeer {
 			system := c.systems[peer]
+			system.NoUplink = iface == service.NoUplinkBridge
 			if c.bootstrap {
 				system.TargetNetworks = append(system.TargetNetworks, lxd.DefaultPendingOVNNetwork(iface))
 				if s.Name == peer {
-					uplink, ovn := lxd.DefaultOVNNetwork(p.OVN.IPv4Gateway, p.OVN.IPv4Range, p.OVN.IPv6Gateway, p.OVN.DNSServers)
+					uplink, ovn, err := c.ovnNAT.networks(lxd.DefaultOVNNetwork(p.OVN.IPv4Gateway, p.OVN.IPv4Range, p.OVN.IPv6Gateway, p.OVN.IPv6Prefix, p.OVN.DNSServers, p.OVN.DNSSearch))
+					if err != nil {
+						return nil, err
+					}
+
 					system.Networks = append(system.Networks, uplink, ovn)
 				}
 			} else {
@@ -921,6 +1441,20 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 			}
 		}
 
+		if directLocal.Path != "" {
+			err := c.checkSystemDiskPath(peer, directLocal.Path)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, disk := range directCeph {
+			err := c.checkSystemDiskPath(peer, disk.Path)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		// Setup directly specified disks for ZFS pool.
 		if directLocal.Path != "" {
 			if c.bootstrap {
@@ -944,6 +1478,14 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 					Encrypt: disk.Encrypt,
 				},
 			)
+
+			if disk.Tier != "" {
+				if system.CephDiskTiers == nil {
+					system.CephDiskTiers = map[string]string{}
+				}
+
+				system.CephDiskTiers[disk.Path] = disk.Tier
+			}
 		}
 
 		// Setup ceph pool for disks specified to MicroCeph.
@@ -976,19 +1518,17 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 
 	allResourcesZFS := map[string]*lxdAPI.Resources{}
 	allResourcesCeph := map[string]*lxdAPI.Resources{}
-	for peer, system := range c.systems {
-		cert := system.ServerInfo.Certificate
-
+	for peer := range c.systems {
 		// Fetch system resources from LXD to find disks if we haven't directly set up disks.
 		if checkFilterZFS[peer] {
-			allResourcesZFS[peer], err = s.Services[types.LXD].(*service.LXDService).GetResources(context.Background(), peer, system.ServerInfo.Address, cert)
+			allResourcesZFS[peer], err = c.systemResources(s, peer)
 			if err != nil {
 				return nil, fmt.Errorf("Failed to get system resources of peer %q: %w", peer, err)
 			}
 		}
 
 		if checkFilterCeph[peer] {
-			allResourcesCeph[peer], err = s.Services[types.LXD].(*service.LXDService).GetResources(context.Background(), peer, system.ServerInfo.Address, cert)
+			allResourcesCeph[peer], err = c.systemResources(s, peer)
 			if err != nil {
 				return nil, fmt.Errorf("Failed to get system resources of peer %q: %w", peer, err)
 			}
@@ -1024,6 +1564,14 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 					},
 				)
 
+				if filter.Tier != "" {
+					if system.CephDiskTiers == nil {
+						system.CephDiskTiers = map[string]string{}
+					}
+
+					system.CephDiskTiers[service.FormatDiskPath(disk)] = filter.Tier
+				}
+
 				// There should only be one ceph pool per system.
 				if !addedCephPool {
 					if c.bootstrap {
@@ -1150,6 +1698,17 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 			}
 		}
 
+		// The Ceph networks default to the lookup subnet, which systems on routed subnets have no address in.
+		routed := c.routedSystems()
+		cephDisks := false
+		for _, system := range c.systems {
+			cephDisks = cephDisks || len(system.MicroCephDisks) > 0
+		}
+
+		if len(routed) > 0 && cephDisks && initializedMicroCephSystem == nil && (p.Ceph.InternalNetwork == "" || p.Ceph.PublicNetwork == "") {
+			return nil, withExitCode(ExitCodeValidation, fmt.Errorf("Systems %s are outside of %s, so %q and %q must be set to subnets that all systems have an address in", strings.Join(routed, ", "), c.lookupSubnet.String(), "ceph.internal_network", "ceph.public_network"))
+		}
+
 		var internalCephNetwork string
 		if customTargetCephInternalNetwork == "" {
 			internalCephNetwork = p.Ceph.InternalNetwork
@@ -1263,7 +1822,7 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 		for name, system := range c.systems {
 			found := false
 			for _, pool := range system.TargetStoragePools {
-				if pool.Name == service.DefaultCephPool {
+				if pool.Name == lxd.ResourceNames().RemotePool {
 					found = true
 				}
 			}
@@ -1274,7 +1833,7 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 
 			found = false
 			for _, pool := range system.StoragePools {
-				if pool.Name == service.DefaultCephPool {
+				if pool.Name == lxd.ResourceNames().RemotePool {
 					found = true
 				}
 			}
@@ -1285,7 +1844,7 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 
 			found = false
 			for _, config := range system.JoinConfig {
-				if config.Name == service.DefaultCephPool {
+				if config.Name == lxd.ResourceNames().RemotePool {
 					found = true
 				}
 			}
@@ -1316,6 +1875,15 @@ func (p *Preseed) Parse(s *service.Handler, c *initConfig, installedServices map
 		}
 	}
 
+	// The storage pools of the performance tiers are set up along with the remote storage pool.
+	err = validateCephTierDisks(c.cephTiers, c.systems)
+	if err != nil {
+		return nil, err
+	}
+
+	c.addCephTierPools(lxd)
+	dropReusedResources(c.systems, reused)
+
 	return c.systems, nil
 }
 
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PreseedError is a constraint of the preseed violated by the value at a YAML path.
+type PreseedError struct {
+	// Path is the YAML path of the value, such as systems[2].storage.ceph[0].path.
+	Path string
+
+	// Value is the offending value, or nil if the value is missing or described by the constraint.
+	Value any
+
+	// Constraint is the violated constraint.
+	Constraint string
+}
+
+// Error returns the path, constraint and value of the error.
+func (e PreseedError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("%s: %s", e.Path, e.Constraint)
+	}
+
+	return fmt.Sprintf("%s: %s (got %#v)", e.Path, e.Constraint, e.Value)
+}
+
+// PreseedErrors are all the constraints violated by a preseed, in the order they were found.
+type PreseedErrors []PreseedError
+
+// Error returns the errors, one per line.
+func (e PreseedErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d errors in the preseed:", len(e))
+	for _, err := range e {
+		b.WriteString("\n  - " + err.Error())
+	}
+
+	return b.String()
+}
+
+// add records that the value at the given path violates the constraint.
+func (e *PreseedErrors) add(path string, value any, constraint string, args ...any) {
+	*e = append(*e, PreseedError{Path: path, Value: value, Constraint: fmt.Sprintf(constraint, args...)})
+}
+
+// addErr records the error of a validator as a constraint violated by the value at the given path, if not nil.
+// The error of the validator is expected to describe the offending value.
+func (e *PreseedErrors) addErr(path string, err error) {
+	if err != nil {
+		*e = append(*e, PreseedError{Path: path, Constraint: err.Error()})
+	}
+}
+
+// err returns the errors, or nil if no constraint is violated.
+func (e PreseedErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
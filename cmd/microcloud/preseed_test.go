@@ -7,6 +7,8 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/units"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/service"
 )
 
 type preseedSuite struct {
@@ -23,7 +25,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 		preseed Preseed
 
 		addErr bool
-		err    error
+		err    *PreseedError
 	}{
 		{
 			desc: "No systems",
@@ -36,7 +38,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("No systems given"),
+			err:    &PreseedError{Path: "systems", Constraint: "Must contain at least one system"},
 		},
 		{
 			desc: "Duplicate systems",
@@ -47,7 +49,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems:           []System{{Name: "n1"}, {Name: "n1"}},
 			},
 			addErr: true,
-			err:    errors.New(`Duplicate system name "n1"`),
+			err:    &PreseedError{Path: "systems[1].name", Value: "n1", Constraint: "Must be unique among the systems"},
 		},
 		{
 			desc: "Single node preseed",
@@ -72,7 +74,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems:      []System{{Name: "n1"}, {Name: "n2"}},
 			},
 			addErr: true,
-			err:    errors.New(`Missing session passphrase`),
+			err:    &PreseedError{Path: "session_passphrase", Constraint: "Must be set when there is more than one system"},
 		},
 		{
 			desc: "Missing initiator's name or address",
@@ -80,7 +82,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems: []System{{Name: "n1"}},
 			},
 			addErr: true,
-			err:    errors.New(`Missing initiator's name or address`),
+			err:    &PreseedError{Path: "initiator", Constraint: "Either the initiator's name or address must be set"},
 		},
 		{
 			desc: "Cannot provide both the initiator's name and address",
@@ -90,7 +92,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems:          []System{{Name: "n1"}},
 			},
 			addErr: true,
-			err:    errors.New(`Cannot provide both the initiator's name and address`),
+			err:    &PreseedError{Path: "initiator_address", Value: "1.0.0.1", Constraint: "Cannot be set along with the initiator's name"},
 		},
 		{
 			desc: "Cannot provide both the initiator's address and lookup subnet",
@@ -100,7 +102,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems:          []System{{Name: "n1"}},
 			},
 			addErr: true,
-			err:    errors.New(`Cannot provide both the initiator's address and lookup subnet`),
+			err:    &PreseedError{Path: "lookup_subnet", Value: "1.0.0.0/24", Constraint: "Cannot be set along with the initiator's address"},
 		},
 		{
 			desc: "Cannot provide both system address and lookup subnet",
@@ -110,7 +112,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems:      []System{{Name: "n1", Address: "1.0.0.1"}},
 			},
 			addErr: true,
-			err:    errors.New(`Cannot provide both the address for system "n1" and the lookup subnet`),
+			err:    &PreseedError{Path: "systems[0].address", Value: "1.0.0.1", Constraint: "Cannot be set along with the lookup subnet"},
 		},
 		{
 			desc: "Missing initiator address if one system has an address",
@@ -118,7 +120,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems: []System{{Name: "n1", Address: "1.0.0.1"}},
 			},
 			addErr: true,
-			err:    errors.New("Missing initiator's name or address"),
+			err:    &PreseedError{Path: "systems[0].address", Value: "1.0.0.1", Constraint: "Cannot be set without the initiator's address"},
 		},
 		{
 			desc: "Missing listen address",
@@ -129,7 +131,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Storage:           StorageFilter{},
 			},
 			addErr: true,
-			err:    errors.New(`Missing address for system "n1" when the initiator's address is set`),
+			err:    &PreseedError{Path: "systems[0].address", Constraint: "Must be set when the initiator's address is set"},
 		},
 		{
 			desc: "Systems missing name",
@@ -144,7 +146,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Missing system name"),
+			err:    &PreseedError{Path: "systems[0].name", Constraint: "Must be set"},
 		},
 		{
 			desc: "FindMin too low for ceph filter",
@@ -158,7 +160,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Remote storage filter cannot be defined with find_min less than 1"),
+			err:    &PreseedError{Path: "storage.ceph[0].find_min", Value: 0, Constraint: "Must be at least 1 for distributed storage"},
 		},
 		{
 			desc: "Ceph direct selection (3) with more systems (4)",
@@ -196,7 +198,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				Systems:           []System{{Name: "n1", Address: "1.0.0.1", Storage: InitStorage{Local: DirectStorage{Path: "def"}}}, {Name: "n2", Address: "1.0.0.2", Storage: InitStorage{Local: DirectStorage{Path: "def"}}}, {Name: "n3", Address: "1.0.0.3"}},
 			},
 			addErr: true,
-			err:    errors.New("Some systems are missing local storage disks"),
+			err:    &PreseedError{Path: "systems[2].storage.local.path", Constraint: "Must be set when other systems have a local disk, unless local disk filters are set"},
 		},
 		{
 			desc: "Invalid zfs filter constraint",
@@ -209,7 +211,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Invalid local storage filter constraints find_max (2) larger than find_min (3)"),
+			err:    &PreseedError{Path: "storage.local[0].find_max", Value: 2, Constraint: "Must be at least find_min (3)"},
 		},
 		{
 			desc: "Invalid zfs filter value",
@@ -222,7 +224,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Received empty local disk filter"),
+			err:    &PreseedError{Path: "storage.local[0].find", Constraint: "Must be set"},
 		},
 		{
 			desc: "Invalid ceph filter min > max",
@@ -235,7 +237,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Invalid remote storage filter constraints find_max (3) must be larger than find_min (4)"),
+			err:    &PreseedError{Path: "storage.ceph[0].find_max", Value: 3, Constraint: "Must be at least find_min (4)"},
 		},
 		{
 			desc: "Invalid ceph filter constraints",
@@ -248,7 +250,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Received empty remote disk filter"),
+			err:    &PreseedError{Path: "storage.ceph[0].find", Constraint: "Must be set"},
 		},
 		{
 			desc: "Systems missing interface",
@@ -263,7 +265,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Some systems are missing an uplink interface"),
+			err:    &PreseedError{Path: "systems[0].ovn_uplink_interface", Constraint: "Must be set when other systems have an uplink interface, unless the system is set up with no uplink"},
 		},
 		{
 			desc: "OVN IPv4 Ranges with no gateway",
@@ -278,7 +280,7 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Cannot specify IPv4 range without IPv4 gateway"),
+			err:    &PreseedError{Path: "ovn.ipv4_range", Value: "10.0.0.100-10.0.0.254", Constraint: "Cannot be set without the IPv4 gateway"},
 		},
 		{
 			desc: "Invalid OVN IPv4 Ranges",
@@ -293,35 +295,311 @@ func (s *preseedSuite) Test_preseedValidateInvalid() {
 				},
 			},
 			addErr: true,
-			err:    errors.New("Invalid IPv4 range (must be of the form <ip>-<ip>)"),
+			err:    &PreseedError{Path: "ovn.ipv4_range", Value: "10.0.0.100,10.0.0.254", Constraint: "Must be of the form <ip>-<ip>"},
+		},
+		{
+			desc: "OVN IPv6 prefix with no gateway",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", UplinkInterface: "eth0"}, {Name: "n3", Address: "1.0.0.3", UplinkInterface: "eth0"}},
+				OVN:               InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254", IPv6Prefix: "cafe:1::/56"},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "ovn.ipv6_prefix", Value: "cafe:1::/56", Constraint: "Cannot be set without the IPv6 gateway"},
+		},
+		{
+			desc: "Invalid OVN IPv6 prefix",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", UplinkInterface: "eth0"}, {Name: "n3", Address: "1.0.0.3", UplinkInterface: "eth0"}},
+				OVN:               InitNetwork{IPv6Gateway: "cafe::1/64", IPv6Prefix: "10.0.0.0/24"},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "ovn.ipv6_prefix", Value: "10.0.0.0/24", Constraint: "Must be an IPv6 subnet"},
+		},
+		{
+			desc: "Invalid OVN DNS search domains",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", UplinkInterface: "eth0"}, {Name: "n3", Address: "1.0.0.3", UplinkInterface: "eth0"}},
+				OVN:               InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254", DNSSearch: "example.com,in valid"},
+				Storage: StorageFilter{
+					Local: []DiskFilter{{Find: "abc", FindMin: 0, FindMax: 3, Wipe: false}},
+					Ceph:  []DiskFilter{{Find: "def", FindMin: 3, FindMax: 3, Wipe: false}},
+				},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "ovn.dns_search", Constraint: `Item "in valid": Invalid label "in valid": Name can only contain alphanumeric and hyphen characters`},
+		},
+		{
+			desc: "Ceph dashboard without Ceph storage",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Storage:           StorageFilter{Local: []DiskFilter{{Find: "abc", FindMin: 0, FindMax: 3}}},
+				Ceph:              CephOptions{Dashboard: true},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "ceph.dashboard", Value: true, Constraint: "Cannot be enabled without Ceph storage disks"},
+		},
+		{
+			desc: "Image remote overriding a built-in remote",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Images:            ImageOptions{Remotes: []ImageRemote{{Name: "images", URL: "https://mirror.example.com"}}},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "images", Constraint: `Image remote "images" is built into LXD and cannot be overridden`},
+		},
+		{
+			desc: "Image remote with unsupported protocol",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Images:            ImageOptions{Remotes: []ImageRemote{{Name: "mirror", URL: "https://mirror.example.com", Protocol: "oci"}}},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "images", Constraint: `Invalid protocol of image remote "mirror": Invalid value "oci" (not one of [simplestreams lxd])`},
+		},
+		{
+			desc: "Project CPU limit without default instance CPU limit",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Limits:            LimitsOptions{Project: ProjectLimits{CPU: "64"}},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "limits", Constraint: "A project CPU limit requires a default instance CPU limit"},
+		},
+		{
+			desc: "Invalid project memory limit",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Limits:            LimitsOptions{Project: ProjectLimits{Memory: "lots"}, Instance: InstanceLimits{Memory: "4GiB"}},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "limits", Constraint: "Invalid project memory limit: Invalid value: lots"},
+		},
+		{
+			desc: "Wildcard LXD cluster address",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1", LXDClusterAddress: "0.0.0.0"}, {Name: "n2", Address: "1.0.0.2"}},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "systems[0].lxd_cluster_address", Value: "0.0.0.0", Constraint: "Must be an IP address, with an optional port"},
+		},
+		{
+			desc: "Project seeded twice",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Projects:          []ProjectOptions{{Name: "tenant1"}, {Name: "tenant1"}},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "projects[1].name", Value: "tenant1", Constraint: "Project is seeded more than once"},
+		},
+		{
+			desc: "Storage benchmark without storage disks",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Benchmark:         true,
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "benchmark", Value: true, Constraint: "Cannot be enabled without storage disks"},
+		},
+		{
+			desc: "Invalid conflict resolution",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Conflicts:         map[string]string{"local": "delete"},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "conflicts.local", Value: "delete", Constraint: "Must be one of rename, reuse, abort"},
+		},
+		{
+			desc: "Performance tiers without Ceph storage disks",
+			preseed: Preseed{
+				SessionPassphrase: "foo",
+				InitiatorAddress:  "1.0.0.1",
+				Systems:           []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}},
+				Ceph:              CephOptions{Tiers: []service.CephTier{{Name: "remote-fast", DeviceClass: "nvme"}}},
+			},
+			addErr: true,
+			err:    &PreseedError{Path: "ceph.tiers", Constraint: "Cannot be set without Ceph storage disks"},
 		},
 	}
 
 	s.T().Log("Preseed init missing local system")
 	p := Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "B", Address: "1.0.0.1"}, {Name: "C", Address: "1.0.0.2"}}}
 	err := p.validate("A", true)
-	s.EqualError(err, "Local MicroCloud must be included in the list of systems when initializing")
+	s.EqualError(err, `systems: Must include the local system "A" when setting up a new MicroCloud`)
+
+	s.T().Log("Preseed with custom storage pool and network names")
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}}, Names: NamesOptions{LocalPool: "fast", FanNetwork: "fan0"}}
+	s.NoError(p.validate("n1", true))
+	s.EqualError(p.validate("n0", false), "names: Can only be set when setting up a new MicroCloud")
+
+	p.Names.RemotePool = "fast"
+	s.EqualError(p.validate("n1", true), `names: Name "fast" is used more than once`)
+
+	s.T().Log("Preseed with performance tiers")
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}}, Storage: StorageFilter{Ceph: []DiskFilter{{Find: "type == nvme", FindMin: 1, Tier: "remote-fast"}}}}
+	p.Ceph.Tiers = []service.CephTier{{Name: "remote-fast", DeviceClass: "nvme"}}
+	s.NoError(p.validate("n1", true))
+	s.EqualError(p.validate("n0", false), "ceph.tiers: Can only be set when setting up a new MicroCloud")
+
+	p.Ceph.Tiers = append(p.Ceph.Tiers, service.CephTier{Name: "remote", DeviceClass: "hdd"})
+	s.EqualError(p.validate("n1", true), `ceph.tiers: Name "remote" is used more than once`)
+
+	s.T().Log("Preseed with Ceph pool options")
+	p.Ceph = CephOptions{Pools: CephPoolOptions{CompressionMode: "aggressive", CompressionAlgorithm: "zstd", TargetSizeRatio: 0.5}}
+	s.NoError(p.validate("n1", true))
+	s.EqualError(p.validate("n0", false), "ceph.pools: Can only be set when setting up a new MicroCloud")
+
+	p.Ceph.Pools.CompressionMode = "always"
+	s.EqualError(p.validate("n1", true), `ceph.pools: Invalid Ceph compression mode: Invalid value "always" (not one of [none passive aggressive force])`)
+
+	s.T().Log("Preseed with OVN encapsulation")
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", UplinkInterface: "eth0"}}}
+	p.OVN = InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254", Encapsulation: "vxlan"}
+	s.NoError(p.validate("n1", true))
+	s.EqualError(p.validate("n0", false), `ovn.encapsulation: Can only be set when setting up a new MicroCloud (got "vxlan")`)
+
+	p.OVN.Encapsulation = "gre"
+	s.EqualError(p.validate("n1", true), `ovn.encapsulation: Must be one of geneve, vxlan (got "gre")`)
+
+	s.T().Log("Preseed with OVN NAT policy")
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", UplinkInterface: "eth0"}}}
+	p.OVN = InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254", NAT: OVNNATOptions{IPv4Address: "10.0.0.99"}}
+	s.NoError(p.validate("n1", true))
+	s.EqualError(p.validate("n0", false), "ovn.nat: Can only be set when setting up a new MicroCloud")
+
+	p.OVN.NAT.IPv6Routed = true
+	s.EqualError(p.validate("n1", true), "ovn.nat: Cannot route IPv6 without IPv6 prefix")
+
+	s.T().Log("Preseed with OVN central systems")
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", UplinkInterface: "eth0"}, {Name: "n3", Address: "1.0.0.3", UplinkInterface: "eth0"}, {Name: "n4", Address: "1.0.0.4", UplinkInterface: "eth0"}}}
+	p.OVN = InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254", Central: []string{"n1", "n2", "n3"}}
+	s.NoError(p.validate("n1", true))
+	s.EqualError(p.validate("n0", false), "ovn.central: Can only be set when setting up a new MicroCloud")
+
+	p.OVN.Central = []string{"n1", "n2"}
+	s.EqualError(p.validate("n1", true), "ovn.central: The OVN central services must run on at least 3 systems, 2 selected")
+
+	p.OVN.Central = []string{"n2", "n3", "n4"}
+	s.EqualError(p.validate("n1", true), `ovn.central: The OVN central services must run on "n1", which sets up MicroOVN`)
+
+	p.OVN.Central = []string{"n1", "n2", "n5"}
+	s.EqualError(p.validate("n1", true), `ovn.central: Unknown system "n5"`)
+
+	s.T().Log("Preseed with a system with no uplink")
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", NoUplink: true}}}
+	p.OVN = InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254"}
+	s.NoError(p.validate("n1", true))
+	s.NoError(p.validate("n0", false))
+
+	p.Systems[1].UplinkInterface = "eth0"
+	s.EqualError(p.validate("n1", true), `systems[1].ovn_uplink_interface: Cannot be set when the system is set up with no uplink (got "eth0")`)
+
+	p.Systems = []System{{Name: "n1", Address: "1.0.0.1", NoUplink: true}, {Name: "n2", Address: "1.0.0.2", NoUplink: true}}
+	s.EqualError(p.validate("n1", true), "systems: At least one system must have an uplink interface when others are set up with no uplink")
+
+	s.T().Log("Preseed with an uplink selector")
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1", UplinkInterface: "eth0"}, {Name: "n2", Address: "1.0.0.2", UplinkSelector: &InterfaceSelector{MinSpeed: 10000, Fastest: true}}}}
+	p.OVN = InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254"}
+	s.NoError(p.validate("n1", true))
+
+	p.Systems[1].UplinkInterface = "eth0"
+	s.EqualError(p.validate("n1", true), "systems[1].ovn_uplink_selector: Cannot be set along with the uplink interface")
+
+	p.Systems[1].UplinkInterface = ""
+	p.Systems[1].UplinkSelector = &InterfaceSelector{}
+	s.EqualError(p.validate("n1", true), "systems[1].ovn_uplink_selector: Must set at least one of mac, pci_address, driver, min_speed or fastest")
+
+	p.Systems[1].UplinkSelector = nil
+	s.EqualError(p.validate("n1", true), "systems[1].ovn_uplink_interface: Must be set when other systems have an uplink interface, unless the system is set up with no uplink")
+
+	s.T().Log("Preseed leaving out optional services")
+	disabled := false
+	p = Preseed{SessionPassphrase: "foo", InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "n1", Address: "1.0.0.1"}, {Name: "n2", Address: "1.0.0.2"}}}
+	p.Services = ServiceOptions{MicroCeph: &disabled, MicroOVN: &disabled}
+	s.NoError(p.validate("n1", true))
+	s.Equal([]string{"MicroCeph", "MicroOVN"}, p.Services.excluded())
+
+	p.Storage.Ceph = []DiskFilter{{Find: "size > 1GiB"}}
+	s.EqualError(p.validate("n1", true), "Found 2 errors in the preseed:\n"+
+		"  - services.microceph: Cannot be disabled when Ceph storage disks are set up (got false)\n"+
+		"  - storage.ceph[0].find_min: Must be at least 1 for distributed storage (got 0)")
+
+	p.Storage.Ceph = nil
+	p.Systems[0].UplinkInterface = "eth0"
+	p.Systems[1].UplinkInterface = "eth0"
+	p.OVN = InitNetwork{IPv4Gateway: "10.0.0.1/24", IPv4Range: "10.0.0.100-10.0.0.254"}
+	s.EqualError(p.validate("n1", true), "services.microovn: Cannot be disabled when OVN uplink or underlay interfaces are set up (got false)")
 
 	for _, c := range cases {
 		s.T().Log(c.desc)
 
+		var errs PreseedErrors
 		err := c.preseed.validate("n1", true)
 		if c.err == nil {
 			s.NoError(err)
-		} else {
-			s.EqualError(err, c.err.Error())
+		} else if s.ErrorAs(err, &errs) {
+			s.Contains(errs, *c.err)
 		}
 
 		s.T().Logf("%s in add mode", c.desc)
 		err = c.preseed.validate("n0", false)
-		if c.addErr {
-			s.EqualError(err, c.err.Error())
-		} else {
+		if !c.addErr {
 			s.NoError(err)
+		} else if s.ErrorAs(err, &errs) {
+			s.Contains(errs, *c.err)
 		}
 	}
 }
 
+func (s *preseedSuite) Test_preseedValidateAllErrors() {
+	p := Preseed{
+		InitiatorAddress: "1.0.0.1",
+		Systems: []System{
+			{Name: "n1", Address: "1.0.0.1"},
+			{Name: "n2", Address: "1.0.0.2"},
+			{Name: "n3", Address: "1.0.0.3", Storage: InitStorage{Ceph: []DirectStorage{{Path: ""}}}},
+		},
+		OVN: InitNetwork{IPv4Range: "10.0.0.100-10.0.0.254"},
+	}
+
+	var errs PreseedErrors
+	s.ErrorAs(p.validate("n1", true), &errs)
+	s.Equal(PreseedErrors{
+		{Path: "session_passphrase", Constraint: "Must be set when there is more than one system"},
+		{Path: "systems[2].storage.ceph[0].path", Constraint: "Must be set"},
+		{Path: "ovn.ipv4_range", Value: "10.0.0.100-10.0.0.254", Constraint: "Cannot be set without the IPv4 gateway"},
+	}, errs)
+
+	s.Equal("Found 3 errors in the preseed:\n"+
+		"  - session_passphrase: Must be set when there is more than one system\n"+
+		"  - systems[2].storage.ceph[0].path: Must be set\n"+
+		`  - ovn.ipv4_range: Cannot be set without the IPv4 gateway (got "10.0.0.100-10.0.0.254")`, errs.Error())
+}
+
 func (s *preseedSuite) Test_preseedMatchDisksMemory() {
 	unit1, err := units.ParseByteSizeString("1MiB")
 	s.NoError(err)
@@ -415,6 +693,101 @@ func (s *preseedSuite) Test_isBootstrap() {
 	}
 }
 
+func (s *preseedSuite) Test_validateAddPreseed() {
+	cases := []struct {
+		desc      string
+		preseed   Preseed
+		name      string
+		address   string
+		expectErr bool
+	}{
+		{
+			desc:    "Run on the initiator named in the preseed",
+			preseed: Preseed{Initiator: "A", Systems: []System{{Name: "B"}, {Name: "C"}}},
+			name:    "A",
+			address: "1.0.0.1",
+		},
+		{
+			desc:    "Run on the initiator with the address given in the preseed",
+			preseed: Preseed{InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "B", Address: "1.0.0.2"}}},
+			name:    "A",
+			address: "1.0.0.1",
+		},
+		{
+			desc:      "Bootstrap preseed listing the initiator",
+			preseed:   Preseed{Initiator: "A", Systems: []System{{Name: "A"}, {Name: "B"}}},
+			name:      "A",
+			address:   "1.0.0.1",
+			expectErr: true,
+		},
+		{
+			desc:      "Run on another cluster member",
+			preseed:   Preseed{Initiator: "A", Systems: []System{{Name: "C"}}},
+			name:      "B",
+			address:   "1.0.0.2",
+			expectErr: true,
+		},
+		{
+			desc:      "Run on one of the new systems",
+			preseed:   Preseed{InitiatorAddress: "1.0.0.1", Systems: []System{{Name: "C", Address: "1.0.0.3"}}},
+			name:      "C",
+			address:   "1.0.0.3",
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := validateAddPreseed(c.preseed, c.name, c.address)
+		if c.expectErr {
+			s.Error(err)
+			s.Equal(ExitCodeValidation, exitCode(err))
+			continue
+		}
+
+		s.NoError(err)
+	}
+}
+
+func (s *preseedSuite) Test_withoutClusteredSystems() {
+	preseed := Preseed{
+		Initiator: "A",
+		Systems:   []System{{Name: "A"}, {Name: "B", Address: "1.0.0.2"}, {Name: "C"}},
+		Limits:    LimitsOptions{Project: ProjectLimits{Instances: "10"}},
+		Ceph:      CephOptions{Dashboard: true, CephFS: true},
+	}
+
+	s.T().Log("Nothing is skipped before the initiator is set up")
+	pending, skipped := preseed.withoutClusteredSystems(map[string]string{})
+	s.Empty(skipped)
+	s.Equal(preseed, pending)
+
+	s.T().Log("Systems are matched by name or address, and the bootstrap options are dropped with the initiator")
+	pending, skipped = preseed.withoutClusteredSystems(map[string]string{"A": "1.0.0.1", "micro02": "1.0.0.2"})
+	s.Equal([]string{"A", "B"}, skipped)
+	s.Equal([]System{{Name: "C"}}, pending.Systems)
+	s.False(pending.isBootstrap())
+	s.Equal(LimitsOptions{}, pending.Limits)
+	s.False(pending.Ceph.Dashboard)
+	s.True(pending.Ceph.CephFS)
+	s.Len(preseed.Systems, 3, "The original preseed must not be changed")
+
+	s.T().Log("Preseeds adding systems keep their options")
+	preseed = Preseed{Initiator: "A", Systems: []System{{Name: "B"}, {Name: "C"}}, Benchmark: true}
+	pending, skipped = preseed.withoutClusteredSystems(map[string]string{"A": "1.0.0.1", "B": "1.0.0.2"})
+	s.Equal([]string{"B"}, skipped)
+	s.Equal([]System{{Name: "C"}}, pending.Systems)
+	s.True(pending.Benchmark)
+}
+
+func (s *preseedSuite) Test_initiatorIn() {
+	s.True((&Preseed{Initiator: "A"}).initiatorIn(map[string]string{"A": "1.0.0.1"}))
+	s.True((&Preseed{InitiatorAddress: "1.0.0.1"}).initiatorIn(map[string]string{"A": "1.0.0.1"}))
+	s.False((&Preseed{Initiator: "B"}).initiatorIn(map[string]string{"A": "1.0.0.1"}))
+	s.False((&Preseed{Initiator: "A"}).initiatorIn(map[string]string{}))
+}
+
 func (s *preseedSuite) Test_address() {
 	cases := []struct {
 		desc    string
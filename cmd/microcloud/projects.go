@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ProjectOptions represents an LXD project seeded for a tenant in the preseed yaml.
+type ProjectOptions struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+
+	// Network is the OVN network of the project, created from the uplink of the default OVN network.
+	Network ProjectNetwork `yaml:"network"`
+
+	// StoragePools are the storage pools the instances of the project may use, all of them if empty.
+	StoragePools []string `yaml:"storage_pools"`
+
+	Limits   ProjectLimits  `yaml:"limits"`
+	Instance InstanceLimits `yaml:"instance"`
+}
+
+// ProjectNetwork represents the OVN network of a seeded project in the preseed yaml.
+type ProjectNetwork struct {
+	Name        string `yaml:"name"`
+	IPv4Address string `yaml:"ipv4_address"`
+	IPv6Address string `yaml:"ipv6_address"`
+}
+
+// networkName returns the name of the OVN network of the project.
+func (p ProjectOptions) networkName() string {
+	if p.Network.Name == "" {
+		return "default"
+	}
+
+	return p.Network.Name
+}
+
+// validate validates the config of a seeded project.
+func (p ProjectOptions) validate() error {
+	if p.Name == "" {
+		return errors.New("Project name is required")
+	}
+
+	err := validate.IsHostname(p.Name)
+	if err != nil {
+		return fmt.Errorf("Invalid project name %q: %w", p.Name, err)
+	}
+
+	if p.Name == "default" {
+		return errors.New(`Project "default" is set up by MicroCloud and cannot be seeded`)
+	}
+
+	err = validate.Optional(validate.IsInterfaceName)(p.Network.Name)
+	if err != nil {
+		return fmt.Errorf("Invalid network name of project %q: %w", p.Name, err)
+	}
+
+	err = validate.Optional(validate.IsNetworkAddressCIDRV4)(p.Network.IPv4Address)
+	if err != nil {
+		return fmt.Errorf("Invalid IPv4 address of project %q: %w", p.Name, err)
+	}
+
+	err = validate.Optional(validate.IsNetworkAddressCIDRV6)(p.Network.IPv6Address)
+	if err != nil {
+		return fmt.Errorf("Invalid IPv6 address of project %q: %w", p.Name, err)
+	}
+
+	for _, pool := range p.StoragePools {
+		err = service.ValidateStoragePoolName(pool)
+		if err != nil {
+			return fmt.Errorf("Invalid storage pool name %q of project %q: %w", pool, p.Name, err)
+		}
+	}
+
+	err = LimitsOptions{Project: p.Limits, Instance: p.Instance}.validate()
+	if err != nil {
+		return fmt.Errorf("Invalid limits of project %q: %w", p.Name, err)
+	}
+
+	return nil
+}
+
+// projectConfig returns the configuration of the seeded project.
+// The project gets its own networks and profiles, and is restricted to the given uplink and storage pools.
+// Without an uplink, the project has no network of its own and uses the networks of the default project.
+func (p ProjectOptions) projectConfig(uplink string, pools []string) map[string]string {
+	config := LimitsOptions{Project: p.Limits}.projectConfig()
+	config["features.profiles"] = "true"
+	config["features.images"] = "false"
+	config["restricted"] = "true"
+	if uplink != "" {
+		config["features.networks"] = "true"
+		config["restricted.networks.uplinks"] = uplink
+		config["restricted.networks.access"] = p.networkName()
+	} else {
+		config["features.networks"] = "false"
+	}
+
+	// Storage pools the project may not use get a disk limit of zero.
+	if len(p.StoragePools) > 0 {
+		for _, pool := range pools {
+			if !slices.Contains(p.StoragePools, pool) {
+				config["limits.disk.pool."+pool] = "0"
+			}
+		}
+	}
+
+	return config
+}
+
+// network returns the OVN network of the seeded project on the given uplink.
+func (p ProjectOptions) network(uplink string) lxdAPI.NetworksPost {
+	return lxdAPI.NetworksPost{
+		NetworkPut: lxdAPI.NetworkPut{
+			Config:      nonEmptyConfig(map[string]string{"network": uplink, "ipv4.address": p.Network.IPv4Address, "ipv6.address": p.Network.IPv6Address}),
+			Description: fmt.Sprintf("OVN network of project %q", p.Name),
+		},
+		Name: p.networkName(),
+		Type: "ovn",
+	}
+}
+
+// profile returns the default profile of the seeded project, based on the default profile of the default project.
+// The root disk stays on the storage pool of the default profile if the project may use it, or moves to the first allowed pool.
+func (p ProjectOptions) profile(defaultProfile lxdAPI.ProfilePut, uplink string) lxdAPI.ProfilePut {
+	profile := lxdAPI.ProfilePut{
+		Config:      maps.Clone(defaultProfile.Config),
+		Description: fmt.Sprintf("Default profile of project %q", p.Name),
+		Devices:     map[string]map[string]string{},
+	}
+
+	if profile.Config == nil {
+		profile.Config = map[string]string{}
+	}
+
+	maps.Copy(profile.Config, LimitsOptions{Instance: p.Instance}.profileConfig())
+
+	root := defaultProfile.Devices["root"]
+	if root != nil {
+		root = maps.Clone(root)
+		if len(p.StoragePools) > 0 && !slices.Contains(p.StoragePools, root["pool"]) {
+			root["pool"] = p.StoragePools[0]
+		}
+
+		profile.Devices["root"] = root
+	}
+
+	eth0 := defaultProfile.Devices["eth0"]
+	if uplink != "" {
+		profile.Devices["eth0"] = map[string]string{"name": "eth0", "network": p.networkName(), "type": "nic"}
+	} else if eth0 != nil {
+		profile.Devices["eth0"] = maps.Clone(eth0)
+	}
+
+	return profile
+}
+
+// setupProjects creates the seeded projects, each with its own OVN network and default profile.
+// The projects are removed again if setting up the cluster fails.
+func (c *initConfig) setupProjects(lxdClient lxd.InstanceServer, system InitSystem, uplink string, defaultProfile lxdAPI.ProfilePut, reverter *revert.Reverter) error {
+	if len(c.projects) == 0 {
+		return nil
+	}
+
+	hasUplink := slices.ContainsFunc(system.Networks, func(network lxdAPI.NetworksPost) bool { return network.Name == uplink })
+	if !hasUplink {
+		uplink = ""
+	}
+
+	pools, err := lxdClient.GetStoragePoolNames()
+	if err != nil {
+		return fmt.Errorf("Failed to get the storage pools: %w", err)
+	}
+
+	for _, project := range c.projects {
+		for _, pool := range project.StoragePools {
+			if !slices.Contains(pools, pool) {
+				return fmt.Errorf("Storage pool %q of project %q does not exist, available pools are %s", pool, project.Name, strings.Join(pools, ", "))
+			}
+		}
+
+		if uplink == "" && project.Network != (ProjectNetwork{}) {
+			return fmt.Errorf("Project %q has a network, but no OVN uplink network is set up", project.Name)
+		}
+
+		err = lxdClient.CreateProject(lxdAPI.ProjectsPost{
+			Name:       project.Name,
+			ProjectPut: lxdAPI.ProjectPut{Description: project.Description, Config: project.projectConfig(uplink, pools)},
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to create project %q: %w", project.Name, err)
+		}
+
+		reverter.Add(func() {
+			_ = lxdClient.DeleteProject(project.Name, true)
+		})
+
+		projectClient := lxdClient.UseProject(project.Name)
+
+		if uplink != "" {
+			err = projectClient.CreateNetwork(project.network(uplink))
+			if err != nil {
+				return fmt.Errorf("Failed to create network of project %q: %w", project.Name, err)
+			}
+		}
+
+		op, err := projectClient.UpdateProfile("default", project.profile(defaultProfile, uplink), "")
+		if err != nil {
+			return fmt.Errorf("Failed to update the default profile of project %q: %w", project.Name, err)
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed to wait for the default profile of project %q to update: %w", project.Name, err)
+		}
+	}
+
+	return nil
+}
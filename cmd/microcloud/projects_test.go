@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type projectsSuite struct {
+	suite.Suite
+}
+
+func TestProjectsSuite(t *testing.T) {
+	suite.Run(t, new(projectsSuite))
+}
+
+func (s *projectsSuite) Test_validate() {
+	cases := []struct {
+		desc    string
+		project ProjectOptions
+		err     string
+	}{
+		{
+			desc:    "Project with network, pools and limits",
+			project: ProjectOptions{Name: "tenant1", Network: ProjectNetwork{Name: "net1", IPv4Address: "10.10.0.1/24"}, StoragePools: []string{"remote"}, Limits: ProjectLimits{CPU: "8"}, Instance: InstanceLimits{CPU: "2"}},
+		},
+		{
+			desc:    "Missing name",
+			project: ProjectOptions{},
+			err:     "Project name is required",
+		},
+		{
+			desc:    "Default project",
+			project: ProjectOptions{Name: "default"},
+			err:     `Project "default" is set up by MicroCloud and cannot be seeded`,
+		},
+		{
+			desc:    "Invalid IPv4 address",
+			project: ProjectOptions{Name: "tenant1", Network: ProjectNetwork{IPv4Address: "10.10.0.1"}},
+			err:     `Invalid IPv4 address of project "tenant1": invalid CIDR address: 10.10.0.1`,
+		},
+		{
+			desc:    "Project CPU limit without default instance CPU limit",
+			project: ProjectOptions{Name: "tenant1", Limits: ProjectLimits{CPU: "8"}},
+			err:     `Invalid limits of project "tenant1": A project CPU limit requires a default instance CPU limit`,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := c.project.validate()
+		if c.err == "" {
+			s.NoError(err)
+		} else {
+			s.EqualError(err, c.err)
+		}
+	}
+}
+
+func (s *projectsSuite) Test_projectConfig() {
+	project := ProjectOptions{Name: "tenant1", StoragePools: []string{"remote"}, Limits: ProjectLimits{Instances: "10"}}
+
+	s.Equal(map[string]string{
+		"features.networks":           "true",
+		"features.profiles":           "true",
+		"features.images":             "false",
+		"restricted":                  "true",
+		"restricted.networks.uplinks": "UPLINK",
+		"restricted.networks.access":  "default",
+		"limits.instances":            "10",
+		"limits.disk.pool.local":      "0",
+	}, project.projectConfig("UPLINK", []string{"local", "remote"}))
+
+	s.T().Log("Without an uplink, the project uses the networks of the default project")
+	s.Equal(map[string]string{
+		"features.networks": "false",
+		"features.profiles": "true",
+		"features.images":   "false",
+		"restricted":        "true",
+	}, ProjectOptions{Name: "tenant1"}.projectConfig("", []string{"local", "remote"}))
+}
+
+func (s *projectsSuite) Test_profile() {
+	defaultProfile := lxdAPI.ProfilePut{
+		Config: map[string]string{"snapshots.schedule": "@daily"},
+		Devices: map[string]map[string]string{
+			"root": {"path": "/", "pool": "remote", "type": "disk"},
+			"eth0": {"name": "eth0", "network": "default", "type": "nic"},
+		},
+	}
+
+	project := ProjectOptions{Name: "tenant1", Network: ProjectNetwork{Name: "net1"}, StoragePools: []string{"local"}, Instance: InstanceLimits{Memory: "4GiB"}}
+	profile := project.profile(defaultProfile, "UPLINK")
+	s.Equal(map[string]string{"snapshots.schedule": "@daily", "limits.memory": "4GiB"}, profile.Config)
+	s.Equal(map[string]map[string]string{
+		"root": {"path": "/", "pool": "local", "type": "disk"},
+		"eth0": {"name": "eth0", "network": "net1", "type": "nic"},
+	}, profile.Devices)
+	s.Equal("remote", defaultProfile.Devices["root"]["pool"], "The default profile must not be changed")
+
+	s.T().Log("Without an uplink, the instances use the network of the default profile")
+	profile = ProjectOptions{Name: "tenant1"}.profile(defaultProfile, "")
+	s.Equal(defaultProfile.Devices, profile.Devices)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// staleMember is what a reinstalled cluster member held in the services before it was reinstalled.
+type staleMember struct {
+	// osds are the paths of the disks backing the member's OSDs.
+	osds []string
+
+	// cephServices are the MicroCeph services the member ran.
+	cephServices []string
+
+	// ovnServices are the MicroOVN services the member ran.
+	ovnServices []string
+}
+
+// staleMemberResources returns the OSDs and services the given cluster member still has records of in MicroCeph and MicroOVN.
+func staleMemberResources(ctx context.Context, sh *service.Handler, name string) (*staleMember, error) {
+	member := &staleMember{}
+
+	ceph, ok := sh.Services[types.MicroCeph].(*service.CephService)
+	if ok {
+		disks, err := ceph.GetDisks(ctx, "", nil)
+		if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+			return nil, fmt.Errorf("Failed to get the MicroCeph disks: %w", err)
+		}
+
+		for _, disk := range disks {
+			if disk.Location == name {
+				member.osds = append(member.osds, disk.Path)
+			}
+		}
+
+		services, err := ceph.GetServices(ctx, "")
+		if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+			return nil, fmt.Errorf("Failed to get the MicroCeph services: %w", err)
+		}
+
+		for _, service := range services {
+			if service.Location == name {
+				member.cephServices = append(member.cephServices, service.Service)
+			}
+		}
+	}
+
+	ovn, ok := sh.Services[types.MicroOVN].(*service.OVNService)
+	if ok {
+		services, err := ovn.GetServices(ctx)
+		if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+			return nil, fmt.Errorf("Failed to get the MicroOVN services: %w", err)
+		}
+
+		for _, service := range services {
+			if service.Location == name {
+				member.ovnServices = append(member.ovnServices, service.Service)
+			}
+		}
+	}
+
+	slices.Sort(member.osds)
+	slices.Sort(member.cephServices)
+	slices.Sort(member.ovnServices)
+
+	return member, nil
+}
+
+type cmdClusterMemberRejoin struct {
+	common *CmdControl
+
+	flagSessionTimeout int64
+}
+
+// command returns the subcommand to rejoin a reinstalled cluster member.
+func (c *cmdClusterMemberRejoin) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rejoin <name>",
+		Short: "Rejoin a reinstalled cluster member with the same name",
+		Long: `Rejoin a reinstalled cluster member with the same name
+
+The stale records of the member are removed from MicroCloud, LXD, MicroCeph and MicroOVN.
+A new trust establishment session is then started, which only accepts the reinstalled system.
+Run "microcloud join" on the reinstalled system, and re-select its disks to re-add its OSDs.`,
+		RunE: c.run,
+
+		ValidArgsFunction: c.common.completeMemberNames,
+	}
+
+	cmd.Flags().Int64Var(&c.flagSessionTimeout, "session-timeout", 0, "Amount of seconds to wait for the trust establishment session. Defaults: 60m")
+
+	return cmd
+}
+
+// run runs the subcommand to rejoin a reinstalled cluster member.
+func (c *cmdClusterMemberRejoin) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	name := args[0]
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	if status.Name == name {
+		return withExitCode(ExitCodeUsage, errors.New("Cannot rejoin the local cluster member, run the command on another cluster member"))
+	}
+
+	client, err := cloudApp.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	members, err := client.GetClusterMembers(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	if !slices.ContainsFunc(members, func(member microTypes.ClusterMember) bool { return member.Name == name }) {
+		return fmt.Errorf("Cluster member %q not found", name)
+	}
+
+	installedServices := []types.ServiceType{types.MicroCloud, types.LXD}
+	for serviceType, stateDir := range addableServices {
+		if service.Exists(serviceType, stateDir) {
+			installedServices = append(installedServices, serviceType)
+		}
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, installedServices...)
+	if err != nil {
+		return err
+	}
+
+	stale, err := staleMemberResources(cmd.Context(), sh, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(tui.SummarizeResult("Found cluster member %s", name))
+	if len(stale.osds) > 0 {
+		fmt.Printf(" OSDs: %s\n", strings.Join(stale.osds, ", "))
+	}
+
+	if len(stale.cephServices) > 0 {
+		fmt.Printf(" MicroCeph services: %s\n", strings.Join(stale.cephServices, ", "))
+	}
+
+	if len(stale.ovnServices) > 0 {
+		fmt.Printf(" MicroOVN services: %s\n", strings.Join(stale.ovnServices, ", "))
+	}
+
+	fmt.Println("")
+
+	confirm, err := c.common.asker.AskBool(fmt.Sprintf("Remove the stale records of %q from all services and rejoin it?", name), false)
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		return withExitCode(ExitCodeCancelled, errors.New("Rejoin cancelled"))
+	}
+
+	// The reinstalled system can't take part in the removal, so force it.
+	err = cloudClient.DeleteClusterMember(cmd.Context(), client, name, true)
+	if err != nil {
+		return fmt.Errorf("Failed to remove the stale records of %q: %w", name, err)
+	}
+
+	fmt.Println(tui.SummarizeResult("Removed the stale records of %s", name))
+	if len(stale.osds) > 0 {
+		fmt.Println(tui.SummarizeResult("Re-select the disks %s when asked, to re-add the OSDs of %s", strings.Join(stale.osds, ", "), name))
+	}
+
+	if len(stale.ovnServices) > 0 {
+		fmt.Println(tui.SummarizeResult("The MicroOVN chassis of %s is re-added once it joins", name))
+	}
+
+	fmt.Println("")
+
+	err = addSystems(c.common, c.flagSessionTimeout, []string{name}, false, nil, false)
+	if err != nil {
+		return fmt.Errorf("Failed to rejoin %q, run \"microcloud add\" to add it again: %w", name, err)
+	}
+
+	return nil
+}
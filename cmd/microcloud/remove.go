@@ -23,6 +23,8 @@ func (c *cmdRemove) command() *cobra.Command {
 		Aliases: []string{"rm"},
 		Short:   "Remove the specified member from all MicroCloud services",
 		RunE:    c.run,
+
+		ValidArgsFunction: c.common.completeMemberNames,
 	}
 
 	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, "Forcibly remove the cluster member")
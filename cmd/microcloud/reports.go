@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// reportFormatHTML renders a health report as a standalone HTML page.
+const reportFormatHTML = "html"
+
+// reportFormats are the formats a single health report can be shown in.
+var reportFormats = []string{reportFormatHTML, tui.TableFormatJSON, tui.TableFormatYAML}
+
+// reportSummary returns the number of members, members which aren't online, OSDs down and warnings of a health report.
+func reportSummary(report types.HealthReport) []string {
+	offline := 0
+	osdsDown := 0
+	for _, member := range report.Members {
+		if member.Status != "ONLINE" {
+			offline++
+		}
+
+		osdsDown += member.OSDsDown
+	}
+
+	return []string{strconv.Itoa(len(report.Members)), strconv.Itoa(offline), strconv.Itoa(osdsDown), strconv.Itoa(len(report.Warnings))}
+}
+
+type cmdReports struct {
+	common *CmdControl
+}
+
+// command returns the reports subcommand.
+func (c *cmdReports) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reports",
+		Short: "Generate and review health and capacity reports of the cluster",
+		RunE:  c.run,
+	}
+
+	var cmdList = cmdReportsList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	var cmdShow = cmdReportsShow{common: c.common}
+	cmd.AddCommand(cmdShow.command())
+
+	var cmdGenerate = cmdReportsGenerate{common: c.common}
+	cmd.AddCommand(cmdGenerate.command())
+
+	return cmd
+}
+
+// run runs the reports subcommand.
+func (c *cmdReports) run(cmd *cobra.Command, args []string) error {
+	return cmd.Help()
+}
+
+type cmdReportsList struct {
+	common     *CmdControl
+	flagFormat string
+}
+
+// command returns the subcommand for listing health reports.
+func (c *cmdReportsList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the stored health reports",
+		Long: `List the stored health reports
+
+Reports are generated on the schedule set by the "reports.schedule" configuration key, or with "microcloud reports generate".
+The most recent reports are kept, oldest first.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand for listing health reports.
+func (c *cmdReportsList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	reports, err := cloudClient.GetReports(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	data := make([][]string, len(reports))
+	for i, report := range reports {
+		data[i] = append([]string{strconv.FormatInt(report.ID, 10), report.CreatedAt.Local().Format("2006-01-02 15:04:05"), report.Member}, reportSummary(report)...)
+	}
+
+	header := []string{"ID", "CREATED", "GENERATED BY", "MEMBERS", "NOT ONLINE", "OSDS DOWN", "WARNINGS"}
+	table, err := tui.FormatData(c.flagFormat, header, data, reports)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+type cmdReportsShow struct {
+	common     *CmdControl
+	flagFormat string
+}
+
+// command returns the subcommand for showing a health report.
+func (c *cmdReportsShow) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a stored health report",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatYAML, "Format (html|json|yaml)")
+
+	return cmd
+}
+
+// run runs the subcommand for showing a health report.
+func (c *cmdReportsShow) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid report ID %q", args[0]))
+	}
+
+	if !slices.Contains(reportFormats, c.flagFormat) {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid format (%s)", c.flagFormat))
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	report, err := cloudClient.GetReport(cmd.Context(), client, id)
+	if err != nil {
+		return err
+	}
+
+	return printReport(*report, c.flagFormat)
+}
+
+type cmdReportsGenerate struct {
+	common     *CmdControl
+	flagFormat string
+	flagForce  bool
+}
+
+// command returns the subcommand for generating a health report.
+func (c *cmdReportsGenerate) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate and store a health report now",
+		RunE:  c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatYAML, "Format (html|json|yaml)")
+	addMaintenanceForceFlag(cmd, &c.flagForce)
+
+	return cmd
+}
+
+// run runs the subcommand for generating a health report.
+func (c *cmdReportsGenerate) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	if !slices.Contains(reportFormats, c.flagFormat) {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid format (%s)", c.flagFormat))
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	err = checkMaintenanceWindows(cmd.Context(), client, c.flagForce)
+	if err != nil {
+		return err
+	}
+
+	report, err := cloudClient.GenerateReport(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	return printReport(*report, c.flagFormat)
+}
+
+// printReport prints the health report in the given format.
+func printReport(report types.HealthReport, format string) error {
+	if format == reportFormatHTML {
+		out, err := service.RenderHealthReportHTML(report)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(string(out))
+
+		return nil
+	}
+
+	out, err := tui.FormatData(format, nil, nil, report)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(out)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// NamesOptions represents the custom names of the storage pools and networks in the preseed yaml.
+type NamesOptions struct {
+	LocalPool     string `yaml:"local_pool"`
+	RemotePool    string `yaml:"remote_pool"`
+	RemoteFSPool  string `yaml:"remote_fs_pool"`
+	FanNetwork    string `yaml:"fan_network"`
+	OVNNetwork    string `yaml:"ovn_network"`
+	UplinkNetwork string `yaml:"uplink_network"`
+}
+
+// isSet returns whether any custom name is given.
+func (n NamesOptions) isSet() bool {
+	return n != NamesOptions{}
+}
+
+// resourceNames returns the names of the storage pools and networks, with the default names filling in the ones not given.
+func (n NamesOptions) resourceNames() service.ResourceNames {
+	names := service.DefaultResourceNames()
+	overrides := map[*string]string{
+		&names.LocalPool:     n.LocalPool,
+		&names.RemotePool:    n.RemotePool,
+		&names.RemoteFSPool:  n.RemoteFSPool,
+		&names.FanNetwork:    n.FanNetwork,
+		&names.OVNNetwork:    n.OVNNetwork,
+		&names.UplinkNetwork: n.UplinkNetwork,
+	}
+
+	for name, override := range overrides {
+		if override != "" {
+			*name = override
+		}
+	}
+
+	return names
+}
+
+// handlerResourceNames returns the names of the storage pools and networks held by the LXD service of the handler,
+// or the default names if the handler has no LXD service.
+func handlerResourceNames(sh *service.Handler) service.ResourceNames {
+	lxd, ok := sh.Services[types.LXD].(*service.LXDService)
+	if !ok {
+		return service.DefaultResourceNames()
+	}
+
+	return lxd.ResourceNames()
+}
+
+// askResourceNames asks for custom names of the storage pools and networks set up by MicroCloud.
+func (c *initConfig) askResourceNames(sh *service.Handler) error {
+	if c.autoSetup || !c.bootstrap {
+		return nil
+	}
+
+	customize, err := c.asker.AskBool("Would you like to customize the names of the storage pools and networks?", false)
+	if err != nil {
+		return err
+	}
+
+	if !customize {
+		return nil
+	}
+
+	lxd := sh.Services[types.LXD].(*service.LXDService)
+	names := lxd.ResourceNames()
+	questions := []struct {
+		label   string
+		name    *string
+		service types.ServiceType
+		check   func(string) error
+	}{
+		{label: "local storage pool", name: &names.LocalPool, service: types.LXD, check: service.ValidateStoragePoolName},
+		{label: "remote storage pool", name: &names.RemotePool, service: types.MicroCeph, check: service.ValidateStoragePoolName},
+		{label: "remote-fs storage pool", name: &names.RemoteFSPool, service: types.MicroCeph, check: service.ValidateStoragePoolName},
+		{label: "Ubuntu Fan network", name: &names.FanNetwork, service: types.LXD, check: validate.IsInterfaceName},
+		{label: "OVN network", name: &names.OVNNetwork, service: types.MicroOVN, check: validate.IsInterfaceName},
+		{label: "OVN uplink network", name: &names.UplinkNetwork, service: types.MicroOVN, check: validate.IsInterfaceName},
+	}
+
+	for _, question := range questions {
+		if sh.Services[question.service] == nil {
+			continue
+		}
+
+		*question.name, err = c.asker.AskString(fmt.Sprintf("What name would you like to use for the %s?", question.label), *question.name, question.check)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = names.Validate()
+	if err != nil {
+		return err
+	}
+
+	lxd.SetResourceNames(names)
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+// backupConfigChanges returns the configuration changes restoring the backed up configuration, with keys to unset having an empty value.
+// Keys not supported by this version of MicroCloud are returned separately, and left out of the changes.
+func backupConfigChanges(current map[string]string, backup map[string]string) (changes map[string]string, unsupported []string) {
+	changes = map[string]string{}
+	for key, value := range backup {
+		if !slices.Contains(types.ConfigKeys, key) {
+			unsupported = append(unsupported, key)
+			continue
+		}
+
+		if current[key] != value {
+			changes[key] = value
+		}
+	}
+
+	for key := range current {
+		_, ok := backup[key]
+		if !ok {
+			changes[key] = ""
+		}
+	}
+
+	sort.Strings(unsupported)
+
+	return changes, unsupported
+}
+
+// compareBackupMembers returns the backed up cluster members which are missing from the current cluster, and the current cluster members not found in the backup.
+func compareBackupMembers(backup Backup, statuses []types.Status) (missing []string, unexpected []string) {
+	current := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		current = append(current, status.Name)
+	}
+
+	for _, member := range backup.Members {
+		if !slices.Contains(current, member.Name) {
+			missing = append(missing, member.Name)
+		}
+	}
+
+	for _, name := range current {
+		if !slices.ContainsFunc(backup.Members, func(member BackupMember) bool { return member.Name == name }) {
+			unexpected = append(unexpected, name)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+
+	return missing, unexpected
+}
+
+// backupOSDRows returns a table row for each backed up OSD, with whether the OSD is found on the same cluster member in the current cluster.
+// The number of OSDs still to re-attach is returned as well.
+func backupOSDRows(backup Backup, statuses []types.Status) (rows [][]string, missing int) {
+	for _, member := range backup.Members {
+		for _, osd := range member.OSDs {
+			found := slices.ContainsFunc(statuses, func(status types.Status) bool {
+				return status.Name == member.Name && slices.ContainsFunc(status.OSDs, func(disk cephTypes.Disk) bool { return disk.OSD == osd.OSD })
+			})
+
+			state := "attached"
+			if !found {
+				state = "missing"
+				missing++
+			}
+
+			rows = append(rows, []string{member.Name, strconv.FormatInt(osd.OSD, 10), osd.Path, state})
+		}
+	}
+
+	return rows, missing
+}
+
+type cmdRestore struct {
+	common *CmdControl
+
+	flagBackup string
+}
+
+// command returns the subcommand to restore the MicroCloud control plane from a backup.
+func (c *cmdRestore) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the MicroCloud control plane from a backup onto new hardware",
+		Long: `Restore the MicroCloud control plane from a backup onto new hardware
+
+Initialize MicroCloud on the new systems first, naming them after the backed up cluster members and without selecting the surviving Ceph disks.
+The restore then checks the cluster members and Ceph networks against the backup, restores the MicroCloud daemon configuration,
+and walks through re-attaching the surviving Ceph disks.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagBackup, "backup", "", "Backup written by \"microcloud backup\""+"``")
+	_ = cmd.MarkFlagRequired("backup")
+
+	return cmd
+}
+
+// run runs the subcommand to restore the MicroCloud control plane from a backup.
+func (c *cmdRestore) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	backup, err := readBackup(c.flagBackup)
+	if err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+
+	names := make([]string, 0, len(backup.Members))
+	for _, member := range backup.Members {
+		names = append(names, member.Name)
+	}
+
+	fmt.Println(tui.SummarizeResult("Restoring the backup of %s from %s", strings.Join(names, ", "), backup.CreatedAt.Local().Format("2006-01-02 15:04:05")))
+	fmt.Println("")
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("%w\nInitialize MicroCloud on systems named %s before restoring the backup", err, strings.Join(names, ", "))
+	}
+
+	statuses, err := cloudClient.GetStatus(cmd.Context(), client)
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	// Check the cluster members.
+	missing, unexpected := compareBackupMembers(*backup, statuses)
+	for _, name := range missing {
+		tui.PrintWarning(fmt.Sprintf("Cluster member %q of the backup is missing, add a system named %q with \"microcloud add\"", name, name))
+	}
+
+	for _, name := range unexpected {
+		tui.PrintWarning(fmt.Sprintf("Cluster member %q is not part of the backup", name))
+	}
+
+	// Check the Ceph networks, which can't be changed once Ceph is set up.
+	if len(backup.CephConfig) > 0 {
+		cephConfig, err := localCephConfig(cmd.Context(), c.common.FlagMicroCloudDir)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range backupCephConfigKeys {
+			if backup.CephConfig[key] != "" && cephConfig[key] != backup.CephConfig[key] {
+				tui.PrintWarning(fmt.Sprintf("Ceph %s is %q, but was %q in the backup", key, cephConfig[key], backup.CephConfig[key]))
+			}
+		}
+	}
+
+	// Restore the MicroCloud daemon configuration.
+	current, err := cloudClient.GetConfig(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+
+	changes, unsupported := backupConfigChanges(current, backup.Config)
+	for _, key := range unsupported {
+		tui.PrintWarning(fmt.Sprintf("Skipping configuration key %q not supported by this version of MicroCloud", key))
+	}
+
+	if len(changes) > 0 {
+		keys := make([]string, 0, len(changes))
+		for key := range changes {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		fmt.Println("")
+		fmt.Println("The MicroCloud configuration differs from the backup:")
+		for _, key := range keys {
+			if changes[key] == "" {
+				fmt.Printf(" Unset %s\n", key)
+			} else {
+				fmt.Printf(" Set %s=%s\n", key, changes[key])
+			}
+		}
+
+		fmt.Println("")
+
+		restore, err := c.common.asker.AskBool("Restore the MicroCloud configuration?", true)
+		if err != nil {
+			return err
+		}
+
+		if restore {
+			err = cloudClient.UpdateConfig(cmd.Context(), client, changes)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(tui.SummarizeResult("Restored the MicroCloud configuration"))
+		}
+	} else {
+		fmt.Println(tui.SummarizeResult("The MicroCloud configuration matches the backup"))
+	}
+
+	// Walk through re-attaching the surviving Ceph disks.
+	rows, missingOSDs := backupOSDRows(*backup, statuses)
+	if len(rows) == 0 {
+		fmt.Println(tui.SummarizeResult("The backup has no Ceph OSDs to re-attach"))
+		return nil
+	}
+
+	fmt.Println("")
+	fmt.Println(tui.NewTable([]string{"MEMBER", "OSD", "DISK", "STATUS"}, rows))
+
+	if missingOSDs == 0 {
+		fmt.Println(tui.SummarizeResult("All Ceph OSDs of the backup are attached"))
+		return nil
+	}
+
+	fmt.Printf(`
+To re-attach the %d missing Ceph OSDs:
+
+ 1. Attach each surviving disk to the new system named after its cluster member. Don't wipe the disks.
+ 2. Rebuild the Ceph monitor store from the surviving OSDs, as described in the MicroCloud disaster recovery documentation.
+ 3. Run "microcloud restore --backup %s" again to confirm all OSDs are attached.
+ 4. Run "lxd recover" to re-import the instances and volumes of the restored storage pools.
+`, missingOSDs, c.flagBackup)
+
+	return errors.New("Some Ceph OSDs of the backup are still missing")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// unfinishedJoins returns the join states of the services the cluster member failed or didn't finish to join, sorted by service.
+func unfinishedJoins(joinStates []types.JoinState) []types.JoinState {
+	unfinished := []types.JoinState{}
+	for _, joinState := range joinStates {
+		if joinState.Status != types.JoinStateJoined {
+			unfinished = append(unfinished, joinState)
+		}
+	}
+
+	slices.SortFunc(unfinished, func(a types.JoinState, b types.JoinState) int {
+		return strings.Compare(string(a.Service), string(b.Service))
+	})
+
+	return unfinished
+}
+
+type cmdClusterMemberRetryJoin struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to retry the failed service joins of a cluster member.
+func (c *cmdClusterMemberRetryJoin) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retry-join <name>",
+		Short: "Retry the service joins a cluster member failed to complete",
+		Long: `Retry the service joins a cluster member failed to complete
+
+Each cluster member records whether it joined LXD, MicroCeph and MicroOVN when it was added.
+If some of the joins failed, for example MicroOVN after MicroCeph succeeded, only the missing joins are retried,
+with the join configuration used when the member was added.`,
+		RunE: c.run,
+
+		ValidArgsFunction: c.common.completeMemberNames,
+	}
+
+	return cmd
+}
+
+// run runs the subcommand to retry the failed service joins of a cluster member.
+func (c *cmdClusterMemberRetryJoin) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	name := args[0]
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	joinStates, err := cloudClient.GetJoinStates(cmd.Context(), client, name)
+	if err != nil {
+		return err
+	}
+
+	if len(joinStates) == 0 {
+		return fmt.Errorf("No service joins are recorded for cluster member %q", name)
+	}
+
+	unfinished := unfinishedJoins(joinStates)
+	if len(unfinished) == 0 {
+		fmt.Println(tui.SummarizeResult("Cluster member %s has joined all services", name))
+		return nil
+	}
+
+	// Serialize with other cluster changes, such as a concurrent "microcloud add".
+	release, err := lockClusterChanges(cmd.Context(), client, "retry-join", "Retry the service joins of "+name)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	status, err := cloudApp.Status(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	installedServices := []types.ServiceType{types.MicroCloud, types.LXD}
+	for serviceType, stateDir := range addableServices {
+		if service.Exists(serviceType, stateDir) {
+			installedServices = append(installedServices, serviceType)
+		}
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, installedServices...)
+	if err != nil {
+		return err
+	}
+
+	// All joins of a cluster member are requested with the same configuration.
+	joinConfig := unfinished[0].Config
+	joinConfig.Tokens = []types.ServiceToken{}
+	for _, joinState := range unfinished {
+		s := sh.Services[joinState.Service]
+		if s == nil {
+			return fmt.Errorf("%s is not installed on the local system", joinState.Service)
+		}
+
+		members, err := service.RetryValue(cmd.Context(), sh.RetryPolicy, func() (map[string]string, error) {
+			return s.ClusterMembers(cmd.Context())
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to get the %s cluster members: %w", joinState.Service, err)
+		}
+
+		if members[name] != "" {
+			fmt.Println(tui.SummarizeResult("Cluster member %s is already part of the %s cluster", name, joinState.Service))
+			continue
+		}
+
+		if joinState.Status == types.JoinStateFailed {
+			fmt.Printf("Retrying to join %s to %s, which failed: %s\n", name, joinState.Service, joinState.Error)
+		} else {
+			fmt.Printf("Retrying to join %s to %s, which was not completed\n", name, joinState.Service)
+		}
+
+		// Drop the token of the failed join, if it was left behind.
+		_ = s.DeleteToken(context.Background(), name, "")
+
+		token, err := s.IssueToken(cmd.Context(), name)
+		if err != nil {
+			return fmt.Errorf("Failed to issue %s token for %q: %w", joinState.Service, name, err)
+		}
+
+		joinConfig.Tokens = append(joinConfig.Tokens, types.ServiceToken{Service: joinState.Service, JoinToken: token})
+	}
+
+	if len(joinConfig.Tokens) == 0 {
+		return nil
+	}
+
+	cloud := sh.Services[types.MicroCloud].(*service.CloudService)
+	err = service.Retry(cmd.Context(), sh.RetryPolicy, func() error {
+		return cloud.RequestJoin(cmd.Context(), name, nil, joinConfig)
+	})
+	if err != nil {
+		return errors.Join(fmt.Errorf("Cluster member %q failed to join the services again", name), err)
+	}
+
+	for _, token := range joinConfig.Tokens {
+		fmt.Println(tui.SummarizeResult("Cluster member %s has joined %s", name, token.Service))
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type retryJoinSuite struct {
+	suite.Suite
+}
+
+func TestRetryJoinSuite(t *testing.T) {
+	suite.Run(t, new(retryJoinSuite))
+}
+
+func (s *retryJoinSuite) Test_unfinishedJoins() {
+	joinStates := []types.JoinState{
+		{Service: types.MicroOVN, Status: types.JoinStateFailed},
+		{Service: types.MicroCeph, Status: types.JoinStateJoined},
+		{Service: types.LXD, Status: types.JoinStatePending},
+	}
+
+	unfinished := unfinishedJoins(joinStates)
+	s.Len(unfinished, 2)
+	s.Equal(types.LXD, unfinished[0].Service)
+	s.Equal(types.MicroOVN, unfinished[1].Service)
+
+	s.Empty(unfinishedJoins([]types.JoinState{{Service: types.LXD, Status: types.JoinStateJoined}}))
+}
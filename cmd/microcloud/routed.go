@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// routedSystems returns the sorted names of the systems whose MicroCloud address is outside the lookup subnet,
+// which are reached through a router rather than on the same subnet.
+func (c *initConfig) routedSystems() []string {
+	if c.lookupSubnet == nil {
+		return nil
+	}
+
+	names := []string{}
+	for name, system := range c.systems {
+		ip := net.ParseIP(system.ServerInfo.Address)
+		if ip != nil && !c.lookupSubnet.Contains(ip) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// unreachableError returns the error listing the systems that can't reach each other, or nil if there are none.
+// The unreachable addresses are keyed by the name of the system connecting to them.
+func unreachableError(unreachable map[string][]string) error {
+	if len(unreachable) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(unreachable))
+	for name := range unreachable {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf(" - %q can't reach %s", name, strings.Join(unreachable[name], ", ")))
+	}
+
+	return fmt.Errorf("Some systems can't reach each other. Check the routes between their subnets:\n%s", strings.Join(lines, "\n"))
+}
+
+// checkReachability checks that each system being set up reaches the MicroCloud address of every other system, when some of them are on routed subnets.
+// Systems on the lookup subnet are expected to reach each other, so the check only runs if the cluster spans several subnets.
+func (c *initConfig) checkReachability(ctx context.Context, sh *service.Handler) error {
+	routed := c.routedSystems()
+	if len(routed) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Checking the routes between the systems on subnets other than %s ...\n", c.lookupSubnet.String())
+
+	// The local system may not be part of the systems yet, but is always set up with them.
+	systemAddresses := map[string]string{c.name: c.address}
+	for name, system := range c.systems {
+		if system.ServerInfo.Address != "" {
+			systemAddresses[name] = system.ServerInfo.Address
+		}
+	}
+
+	names := make([]string, 0, len(systemAddresses))
+	for name := range systemAddresses {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	cloud := sh.Services[types.MicroCloud].(*service.CloudService)
+	unreachable := map[string][]string{}
+	for _, name := range names {
+		addresses := make([]string, 0, len(names)-1)
+		for _, peer := range names {
+			if peer != name {
+				addresses = append(addresses, util.CanonicalNetworkAddress(systemAddresses[peer], service.CloudPort))
+			}
+		}
+
+		var results []types.Reachability
+		if name == c.name {
+			results = service.CheckReachability(ctx, addresses, service.ReachabilityTimeout)
+		} else {
+			system := c.systems[name]
+			remoteClient, err := cloud.RemoteClient(system.ServerInfo.Certificate, util.CanonicalNetworkAddress(system.ServerInfo.Address, service.CloudPort))
+			if err != nil {
+				return err
+			}
+
+			results, err = cloudClient.CheckReachability(ctx, remoteClient, types.ReachabilityPost{Addresses: addresses})
+			if err != nil {
+				// Systems running an older MicroCloud can't check their routes.
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					tui.PrintWarning(fmt.Sprintf("Unable to check the routes of %q", name))
+					continue
+				}
+
+				return err
+			}
+		}
+
+		for _, result := range results {
+			if result.Error != "" {
+				unreachable[name] = append(unreachable[name], result.Address)
+			}
+		}
+	}
+
+	err := unreachableError(unreachable)
+	if err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/multicast"
+)
+
+type routedSuite struct {
+	suite.Suite
+}
+
+func TestRoutedSuite(t *testing.T) {
+	suite.Run(t, new(routedSuite))
+}
+
+func (s *routedSuite) Test_routedSystems() {
+	_, subnet, err := net.ParseCIDR("10.0.1.0/24")
+	s.Require().NoError(err)
+
+	c := initConfig{
+		lookupSubnet: subnet,
+		systems: map[string]InitSystem{
+			"micro01": {ServerInfo: multicast.ServerInfo{Name: "micro01", Address: "10.0.1.1"}},
+			"micro02": {ServerInfo: multicast.ServerInfo{Name: "micro02", Address: "10.0.1.2"}},
+			"micro03": {ServerInfo: multicast.ServerInfo{Name: "micro03", Address: "10.0.3.1"}},
+			"micro04": {ServerInfo: multicast.ServerInfo{Name: "micro04", Address: "10.0.2.1"}},
+			"micro05": {ServerInfo: multicast.ServerInfo{Name: "micro05"}},
+		},
+	}
+
+	s.Equal([]string{"micro03", "micro04"}, c.routedSystems())
+
+	c.lookupSubnet = nil
+	s.Empty(c.routedSystems())
+}
+
+func (s *routedSuite) Test_unreachableError() {
+	s.NoError(unreachableError(nil))
+
+	err := unreachableError(map[string][]string{
+		"micro02": {"10.0.1.1:9443"},
+		"micro01": {"10.0.2.1:9443", "10.0.3.1:9443"},
+	})
+	s.EqualError(err, `Some systems can't reach each other. Check the routes between their subnets:
+ - "micro01" can't reach 10.0.2.1:9443, 10.0.3.1:9443
+ - "micro02" can't reach 10.0.1.1:9443`)
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+	"github.com/canonical/microcloud/microcloud/version"
+)
+
+// seedPreseedPath is the path of the joining preseed file written by the cloud-init seed.
+const seedPreseedPath = "/root/microcloud-join.yaml"
+
+// seedHostname is the cloud-init template of the hostname of the new node.
+const seedHostname = "{{ v1.local_hostname }}"
+
+// seedVersionRegex matches the major and minor number of a service version.
+var seedVersionRegex = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// cephReleases are the MicroCeph snap tracks, keyed by Ceph major version.
+var cephReleases = map[string]string{
+	"17": "quincy",
+	"18": "reef",
+	"19": "squid",
+}
+
+// seedSnap is a snap to install on the new node.
+type seedSnap struct {
+	Name    string
+	Channel string
+	Version string
+}
+
+// seedOptions are the options of the cloud-init seed for a new node.
+type seedOptions struct {
+	// Name is the name of the new node, defaults to its hostname when empty.
+	Name           string
+	Initiator      string
+	LookupSubnet   string
+	Passphrase     string
+	SessionTimeout int64
+	Snaps          []seedSnap
+}
+
+type cmdSeed struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to generate provisioning seeds.
+func (c *cmdSeed) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Generate provisioning seeds for new systems",
+		RunE:  func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdNewNode = cmdSeedNewNode{common: c.common}
+	cmd.AddCommand(cmdNewNode.command())
+
+	return cmd
+}
+
+type cmdSeedNewNode struct {
+	common *CmdControl
+
+	flagName           string
+	flagLookupSubnet   string
+	flagPassphrase     string
+	flagSessionTimeout int64
+	flagChannels       []string
+	flagOutput         string
+}
+
+// command returns the subcommand to generate a cloud-init seed for a new node.
+func (c *cmdSeedNewNode) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "new-node",
+		Short: "Generate a cloud-init seed for a new system to join this MicroCloud",
+		Long: `Generate a cloud-init seed for a new system to join this MicroCloud
+
+The seed installs the snaps of the services running on this cluster, holds their updates
+and then joins the cluster unattended with "microcloud preseed", using this system as the initiator.
+The snap channels are derived from the versions running on this system, unless given with --channel.
+
+To accept the new systems, run "microcloud preseed" on this system with the same session passphrase
+and lookup subnet, listing the new systems by name.
+
+Example:
+  microcloud seed new-node --lookup-subnet 10.0.0.0/24 --channel lxd=5.21/stable > user-data`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagName, "name", "", "Name of the new system (defaults to its hostname)"+"``")
+	cmd.Flags().StringVar(&c.flagLookupSubnet, "lookup-subnet", "", "Subnet to find the initiator in (defaults to the subnet of this system's MicroCloud address)"+"``")
+	cmd.Flags().StringVar(&c.flagPassphrase, "passphrase", "", "Session passphrase (defaults to a new random passphrase)"+"``")
+	cmd.Flags().Int64Var(&c.flagSessionTimeout, "session-timeout", 0, "Amount of seconds the new system waits for the trust establishment session"+"``")
+	cmd.Flags().StringSliceVar(&c.flagChannels, "channel", nil, "Snap channel to install, as <snap>=<channel>, can be given multiple times"+"``")
+	cmd.Flags().StringVarP(&c.flagOutput, "output", "o", "", "File to write the seed to (defaults to stdout)"+"``")
+
+	return cmd
+}
+
+// run runs the subcommand to generate a cloud-init seed for a new node.
+func (c *cmdSeedNewNode) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	channels := map[string]string{}
+	for _, channel := range c.flagChannels {
+		snap, value, ok := strings.Cut(channel, "=")
+		if !ok || snap == "" || value == "" {
+			return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid channel %q (must be of the form <snap>=<channel>)", channel))
+		}
+
+		channels[snap] = value
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	opts := seedOptions{
+		Name:           c.flagName,
+		Initiator:      status.Name,
+		LookupSubnet:   c.flagLookupSubnet,
+		Passphrase:     c.flagPassphrase,
+		SessionTimeout: c.flagSessionTimeout,
+	}
+
+	if opts.LookupSubnet == "" {
+		_, subnet, err := (&Preseed{}).findInterfaceAndNetworkForAddress(status.Address.Addr().String())
+		if err != nil {
+			return err
+		}
+
+		opts.LookupSubnet = subnet.String()
+	}
+
+	if opts.Passphrase == "" {
+		opts.Passphrase, err = service.GeneratePassphrase()
+		if err != nil {
+			return err
+		}
+	}
+
+	installedServices := []types.ServiceType{types.LXD}
+	for _, serviceType := range []types.ServiceType{types.MicroCeph, types.MicroOVN} {
+		if service.Exists(serviceType, addableServices[serviceType]) {
+			installedServices = append(installedServices, serviceType)
+		}
+	}
+
+	s, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, installedServices...)
+	if err != nil {
+		return err
+	}
+
+	// Install the services in the same order as a manual installation, with MicroCloud last.
+	for _, serviceType := range append(installedServices, types.MicroCloud) {
+		snap := seedSnap{Name: strings.ToLower(string(serviceType)), Version: version.RawVersion}
+		if serviceType != types.MicroCloud {
+			snap.Version, err = s.Services[serviceType].GetVersion(context.Background())
+			if err != nil {
+				return err
+			}
+		}
+
+		snap.Channel = channels[snap.Name]
+		if snap.Channel == "" {
+			snap.Channel = snapChannel(serviceType, snap.Version)
+		}
+
+		delete(channels, snap.Name)
+		opts.Snaps = append(opts.Snaps, snap)
+	}
+
+	if len(channels) > 0 {
+		unknown := make([]string, 0, len(channels))
+		for snap := range channels {
+			unknown = append(unknown, snap)
+		}
+
+		sort.Strings(unknown)
+
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Snaps not used by this MicroCloud: %s", strings.Join(unknown, ", ")))
+	}
+
+	seed, err := newNodeSeed(opts)
+	if err != nil {
+		return err
+	}
+
+	if c.flagOutput == "" {
+		fmt.Print(string(seed))
+	} else {
+		err = os.WriteFile(c.flagOutput, seed, 0600)
+		if err != nil {
+			return fmt.Errorf("Failed to write the seed to %q: %w", c.flagOutput, err)
+		}
+	}
+
+	// The passphrase is required by the initiator, so always show it, even when the seed goes to stdout.
+	fmt.Fprintf(os.Stderr, "Session passphrase for the new systems: %s\n", opts.Passphrase)
+
+	return nil
+}
+
+// snapChannel returns the stable channel of the snap track matching the given service version.
+// An empty channel is returned if no track matches, in which case the snap's default channel is used.
+func snapChannel(serviceType types.ServiceType, serviceVersion string) string {
+	match := seedVersionRegex.FindStringSubmatch(serviceVersion)
+	if match == nil {
+		return ""
+	}
+
+	track := match[1] + "." + match[2]
+	switch serviceType {
+	case types.MicroCloud:
+		track = match[1]
+	case types.MicroCeph:
+		track = cephReleases[match[1]]
+	}
+
+	if track == "" {
+		return ""
+	}
+
+	return track + "/stable"
+}
+
+// newNodeSeed returns the cloud-init user data which installs the snaps and joins the new node to the initiator.
+func newNodeSeed(opts seedOptions) ([]byte, error) {
+	name := opts.Name
+	if name == "" {
+		name = seedHostname
+	}
+
+	preseed := map[string]any{
+		"initiator":          opts.Initiator,
+		"lookup_subnet":      opts.LookupSubnet,
+		"session_passphrase": opts.Passphrase,
+		"systems":            []map[string]string{{"name": name}},
+	}
+
+	if opts.SessionTimeout > 0 {
+		preseed["session_timeout"] = opts.SessionTimeout
+	}
+
+	preseedYAML, err := yaml.Marshal(preseed)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format the preseed: %w", err)
+	}
+
+	snapCommands := make([][]string, 0, len(opts.Snaps)+1)
+	hold := []string{"snap", "refresh", "--hold"}
+	versions := make([]string, 0, len(opts.Snaps))
+	for _, snap := range opts.Snaps {
+		command := []string{"snap", "install", snap.Name, "--cohort=+"}
+		if snap.Channel != "" {
+			command = append(command, "--channel="+snap.Channel)
+		}
+
+		snapCommands = append(snapCommands, command)
+		hold = append(hold, snap.Name)
+		versions = append(versions, fmt.Sprintf("%s %s", snap.Name, snap.Version))
+	}
+
+	snapCommands = append(snapCommands, hold)
+
+	config := map[string]any{
+		"snap": map[string]any{"commands": snapCommands},
+		"write_files": []map[string]string{{
+			"path":        seedPreseedPath,
+			"permissions": "0600",
+			"content":     string(preseedYAML),
+		}},
+		"runcmd": [][]string{
+			{"microcloud", "waitready"},
+			{"sh", "-c", fmt.Sprintf("microcloud preseed < %s && rm %s", seedPreseedPath, seedPreseedPath)},
+		},
+	}
+
+	configYAML, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format the seed: %w", err)
+	}
+
+	header := "#cloud-config\n"
+	if opts.Name == "" {
+		header = "## template: jinja\n" + header
+	}
+
+	header += fmt.Sprintf("# Joins the MicroCloud of %q.\n# Expected versions: %s\n", opts.Initiator, strings.Join(versions, ", "))
+
+	return append([]byte(header), configYAML...), nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type seedSuite struct {
+	suite.Suite
+}
+
+func TestSeedSuite(t *testing.T) {
+	suite.Run(t, new(seedSuite))
+}
+
+func (s *seedSuite) Test_snapChannel() {
+	cases := []struct {
+		desc        string
+		serviceType types.ServiceType
+		version     string
+		channel     string
+	}{
+		{desc: "LXD LTS", serviceType: types.LXD, version: "5.21.3 LTS", channel: "5.21/stable"},
+		{desc: "MicroOVN", serviceType: types.MicroOVN, version: "24.03.2", channel: "24.03/stable"},
+		{desc: "MicroCeph", serviceType: types.MicroCeph, version: "ceph-version: 19.2.0-0ubuntu0.24.04.1", channel: "squid/stable"},
+		{desc: "Unknown MicroCeph release", serviceType: types.MicroCeph, version: "99.1.0", channel: ""},
+		{desc: "MicroCloud", serviceType: types.MicroCloud, version: "3.0", channel: "3/stable"},
+		{desc: "Unparsable version", serviceType: types.LXD, version: "unknown", channel: ""},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.channel, snapChannel(c.serviceType, c.version))
+	}
+}
+
+func (s *seedSuite) Test_newNodeSeed() {
+	opts := seedOptions{
+		Initiator:    "micro01",
+		LookupSubnet: "10.0.0.0/24",
+		Passphrase:   "a b c d",
+		Snaps: []seedSnap{
+			{Name: "lxd", Channel: "5.21/stable", Version: "5.21.3"},
+			{Name: "microcloud", Version: "3.0"},
+		},
+	}
+
+	seed, err := newNodeSeed(opts)
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(string(seed), "## template: jinja\n#cloud-config\n"))
+
+	config := struct {
+		Snap struct {
+			Commands [][]string `yaml:"commands"`
+		} `yaml:"snap"`
+		WriteFiles []map[string]string `yaml:"write_files"`
+		RunCmd     [][]string          `yaml:"runcmd"`
+	}{}
+
+	s.Require().NoError(yaml.Unmarshal(seed, &config))
+	s.Equal([][]string{
+		{"snap", "install", "lxd", "--cohort=+", "--channel=5.21/stable"},
+		{"snap", "install", "microcloud", "--cohort=+"},
+		{"snap", "refresh", "--hold", "lxd", "microcloud"},
+	}, config.Snap.Commands)
+
+	s.Require().Len(config.WriteFiles, 1)
+	preseed := Preseed{}
+	s.Require().NoError(yaml.Unmarshal([]byte(config.WriteFiles[0]["content"]), &preseed))
+	s.Equal("micro01", preseed.Initiator)
+	s.Equal("10.0.0.0/24", preseed.LookupSubnet)
+	s.Equal("a b c d", preseed.SessionPassphrase)
+	s.Equal([]System{{Name: seedHostname}}, preseed.Systems)
+	s.Equal([]string{"microcloud", "waitready"}, config.RunCmd[0])
+
+	// A fixed name doesn't require cloud-init templating.
+	opts.Name = "micro04"
+	seed, err = newNodeSeed(opts)
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(string(seed), "#cloud-config\n"))
+}
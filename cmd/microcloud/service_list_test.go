@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type serviceListSuite struct {
+	suite.Suite
+}
+
+func TestServiceListSuite(t *testing.T) {
+	suite.Run(t, new(serviceListSuite))
+}
+
+func (s *serviceListSuite) Test_serviceListError() {
+	s.NoError(serviceListError(nil))
+	s.NoError(serviceListError(map[types.ServiceType]error{}))
+
+	err := serviceListError(map[types.ServiceType]error{
+		types.MicroOVN:  errors.New("connection refused"),
+		types.MicroCeph: errors.New("timeout"),
+	})
+
+	s.EqualError(err, "Failed to list the cluster members of some services:\n  - MicroCeph: timeout\n  - MicroOVN: connection refused")
+}
+
+func (s *serviceListSuite) Test_selectServices() {
+	optional := map[types.ServiceType]string{types.MicroCeph: api.MicroCephDir, types.MicroOVN: api.MicroOVNDir}
+
+	selected, err := selectServices(optional, nil, nil)
+	s.NoError(err)
+	s.Equal(optional, selected)
+
+	selected, err = selectServices(optional, []string{"lxd", "MicroCeph"}, nil)
+	s.NoError(err)
+	s.Equal(map[types.ServiceType]string{types.MicroCeph: api.MicroCephDir}, selected)
+
+	selected, err = selectServices(optional, nil, []string{"microceph"})
+	s.NoError(err)
+	s.Equal(map[types.ServiceType]string{types.MicroOVN: api.MicroOVNDir}, selected)
+
+	_, err = selectServices(optional, []string{"ceph"}, nil)
+	s.EqualError(err, `Unsupported service "ceph" in --only, must be one of lxd, microceph or microovn`)
+
+	_, err = selectServices(optional, nil, []string{"lxd"})
+	s.EqualError(err, "LXD and MicroCloud are always set up and can't be excluded")
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/canonical/microcluster/v3/client"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// passthroughName returns the name of the pass-through subcommand of the given service.
+func passthroughName(serviceType types.ServiceType) string {
+	return strings.TrimPrefix(strings.ToLower(string(serviceType)), "micro")
+}
+
+type cmdServicePassthrough struct {
+	common *CmdControl
+
+	serviceType types.ServiceType
+}
+
+// command returns the subcommand to interact with the API of a MicroCloud managed service.
+func (c *cmdServicePassthrough) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   passthroughName(c.serviceType),
+		Short: fmt.Sprintf("Interact with the %s API through MicroCloud", c.serviceType),
+		RunE:  func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdQuery = cmdServiceQuery{common: c.common, serviceType: c.serviceType}
+	cmd.AddCommand(cmdQuery.command())
+
+	return cmd
+}
+
+type cmdServiceQuery struct {
+	common *CmdControl
+
+	serviceType types.ServiceType
+
+	flagTarget  string
+	flagRequest string
+	flagData    string
+}
+
+// command returns the subcommand to send a raw query to the API of a MicroCloud managed service.
+func (c *cmdServiceQuery) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query <path>",
+		Short: fmt.Sprintf("Send a raw query to the %s API", c.serviceType),
+		Long: fmt.Sprintf(`Send a raw query to the %s API
+
+The query is sent through the local MicroCloud daemon, which forwards it to the %s unix socket.
+Use --target to run the query on another cluster member.
+
+Example:
+  microcloud %s query /1.0/services --target micro02`, c.serviceType, c.serviceType, passthroughName(c.serviceType)),
+		RunE: c.run,
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Cluster member to run the query on")
+	cmd.Flags().StringVarP(&c.flagRequest, "request", "X", "GET", "Action (defaults to GET)"+"``")
+	cmd.Flags().StringVar(&c.flagData, "data", "", "Input data in JSON format"+"``")
+
+	return cmd
+}
+
+// client returns a client to the service API, proxied through MicroCloud.
+func (c *cmdServiceQuery) client() (*client.Client, error) {
+	switch c.serviceType {
+	case types.MicroCeph:
+		cephService, err := service.NewCephService("", "", c.common.FlagMicroCloudDir)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create MicroCeph service: %w", err)
+		}
+
+		return cephService.Client(c.flagTarget)
+	case types.MicroOVN:
+		ovnService, err := service.NewOVNService("", "", c.common.FlagMicroCloudDir)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create MicroOVN service: %w", err)
+		}
+
+		client, err := ovnService.Client()
+		if err != nil {
+			return nil, err
+		}
+
+		if c.flagTarget != "" {
+			client = client.UseTarget(c.flagTarget)
+		}
+
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported service %q", c.serviceType)
+}
+
+// run runs the subcommand to send a raw query to the API of a MicroCloud managed service.
+func (c *cmdServiceQuery) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	path, err := url.Parse(args[0])
+	if err != nil {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid path %q: %w", args[0], err))
+	}
+
+	var data any
+	if c.flagData != "" {
+		if !json.Valid([]byte(c.flagData)) {
+			return withExitCode(ExitCodeUsage, errors.New("Input data is not valid JSON"))
+		}
+
+		data = json.RawMessage(c.flagData)
+	}
+
+	err = checkInitialized(c.common.FlagMicroCloudDir, true, false)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	var metadata any
+	err = client.Query(context.Background(), strings.ToUpper(c.flagRequest), "", path, data, &metadata)
+	if err != nil {
+		return fmt.Errorf("Failed to query %s: %w", c.serviceType, err)
+	}
+
+	if metadata == nil {
+		return nil
+	}
+
+	out, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return fmt.Errorf("Failed to format the response: %w", err)
+	}
+
+	fmt.Println(string(out))
+
+	return nil
+}
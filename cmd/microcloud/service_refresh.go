@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+type cmdServiceRefresh struct {
+	common *CmdControl
+
+	flagTarget  string
+	flagChannel string
+	flagTimeout time.Duration
+	flagForce   bool
+}
+
+// command returns the subcommand to refresh the snap of a service across the cluster.
+func (c *cmdServiceRefresh) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refresh <service>",
+		Short: "Refresh the snap of a service on all cluster members, one at a time",
+		Long: `Refresh the snap of a service on all cluster members, one at a time
+
+The snap of the service (lxd, microceph or microovn) is refreshed on one cluster member after the other.
+After each refresh, the command waits for all cluster members of the service to be online again before moving on to the next member.
+The refresh stops at the first member on which the service doesn't get healthy within the timeout.
+Use --target to only refresh the snap on one cluster member.
+
+The refresh isn't blocked by the automatic refresh holds set with the snap.refresh.hold configuration key,
+but only runs within the maintenance windows unless given --force.`,
+		RunE: c.run,
+
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return []string{"lxd", "microceph", "microovn"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Only refresh the snap on this cluster member")
+	cmd.Flags().StringVar(&c.flagChannel, "channel", "", "Channel to refresh the snap to, instead of the tracked channel"+"``")
+	cmd.Flags().DurationVar(&c.flagTimeout, "timeout", 5*time.Minute, "How long to wait for the service to get healthy after refreshing it on a cluster member"+"``")
+	addMaintenanceForceFlag(cmd, &c.flagForce)
+
+	return cmd
+}
+
+// run runs the subcommand to refresh the snap of a service across the cluster.
+func (c *cmdServiceRefresh) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	serviceType, err := restartableService(args[0])
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	err = checkMaintenanceWindows(cmd.Context(), client, c.flagForce)
+	if err != nil {
+		return err
+	}
+
+	refreshed, err := rollService(cmd.Context(), client, serviceType, "refresh", c.flagTarget, c.flagTimeout, func(ctx context.Context, name string) error {
+		version, err := cloudClient.RefreshService(ctx, client.UseTarget(name), serviceType, types.ServiceRefreshPost{Channel: c.flagChannel})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s on %q is at version %s\n", serviceType, name, version)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(tui.SummarizeResult("Refreshed %s on %d cluster members", serviceType, refreshed))
+
+	return nil
+}
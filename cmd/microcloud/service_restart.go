@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	microClient "github.com/canonical/microcluster/v3/client"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// serviceHealthPollInterval is how often the health of a service is checked after restarting it on a cluster member.
+const serviceHealthPollInterval = 5 * time.Second
+
+// restartableService returns the service with the given case insensitive name, if it can be restarted.
+func restartableService(name string) (types.ServiceType, error) {
+	for serviceType := range service.RestartableServices {
+		if strings.EqualFold(name, string(serviceType)) {
+			return serviceType, nil
+		}
+	}
+
+	return "", fmt.Errorf("Unsupported service %q, must be one of lxd, microceph or microovn", name)
+}
+
+// serviceMembers returns the sorted names of the MicroCloud cluster members which are part of the given service.
+func serviceMembers(statuses []types.Status, serviceType types.ServiceType) []string {
+	names := []string{}
+	for _, s := range statuses {
+		if len(s.Clusters[serviceType]) > 0 {
+			names = append(names, s.Name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// unhealthyMembers returns the sorted names of the cluster members of the given service which any MicroCloud cluster member doesn't see online.
+func unhealthyMembers(statuses []types.Status, serviceType types.ServiceType) []string {
+	names := []string{}
+	for _, s := range statuses {
+		for _, member := range s.Clusters[serviceType] {
+			if member.Status != microTypes.MemberOnline && !slices.Contains(names, member.Name) {
+				names = append(names, member.Name)
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+type cmdServiceRestart struct {
+	common *CmdControl
+
+	flagTarget  string
+	flagTimeout time.Duration
+}
+
+// command returns the subcommand to restart a service across the cluster.
+func (c *cmdServiceRestart) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart <service>",
+		Short: "Restart a service on all cluster members, one at a time",
+		Long: `Restart a service on all cluster members, one at a time
+
+The service (lxd, microceph or microovn) is restarted on one cluster member after the other.
+After each restart, the command waits for all cluster members of the service to be online again before moving on to the next member.
+The restart stops at the first member on which the service doesn't get healthy within the timeout.
+Use --target to only restart the service on one cluster member.`,
+		RunE: c.run,
+
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return []string{"lxd", "microceph", "microovn"}, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Only restart the service on this cluster member")
+	cmd.Flags().DurationVar(&c.flagTimeout, "timeout", 5*time.Minute, "How long to wait for the service to get healthy after restarting it on a cluster member"+"``")
+
+	return cmd
+}
+
+// waitServiceHealthy waits for all cluster members of the given service to be online.
+func waitServiceHealthy(ctx context.Context, client *microClient.Client, serviceType types.ServiceType, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		statuses, err := cloudClient.GetStatus(ctx, client)
+		var unhealthy []string
+		if err == nil {
+			unhealthy = unhealthyMembers(statuses, serviceType)
+			if len(unhealthy) == 0 {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("Failed to get the %s status: %w", serviceType, err)
+			}
+
+			return fmt.Errorf("%s is not online on %s", serviceType, strings.Join(unhealthy, ", "))
+		case <-time.After(serviceHealthPollInterval):
+		}
+	}
+}
+
+// rollService runs the given action for the service on one cluster member of the service after the other, or only on the target member if given.
+// After each member, it waits for the service to be healthy again before moving on, and stops if it doesn't get healthy within the timeout.
+func rollService(ctx context.Context, client *microClient.Client, serviceType types.ServiceType, opType string, target string, timeout time.Duration, action func(ctx context.Context, name string) error) (int, error) {
+	statuses, err := cloudClient.GetStatus(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+
+	members := serviceMembers(statuses, serviceType)
+	if len(members) == 0 {
+		return 0, fmt.Errorf("%s is not set up on any cluster member", serviceType)
+	}
+
+	if target != "" {
+		if !slices.Contains(members, target) {
+			return 0, fmt.Errorf("%s is not set up on cluster member %q", serviceType, target)
+		}
+
+		members = []string{target}
+	}
+
+	// Taking down a member of a service which is already degraded risks taking down the service entirely.
+	unhealthy := unhealthyMembers(statuses, serviceType)
+	if len(unhealthy) > 0 {
+		return 0, fmt.Errorf("%s is not online on %s, resolve this before the %s", serviceType, strings.Join(unhealthy, ", "), opType)
+	}
+
+	// Serialize with other cluster changes, such as a concurrent "microcloud add".
+	release, err := lockClusterChanges(ctx, client, opType, fmt.Sprintf("Rolling %s of %s", opType, serviceType))
+	if err != nil {
+		return 0, err
+	}
+
+	defer release()
+
+	for i, name := range members {
+		fmt.Printf("Running the %s of %s on %q (%d/%d) ...\n", opType, serviceType, name, i+1, len(members))
+
+		err := action(ctx, name)
+		if err != nil {
+			return i, fmt.Errorf("Failed the %s of %s on %q: %w", opType, serviceType, name, err)
+		}
+
+		err = waitServiceHealthy(ctx, client, serviceType, timeout)
+		if err != nil {
+			return i + 1, fmt.Errorf("Stopped the %s after %q: %w", opType, name, err)
+		}
+	}
+
+	return len(members), nil
+}
+
+// run runs the subcommand to restart a service across the cluster.
+func (c *cmdServiceRestart) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return cmd.Help()
+	}
+
+	serviceType, err := restartableService(args[0])
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	restarted, err := rollService(cmd.Context(), client, serviceType, "restart", c.flagTarget, c.flagTimeout, func(ctx context.Context, name string) error {
+		return cloudClient.RestartService(ctx, client.UseTarget(name), serviceType)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(tui.SummarizeResult("Restarted %s on %d cluster members", serviceType, restarted))
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type serviceRestartSuite struct {
+	suite.Suite
+}
+
+func TestServiceRestartSuite(t *testing.T) {
+	suite.Run(t, new(serviceRestartSuite))
+}
+
+func (s *serviceRestartSuite) Test_restartableService() {
+	serviceType, err := restartableService("microovn")
+	s.NoError(err)
+	s.Equal(types.MicroOVN, serviceType)
+
+	serviceType, err = restartableService("LXD")
+	s.NoError(err)
+	s.Equal(types.LXD, serviceType)
+
+	s.T().Log("MicroCloud can't restart itself")
+	_, err = restartableService("microcloud")
+	s.Error(err)
+}
+
+func (s *serviceRestartSuite) Test_serviceHealth() {
+	genMember := func(name string, status microTypes.MemberStatus) microTypes.ClusterMember {
+		return microTypes.ClusterMember{
+			ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: name},
+			Status:             status,
+		}
+	}
+
+	statuses := []types.Status{
+		{
+			Name: "micro02",
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+				types.MicroOVN: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline)},
+			},
+		},
+		{
+			Name: "micro01",
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+				types.MicroOVN: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberUnreachable)},
+			},
+		},
+		{
+			Name:     "micro03",
+			Clusters: map[types.ServiceType][]microTypes.ClusterMember{},
+		},
+	}
+
+	s.Equal([]string{"micro01", "micro02"}, serviceMembers(statuses, types.MicroOVN))
+	s.Empty(serviceMembers(statuses, types.MicroCeph))
+
+	s.T().Log("A member is unhealthy if any member doesn't see it online")
+	s.Equal([]string{"micro02"}, unhealthyMembers(statuses, types.MicroOVN))
+
+	statuses[1].Clusters[types.MicroOVN][1].Status = microTypes.MemberOnline
+	s.Empty(unhealthyMembers(statuses, types.MicroOVN))
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +16,7 @@ import (
 	cli "github.com/canonical/lxd/shared/cmd"
 	"github.com/canonical/microcluster/v3/client"
 	"github.com/canonical/microcluster/v3/microcluster"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
 	"github.com/spf13/cobra"
 
 	"github.com/canonical/microcloud/microcloud/api"
@@ -41,11 +44,28 @@ func (c *cmdServices) command() *cobra.Command {
 	var cmdServiceAdd = cmdServiceAdd{common: c.common}
 	cmd.AddCommand(cmdServiceAdd.command())
 
+	var cmdServiceRestart = cmdServiceRestart{common: c.common}
+	cmd.AddCommand(cmdServiceRestart.command())
+
+	var cmdServiceRefresh = cmdServiceRefresh{common: c.common}
+	cmd.AddCommand(cmdServiceRefresh.command())
+
 	return cmd
 }
 
 type cmdServiceList struct {
 	common *CmdControl
+
+	flagFormat string
+	flagStrict bool
+}
+
+// serviceMember is the machine readable representation of a cluster member of a service.
+type serviceMember struct {
+	Name    string `json:"name"    yaml:"name"`
+	Address string `json:"address" yaml:"address"`
+	Role    string `json:"role"    yaml:"role"`
+	Status  string `json:"status"  yaml:"status"`
 }
 
 // command returns the subcommand to list MicroCloud services.
@@ -56,15 +76,44 @@ func (c *cmdServiceList) command() *cobra.Command {
 		RunE:  c.run,
 	}
 
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+	cmd.Flags().BoolVar(&c.flagStrict, "strict", false, "Fail if the cluster members of any service can't be listed")
+
 	return cmd
 }
 
+// printServiceTable prints the cluster members of a single service in a human readable format.
+func (c *cmdServiceList) printServiceTable(serviceType types.ServiceType, header []string, data [][]string) error {
+	if len(data) == 0 {
+		fmt.Printf("%s: Not initialized\n", serviceType)
+		return nil
+	}
+
+	table, err := tui.FormatData(c.flagFormat, header, data, nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s:\n", serviceType)
+	fmt.Println(table)
+
+	return nil
+}
+
 // run runs the subcommand to list MicroCloud services.
 func (c *cmdServiceList) run(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
 		return cmd.Help()
 	}
 
+	formats := []string{tui.TableFormatCSV, tui.TableFormatJSON, tui.TableFormatTable, tui.TableFormatYAML, tui.TableFormatCompact}
+	if !slices.Contains(formats, c.flagFormat) {
+		return fmt.Errorf("Invalid format (%s)", c.flagFormat)
+	}
+
+	// Human readable tables are printed as soon as the data of each service arrives.
+	streamOutput := c.flagFormat == tui.TableFormatTable || c.flagFormat == tui.TableFormatCompact
+
 	// Get a microcluster client so we can get state information.
 	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
 	if err != nil {
@@ -83,7 +132,7 @@ func (c *cmdServiceList) run(cmd *cobra.Command, args []string) error {
 	}
 
 	if !status.Ready {
-		return errors.New("MicroCloud is uninitialized, run 'microcloud init' first")
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
 	}
 
 	services := []types.ServiceType{types.MicroCloud, types.LXD}
@@ -118,102 +167,252 @@ func (c *cmdServiceList) run(cmd *cobra.Command, args []string) error {
 	mu := sync.Mutex{}
 	header := []string{"NAME", "ADDRESS", "ROLE", "STATUS"}
 	allClusters := map[types.ServiceType][][]string{}
+	failures := map[types.ServiceType]error{}
 	err = s.RunConcurrent("", "", func(s service.Service) error {
-		var err error
-		var data [][]string
-		var microClient *client.Client
-		var lxd lxd.InstanceServer
-		switch s.Type() {
-		case types.LXD:
-			lxd, err = s.(*service.LXDService).Client(context.Background())
-		case types.MicroCeph:
-			microClient, err = s.(*service.CephService).Client("")
-		case types.MicroOVN:
-			microClient, err = s.(*service.OVNService).Client()
-		case types.MicroCloud:
-			microClient, err = s.(*service.CloudService).Client()
+		// A service which can't be reached must not hide the others, so it is listed as unreachable on the local member instead.
+		data, err := listServiceMembers(s)
+		if err != nil {
+			data = [][]string{{cfg.name, cfg.address, "", string(microTypes.MemberUnreachable)}}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			failures[s.Type()] = err
 		}
 
+		if streamOutput {
+			return c.printServiceTable(s.Type(), header, data)
+		}
+
+		allClusters[s.Type()] = data
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !streamOutput {
+		err = c.printServiceRows(header, allClusters)
 		if err != nil {
 			return err
 		}
+	}
 
-		if microClient != nil {
-			clusterMembers, err := microClient.GetClusterMembers(context.Background())
-			if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
-				return err
-			}
+	listErr := serviceListError(failures)
+	if listErr == nil {
+		return nil
+	}
 
-			if len(clusterMembers) != 0 {
-				data = make([][]string, len(clusterMembers))
-				for i, clusterMember := range clusterMembers {
-					data[i] = []string{clusterMember.Name, clusterMember.Address.String(), clusterMember.Role, string(clusterMember.Status)}
-				}
+	if c.flagStrict {
+		return withExitCode(ExitCodeUnreachable, listErr)
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", listErr)
+
+	return nil
+}
+
+// serviceListError returns an error listing the services whose cluster members couldn't be listed, or nil if there are none.
+func serviceListError(failures map[types.ServiceType]error) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(failures))
+	for serviceType, err := range failures {
+		messages = append(messages, fmt.Sprintf("%s: %v", serviceType, err))
+	}
+
+	sort.Strings(messages)
+
+	return errors.New("Failed to list the cluster members of some services:\n  - " + strings.Join(messages, "\n  - "))
+}
+
+// listServiceMembers returns a row with the name, address, role and status of each cluster member of the service.
+// No rows are returned if the service is not initialized.
+func listServiceMembers(s service.Service) ([][]string, error) {
+	var err error
+	var data [][]string
+	var microClient *client.Client
+	var lxd lxd.InstanceServer
+	switch s.Type() {
+	case types.LXD:
+		lxd, err = s.(*service.LXDService).Client(context.Background())
+	case types.MicroCeph:
+		microClient, err = s.(*service.CephService).Client("")
+	case types.MicroOVN:
+		microClient, err = s.(*service.OVNService).Client()
+	case types.MicroCloud:
+		microClient, err = s.(*service.CloudService).Client()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if microClient != nil {
+		clusterMembers, err := microClient.GetClusterMembers(context.Background())
+		if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+			return nil, err
+		}
 
-				sort.Sort(cli.SortColumnsNaturally(data))
+		if len(clusterMembers) != 0 {
+			data = make([][]string, len(clusterMembers))
+			for i, clusterMember := range clusterMembers {
+				data[i] = []string{clusterMember.Name, clusterMember.Address.String(), clusterMember.Role, string(clusterMember.Status)}
 			}
-		} else if lxd != nil {
-			server, _, err := lxd.GetServer()
+
+			sort.Sort(cli.SortColumnsNaturally(data))
+		}
+	} else if lxd != nil {
+		server, _, err := lxd.GetServer()
+		if err != nil {
+			return nil, err
+		}
+
+		if server.Environment.ServerClustered {
+			clusterMembers, err := lxd.GetClusterMembers()
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			if server.Environment.ServerClustered {
-				clusterMembers, err := lxd.GetClusterMembers()
-				if err != nil {
-					return err
-				}
+			data = make([][]string, len(clusterMembers))
+			for i, clusterMember := range clusterMembers {
+				data[i] = []string{clusterMember.ServerName, clusterMember.URL, strings.Join(clusterMember.Roles, "\n"), string(clusterMember.Status)}
+			}
+
+			sort.Sort(cli.SortColumnsNaturally(data))
+		}
+	}
 
-				data = make([][]string, len(clusterMembers))
-				for i, clusterMember := range clusterMembers {
-					data[i] = []string{clusterMember.ServerName, clusterMember.URL, strings.Join(clusterMember.Roles, "\n"), string(clusterMember.Status)}
+	return data, nil
+}
+
+// printServiceRows prints the cluster members of all services in a machine readable format, ordered by service.
+func (c *cmdServiceList) printServiceRows(header []string, allClusters map[types.ServiceType][][]string) error {
+	serviceTypes := make([]string, 0, len(allClusters))
+	for serviceType := range allClusters {
+		serviceTypes = append(serviceTypes, string(serviceType))
+	}
+
+	sort.Strings(serviceTypes)
+
+	rows := [][]string{}
+	raw := make(map[types.ServiceType][]serviceMember, len(allClusters))
+	for _, serviceType := range serviceTypes {
+		members := []serviceMember{}
+		for _, row := range allClusters[types.ServiceType(serviceType)] {
+			members = append(members, serviceMember{Name: row[0], Address: row[1], Role: row[2], Status: row[3]})
+			rows = append(rows, append([]string{serviceType}, row...))
+		}
+
+		raw[types.ServiceType(serviceType)] = members
+	}
+
+	out, err := tui.FormatData(c.flagFormat, append([]string{"SERVICE"}, header...), rows, raw)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(out)
+
+	return nil
+}
+
+// selectServices returns the optional services to set up, keeping only the ones named in only if set, and dropping the ones named in exclude.
+// LXD and MicroCloud are always set up, so they can be named in only but not in exclude.
+func selectServices(optionalServices map[types.ServiceType]string, only []string, exclude []string) (map[types.ServiceType]string, error) {
+	parse := func(flag string, names []string) (map[types.ServiceType]bool, error) {
+		selected := make(map[types.ServiceType]bool, len(names))
+		for _, name := range names {
+			found := false
+			for _, serviceType := range []types.ServiceType{types.MicroCloud, types.LXD, types.MicroCeph, types.MicroOVN} {
+				if strings.EqualFold(name, string(serviceType)) {
+					selected[serviceType] = true
+					found = true
 				}
+			}
 
-				sort.Sort(cli.SortColumnsNaturally(data))
+			if !found {
+				return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Unsupported service %q in --%s, must be one of lxd, microceph or microovn", name, flag))
 			}
 		}
 
-		mu.Lock()
-		allClusters[s.Type()] = data
-		mu.Unlock()
+		return selected, nil
+	}
 
-		return nil
-	})
+	included, err := parse("only", only)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	excluded, err := parse("exclude", exclude)
+	if err != nil {
+		return nil, err
 	}
 
-	for serviceType, data := range allClusters {
-		if len(data) == 0 {
-			fmt.Printf("%s: Not initialized\n", serviceType)
-		} else {
-			fmt.Printf("%s:\n", serviceType)
-			fmt.Println(tui.NewTable(header, data))
+	if excluded[types.LXD] || excluded[types.MicroCloud] {
+		return nil, withExitCode(ExitCodeUsage, errors.New("LXD and MicroCloud are always set up and can't be excluded"))
+	}
+
+	selected := make(map[types.ServiceType]string, len(optionalServices))
+	for serviceType, stateDir := range optionalServices {
+		if len(only) > 0 && !included[serviceType] {
+			continue
+		}
+
+		if excluded[serviceType] {
+			continue
 		}
+
+		selected[serviceType] = stateDir
 	}
 
-	return nil
+	return selected, nil
 }
 
 type cmdServiceAdd struct {
 	common *CmdControl
+
+	flagForce bool
 }
 
 // command returns the subcommand to add services to MicroCloud.
 func (c *cmdServiceAdd) command() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "add",
+		Use:   "add [<service>...]",
 		Short: "Add new services to the existing MicroCloud",
-		RunE:  c.run,
+		Long: `Add new services to the existing MicroCloud
+
+By default, all installed services which are not yet set up are added.
+Specify the services (microceph or microovn) to only add those.`,
+		RunE: c.run,
+
+		ValidArgsFunction: c.common.completeAddableServices,
 	}
 
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, "Add the services despite unsupported service versions or API extensions")
+
 	return cmd
 }
 
 // run runs the subcommand to add services to MicroCloud.
 func (c *cmdServiceAdd) run(cmd *cobra.Command, args []string) error {
-	if len(args) != 0 {
-		return cmd.Help()
+	requested := make(map[types.ServiceType]bool, len(args))
+	for _, arg := range args {
+		found := false
+		for serviceType := range addableServices {
+			if strings.EqualFold(arg, string(serviceType)) {
+				requested[serviceType] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return withExitCode(ExitCodeUsage, fmt.Errorf("Unsupported service %q, must be one of microceph or microovn", arg))
+		}
 	}
 
 	fmt.Println("Waiting for services to start ...")
@@ -230,6 +429,7 @@ func (c *cmdServiceAdd) run(cmd *cobra.Command, args []string) error {
 		asker:     c.common.asker,
 		systems:   map[string]InitSystem{},
 		state:     map[string]service.SystemInformation{},
+		force:     c.flagForce,
 	}
 
 	// Get a microcluster client so we can get state information.
@@ -244,6 +444,19 @@ func (c *cmdServiceAdd) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
 	}
 
+	localClient, err := cloudApp.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	// Serialize with other cluster changes, such as a concurrent "microcloud add".
+	release, err := lockClusterChanges(context.Background(), localClient, "service add", "Add services")
+	if err != nil {
+		return err
+	}
+
+	defer release()
+
 	cfg.name = status.Name
 	cfg.address = status.Address.Addr().String()
 	// enable auto setup to skip lookup related questions.
@@ -255,13 +468,16 @@ func (c *cmdServiceAdd) run(cmd *cobra.Command, args []string) error {
 
 	cfg.autoSetup = false
 	installedServices := []types.ServiceType{types.MicroCloud, types.LXD}
-	optionalServices := map[types.ServiceType]string{
-		types.MicroCeph: api.MicroCephDir,
-		types.MicroOVN:  api.MicroOVNDir,
+	// Set the auto flag to true so that we automatically omit any services that aren't installed.
+	// Only look for the requested services, so that missing services which aren't being added are not asked about.
+	stateDirs := make(map[types.ServiceType]string, len(addableServices))
+	for serviceType, stateDir := range addableServices {
+		if len(requested) == 0 || requested[serviceType] {
+			stateDirs[serviceType] = stateDir
+		}
 	}
 
-	// Set the auto flag to true so that we automatically omit any services that aren't installed.
-	installedServices, err = cfg.askMissingServices(installedServices, optionalServices)
+	installedServices, err = cfg.askMissingServices(installedServices, stateDirs)
 	if err != nil {
 		return err
 	}
@@ -272,17 +488,23 @@ func (c *cmdServiceAdd) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	services := make(map[types.ServiceType]string, len(installedServices))
-	for _, s := range s.Services {
-		version, err := s.GetVersion(context.Background())
-		if err != nil {
-			return err
-		}
+	// Set up the storage pools and networks like the ones set up with MicroCloud.
+	err = cfg.loadSetupConfig(context.Background(), s)
+	if err != nil {
+		return err
+	}
 
-		services[s.Type()] = version
+	services, err := s.GetVersions(context.Background())
+	if err != nil {
+		return err
 	}
 
-	state, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: cfg.name, Address: cfg.address, Services: services})
+	// The disks of the systems are only needed to set up MicroCeph.
+	_, addsMicroCeph := s.Services[types.MicroCeph]
+	cfg.collectOptions = service.CollectOptions{SkipStorage: !addsMicroCeph}
+
+	// The information of each system is collected once, and reused for all of the questions.
+	state, err := s.CollectSystemInformation(context.Background(), multicast.ServerInfo{Name: cfg.name, Address: cfg.address, Services: services}, cfg.collectOptions)
 	if err != nil {
 		return err
 	}
@@ -300,17 +522,27 @@ func (c *cmdServiceAdd) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	peers := make([]multicast.ServerInfo, 0, len(cfg.systems))
 	for _, system := range cfg.systems {
 		if system.ServerInfo.Name == "" || system.ServerInfo.Name == cfg.name {
 			continue
 		}
 
-		state, err := s.CollectSystemInformation(context.Background(), system.ServerInfo)
-		if err != nil {
-			return err
-		}
+		peers = append(peers, system.ServerInfo)
+	}
+
+	peerStates, err := s.CollectSystemInformationConcurrent(context.Background(), peers, cfg.collectOptions)
+	if err != nil {
+		return err
+	}
 
-		cfg.state[system.ServerInfo.Name] = *state
+	for name, state := range peerStates {
+		cfg.state[name] = *state
+	}
+
+	err = cfg.checkCompatibility(s)
+	if err != nil {
+		return err
 	}
 
 	askClusteredServices := map[types.ServiceType]string{}
@@ -321,17 +553,31 @@ func (c *cmdServiceAdd) run(cmd *cobra.Command, args []string) error {
 			return errors.New("Unable to add services. Some systems are not part of the LXD cluster")
 		}
 
-		if len(state.ExistingServices[types.MicroCeph]) <= 0 && !serviceMap[types.MicroCeph] {
+		if services[types.MicroCeph] != "" && len(state.ExistingServices[types.MicroCeph]) <= 0 && !serviceMap[types.MicroCeph] {
 			askClusteredServices[types.MicroCeph] = services[types.MicroCeph]
 			serviceMap[types.MicroCeph] = true
 		}
 
-		if len(state.ExistingServices[types.MicroOVN]) <= 0 && !serviceMap[types.MicroOVN] {
+		if services[types.MicroOVN] != "" && len(state.ExistingServices[types.MicroOVN]) <= 0 && !serviceMap[types.MicroOVN] {
 			askClusteredServices[types.MicroOVN] = services[types.MicroOVN]
 			serviceMap[types.MicroOVN] = true
 		}
 	}
 
+	if len(requested) > 0 {
+		for serviceType := range requested {
+			if askClusteredServices[serviceType] == "" {
+				return fmt.Errorf("%s is either not installed or already set up", serviceType)
+			}
+		}
+
+		for serviceType := range askClusteredServices {
+			if !requested[serviceType] {
+				delete(askClusteredServices, serviceType)
+			}
+		}
+	}
+
 	if len(askClusteredServices) == 0 {
 		return errors.New("All services have already been set up")
 	}
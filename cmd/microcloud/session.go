@@ -32,22 +32,55 @@ func (c *initConfig) runSession(ctx context.Context, s *service.Handler, role ty
 	return f(cloudClient.NewWebsocketGateway(ctx, conn))
 }
 
+// attachInitiatingSession attaches to the initiating session which lost its client, and returns the details the session was started with.
+// The returned function closes the connection to the session.
+func (c *initConfig) attachInitiatingSession(ctx context.Context) (*cloudClient.WebsocketGateway, types.Session, func(), error) {
+	cloud, err := service.NewCloudService(c.name, "", c.common.FlagMicroCloudDir)
+	if err != nil {
+		return nil, types.Session{}, nil, err
+	}
+
+	conn, err := cloud.AttachSession(ctx, c.attachSession, c.sessionTimeout)
+	if err != nil {
+		return nil, types.Session{}, nil, err
+	}
+
+	gw := cloudClient.NewWebsocketGateway(ctx, conn)
+
+	var session types.Session
+	err = gw.ReceiveWithContext(gw.Context(), &session)
+	if err != nil {
+		_ = conn.Close()
+		return nil, types.Session{}, nil, fmt.Errorf("Failed to attach to session %q: %w", c.attachSession, err)
+	}
+
+	fmt.Println(tui.SummarizeResult("Attached to session %s", session.ID))
+
+	return gw, session, func() { _ = conn.Close() }, nil
+}
+
 func (c *initConfig) initiatingSession(gw *cloudClient.WebsocketGateway, sh *service.Handler, services map[types.ServiceType]string, passphrase string, expectedSystems []string) error {
 	session := types.Session{
+		ID:         c.attachSession,
 		Address:    c.address,
+		AltAddress: c.altAddress,
 		Interface:  c.lookupIface.Name,
 		Services:   services,
 		Passphrase: passphrase,
 	}
 
-	err := gw.Write(session)
-	if err != nil {
-		return fmt.Errorf("Failed to send session start: %w", err)
-	}
+	// An attached session is already started.
+	var err error
+	if c.attachSession == "" {
+		err = gw.Write(session)
+		if err != nil {
+			return fmt.Errorf("Failed to send session start: %w", err)
+		}
 
-	err = gw.ReceiveWithContext(gw.Context(), &session)
-	if err != nil {
-		return fmt.Errorf("Failed to read session reply: %w", err)
+		err = gw.ReceiveWithContext(gw.Context(), &session)
+		if err != nil {
+			return fmt.Errorf("Failed to read session reply: %w", err)
+		}
 	}
 
 	if !c.autoSetup {
@@ -93,6 +126,11 @@ Verify the fingerprint %s is displayed on joining systems.
 		passArg := tui.Fmt{Arg: session.Passphrase, Color: tui.Green, Bold: true}
 		fingerprintArg := tui.Fmt{Arg: fingerprint, Color: tui.Green, Bold: true}
 		fmt.Print(tui.Printf(tui.Fmt{Arg: template}, cmdArg, passArg, fingerprintArg))
+
+		if c.bootstrap && session.ID != "" {
+			attachArg := tui.Fmt{Arg: "microcloud init --attach " + session.ID, Bold: true}
+			fmt.Println(tui.Printf(tui.Fmt{Arg: "If this terminal gets disconnected, continue from another one with %s\n"}, attachArg))
+		}
 	}
 
 	confirmedIntents, err := c.askJoinIntents(gw, expectedSystems)
@@ -169,6 +207,7 @@ func (c *initConfig) joiningSession(gw *cloudClient.WebsocketGateway, sh *servic
 	session := types.Session{
 		Passphrase:       passphrase,
 		Address:          sh.Address(),
+		AltAddress:       c.altAddress,
 		InitiatorAddress: initiatorAddress,
 		Interface:        c.lookupIface.Name,
 		Services:         services,
@@ -200,6 +239,10 @@ func (c *initConfig) joiningSession(gw *cloudClient.WebsocketGateway, sh *servic
 		fingerprintArg := tui.SetColor(tui.Green, fingerprint, true)
 		fmt.Printf("\n%s %s\n\n", tmpl, fingerprintArg)
 
+		if session.Address != "" && session.Address != sh.Address() {
+			fmt.Printf("%s\n\n", tui.SummarizeResult("Using address %s for MicroCloud, as %s is reachable with its IP family", session.Address, session.InitiatorName))
+		}
+
 		tmplArg := tui.Fmt{Arg: "Select system %s on %s to let it join the cluster."}
 		localArg := tui.Fmt{Arg: sh.Name, Bold: true}
 		remoteArg := tui.Fmt{Arg: session.InitiatorName, Bold: true}
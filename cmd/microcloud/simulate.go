@@ -0,0 +1,280 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/multicast"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// maxSimulatedSystems is the largest number of systems which can be simulated.
+const maxSimulatedSystems = 64
+
+// validateSimulatedSystems checks the number of systems to simulate.
+func validateSimulatedSystems(count int) error {
+	if count < 1 || count > maxSimulatedSystems {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("The number of simulated systems must be between 1 and %d", maxSimulatedSystems))
+	}
+
+	return nil
+}
+
+// simulationRows returns a row with the disks and uplink interface selected for each system, sorted by name.
+func simulationRows(systems map[string]InitSystem, names service.ResourceNames) [][]string {
+	rows := make([][]string, 0, len(systems))
+	for name, system := range systems {
+		localDisk := ""
+		uplink := ""
+		for _, pool := range system.TargetStoragePools {
+			if pool.Name == names.LocalPool {
+				localDisk = pool.Config["source"]
+			}
+		}
+
+		for _, network := range system.TargetNetworks {
+			if network.Name == names.UplinkNetwork {
+				uplink = network.Config["parent"]
+			}
+		}
+
+		if system.NoUplink {
+			uplink = ""
+		}
+
+		cephDisks := []string{}
+		for _, disk := range system.MicroCephDisks {
+			cephDisks = append(cephDisks, disk.Path...)
+		}
+
+		rows = append(rows, []string{name, system.ServerInfo.Address, localDisk, strings.Join(cephDisks, "\n"), uplink})
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	return rows
+}
+
+// printSimulation prints what would be set up on each simulated system.
+func printSimulation(systems map[string]InitSystem, names service.ResourceNames) error {
+	header := []string{"NAME", "ADDRESS", "LOCAL DISK", "CEPH DISKS", "UPLINK"}
+	out, err := tui.FormatData(tui.TableFormatTable, header, simulationRows(systems, names), nil)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(out)
+	fmt.Println(tui.SummarizeResult("Simulation complete, nothing was set up"))
+
+	return nil
+}
+
+// runSimulation asks the questions of the interactive setup for the given number of simulated systems, and prints the resulting configuration instead of setting it up.
+// The local system is the first simulated system, and all services are simulated as installed.
+func (c *initConfig) runSimulation(count int) error {
+	err := validateSimulatedSystems(count)
+	if err != nil {
+		return err
+	}
+
+	tui.PrintWarning(fmt.Sprintf("Simulating %d systems, nothing will be set up", count))
+
+	systemNames := service.SimulatedSystemNames(count)
+	c.state = service.SimulatedSystems(systemNames)
+	c.name = systemNames[0]
+	c.address = c.state[c.name].ClusterAddress
+	c.setupMany = count > 1
+	c.lookupIface = &net.Interface{Name: service.SimulatedInterface}
+	_, c.lookupSubnet, err = net.ParseCIDR(service.SimulatedSubnet)
+	if err != nil {
+		return err
+	}
+
+	optionalServices, err := selectServices(map[types.ServiceType]string{types.MicroCeph: "", types.MicroOVN: ""}, c.onlyServices, c.excludedServices)
+	if err != nil {
+		return err
+	}
+
+	installedServices := []types.ServiceType{types.MicroCloud, types.LXD}
+	versions := map[types.ServiceType]string{types.MicroCloud: service.SimulatedVersion, types.LXD: service.SimulatedVersion}
+	for serviceType := range optionalServices {
+		installedServices = append(installedServices, serviceType)
+		versions[serviceType] = service.SimulatedVersion
+	}
+
+	sh, err := service.NewSimulatedHandler(c.name, c.address, installedServices...)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range systemNames {
+		state := c.state[name]
+		system := InitSystem{ServerInfo: multicast.ServerInfo{Name: name, Address: state.ClusterAddress, Services: versions}}
+		err := populateMicroCloudNetworkFromState(&state, name, &system, c.lookupSubnet)
+		if err != nil {
+			return err
+		}
+
+		c.systems[name] = system
+	}
+
+	err = c.askResourceNames(sh)
+	if err != nil {
+		return err
+	}
+
+	for _, ask := range []func(sh *service.Handler) error{c.askDisks, c.askNetwork, c.askVolumes} {
+		err := ask(sh)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.askImages()
+	if err != nil {
+		return err
+	}
+
+	err = c.askSnapshots()
+	if err != nil {
+		return err
+	}
+
+	err = c.askTuning()
+	if err != nil {
+		return err
+	}
+
+	err = c.validateSystems(sh)
+	if err != nil {
+		return err
+	}
+
+	return printSimulation(c.systems, sh.Services[types.LXD].(*service.LXDService).ResourceNames())
+}
+
+// simulatePreseed validates the preseed, and prints the disks its filters match on simulated systems named after the systems of the preseed.
+func (c *initConfig) simulatePreseed(config Preseed) error {
+	if len(config.Systems) > maxSimulatedSystems {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("At most %d systems can be simulated", maxSimulatedSystems))
+	}
+
+	systemNames := make([]string, 0, len(config.Systems))
+	for _, system := range config.Systems {
+		systemNames = append(systemNames, system.Name)
+	}
+
+	initiator := config.Initiator
+	if initiator == "" {
+		for _, system := range config.Systems {
+			if system.Address == config.InitiatorAddress {
+				initiator = system.Name
+			}
+		}
+	}
+
+	if initiator == "" {
+		return withExitCode(ExitCodeValidation, errors.New("Failed to find the initiator among the systems of the preseed"))
+	}
+
+	tui.PrintWarning(fmt.Sprintf("Simulating %d systems, nothing will be set up", len(systemNames)))
+
+	err := config.validate(initiator, true)
+	if err != nil {
+		return withExitCode(ExitCodeValidation, err)
+	}
+
+	names := config.Names.resourceNames()
+	states := service.SimulatedSystems(systemNames)
+	for _, system := range config.Systems {
+		state := states[system.Name]
+		disks := slices.Clone(state.Resources.Storage.Disks)
+		initSystem := InitSystem{ServerInfo: multicast.ServerInfo{Name: system.Name, Address: system.Address}, NoUplink: system.NoUplink}
+
+		cephDisks := []string{}
+		for _, disk := range system.Storage.Ceph {
+			cephDisks = append(cephDisks, disk.Path)
+		}
+
+		if len(cephDisks) == 0 {
+			cephDisks, disks, err = matchSimulatedDisks(config.Storage.Ceph, disks, false)
+			if err != nil {
+				return fmt.Errorf("Failed to apply filter for ceph disks: %w", err)
+			}
+		}
+
+		for _, path := range cephDisks {
+			initSystem.MicroCephDisks = append(initSystem.MicroCephDisks, cephTypes.DisksPost{Path: []string{path}})
+		}
+
+		localDisk := system.Storage.Local.Path
+		if localDisk == "" {
+			matched, _, err := matchSimulatedDisks(config.Storage.Local, disks, true)
+			if err != nil {
+				return fmt.Errorf("Failed to apply filter for local disks: %w", err)
+			}
+
+			if len(matched) > 0 {
+				localDisk = matched[0]
+			}
+		}
+
+		if localDisk != "" {
+			initSystem.TargetStoragePools = append(initSystem.TargetStoragePools, lxdAPI.StoragePoolsPost{Name: names.LocalPool, StoragePoolPut: lxdAPI.StoragePoolPut{Config: map[string]string{"source": localDisk}}})
+		}
+
+		uplink := system.UplinkInterface
+		if system.UplinkSelector != nil {
+			uplink, err = system.UplinkSelector.selectInterface(state.Resources, state.AvailableUplinkInterfaces)
+			if err != nil {
+				return withExitCode(ExitCodeValidation, fmt.Errorf("Failed to select the uplink interface of %q: %w", system.Name, err))
+			}
+		}
+
+		if uplink == "" && !system.NoUplink {
+			uplink = slices.Sorted(maps.Keys(state.AvailableUplinkInterfaces))[0]
+		}
+
+		initSystem.TargetNetworks = append(initSystem.TargetNetworks, lxdAPI.NetworksPost{Name: names.UplinkNetwork, NetworkPut: lxdAPI.NetworkPut{Config: map[string]string{"parent": uplink}}})
+		c.systems[system.Name] = initSystem
+	}
+
+	return printSimulation(c.systems, names)
+}
+
+// matchSimulatedDisks applies the disk filters to the disks of a simulated system in turn, and returns the paths of the matched disks along with the remaining disks.
+// With first, only the disk matched by the first matching filter is returned.
+func matchSimulatedDisks(filters []DiskFilter, disks []lxdAPI.ResourcesStorageDisk, first bool) ([]string, []lxdAPI.ResourcesStorageDisk, error) {
+	paths := []string{}
+	for _, filter := range filters {
+		matched, err := filter.Match(disks)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(matched) == 0 {
+			continue
+		}
+
+		if first {
+			return []string{service.FormatDiskPath(matched[0])}, disks, nil
+		}
+
+		for _, disk := range matched {
+			paths = append(paths, service.FormatDiskPath(disk))
+			disks = slices.DeleteFunc(disks, func(d lxdAPI.ResourcesStorageDisk) bool { return d.ID == disk.ID })
+		}
+	}
+
+	return paths, disks, nil
+}
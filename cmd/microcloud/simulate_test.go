@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/multicast"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type simulateSuite struct {
+	suite.Suite
+}
+
+func TestSimulateSuite(t *testing.T) {
+	suite.Run(t, new(simulateSuite))
+}
+
+func (s *simulateSuite) Test_validateSimulatedSystems() {
+	s.NoError(validateSimulatedSystems(1))
+	s.NoError(validateSimulatedSystems(maxSimulatedSystems))
+	s.Error(validateSimulatedSystems(0))
+	s.Error(validateSimulatedSystems(-1))
+	s.Error(validateSimulatedSystems(maxSimulatedSystems + 1))
+}
+
+func (s *simulateSuite) Test_matchSimulatedDisks() {
+	cases := []struct {
+		desc      string
+		filters   []DiskFilter
+		first     bool
+		paths     []string
+		remaining int
+		err       bool
+	}{
+		{
+			desc:      "No filters",
+			paths:     []string{},
+			remaining: 3,
+		},
+		{
+			desc:      "Match all SSDs",
+			filters:   []DiskFilter{{Find: "type == scsi"}},
+			paths:     []string{"/dev/disk/by-id/scsi-Simulated_SSD_micro01_1", "/dev/disk/by-id/scsi-Simulated_SSD_micro01_2"},
+			remaining: 1,
+		},
+		{
+			desc:      "Filters apply in turn to the remaining disks",
+			filters:   []DiskFilter{{Find: "type == nvme"}, {Find: "size > 1GiB"}},
+			paths:     []string{"/dev/disk/by-id/nvme-Simulated_NVMe_micro01", "/dev/disk/by-id/scsi-Simulated_SSD_micro01_1", "/dev/disk/by-id/scsi-Simulated_SSD_micro01_2"},
+			remaining: 0,
+		},
+		{
+			desc:      "First matching disk only",
+			filters:   []DiskFilter{{Find: "type == hdd"}, {Find: "type == scsi"}},
+			first:     true,
+			paths:     []string{"/dev/disk/by-id/scsi-Simulated_SSD_micro01_1"},
+			remaining: 3,
+		},
+		{
+			desc:    "Invalid filter",
+			filters: []DiskFilter{{Find: "type =="}},
+			err:     true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		disks := service.SimulatedSystems([]string{"micro01"})["micro01"].Resources.Storage.Disks
+		paths, remaining, err := matchSimulatedDisks(c.filters, disks, c.first)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.paths, paths)
+		s.Len(remaining, c.remaining)
+	}
+}
+
+func (s *simulateSuite) Test_simulationRows() {
+	names := service.DefaultResourceNames()
+	systems := map[string]InitSystem{
+		"micro02": {
+			ServerInfo: multicast.ServerInfo{Name: "micro02", Address: "10.0.1.12"},
+			NoUplink:   true,
+			TargetNetworks: []lxdAPI.NetworksPost{
+				{Name: names.UplinkNetwork, NetworkPut: lxdAPI.NetworkPut{Config: map[string]string{"parent": service.NoUplinkBridge}}},
+			},
+		},
+		"micro01": {
+			ServerInfo: multicast.ServerInfo{Name: "micro01", Address: "10.0.1.11"},
+			TargetStoragePools: []lxdAPI.StoragePoolsPost{
+				{Name: names.LocalPool, StoragePoolPut: lxdAPI.StoragePoolPut{Config: map[string]string{"source": "/dev/sda"}}},
+			},
+			TargetNetworks: []lxdAPI.NetworksPost{
+				{Name: names.UplinkNetwork, NetworkPut: lxdAPI.NetworkPut{Config: map[string]string{"parent": "enp7s0"}}},
+			},
+			MicroCephDisks: []cephTypes.DisksPost{{Path: []string{"/dev/sdb"}}, {Path: []string{"/dev/sdc"}}},
+		},
+	}
+
+	s.Equal([][]string{
+		{"micro01", "10.0.1.11", "/dev/sda", "/dev/sdb\n/dev/sdc", "enp7s0"},
+		{"micro02", "10.0.1.12", "", "", ""},
+	}, simulationRows(systems, names))
+}
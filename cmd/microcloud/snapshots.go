@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+const (
+	// defaultSnapshotSchedule is the snapshot schedule of instances offered when setting up MicroCloud.
+	defaultSnapshotSchedule = "@daily"
+
+	// defaultSnapshotExpiry is how long the scheduled snapshots of instances are kept by default.
+	defaultSnapshotExpiry = "2w"
+)
+
+// snapshotScheduleAliases are the aliases LXD accepts in place of a cron pattern for the snapshot schedule.
+var snapshotScheduleAliases = []string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@startup", "@never"}
+
+// SnapshotOptions represents the default snapshot schedule of instances in the preseed yaml.
+type SnapshotOptions struct {
+	Schedule string `yaml:"schedule"`
+	Expiry   string `yaml:"expiry"`
+}
+
+// validateSnapshotExpiry validates an expiry expression like "1d 2w".
+func validateSnapshotExpiry(value string) error {
+	_, err := shared.GetExpiry(time.Time{}, value)
+	return err
+}
+
+// validate validates the snapshots config.
+func (s SnapshotOptions) validate() error {
+	err := validate.Optional(validate.IsCron(snapshotScheduleAliases))(s.Schedule)
+	if err != nil {
+		return fmt.Errorf("Invalid snapshot schedule: %w", err)
+	}
+
+	err = validate.Optional(validateSnapshotExpiry)(s.Expiry)
+	if err != nil {
+		return fmt.Errorf("Invalid snapshot expiry: %w", err)
+	}
+
+	return nil
+}
+
+// profileConfig returns the configuration of the default profile for the snapshot schedule.
+func (s SnapshotOptions) profileConfig() map[string]string {
+	return nonEmptyConfig(map[string]string{
+		"snapshots.schedule": s.Schedule,
+		"snapshots.expiry":   s.Expiry,
+	})
+}
+
+// askSnapshots asks whether to snapshot instances on a schedule, so their data is protected by default.
+func (c *initConfig) askSnapshots() error {
+	if !c.bootstrap || len(c.systems[c.name].StoragePools) == 0 {
+		return nil
+	}
+
+	wantsSnapshots, err := c.asker.AskBool("Would you like to take scheduled snapshots of instances?", true)
+	if err != nil {
+		return err
+	}
+
+	if !wantsSnapshots {
+		return nil
+	}
+
+	schedule, err := c.asker.AskString("What schedule should the snapshots follow? (cron pattern or alias like @daily)", defaultSnapshotSchedule, validate.IsCron(snapshotScheduleAliases))
+	if err != nil {
+		return err
+	}
+
+	expiry, err := c.asker.AskString("How long should the snapshots be kept? (e.g. 2w, empty to keep them)", defaultSnapshotExpiry, validate.Optional(validateSnapshotExpiry))
+	if err != nil {
+		return err
+	}
+
+	c.snapshots = SnapshotOptions{Schedule: schedule, Expiry: expiry}
+
+	return nil
+}
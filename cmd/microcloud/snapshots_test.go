@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type snapshotsSuite struct {
+	suite.Suite
+}
+
+func TestSnapshotsSuite(t *testing.T) {
+	suite.Run(t, new(snapshotsSuite))
+}
+
+func (s *snapshotsSuite) Test_snapshotOptions() {
+	cases := []struct {
+		desc      string
+		snapshots SnapshotOptions
+		config    map[string]string
+		err       bool
+	}{
+		{
+			desc:      "No snapshot schedule",
+			snapshots: SnapshotOptions{},
+			config:    map[string]string{},
+		},
+		{
+			desc:      "Daily snapshots kept for two weeks",
+			snapshots: SnapshotOptions{Schedule: "@daily", Expiry: "2w"},
+			config:    map[string]string{"snapshots.schedule": "@daily", "snapshots.expiry": "2w"},
+		},
+		{
+			desc:      "Cron pattern without expiry",
+			snapshots: SnapshotOptions{Schedule: "0 */6 * * *"},
+			config:    map[string]string{"snapshots.schedule": "0 */6 * * *"},
+		},
+		{
+			desc:      "Invalid schedule",
+			snapshots: SnapshotOptions{Schedule: "@sometimes"},
+			err:       true,
+		},
+		{
+			desc:      "Invalid expiry",
+			snapshots: SnapshotOptions{Schedule: "@daily", Expiry: "2 fortnights"},
+			err:       true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := c.snapshots.validate()
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.config, c.snapshots.profileConfig())
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/canonical/microcluster/v3/microcluster"
 	"github.com/spf13/cobra"
 
 	"github.com/canonical/microcloud/microcloud/cmd/tui"
@@ -20,6 +19,8 @@ func (c *cmdSQL) command() *cobra.Command {
 		Use:   "sql <query>",
 		Short: "Run a SQL query against the daemon",
 		RunE:  c.run,
+
+		Annotations: map[string]string{contextAnnotation: "true"},
 	}
 
 	return cmd
@@ -38,8 +39,7 @@ func (c *cmdSQL) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	options := microcluster.Args{StateDir: c.common.FlagMicroCloudDir}
-	m, err := microcluster.App(options)
+	m, err := c.common.app()
 	if err != nil {
 		return err
 	}
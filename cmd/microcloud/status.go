@@ -7,7 +7,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	microClient "github.com/canonical/microcluster/v3/client"
 	"github.com/canonical/microcluster/v3/microcluster"
 	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
 	"github.com/spf13/cobra"
@@ -90,6 +92,11 @@ func (s StatusLevel) String() string {
 
 type cmdStatus struct {
 	common *CmdControl
+
+	flagTarget      string
+	flagWarnings    bool
+	flagAll         bool
+	flagAcknowledge []string
 }
 
 // command returns the subcommand for the deployment status.
@@ -98,8 +105,15 @@ func (c *cmdStatus) command() *cobra.Command {
 		Use:   "status",
 		Short: "Deployment status with configuration warnings",
 		RunE:  c.run,
+
+		Annotations: map[string]string{contextAnnotation: "true"},
 	}
 
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Cluster member to show the status as seen by, or to show the warnings of")
+	cmd.Flags().BoolVar(&c.flagWarnings, "warnings", false, "Show the warnings raised by LXD, Ceph and OVN on all cluster members")
+	cmd.Flags().BoolVar(&c.flagAll, "all", false, "Include acknowledged warnings (with --warnings)")
+	cmd.Flags().StringSliceVar(&c.flagAcknowledge, "acknowledge", nil, "Acknowledge the warnings with the given IDs, so they are no longer shown")
+
 	return cmd
 }
 
@@ -109,7 +123,15 @@ func (c *cmdStatus) run(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	}
 
-	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if c.flagWarnings || len(c.flagAcknowledge) > 0 {
+		return c.runWarnings(cmd.Context())
+	}
+
+	if c.flagAll {
+		return withExitCode(ExitCodeUsage, errors.New("--all can only be used with --warnings"))
+	}
+
+	cloudApp, err := c.common.app()
 	if err != nil {
 		return err
 	}
@@ -125,7 +147,7 @@ func (c *cmdStatus) run(cmd *cobra.Command, args []string) error {
 	}
 
 	if !status.Ready {
-		return errors.New("MicroCloud is uninitialized, run 'microcloud init' first")
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
 	}
 
 	cfg := initConfig{
@@ -140,36 +162,36 @@ func (c *cmdStatus) run(cmd *cobra.Command, args []string) error {
 	cfg.name = status.Name
 	cfg.address = status.Address.Addr().String()
 
-	services := []types.ServiceType{types.MicroCloud, types.LXD}
-	optionalServices := map[types.ServiceType]string{
-		types.MicroCeph: api.MicroCephDir,
-		types.MicroOVN:  api.MicroOVNDir,
-	}
-
-	services, err = cfg.askMissingServices(services, optionalServices)
+	cloudClient, err := c.cloudClient(cloudApp, &cfg)
 	if err != nil {
 		return err
 	}
 
-	// Instantiate a handler for the services.
-	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, services...)
+	// Query the status through the target member, so the warnings are compiled from its point of view.
+	if c.flagTarget != "" {
+		cloudClient = cloudClient.UseTarget(c.flagTarget)
+		cfg.name = c.flagTarget
+	}
+
+	// Query the status API for the cluster.
+	statuses, err := client.GetStatus(context.Background(), cloudClient)
 	if err != nil {
 		return err
 	}
 
-	cloudClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	config, err := client.GetConfig(context.Background(), cloudClient)
 	if err != nil {
 		return err
 	}
 
-	// Query the status API for the cluster.
-	statuses, err := client.GetStatus(context.Background(), cloudClient)
+	expected, err := service.ParseExpectedMembers(config[types.ConfigMembersExpected])
 	if err != nil {
-		return err
+		return fmt.Errorf("Failed to parse the expected cluster members: %w", err)
 	}
 
 	// compile all warning messages.
 	warnings := compileWarnings(cfg.name, statuses)
+	warnings = append(warnings, expectedMemberWarnings(cfg.name, statuses, expected)...)
 
 	// Print the warning summary, and all warnings.
 	fmt.Println("")
@@ -183,7 +205,7 @@ func (c *cmdStatus) run(cmd *cobra.Command, args []string) error {
 		fmt.Println("")
 	}
 
-	headers := []string{"Name", "Address", "OSDs", "MicroCeph Units", "MicroOVN Units", "Status"}
+	headers := []string{"Name", "Address", "Platform", "OSDs", "MicroCeph Units", "MicroOVN Units", "Status"}
 
 	statusByName := make(map[string]types.Status, len(statuses))
 	var localStatus types.Status
@@ -227,6 +249,33 @@ func (c *cmdStatus) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// cloudClient returns the client of the MicroCloud to query the status of.
+// The services installed on this system are only checked for the local MicroCloud.
+func (c *cmdStatus) cloudClient(cloudApp *microcluster.MicroCluster, cfg *initConfig) (*microClient.Client, error) {
+	if c.common.contextName() != "" {
+		return cloudApp.LocalClient()
+	}
+
+	services := []types.ServiceType{types.MicroCloud, types.LXD}
+	optionalServices := map[types.ServiceType]string{
+		types.MicroCeph: api.MicroCephDir,
+		types.MicroOVN:  api.MicroOVNDir,
+	}
+
+	services, err := cfg.askMissingServices(services, optionalServices)
+	if err != nil {
+		return nil, err
+	}
+
+	// Instantiate a handler for the services.
+	sh, err := service.NewHandler(cfg.name, cfg.address, c.common.FlagMicroCloudDir, services...)
+	if err != nil {
+		return nil, err
+	}
+
+	return sh.Services[types.MicroCloud].(*service.CloudService).Client()
+}
+
 // compileWarnings returns a set of warnings based on the given set of statuses. The name supplied should be the local cluster name.
 func compileWarnings(name string, statuses []types.Status) Warnings {
 	// Systems that exist in other clusters but not in MicroCloud.
@@ -244,6 +293,25 @@ func compileWarnings(name string, statuses []types.Status) Warnings {
 	// Systems that are offline on at least one service.
 	offlineSystems := map[string][]string{}
 
+	// OVN underlay paths the watchdog found unreachable, by system.
+	brokenPaths := map[string][]string{}
+
+	// Systems the daemon heartbeats found unreachable, with the time they were last seen.
+	unreachableSystems := map[string]time.Time{}
+
+	// Systems whose clock the daemon heartbeats found skewed, with the largest skew.
+	skewedSystems := map[string]time.Duration{}
+
+	// Systems whose clock isn't synchronized to a time source.
+	unsynchronizedSystems := map[string]bool{}
+
+	// Boot steps the daemons are still waiting for, or which failed, by system.
+	startingSystems := map[string][]string{}
+	failedBootSteps := map[string][]string{}
+
+	// Virtual machines without nested KVM, which can't run virtual machines themselves.
+	noKVMSystems := []string{}
+
 	osdsConfigured := false
 	clusterSize := 0
 	osdCount := 0
@@ -266,6 +334,51 @@ func compileWarnings(name string, statuses []types.Status) Warnings {
 			}
 		}
 
+		for _, path := range s.UnderlayPaths {
+			if !path.Reachable {
+				brokenPaths[s.Name] = append(brokenPaths[s.Name], path.RemoteAddress)
+			}
+		}
+
+		for _, member := range s.Liveness {
+			if member.Reachable {
+				continue
+			}
+
+			lastSeen := member.LastSeen
+			if lastSeen.IsZero() {
+				lastSeen = member.Since
+			}
+
+			previous, ok := unreachableSystems[member.Name]
+			if !ok || lastSeen.Before(previous) {
+				unreachableSystems[member.Name] = lastSeen
+			}
+		}
+
+		for _, clock := range s.Clocks {
+			if !clock.Synchronized {
+				unsynchronizedSystems[clock.Name] = true
+			}
+
+			if clock.Skewed && clock.Skew.Abs() > skewedSystems[clock.Name].Abs() {
+				skewedSystems[clock.Name] = clock.Skew
+			}
+		}
+
+		for _, step := range s.Boot {
+			switch step.Status {
+			case types.BootStepWaiting:
+				startingSystems[s.Name] = append(startingSystems[s.Name], step.Name)
+			case types.BootStepFailed:
+				failedBootSteps[s.Name] = append(failedBootSteps[s.Name], step.Name)
+			}
+		}
+
+		if s.Platform != nil && s.Platform.Virtual && !s.Platform.KVM {
+			noKVMSystems = append(noKVMSystems, s.Name)
+		}
+
 		osdCount = osdCount + len(s.OSDs)
 		allServices := []types.ServiceType{types.LXD, types.MicroCeph, types.MicroOVN, types.MicroCloud}
 		cloudMembers := make(map[string]bool, len(s.Clusters[types.MicroCloud]))
@@ -372,6 +485,49 @@ func compileWarnings(name string, statuses []types.Status) Warnings {
 		warnings = append(warnings, Warning{Level: Error, Message: msg})
 	}
 
+	for name, lastSeen := range unreachableSystems {
+		tmpl := tui.Fmt{Arg: "%s unreachable for %s"}
+		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: name}, tui.Fmt{Color: tui.Bright, Bold: true, Arg: time.Since(lastSeen).Truncate(time.Second).String()})
+		warnings = append(warnings, Warning{Level: Error, Message: msg})
+	}
+
+	for name, skew := range skewedSystems {
+		tmpl := tui.Fmt{Arg: "Clock of %s is off by %s"}
+		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: name}, tui.Fmt{Color: tui.Bright, Bold: true, Arg: skew.Abs().String()})
+		warnings = append(warnings, Warning{Level: Error, Message: msg})
+	}
+
+	for name := range unsynchronizedSystems {
+		tmpl := tui.Fmt{Arg: "Clock of %s is not synchronized to a time source"}
+		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: name})
+		warnings = append(warnings, Warning{Level: Warn, Message: msg})
+	}
+
+	for name, steps := range startingSystems {
+		tmpl := tui.Fmt{Arg: "%s is still starting, waiting for %s"}
+		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: name}, tui.Fmt{Color: tui.Bright, Bold: true, Arg: strings.Join(steps, ", ")})
+		warnings = append(warnings, Warning{Level: Warn, Message: msg})
+	}
+
+	for name, steps := range failedBootSteps {
+		tmpl := tui.Fmt{Arg: "%s failed to start on %s"}
+		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: strings.Join(steps, ", ")}, tui.Fmt{Color: tui.Bright, Bold: true, Arg: name})
+		warnings = append(warnings, Warning{Level: Error, Message: msg})
+	}
+
+	if len(noKVMSystems) > 0 {
+		sort.Strings(noKVMSystems)
+		tmpl := tui.Fmt{Arg: "Nested KVM is not available on %s"}
+		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: strings.Join(noKVMSystems, ", ")})
+		warnings = append(warnings, Warning{Level: Warn, Message: msg})
+	}
+
+	for name, addresses := range brokenPaths {
+		tmpl := tui.Fmt{Arg: "OVN underlay paths from %s are down: %s"}
+		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: name}, tui.Fmt{Color: tui.Bright, Bold: true, Arg: strings.Join(addresses, ", ")})
+		warnings = append(warnings, Warning{Level: Error, Message: msg})
+	}
+
 	for service := range upgradingServices {
 		tmpl := tui.Fmt{Arg: "%s upgrade in progress"}
 		msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: service})
@@ -406,6 +562,34 @@ func compileWarnings(name string, statuses []types.Status) Warnings {
 	return warnings
 }
 
+// expectedMemberWarnings returns a warning for the expected members which aren't part of MicroCloud, as seen by the local cluster member.
+func expectedMemberWarnings(name string, statuses []types.Status, expected []service.ExpectedMember) Warnings {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	memberNames := []string{}
+	for _, s := range statuses {
+		if s.Name != name {
+			continue
+		}
+
+		for _, member := range s.Clusters[types.MicroCloud] {
+			memberNames = append(memberNames, member.Name)
+		}
+	}
+
+	missing := service.MissingMembers(expected, memberNames)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	tmpl := tui.Fmt{Arg: "Expected cluster members have not joined: %s"}
+	msg := tui.Printf(tmpl, tui.Fmt{Color: tui.Bright, Bold: true, Arg: strings.Join(missing, ", ")})
+
+	return Warnings{{Level: Warn, Message: msg}}
+}
+
 // formatStatusRow formats the given status data for a cluster member into a row of the table.
 // Also takes the local system's status which will be used as the source of truth for cluster member responsiveness.
 func formatStatusRow(localStatus types.Status, s types.Status) []string {
@@ -462,5 +646,5 @@ func formatStatusRow(localStatus types.Status, s types.Status) []string {
 		}
 	}
 
-	return []string{s.Name, s.Address, osds, cephServices, ovnServices, status}
+	return []string{s.Name, s.Address, formatPlatform(s.Platform), osds, cephServices, ovnServices, status}
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	cephTypes "github.com/canonical/microceph/microceph/api/types"
 	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
@@ -467,6 +468,187 @@ func (s *statusSuite) Test_statusWarnings() {
 			},
 			expectedWarnings: []Warning{},
 		},
+		{
+			desc: "3 node MicroCloud with unreachable OVN underlay paths",
+			statuses: []types.Status{
+				{
+					Name:    "micro01",
+					Address: "10.0.0.100",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					UnderlayPaths: []types.UnderlayPath{{RemoteAddress: "10.0.0.101", Reachable: true}, {RemoteAddress: "10.0.0.102", Reachable: false}},
+				},
+				{
+					Name:    "micro02",
+					Address: "10.0.0.101",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					UnderlayPaths: []types.UnderlayPath{{RemoteAddress: "10.0.0.100", Reachable: true}, {RemoteAddress: "10.0.0.102", Reachable: true}},
+				},
+				{
+					Name:    "micro03",
+					Address: "10.0.0.102",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					UnderlayPaths: []types.UnderlayPath{{RemoteAddress: "10.0.0.100", Reachable: false}, {RemoteAddress: "10.0.0.101", Reachable: true}},
+				},
+			},
+			expectedWarnings: []Warning{
+				{Level: Warn, Message: "MicroCeph is not found on micro01, micro02, micro03"},
+				{Level: Error, Message: "OVN underlay paths from micro01 are down: 10.0.0.102"},
+				{Level: Error, Message: "OVN underlay paths from micro03 are down: 10.0.0.100"},
+			},
+		},
+		{
+			desc: "3 node MicroCloud with a member unreachable by the daemon heartbeats",
+			statuses: []types.Status{
+				{
+					Name:    "micro01",
+					Address: "10.0.0.100",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Liveness: []types.MemberLiveness{{Name: "micro02", Reachable: true}, {Name: "micro03", Reachable: false, LastSeen: time.Now().Add(-4 * time.Minute)}},
+				},
+				{
+					Name:    "micro02",
+					Address: "10.0.0.101",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Liveness: []types.MemberLiveness{{Name: "micro01", Reachable: true}, {Name: "micro03", Reachable: false, LastSeen: time.Now().Add(-3 * time.Minute)}},
+				},
+			},
+			expectedWarnings: []Warning{
+				{Level: Warn, Message: "No MicroCeph OSDs configured"},
+				{Level: Warn, Message: "MicroCeph is not found on micro01, micro02"},
+				{Level: Error, Message: "micro03 unreachable for 4m0s"},
+			},
+		},
+		{
+			desc: "3 node MicroCloud with a member clock skewed and not synchronized",
+			statuses: []types.Status{
+				{
+					Name:    "micro01",
+					Address: "10.0.0.100",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Clocks: []types.MemberClock{{Name: "micro02", Skew: 2 * time.Millisecond, Synchronized: true}, {Name: "micro03", Skew: -300 * time.Millisecond, Skewed: true}},
+				},
+				{
+					Name:    "micro02",
+					Address: "10.0.0.101",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Clocks: []types.MemberClock{{Name: "micro01", Skew: -2 * time.Millisecond, Synchronized: true}, {Name: "micro03", Skew: -298 * time.Millisecond, Skewed: true}},
+				},
+			},
+			expectedWarnings: []Warning{
+				{Level: Warn, Message: "No MicroCeph OSDs configured"},
+				{Level: Warn, Message: "MicroCeph is not found on micro01, micro02"},
+				{Level: Error, Message: "Clock of micro03 is off by 300ms"},
+				{Level: Warn, Message: "Clock of micro03 is not synchronized to a time source"},
+			},
+		},
+		{
+			desc: "3 node MicroCloud with virtual machine members without nested KVM",
+			statuses: []types.Status{
+				{
+					Name:    "micro01",
+					Address: "10.0.0.100",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Platform: &types.Platform{Virtual: true, KVM: true},
+				},
+				{
+					Name:    "micro02",
+					Address: "10.0.0.101",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Platform: &types.Platform{Virtual: true, Cloud: "aws"},
+				},
+				{
+					Name:    "micro03",
+					Address: "10.0.0.102",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Platform: &types.Platform{Virtual: true},
+				},
+			},
+			expectedWarnings: []Warning{
+				{Level: Warn, Message: "MicroCeph is not found on micro01, micro02, micro03"},
+				{Level: Warn, Message: "Nested KVM is not available on micro02, micro03"},
+			},
+		},
+		{
+			desc: "3 node MicroCloud with members still starting after a power loss",
+			statuses: []types.Status{
+				{
+					Name:    "micro01",
+					Address: "10.0.0.100",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Boot: []types.BootStep{{Name: "MicroOVN", Status: types.BootStepReady}, {Name: "LXD", Status: types.BootStepReady}, {Name: "LXD networks", Status: types.BootStepWaiting}},
+				},
+				{
+					Name:    "micro02",
+					Address: "10.0.0.101",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Boot: []types.BootStep{{Name: "MicroOVN", Status: types.BootStepFailed}, {Name: "LXD", Status: types.BootStepWaiting}},
+				},
+				{
+					Name:    "micro03",
+					Address: "10.0.0.102",
+					Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+						types.MicroCloud: {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.MicroOVN:   {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+						types.LXD:        {genMember("micro01", microTypes.MemberOnline), genMember("micro02", microTypes.MemberOnline), genMember("micro03", microTypes.MemberOnline)},
+					},
+					Boot: []types.BootStep{{Name: "MicroOVN", Status: types.BootStepReady}, {Name: "LXD", Status: types.BootStepReady}},
+				},
+			},
+			expectedWarnings: []Warning{
+				{Level: Warn, Message: "MicroCeph is not found on micro01, micro02, micro03"},
+				{Level: Warn, Message: "micro01 is still starting, waiting for LXD networks"},
+				{Level: Warn, Message: "micro02 is still starting, waiting for LXD"},
+				{Level: Error, Message: "MicroOVN failed to start on micro02"},
+			},
+		},
 	}
 
 	for i, c := range cases {
@@ -490,3 +672,23 @@ func (s *statusSuite) Test_statusWarnings() {
 		}
 	}
 }
+
+func (s *statusSuite) Test_serviceWarningRows() {
+	warnings := []types.ServiceWarning{
+		{ID: "ovn:micro02:controller-disconnected", Service: types.MicroOVN, Location: "micro02", Severity: "high", Message: "OVN controller is not connected to the southbound database: not connected"},
+		{ID: "ceph:OSD_DOWN", Service: types.MicroCeph, Severity: "moderate", Message: "1 osds down", Acknowledged: true},
+	}
+
+	header, rows := serviceWarningRows(warnings, false)
+	s.Len(header, 5)
+	s.Len(rows, 1)
+	s.Equal("ovn:micro02:controller-disconnected", rows[0][0])
+	s.Equal("micro02", rows[0][2])
+
+	s.T().Log("Acknowledged warnings are only shown with --all")
+	header, rows = serviceWarningRows(warnings, true)
+	s.Equal("Acknowledged", header[5])
+	s.Len(rows, 2)
+	s.Equal("cluster", rows[1][2])
+	s.Equal("yes", rows[1][5])
+}
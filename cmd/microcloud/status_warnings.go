@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+// runWarnings acknowledges the given warnings, and prints the warnings raised by the services of the cluster.
+func (c *cmdStatus) runWarnings(ctx context.Context) error {
+	client, err := c.common.configClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range c.flagAcknowledge {
+		err := cloudClient.AcknowledgeWarning(ctx, client, id, true)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(tui.SummarizeResult("Acknowledged warning %s", id))
+	}
+
+	if !c.flagWarnings {
+		return nil
+	}
+
+	warnings, err := cloudClient.GetWarnings(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if c.flagTarget != "" {
+		warnings = slices.DeleteFunc(warnings, func(w types.ServiceWarning) bool { return w.Location != c.flagTarget })
+	}
+
+	header, rows := serviceWarningRows(warnings, c.flagAll)
+	if len(rows) == 0 {
+		fmt.Println(tui.SummarizeResult("No warnings"))
+
+		return nil
+	}
+
+	fmt.Println(tui.NewTable(header, rows))
+
+	return nil
+}
+
+// serviceWarningRows returns the table of the given warnings, leaving out the acknowledged ones unless all is set.
+func serviceWarningRows(warnings []types.ServiceWarning, all bool) ([]string, [][]string) {
+	header := []string{"ID", "Service", "Location", "Severity", "Message"}
+	if all {
+		header = append(header, "Acknowledged")
+	}
+
+	rows := [][]string{}
+	for _, w := range warnings {
+		if w.Acknowledged && !all {
+			continue
+		}
+
+		level := Warn
+		if w.Severity == "high" {
+			level = Error
+		}
+
+		location := w.Location
+		if location == "" {
+			location = "cluster"
+		}
+
+		row := []string{w.ID, string(w.Service), location, level.Symbol() + " " + w.Severity, w.Message}
+		if all {
+			acknowledged := "no"
+			if w.Acknowledged {
+				acknowledged = "yes"
+			}
+
+			row = append(row, acknowledged)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return header, rows
+}
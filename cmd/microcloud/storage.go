@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/units"
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// StoragePoolSummary is a storage pool managed by MicroCloud, merging the information of LXD and MicroCeph.
+type StoragePoolSummary struct {
+	Name   string `json:"name" yaml:"name"`
+	Driver string `json:"driver" yaml:"driver"`
+
+	// Total and Used are the capacity and usage of the pool in bytes, summed over the members for local pools.
+	Total uint64 `json:"total" yaml:"total"`
+	Used  uint64 `json:"used" yaml:"used"`
+
+	// Replicas is the number of copies kept of the data of the pool.
+	Replicas int64 `json:"replicas" yaml:"replicas"`
+
+	// Members are the cluster members contributing disks to the pool.
+	Members []string `json:"members" yaml:"members"`
+}
+
+// storageInput is the information gathered from LXD and MicroCeph to summarize the storage pools.
+type storageInput struct {
+	Names service.ResourceNames
+	Tiers []service.CephTier
+
+	StoragePools []lxdAPI.StoragePool
+
+	// Usage is the capacity and usage of each storage pool, keyed by name and member, or by an empty member for remote pools.
+	Usage map[string]map[string]lxdAPI.ResourcesStoragePool
+
+	CephPools []cephTypes.Pool
+	CephDisks cephTypes.Disks
+
+	// ClassOSDs are the OSDs of each device class used by a tier.
+	ClassOSDs map[string][]int64
+}
+
+type cmdStorage struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to inspect the storage pools managed by MicroCloud.
+func (c *cmdStorage) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Inspect the storage pools managed by MicroCloud",
+		RunE:  func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdList = cmdStorageList{common: c.common}
+	cmd.AddCommand(cmdList.command())
+
+	return cmd
+}
+
+type cmdStorageList struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to list the storage pools managed by MicroCloud.
+func (c *cmdStorageList) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the storage pools managed by MicroCloud",
+		Long: `List the storage pools managed by MicroCloud
+
+The capacity and usage of the local storage pool are summed over the cluster members.
+The replicas of the remote storage pools are the size of their OSD pools, and their members are the ones contributing OSDs.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to list the storage pools managed by MicroCloud.
+func (c *cmdStorageList) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	services := []types.ServiceType{types.MicroCloud, types.LXD}
+	if service.Exists(types.MicroCeph, addableServices[types.MicroCeph]) {
+		services = append(services, types.MicroCeph)
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, services...)
+	if err != nil {
+		return err
+	}
+
+	input, err := gatherStorageInput(context.Background(), sh)
+	if err != nil {
+		return err
+	}
+
+	summaries := buildStorageSummaries(input)
+	rows := make([][]string, 0, len(summaries))
+	for _, summary := range summaries {
+		replicas := "-"
+		if summary.Replicas > 0 {
+			replicas = strconv.FormatInt(summary.Replicas, 10)
+		}
+
+		rows = append(rows, []string{
+			summary.Name,
+			summary.Driver,
+			units.GetByteSizeStringIEC(int64(summary.Total), 2),
+			units.GetByteSizeStringIEC(int64(summary.Used), 2),
+			replicas,
+			strings.Join(summary.Members, "\n"),
+		})
+	}
+
+	table, err := tui.FormatData(c.flagFormat, []string{"NAME", "DRIVER", "SIZE", "USED", "REPLICAS", "MEMBERS"}, rows, summaries)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+// gatherStorageInput collects the storage pools managed by MicroCloud from LXD, and the OSD pools and disks backing them from MicroCeph.
+func gatherStorageInput(ctx context.Context, sh *service.Handler) (storageInput, error) {
+	input := storageInput{Usage: map[string]map[string]lxdAPI.ResourcesStoragePool{}, ClassOSDs: map[string][]int64{}}
+
+	microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return input, err
+	}
+
+	config, err := cloudClient.GetConfig(ctx, microClient)
+	if err != nil {
+		return input, err
+	}
+
+	input.Names = service.ResourceNamesFromConfig(config)
+	input.Tiers, err = service.ParseCephTiers(config[types.ConfigCephTiers])
+	if err != nil {
+		return input, fmt.Errorf("Invalid %q configuration: %w", types.ConfigCephTiers, err)
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(ctx)
+	if err != nil {
+		return input, err
+	}
+
+	pools, err := lxdClient.GetStoragePools()
+	if err != nil {
+		return input, fmt.Errorf("Failed to get LXD storage pools: %w", err)
+	}
+
+	for _, pool := range pools {
+		if !storagePoolManaged(pool.Name, input.Names, input.Tiers) {
+			continue
+		}
+
+		input.StoragePools = append(input.StoragePools, pool)
+		input.Usage[pool.Name] = map[string]lxdAPI.ResourcesStoragePool{}
+
+		// Remote pools share the same capacity on all members.
+		members := pool.Locations
+		if isRemoteDriver(pool.Driver) {
+			members = []string{""}
+		}
+
+		for _, member := range members {
+			resources, err := lxdClient.UseTarget(member).GetStoragePoolResources(pool.Name)
+			if err != nil {
+				return input, fmt.Errorf("Failed to get the resources of LXD storage pool %q: %w", pool.Name, err)
+			}
+
+			input.Usage[pool.Name][member] = *resources
+		}
+	}
+
+	if sh.Services[types.MicroCeph] == nil {
+		return input, nil
+	}
+
+	cephService := sh.Services[types.MicroCeph].(*service.CephService)
+	input.CephPools, err = cephService.GetPools(ctx, "")
+	if err != nil {
+		return input, fmt.Errorf("Failed to get the MicroCeph pools: %w", err)
+	}
+
+	input.CephDisks, err = cephService.GetDisks(ctx, "", nil)
+	if err != nil {
+		return input, fmt.Errorf("Failed to get the MicroCeph disks: %w", err)
+	}
+
+	for _, tier := range input.Tiers {
+		out, err := runCeph(ctx, "osd", "crush", "class", "ls-osd", tier.DeviceClass, "--format", "json")
+		if err != nil {
+			return input, fmt.Errorf("Failed to get the OSDs of device class %q: %w", tier.DeviceClass, err)
+		}
+
+		osds := []int64{}
+		err = json.Unmarshal([]byte(out), &osds)
+		if err != nil {
+			return input, fmt.Errorf("Failed to parse the OSDs of device class %q: %w", tier.DeviceClass, err)
+		}
+
+		input.ClassOSDs[tier.DeviceClass] = osds
+	}
+
+	return input, nil
+}
+
+// storagePoolManaged returns whether the storage pool with the given name is set up by MicroCloud.
+func storagePoolManaged(name string, names service.ResourceNames, tiers []service.CephTier) bool {
+	if name == names.LocalPool || name == names.RemotePool || name == names.RemoteFSPool {
+		return true
+	}
+
+	_, err := service.FindCephTier(tiers, name)
+
+	return err == nil
+}
+
+// isRemoteDriver returns whether the given LXD storage driver is backed by Ceph, and so shared by the cluster members.
+func isRemoteDriver(driver string) bool {
+	return driver == "ceph" || driver == "cephfs"
+}
+
+// buildStorageSummaries returns the summaries of the storage pools described by the input, sorted by name.
+func buildStorageSummaries(input storageInput) []StoragePoolSummary {
+	summaries := make([]StoragePoolSummary, 0, len(input.StoragePools))
+	for _, pool := range input.StoragePools {
+		summary := StoragePoolSummary{Name: pool.Name, Driver: pool.Driver, Members: []string{}}
+		for _, usage := range input.Usage[pool.Name] {
+			summary.Total += usage.Space.Total
+			summary.Used += usage.Space.Used
+		}
+
+		if !isRemoteDriver(pool.Driver) {
+			summary.Replicas = 1
+			summary.Members = append(summary.Members, pool.Locations...)
+			sort.Strings(summary.Members)
+			summaries = append(summaries, summary)
+			continue
+		}
+
+		osdPool := pool.Config["ceph.osd.pool_name"]
+		if pool.Driver == "cephfs" {
+			osdPool = pool.Config["cephfs.data_pool"]
+		}
+
+		for _, cephPool := range input.CephPools {
+			if cephPool.Pool == osdPool {
+				summary.Replicas = cephPool.Size
+			}
+		}
+
+		// The pools of the tiers only place data on the OSDs of their device class.
+		tier, err := service.FindCephTier(input.Tiers, pool.Name)
+		for _, disk := range input.CephDisks {
+			if err == nil && !slices.Contains(input.ClassOSDs[tier.DeviceClass], disk.OSD) {
+				continue
+			}
+
+			if !slices.Contains(summary.Members, disk.Location) {
+				summary.Members = append(summary.Members, disk.Location)
+			}
+		}
+
+		sort.Strings(summary.Members)
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+
+	return summaries
+}
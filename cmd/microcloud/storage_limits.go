@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/units"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// reservedVolume is the volume holding the space of the local storage pool of each system beyond its maximum size.
+const reservedVolume = "reserved"
+
+// StorageLimits represents the maximum sizes of the storage pools in the preseed yaml.
+// Each maximum size is either a size (e.g. 500GiB), or a percentage of the capacity of the pool (e.g. 80%).
+type StorageLimits struct {
+	// Local is the maximum size of the local storage pool of each system.
+	Local string `yaml:"local"`
+
+	// Remote is the maximum size of each remote storage pool, including the pools of the performance tiers.
+	Remote string `yaml:"remote"`
+
+	// RemoteFS is the maximum size of the data of the remote-fs storage pool.
+	RemoteFS string `yaml:"remote_fs"`
+}
+
+// reserveLocalPoolSpace creates a volume guaranteed the space of the local storage pool of the given system beyond its maximum size,
+// so the instances and volumes of the pool can't use more than the maximum size.
+// The reverter is given the removal of the volume.
+func (c *initConfig) reserveLocalPoolSpace(targetClient lxd.InstanceServer, system InitSystem, names service.ResourceNames, addRevert func(func())) error {
+	pool := names.LocalPool
+	if c.limits.Storage.Local == "" || !slices.Contains(memberStoragePools(system, names), pool) {
+		return nil
+	}
+
+	resources, err := targetClient.GetStoragePoolResources(pool)
+	if err != nil {
+		return fmt.Errorf("Failed to get the capacity of pool %q: %w", pool, err)
+	}
+
+	limit, err := service.PoolSizeLimit(c.limits.Storage.Local, resources.Space.Total)
+	if err != nil {
+		return err
+	}
+
+	if limit >= resources.Space.Total {
+		return nil
+	}
+
+	reserved := resources.Space.Total - limit
+	volume := lxdAPI.StorageVolumesPost{
+		Name: reservedVolume,
+		Type: "custom",
+		StorageVolumePut: lxdAPI.StorageVolumePut{
+			Description: "Space of the storage pool kept free by MicroCloud",
+			Config:      map[string]string{"size": strconv.FormatUint(reserved, 10) + "B", "zfs.reserve_space": "true"},
+		},
+	}
+
+	op, err := targetClient.CreateStoragePoolVolume(pool, volume)
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to reserve %s of pool %q: %w", units.GetByteSizeStringIEC(int64(reserved), 2), pool, err)
+	}
+
+	addRevert(func() {
+		op, err := targetClient.DeleteStoragePoolVolume(pool, "custom", volume.Name)
+		if err == nil {
+			_ = op.Wait()
+		}
+	})
+
+	return nil
+}
+
+// deleteReservedVolume deletes the volume holding the reserved space of the local storage pool, if any.
+func deleteReservedVolume(targetClient lxd.InstanceServer, pool string) error {
+	op, err := targetClient.DeleteStoragePoolVolume(pool, "custom", reservedVolume)
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// cephPoolStats is the usage of an OSD pool, as reported by "ceph df".
+type cephPoolStats struct {
+	Name  string `json:"name"`
+	Stats struct {
+		Stored   uint64 `json:"stored"`
+		MaxAvail uint64 `json:"max_avail"`
+	} `json:"stats"`
+}
+
+// cephPoolCapacity returns the capacity of the OSD pool in bytes, which is the data it stores along with the data it can still store.
+func cephPoolCapacity(ctx context.Context, osdPool string) (uint64, error) {
+	out, err := runCeph(ctx, "df", "--format", "json")
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get the Ceph usage: %w", err)
+	}
+
+	var usage struct {
+		Pools []cephPoolStats `json:"pools"`
+	}
+
+	err = json.Unmarshal([]byte(out), &usage)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse the Ceph usage: %w", err)
+	}
+
+	for _, pool := range usage.Pools {
+		if pool.Name == osdPool {
+			return pool.Stats.Stored + pool.Stats.MaxAvail, nil
+		}
+	}
+
+	return 0, fmt.Errorf("OSD pool %q not found", osdPool)
+}
+
+// setupCephPoolQuotas sets the quota of the OSD pools backing the given Ceph storage pools to their maximum size.
+func (c *initConfig) setupCephPoolQuotas(ctx context.Context, pools []lxdAPI.StoragePoolsPost, targetPools []lxdAPI.StoragePoolsPost) error {
+	for _, pool := range pools {
+		limit := c.limits.Storage.Remote
+		if pool.Driver == "cephfs" {
+			limit = c.limits.Storage.RemoteFS
+		}
+
+		if limit == "" {
+			continue
+		}
+
+		for _, osdPool := range cephOSDPools([]lxdAPI.StoragePoolsPost{pool}, targetPools) {
+			var capacity uint64
+			if strings.HasSuffix(limit, "%") {
+				var err error
+				capacity, err = cephPoolCapacity(ctx, osdPool)
+				if err != nil {
+					return err
+				}
+			}
+
+			maxBytes, err := service.PoolSizeLimit(limit, capacity)
+			if err != nil {
+				return err
+			}
+
+			if maxBytes == 0 {
+				return errors.New("The Ceph cluster has no capacity to limit")
+			}
+
+			_, err = runCeph(ctx, "osd", "pool", "set-quota", osdPool, "max_bytes", strconv.FormatUint(maxBytes, 10))
+			if err != nil {
+				return fmt.Errorf("Failed to set the quota of OSD pool %q: %w", osdPool, err)
+			}
+
+			fmt.Println(tui.SummarizeResult("Limited pool %s to %s", pool.Name, units.GetByteSizeStringIEC(int64(maxBytes), 2)))
+		}
+	}
+
+	return nil
+}
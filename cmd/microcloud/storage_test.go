@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type storageSuite struct {
+	suite.Suite
+}
+
+func TestStorageSuite(t *testing.T) {
+	suite.Run(t, new(storageSuite))
+}
+
+func (s *storageSuite) Test_buildStorageSummaries() {
+	usage := func(total uint64, used uint64) lxdAPI.ResourcesStoragePool {
+		return lxdAPI.ResourcesStoragePool{Space: lxdAPI.ResourcesStoragePoolSpace{Total: total, Used: used}}
+	}
+
+	input := storageInput{
+		Names: service.DefaultResourceNames(),
+		Tiers: []service.CephTier{{Name: "remote-fast", DeviceClass: "nvme"}},
+		StoragePools: []lxdAPI.StoragePool{
+			{Name: "remote", Driver: "ceph", Config: map[string]string{"ceph.osd.pool_name": service.DefaultCephOSDPool}},
+			{Name: "remote-fast", Driver: "ceph", Config: map[string]string{"ceph.osd.pool_name": "lxd_tier_remote-fast"}},
+			{Name: "local", Driver: "zfs", Locations: []string{"micro02", "micro01"}},
+		},
+		Usage: map[string]map[string]lxdAPI.ResourcesStoragePool{
+			"local":       {"micro01": usage(100, 10), "micro02": usage(200, 20)},
+			"remote":      {"": usage(3000, 300)},
+			"remote-fast": {"": usage(1000, 50)},
+		},
+		CephPools: []cephTypes.Pool{{Pool: service.DefaultCephOSDPool, Size: 3}, {Pool: "lxd_tier_remote-fast", Size: 2}},
+		CephDisks: cephTypes.Disks{
+			{OSD: 0, Location: "micro01"},
+			{OSD: 1, Location: "micro02"},
+			{OSD: 2, Location: "micro03"},
+			{OSD: 3, Location: "micro01"},
+		},
+		ClassOSDs: map[string][]int64{"nvme": {0, 1}},
+	}
+
+	s.Equal([]StoragePoolSummary{
+		{Name: "local", Driver: "zfs", Total: 300, Used: 30, Replicas: 1, Members: []string{"micro01", "micro02"}},
+		{Name: "remote", Driver: "ceph", Total: 3000, Used: 300, Replicas: 3, Members: []string{"micro01", "micro02", "micro03"}},
+		{Name: "remote-fast", Driver: "ceph", Total: 1000, Used: 50, Replicas: 2, Members: []string{"micro01", "micro02"}},
+	}, buildStorageSummaries(input))
+}
+
+func (s *storageSuite) Test_storagePoolManaged() {
+	names := service.DefaultResourceNames()
+	tiers := []service.CephTier{{Name: "remote-fast", DeviceClass: "nvme"}}
+
+	s.True(storagePoolManaged(names.LocalPool, names, tiers))
+	s.True(storagePoolManaged(names.RemoteFSPool, names, tiers))
+	s.True(storagePoolManaged("remote-fast", names, tiers))
+	s.False(storagePoolManaged("default", names, tiers))
+}
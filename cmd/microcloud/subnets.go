@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// namedSubnet is a subnet given to MicroCloud, along with the purpose it is given for.
+type namedSubnet struct {
+	name   string
+	subnet *net.IPNet
+
+	// gateway subnets are the subnets of the gateways of the uplink network.
+	gateway bool
+
+	// routed subnets are routed to the OVN networks by the uplink network, so they can't be used by any other network.
+	routed bool
+}
+
+// overlaps returns whether the given subnets share any address.
+func overlaps(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// validateSubnetMask checks the subnet has room for more than a single host, and isn't the whole address space.
+// Routed subnets may hold a single address.
+func validateSubnetMask(subnet namedSubnet) error {
+	ones, bits := subnet.subnet.Mask.Size()
+	if ones == 0 {
+		return fmt.Errorf("%s subnet %q spans the whole address space", subnet.name, subnet.subnet.String())
+	}
+
+	if !subnet.routed && ones >= bits-1 {
+		return fmt.Errorf("%s subnet %q has no room for hosts", subnet.name, subnet.subnet.String())
+	}
+
+	return nil
+}
+
+// routedOnUplink returns whether the routed subnet is part of the other subnet, which is the subnet of a gateway of the uplink network.
+func routedOnUplink(routed namedSubnet, other namedSubnet, gateways map[string]bool) bool {
+	return routed.routed && !other.routed && gateways[other.subnet.String()]
+}
+
+// validateSubnets cross-checks the subnets given to MicroCloud for overlaps and invalid masks.
+// Networks can share the same subnet, such as the Ceph networks defaulting to the MicroCloud internal network,
+// but a subnet nested in another one is rejected. The subnets routed to the OVN networks may only be part of the uplink network.
+func validateSubnets(subnets []namedSubnet) error {
+	gateways := map[string]bool{}
+	for _, subnet := range subnets {
+		if subnet.gateway {
+			gateways[subnet.subnet.String()] = true
+		}
+	}
+
+	for i, subnet := range subnets {
+		err := validateSubnetMask(subnet)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range subnets[i+1:] {
+			if !overlaps(subnet.subnet, other.subnet) {
+				continue
+			}
+
+			if routedOnUplink(subnet, other, gateways) || routedOnUplink(other, subnet, gateways) {
+				continue
+			}
+
+			if subnet.routed || other.routed {
+				return fmt.Errorf("%s subnet %q must not overlap %s subnet %q", subnet.name, subnet.subnet.String(), other.name, other.subnet.String())
+			}
+
+			if subnet.subnet.String() != other.subnet.String() {
+				return fmt.Errorf("%s subnet %q partially overlaps %s subnet %q, use either the same subnet or separate ones", subnet.name, subnet.subnet.String(), other.name, other.subnet.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+// uplinkSubnets returns the subnets of the gateways of the uplink network, and the subnets it routes to the OVN networks.
+func uplinkSubnets(uplinkName string, config map[string]string) ([]namedSubnet, error) {
+	subnets := []namedSubnet{}
+	for _, ipPrefix := range []string{"ipv4", "ipv6"} {
+		gateway := config[ipPrefix+".gateway"]
+		if gateway != "" {
+			_, gatewayNet, err := net.ParseCIDR(gateway)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s.gateway %q: %w", ipPrefix, gateway, err)
+			}
+
+			subnets = append(subnets, namedSubnet{name: fmt.Sprintf("%s %s gateway", uplinkName, ipPrefix), subnet: gatewayNet, gateway: true})
+		}
+
+		routes := config[ipPrefix+".routes"]
+		if routes == "" {
+			continue
+		}
+
+		for _, route := range strings.Split(routes, ",") {
+			_, routeNet, err := net.ParseCIDR(strings.TrimSpace(route))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s.routes %q: %w", ipPrefix, routes, err)
+			}
+
+			subnets = append(subnets, namedSubnet{name: fmt.Sprintf("%s %s route", uplinkName, ipPrefix), subnet: routeNet, routed: true})
+		}
+	}
+
+	return subnets, nil
+}
+
+// systemSubnets returns the MicroCloud internal, Ceph and OVN underlay subnets of the systems, without duplicates.
+func (c *initConfig) systemSubnets() []namedSubnet {
+	names := make([]string, 0, len(c.systems))
+	for name := range c.systems {
+		names = append(names, name)
+	}
+
+	// Sort the systems so the first conflict is always reported.
+	sort.Strings(names)
+
+	subnets := []namedSubnet{}
+	add := func(name string, subnet *net.IPNet) {
+		if subnet == nil {
+			return
+		}
+
+		// The subnets of the interfaces may keep the address of the interface.
+		subnet = &net.IPNet{IP: subnet.IP.Mask(subnet.Mask), Mask: subnet.Mask}
+		if slices.ContainsFunc(subnets, func(s namedSubnet) bool { return s.name == name && s.subnet.String() == subnet.String() }) {
+			return
+		}
+
+		subnets = append(subnets, namedSubnet{name: name, subnet: subnet})
+	}
+
+	add("MicroCloud internal", c.lookupSubnet)
+	for _, name := range names {
+		system := c.systems[name]
+		networks := []struct {
+			name  string
+			iface *NetworkInterfaceInfo
+		}{
+			{name: "MicroCloud internal", iface: system.MicroCloudInternalNetwork},
+			{name: "Ceph public", iface: system.MicroCephPublicNetwork},
+			{name: "Ceph internal", iface: system.MicroCephInternalNetwork},
+			{name: "OVN underlay", iface: system.OVNGeneveNetwork},
+		}
+
+		for _, network := range networks {
+			if network.iface != nil {
+				add(network.name, network.iface.Subnet)
+			}
+		}
+	}
+
+	return subnets
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type subnetsSuite struct {
+	suite.Suite
+}
+
+func TestSubnetsSuite(t *testing.T) {
+	suite.Run(t, new(subnetsSuite))
+}
+
+func (s *subnetsSuite) Test_validateSubnets() {
+	subnet := func(name string, cidr string) namedSubnet {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		s.Require().NoError(err)
+
+		return namedSubnet{name: name, subnet: ipNet}
+	}
+
+	gateway := func(cidr string) namedSubnet {
+		s := subnet("UPLINK ipv4 gateway", cidr)
+		s.gateway = true
+
+		return s
+	}
+
+	route := func(cidr string) namedSubnet {
+		s := subnet("UPLINK route", cidr)
+		s.routed = true
+
+		return s
+	}
+
+	cases := []struct {
+		desc    string
+		subnets []namedSubnet
+		err     string
+	}{
+		{
+			desc:    "Separate subnets",
+			subnets: []namedSubnet{subnet("MicroCloud internal", "10.0.0.0/24"), subnet("Ceph internal", "10.0.1.0/24"), gateway("10.0.2.1/24")},
+		},
+		{
+			desc:    "Shared subnets",
+			subnets: []namedSubnet{subnet("MicroCloud internal", "10.0.0.0/24"), subnet("Ceph public", "10.0.0.0/24"), gateway("10.0.0.1/24")},
+		},
+		{
+			desc:    "Nested subnets",
+			subnets: []namedSubnet{subnet("MicroCloud internal", "10.0.0.0/16"), subnet("Ceph internal", "10.0.1.0/24")},
+			err:     `MicroCloud internal subnet "10.0.0.0/16" partially overlaps Ceph internal subnet "10.0.1.0/24", use either the same subnet or separate ones`,
+		},
+		{
+			desc:    "Whole address space",
+			subnets: []namedSubnet{subnet("OVN underlay", "0.0.0.0/0")},
+			err:     `OVN underlay subnet "0.0.0.0/0" spans the whole address space`,
+		},
+		{
+			desc:    "Host subnet",
+			subnets: []namedSubnet{subnet("Ceph public", "10.0.0.5/32")},
+			err:     `Ceph public subnet "10.0.0.5/32" has no room for hosts`,
+		},
+		{
+			desc:    "Route within the uplink",
+			subnets: []namedSubnet{subnet("MicroCloud internal", "10.0.0.0/24"), gateway("10.0.0.1/24"), route("10.0.0.99/32")},
+		},
+		{
+			desc:    "Route overlapping another network",
+			subnets: []namedSubnet{subnet("Ceph internal", "10.0.1.0/24"), gateway("10.0.0.1/24"), route("10.0.1.99/32")},
+			err:     `Ceph internal subnet "10.0.1.0/24" must not overlap UPLINK route subnet "10.0.1.99/32"`,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := validateSubnets(c.subnets)
+		if c.err == "" {
+			s.NoError(err)
+		} else {
+			s.EqualError(err, c.err)
+		}
+	}
+}
+
+func (s *subnetsSuite) Test_systemSubnets() {
+	_, internal, _ := net.ParseCIDR("10.0.0.0/24")
+	_, ceph, _ := net.ParseCIDR("10.0.1.0/24")
+
+	cfg := initConfig{
+		lookupSubnet: &net.IPNet{IP: net.ParseIP("10.0.0.5"), Mask: internal.Mask},
+		systems: map[string]InitSystem{
+			"n1": {MicroCephInternalNetwork: &NetworkInterfaceInfo{Subnet: ceph}},
+			"n2": {MicroCephInternalNetwork: &NetworkInterfaceInfo{Subnet: ceph}, MicroCephPublicNetwork: &NetworkInterfaceInfo{Subnet: internal}},
+		},
+	}
+
+	subnets := cfg.systemSubnets()
+	s.Len(subnets, 3)
+	s.Equal("MicroCloud internal", subnets[0].name)
+	s.Equal("10.0.0.0/24", subnets[0].subnet.String())
+	s.Equal("Ceph internal", subnets[1].name)
+	s.Equal("Ceph public", subnets[2].name)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// allowSystemDisksFlag is the flag offering the disks holding the operating system for storage.
+const allowSystemDisksFlag = "allow-system-disks"
+
+// allowSystemDisksUsage is the usage of the flag offering the disks holding the operating system for storage.
+const allowSystemDisksUsage = "Offer the disks holding the operating system (root, boot, EFI or swap) for storage. Using them destroys the system"
+
+// allowSystemDisks offers the disks holding the operating system for storage, if allowed, and warns about it.
+func (c *initConfig) allowSystemDisks(allow bool) {
+	if !allow {
+		return
+	}
+
+	c.collectOptions.IncludeSystemDisks = true
+	tui.PrintWarning("The disks holding the operating system are offered for storage. Using them destroys the system")
+}
+
+// warnSystemDisks warns about each available disk which holds the operating system of its system.
+func (c *initConfig) warnSystemDisks() {
+	for _, name := range slices.Sorted(maps.Keys(c.state)) {
+		state := c.state[name]
+		for _, id := range slices.Sorted(maps.Keys(state.SystemDisks)) {
+			_, ok := state.AvailableDisks[id]
+			if ok {
+				tui.PrintWarning(fmt.Sprintf("Disk %q on %q holds the %s of the operating system", id, name, strings.Join(state.SystemDisks[id], ", ")))
+			}
+		}
+	}
+}
+
+// systemDiskUses returns the uses of the disk at the given path by the operating system of the system, if it holds the operating system.
+// The path matches the paths MicroCloud uses for the disks, as well as their kernel name in /dev.
+func systemDiskUses(state service.SystemInformation, path string) []string {
+	if state.Resources == nil {
+		return nil
+	}
+
+	for _, disk := range state.Resources.Storage.Disks {
+		if path == service.FormatDiskPath(disk) || path == "/dev/"+disk.ID {
+			return state.SystemDisks[disk.ID]
+		}
+	}
+
+	return nil
+}
+
+// checkSystemDiskPath returns an error if the disk at the given path of the system holds its operating system, unless allowed.
+func (c *initConfig) checkSystemDiskPath(name string, path string) error {
+	uses := systemDiskUses(c.state[name], path)
+	if len(uses) == 0 || c.collectOptions.IncludeSystemDisks {
+		return nil
+	}
+
+	return withExitCode(ExitCodeValidation, fmt.Errorf("Disk %q on %q holds the %s of the operating system, use --%s to use it anyway", path, name, strings.Join(uses, ", "), allowSystemDisksFlag))
+}
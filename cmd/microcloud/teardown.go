@@ -0,0 +1,469 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	microClient "github.com/canonical/microcluster/v3/client"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// teardownPlan is what tearing down the MicroCloud removes from LXD.
+type teardownPlan struct {
+	// pools are the storage pools set up by MicroCloud, in the order they are deleted.
+	pools []string
+
+	// networks are the networks set up by MicroCloud, in the order they are deleted.
+	networks []string
+
+	// volumesPool is the storage pool holding the images and backups volumes, if any.
+	volumesPool string
+
+	// volumes are the names of the images and backups volumes of each cluster member.
+	volumes map[string][]string
+
+	// reservedPool is the local storage pool, if its space beyond its maximum size is reserved by a volume on each cluster member.
+	reservedPool string
+
+	// reservedMembers are the cluster members with a volume reserving the space of the local storage pool.
+	reservedMembers []string
+}
+
+// newTeardownPlan returns the storage pools, networks and volumes MicroCloud set up according to its configuration, out of those existing in LXD.
+// Tier pools are deleted before the remote pools, and the OVN network before its uplink network.
+func newTeardownPlan(config map[string]string, members []string, pools []string, networks []string) (teardownPlan, error) {
+	names := service.ResourceNamesFromConfig(config)
+	tiers, err := service.ParseCephTiers(config[types.ConfigCephTiers])
+	if err != nil {
+		return teardownPlan{}, fmt.Errorf("Invalid %q configuration: %w", types.ConfigCephTiers, err)
+	}
+
+	plan := teardownPlan{volumes: map[string][]string{}}
+	candidatePools := []string{}
+	for _, tier := range tiers {
+		candidatePools = append(candidatePools, tier.Name)
+	}
+
+	candidatePools = append(candidatePools, names.RemoteFSPool, names.RemotePool, names.LocalPool)
+	for _, pool := range candidatePools {
+		if slices.Contains(pools, pool) && !slices.Contains(plan.pools, pool) {
+			plan.pools = append(plan.pools, pool)
+		}
+	}
+
+	for _, network := range []string{names.OVNNetwork, names.UplinkNetwork, names.FanNetwork} {
+		if slices.Contains(networks, network) && !slices.Contains(plan.networks, network) {
+			plan.networks = append(plan.networks, network)
+		}
+	}
+
+	if config[types.ConfigLocalPoolLimit] != "" && slices.Contains(plan.pools, names.LocalPool) {
+		plan.reservedPool = names.LocalPool
+		plan.reservedMembers = members
+	}
+
+	volumes := volumeOptionsFromConfig(config)
+	switch volumes.Pool {
+	case "", volumesPoolLocal:
+		plan.volumesPool = names.LocalPool
+	case volumesPoolRemote:
+		plan.volumesPool = names.RemotePool
+	}
+
+	if !slices.Contains(plan.pools, plan.volumesPool) {
+		plan.volumesPool = ""
+		return plan, nil
+	}
+
+	for _, member := range members {
+		for _, volumeType := range volumeTypes {
+			plan.volumes[member] = append(plan.volumes[member], volumes.volumeName(volumeType, member))
+		}
+	}
+
+	return plan, nil
+}
+
+// profileDevicesIn returns the names of the devices of the profile which use the given storage pools or networks.
+func profileDevicesIn(profile lxdAPI.Profile, pools []string, networks []string) []string {
+	devices := []string{}
+	for name, device := range profile.Devices {
+		if slices.Contains(pools, device["pool"]) || slices.Contains(networks, device["network"]) {
+			devices = append(devices, name)
+		}
+	}
+
+	sort.Strings(devices)
+
+	return devices
+}
+
+type cmdTeardown struct {
+	common *CmdControl
+
+	flagWipeDisks bool
+}
+
+// command returns the subcommand to dismantle the MicroCloud.
+func (c *cmdTeardown) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "teardown",
+		Short: "Dismantle the MicroCloud and reset all cluster members",
+		Long: `Dismantle the MicroCloud and reset all cluster members
+
+The LXD storage pools, networks and volumes set up by MicroCloud are deleted, along with the cached images.
+The other cluster members are removed from LXD, MicroCeph, MicroOVN and MicroCloud, which resets them.
+MicroCeph, MicroOVN and MicroCloud are then reset on this cluster member.
+
+LXD can't remove the last member of its cluster, so LXD stays clustered on this cluster member.
+Reinstall the LXD snap on it before running "microcloud init" again.
+
+The command refuses to run while there are instances.
+With --wipe-disks, the OSDs are removed and their disks wiped, so they can be selected again.`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().BoolVar(&c.flagWipeDisks, "wipe-disks", false, "Remove the OSDs and wipe their disks")
+
+	return cmd
+}
+
+// run runs the subcommand to dismantle the MicroCloud.
+func (c *cmdTeardown) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	ctx := cmd.Context()
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, there is nothing to tear down"))
+	}
+
+	installedServices := []types.ServiceType{types.MicroCloud, types.LXD}
+	for serviceType, stateDir := range addableServices {
+		if service.Exists(serviceType, stateDir) {
+			installedServices = append(installedServices, serviceType)
+		}
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, installedServices...)
+	if err != nil {
+		return err
+	}
+
+	client, err := cloudApp.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	instances, err := lxdClient.GetInstancesAllProjects(lxdAPI.InstanceTypeAny)
+	if err != nil {
+		return fmt.Errorf("Failed to get the instances: %w", err)
+	}
+
+	if len(instances) > 0 {
+		return withExitCode(ExitCodeValidation, fmt.Errorf("Cannot tear down the MicroCloud while it has %d instances, delete them first", len(instances)))
+	}
+
+	config, err := cloudClient.GetConfig(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	members, err := sh.Services[types.MicroCloud].ClusterMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	memberNames := make([]string, 0, len(members))
+	for name := range members {
+		memberNames = append(memberNames, name)
+	}
+
+	sort.Strings(memberNames)
+
+	pools, err := lxdClient.GetStoragePoolNames()
+	if err != nil {
+		return fmt.Errorf("Failed to get the storage pools: %w", err)
+	}
+
+	networks, err := lxdClient.GetNetworkNames()
+	if err != nil {
+		return fmt.Errorf("Failed to get the networks: %w", err)
+	}
+
+	plan, err := newTeardownPlan(config, memberNames, pools, networks)
+	if err != nil {
+		return err
+	}
+
+	var osds []string
+	ceph, hasCeph := sh.Services[types.MicroCeph].(*service.CephService)
+	if c.flagWipeDisks && hasCeph {
+		disks, err := ceph.GetDisks(ctx, "", nil)
+		if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+			return fmt.Errorf("Failed to get the MicroCeph disks: %w", err)
+		}
+
+		for _, disk := range disks {
+			osds = append(osds, disk.Location+":"+disk.Path)
+		}
+
+		sort.Strings(osds)
+	}
+
+	fmt.Println(tui.SummarizeResult("Tearing down the MicroCloud removes"))
+	fmt.Printf(" Cluster members: %s\n", strings.Join(memberNames, ", "))
+	if len(plan.pools) > 0 {
+		fmt.Printf(" Storage pools: %s\n", strings.Join(plan.pools, ", "))
+	}
+
+	if len(plan.networks) > 0 {
+		fmt.Printf(" Networks: %s\n", strings.Join(plan.networks, ", "))
+	}
+
+	if len(osds) > 0 {
+		fmt.Printf(" OSDs, whose disks are wiped: %s\n", strings.Join(osds, ", "))
+	}
+
+	fmt.Println("")
+
+	answer, err := c.common.asker.AskString(fmt.Sprintf("Type the name of this cluster member (%q) to tear down the MicroCloud:", status.Name), "", func(string) error { return nil })
+	if err != nil {
+		return err
+	}
+
+	if answer != status.Name {
+		return withExitCode(ExitCodeCancelled, errors.New("Teardown cancelled"))
+	}
+
+	release, err := lockClusterChanges(ctx, client, "teardown", "Tear down the MicroCloud")
+	if err != nil {
+		return err
+	}
+
+	err = c.teardownLXD(lxdClient, plan)
+	if err == nil && c.flagWipeDisks && hasCeph {
+		err = c.wipeOSDs(ctx, ceph, client)
+	}
+
+	if err == nil {
+		err = c.removeMembers(ctx, client, status.Name, memberNames)
+	}
+
+	// The lock is held in the MicroCloud database, which is gone once MicroCloud is reset.
+	release()
+	if err != nil {
+		return err
+	}
+
+	for _, serviceType := range []types.ServiceType{types.MicroCeph, types.MicroOVN, types.MicroCloud} {
+		var reset func(ctx context.Context) error
+		switch s := sh.Services[serviceType].(type) {
+		case *service.CephService:
+			reset = s.ResetClusterMember
+		case *service.OVNService:
+			reset = s.ResetClusterMember
+		case *service.CloudService:
+			reset = s.ResetClusterMember
+		default:
+			continue
+		}
+
+		initialized, err := sh.Services[serviceType].IsInitialized(ctx)
+		if err != nil || !initialized {
+			continue
+		}
+
+		err = reset(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to reset %s: %w", serviceType, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Reset %s", serviceType))
+	}
+
+	tui.PrintWarning("LXD is still clustered on this system, reinstall the LXD snap before running \"microcloud init\" again")
+
+	return nil
+}
+
+// teardownLXD deletes the cached images, and the storage pools, networks and volumes MicroCloud set up in LXD,
+// after removing them from the default profile and from the server configuration of the cluster members.
+func (c *cmdTeardown) teardownLXD(client lxd.InstanceServer, plan teardownPlan) error {
+	profile, etag, err := client.GetProfile("default")
+	if err != nil {
+		return fmt.Errorf("Failed to get the default profile: %w", err)
+	}
+
+	devices := profileDevicesIn(*profile, plan.pools, plan.networks)
+	if len(devices) > 0 {
+		put := profile.Writable()
+		for _, device := range devices {
+			delete(put.Devices, device)
+		}
+
+		op, err := client.UpdateProfile("default", put, etag)
+		if err == nil {
+			err = op.Wait()
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to remove the MicroCloud devices from the default profile: %w", err)
+		}
+	}
+
+	images, err := client.GetImagesAllProjects()
+	if err != nil {
+		return fmt.Errorf("Failed to get the images: %w", err)
+	}
+
+	for _, image := range images {
+		op, err := client.UseProject(image.Project).DeleteImage(image.Fingerprint)
+		if err == nil {
+			err = op.Wait()
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed to delete image %q: %w", image.Fingerprint, err)
+		}
+	}
+
+	for _, member := range slices.Sorted(maps.Keys(plan.volumes)) {
+		target := client.UseTarget(member)
+		server, etag, err := target.GetServer()
+		if err != nil {
+			return fmt.Errorf("Failed to get the configuration of %q: %w", member, err)
+		}
+
+		put := server.Writable()
+		for _, volumeType := range volumeTypes {
+			delete(put.Config, "storage."+volumeType+"_volume")
+		}
+
+		err = target.UpdateServer(put, etag)
+		if err != nil {
+			return fmt.Errorf("Failed to unset the volumes of %q: %w", member, err)
+		}
+
+		for _, volume := range plan.volumes[member] {
+			op, err := target.DeleteStoragePoolVolume(plan.volumesPool, "custom", volume)
+			if err == nil {
+				err = op.Wait()
+			}
+
+			if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusNotFound) {
+				return fmt.Errorf("Failed to delete volume %q of %q: %w", volume, member, err)
+			}
+		}
+	}
+
+	for _, member := range plan.reservedMembers {
+		err := deleteReservedVolume(client.UseTarget(member), plan.reservedPool)
+		if err != nil {
+			return fmt.Errorf("Failed to delete the reserved space of %q: %w", member, err)
+		}
+	}
+
+	for _, network := range plan.networks {
+		err := client.DeleteNetwork(network)
+		if err != nil {
+			return fmt.Errorf("Failed to delete network %q: %w", network, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Deleted network %s", network))
+	}
+
+	for _, pool := range plan.pools {
+		err := client.DeleteStoragePool(pool)
+		if err != nil {
+			return fmt.Errorf("Failed to delete storage pool %q: %w", pool, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Deleted storage pool %s", pool))
+	}
+
+	return nil
+}
+
+// wipeOSDs removes all OSDs, and wipes their disks on each cluster member.
+func (c *cmdTeardown) wipeOSDs(ctx context.Context, ceph *service.CephService, client *microClient.Client) error {
+	disks, err := ceph.GetDisks(ctx, "", nil)
+	if err != nil {
+		return fmt.Errorf("Failed to get the MicroCeph disks: %w", err)
+	}
+
+	paths := map[string][]string{}
+	for _, disk := range disks {
+		err := ceph.RemoveDisk(ctx, disk)
+		if err != nil {
+			return err
+		}
+
+		paths[disk.Location] = append(paths[disk.Location], disk.Path)
+	}
+
+	for member, memberPaths := range paths {
+		err := cloudClient.WipeDisks(ctx, client.UseTarget(member), types.DisksWipe{Paths: memberPaths})
+		if err != nil {
+			return fmt.Errorf("Failed to wipe the disks of %q: %w", member, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Wiped disks %s on %s", strings.Join(memberPaths, ", "), member))
+	}
+
+	return nil
+}
+
+// removeMembers removes the cluster members other than the local one from all services, which resets them.
+func (c *cmdTeardown) removeMembers(ctx context.Context, client *microClient.Client, local string, members []string) error {
+	for _, member := range members {
+		if member == local {
+			continue
+		}
+
+		// The cluster is being dismantled, so its members may not get back a healthy quorum in between.
+		err := cloudClient.DeleteClusterMember(ctx, client, member, true)
+		if err != nil {
+			return fmt.Errorf("Failed to remove cluster member %q: %w", member, err)
+		}
+
+		fmt.Println(tui.SummarizeResult("Removed cluster member %s", member))
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type teardownSuite struct {
+	suite.Suite
+}
+
+func TestTeardownSuite(t *testing.T) {
+	suite.Run(t, new(teardownSuite))
+}
+
+func (s *teardownSuite) Test_newTeardownPlan() {
+	names := service.DefaultResourceNames()
+	members := []string{"micro01", "micro02"}
+
+	plan, err := newTeardownPlan(map[string]string{}, members, []string{names.LocalPool, names.RemotePool, "custom"}, []string{names.OVNNetwork, names.UplinkNetwork, "lxdbr0"})
+	s.NoError(err)
+	s.Equal([]string{names.RemotePool, names.LocalPool}, plan.pools)
+	s.Equal([]string{names.OVNNetwork, names.UplinkNetwork}, plan.networks)
+	s.Equal(names.LocalPool, plan.volumesPool)
+	s.Equal(map[string][]string{"micro01": {"images", "backups"}, "micro02": {"images", "backups"}}, plan.volumes)
+	s.Equal("", plan.reservedPool)
+	s.Empty(plan.reservedMembers)
+
+	config := map[string]string{
+		types.ConfigCephTiers:   "fast:nvme",
+		types.ConfigVolumesPool: volumesPoolRemote,
+	}
+
+	plan, err = newTeardownPlan(config, members, []string{"fast", names.RemotePool}, []string{names.FanNetwork})
+	s.NoError(err)
+	s.Equal([]string{"fast", names.RemotePool}, plan.pools)
+	s.Equal([]string{names.FanNetwork}, plan.networks)
+	s.Equal(names.RemotePool, plan.volumesPool)
+	s.Equal([]string{"images-micro01", "backups-micro01"}, plan.volumes["micro01"])
+
+	plan, err = newTeardownPlan(map[string]string{types.ConfigVolumesPool: volumesPoolNone}, members, []string{names.LocalPool}, nil)
+	s.NoError(err)
+	s.Equal("", plan.volumesPool)
+	s.Empty(plan.volumes)
+
+	plan, err = newTeardownPlan(map[string]string{types.ConfigLocalPoolLimit: "80%"}, members, []string{names.LocalPool}, nil)
+	s.NoError(err)
+	s.Equal(names.LocalPool, plan.reservedPool)
+	s.Equal(members, plan.reservedMembers)
+
+	plan, err = newTeardownPlan(map[string]string{types.ConfigLocalPoolLimit: "80%"}, members, []string{names.RemotePool}, nil)
+	s.NoError(err)
+	s.Equal("", plan.reservedPool)
+
+	_, err = newTeardownPlan(map[string]string{types.ConfigCephTiers: "invalid"}, members, nil, nil)
+	s.Error(err)
+}
+
+func (s *teardownSuite) Test_profileDevicesIn() {
+	profile := lxdAPI.Profile{Devices: map[string]map[string]string{
+		"root": {"type": "disk", "path": "/", "pool": "local"},
+		"eth0": {"type": "nic", "network": "default"},
+		"eth1": {"type": "nic", "network": "lxdbr0"},
+		"data": {"type": "disk", "path": "/data", "pool": "custom"},
+		"gpu0": {"type": "gpu"},
+	}}
+
+	s.Equal([]string{"eth0", "root"}, profileDevicesIn(profile, []string{"local", "remote"}, []string{"default"}))
+	s.Empty(profileDevicesIn(profile, nil, nil))
+}
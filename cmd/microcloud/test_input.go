@@ -29,12 +29,14 @@ func setupAsker(ctx context.Context) (*tui.InputHandler, error) {
 cat << EOF | microcloud init
 table:select                # selects an element in the table
 table:select-all            # selects all elements in the table
-table:select-none           # de-selects all elements in the table
+table:select-none           # de-selects all elements in the table matching the filter
 table:up                    # move up in the table
 table:down                  # move down in the table
+table:page-up               # move up one page in the table
+table:page-down             # move down one page in the table
 table:wait <time.Duration>  # waits before the next instruction
 table:expect <count>        # waits until exactly <count> peers are available, and errors out if more are found
-table:filter <text>         # applies filtering text to the table output
+table:filter <text>         # applies filtering text to the table output, use <column>:<text> to filter a single column
 table:done                  # confirms the table selection and exits the table
 ctrl:m						# carriage return, used to submit text input
 
@@ -6,7 +6,6 @@ import (
 	"sort"
 
 	cli "github.com/canonical/lxd/shared/cmd"
-	"github.com/canonical/microcluster/v3/microcluster"
 	"github.com/spf13/cobra"
 
 	"github.com/canonical/microcloud/microcloud/cmd/tui"
@@ -22,6 +21,8 @@ func (c *cmdSecrets) command() *cobra.Command {
 		Use:   "tokens",
 		Short: "Manage join tokens",
 		RunE:  c.run,
+
+		Annotations: map[string]string{contextAnnotation: "true"},
 	}
 
 	var cmdList = cmdTokensList{common: c.common}
@@ -62,8 +63,7 @@ func (c *cmdTokensList) run(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	}
 
-	options := microcluster.Args{StateDir: c.common.FlagMicroCloudDir}
-	m, err := microcluster.App(options)
+	m, err := c.common.app()
 	if err != nil {
 		return err
 	}
@@ -117,8 +117,7 @@ func (c *cmdTokensRevoke) run(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	}
 
-	options := microcluster.Args{StateDir: c.common.FlagMicroCloudDir}
-	m, err := microcluster.App(options)
+	m, err := c.common.app()
 	if err != nil {
 		return err
 	}
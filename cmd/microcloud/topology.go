@@ -0,0 +1,474 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/microcluster/v3/microcluster"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+const (
+	// TopologyFormatDOT is the Graphviz DOT format of the topology command.
+	TopologyFormatDOT = "dot"
+
+	// TopologyFormatMermaid is the Mermaid flowchart format of the topology command.
+	TopologyFormatMermaid = "mermaid"
+)
+
+// topologyIDRegex matches the characters which are not allowed in diagram node identifiers.
+var topologyIDRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// Kinds of topology nodes, which decide their shape in the rendered diagram.
+const (
+	topologyService = "service"
+	topologyNetwork = "network"
+	topologyStorage = "storage"
+)
+
+// topologyNode is a node of the topology diagram.
+type topologyNode struct {
+	ID    string
+	Label []string
+	Kind  string
+
+	// Member is the cluster member the node belongs to, or empty if shared by the cluster.
+	Member string
+}
+
+// topologyEdge connects two nodes of the topology diagram.
+type topologyEdge struct {
+	From  string
+	To    string
+	Label string
+}
+
+// topology is the graph of the cluster members, their services and the networks and storage pools which connect them.
+type topology struct {
+	// Members are the names of the cluster members, with their addresses.
+	Members map[string]string
+
+	Nodes []topologyNode
+	Edges []topologyEdge
+}
+
+// topologyInput holds the cluster information the topology is built from.
+type topologyInput struct {
+	// LocalName is the name of the local cluster member, whose status holds the cluster-wide information.
+	LocalName string
+
+	Statuses     []types.Status
+	LXDMembers   []lxdAPI.ClusterMember
+	Networks     []lxdAPI.Network
+	StoragePools []lxdAPI.StoragePool
+
+	// MemberConfig is the member specific configuration of each network and storage pool, keyed by name and member.
+	MemberConfig map[string]map[string]map[string]string
+
+	// CephConfig is the MicroCeph cluster configuration.
+	CephConfig map[string]string
+}
+
+type cmdTopology struct {
+	common *CmdControl
+
+	flagFormat string
+}
+
+// command returns the subcommand to print the topology of the MicroCloud.
+func (c *cmdTopology) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Print a diagram of the MicroCloud cluster",
+		Long: `Print a diagram of the MicroCloud cluster
+
+Renders the cluster members with their services, and the networks (e.g. the OVN uplink and Ceph networks)
+and storage pools connecting them, as Graphviz DOT or as a Mermaid flowchart.
+
+Example:
+  microcloud topology --format dot | dot -Tsvg > microcloud.svg`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", TopologyFormatDOT, "Format (dot|mermaid)")
+
+	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{TopologyFormatDOT, TopologyFormatMermaid}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	return cmd
+}
+
+// run runs the subcommand to print the topology of the MicroCloud.
+func (c *cmdTopology) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	if c.flagFormat != TopologyFormatDOT && c.flagFormat != TopologyFormatMermaid {
+		return withExitCode(ExitCodeUsage, fmt.Errorf("Invalid format (%s)", c.flagFormat))
+	}
+
+	cloudApp, err := microcluster.App(microcluster.Args{StateDir: c.common.FlagMicroCloudDir})
+	if err != nil {
+		return err
+	}
+
+	err = cloudApp.Ready(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to wait for MicroCloud to get ready: %w", err)
+	}
+
+	status, err := cloudApp.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud status: %w", err)
+	}
+
+	if !status.Ready {
+		return withExitCode(ExitCodeNotInitialized, errors.New("MicroCloud is uninitialized, run 'microcloud init' first"))
+	}
+
+	services := []types.ServiceType{types.MicroCloud, types.LXD}
+	if service.Exists(types.MicroCeph, addableServices[types.MicroCeph]) {
+		services = append(services, types.MicroCeph)
+	}
+
+	sh, err := service.NewHandler(status.Name, status.Address.Addr().String(), c.common.FlagMicroCloudDir, services...)
+	if err != nil {
+		return err
+	}
+
+	microClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	input := topologyInput{LocalName: status.Name, MemberConfig: map[string]map[string]map[string]string{}}
+	input.Statuses, err = cloudClient.GetStatus(context.Background(), microClient)
+	if err != nil {
+		return err
+	}
+
+	lxdClient, err := sh.Services[types.LXD].(*service.LXDService).Client(context.Background())
+	if err != nil {
+		return err
+	}
+
+	input.LXDMembers, err = lxdClient.GetClusterMembers()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD cluster members: %w", err)
+	}
+
+	networks, err := lxdClient.GetNetworks()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD networks: %w", err)
+	}
+
+	for _, network := range networks {
+		if !network.Managed {
+			continue
+		}
+
+		input.Networks = append(input.Networks, network)
+		input.MemberConfig[network.Name] = map[string]map[string]string{}
+		for _, member := range input.LXDMembers {
+			memberNetwork, _, err := lxdClient.UseTarget(member.ServerName).GetNetwork(network.Name)
+			if err != nil {
+				return fmt.Errorf("Failed to get LXD network %q on %q: %w", network.Name, member.ServerName, err)
+			}
+
+			input.MemberConfig[network.Name][member.ServerName] = memberNetwork.Config
+		}
+	}
+
+	input.StoragePools, err = lxdClient.GetStoragePools()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD storage pools: %w", err)
+	}
+
+	for _, pool := range input.StoragePools {
+		input.MemberConfig[pool.Name] = map[string]map[string]string{}
+		for _, member := range input.LXDMembers {
+			memberPool, _, err := lxdClient.UseTarget(member.ServerName).GetStoragePool(pool.Name)
+			if err != nil {
+				return fmt.Errorf("Failed to get LXD storage pool %q on %q: %w", pool.Name, member.ServerName, err)
+			}
+
+			input.MemberConfig[pool.Name][member.ServerName] = memberPool.Config
+		}
+	}
+
+	if sh.Services[types.MicroCeph] != nil {
+		input.CephConfig, err = sh.Services[types.MicroCeph].(*service.CephService).ClusterConfig(context.Background(), "", nil)
+		if err != nil {
+			return fmt.Errorf("Failed to get MicroCeph configuration: %w", err)
+		}
+	}
+
+	t := buildTopology(input)
+	if c.flagFormat == TopologyFormatMermaid {
+		fmt.Print(t.mermaid())
+	} else {
+		fmt.Print(t.dot())
+	}
+
+	return nil
+}
+
+// topologyID returns a diagram node identifier made of the given parts.
+func topologyID(parts ...string) string {
+	return topologyIDRegex.ReplaceAllString(strings.Join(parts, "_"), "_")
+}
+
+// buildTopology returns the topology of the cluster described by the input.
+func buildTopology(input topologyInput) topology {
+	t := topology{Members: map[string]string{}}
+
+	var localStatus types.Status
+	for _, status := range input.Statuses {
+		t.Members[status.Name] = status.Address
+		if status.Name == input.LocalName {
+			localStatus = status
+		}
+	}
+
+	// The cluster roles of each member, keyed by service and member.
+	roles := map[types.ServiceType]map[string]string{}
+	for serviceType, members := range localStatus.Clusters {
+		roles[serviceType] = map[string]string{}
+		for _, member := range members {
+			roles[serviceType][member.Name] = member.Role
+		}
+	}
+
+	for _, member := range input.LXDMembers {
+		if roles[types.LXD] == nil {
+			roles[types.LXD] = map[string]string{}
+		}
+
+		roles[types.LXD][member.ServerName] = strings.Join(member.Roles, ", ")
+	}
+
+	cephMembers := []string{}
+	for _, status := range input.Statuses {
+		for _, serviceType := range []types.ServiceType{types.MicroCloud, types.LXD, types.MicroCeph, types.MicroOVN} {
+			role, ok := roles[serviceType][status.Name]
+			if !ok {
+				continue
+			}
+
+			label := []string{string(serviceType)}
+			switch serviceType {
+			case types.MicroCeph:
+				cephServices := make([]string, 0, len(status.CephServices))
+				for _, s := range status.CephServices {
+					cephServices = append(cephServices, s.Service)
+				}
+
+				sort.Strings(cephServices)
+				label = append(label, strings.Join(cephServices, ", "), fmt.Sprintf("%d OSDs", len(status.OSDs)))
+				cephMembers = append(cephMembers, status.Name)
+			case types.MicroOVN:
+				ovnServices := make([]string, 0, len(status.OVNServices))
+				for _, s := range status.OVNServices {
+					ovnServices = append(ovnServices, string(s.Service))
+				}
+
+				sort.Strings(ovnServices)
+				label = append(label, strings.Join(ovnServices, ", "))
+			default:
+				if role != "" {
+					label = append(label, role)
+				}
+			}
+
+			t.Nodes = append(t.Nodes, topologyNode{ID: topologyID(status.Name, string(serviceType)), Label: label, Kind: topologyService, Member: status.Name})
+		}
+	}
+
+	members := make([]string, 0, len(t.Members))
+	for name := range t.Members {
+		members = append(members, name)
+	}
+
+	sort.Strings(members)
+
+	for _, network := range input.Networks {
+		id := topologyID("network", network.Name)
+		t.Nodes = append(t.Nodes, topologyNode{ID: id, Label: []string{network.Name, network.Type}, Kind: topologyNetwork})
+
+		switch network.Type {
+		case "physical":
+			// Physical networks are the uplinks, connected to the parent interface of each member.
+			for _, member := range members {
+				parent := input.MemberConfig[network.Name][member]["parent"]
+				if parent != "" {
+					t.Edges = append(t.Edges, topologyEdge{From: topologyID(member, string(types.LXD)), To: id, Label: parent})
+				}
+			}
+
+		case "ovn":
+			if network.Config["network"] != "" {
+				t.Edges = append(t.Edges, topologyEdge{From: id, To: topologyID("network", network.Config["network"]), Label: "uplink"})
+			}
+
+			for _, member := range members {
+				if _, ok := roles[types.MicroOVN][member]; ok {
+					t.Edges = append(t.Edges, topologyEdge{From: topologyID(member, string(types.MicroOVN)), To: id, Label: "chassis"})
+				}
+			}
+
+		default:
+			for _, member := range members {
+				if _, ok := roles[types.LXD][member]; ok {
+					t.Edges = append(t.Edges, topologyEdge{From: topologyID(member, string(types.LXD)), To: id})
+				}
+			}
+		}
+	}
+
+	remoteDrivers := []string{"ceph", "cephfs", "cephobject"}
+	for _, pool := range input.StoragePools {
+		id := topologyID("pool", pool.Name)
+		t.Nodes = append(t.Nodes, topologyNode{ID: id, Label: []string{pool.Name, pool.Driver}, Kind: topologyStorage})
+
+		if slices.Contains(remoteDrivers, pool.Driver) {
+			t.Edges = append(t.Edges, topologyEdge{From: id, To: "ceph"})
+			continue
+		}
+
+		for _, member := range members {
+			config, ok := input.MemberConfig[pool.Name][member]
+			if ok {
+				t.Edges = append(t.Edges, topologyEdge{From: topologyID(member, string(types.LXD)), To: id, Label: config["source"]})
+			}
+		}
+	}
+
+	if len(cephMembers) > 0 {
+		t.Nodes = append(t.Nodes, topologyNode{ID: "ceph", Label: []string{"Ceph cluster"}, Kind: topologyStorage})
+		for _, member := range cephMembers {
+			t.Edges = append(t.Edges, topologyEdge{From: topologyID(member, string(types.MicroCeph)), To: "ceph"})
+		}
+
+		for _, key := range []string{"public_network", "cluster_network"} {
+			subnet := input.CephConfig[key]
+			if subnet == "" {
+				continue
+			}
+
+			id := topologyID("ceph", key)
+			t.Nodes = append(t.Nodes, topologyNode{ID: id, Label: []string{"Ceph " + strings.ReplaceAll(key, "_", " "), subnet}, Kind: topologyNetwork})
+			t.Edges = append(t.Edges, topologyEdge{From: "ceph", To: id})
+		}
+	}
+
+	return t
+}
+
+// memberNodes returns the names of the members in order, and the nodes grouped by member, with the shared nodes keyed by the empty name.
+func (t topology) memberNodes() ([]string, map[string][]topologyNode) {
+	members := make([]string, 0, len(t.Members))
+	for name := range t.Members {
+		members = append(members, name)
+	}
+
+	sort.Strings(members)
+
+	nodes := map[string][]topologyNode{}
+	for _, node := range t.Nodes {
+		nodes[node.Member] = append(nodes[node.Member], node)
+	}
+
+	return members, nodes
+}
+
+// dot renders the topology in the Graphviz DOT format.
+func (t topology) dot() string {
+	label := func(lines []string) string {
+		return strings.ReplaceAll(strings.Join(lines, `\n`), `"`, `\"`)
+	}
+
+	shapes := map[string]string{topologyService: "ellipse", topologyNetwork: "box", topologyStorage: "cylinder"}
+	node := func(b *strings.Builder, indent string, n topologyNode) {
+		fmt.Fprintf(b, "%s%s [label=\"%s\", shape=%s];\n", indent, n.ID, label(n.Label), shapes[n.Kind])
+	}
+
+	members, nodes := t.memberNodes()
+
+	b := &strings.Builder{}
+	b.WriteString("digraph microcloud {\n\trankdir=LR;\n")
+	for _, member := range members {
+		fmt.Fprintf(b, "\tsubgraph %s {\n\t\tlabel=\"%s\";\n", topologyID("cluster", member), label([]string{member, t.Members[member]}))
+		for _, n := range nodes[member] {
+			node(b, "\t\t", n)
+		}
+
+		b.WriteString("\t}\n")
+	}
+
+	for _, n := range nodes[""] {
+		node(b, "\t", n)
+	}
+
+	for _, e := range t.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(b, "\t%s -> %s [label=\"%s\"];\n", e.From, e.To, label([]string{e.Label}))
+		} else {
+			fmt.Fprintf(b, "\t%s -> %s;\n", e.From, e.To)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// mermaid renders the topology as a Mermaid flowchart.
+func (t topology) mermaid() string {
+	label := func(lines []string) string {
+		return strings.ReplaceAll(strings.Join(lines, "<br/>"), `"`, "#quot;")
+	}
+
+	shapes := map[string][2]string{topologyService: {"([", "])"}, topologyNetwork: {"[", "]"}, topologyStorage: {"[(", ")]"}}
+	node := func(b *strings.Builder, indent string, n topologyNode) {
+		fmt.Fprintf(b, "%s%s%s\"%s\"%s\n", indent, n.ID, shapes[n.Kind][0], label(n.Label), shapes[n.Kind][1])
+	}
+
+	members, nodes := t.memberNodes()
+
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	for _, member := range members {
+		fmt.Fprintf(b, "    subgraph %s[\"%s\"]\n", topologyID("member", member), label([]string{member, t.Members[member]}))
+		for _, n := range nodes[member] {
+			node(b, "        ", n)
+		}
+
+		b.WriteString("    end\n")
+	}
+
+	for _, n := range nodes[""] {
+		node(b, "    ", n)
+	}
+
+	for _, e := range t.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(b, "    %s -->|\"%s\"| %s\n", e.From, label([]string{e.Label}), e.To)
+		} else {
+			fmt.Fprintf(b, "    %s --> %s\n", e.From, e.To)
+		}
+	}
+
+	return b.String()
+}
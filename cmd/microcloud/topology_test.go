@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type topologySuite struct {
+	suite.Suite
+}
+
+func TestTopologySuite(t *testing.T) {
+	suite.Run(t, new(topologySuite))
+}
+
+func (s *topologySuite) Test_buildTopology() {
+	member := func(name string, role string) microTypes.ClusterMember {
+		return microTypes.ClusterMember{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: name}, Role: role}
+	}
+
+	input := topologyInput{
+		LocalName: "micro01",
+		Statuses: []types.Status{
+			{
+				Name:    "micro01",
+				Address: "10.0.0.1",
+				Clusters: map[types.ServiceType][]microTypes.ClusterMember{
+					types.MicroCloud: {member("micro01", "voter"), member("micro02", "spare")},
+					types.MicroCeph:  {member("micro01", "voter")},
+					types.MicroOVN:   {member("micro01", "voter"), member("micro02", "spare")},
+				},
+				CephServices: cephTypes.Services{{Service: "osd"}, {Service: "mon"}},
+				OSDs:         cephTypes.Disks{{OSD: 1}},
+			},
+			{Name: "micro02", Address: "10.0.0.2"},
+		},
+		LXDMembers: []lxdAPI.ClusterMember{{ServerName: "micro01", Roles: []string{"database-leader"}}, {ServerName: "micro02"}},
+		Networks: []lxdAPI.Network{
+			{Name: "UPLINK", Type: "physical"},
+			{Name: "default", Type: "ovn", Config: map[string]string{"network": "UPLINK"}},
+		},
+		StoragePools: []lxdAPI.StoragePool{{Name: "local", Driver: "zfs"}, {Name: "remote", Driver: "ceph"}},
+		MemberConfig: map[string]map[string]map[string]string{
+			"UPLINK": {"micro01": {"parent": "enp6s0"}, "micro02": {"parent": "enp7s0"}},
+			"local":  {"micro01": {"source": "/dev/sdb"}},
+		},
+		CephConfig: map[string]string{"public_network": "10.1.0.0/24"},
+	}
+
+	t := buildTopology(input)
+
+	nodes := map[string][]string{}
+	for _, n := range t.Nodes {
+		nodes[n.ID] = n.Label
+	}
+
+	s.Equal([]string{"LXD", "database-leader"}, nodes["micro01_LXD"])
+	s.Equal([]string{"MicroCeph", "mon, osd", "1 OSDs"}, nodes["micro01_MicroCeph"])
+	s.Equal([]string{"Ceph public network", "10.1.0.0/24"}, nodes["ceph_public_network"])
+	s.NotContains(nodes, "micro02_MicroCeph")
+
+	s.Subset(t.Edges, []topologyEdge{
+		{From: "micro01_LXD", To: "network_UPLINK", Label: "enp6s0"},
+		{From: "micro02_LXD", To: "network_UPLINK", Label: "enp7s0"},
+		{From: "network_default", To: "network_UPLINK", Label: "uplink"},
+		{From: "micro02_MicroOVN", To: "network_default", Label: "chassis"},
+		{From: "micro01_LXD", To: "pool_local", Label: "/dev/sdb"},
+		{From: "pool_remote", To: "ceph"},
+		{From: "micro01_MicroCeph", To: "ceph"},
+		{From: "ceph", To: "ceph_public_network"},
+	})
+
+	dot := t.dot()
+	s.Contains(dot, "digraph microcloud {")
+	s.Contains(dot, "\tsubgraph cluster_micro01 {\n\t\tlabel=\"micro01\\n10.0.0.1\";\n")
+	s.Contains(dot, "\tmicro01_LXD -> network_UPLINK [label=\"enp6s0\"];\n")
+	s.Contains(dot, "\tpool_remote [label=\"remote\\nceph\", shape=cylinder];\n")
+
+	mermaid := t.mermaid()
+	s.Contains(mermaid, "flowchart LR\n")
+	s.Contains(mermaid, "    subgraph member_micro02[\"micro02<br/>10.0.0.2\"]\n")
+	s.Contains(mermaid, "    micro01_LXD -->|\"enp6s0\"| network_UPLINK\n")
+	s.Contains(mermaid, "    network_UPLINK[\"UPLINK<br/>physical\"]\n")
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	microClient "github.com/canonical/microcluster/v3/client"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// TuningOptions represents the kernel tuning to apply on each system in the preseed yaml.
+type TuningOptions struct {
+	Apply     bool `yaml:"apply"`
+	Hugepages int  `yaml:"hugepages"`
+}
+
+// validate checks the hugepages share is within the accepted range.
+func (t TuningOptions) validate() error {
+	if t.Hugepages < 0 || t.Hugepages > service.MaximumHugepagesShare {
+		return fmt.Errorf("Hugepages must be between 0 and %d percent of the memory", service.MaximumHugepagesShare)
+	}
+
+	if t.Hugepages > 0 && !t.Apply {
+		return errors.New("Hugepages can only be reserved when applying the kernel tuning")
+	}
+
+	return nil
+}
+
+// validateHugepagesShare validates a share of the memory in percent to reserve as hugepages.
+func validateHugepagesShare(value string) error {
+	share, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("Invalid share of the memory %q: %w", value, err)
+	}
+
+	return TuningOptions{Apply: true, Hugepages: share}.validate()
+}
+
+// tuningHeader is the table header of kernel tuning settings.
+var tuningHeader = []string{"MEMBER", "KEY", "PREVIOUS", "VALUE"}
+
+// tuningRow returns the table row of the given kernel tuning setting.
+func tuningRow(s types.TuningSetting) []string {
+	return []string{s.Member, s.Key, strconv.FormatInt(s.Previous, 10), strconv.FormatInt(s.Value, 10)}
+}
+
+// askTuning asks whether to apply the recommended kernel settings on each system, and how much memory to reserve as hugepages.
+func (c *initConfig) askTuning() error {
+	apply, err := c.asker.AskBool("Would you like to apply the recommended kernel settings (sysctls) on each system? They can be reverted with \"microcloud tuning revert\"", false)
+	if err != nil {
+		return err
+	}
+
+	if !apply {
+		return nil
+	}
+
+	hugepages, err := c.asker.AskString("What share of the memory in percent should be reserved as hugepages for virtual machines? (0 to reserve none)", "0", validateHugepagesShare)
+	if err != nil {
+		return err
+	}
+
+	share, err := strconv.Atoi(hugepages)
+	if err != nil {
+		return err
+	}
+
+	c.tuning = TuningOptions{Apply: true, Hugepages: share}
+
+	return nil
+}
+
+// setupTuning applies the recommended kernel settings on the set up systems if requested.
+// Failures are only reported as warnings as the cluster is already set up at this point.
+func (c *initConfig) setupTuning(s *service.Handler) {
+	if !c.tuning.Apply {
+		return
+	}
+
+	client, err := s.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		tui.PrintWarning(fmt.Sprintf("Failed to apply the kernel tuning: %v", err))
+		return
+	}
+
+	names := make([]string, 0, len(c.systems))
+	for name := range c.systems {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	changed := 0
+	for _, name := range names {
+		settings, err := cloudClient.ApplyTuning(context.Background(), client.UseTarget(name), types.TuningPost{Hugepages: c.tuning.Hugepages})
+		if err != nil {
+			tui.PrintWarning(fmt.Sprintf("Failed to apply the kernel tuning on %q: %v", name, err))
+			continue
+		}
+
+		changed += len(settings)
+	}
+
+	fmt.Println(tui.SummarizeResult("Changed %d kernel settings. Run \"microcloud tuning show\" to list them", changed))
+}
+
+type cmdTuning struct {
+	common *CmdControl
+}
+
+// command returns the subcommand to manage the kernel tuning.
+func (c *cmdTuning) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tuning",
+		Short: "Manage the kernel tuning of the cluster members",
+		Long: `Manage the kernel tuning of the cluster members
+
+MicroCloud can raise the sysctls recommended for running many instances, like the inotify limits,
+the network buffer sizes and fs.aio-max-nr, and reserve hugepages for virtual machines.
+Each changed setting is recorded in the MicroCloud database along with its previous value, so it
+can be listed and reverted later. The recorded settings are applied again when MicroCloud starts.`,
+		RunE: func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+
+	var cmdShow = cmdTuningShow{common: c.common}
+	cmd.AddCommand(cmdShow.command())
+
+	var cmdApply = cmdTuningApply{common: c.common}
+	cmd.AddCommand(cmdApply.command())
+
+	var cmdRevert = cmdTuningRevert{common: c.common}
+	cmd.AddCommand(cmdRevert.command())
+
+	return cmd
+}
+
+// tuningMembers returns the cluster members to apply or revert the kernel tuning on.
+func tuningMembers(ctx context.Context, client *microClient.Client, target string) ([]string, error) {
+	if target != "" {
+		return []string{target}, nil
+	}
+
+	clusterMembers, err := client.GetClusterMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	names := make([]string, 0, len(clusterMembers))
+	for _, member := range clusterMembers {
+		names = append(names, member.Name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+type cmdTuningShow struct {
+	common *CmdControl
+
+	flagTarget string
+	flagFormat string
+}
+
+// command returns the subcommand to show the kernel settings changed by MicroCloud.
+func (c *cmdTuningShow) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the kernel settings changed by MicroCloud",
+		RunE:  c.run,
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Only show the kernel settings changed on this cluster member")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to show the kernel settings changed by MicroCloud.
+func (c *cmdTuningShow) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	settings, err := cloudClient.GetTuning(cmd.Context(), client, c.flagTarget)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(settings))
+	for _, s := range settings {
+		rows = append(rows, append(tuningRow(s), s.AppliedAt.Local().Format(time.DateTime)))
+	}
+
+	table, err := tui.FormatData(c.flagFormat, append(slices.Clone(tuningHeader), "APPLIED"), rows, settings)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	return nil
+}
+
+type cmdTuningApply struct {
+	common *CmdControl
+
+	flagTarget    string
+	flagHugepages int
+}
+
+// command returns the subcommand to apply the recommended kernel settings.
+func (c *cmdTuningApply) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the recommended kernel settings",
+		Long: `Apply the recommended kernel settings
+
+The recommended sysctls are raised on each cluster member. Settings which are already higher are left untouched.
+Applying again keeps the originally recorded previous values, so a revert always restores the settings from before any tuning.`,
+		RunE: c.run,
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Cluster member to apply the kernel settings on")
+	cmd.Flags().IntVar(&c.flagHugepages, "hugepages", 0, "Share of the memory in percent to reserve as hugepages for virtual machines"+"``")
+
+	return cmd
+}
+
+// run runs the subcommand to apply the recommended kernel settings.
+func (c *cmdTuningApply) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	err := TuningOptions{Apply: true, Hugepages: c.flagHugepages}.validate()
+	if err != nil {
+		return withExitCode(ExitCodeUsage, err)
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	names, err := tuningMembers(cmd.Context(), client, c.flagTarget)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{}
+	for _, name := range names {
+		// Report progress on stderr, so it doesn't mix with the table of changed settings.
+		fmt.Fprintf(os.Stderr, "Applying kernel settings on %q ...\n", name)
+
+		settings, err := cloudClient.ApplyTuning(cmd.Context(), client.UseTarget(name), types.TuningPost{Hugepages: c.flagHugepages})
+		if err != nil {
+			return fmt.Errorf("Failed to apply kernel settings on %q: %w", name, err)
+		}
+
+		for _, s := range settings {
+			rows = append(rows, tuningRow(s))
+		}
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("All kernel settings are already at or above their recommended values")
+		return nil
+	}
+
+	fmt.Println(tui.NewTable(tuningHeader, rows))
+
+	return nil
+}
+
+type cmdTuningRevert struct {
+	common *CmdControl
+
+	flagTarget string
+}
+
+// command returns the subcommand to revert the kernel settings changed by MicroCloud.
+func (c *cmdTuningRevert) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revert",
+		Short: "Revert the kernel settings changed by MicroCloud",
+		Long: `Revert the kernel settings changed by MicroCloud
+
+Each recorded setting is restored to its value from before the tuning, and removed from the MicroCloud database.`,
+		RunE: c.run,
+	}
+
+	c.common.addTargetFlag(cmd, &c.flagTarget, "Cluster member to revert the kernel settings on")
+
+	return cmd
+}
+
+// run runs the subcommand to revert the kernel settings changed by MicroCloud.
+func (c *cmdTuningRevert) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	names, err := tuningMembers(cmd.Context(), client, c.flagTarget)
+	if err != nil {
+		return err
+	}
+
+	reverted := 0
+	for _, name := range names {
+		settings, err := cloudClient.RevertTuning(cmd.Context(), client.UseTarget(name))
+		if err != nil {
+			return fmt.Errorf("Failed to revert kernel settings on %q: %w", name, err)
+		}
+
+		reverted += len(settings)
+	}
+
+	fmt.Println(tui.SummarizeResult("Reverted %d kernel settings", reverted))
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/cmd/tui"
+)
+
+const (
+	// verifyPassed is the status of a passed check.
+	verifyPassed = "PASSED"
+
+	// verifyFailed is the status of a failed check.
+	verifyFailed = "FAILED"
+)
+
+// verifySchedule returns the configuration scheduling the verification at the given interval, or unsetting the schedule if the interval is zero.
+func verifySchedule(value string) (map[string]string, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Invalid schedule %q: Must be a positive duration, or 0 to disable the scheduled verification", value))
+	}
+
+	if interval == 0 {
+		return map[string]string{types.ConfigVerifySchedule: ""}, nil
+	}
+
+	return map[string]string{types.ConfigVerifySchedule: value}, nil
+}
+
+// verifyRows returns the table rows of the checks, and the number of failed checks.
+func verifyRows(checks []types.VerifyCheck, highlight bool) ([][]string, int) {
+	failed := 0
+	rows := make([][]string, 0, len(checks))
+	for _, check := range checks {
+		status := verifyPassed
+		if check.Error != "" {
+			failed++
+			status = verifyFailed
+			if highlight {
+				status = tui.ErrorColor(status, true)
+			}
+		}
+
+		rows = append(rows, []string{check.Member, check.Check, check.Target, status, check.Error})
+	}
+
+	return rows, failed
+}
+
+type cmdVerify struct {
+	common *CmdControl
+
+	flagSchedule string
+	flagFormat   string
+}
+
+// command returns the subcommand to verify the cluster members.
+func (c *cmdVerify) command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the connectivity and storage of the cluster members",
+		Long: `Verify the connectivity and storage of the cluster members
+
+Each cluster member runs lightweight checks of:
+  network  Connection to the MicroCloud address of every other cluster member
+  ovn      Reachability of the remote endpoint of each OVN tunnel of its chassis
+  storage  Status and usage of each LXD storage pool it is part of
+
+Failed checks make the command fail.
+
+With --schedule, the cluster members instead run the checks periodically and record the result in the event history,
+listed by "microcloud events list --type verify". The schedule is stored in the verify.schedule configuration key,
+and --schedule 0 stops the scheduled verification.`,
+		Example: `  microcloud verify
+  microcloud verify --schedule 6h`,
+		RunE: c.run,
+	}
+
+	cmd.Flags().StringVar(&c.flagSchedule, "schedule", "", "Verify the cluster members periodically at this interval instead (e.g. 6h), or 0 to stop"+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", tui.TableFormatTable, "Format (csv|json|table|yaml|compact)")
+
+	return cmd
+}
+
+// run runs the subcommand to verify the cluster members.
+func (c *cmdVerify) run(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return cmd.Help()
+	}
+
+	var schedule map[string]string
+	if cmd.Flags().Changed("schedule") {
+		var err error
+		schedule, err = verifySchedule(c.flagSchedule)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := c.common.configClient(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if schedule != nil {
+		err := cloudClient.UpdateConfig(cmd.Context(), client, schedule)
+		if err != nil {
+			return err
+		}
+
+		if schedule[types.ConfigVerifySchedule] == "" {
+			fmt.Println(tui.SummarizeResult("Stopped the scheduled verification"))
+		} else {
+			fmt.Println(tui.SummarizeResult("Scheduled the verification of the cluster members every %s", schedule[types.ConfigVerifySchedule]))
+		}
+
+		return nil
+	}
+
+	members, err := client.GetClusterMembers(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("Failed to get MicroCloud cluster members: %w", err)
+	}
+
+	checks := []types.VerifyCheck{}
+	for _, member := range members {
+		// Report progress on stderr, so it doesn't mix with machine readable output.
+		fmt.Fprintf(os.Stderr, "Verifying %q ...\n", member.Name)
+
+		memberChecks, err := cloudClient.Verify(cmd.Context(), client.UseTarget(member.Name))
+		if err != nil {
+			checks = append(checks, types.VerifyCheck{Member: member.Name, Error: err.Error()})
+			continue
+		}
+
+		checks = append(checks, memberChecks...)
+	}
+
+	// Only highlight the status in human readable output.
+	highlight := c.flagFormat == tui.TableFormatTable || c.flagFormat == tui.TableFormatCompact
+	rows, failed := verifyRows(checks, highlight)
+
+	header := []string{"MEMBER", "CHECK", "TARGET", "STATUS", "ERROR"}
+	table, err := tui.FormatData(c.flagFormat, header, rows, checks)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(table)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type verifySuite struct {
+	suite.Suite
+}
+
+func TestVerifySuite(t *testing.T) {
+	suite.Run(t, new(verifySuite))
+}
+
+func (s *verifySuite) Test_verifySchedule() {
+	cases := []struct {
+		desc     string
+		value    string
+		schedule string
+		err      bool
+	}{
+		{
+			desc:     "Interval",
+			value:    "6h",
+			schedule: "6h",
+		},
+		{
+			desc:  "Zero interval stops the verification",
+			value: "0",
+		},
+		{
+			desc:  "Negative interval",
+			value: "-1h",
+			err:   true,
+		},
+		{
+			desc:  "Not a duration",
+			value: "daily",
+			err:   true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		config, err := verifySchedule(c.value)
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(map[string]string{types.ConfigVerifySchedule: c.schedule}, config)
+	}
+}
+
+func (s *verifySuite) Test_verifyRows() {
+	checks := []types.VerifyCheck{
+		{Member: "micro01", Check: types.VerifyCheckNetwork, Target: "micro02"},
+		{Member: "micro01", Check: types.VerifyCheckOVN, Target: "10.0.2.2", Error: "BFD session is down"},
+	}
+
+	rows, failed := verifyRows(checks, false)
+	s.Equal(1, failed)
+	s.Equal([][]string{
+		{"micro01", "network", "micro02", verifyPassed, ""},
+		{"micro01", "ovn", "10.0.2.2", verifyFailed, "BFD session is down"},
+	}, rows)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+const (
+	// volumesPoolLocal keeps the images and backups volumes on the local storage pool of each system.
+	volumesPoolLocal = "local"
+
+	// volumesPoolRemote keeps the images and backups volumes on the remote storage pool, one of each per system.
+	volumesPoolRemote = "remote"
+
+	// volumesPoolNone keeps images and backups on the root disk of each system.
+	volumesPoolNone = "none"
+)
+
+// volumeTypes are the purposes of the volumes set up on each system, matching the storage.*_volume LXD server keys.
+var volumeTypes = []string{"images", "backups"}
+
+// VolumeOptions represents the images and backups volumes in the preseed yaml.
+type VolumeOptions struct {
+	// Pool is the storage pool holding the volumes, either "local", "remote" or "none". Defaults to "local".
+	Pool        string `yaml:"pool"`
+	ImagesSize  string `yaml:"images_size"`
+	BackupsSize string `yaml:"backups_size"`
+}
+
+// validate validates the volumes config.
+func (v VolumeOptions) validate() error {
+	err := validate.Optional(validate.IsOneOf(types.VolumesPools...))(v.Pool)
+	if err != nil {
+		return fmt.Errorf("Invalid volumes pool: %w", err)
+	}
+
+	for volumeType, size := range map[string]string{"images": v.ImagesSize, "backups": v.BackupsSize} {
+		err := validate.Optional(validate.IsSize)(size)
+		if err != nil {
+			return fmt.Errorf("Invalid %s volume size: %w", volumeType, err)
+		}
+	}
+
+	return nil
+}
+
+// volumeOptionsFromConfig returns the volumes recorded in the given MicroCloud daemon configuration.
+func volumeOptionsFromConfig(config map[string]string) VolumeOptions {
+	return VolumeOptions{
+		Pool:        config[types.ConfigVolumesPool],
+		ImagesSize:  config[types.ConfigVolumesImagesSize],
+		BackupsSize: config[types.ConfigVolumesBackupsSize],
+	}
+}
+
+// config returns the MicroCloud daemon configuration recording the volumes, without the defaults.
+func (v VolumeOptions) config() map[string]string {
+	pool := v.Pool
+	if pool == volumesPoolLocal {
+		pool = ""
+	}
+
+	return nonEmptyConfig(map[string]string{
+		types.ConfigVolumesPool:        pool,
+		types.ConfigVolumesImagesSize:  v.ImagesSize,
+		types.ConfigVolumesBackupsSize: v.BackupsSize,
+	})
+}
+
+// memberPool returns the storage pool holding the volumes of the given system, if it gets the volumes.
+func (v VolumeOptions) memberPool(system InitSystem, names service.ResourceNames) string {
+	pool := ""
+	switch v.Pool {
+	case "", volumesPoolLocal:
+		pool = names.LocalPool
+	case volumesPoolRemote:
+		pool = names.RemotePool
+	}
+
+	if pool == "" || !slices.Contains(memberStoragePools(system, names), pool) {
+		return ""
+	}
+
+	return pool
+}
+
+// volumeName returns the name of the volume of the given type for the given system.
+// The remote storage pool is shared by the systems, so their volumes are told apart by the system name.
+func (v VolumeOptions) volumeName(volumeType string, member string) string {
+	if v.Pool == volumesPoolRemote {
+		return volumeType + "-" + member
+	}
+
+	return volumeType
+}
+
+// volumeSize returns the size of the volume of the given type, if any.
+func (v VolumeOptions) volumeSize(volumeType string) string {
+	if volumeType == "images" {
+		return v.ImagesSize
+	}
+
+	return v.BackupsSize
+}
+
+// askVolumes asks where to keep the images and backups of each system, and how large their volumes are.
+func (c *initConfig) askVolumes(sh *service.Handler) error {
+	if !c.bootstrap {
+		return nil
+	}
+
+	names := sh.Services[types.LXD].(*service.LXDService).ResourceNames()
+	options := []string{}
+	for _, pool := range []string{volumesPoolLocal, volumesPoolRemote} {
+		available := false
+		for _, system := range c.systems {
+			if (VolumeOptions{Pool: pool}).memberPool(system, names) != "" {
+				available = true
+				break
+			}
+		}
+
+		if available {
+			options = append(options, pool)
+		}
+	}
+
+	if len(options) == 0 {
+		return nil
+	}
+
+	options = append(options, volumesPoolNone)
+	pool, err := c.asker.AskString(fmt.Sprintf("Which storage pool would you like to keep the images and backups of each system on? (%s)", strings.Join(options, "/")), options[0], validate.IsOneOf(options...))
+	if err != nil {
+		return err
+	}
+
+	volumes := VolumeOptions{Pool: pool}
+	if pool != volumesPoolNone {
+		volumes.ImagesSize, err = c.asker.AskString("What size should the images volume of each system have? (empty for no limit)", "", validate.Optional(validate.IsSize))
+		if err != nil {
+			return err
+		}
+
+		volumes.BackupsSize, err = c.asker.AskString("What size should the backups volume of each system have? (empty for no limit)", "", validate.Optional(validate.IsSize))
+		if err != nil {
+			return err
+		}
+	}
+
+	c.volumes = volumes
+
+	return nil
+}
+
+// loadSetupConfig sets up the storage pool and network names, the volumes, the maximum size of the local storage pools, the performance tiers and the OVN encapsulation,
+// recorded in the MicroCloud daemon configuration when setting up MicroCloud, along with the expected cluster members.
+func (c *initConfig) loadSetupConfig(ctx context.Context, sh *service.Handler) error {
+	client, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	config, err := cloudClient.GetConfig(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	sh.Services[types.LXD].(*service.LXDService).SetResourceNames(service.ResourceNamesFromConfig(config))
+	c.volumes = volumeOptionsFromConfig(config)
+	c.limits.Storage.Local = config[types.ConfigLocalPoolLimit]
+	c.cephTiers, err = service.ParseCephTiers(config[types.ConfigCephTiers])
+	if err != nil {
+		return fmt.Errorf("Invalid %q configuration: %w", types.ConfigCephTiers, err)
+	}
+
+	c.ovnEncapsulation = config[types.ConfigOVNEncapsulation]
+	c.expectedMembers, err = service.ParseExpectedMembers(config[types.ConfigMembersExpected])
+	if err != nil {
+		return fmt.Errorf("Invalid %q configuration: %w", types.ConfigMembersExpected, err)
+	}
+
+	return nil
+}
+
+// saveSetupConfig records the custom storage pool and network names, the volumes, the maximum size of the local storage pools, the performance tiers and the OVN encapsulation,
+// in the MicroCloud daemon configuration, so systems added later on are set up the same way.
+func (c *initConfig) saveSetupConfig(ctx context.Context, sh *service.Handler) error {
+	config := sh.Services[types.LXD].(*service.LXDService).ResourceNames().Config()
+	maps.Copy(config, c.volumes.config())
+	if c.limits.Storage.Local != "" {
+		config[types.ConfigLocalPoolLimit] = c.limits.Storage.Local
+	}
+
+	if len(c.cephTiers) > 0 {
+		config[types.ConfigCephTiers] = service.FormatCephTiers(c.cephTiers)
+	}
+
+	if c.ovnEncapsulation != "" {
+		config[types.ConfigOVNEncapsulation] = c.ovnEncapsulation
+	}
+
+	if len(config) == 0 {
+		return nil
+	}
+
+	client, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		return err
+	}
+
+	err = cloudClient.UpdateConfig(ctx, client, config)
+	if err != nil {
+		return fmt.Errorf("Failed to record the MicroCloud setup, set it with \"microcloud config set\": %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+type volumesSuite struct {
+	suite.Suite
+}
+
+func TestVolumesSuite(t *testing.T) {
+	suite.Run(t, new(volumesSuite))
+}
+
+func (s *volumesSuite) Test_memberPool() {
+	names := service.DefaultResourceNames()
+	localOnly := InitSystem{TargetStoragePools: []lxdAPI.StoragePoolsPost{{Name: names.LocalPool}}}
+	both := InitSystem{JoinConfig: []lxdAPI.ClusterMemberConfigKey{
+		{Entity: "storage-pool", Name: names.LocalPool, Key: "source", Value: "/dev/sdb"},
+		{Entity: "storage-pool", Name: names.RemotePool, Key: "source", Value: service.DefaultCephOSDPool},
+	}}
+
+	cases := []struct {
+		desc    string
+		volumes VolumeOptions
+		system  InitSystem
+		pool    string
+	}{
+		{desc: "Default on the local pool", volumes: VolumeOptions{}, system: localOnly, pool: names.LocalPool},
+		{desc: "No storage pools", volumes: VolumeOptions{}, system: InitSystem{}, pool: ""},
+		{desc: "Remote pool missing", volumes: VolumeOptions{Pool: volumesPoolRemote}, system: localOnly, pool: ""},
+		{desc: "Remote pool", volumes: VolumeOptions{Pool: volumesPoolRemote}, system: both, pool: names.RemotePool},
+		{desc: "No volumes", volumes: VolumeOptions{Pool: volumesPoolNone}, system: both, pool: ""},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.pool, c.volumes.memberPool(c.system, names))
+	}
+}
+
+func (s *volumesSuite) Test_volumeConfig() {
+	s.Equal("images", VolumeOptions{}.volumeName("images", "micro01"))
+	s.Equal("backups-micro01", VolumeOptions{Pool: volumesPoolRemote}.volumeName("backups", "micro01"))
+
+	volumes := VolumeOptions{Pool: volumesPoolLocal, ImagesSize: "50GiB"}
+	s.Equal("50GiB", volumes.volumeSize("images"))
+	s.Equal("", volumes.volumeSize("backups"))
+	s.Equal(map[string]string{types.ConfigVolumesImagesSize: "50GiB"}, volumes.config())
+
+	volumes = VolumeOptions{Pool: volumesPoolRemote, BackupsSize: "100GiB"}
+	s.Equal(volumes, volumeOptionsFromConfig(volumes.config()))
+
+	s.NoError(volumes.validate())
+	s.Error(VolumeOptions{Pool: "remote-fs"}.validate())
+	s.Error(VolumeOptions{ImagesSize: "lots"}.validate())
+}
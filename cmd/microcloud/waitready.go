@@ -3,18 +3,24 @@ package main
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/canonical/lxd/shared/logger"
 	"github.com/canonical/microcluster/v3/microcluster"
 	"github.com/spf13/cobra"
 
+	"github.com/canonical/microcloud/microcloud/api/types"
 	"github.com/canonical/microcloud/microcloud/service"
 )
 
 type cmdWaitready struct {
 	common *CmdControl
 
-	flagTimeout int
+	flagTimeout  int
+	flagServices []string
+	flagAll      bool
 }
 
 // command returns the subcommand for waiting on the daemon to be ready.
@@ -22,20 +28,77 @@ func (c *cmdWaitready) command() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "waitready",
 		Short: "Wait for MicroCloud to be ready to process requests",
-		RunE:  c.run,
+		Long: `Wait for MicroCloud to be ready to process requests
+
+By default, waits for MicroCloud and LXD on the local system.
+Use --service to wait for specific services only, or --all to wait for every installed service.`,
+		RunE: c.run,
 	}
 
 	cmd.Flags().IntVarP(&c.flagTimeout, "timeout", "t", 0, "Number of seconds to wait before giving up"+"``")
+	cmd.Flags().StringSliceVar(&c.flagServices, "service", nil, "Service to wait for (microcloud|lxd|microceph|microovn), can be given multiple times"+"``")
+	cmd.Flags().BoolVar(&c.flagAll, "all", false, "Wait for all installed services")
+	cmd.MarkFlagsMutuallyExclusive("service", "all")
+
+	_ = cmd.RegisterFlagCompletionFunc("service", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"microcloud", "lxd", "microceph", "microovn"}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
 
+// waitServices returns the services to wait for, in the order they should be waited on.
+func (c *cmdWaitready) waitServices() ([]types.ServiceType, error) {
+	services := []types.ServiceType{types.MicroCloud, types.LXD}
+	if c.flagAll {
+		for _, serviceType := range []types.ServiceType{types.MicroCeph, types.MicroOVN} {
+			if service.Exists(serviceType, addableServices[serviceType]) {
+				services = append(services, serviceType)
+			}
+		}
+	}
+
+	if len(c.flagServices) == 0 {
+		return services, nil
+	}
+
+	services = []types.ServiceType{}
+	for _, name := range c.flagServices {
+		var found types.ServiceType
+		for _, serviceType := range []types.ServiceType{types.MicroCloud, types.LXD, types.MicroCeph, types.MicroOVN} {
+			if strings.EqualFold(name, string(serviceType)) {
+				found = serviceType
+			}
+		}
+
+		if found == "" {
+			return nil, withExitCode(ExitCodeUsage, fmt.Errorf("Unsupported service %q", name))
+		}
+
+		if !slices.Contains(services, found) {
+			services = append(services, found)
+		}
+	}
+
+	// The other services are reached through MicroCloud, so it always has to be ready first.
+	if !slices.Contains(services, types.MicroCloud) {
+		services = append([]types.ServiceType{types.MicroCloud}, services...)
+	}
+
+	return services, nil
+}
+
 // run runs the subcommand for waiting on the daemon to be ready.
 func (c *cmdWaitready) run(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		return cmd.Help()
 	}
 
+	services, err := c.waitServices()
+	if err != nil {
+		return err
+	}
+
 	options := microcluster.Args{StateDir: c.common.FlagMicroCloudDir}
 	m, err := microcluster.App(options)
 	if err != nil {
@@ -52,12 +115,52 @@ func (c *cmdWaitready) run(cmd *cobra.Command, args []string) error {
 		defer cancel()
 	}
 
-	// First wait for the local MicroCloud daemon.
-	err = m.Ready(ctx)
-	if err != nil {
-		return fmt.Errorf("Failed waiting for the MicroCloud daemon: %w", err)
+	for _, serviceType := range services {
+		switch serviceType {
+		case types.MicroCloud:
+			// First wait for the local MicroCloud daemon.
+			err = m.Ready(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed waiting for the MicroCloud daemon: %w", err)
+			}
+
+		case types.LXD:
+			err = c.waitLXD(ctx)
+			if err != nil {
+				return err
+			}
+
+		case types.MicroCeph:
+			cephService, err := service.NewCephService("", "", c.common.FlagMicroCloudDir)
+			if err != nil {
+				return fmt.Errorf("Failed to create MicroCeph service: %w", err)
+			}
+
+			err = cephService.Ready(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed waiting for the MicroCeph daemon: %w", err)
+			}
+
+		case types.MicroOVN:
+			ovnService, err := service.NewOVNService("", "", c.common.FlagMicroCloudDir)
+			if err != nil {
+				return fmt.Errorf("Failed to create MicroOVN service: %w", err)
+			}
+
+			err = ovnService.Ready(ctx)
+			if err != nil {
+				return fmt.Errorf("Failed waiting for the MicroOVN daemon: %w", err)
+			}
+		}
+
+		logger.Info("Service is ready", logger.Ctx{"service": serviceType})
 	}
 
+	return nil
+}
+
+// waitLXD waits for LXD and, if it is initialized, for all of its networks and storage pools to be ready.
+func (c *cmdWaitready) waitLXD(ctx context.Context) error {
 	// Only MicroCloud's state dir is required as we use the proxy to reach out to LXD's unix socket.
 	lxdService, err := service.NewLXDService("", "", c.common.FlagMicroCloudDir)
 	if err != nil {
@@ -65,19 +168,21 @@ func (c *cmdWaitready) run(cmd *cobra.Command, args []string) error {
 	}
 
 	initialized, _ := lxdService.IsInitialized(ctx)
-	if initialized {
-		client, err := lxdService.Client(ctx)
-		if err != nil {
-			return err
-		}
+	if !initialized {
+		return nil
+	}
 
-		// Wait for all networks and storage pools to be ready in LXD.
-		// If no remote storage was configured, wait for the local pool.
-		// If no distributed networking was configured, wait for the FAN network.
-		err = lxdService.WaitReady(ctx, client, true, true)
-		if err != nil {
-			return fmt.Errorf("Failed waiting for the LXD daemon: %w", err)
-		}
+	client, err := lxdService.Client(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Wait for all networks and storage pools to be ready in LXD.
+	// If no remote storage was configured, wait for the local pool.
+	// If no distributed networking was configured, wait for the FAN network.
+	err = lxdService.WaitReady(ctx, client, true, true)
+	if err != nil {
+		return fmt.Errorf("Failed waiting for the LXD daemon: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// alertsInterval is how often the conditions of the alert rules are checked.
+const alertsInterval = 30 * time.Second
+
+// AlertsTask starts a go routine, that periodically checks the conditions of the configured alert rules and delivers the alerts which start firing or get resolved.
+func AlertsTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		ticker := time.NewTicker(alertsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				checkAlerts(ctx, sh, s)
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, sh, s)
+}
+
+// checkAlerts checks the conditions of the configured alert rules, and delivers the alerts which started firing or were resolved.
+// The cluster-wide conditions are only checked by the database leader, so their alerts are delivered once for the cluster.
+func checkAlerts(ctx context.Context, sh *service.Handler, s state.State) {
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping alerts")
+		return
+	}
+
+	var config map[string]string
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = database.GetConfig(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the MicroCloud configuration", logger.Ctx{"err": err})
+		return
+	}
+
+	rules, err := service.ParseAlertRules(config[types.ConfigAlertRules])
+	if err != nil {
+		logger.Error("Failed to parse the alert rules", logger.Ctx{"err": err})
+		return
+	}
+
+	leader := false
+	if len(rules) > 0 {
+		leaderClient, err := s.Database().Leader(ctx)
+		if err != nil {
+			logger.Error("Failed to get database leader client", logger.Ctx{"err": err})
+			return
+		}
+
+		leaderInfo, err := leaderClient.Leader(ctx)
+		if err != nil {
+			logger.Error("Failed to get database leader info", logger.Ctx{"err": err})
+			return
+		}
+
+		leader = leaderInfo.Address == s.Address().URL.Host
+	}
+
+	uplink := service.ResourceNamesFromConfig(config).UplinkNetwork
+	evaluated, conditions := sh.LocalAlertConditions(ctx, s.Name(), rules, leader, uplink)
+	alerts := sh.EvaluateAlerts(rules, evaluated, conditions, time.Now())
+	if len(alerts) == 0 {
+		return
+	}
+
+	webhooks := shared.SplitNTrimSpace(config[types.ConfigWebhookURLs], ",", -1, true)
+	mail := service.AlertSMTP{
+		Server: config[types.ConfigAlertSMTPServer],
+		From:   config[types.ConfigAlertSMTPFrom],
+		To:     shared.SplitNTrimSpace(config[types.ConfigAlertSMTPTo], ",", -1, true),
+	}
+
+	for _, alert := range alerts {
+		alert.Member = s.Name()
+		api.RecordEvent(ctx, s, types.EventHealth, alert.String())
+
+		err := service.NotifyAlert(ctx, alert, webhooks, mail)
+		if err != nil {
+			logger.Error("Failed to deliver alert", logger.Ctx{"condition": alert.Condition, "subject": alert.Subject, "err": err})
+		}
+	}
+}
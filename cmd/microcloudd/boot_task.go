@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// bootTimeout is how long a boot step is retried before it is reported as failed. It keeps being retried afterwards.
+const bootTimeout = 15 * time.Minute
+
+// bootAttemptTimeout is how long a single attempt of a boot step may take.
+const bootAttemptTimeout = 30 * time.Second
+
+// BootOrchestratorTask starts a go routine, that waits for the services of this cluster member to get ready in dependency order after the daemon started.
+// LXD retries setting up its storage pools and networks on its own, so the last steps wait until it succeeded.
+func BootOrchestratorTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		// Systems which aren't part of a MicroCloud yet have nothing to wait for.
+		err := s.Database().IsOpen(ctx)
+		if err != nil && !lxdAPI.StatusErrorCheck(err, http.StatusServiceUnavailable) {
+			return
+		}
+
+		steps := service.BootSteps(sh.Services)
+		sh.StartBoot(steps)
+
+		started := time.Now()
+		for _, step := range steps {
+			if !runBootStep(ctx, sh, s, step, started) {
+				return
+			}
+		}
+
+		logger.Info("All services are ready", logger.Ctx{"duration": time.Since(started).Round(time.Second)})
+	}(ctx, sh, s)
+}
+
+// runBootStep retries the given boot step until it succeeds, backing off between attempts.
+// Returns false if the daemon is shutting down.
+func runBootStep(ctx context.Context, sh *service.Handler, s state.State, step string, started time.Time) bool {
+	failed := false
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, bootAttemptTimeout)
+		err := bootStepReady(attemptCtx, sh, s, step)
+		cancel()
+
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if err == nil {
+			sh.UpdateBoot(step, types.BootStepReady, nil)
+			if failed {
+				api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("%s got ready %s after the start", step, time.Since(started).Round(time.Second)))
+			}
+
+			return true
+		}
+
+		if !failed && time.Since(started) > bootTimeout {
+			failed = true
+			sh.UpdateBoot(step, types.BootStepFailed, err)
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("%s isn't ready %s after the start: %v", step, bootTimeout, err))
+		} else if failed {
+			sh.UpdateBoot(step, types.BootStepFailed, err)
+		} else {
+			sh.UpdateBoot(step, types.BootStepWaiting, err)
+		}
+
+		logger.Debug("Boot step isn't ready", logger.Ctx{"step": step, "attempt": attempt, "err": err})
+
+		select {
+		case <-time.After(service.BootRetryDelay(attempt)):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// bootStepReady checks whether the given boot step is complete.
+func bootStepReady(ctx context.Context, sh *service.Handler, s state.State, step string) error {
+	switch step {
+	case service.BootStepDatabase:
+		return s.Database().IsOpen(ctx)
+	case service.BootStepStoragePools:
+		pools, err := sh.Services[types.LXD].(*service.LXDService).UnavailableStoragePools(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(pools) > 0 {
+			return fmt.Errorf("Storage pools are unavailable: %s", strings.Join(pools, ", "))
+		}
+
+		return nil
+	case service.BootStepNetworks:
+		networks, err := sh.Services[types.LXD].(*service.LXDService).UnavailableNetworks(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(networks) > 0 {
+			return fmt.Errorf("Networks are unavailable: %s", strings.Join(networks, ", "))
+		}
+
+		return nil
+	}
+
+	// The service is ready once its daemon answers, whether or not it is part of a cluster.
+	_, err := sh.Services[types.ServiceType(step)].IsInitialized(ctx)
+
+	return err
+}
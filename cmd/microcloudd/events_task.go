@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+)
+
+// pruneEventsInterval is how often expired events are removed from the event history.
+const pruneEventsInterval = time.Hour
+
+// PruneEventsTask starts a go routine, that periodically removes the events older than the configured retention.
+func PruneEventsTask(ctx context.Context, s state.State) {
+	go func(ctx context.Context, s state.State) {
+		ticker := time.NewTicker(pruneEventsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				pruneEvents(ctx, s)
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, s)
+}
+
+// pruneEvents removes the events older than the configured retention.
+func pruneEvents(ctx context.Context, s state.State) {
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping event history pruning")
+		return
+	}
+
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		config, err := database.GetConfig(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		retention := api.DefaultEventsRetention
+		if config[types.ConfigEventsRetention] != "" {
+			retention, err = time.ParseDuration(config[types.ConfigEventsRetention])
+			if err != nil {
+				return err
+			}
+		}
+
+		return database.DeleteEventsBefore(ctx, tx, time.Now().UTC().Add(-retention))
+	})
+	if err != nil {
+		logger.Error("Failed to prune the event history", logger.Ctx{"err": err})
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/microcluster/v3/microcluster/rest"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// listenAddressesFile is the file in the state directory listing the additional addresses the MicroCloud API is served on.
+// It survives restarts of the daemon, so the listeners don't depend on the daemon always being started with the same flags.
+const listenAddressesFile = "listen-addresses"
+
+// persistListenAddresses returns the additional listen addresses of the daemon.
+// If the addresses were set, they replace the ones recorded in the state directory,
+// so an empty list removes all additional listeners. Otherwise the recorded addresses are returned.
+func persistListenAddresses(stateDir string, addresses []string, set bool) ([]string, error) {
+	path := filepath.Join(stateDir, listenAddressesFile)
+	if !set {
+		content, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("Failed to read the listen addresses: %w", err)
+		}
+
+		if len(content) == 0 {
+			return nil, nil
+		}
+
+		return strings.Split(strings.TrimSpace(string(content)), "\n"), nil
+	}
+
+	if len(addresses) == 0 {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("Failed to remove the listen addresses: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	err := os.WriteFile(path, []byte(strings.Join(addresses, "\n")+"\n"), 0600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to record the listen addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// listenerName returns the name of the additional API listener on the given address.
+// The name is derived from the address, so a listener keeps its certificate regardless of the order of the addresses.
+func listenerName(addrPort microTypes.AddrPort) string {
+	address := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+
+		return '-'
+	}, addrPort.Addr().String())
+
+	return fmt.Sprintf("microcloud-listener-%s-%d", address, addrPort.Port())
+}
+
+// additionalListeners returns an additional API listener for each of the given addresses.
+// Addresses without a port will listen on the default MicroCloud port.
+// Each listener has a dedicated certificate named after the listener in the daemon's certificates directory.
+// It is generated on first start for the hostname and addresses of the system, unless a custom certificate with that name is found.
+func additionalListeners(addresses []string, endpoints []rest.Endpoint) (map[string]rest.Server, error) {
+	servers := make(map[string]rest.Server, len(addresses))
+	for _, address := range addresses {
+		addrPort, err := microTypes.ParseAddrPort(util.CanonicalNetworkAddress(address, service.CloudPort))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid listen address %q: %w", address, err)
+		}
+
+		name := listenerName(addrPort)
+		_, ok := servers[name]
+		if ok {
+			return nil, fmt.Errorf("Duplicate listen address %q", address)
+		}
+
+		servers[name] = rest.Server{
+			ServerConfig:         microTypes.ServerConfig{Address: addrPort},
+			PreInit:              true,
+			DedicatedCertificate: true,
+			Resources: []rest.Resources{
+				{
+					PathPrefix: types.APIVersion,
+					Endpoints:  endpoints,
+				},
+			},
+		}
+	}
+
+	return servers, nil
+}
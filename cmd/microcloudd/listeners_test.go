@@ -0,0 +1,113 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type listenersSuite struct {
+	suite.Suite
+}
+
+func TestListenersSuite(t *testing.T) {
+	suite.Run(t, new(listenersSuite))
+}
+
+func (s *listenersSuite) Test_additionalListeners() {
+	cases := []struct {
+		desc      string
+		addresses []string
+		expected  map[string]string
+		expectErr bool
+	}{
+		{
+			desc:     "No addresses",
+			expected: map[string]string{},
+		},
+		{
+			desc:      "Addresses without a port use the MicroCloud port",
+			addresses: []string{"10.0.0.1", "fd42::1", "10.0.0.2:8443"},
+			expected: map[string]string{
+				"microcloud-listener-10-0-0-1-9443": "10.0.0.1:9443",
+				"microcloud-listener-fd42--1-9443":  "[fd42::1]:9443",
+				"microcloud-listener-10-0-0-2-8443": "10.0.0.2:8443",
+			},
+		},
+		{
+			desc:      "Duplicate addresses",
+			addresses: []string{"10.0.0.1", "10.0.0.1:9443"},
+			expectErr: true,
+		},
+		{
+			desc:      "Invalid address",
+			addresses: []string{"micro01:port"},
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		servers, err := additionalListeners(c.addresses, nil)
+		if c.expectErr {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+
+		addresses := make(map[string]string, len(servers))
+		for name, server := range servers {
+			addresses[name] = server.Address.String()
+			s.True(server.PreInit)
+			s.True(server.DedicatedCertificate)
+			s.False(server.CoreAPI)
+		}
+
+		s.Equal(c.expected, addresses)
+	}
+
+	// The listener names don't depend on the order of the addresses.
+	first, err := additionalListeners([]string{"10.0.0.1", "10.0.0.2"}, nil)
+	s.NoError(err)
+	second, err := additionalListeners([]string{"10.0.0.2", "10.0.0.1"}, nil)
+	s.NoError(err)
+	s.Equal(first, second)
+}
+
+func (s *listenersSuite) Test_persistListenAddresses() {
+	stateDir := s.T().TempDir()
+
+	// Nothing is recorded yet.
+	addresses, err := persistListenAddresses(stateDir, nil, false)
+	s.NoError(err)
+	s.Empty(addresses)
+
+	// Setting the addresses records them.
+	addresses, err = persistListenAddresses(stateDir, []string{"10.0.0.1", "fd42::1"}, true)
+	s.NoError(err)
+	s.Equal([]string{"10.0.0.1", "fd42::1"}, addresses)
+	s.FileExists(filepath.Join(stateDir, listenAddressesFile))
+
+	// A restart without the flag uses the recorded addresses.
+	addresses, err = persistListenAddresses(stateDir, nil, false)
+	s.NoError(err)
+	s.Equal([]string{"10.0.0.1", "fd42::1"}, addresses)
+
+	// Setting the addresses again replaces them.
+	addresses, err = persistListenAddresses(stateDir, []string{"10.0.0.2"}, true)
+	s.NoError(err)
+	s.Equal([]string{"10.0.0.2"}, addresses)
+
+	addresses, err = persistListenAddresses(stateDir, nil, false)
+	s.NoError(err)
+	s.Equal([]string{"10.0.0.2"}, addresses)
+
+	// Setting no addresses removes the record.
+	addresses, err = persistListenAddresses(stateDir, []string{}, true)
+	s.NoError(err)
+	s.Empty(addresses)
+	s.NoFileExists(filepath.Join(stateDir, listenAddressesFile))
+}
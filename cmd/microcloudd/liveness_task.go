@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	microClient "github.com/canonical/microcluster/v3/client"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/client"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// livenessInterval is the interval between heartbeats to the other cluster members.
+const livenessInterval = 10 * time.Second
+
+// defaultLivenessThreshold is the number of consecutive missed heartbeats after which a cluster member is reported unreachable, unless configured otherwise.
+const defaultLivenessThreshold = 3
+
+// MemberLivenessTask starts a go routine, that periodically sends heartbeats to the other cluster members to track whether they are reachable.
+func MemberLivenessTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		ticker := time.NewTicker(livenessInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				heartbeatMembers(ctx, sh, s)
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, sh, s)
+}
+
+// heartbeatMembers sends a heartbeat to each of the other cluster members, and records which of them are reachable.
+func heartbeatMembers(ctx context.Context, sh *service.Handler, s state.State) {
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping cluster member heartbeats")
+		sh.ResetMemberLiveness()
+		sh.ResetMemberClocks()
+		return
+	}
+
+	threshold := defaultLivenessThreshold
+	skewThreshold := service.DefaultClockSkewThreshold
+	var expected []service.ExpectedMember
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		config, err := database.GetConfig(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if config[types.ConfigLivenessThreshold] != "" {
+			threshold, err = strconv.Atoi(config[types.ConfigLivenessThreshold])
+			if err != nil {
+				return err
+			}
+		}
+
+		if config[types.ConfigClockSkewThreshold] != "" {
+			skewThreshold, err = time.ParseDuration(config[types.ConfigClockSkewThreshold])
+			if err != nil {
+				return err
+			}
+		}
+
+		expected, err = service.ParseExpectedMembers(config[types.ConfigMembersExpected])
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the MicroCloud configuration", logger.Ctx{"err": err})
+		return
+	}
+
+	cloudClient, err := sh.Services[types.MicroCloud].(*service.CloudService).Client()
+	if err != nil {
+		logger.Error("Failed to get MicroCloud client", logger.Ctx{"err": err})
+		return
+	}
+
+	members, err := cloudClient.GetClusterMembers(ctx)
+	if err != nil {
+		logger.Error("Failed to get MicroCloud cluster members", logger.Ctx{"err": err})
+		return
+	}
+
+	names := make(map[string]string, len(members))
+	memberNames := make([]string, 0, len(members))
+	for _, member := range members {
+		memberNames = append(memberNames, member.Name)
+		if member.Name != s.Name() {
+			names[member.Address.String()] = member.Name
+		}
+	}
+
+	reportMissingMembers(ctx, sh, s, expected, memberNames)
+
+	cluster, err := s.Cluster(false)
+	if err != nil {
+		logger.Error("Failed to get clients for the MicroCloud cluster members", logger.Ctx{"err": err})
+		return
+	}
+
+	var heartbeatsLock sync.Mutex
+	heartbeats := make([]service.Heartbeat, 0, len(names))
+	clockSamples := make([]service.ClockSample, 0, len(names))
+	_ = cluster.Query(ctx, true, func(ctx context.Context, c *microClient.Client) error {
+		address := c.URL().URL.Host
+		name, ok := names[address]
+		if !ok {
+			return nil
+		}
+
+		heartbeatCtx, cancel := context.WithTimeout(ctx, livenessInterval/2)
+		defer cancel()
+
+		err := client.Heartbeat(heartbeatCtx, c)
+
+		heartbeatsLock.Lock()
+		heartbeats = append(heartbeats, service.Heartbeat{Name: name, Address: address, Err: err})
+		heartbeatsLock.Unlock()
+
+		if err != nil {
+			return nil
+		}
+
+		// Measure the clock skew to the reachable members.
+		sent := time.Now()
+		clock, err := client.GetClock(heartbeatCtx, c)
+		if err != nil {
+			logger.Debug("Failed to get the clock of cluster member", logger.Ctx{"name": name, "err": err})
+			return nil
+		}
+
+		sample := service.ClockSample{Name: name, Skew: service.ClockSkew(sent, time.Now(), clock.Time), Synchronized: clock.Synchronized}
+
+		heartbeatsLock.Lock()
+		clockSamples = append(clockSamples, sample)
+		heartbeatsLock.Unlock()
+
+		return nil
+	})
+
+	for _, member := range sh.RecordHeartbeats(heartbeats, threshold) {
+		if member.Reachable {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Cluster member %s is reachable again", member.Name))
+		} else {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Cluster member %s is unreachable: %s", member.Name, member.Error))
+		}
+	}
+
+	for _, change := range sh.RecordClockSamples(clockSamples, memberNames, skewThreshold) {
+		clock := change.Clock
+		if change.SkewChanged && clock.Skewed {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Clock of cluster member %s is off by %s", clock.Name, clock.Skew))
+		} else if change.SkewChanged {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Clock of cluster member %s is back within %s", clock.Name, skewThreshold))
+		}
+
+		if change.SyncChanged && !clock.Synchronized {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Clock of cluster member %s isn't synchronized to a time source", clock.Name))
+		} else if change.SyncChanged {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Clock of cluster member %s is synchronized to a time source again", clock.Name))
+		}
+	}
+}
+
+// reportMissingMembers records health events for the expected cluster members which haven't joined MicroCloud, and for those which joined since.
+// Only the database leader records the events, so they are recorded once for the cluster.
+func reportMissingMembers(ctx context.Context, sh *service.Handler, s state.State, expected []service.ExpectedMember, memberNames []string) {
+	newlyMissing, joined := sh.RecordMissingMembers(service.MissingMembers(expected, memberNames))
+
+	// Members which are no longer expected aren't reported as joined.
+	joined = slices.DeleteFunc(joined, func(name string) bool { return !slices.Contains(memberNames, name) })
+	if len(newlyMissing) == 0 && len(joined) == 0 {
+		return
+	}
+
+	leaderClient, err := s.Database().Leader(ctx)
+	if err != nil {
+		logger.Error("Failed to get database leader client", logger.Ctx{"err": err})
+		return
+	}
+
+	leaderInfo, err := leaderClient.Leader(ctx)
+	if err != nil {
+		logger.Error("Failed to get database leader info", logger.Ctx{"err": err})
+		return
+	}
+
+	if leaderInfo.Address != s.Address().URL.Host {
+		return
+	}
+
+	for _, name := range newlyMissing {
+		api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Expected cluster member %s hasn't joined MicroCloud", name))
+	}
+
+	for _, name := range joined {
+		api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("Expected cluster member %s has joined MicroCloud", name))
+	}
+}
@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"os"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/canonical/microcloud/microcloud/api"
 	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/client"
 	"github.com/canonical/microcloud/microcloud/database"
 	"github.com/canonical/microcloud/microcloud/service"
 	"github.com/canonical/microcloud/microcloud/version"
@@ -53,6 +55,7 @@ type cmdDaemon struct {
 
 	flagMicroCloudDir     string
 	flagHeartbeatInterval time.Duration
+	flagListenAddresses   []string
 }
 
 // command returns the main microcloudd command.
@@ -132,6 +135,8 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 		api.StatusCmd(s),
 		api.ServicesCmd(s),
 		api.ServiceTokensCmd(s),
+		api.ServiceRestartCmd(s),
+		api.ServiceRefreshCmd(s),
 		api.ServicesClusterCmd(s),
 		api.SessionJoinCmd(s),
 		api.SessionInitiatingCmd(s),
@@ -139,6 +144,34 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 		api.SessionStopCmd(s),
 		api.ClusterManagersCmd(s),
 		api.ClusterManagersJoinCmd(s),
+		api.ConfigCmd(s),
+		api.BenchmarksCmd(s),
+		api.NetworkTestServerCmd(s),
+		api.NetworkTestCmd(s),
+		api.OVNUnderlayCmd(s),
+		api.OperationsCmd(s),
+		api.OperationCmd(s),
+		api.EventsCmd(s),
+		api.MembersCmd(s),
+		api.JoinStatesCmd(s),
+		api.JoinProgressCmd(s),
+		api.ClockCmd(s),
+		api.ReachabilityCmd(s),
+		api.DocCmd(s),
+		api.PlatformCmd(s),
+		api.ServiceVersionsCmd(s),
+		api.InspectCmd(s),
+		api.WarningsCmd(s),
+		api.WarningCmd(s),
+		api.TuningCmd(s),
+		api.ReportsCmd(s),
+		api.ReportCmd(s),
+		api.VerifyCmd(s),
+		api.MetricsCmd(s),
+		api.DisksWipeCmd(s),
+		api.DisksSystemCmd(s),
+		api.AuthTokensCmd(s),
+		api.AuthTokenCmd(s),
 		api.LXDProxy(s),
 		api.CephProxy(s),
 		api.OVNProxy(s),
@@ -162,6 +195,37 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	client.SetDebugLogging(c.global.flagLogDebug)
+
+	extensionServers := map[string]rest.Server{
+		"microcloud": {
+			CoreAPI:   true,
+			PreInit:   true,
+			ServeUnix: true,
+			Resources: []rest.Resources{
+				{
+					PathPrefix: types.APIVersion,
+					Endpoints:  endpoints,
+				},
+			},
+		},
+	}
+
+	cloudService := s.Services[types.MicroCloud].(*service.CloudService)
+	listenAddresses, err := persistListenAddresses(cloudService.StateDir(), c.flagListenAddresses, cmd.Flags().Changed("listen"))
+	if err != nil {
+		return err
+	}
+
+	listenServers, err := additionalListeners(listenAddresses, endpoints)
+	if err != nil {
+		return err
+	}
+
+	for name, server := range listenServers {
+		extensionServers[name] = server
+	}
+
 	dargs := microcluster.DaemonArgs{
 		Version:           version.RawVersion,
 		HeartbeatInterval: c.flagHeartbeatInterval,
@@ -171,6 +235,8 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 		PreInitListenAddress: "[::]:" + strconv.FormatInt(service.CloudPort, 10),
 		Hooks: &state.Hooks{
 			PostBootstrap: func(ctx context.Context, state state.State, initConfig map[string]string) error {
+				api.RecordEvent(ctx, state, types.EventMemberJoined, "Bootstrapped the cluster")
+
 				return setHandlerAddress(state.Address().URL.Host)
 			},
 			PostJoin: func(ctx context.Context, state state.State, cfg map[string]string) error {
@@ -183,10 +249,45 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 				case <-ctx.Done():
 				}
 
+				api.RecordEvent(ctx, state, types.EventMemberJoined, "Joined the cluster")
+
 				return setHandlerAddress(state.Address().URL.Host)
 			},
+			PreRemove: func(ctx context.Context, state state.State, force bool) error {
+				// Record the removal while the member can still reach the database.
+				if force {
+					api.RecordEvent(ctx, state, types.EventMemberRemoved, "Forcefully removed from the cluster")
+				} else {
+					api.RecordEvent(ctx, state, types.EventMemberRemoved, "Removed from the cluster")
+				}
+
+				err := state.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					return database.DeleteJoinStates(ctx, tx, state.Name())
+				})
+				if err != nil {
+					logger.Error("Failed to remove join states", logger.Ctx{"error": err})
+				}
+
+				err = state.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+					return database.DeleteTuning(ctx, tx, state.Name())
+				})
+				if err != nil {
+					logger.Error("Failed to remove tuning settings", logger.Ctx{"error": err})
+				}
+
+				return nil
+			},
 			OnStart: func(ctx context.Context, state state.State) error {
 				SendClusterManagerStatusMessageTask(ctx, s, state)
+				OVNUnderlayWatchdogTask(ctx, s, state)
+				MemberLivenessTask(ctx, s, state)
+				PruneEventsTask(ctx, state)
+				BootOrchestratorTask(ctx, s, state)
+				SnapRefreshHoldTask(ctx, s, state)
+				AlertsTask(ctx, s, state)
+				ReportsTask(ctx, s, state)
+				VerifyTask(ctx, s, state)
+				RequestLimitsTask(ctx, s, state)
 
 				// If we are already initialized, there's nothing to do.
 				err := state.Database().IsOpen(ctx)
@@ -207,6 +308,9 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 					return nil
 				}
 
+				// Sysctls don't persist across reboots, so apply the recorded kernel tuning again.
+				reapplyTuning(ctx, state)
+
 				// Create initialization context with timeout defined in LXDInitializationTimeout.
 				initializationCtx, cancel := context.WithTimeout(ctx, LXDInitializationTimeout)
 				defer cancel()
@@ -249,26 +353,18 @@ func (c *cmdDaemon) run(cmd *cobra.Command, args []string) error {
 					logger.Error("Failed to update LXD configuration on start", logger.Ctx{"error": err})
 				}
 
+				if ok && val != "" {
+					api.RecordEvent(ctx, state, types.EventUpgrade, fmt.Sprintf("Upgraded MicroCloud from %s to %s", val, version.RawVersion))
+				}
+
 				return nil
 			},
 		},
 
-		ExtensionServers: map[string]rest.Server{
-			"microcloud": {
-				CoreAPI:   true,
-				PreInit:   true,
-				ServeUnix: true,
-				Resources: []rest.Resources{
-					{
-						PathPrefix: types.APIVersion,
-						Endpoints:  endpoints,
-					},
-				},
-			},
-		},
+		ExtensionServers: extensionServers,
 	}
 
-	return s.Services[types.MicroCloud].(*service.CloudService).StartCloud(context.Background(), dargs)
+	return cloudService.StartCloud(context.Background(), dargs)
 }
 
 func main() {
@@ -290,6 +386,7 @@ func main() {
 
 	app.PersistentFlags().StringVar(&daemonCmd.flagMicroCloudDir, "state-dir", "", "Path to store state information for MicroCloud"+"``")
 	app.PersistentFlags().DurationVar(&daemonCmd.flagHeartbeatInterval, "heartbeat", time.Second*10, "Time between attempted heartbeats")
+	app.PersistentFlags().StringSliceVar(&daemonCmd.flagListenAddresses, "listen", nil, "Additional addresses on which to serve the MicroCloud API, remembered across restarts until set again"+"``")
 
 	app.SetVersionTemplate("{{.Version}}\n")
 
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// ovnUnderlayCheckInterval is how often the configuration is checked while the OVN underlay watchdog is disabled.
+const ovnUnderlayCheckInterval = time.Minute
+
+// OVNUnderlayWatchdogTask starts a go routine, that periodically probes the OVN underlay paths of this cluster member.
+func OVNUnderlayWatchdogTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		ticker := time.NewTicker(ovnUnderlayCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				interval := probeOVNUnderlay(ctx, sh, s)
+				if interval > 0 {
+					ticker.Reset(interval)
+				} else {
+					ticker.Reset(ovnUnderlayCheckInterval)
+				}
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, sh, s)
+}
+
+// probeOVNUnderlay probes the OVN underlay paths if the watchdog is enabled, and returns the configured interval until the next probe.
+func probeOVNUnderlay(ctx context.Context, sh *service.Handler, s state.State) time.Duration {
+	if sh.Services[types.MicroOVN] == nil {
+		sh.ResetOVNUnderlay()
+		return 0
+	}
+
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping OVN underlay probe")
+		return 0
+	}
+
+	var config map[string]string
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = database.GetConfig(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the MicroCloud configuration", logger.Ctx{"err": err})
+		return 0
+	}
+
+	value := config[types.ConfigOVNWatchdogInterval]
+	if value == "" {
+		sh.ResetOVNUnderlay()
+		return 0
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Error("Failed to parse OVN underlay watchdog interval", logger.Ctx{"err": err})
+		return 0
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+
+	changed, err := sh.ProbeOVNUnderlay(probeCtx)
+	if err != nil {
+		logger.Error("Failed to probe the OVN underlay", logger.Ctx{"err": err})
+	}
+
+	for _, path := range changed {
+		if path.Reachable {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("OVN underlay path to %s recovered", path.RemoteAddress))
+		} else {
+			api.RecordEvent(ctx, s, types.EventHealth, fmt.Sprintf("OVN underlay path to %s is down: %s", path.RemoteAddress, path.Error))
+		}
+	}
+
+	return interval
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// reportsInterval is how often the schedule of the health reports is checked.
+const reportsInterval = time.Hour
+
+// ReportsTask starts a go routine, that periodically generates a health report of the cluster on the configured schedule.
+func ReportsTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		ticker := time.NewTicker(reportsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				generateScheduledReport(ctx, sh, s)
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, sh, s)
+}
+
+// generateScheduledReport generates and stores a health report once the configured schedule is due, and posts it to the configured URL.
+// Only the database leader generates reports, so there is a single report per period for the cluster.
+// Reports which are due outside of the maintenance windows are generated once the next window opens.
+func generateScheduledReport(ctx context.Context, sh *service.Handler, s state.State) {
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping health report")
+		return
+	}
+
+	var config map[string]string
+	var latest time.Time
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = database.GetConfig(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		latest, err = database.GetLatestReportTime(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the health report schedule", logger.Ctx{"err": err})
+		return
+	}
+
+	schedule := config[types.ConfigReportsSchedule]
+	if schedule == "" || time.Since(latest) < service.ReportPeriod(schedule) {
+		return
+	}
+
+	open, err := service.InMaintenanceWindow(config[types.ConfigMaintenanceWindows], time.Now())
+	if err != nil {
+		logger.Error("Failed to parse the maintenance windows", logger.Ctx{"err": err})
+		return
+	}
+
+	if !open {
+		logger.Debug("Outside of the maintenance windows, skipping health report")
+		return
+	}
+
+	leaderClient, err := s.Database().Leader(ctx)
+	if err != nil {
+		logger.Error("Failed to get database leader client", logger.Ctx{"err": err})
+		return
+	}
+
+	leaderInfo, err := leaderClient.Leader(ctx)
+	if err != nil {
+		logger.Error("Failed to get database leader info", logger.Ctx{"err": err})
+		return
+	}
+
+	if leaderInfo.Address != s.Address().URL.Host {
+		return
+	}
+
+	report, err := api.GenerateHealthReport(ctx, sh, s)
+	if err != nil {
+		logger.Error("Failed to generate health report", logger.Ctx{"err": err})
+		return
+	}
+
+	api.RecordEvent(ctx, s, types.EventReport, fmt.Sprintf("Generated %s health report %d", schedule, report.ID))
+
+	if config[types.ConfigReportsURL] != "" {
+		err := service.PostHealthReport(ctx, config[types.ConfigReportsURL], *report)
+		if err != nil {
+			logger.Error("Failed to post health report", logger.Ctx{"report": report.ID, "err": err})
+		}
+	}
+}
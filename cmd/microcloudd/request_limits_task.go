@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// requestLimitsInterval is how often the configured request limits of the MicroCloud API are reloaded.
+const requestLimitsInterval = 10 * time.Second
+
+// RequestLimitsTask starts a go routine, that periodically applies the configured rate limit and quota to the requests of the MicroCloud API.
+func RequestLimitsTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		ticker := time.NewTicker(requestLimitsInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				updateRequestLimits(ctx, sh, s)
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, sh, s)
+}
+
+// updateRequestLimits loads the rate limit and quota of the MicroCloud API from the configuration, and applies them.
+// The previous limits are kept if the configuration can't be loaded.
+func updateRequestLimits(ctx context.Context, sh *service.Handler, s state.State) {
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping request limits")
+		return
+	}
+
+	var config map[string]string
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = database.GetConfig(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the MicroCloud configuration", logger.Ctx{"err": err})
+		return
+	}
+
+	limits := service.RequestLimits{}
+	if config[types.ConfigAPIRateLimit] != "" {
+		limits.RateRequests, limits.RatePeriod, err = service.ParseRequestLimit(config[types.ConfigAPIRateLimit])
+		if err != nil {
+			logger.Error("Failed to parse the rate limit of the MicroCloud API", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	if config[types.ConfigAPIQuota] != "" {
+		limits.QuotaRequests, limits.QuotaPeriod, err = service.ParseRequestLimit(config[types.ConfigAPIQuota])
+		if err != nil {
+			logger.Error("Failed to parse the quota of the MicroCloud API", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	sh.SetRequestLimits(limits, time.Now())
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// snapHoldCheckInterval is how often the snap refresh holds are reconciled with the configuration.
+const snapHoldCheckInterval = time.Minute
+
+// snapHoldFile is the file in the state directory listing the snaps whose refreshes are held by MicroCloud.
+// It survives restarts of the daemon, so the holds can still be released once the configuration is changed.
+const snapHoldFile = "snap-refresh-hold"
+
+// SnapRefreshHoldTask starts a go routine, that periodically holds or releases the automatic snap refreshes of this cluster member as configured.
+func SnapRefreshHoldTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		reconcileSnapHolds(ctx, sh, s)
+
+		ticker := time.NewTicker(snapHoldCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				reconcileSnapHolds(ctx, sh, s)
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, sh, s)
+}
+
+// reconcileSnapHolds holds the automatic refreshes of the MicroCloud snaps if configured or outside of the maintenance windows,
+// or releases the holds MicroCloud set earlier.
+func reconcileSnapHolds(ctx context.Context, sh *service.Handler, s state.State) {
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping snap refresh holds")
+		return
+	}
+
+	var config map[string]string
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = database.GetConfig(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the MicroCloud configuration", logger.Ctx{"err": err})
+		return
+	}
+
+	hold := false
+	if config[types.ConfigSnapRefreshHold] != "" {
+		hold, err = strconv.ParseBool(config[types.ConfigSnapRefreshHold])
+		if err != nil {
+			logger.Error("Failed to parse the snap refresh hold", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	// Outside of the maintenance windows, the snaps are held so they only refresh within them.
+	if !hold {
+		open, err := service.InMaintenanceWindow(config[types.ConfigMaintenanceWindows], time.Now())
+		if err != nil {
+			logger.Error("Failed to parse the maintenance windows", logger.Ctx{"err": err})
+			return
+		}
+
+		hold = !open
+	}
+
+	path := filepath.Join(s.FileSystem().StateDir(), snapHoldFile)
+	held := []string{}
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Error("Failed to read the held snaps", logger.Ctx{"err": err})
+		return
+	}
+
+	if len(content) > 0 {
+		held = strings.Split(strings.TrimSpace(string(content)), "\n")
+	}
+
+	if hold {
+		snaps := service.HeldSnaps(sh.Services)
+		if slices.Equal(snaps, held) {
+			return
+		}
+
+		err = service.HoldSnapRefreshes(ctx, snaps, true)
+		if err != nil {
+			logger.Error("Failed to hold the snap refreshes", logger.Ctx{"err": err})
+			return
+		}
+
+		err = os.WriteFile(path, []byte(strings.Join(snaps, "\n")+"\n"), 0600)
+		if err != nil {
+			logger.Error("Failed to record the held snaps", logger.Ctx{"err": err})
+			return
+		}
+
+		api.RecordEvent(ctx, s, types.EventUpgrade, fmt.Sprintf("Held the automatic refreshes of snaps %s", strings.Join(snaps, ", ")))
+
+		return
+	}
+
+	if len(held) == 0 {
+		return
+	}
+
+	// Only release the holds set by MicroCloud, so holds set by the operator are left alone.
+	err = service.HoldSnapRefreshes(ctx, held, false)
+	if err != nil {
+		logger.Error("Failed to release the snap refresh holds", logger.Ctx{"err": err})
+		return
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		logger.Error("Failed to remove the record of the held snaps", logger.Ctx{"err": err})
+		return
+	}
+
+	api.RecordEvent(ctx, s, types.EventUpgrade, fmt.Sprintf("Released the automatic refreshes of snaps %s", strings.Join(held, ", ")))
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// reapplyTuning applies the kernel settings recorded for this cluster member again, unless they are already at least as high.
+func reapplyTuning(ctx context.Context, s state.State) {
+	var records []database.TuningSetting
+	err := s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		records, err = database.GetTuning(ctx, tx, s.Name())
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the kernel tuning settings", logger.Ctx{"err": err})
+		return
+	}
+
+	if len(records) == 0 {
+		return
+	}
+
+	targets := make([]service.Sysctl, 0, len(records))
+	for _, record := range records {
+		targets = append(targets, service.Sysctl{Key: record.Key, Value: record.Value})
+	}
+
+	current, err := service.CurrentSysctls(targets)
+	if err != nil {
+		logger.Error("Failed to read the kernel tuning settings", logger.Ctx{"err": err})
+		return
+	}
+
+	err = service.ApplyTuning(service.PlanTuning(current, targets))
+	if err != nil {
+		logger.Error("Failed to apply the kernel tuning settings", logger.Ctx{"err": err})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/microcluster/v3/state"
+
+	"github.com/canonical/microcloud/microcloud/api"
+	"github.com/canonical/microcloud/microcloud/api/types"
+	"github.com/canonical/microcloud/microcloud/database"
+	"github.com/canonical/microcloud/microcloud/service"
+)
+
+// verifyCheckInterval is how often the configuration is checked while the scheduled verification is disabled.
+const verifyCheckInterval = time.Minute
+
+// verifyTimeout is how long a scheduled verification may take.
+const verifyTimeout = 2 * time.Minute
+
+// VerifyTask starts a go routine, that periodically verifies this cluster member on the configured schedule.
+func VerifyTask(ctx context.Context, sh *service.Handler, s state.State) {
+	go func(ctx context.Context, sh *service.Handler, s state.State) {
+		ticker := time.NewTicker(verifyCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				interval := verifyMember(ctx, sh, s)
+				if interval > 0 {
+					ticker.Reset(interval)
+				} else {
+					ticker.Reset(verifyCheckInterval)
+				}
+
+			case <-ctx.Done():
+				return // exit the loop and close the go routine
+			}
+		}
+	}(ctx, sh, s)
+}
+
+// verifyMember verifies this cluster member if the verification is scheduled, records the result in the event history,
+// and returns the configured interval until the next verification.
+func verifyMember(ctx context.Context, sh *service.Handler, s state.State) time.Duration {
+	err := s.Database().IsOpen(ctx)
+	if err != nil {
+		logger.Debug("MicroCloud database is not ready, skipping verification")
+		return 0
+	}
+
+	var config map[string]string
+	err = s.Database().Transaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		config, err = database.GetConfig(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to load the MicroCloud configuration", logger.Ctx{"err": err})
+		return 0
+	}
+
+	value := config[types.ConfigVerifySchedule]
+	if value == "" {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		logger.Error("Failed to parse verification schedule", logger.Ctx{"err": err})
+		return 0
+	}
+
+	verifyCtx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	checks := sh.Verify(verifyCtx, s.Name())
+	for _, check := range checks {
+		if check.Error != "" {
+			logger.Warn("Verification check failed", logger.Ctx{"check": check.Check, "target": check.Target, "err": check.Error})
+		}
+	}
+
+	api.RecordEvent(ctx, s, types.EventVerify, service.VerificationMessage(checks))
+
+	return interval
+}
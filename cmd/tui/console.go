@@ -113,6 +113,10 @@ func (c *testConsole) parseInput(handler *InputHandler) error {
 		action = ansi.CUD1
 	case "table:up":
 		action = ansi.CUU1
+	case "table:page-down":
+		action = "\x1b[6~"
+	case "table:page-up":
+		action = "\x1b[5~"
 	case "table:select-none":
 		action = ansi.CUB1
 	case "table:select-all":
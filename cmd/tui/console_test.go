@@ -104,6 +104,26 @@ func (s *inputSuite) Test_parseInput() {
 			in:  []string{"table:filter afdasdfa", "table:select", "table:done"},
 			out: []map[string]string{},
 		},
+		{
+			in:  []string{"table:filter b:2", "table:select", "table:done"},
+			out: []map[string]string{{"a": "a2", "b": "b2"}},
+		},
+		{
+			in:  []string{"table:filter a:b", "table:select", "table:done"},
+			out: []map[string]string{},
+		},
+		{
+			in:  []string{"table:select-all", "table:filter a2", "table:select-none", "table:done"},
+			out: []map[string]string{{"a": "a1", "b": "b1"}},
+		},
+		{
+			in:  []string{"table:page-down", "table:select", "table:done"},
+			out: []map[string]string{{"a": "a2", "b": "b2"}},
+		},
+		{
+			in:  []string{"table:page-down", "table:page-up", "table:select", "table:done"},
+			out: []map[string]string{{"a": "a1", "b": "b1"}},
+		},
 		{
 			in:  []string{"table:select", "table:done"},
 			out: []map[string]string{{"a": "a1", "b": "b1"}},
@@ -34,8 +34,14 @@ type InputHandler struct {
 	// testMode is set to true if the handler is initialized in test mode with PrepareTestAsker.
 	testMode bool
 
+	// plain is set to true if interactive selectors are replaced with simple numbered prompts.
+	plain bool
+
 	table *selectableTable
 
+	// tablePrefs persists the preferences of selectable tables between runs, if set.
+	tablePrefs TablePreferenceStore
+
 	activeMu sync.RWMutex
 	active   bool
 	activeCh chan struct{}
@@ -51,6 +57,35 @@ func NewInputHandler(input *os.File, output *os.File) *InputHandler {
 	}
 }
 
+// TablePreferences are the display preferences of a selectable table.
+type TablePreferences struct {
+	SortColumn     string   `yaml:"sort_column,omitempty"`
+	SortDescending bool     `yaml:"sort_descending,omitempty"`
+	HiddenColumns  []string `yaml:"hidden_columns,omitempty"`
+}
+
+// TablePreferenceStore loads and stores the preferences of selectable tables, keyed by a table identifier.
+type TablePreferenceStore interface {
+	TablePreferences(id string) TablePreferences
+	SetTablePreferences(id string, prefs TablePreferences) error
+}
+
+// SetPlain sets whether interactive selectors should be replaced with simple numbered prompts,
+// for use on limited terminals and with screen readers.
+func (i *InputHandler) SetPlain(plain bool) {
+	i.plain = plain
+}
+
+// IsTestMode returns whether the handler reads its input from the test console.
+func (i *InputHandler) IsTestMode() bool {
+	return i.testMode
+}
+
+// SetTablePreferenceStore sets the store used to persist the sort order and hidden columns of selectable tables.
+func (i *InputHandler) SetTablePreferenceStore(store TablePreferenceStore) {
+	i.tablePrefs = store
+}
+
 func (i *InputHandler) setActive(active bool) {
 	i.activeMu.Lock()
 	defer i.activeMu.Unlock()
@@ -175,6 +210,29 @@ func (i *InputHandler) AskPassphrase(question string, suggestions []string, vali
 	defer i.setActive(false)
 
 	for {
+		// Plain prompts can't render autocomplete suggestions, so read the passphrase directly.
+		if i.plain {
+			answer, err := i.askQuestion(question+" ", "")
+			if err != nil {
+				return "", err
+			}
+
+			if validator != nil {
+				err := validator(answer)
+				if err != nil {
+					InvalidInputError(err)
+					continue
+				}
+			}
+
+			if answer != "" {
+				return answer, nil
+			}
+
+			InvalidInputError(nil)
+			continue
+		}
+
 		// Start a Bubble Tea program with autocomplete model.
 		m := autocompleteModel(question, suggestions, maxTokens, i.testMode)
 		p := tea.NewProgram(m, tea.WithInput(i.input), tea.WithOutput(i.output))
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// plainTableHelp explains the answers accepted by a table rendered in plain mode.
+const plainTableHelp = "Enter the numbers of the rows to select, separated by commas (e.g. 1,3-5), \"all\" or \"none\".\nPress enter without any input to refresh the list"
+
+// parseSelection parses a plain mode table selection over the given number of rows, and returns the selected row indexes.
+// Rows are numbered starting from 1, and ranges of rows can be given as <start>-<end>.
+func parseSelection(input string, count int) ([]int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	switch input {
+	case "all":
+		selection := make([]int, 0, count)
+		for i := range count {
+			selection = append(selection, i)
+		}
+
+		return selection, nil
+	case "none":
+		return []int{}, nil
+	}
+
+	selected := map[int]bool{}
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		startStr, endStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			endStr = startStr
+		}
+
+		start, err := strconv.Atoi(strings.TrimSpace(startStr))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid row number %q", part)
+		}
+
+		end, err := strconv.Atoi(strings.TrimSpace(endStr))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid row number %q", part)
+		}
+
+		if start < 1 || end > count || start > end {
+			return nil, fmt.Errorf("Row selection %q is out of range (1-%d)", part, count)
+		}
+
+		for i := start; i <= end; i++ {
+			selected[i-1] = true
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("No rows selected")
+	}
+
+	selection := make([]int, 0, len(selected))
+	for i := range selected {
+		selection = append(selection, i)
+	}
+
+	sort.Ints(selection)
+
+	return selection, nil
+}
+
+// renderPlain prints the table rows as a numbered list and asks for the selection with a simple prompt.
+// It is used instead of the interactive table on limited terminals and for screen readers.
+func (s *selectableTable) renderPlain(ctx context.Context, handler *InputHandler) error {
+	s.Init()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		s.tableMu.Lock()
+		fmt.Println(s.title)
+		fmt.Printf("    %s\n", strings.Join(s.header, " | "))
+		for i, row := range s.rawRows {
+			line := fmt.Sprintf("%3d %s", i+1, strings.Join(row, " | "))
+			if s.disabledRows[i] {
+				line += " (unavailable)"
+			}
+
+			fmt.Println(line)
+		}
+
+		count := len(s.rawRows)
+		s.tableMu.Unlock()
+
+		answer, err := handler.askQuestion(plainTableHelp+"\nSelection: ", "")
+		if err != nil {
+			return err
+		}
+
+		if s.err != nil {
+			return s.err
+		}
+
+		if strings.TrimSpace(answer) == "" {
+			continue
+		}
+
+		selection, err := parseSelection(answer, count)
+		if err != nil {
+			InvalidInputError(err)
+			continue
+		}
+
+		s.tableMu.Lock()
+		s.activeRows = make(map[int]bool, len(selection))
+		for _, i := range selection {
+			// Rows may have been removed since the list was printed.
+			if i < len(s.rawRows) && !s.disabledRows[i] {
+				s.activeRows[i] = true
+			}
+		}
+
+		s.active = false
+		s.tableMu.Unlock()
+
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type plainTableSuite struct {
+	suite.Suite
+}
+
+func TestPlainTableSuite(t *testing.T) {
+	suite.Run(t, new(plainTableSuite))
+}
+
+func (s *plainTableSuite) Test_parseSelection() {
+	cases := []struct {
+		desc      string
+		input     string
+		count     int
+		expected  []int
+		expectErr bool
+	}{
+		{
+			desc:     "Select all rows",
+			input:    "all",
+			count:    3,
+			expected: []int{0, 1, 2},
+		},
+		{
+			desc:     "Select no rows",
+			input:    " None ",
+			count:    3,
+			expected: []int{},
+		},
+		{
+			desc:     "Select single rows and ranges",
+			input:    "4-5, 1,3-4",
+			count:    5,
+			expected: []int{0, 2, 3, 4},
+		},
+		{
+			desc:      "Row out of range",
+			input:     "1,6",
+			count:     5,
+			expectErr: true,
+		},
+		{
+			desc:      "Reversed range",
+			input:     "3-1",
+			count:     5,
+			expectErr: true,
+		},
+		{
+			desc:      "Invalid row number",
+			input:     "1,a",
+			count:     5,
+			expectErr: true,
+		},
+		{
+			desc:      "Only separators",
+			input:     ",,",
+			count:     5,
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		selection, err := parseSelection(c.input, c.count)
+		if c.expectErr {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+			s.Equal(c.expected, selection)
+		}
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +15,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/fvbommel/sortorder"
 	"github.com/muesli/reflow/wrap"
 )
 
@@ -82,8 +86,78 @@ type selectableTable struct {
 	// testMode is set if the associated input handler is in test mode.
 	testMode bool
 
+	// plain is set if the table is rendered as a simple numbered prompt instead of an interactive table.
+	plain bool
+
 	// windowWidth contains the current width of the terminal window.
 	windowWidth int
+
+	// sortCol is the absolute index of the column used to sort the displayed rows, or -1 to keep the original order.
+	sortCol int
+
+	// sortDesc reverses the sort order of the displayed rows.
+	sortDesc bool
+
+	// hiddenCols is the set of absolute column indexes that are not displayed.
+	hiddenCols map[int]bool
+
+	// visibleCols is a mapping of displayed column indexes to absolute column indexes in the rawRows list.
+	visibleCols []int
+
+	// prefs persists the sort order and hidden columns of the table between runs, if set.
+	prefs TablePreferenceStore
+
+	// loadedPrefs are the table preferences at the time the table was rendered.
+	loadedPrefs TablePreferences
+}
+
+// sizeRegex matches human readable byte sizes such as "1.50GiB".
+var sizeRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([kKMGTPE]i?B|B)?$`)
+
+// sizeMultipliers are the multipliers for each unit supported by sizeRegex.
+var sizeMultipliers = map[string]float64{
+	"":    1,
+	"B":   1,
+	"kB":  1e3,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"EB":  1e18,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+	"PiB": 1 << 50,
+	"EiB": 1 << 60,
+}
+
+// parseSize returns the value of a number or human readable byte size, and whether the string could be parsed.
+func parseSize(str string) (float64, bool) {
+	match := sizeRegex.FindStringSubmatch(strings.TrimSpace(str))
+	if match == nil {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return value * sizeMultipliers[match[2]], true
+}
+
+// lessCell reports whether cell a should be sorted before cell b.
+// Numbers and byte sizes are compared by value, and any other text is compared naturally.
+func lessCell(a string, b string) bool {
+	sizeA, okA := parseSize(a)
+	sizeB, okB := parseSize(b)
+	if okA && okB && sizeA != sizeB {
+		return sizeA < sizeB
+	}
+
+	return sortorder.NaturalLess(a, b)
 }
 
 // SummarizeResult formats the result string and args with the standard style for table result summaries.
@@ -102,6 +176,7 @@ func NewSelectableTable(header []string, rows [][]string) *selectableTable {
 		header:  header,
 		rawRows: rows,
 		size:    defaultTableSize,
+		sortCol: -1,
 	}
 
 	return s
@@ -118,6 +193,12 @@ func (s *selectableTable) Render(ctx context.Context, handler *InputHandler, tit
 	s.active = true
 	s.title = title
 	s.testMode = handler.testMode
+	s.plain = handler.plain
+
+	// Don't use persisted preferences in test mode, so that the table layout is always predictable.
+	if !s.testMode {
+		s.prefs = handler.tablePrefs
+	}
 
 	if newRows != nil {
 		s.rawRows = newRows
@@ -133,14 +214,25 @@ func (s *selectableTable) Render(ctx context.Context, handler *InputHandler, tit
 		handler.setActive(false)
 	}()
 
-	s.program = tea.NewProgram(s, tea.WithContext(ctx), tea.WithInput(handler.input), tea.WithOutput(handler.output))
-	result, err := s.program.Run()
-	if err != nil {
-		return nil, fmt.Errorf("Failed to render table: %w", err)
-	}
+	var result tea.Model
+	var err error
+	if s.plain {
+		err = s.renderPlain(ctx, handler)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to render table: %w", err)
+		}
 
-	// unset the program, as the table has finished running.
-	s.program = nil
+		result = s
+	} else {
+		s.program = tea.NewProgram(s, tea.WithContext(ctx), tea.WithInput(handler.input), tea.WithOutput(handler.output))
+		result, err = s.program.Run()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to render table: %w", err)
+		}
+
+		// unset the program, as the table has finished running.
+		s.program = nil
+	}
 
 	if s.err != nil {
 		return nil, s.err
@@ -151,6 +243,11 @@ func (s *selectableTable) Render(ctx context.Context, handler *InputHandler, tit
 		return nil, errors.New("Unexpected result type")
 	}
 
+	err = table.storePreferences()
+	if err != nil {
+		PrintWarning(fmt.Sprintf("Failed to store table preferences: %v", err))
+	}
+
 	resultMap := make([]map[string]string, 0, len(table.rawRows))
 	for i := range table.activeRows {
 		if table.disabledRows[i] {
@@ -180,6 +277,12 @@ func (s *selectableTable) SendUpdate(msg tea.Msg) {
 		return
 	}
 
+	// Plain tables have no program to notify, so apply the update directly.
+	if s.plain {
+		_, _ = s.Update(msg)
+		return
+	}
+
 	// Sleep until the program is set.
 	for s.program == nil {
 		time.Sleep(300 * time.Millisecond)
@@ -220,24 +323,96 @@ func (s *selectableTable) Init() tea.Cmd {
 	s.formatRows = make([][]string, 0, len(s.rawRows))
 	s.filterMap = make(map[int]int, len(s.rawRows))
 
+	s.hiddenCols = make(map[int]bool)
+
 	s.currentRow = 0
 	s.startIndex = 0
 	s.filter = ""
 
-	for i, row := range s.rawRows {
-		s.filterMap[i] = i
-		col := make([]string, 0, len(row))
-		col = append(col, row...)
+	s.loadPreferences()
+	s.resetColumns()
+	s.filterRows(true)
+	return nil
+}
 
-		s.formatRows = append(s.formatRows, col)
+// tableID returns the key under which the preferences of this table are stored.
+func (s *selectableTable) tableID() string {
+	return strings.ToLower(strings.Join(s.header, ","))
+}
+
+// loadPreferences applies the persisted sort order and hidden columns to the table.
+func (s *selectableTable) loadPreferences() {
+	if s.prefs == nil {
+		return
 	}
 
-	header := make([]string, len(s.header))
-	copy(header, s.header)
+	s.loadedPrefs = s.prefs.TablePreferences(s.tableID())
+	for i, header := range s.header {
+		if strings.EqualFold(header, s.loadedPrefs.SortColumn) {
+			s.sortCol = i
+			s.sortDesc = s.loadedPrefs.SortDescending
+		}
+
+		for _, hidden := range s.loadedPrefs.HiddenColumns {
+			if strings.EqualFold(header, hidden) {
+				s.hiddenCols[i] = true
+			}
+		}
+	}
+
+	// Always keep at least one column visible.
+	if len(s.hiddenCols) == len(s.header) {
+		s.hiddenCols = make(map[int]bool)
+	}
+}
+
+// storePreferences persists the sort order and hidden columns of the table, if they have changed.
+func (s *selectableTable) storePreferences() error {
+	if s.prefs == nil {
+		return nil
+	}
+
+	prefs := TablePreferences{}
+	if s.sortCol >= 0 {
+		prefs.SortColumn = s.header[s.sortCol]
+		prefs.SortDescending = s.sortDesc
+	}
+
+	for i, header := range s.header {
+		if s.hiddenCols[i] {
+			prefs.HiddenColumns = append(prefs.HiddenColumns, header)
+		}
+	}
+
+	if prefs.SortColumn == s.loadedPrefs.SortColumn && prefs.SortDescending == s.loadedPrefs.SortDescending && slices.Equal(prefs.HiddenColumns, s.loadedPrefs.HiddenColumns) {
+		return nil
+	}
+
+	return s.prefs.SetTablePreferences(s.tableID(), prefs)
+}
+
+// resetColumns recomputes the displayed columns and rebuilds the table with the corresponding header.
+func (s *selectableTable) resetColumns() {
+	s.visibleCols = make([]int, 0, len(s.header))
+	header := make([]string, 0, len(s.header))
+	for i, col := range s.header {
+		if s.hiddenCols[i] {
+			continue
+		}
+
+		if i == s.sortCol {
+			if s.sortDesc {
+				col += " ▼"
+			} else {
+				col += " ▲"
+			}
+		}
+
+		s.visibleCols = append(s.visibleCols, i)
+		header = append(header, col)
+	}
 
 	s.table = baseTableTemplate(header, false)
-	s.updateTableRows()
-	return nil
 }
 
 // View draws the table and its menus and returns it as a string.
@@ -301,10 +476,32 @@ func (s *selectableTable) View() string {
 	helpUp := Fmt{Color: Bright, Arg: "↑", Bold: true}
 	helpDown := Fmt{Color: Bright, Arg: "↓", Bold: true}
 
-	helpTmpl := Fmt{Arg: " %s to select; %s to confirm\n %s/%s to move; %s to select all; %s to select none"}
-	help := Printf(helpTmpl, helpSpace, helpEnter, helpUp, helpDown, helpRight, helpLeft)
+	helpPgUp := Fmt{Color: Bright, Arg: "pgup", Bold: true}
+	helpPgDown := Fmt{Color: Bright, Arg: "pgdown", Bold: true}
+
+	helpTab := Fmt{Color: Bright, Arg: "tab", Bold: true}
+	helpShiftTab := Fmt{Color: Bright, Arg: "shift+tab", Bold: true}
+	helpCtrlX := Fmt{Color: Bright, Arg: "ctrl+x", Bold: true}
 
-	return title + filter + strings.Join(parts, "\n") + "\n" + help
+	helpTmpl := Fmt{Arg: " %s to select; %s to confirm\n %s/%s to move; %s/%s to change page\n %s to select all; %s to select none\n %s to sort; %s to reverse; %s to hide the sorted column\n Filter a single column with <column>:<text>"}
+	help := Printf(helpTmpl, helpSpace, helpEnter, helpUp, helpDown, helpPgUp, helpPgDown, helpRight, helpLeft, helpTab, helpShiftTab, helpCtrlX)
+
+	return title + filter + strings.Join(parts, "\n") + "\n" + s.pageInfo() + help
+}
+
+// pageInfo returns the current page of the table and the number of matching rows, if the rows don't fit on a single page.
+func (s *selectableTable) pageInfo() string {
+	if len(s.formatRows) <= s.size {
+		return ""
+	}
+
+	pages := (len(s.formatRows) + s.size - 1) / s.size
+	page := min(s.startIndex/s.size+1, pages)
+	if s.startIndex+s.size >= len(s.formatRows) {
+		page = pages
+	}
+
+	return Printf(Fmt{Arg: " Page %s of %s (%s rows)\n", Color: White}, Fmt{Arg: page, Bold: true}, Fmt{Arg: pages, Bold: true}, Fmt{Arg: len(s.formatRows), Bold: true})
 }
 
 func (s *selectableTable) filterRows(updatePos bool) {
@@ -315,12 +512,30 @@ func (s *selectableTable) filterRows(updatePos bool) {
 		s.startIndex = 0
 	}
 
-	index := 0
+	// A filter of the form "<column>:<text>" only matches against the given column.
+	filterCol := -1
+	filterText := s.filter
+	column, text, ok := strings.Cut(s.filter, ":")
+	if ok {
+		for i, header := range s.header {
+			if strings.EqualFold(header, column) {
+				filterCol = i
+				filterText = text
+				break
+			}
+		}
+	}
+
+	matches := make([]int, 0, len(s.rawRows))
 	for i, row := range s.rawRows {
-		match := len(s.filter) == 0
+		match := len(filterText) == 0
 		if !match {
-			for _, col := range row {
-				if strings.Contains(col, s.filter) {
+			for j, col := range row {
+				if filterCol >= 0 && j != filterCol {
+					continue
+				}
+
+				if strings.Contains(col, filterText) {
 					match = true
 					break
 				}
@@ -328,13 +543,30 @@ func (s *selectableTable) filterRows(updatePos bool) {
 		}
 
 		if match {
-			col := make([]string, 0, len(row))
-			col = append(col, row...)
-			s.formatRows = append(s.formatRows, col)
+			matches = append(matches, i)
+		}
+	}
+
+	if s.sortCol >= 0 {
+		sort.SliceStable(matches, func(i, j int) bool {
+			a := s.rawRows[matches[i]][s.sortCol]
+			b := s.rawRows[matches[j]][s.sortCol]
+			if s.sortDesc {
+				return lessCell(b, a)
+			}
+
+			return lessCell(a, b)
+		})
+	}
 
-			s.filterMap[index] = i
-			index++
+	for index, i := range matches {
+		col := make([]string, 0, len(s.visibleCols))
+		for _, j := range s.visibleCols {
+			col = append(col, s.rawRows[i][j])
 		}
+
+		s.formatRows = append(s.formatRows, col)
+		s.filterMap[index] = i
 	}
 
 	s.updateTableRows()
@@ -437,8 +669,63 @@ func (s *selectableTable) handleKeyEvent(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 				s.startIndex++
 			}
 		}
+	case tea.KeyPgUp:
+		s.currentRow = max(s.currentRow-s.size, 0)
+		s.startIndex = max(s.startIndex-s.size, 0)
+		if s.currentRow < s.startIndex {
+			s.startIndex = s.currentRow
+		}
+
+	case tea.KeyPgDown:
+		lastRow := max(len(s.formatRows)-1, 0)
+		s.currentRow = min(s.currentRow+s.size, lastRow)
+		s.startIndex = min(s.startIndex+s.size, max(len(s.formatRows)-s.size, 0))
+		if s.currentRow > s.startIndex+(s.size-1) {
+			s.startIndex = s.currentRow - (s.size - 1)
+		}
+
+	case tea.KeyHome:
+		s.currentRow = 0
+		s.startIndex = 0
+	case tea.KeyEnd:
+		s.currentRow = max(len(s.formatRows)-1, 0)
+		s.startIndex = max(len(s.formatRows)-s.size, 0)
+	case tea.KeyTab:
+		// Cycle through the columns to sort by, ending with the original order.
+		s.sortCol++
+		if s.sortCol >= len(s.header) {
+			s.sortCol = -1
+		}
+
+		s.sortDesc = false
+		s.resetColumns()
+		s.filterRows(true)
+	case tea.KeyShiftTab:
+		if s.sortCol >= 0 {
+			s.sortDesc = !s.sortDesc
+			s.resetColumns()
+			s.filterRows(true)
+		}
+
+	case tea.KeyCtrlX:
+		// Toggle the visibility of the sorted column, but always keep at least one column visible.
+		if s.sortCol >= 0 && (s.hiddenCols[s.sortCol] || len(s.hiddenCols) < len(s.header)-1) {
+			if s.hiddenCols[s.sortCol] {
+				delete(s.hiddenCols, s.sortCol)
+			} else {
+				s.hiddenCols[s.sortCol] = true
+			}
+
+			s.resetColumns()
+			s.filterRows(false)
+		}
+
 	case tea.KeyLeft:
-		s.activeRows = map[int]bool{}
+		// Only de-select the rows matching the current filter.
+		for i := range s.formatRows {
+			delete(s.activeRows, s.filterMap[i])
+		}
+
 	case tea.KeyRight:
 		for i := range s.formatRows {
 			if !s.disabledRows[s.filterMap[i]] {
@@ -490,7 +777,7 @@ func (s *selectableTable) handleKeyEvent(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (s *selectableTable) rowStyle(row int) {
 	for col := range s.formatRows[row] {
 		rawRowIndex := s.filterMap[row]
-		textStyle := lipgloss.NewStyle().SetString(s.rawRows[rawRowIndex][col])
+		textStyle := lipgloss.NewStyle().SetString(s.rawRows[rawRowIndex][s.visibleCols[col]])
 		if row == s.currentRow {
 			if s.activeRows[s.filterMap[row]] {
 				textStyle = textStyle.Bold(true).Foreground(Green)
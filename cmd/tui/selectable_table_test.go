@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type selectableTableSuite struct {
+	suite.Suite
+}
+
+func TestSelectableTableSuite(t *testing.T) {
+	suite.Run(t, new(selectableTableSuite))
+}
+
+// testPreferenceStore keeps table preferences in memory.
+type testPreferenceStore map[string]TablePreferences
+
+func (t testPreferenceStore) TablePreferences(id string) TablePreferences {
+	return t[id]
+}
+
+func (t testPreferenceStore) SetTablePreferences(id string, prefs TablePreferences) error {
+	t[id] = prefs
+
+	return nil
+}
+
+func (s *selectableTableSuite) Test_parseSize() {
+	cases := []struct {
+		input    string
+		expected float64
+		ok       bool
+	}{
+		{input: "12", expected: 12, ok: true},
+		{input: "1.5", expected: 1.5, ok: true},
+		{input: "512B", expected: 512, ok: true},
+		{input: "2kB", expected: 2e3, ok: true},
+		{input: " 1.50GiB ", expected: 1.5 * (1 << 30), ok: true},
+		{input: "3 TB", expected: 3e12, ok: true},
+		{input: "1EiB", expected: 1 << 60, ok: true},
+		{input: "micro01", ok: false},
+		{input: "12XB", ok: false},
+		{input: "-1", ok: false},
+		{input: "", ok: false},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %q", i, c.input)
+
+		value, ok := parseSize(c.input)
+		s.Equal(c.ok, ok)
+		if c.ok {
+			s.Equal(c.expected, value)
+		}
+	}
+}
+
+func (s *selectableTableSuite) Test_sortRows() {
+	header := []string{"NAME", "SIZE", "DISKS"}
+	rows := [][]string{
+		{"micro10", "1TiB", "12"},
+		{"micro2", "500GiB", "2"},
+		{"micro1", "1.5TB", "100"},
+		{"micro03", "64MiB", "9"},
+	}
+
+	cases := []struct {
+		desc       string
+		sortColumn string
+		descending bool
+		expected   []string
+	}{
+		{
+			desc:     "Original order",
+			expected: []string{"micro10", "micro2", "micro1", "micro03"},
+		},
+		{
+			desc:       "Text column is sorted naturally",
+			sortColumn: "NAME",
+			expected:   []string{"micro1", "micro2", "micro03", "micro10"},
+		},
+		{
+			desc:       "Text column is sorted naturally in descending order",
+			sortColumn: "NAME",
+			descending: true,
+			expected:   []string{"micro10", "micro03", "micro2", "micro1"},
+		},
+		{
+			desc:       "Size column is sorted by byte size",
+			sortColumn: "SIZE",
+			expected:   []string{"micro03", "micro2", "micro10", "micro1"},
+		},
+		{
+			desc:       "Number column is sorted by value",
+			sortColumn: "disks",
+			expected:   []string{"micro2", "micro03", "micro10", "micro1"},
+		},
+		{
+			desc:       "Number column is sorted by value in descending order",
+			sortColumn: "DISKS",
+			descending: true,
+			expected:   []string{"micro1", "micro10", "micro03", "micro2"},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		table := NewSelectableTable(header, rows)
+		table.prefs = testPreferenceStore{table.tableID(): {SortColumn: c.sortColumn, SortDescending: c.descending}}
+		table.Init()
+
+		names := make([]string, 0, len(table.formatRows))
+		for _, row := range table.formatRows {
+			names = append(names, row[0])
+		}
+
+		s.Equal(c.expected, names)
+	}
+}
+
+func (s *selectableTableSuite) Test_storePreferences() {
+	header := []string{"NAME", "SIZE", "DISKS"}
+	rows := [][]string{{"micro1", "1TiB", "2"}, {"micro2", "2TiB", "1"}}
+	store := testPreferenceStore{}
+
+	table := NewSelectableTable(header, rows)
+	table.prefs = store
+	table.Init()
+
+	// Unchanged preferences aren't stored.
+	s.NoError(table.storePreferences())
+	s.Empty(store)
+
+	table.sortCol = 2
+	table.sortDesc = true
+	table.hiddenCols[1] = true
+	s.NoError(table.storePreferences())
+	s.Equal(TablePreferences{SortColumn: "DISKS", SortDescending: true, HiddenColumns: []string{"SIZE"}}, store[table.tableID()])
+
+	// A new table with the same columns picks up the stored preferences.
+	table = NewSelectableTable(header, rows)
+	table.prefs = store
+	table.Init()
+	s.Equal(2, table.sortCol)
+	s.True(table.sortDesc)
+	s.Equal([]int{0, 2}, table.visibleCols)
+	s.Equal([][]string{{"micro1", "2"}, {"micro2", "1"}}, table.formatRows)
+}
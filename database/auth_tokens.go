@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// AuthToken is a time-limited API token, stored by the hash of its secret.
+type AuthToken struct {
+	Name      string
+	Role      string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// authTokensTable creates the table holding the API tokens used by automation.
+func authTokensTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE auth_tokens (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name        TEXT NOT NULL,
+    role        TEXT NOT NULL,
+    token_hash  TEXT NOT NULL,
+    created_at  DATETIME NOT NULL,
+    expires_at  DATETIME NOT NULL,
+    UNIQUE (name),
+    UNIQUE (token_hash)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetAuthTokens returns the API tokens, ordered by name.
+func GetAuthTokens(ctx context.Context, tx *sql.Tx) ([]AuthToken, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT name, role, token_hash, created_at, expires_at FROM auth_tokens ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query API tokens: %w", err)
+	}
+
+	defer rows.Close()
+
+	tokens := []AuthToken{}
+	for rows.Next() {
+		var token AuthToken
+		err := rows.Scan(&token.Name, &token.Role, &token.TokenHash, &token.CreatedAt, &token.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan API token: %w", err)
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query API tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// GetAuthTokenByHash returns the API token with the given secret hash.
+func GetAuthTokenByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (*AuthToken, error) {
+	var token AuthToken
+	err := tx.QueryRowContext(ctx, "SELECT name, role, token_hash, created_at, expires_at FROM auth_tokens WHERE token_hash = ?", tokenHash).Scan(&token.Name, &token.Role, &token.TokenHash, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "API token not found")
+		}
+
+		return nil, fmt.Errorf("Failed to get API token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// CreateAuthToken stores a new API token.
+func CreateAuthToken(ctx context.Context, tx *sql.Tx, token AuthToken) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO auth_tokens (name, role, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?, ?)", token.Name, token.Role, token.TokenHash, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("Failed to create API token %q: %w", token.Name, err)
+	}
+
+	return nil
+}
+
+// DeleteAuthToken revokes the API token with the given name.
+func DeleteAuthToken(ctx context.Context, tx *sql.Tx, name string) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM auth_tokens WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("Failed to delete API token %q: %w", name, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to delete API token %q: %w", name, err)
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "API token %q not found", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Benchmark is the recorded baseline of a storage benchmark of a storage pool on a cluster member.
+type Benchmark struct {
+	Member    string
+	Pool      string
+	Test      string
+	IOPS      float64
+	Bandwidth int64
+	Latency   int64
+	CreatedAt time.Time
+}
+
+// benchmarksTable creates the table holding the storage performance baseline of the cluster members.
+func benchmarksTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE benchmarks (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    member      TEXT NOT NULL,
+    pool        TEXT NOT NULL,
+    test        TEXT NOT NULL,
+    iops        REAL NOT NULL,
+    bandwidth   INTEGER NOT NULL,
+    latency     INTEGER NOT NULL,
+    created_at  DATETIME NOT NULL,
+    UNIQUE (member, pool, test)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetBenchmarks returns the storage performance baseline of all cluster members.
+func GetBenchmarks(ctx context.Context, tx *sql.Tx) ([]Benchmark, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT member, pool, test, iops, bandwidth, latency, created_at FROM benchmarks ORDER BY member, pool, test")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query benchmarks: %w", err)
+	}
+
+	defer rows.Close()
+
+	benchmarks := []Benchmark{}
+	for rows.Next() {
+		var b Benchmark
+		err := rows.Scan(&b.Member, &b.Pool, &b.Test, &b.IOPS, &b.Bandwidth, &b.Latency, &b.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan benchmark: %w", err)
+		}
+
+		benchmarks = append(benchmarks, b)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query benchmarks: %w", err)
+	}
+
+	return benchmarks, nil
+}
+
+// ReplaceBenchmarks replaces the baseline of the given storage pool on the given cluster member.
+func ReplaceBenchmarks(ctx context.Context, tx *sql.Tx, member string, pool string, benchmarks []Benchmark) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM benchmarks WHERE member = ? AND pool = ?", member, pool)
+	if err != nil {
+		return fmt.Errorf("Failed to delete benchmarks of pool %q on %q: %w", pool, member, err)
+	}
+
+	for _, b := range benchmarks {
+		_, err := tx.ExecContext(ctx, "INSERT INTO benchmarks (member, pool, test, iops, bandwidth, latency, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)", member, pool, b.Test, b.IOPS, b.Bandwidth, b.Latency, b.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("Failed to record benchmark %q of pool %q on %q: %w", b.Test, pool, member, err)
+		}
+	}
+
+	return nil
+}
@@ -12,6 +12,15 @@ import (
 // Each entry will increase the database schema version by one, and will be applied after internal schema updates.
 var SchemaExtensions = []db.Update{
 	clusterManagerTables,
+	configTable,
+	benchmarksTable,
+	operationsTable,
+	eventsTable,
+	joinStatesTable,
+	tuningTable,
+	warningAcknowledgementsTable,
+	reportsTable,
+	authTokensTable,
 }
 
 func clusterManagerTables(ctx context.Context, tx *sql.Tx) error {
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// configTable creates the table holding the cluster-wide MicroCloud daemon configuration.
+func configTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE config (
+    id     INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    key    TEXT NOT NULL,
+    value  TEXT NOT NULL,
+    UNIQUE (key)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetConfig returns the cluster-wide MicroCloud daemon configuration.
+func GetConfig(ctx context.Context, tx *sql.Tx) (map[string]string, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT key, value FROM config ORDER BY key")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query config: %w", err)
+	}
+
+	defer rows.Close()
+
+	config := map[string]string{}
+	for rows.Next() {
+		var key, value string
+		err := rows.Scan(&key, &value)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan config: %w", err)
+		}
+
+		config[key] = value
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query config: %w", err)
+	}
+
+	return config, nil
+}
+
+// UpdateConfig sets the given keys of the cluster-wide MicroCloud daemon configuration.
+// Keys with an empty value are removed.
+func UpdateConfig(ctx context.Context, tx *sql.Tx, config map[string]string) error {
+	for key, value := range config {
+		if value == "" {
+			_, err := tx.ExecContext(ctx, "DELETE FROM config WHERE key = ?", key)
+			if err != nil {
+				return fmt.Errorf("Failed to unset config key %q: %w", key, err)
+			}
+
+			continue
+		}
+
+		_, err := tx.ExecContext(ctx, "INSERT INTO config (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value", key, value)
+		if err != nil {
+			return fmt.Errorf("Failed to set config key %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Event is a significant event in the history of the MicroCloud cluster.
+type Event struct {
+	ID        int64
+	Type      string
+	Member    string
+	Message   string
+	CreatedAt time.Time
+}
+
+// EventFilter restricts the events returned by GetEvents. Zero values match all events.
+// Limit and Offset select a page of the matching events.
+type EventFilter struct {
+	Type    string
+	Members []string
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+	Offset  int
+}
+
+// eventsTable creates the table holding the event history of the cluster.
+func eventsTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE events (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    type        TEXT NOT NULL,
+    member      TEXT NOT NULL,
+    message     TEXT NOT NULL,
+    created_at  DATETIME NOT NULL
+);
+CREATE INDEX events_created_at ON events (created_at);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetEvents returns the events matching the filter, oldest first.
+func GetEvents(ctx context.Context, tx *sql.Tx, filter EventFilter) ([]Event, error) {
+	var where []string
+	var args []any
+	if filter.Type != "" {
+		where = append(where, "type = ?")
+		args = append(args, filter.Type)
+	}
+
+	if len(filter.Members) > 0 {
+		where = append(where, "member IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(filter.Members)), ", ")+")")
+		for _, member := range filter.Members {
+			args = append(args, member)
+		}
+	}
+
+	if !filter.Since.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+
+	if !filter.Until.IsZero() {
+		where = append(where, "created_at <= ?")
+		args = append(args, filter.Until)
+	}
+
+	stmt := "SELECT id, type, member, message, created_at FROM events"
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	stmt += " ORDER BY created_at, id"
+	if filter.Limit > 0 || filter.Offset > 0 {
+		// SQLite requires a limit with an offset, where a negative limit means no limit.
+		limit := filter.Limit
+		if limit == 0 {
+			limit = -1
+		}
+
+		stmt += " LIMIT ? OFFSET ?"
+		args = append(args, limit, filter.Offset)
+	}
+
+	rows, err := tx.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query events: %w", err)
+	}
+
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var event Event
+		err := rows.Scan(&event.ID, &event.Type, &event.Member, &event.Message, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan event: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query events: %w", err)
+	}
+
+	return events, nil
+}
+
+// CreateEvent records an event in the history of the cluster.
+func CreateEvent(ctx context.Context, tx *sql.Tx, event Event) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO events (type, member, message, created_at) VALUES (?, ?, ?, ?)", event.Type, event.Member, event.Message, event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("Failed to create event: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEventsBefore removes the events older than the given time.
+func DeleteEventsBefore(ctx context.Context, tx *sql.Tx, before time.Time) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM events WHERE created_at < ?", before)
+	if err != nil {
+		return fmt.Errorf("Failed to delete expired events: %w", err)
+	}
+
+	return nil
+}
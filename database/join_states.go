@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// JoinState is the state of a cluster member joining the cluster of one of the services.
+type JoinState struct {
+	Member    string
+	Service   string
+	Status    string
+	Error     string
+	Config    string
+	UpdatedAt time.Time
+}
+
+// joinStatesTable creates the table tracking the join of each cluster member to each service.
+func joinStatesTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE join_states (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    member      TEXT NOT NULL,
+    service     TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    error       TEXT NOT NULL,
+    config      TEXT NOT NULL,
+    updated_at  DATETIME NOT NULL,
+    UNIQUE (member, service)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetJoinStates returns the join states of the given cluster member, or of all cluster members if no member is given.
+func GetJoinStates(ctx context.Context, tx *sql.Tx, member string) ([]JoinState, error) {
+	stmt := "SELECT member, service, status, error, config, updated_at FROM join_states"
+	args := []any{}
+	if member != "" {
+		stmt += " WHERE member = ?"
+		args = append(args, member)
+	}
+
+	rows, err := tx.QueryContext(ctx, stmt+" ORDER BY member, service", args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query join states: %w", err)
+	}
+
+	defer rows.Close()
+
+	states := []JoinState{}
+	for rows.Next() {
+		var state JoinState
+		err := rows.Scan(&state.Member, &state.Service, &state.Status, &state.Error, &state.Config, &state.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan join state: %w", err)
+		}
+
+		states = append(states, state)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query join states: %w", err)
+	}
+
+	return states, nil
+}
+
+// UpdateJoinState sets the join state of a cluster member for a service.
+func UpdateJoinState(ctx context.Context, tx *sql.Tx, state JoinState) error {
+	stmt := `
+INSERT INTO join_states (member, service, status, error, config, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (member, service) DO UPDATE SET status = excluded.status, error = excluded.error, config = excluded.config, updated_at = excluded.updated_at
+`
+
+	_, err := tx.ExecContext(ctx, stmt, state.Member, state.Service, state.Status, state.Error, state.Config, state.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("Failed to update join state of %q for %s: %w", state.Member, state.Service, err)
+	}
+
+	return nil
+}
+
+// DeleteJoinStates removes the join states of the given cluster member.
+func DeleteJoinStates(ctx context.Context, tx *sql.Tx, member string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM join_states WHERE member = ?", member)
+	if err != nil {
+		return fmt.Errorf("Failed to delete join states of %q: %w", member, err)
+	}
+
+	return nil
+}
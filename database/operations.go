@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Operation is a cluster change holding or waiting for the cluster-wide change lock.
+type Operation struct {
+	ID          int64
+	Type        string
+	Member      string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// operationsTable creates the table queueing the cluster changes for the cluster-wide change lock.
+func operationsTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE operations (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    type         TEXT NOT NULL,
+    member       TEXT NOT NULL,
+    description  TEXT NOT NULL,
+    created_at   DATETIME NOT NULL,
+    updated_at   DATETIME NOT NULL
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetOperations returns the queued cluster changes, in the order they were queued.
+func GetOperations(ctx context.Context, tx *sql.Tx) ([]Operation, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, type, member, description, created_at, updated_at FROM operations ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query operations: %w", err)
+	}
+
+	defer rows.Close()
+
+	operations := []Operation{}
+	for rows.Next() {
+		var op Operation
+		err := rows.Scan(&op.ID, &op.Type, &op.Member, &op.Description, &op.CreatedAt, &op.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan operation: %w", err)
+		}
+
+		operations = append(operations, op)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query operations: %w", err)
+	}
+
+	return operations, nil
+}
+
+// CreateOperation queues a cluster change, and returns its ID.
+func CreateOperation(ctx context.Context, tx *sql.Tx, op Operation) (int64, error) {
+	result, err := tx.ExecContext(ctx, "INSERT INTO operations (type, member, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?)", op.Type, op.Member, op.Description, op.CreatedAt, op.UpdatedAt)
+	if err != nil {
+		return -1, fmt.Errorf("Failed to create operation: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("Failed to get operation ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// TouchOperation records that the client of the given cluster change is still alive.
+func TouchOperation(ctx context.Context, tx *sql.Tx, id int64, updatedAt time.Time) error {
+	result, err := tx.ExecContext(ctx, "UPDATE operations SET updated_at = ? WHERE id = ?", updatedAt, id)
+	if err != nil {
+		return fmt.Errorf("Failed to update operation %d: %w", id, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("Failed to update operation %d: %w", id, err)
+	}
+
+	if n == 0 {
+		return api.StatusErrorf(http.StatusNotFound, "Operation %d not found", id)
+	}
+
+	return nil
+}
+
+// DeleteOperation removes the given cluster change from the queue, releasing the change lock if it held it.
+func DeleteOperation(ctx context.Context, tx *sql.Tx, id int64) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM operations WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete operation %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteStaleOperations removes the cluster changes whose client didn't check in since the given time.
+func DeleteStaleOperations(ctx context.Context, tx *sql.Tx, before time.Time) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM operations WHERE updated_at < ?", before)
+	if err != nil {
+		return fmt.Errorf("Failed to delete stale operations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Report is a health report of the cluster, stored as JSON.
+type Report struct {
+	ID        int64
+	Member    string
+	Report    string
+	CreatedAt time.Time
+}
+
+// reportsTable creates the table holding the generated health reports of the cluster.
+func reportsTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE reports (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    member      TEXT NOT NULL,
+    report      TEXT NOT NULL,
+    created_at  DATETIME NOT NULL
+);
+CREATE INDEX reports_created_at ON reports (created_at);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetReports returns the health reports, oldest first.
+func GetReports(ctx context.Context, tx *sql.Tx) ([]Report, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT id, member, report, created_at FROM reports ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query reports: %w", err)
+	}
+
+	defer rows.Close()
+
+	reports := []Report{}
+	for rows.Next() {
+		var report Report
+		err := rows.Scan(&report.ID, &report.Member, &report.Report, &report.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan report: %w", err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetReport returns the health report with the given ID.
+func GetReport(ctx context.Context, tx *sql.Tx, id int64) (*Report, error) {
+	var report Report
+	err := tx.QueryRowContext(ctx, "SELECT id, member, report, created_at FROM reports WHERE id = ?", id).Scan(&report.ID, &report.Member, &report.Report, &report.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Report %d not found", id)
+		}
+
+		return nil, fmt.Errorf("Failed to get report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// GetLatestReportTime returns the time the last health report was generated, or the zero time if there is none.
+func GetLatestReportTime(ctx context.Context, tx *sql.Tx) (time.Time, error) {
+	var latest time.Time
+	err := tx.QueryRowContext(ctx, "SELECT created_at FROM reports ORDER BY created_at DESC LIMIT 1").Scan(&latest)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("Failed to get latest report time: %w", err)
+	}
+
+	return latest, nil
+}
+
+// CreateReport stores a health report, and returns its ID.
+func CreateReport(ctx context.Context, tx *sql.Tx, report Report) (int64, error) {
+	result, err := tx.ExecContext(ctx, "INSERT INTO reports (member, report, created_at) VALUES (?, ?, ?)", report.Member, report.Report, report.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to create report: %w", err)
+	}
+
+	return result.LastInsertId()
+}
+
+// DeleteOldReports removes all but the given number of most recent health reports.
+func DeleteOldReports(ctx context.Context, tx *sql.Tx, keep int) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM reports WHERE id NOT IN (SELECT id FROM reports ORDER BY created_at DESC, id DESC LIMIT ?)", keep)
+	if err != nil {
+		return fmt.Errorf("Failed to delete old reports: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// TuningSetting is a kernel setting changed by MicroCloud on a cluster member, along with its value before the change.
+type TuningSetting struct {
+	Member    string
+	Key       string
+	Previous  int64
+	Value     int64
+	AppliedAt time.Time
+}
+
+// tuningTable creates the table recording the kernel settings changed on each cluster member.
+func tuningTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE tuning (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    member      TEXT NOT NULL,
+    key         TEXT NOT NULL,
+    previous    INTEGER NOT NULL,
+    value       INTEGER NOT NULL,
+    applied_at  DATETIME NOT NULL,
+    UNIQUE (member, key)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetTuning returns the kernel settings changed on the given cluster member, or on all cluster members if no member is given.
+func GetTuning(ctx context.Context, tx *sql.Tx, member string) ([]TuningSetting, error) {
+	stmt := "SELECT member, key, previous, value, applied_at FROM tuning"
+	args := []any{}
+	if member != "" {
+		stmt += " WHERE member = ?"
+		args = append(args, member)
+	}
+
+	rows, err := tx.QueryContext(ctx, stmt+" ORDER BY member, key", args...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tuning settings: %w", err)
+	}
+
+	defer rows.Close()
+
+	settings := []TuningSetting{}
+	for rows.Next() {
+		var setting TuningSetting
+		err := rows.Scan(&setting.Member, &setting.Key, &setting.Previous, &setting.Value, &setting.AppliedAt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan tuning setting: %w", err)
+		}
+
+		settings = append(settings, setting)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query tuning settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// RecordTuning records a kernel setting changed on a cluster member.
+// If the setting was already changed before, its original previous value is kept so a revert restores the value from before any tuning.
+func RecordTuning(ctx context.Context, tx *sql.Tx, setting TuningSetting) error {
+	stmt := `
+INSERT INTO tuning (member, key, previous, value, applied_at) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (member, key) DO UPDATE SET value = excluded.value, applied_at = excluded.applied_at
+`
+
+	_, err := tx.ExecContext(ctx, stmt, setting.Member, setting.Key, setting.Previous, setting.Value, setting.AppliedAt)
+	if err != nil {
+		return fmt.Errorf("Failed to record tuning setting %q of %q: %w", setting.Key, setting.Member, err)
+	}
+
+	return nil
+}
+
+// DeleteTuning removes the recorded kernel settings of the given cluster member.
+func DeleteTuning(ctx context.Context, tx *sql.Tx, member string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM tuning WHERE member = ?", member)
+	if err != nil {
+		return fmt.Errorf("Failed to delete tuning settings of %q: %w", member, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// warningAcknowledgementsTable creates the table holding the acknowledged Ceph and OVN warnings.
+// LXD warnings are acknowledged in LXD itself.
+func warningAcknowledgementsTable(ctx context.Context, tx *sql.Tx) error {
+	stmt := `
+CREATE TABLE warning_acknowledgements (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    warning_id       TEXT NOT NULL,
+    acknowledged_at  DATETIME NOT NULL,
+    UNIQUE (warning_id)
+);
+`
+
+	_, err := tx.ExecContext(ctx, stmt)
+
+	return err
+}
+
+// GetWarningAcknowledgements returns the IDs of the acknowledged warnings.
+func GetWarningAcknowledgements(ctx context.Context, tx *sql.Tx) (map[string]bool, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT warning_id FROM warning_acknowledgements")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query warning acknowledgements: %w", err)
+	}
+
+	defer rows.Close()
+
+	acknowledged := map[string]bool{}
+	for rows.Next() {
+		var id string
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to scan warning acknowledgement: %w", err)
+		}
+
+		acknowledged[id] = true
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to query warning acknowledgements: %w", err)
+	}
+
+	return acknowledged, nil
+}
+
+// AcknowledgeWarning records the warning with the given ID as acknowledged.
+func AcknowledgeWarning(ctx context.Context, tx *sql.Tx, id string, at time.Time) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO warning_acknowledgements (warning_id, acknowledged_at) VALUES (?, ?) ON CONFLICT (warning_id) DO NOTHING", id, at)
+	if err != nil {
+		return fmt.Errorf("Failed to acknowledge warning: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWarningAcknowledgement removes the acknowledgement of the warning with the given ID.
+func DeleteWarningAcknowledgement(ctx context.Context, tx *sql.Tx, id string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM warning_acknowledgements WHERE warning_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("Failed to delete warning acknowledgement: %w", err)
+	}
+
+	return nil
+}
+
+// PruneWarningAcknowledgements removes the acknowledgements of the warnings which are no longer raised, so they are shown again if they come back.
+func PruneWarningAcknowledgements(ctx context.Context, tx *sql.Tx, raised map[string]bool) error {
+	acknowledged, err := GetWarningAcknowledgements(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for id := range acknowledged {
+		if raised[id] {
+			continue
+		}
+
+		err := DeleteWarningAcknowledgement(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+// Package maas provides a minimal client for the MAAS API, used to discover and annotate MicroCloud machines.
+package maas
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIVersion is the version of the MAAS API used by the client.
+const APIVersion = "2.0"
+
+// Client is a client for the MAAS API.
+type Client struct {
+	endpoint *url.URL
+	http     *http.Client
+
+	consumerKey string
+	tokenKey    string
+	tokenSecret string
+}
+
+// NewClient returns a client for the MAAS API at the given URL (such as "http://maas:5240/MAAS"),
+// authenticated with the given API key of the form "<consumer key>:<token key>:<token secret>".
+func NewClient(apiURL string, apiKey string) (*Client, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(apiURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid MAAS URL %q: %w", apiURL, err)
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("Invalid MAAS URL %q: Unsupported scheme %q", apiURL, endpoint.Scheme)
+	}
+
+	keyParts := strings.Split(apiKey, ":")
+	if len(keyParts) != 3 {
+		return nil, errors.New("Invalid MAAS API key (must be of the form <consumer key>:<token key>:<token secret>)")
+	}
+
+	endpoint.Path += "/api/" + APIVersion + "/"
+
+	return &Client{
+		endpoint:    endpoint,
+		http:        &http.Client{Timeout: 30 * time.Second},
+		consumerKey: keyParts[0],
+		tokenKey:    keyParts[1],
+		tokenSecret: keyParts[2],
+	}, nil
+}
+
+// authorization returns the OAuth 1.0 PLAINTEXT authorization header used by MAAS.
+func (c *Client) authorization() (string, error) {
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("Failed to generate nonce: %w", err)
+	}
+
+	params := []string{
+		`oauth_version="1.0"`,
+		`oauth_signature_method="PLAINTEXT"`,
+		fmt.Sprintf(`oauth_consumer_key="%s"`, url.QueryEscape(c.consumerKey)),
+		fmt.Sprintf(`oauth_token="%s"`, url.QueryEscape(c.tokenKey)),
+		fmt.Sprintf(`oauth_signature="&%s"`, url.QueryEscape(c.tokenSecret)),
+		fmt.Sprintf(`oauth_nonce="%s"`, hex.EncodeToString(nonce)),
+		fmt.Sprintf(`oauth_timestamp="%d"`, time.Now().Unix()),
+	}
+
+	return "OAuth " + strings.Join(params, ", "), nil
+}
+
+// query sends a request to the given path of the MAAS API and decodes the JSON response into target, if set.
+func (c *Client) query(ctx context.Context, method string, path string, op string, values url.Values, target any) error {
+	u := c.endpoint.JoinPath(path)
+	// MAAS requires the trailing slash.
+	u.Path += "/"
+	if op != "" {
+		u.RawQuery = url.Values{"op": []string{op}}.Encode()
+	}
+
+	var body io.Reader
+	if method == http.MethodGet {
+		if len(values) > 0 {
+			query := u.Query()
+			for key, v := range values {
+				query[key] = v
+			}
+
+			u.RawQuery = query.Encode()
+		}
+	} else if values != nil {
+		body = strings.NewReader(values.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("Failed to create MAAS request: %w", err)
+	}
+
+	auth, err := c.authorization()
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed to send MAAS request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Failed to read MAAS response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	err = json.Unmarshal(data, target)
+	if err != nil {
+		return fmt.Errorf("Failed to parse MAAS response: %w", err)
+	}
+
+	return nil
+}
+
+// StatusError is returned when the MAAS API responds with an unsuccessful status code.
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("MAAS request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Machines returns the machines known to MAAS. If tag is set, only machines with this tag are returned.
+func (c *Client) Machines(ctx context.Context, tag string) ([]Machine, error) {
+	machines := []Machine{}
+	var err error
+	if tag != "" {
+		err = c.query(ctx, http.MethodGet, "tags/"+url.PathEscape(tag), "machines", nil, &machines)
+	} else {
+		err = c.query(ctx, http.MethodGet, "machines", "", nil, &machines)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MAAS machines: %w", err)
+	}
+
+	return machines, nil
+}
+
+// AddTag adds the tag to the given machines, creating the tag first if it doesn't exist yet.
+func (c *Client) AddTag(ctx context.Context, tag string, systemIDs ...string) error {
+	err := c.query(ctx, http.MethodGet, "tags/"+url.PathEscape(tag), "", nil, nil)
+	if err != nil {
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("Failed to get MAAS tag %q: %w", tag, err)
+		}
+
+		err = c.query(ctx, http.MethodPost, "tags", "", url.Values{"name": []string{tag}, "comment": []string{"Managed by MicroCloud"}}, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to create MAAS tag %q: %w", tag, err)
+		}
+	}
+
+	err = c.query(ctx, http.MethodPost, "tags/"+url.PathEscape(tag), "update_nodes", url.Values{"add": systemIDs}, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to add MAAS tag %q to machines: %w", tag, err)
+	}
+
+	return nil
+}
+
+// SetDescription sets the description of the machine, which MAAS shows as its notes.
+func (c *Client) SetDescription(ctx context.Context, systemID string, description string) error {
+	err := c.query(ctx, http.MethodPut, "machines/"+url.PathEscape(systemID), "", url.Values{"description": []string{description}}, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to update the description of MAAS machine %q: %w", systemID, err)
+	}
+
+	return nil
+}
+
+// Machine is a machine as reported by MAAS.
+type Machine struct {
+	SystemID     string        `json:"system_id"`
+	Hostname     string        `json:"hostname"`
+	FQDN         string        `json:"fqdn"`
+	StatusName   string        `json:"status_name"`
+	IPAddresses  []string      `json:"ip_addresses"`
+	TagNames     []string      `json:"tag_names"`
+	Interfaces   []Interface   `json:"interface_set"`
+	BlockDevices []BlockDevice `json:"blockdevice_set"`
+}
+
+// Interface is a network interface of a MAAS machine.
+type Interface struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	MACAddress string `json:"mac_address"`
+	Links      []Link `json:"links"`
+}
+
+// Link is an address configuration of a MAAS machine's network interface.
+type Link struct {
+	Mode      string  `json:"mode"`
+	IPAddress string  `json:"ip_address"`
+	Subnet    *Subnet `json:"subnet"`
+}
+
+// Subnet is a subnet known to MAAS.
+type Subnet struct {
+	Name string `json:"name"`
+	CIDR string `json:"cidr"`
+}
+
+// BlockDevice is a disk of a MAAS machine.
+type BlockDevice struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	IDPath  string `json:"id_path"`
+	Serial  string `json:"serial"`
+	Model   string `json:"model"`
+	Size    int64  `json:"size"`
+	UsedFor string `json:"used_for"`
+}
+
+// BlockDeviceUnused is the usage MAAS reports for disks without partitions or filesystems.
+const BlockDeviceUnused = "Unused"
+
+// HasAddress returns whether the given IP address is assigned to the machine.
+func (m Machine) HasAddress(address string) bool {
+	if slices.Contains(m.IPAddresses, address) {
+		return true
+	}
+
+	for _, iface := range m.Interfaces {
+		for _, link := range iface.Links {
+			if link.IPAddress == address {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// UnusedBlockDevice returns the unused disk of the machine matching the given name or serial, if any.
+func (m Machine) UnusedBlockDevice(name string, serial string) *BlockDevice {
+	for _, disk := range m.BlockDevices {
+		if disk.UsedFor != BlockDeviceUnused {
+			continue
+		}
+
+		if disk.Name == name || (serial != "" && disk.Serial == serial) {
+			return &disk
+		}
+	}
+
+	return nil
+}
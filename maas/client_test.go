@@ -0,0 +1,121 @@
+package maas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type maasSuite struct {
+	suite.Suite
+}
+
+func TestMAASSuite(t *testing.T) {
+	suite.Run(t, new(maasSuite))
+}
+
+func (m *maasSuite) Test_NewClient() {
+	cases := []struct {
+		desc   string
+		url    string
+		apiKey string
+		err    bool
+	}{
+		{desc: "Valid URL and API key", url: "http://maas:5240/MAAS/", apiKey: "a:b:c"},
+		{desc: "Unsupported scheme", url: "ftp://maas/MAAS", apiKey: "a:b:c", err: true},
+		{desc: "API key with missing parts", url: "http://maas:5240/MAAS", apiKey: "a:b", err: true},
+	}
+
+	for i, c := range cases {
+		m.T().Logf("%d: %s", i, c.desc)
+
+		_, err := NewClient(c.url, c.apiKey)
+		if c.err {
+			m.Error(err)
+		} else {
+			m.NoError(err)
+		}
+	}
+}
+
+func (m *maasSuite) Test_Client() {
+	requests := []string{}
+	descriptions := map[string]string{}
+	tagged := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.Contains(auth, `oauth_consumer_key="consumer"`) || !strings.Contains(auth, `oauth_signature="&secret"`) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+		_ = r.ParseForm()
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/MAAS/api/2.0/tags/cloud/":
+			machines := []Machine{{
+				SystemID:    "abc123",
+				Hostname:    "micro01",
+				IPAddresses: []string{"10.0.0.1"},
+				Interfaces:  []Interface{{Name: "enp5s0", Links: []Link{{IPAddress: "10.0.1.1"}}}},
+				BlockDevices: []BlockDevice{
+					{Name: "sda", Serial: "S1", UsedFor: "GPT partitioned with 2 partitions"},
+					{Name: "sdb", Serial: "S2", UsedFor: BlockDeviceUnused},
+				},
+			}}
+
+			_ = json.NewEncoder(w).Encode(machines)
+		case r.Method == http.MethodGet && r.URL.Path == "/MAAS/api/2.0/tags/microcloud/":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/MAAS/api/2.0/tags/microcloud/":
+			tagged = append(tagged, r.PostForm["add"]...)
+		case r.Method == http.MethodPut && r.URL.Path == "/MAAS/api/2.0/machines/abc123/":
+			descriptions["abc123"] = r.PostForm.Get("description")
+		}
+	}))
+
+	defer server.Close()
+
+	client, err := NewClient(server.URL+"/MAAS", "consumer:token:secret")
+	m.Require().NoError(err)
+
+	machines, err := client.Machines(context.Background(), "cloud")
+	m.Require().NoError(err)
+	m.Require().Len(machines, 1)
+
+	machine := machines[0]
+	m.Equal("micro01", machine.Hostname)
+	m.True(machine.HasAddress("10.0.0.1"))
+	m.True(machine.HasAddress("10.0.1.1"))
+	m.False(machine.HasAddress("10.0.2.1"))
+	m.Nil(machine.UnusedBlockDevice("sda", "S1"))
+	m.NotNil(machine.UnusedBlockDevice("vdb", "S2"))
+
+	m.NoError(client.SetDescription(context.Background(), "abc123", "notes"))
+	m.Equal("notes", descriptions["abc123"])
+
+	m.NoError(client.AddTag(context.Background(), "microcloud", "abc123"))
+	m.Equal([]string{"abc123"}, tagged)
+
+	m.Equal([]string{
+		"GET /MAAS/api/2.0/tags/cloud/?op=machines",
+		"PUT /MAAS/api/2.0/machines/abc123/?",
+		"GET /MAAS/api/2.0/tags/microcloud/?",
+		"POST /MAAS/api/2.0/tags/?",
+		"POST /MAAS/api/2.0/tags/microcloud/?op=update_nodes",
+	}, requests)
+
+	client, err = NewClient(server.URL+"/MAAS", "consumer:token:wrong")
+	m.Require().NoError(err)
+
+	_, err = client.Machines(context.Background(), "")
+	var statusErr *StatusError
+	m.Require().ErrorAs(err, &statusErr)
+	m.Equal(http.StatusUnauthorized, statusErr.StatusCode)
+}
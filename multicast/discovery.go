@@ -22,6 +22,9 @@ type ServerInfo struct {
 	Address     string                       `json:"address,omitempty"`
 	Services    map[types.ServiceType]string `json:"services,omitempty"`
 	Certificate *x509.Certificate            `json:"certificates,omitempty"`
+
+	// AltAddress is the address of the other IP family on dual-stack systems.
+	AltAddress string `json:"alt_address,omitempty"`
 }
 
 // Discovery represents the information used for discovering peers using multicast.
@@ -1,10 +1,18 @@
 package multicast
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
 )
 
+// reachableTimeout is how long to wait for a connection to each of the addresses of a server.
+const reachableTimeout = 3 * time.Second
+
 // NetworkInfo represents information about a network interface.
 type NetworkInfo struct {
 	Interface net.Interface
@@ -46,3 +54,53 @@ func GetNetworkInfo() ([]NetworkInfo, error) {
 
 	return networks, nil
 }
+
+// SameFamily returns whether both addresses are of the same IP family.
+func SameFamily(a string, b string) bool {
+	ipA := net.ParseIP(a)
+	ipB := net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return false
+	}
+
+	return (ipA.To4() == nil) == (ipB.To4() == nil)
+}
+
+// PreferFamily returns the first of the given addresses of the same IP family as the target address,
+// or the first address if none of them is.
+func PreferFamily(target string, addresses ...string) string {
+	for _, address := range addresses {
+		if address != "" && SameFamily(target, address) {
+			return address
+		}
+	}
+
+	if len(addresses) == 0 {
+		return ""
+	}
+
+	return addresses[0]
+}
+
+// ReachableAddress returns the address of the server accepting TCP connections on the given port, preferring Address over AltAddress.
+// Address is returned if neither is reachable, so the failure is reported when connecting to it.
+func (s ServerInfo) ReachableAddress(ctx context.Context, port int64) string {
+	if s.AltAddress == "" {
+		return s.Address
+	}
+
+	dialer := net.Dialer{Timeout: reachableTimeout}
+	for _, address := range []string{s.Address, s.AltAddress} {
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, strconv.FormatInt(port, 10)))
+		if err != nil {
+			logger.Debug("Server address is unreachable", logger.Ctx{"name": s.Name, "address": address, "err": err})
+			continue
+		}
+
+		_ = conn.Close()
+
+		return address
+	}
+
+	return s.Address
+}
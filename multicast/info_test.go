@@ -0,0 +1,45 @@
+package multicast
+
+import (
+	"context"
+	"net"
+	"strconv"
+)
+
+func (m *multicastSuite) Test_SameFamily() {
+	m.True(SameFamily("10.0.0.1", "192.168.1.1"))
+	m.True(SameFamily("fd00::1", "2001:db8::1"))
+	m.False(SameFamily("10.0.0.1", "fd00::1"))
+	m.False(SameFamily("10.0.0.1", ""))
+	m.False(SameFamily("foo", "10.0.0.1"))
+}
+
+func (m *multicastSuite) Test_PreferFamily() {
+	m.Equal("10.0.0.2", PreferFamily("10.0.0.1", "10.0.0.2", "fd00::2"))
+	m.Equal("fd00::2", PreferFamily("fd00::1", "10.0.0.2", "fd00::2"))
+	m.Equal("10.0.0.2", PreferFamily("fd00::1", "10.0.0.2", ""))
+	m.Equal("", PreferFamily("fd00::1"))
+}
+
+func (m *multicastSuite) Test_ReachableAddress() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	m.Require().NoError(err)
+	defer listener.Close()
+
+	_, portStr, err := net.SplitHostPort(listener.Addr().String())
+	m.Require().NoError(err)
+	port, err := strconv.ParseInt(portStr, 10, 64)
+	m.Require().NoError(err)
+
+	m.T().Log("Address without an alternative")
+	m.Equal("127.0.0.2", ServerInfo{Address: "127.0.0.2"}.ReachableAddress(context.Background(), port))
+
+	m.T().Log("Reachable address")
+	m.Equal("127.0.0.1", ServerInfo{Address: "127.0.0.1", AltAddress: "127.0.0.2"}.ReachableAddress(context.Background(), port))
+
+	m.T().Log("Reachable alternative address")
+	m.Equal("127.0.0.1", ServerInfo{Address: "127.0.0.2", AltAddress: "127.0.0.1"}.ReachableAddress(context.Background(), port))
+
+	m.T().Log("Unreachable addresses")
+	m.Equal("127.0.0.2", ServerInfo{Address: "127.0.0.2", AltAddress: "127.0.0.3"}.ReachableAddress(context.Background(), port))
+}
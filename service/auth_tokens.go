@@ -0,0 +1,88 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// AuthTokenMaxLifetime is the longest time an API token can stay valid for.
+const AuthTokenMaxLifetime = 366 * 24 * time.Hour
+
+// authTokenRoles are the roles of the API tokens, mapped to the roles they include.
+var authTokenRoles = map[string][]string{
+	types.AuthTokenRoleReadOnly: {types.AuthTokenRoleReadOnly},
+	types.AuthTokenRoleOperator: {types.AuthTokenRoleReadOnly, types.AuthTokenRoleOperator},
+}
+
+// ValidateAuthTokenName checks the given name can be used for an API token.
+func ValidateAuthTokenName(name string) error {
+	return validate.IsHostname(name)
+}
+
+// ValidateAuthTokenRole checks the given role is a role of the API tokens.
+func ValidateAuthTokenRole(role string) error {
+	_, ok := authTokenRoles[role]
+	if !ok {
+		return fmt.Errorf("Invalid role %q, must be one of %q or %q", role, types.AuthTokenRoleReadOnly, types.AuthTokenRoleOperator)
+	}
+
+	return nil
+}
+
+// AuthTokenAllows returns whether an API token with the given role may run a request requiring the other role.
+func AuthTokenAllows(role string, required string) bool {
+	return slices.Contains(authTokenRoles[role], required)
+}
+
+// AuthTokenExpiry returns the time an API token created at the given time with the given expiry expires.
+// The expiry is in the "<integer>(S|M|H|d|w|m|y)" format, and cannot exceed AuthTokenMaxLifetime.
+func AuthTokenExpiry(createdAt time.Time, expiry string) (time.Time, error) {
+	if expiry == "" {
+		return time.Time{}, errors.New("API tokens must have an expiry")
+	}
+
+	expiresAt, err := shared.GetExpiry(createdAt, expiry)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid expiry %q: %w", expiry, err)
+	}
+
+	if !expiresAt.After(createdAt) {
+		return time.Time{}, fmt.Errorf("Invalid expiry %q: Must be in the future", expiry)
+	}
+
+	if expiresAt.Sub(createdAt) > AuthTokenMaxLifetime {
+		return time.Time{}, fmt.Errorf("Invalid expiry %q: API tokens cannot be valid for more than a year", expiry)
+	}
+
+	return expiresAt, nil
+}
+
+// NewAuthToken generates the secret of a new API token, and returns it with its hash.
+func NewAuthToken() (string, string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to generate API token: %w", err)
+	}
+
+	token := hex.EncodeToString(secret)
+
+	return token, AuthTokenHash(token), nil
+}
+
+// AuthTokenHash returns the hash of the secret of an API token, as stored by MicroCloud.
+func AuthTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
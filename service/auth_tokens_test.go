@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type authTokensSuite struct {
+	suite.Suite
+}
+
+func TestAuthTokensSuite(t *testing.T) {
+	suite.Run(t, new(authTokensSuite))
+}
+
+func (s *authTokensSuite) Test_AuthTokenAllows() {
+	s.True(AuthTokenAllows(types.AuthTokenRoleReadOnly, types.AuthTokenRoleReadOnly))
+	s.False(AuthTokenAllows(types.AuthTokenRoleReadOnly, types.AuthTokenRoleOperator))
+	s.True(AuthTokenAllows(types.AuthTokenRoleOperator, types.AuthTokenRoleReadOnly))
+	s.True(AuthTokenAllows(types.AuthTokenRoleOperator, types.AuthTokenRoleOperator))
+	s.False(AuthTokenAllows("admin", types.AuthTokenRoleReadOnly))
+
+	s.NoError(ValidateAuthTokenRole(types.AuthTokenRoleOperator))
+	s.Error(ValidateAuthTokenRole("admin"))
+}
+
+func (s *authTokensSuite) Test_AuthTokenExpiry() {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc      string
+		expiry    string
+		expiresAt time.Time
+		err       bool
+	}{
+		{desc: "Days", expiry: "30d", expiresAt: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{desc: "Days and hours", expiry: "1d 12H", expiresAt: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)},
+		{desc: "One year", expiry: "1y", expiresAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{desc: "No expiry", expiry: "", err: true},
+		{desc: "Zero expiry", expiry: "0d", err: true},
+		{desc: "More than a year", expiry: "2y", err: true},
+		{desc: "Invalid expiry", expiry: "30 days", err: true},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		expiresAt, err := AuthTokenExpiry(createdAt, c.expiry)
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+			s.Equal(c.expiresAt, expiresAt)
+		}
+	}
+}
+
+func (s *authTokensSuite) Test_NewAuthToken() {
+	token, hash, err := NewAuthToken()
+	s.NoError(err)
+	s.Len(token, 64)
+	s.Equal(AuthTokenHash(token), hash)
+	s.NotEqual(token, hash)
+
+	other, _, err := NewAuthToken()
+	s.NoError(err)
+	s.NotEqual(token, other)
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// BenchmarkVolumePrefix is the prefix of the temporary custom volumes the storage benchmarks run on.
+const BenchmarkVolumePrefix = "microcloud-benchmark-"
+
+// benchmarkVolumeSize is the size of the temporary custom volumes the storage benchmarks run on.
+const benchmarkVolumeSize = "1GiB"
+
+// BenchmarkTest is a fio test run against a storage pool.
+type BenchmarkTest struct {
+	Name      string
+	RW        string
+	BlockSize string
+}
+
+// BenchmarkTests are the fio tests run against each storage pool.
+var BenchmarkTests = []BenchmarkTest{
+	{Name: "randread", RW: "randread", BlockSize: "4k"},
+	{Name: "randwrite", RW: "randwrite", BlockSize: "4k"},
+	{Name: "read", RW: "read", BlockSize: "1M"},
+	{Name: "write", RW: "write", BlockSize: "1M"},
+}
+
+// fioResult is the subset of the JSON output of fio used for the benchmark results.
+type fioResult struct {
+	Jobs []struct {
+		Read  fioJobResult `json:"read"`
+		Write fioJobResult `json:"write"`
+	} `json:"jobs"`
+}
+
+// fioJobResult holds the results of a fio job in one direction.
+type fioJobResult struct {
+	IOPS     float64 `json:"iops"`
+	BWBytes  int64   `json:"bw_bytes"`
+	ClatNSec struct {
+		Mean float64 `json:"mean"`
+	} `json:"clat_ns"`
+}
+
+// parseFioOutput returns the results of the given test from the JSON output of fio.
+func parseFioOutput(out string, test BenchmarkTest) (*types.Benchmark, error) {
+	result := fioResult{}
+	err := json.Unmarshal([]byte(out), &result)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse fio output: %w", err)
+	}
+
+	if len(result.Jobs) == 0 {
+		return nil, errors.New("fio didn't report any jobs")
+	}
+
+	job := result.Jobs[0].Read
+	if test.RW == "write" || test.RW == "randwrite" {
+		job = result.Jobs[0].Write
+	}
+
+	return &types.Benchmark{
+		Test:      test.Name,
+		IOPS:      job.IOPS,
+		Bandwidth: job.BWBytes,
+		Latency:   int64(job.ClatNSec.Mean),
+	}, nil
+}
+
+// benchmarkTarget returns the fio arguments to run against the given custom block volume.
+// Only ZFS and Ceph storage pools are supported, as MicroCloud sets up the local and remote storage pools with those drivers.
+func benchmarkTarget(pool api.StoragePool, volume string) ([]string, error) {
+	switch pool.Driver {
+	case "zfs":
+		return []string{"--ioengine=libaio", "--filename=/dev/zvol/" + pool.Config["zfs.pool_name"] + "/custom/default_" + volume}, nil
+	case "ceph":
+		user := pool.Config["ceph.user.name"]
+		if user == "" {
+			user = "admin"
+		}
+
+		return []string{"--ioengine=rbd", "--clientname=" + user, "--pool=" + pool.Config["ceph.osd.pool_name"], "--rbdname=custom_default_" + volume + ".block"}, nil
+	}
+
+	return nil, fmt.Errorf("Storage pool %q uses the unsupported driver %q", pool.Name, pool.Driver)
+}
+
+// BenchmarkPools returns the storage pools on this system that can be benchmarked.
+func (s LXDService) BenchmarkPools(ctx context.Context) ([]string, error) {
+	client, err := s.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pools, err := client.GetStoragePools()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get storage pools: %w", err)
+	}
+
+	names := []string{}
+	for _, pool := range pools {
+		if pool.Status == api.StoragePoolStatusCreated && (pool.Driver == "zfs" || pool.Driver == "ceph") {
+			names = append(names, pool.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// Benchmark runs the fio tests against a temporary custom block volume on the given storage pool of this system.
+func (s LXDService) Benchmark(ctx context.Context, poolName string, runtime time.Duration) ([]types.Benchmark, error) {
+	client, err := s.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the pool from this system, as the ZFS pool name is member specific.
+	client = client.UseTarget(s.name)
+	pool, _, err := client.GetStoragePool(poolName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get storage pool %q: %w", poolName, err)
+	}
+
+	// Remote pools are shared by all systems, so the volume name includes the system name.
+	volume := BenchmarkVolumePrefix + s.name
+	target, err := benchmarkTarget(*pool, volume)
+	if err != nil {
+		return nil, err
+	}
+
+	volumePost := api.StorageVolumesPost{
+		Name:             volume,
+		Type:             "custom",
+		ContentType:      "block",
+		StorageVolumePut: api.StorageVolumePut{Config: map[string]string{"size": benchmarkVolumeSize}},
+	}
+
+	op, err := client.CreateStoragePoolVolume(poolName, volumePost)
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create benchmark volume on storage pool %q: %w", poolName, err)
+	}
+
+	defer func() {
+		op, err := client.DeleteStoragePoolVolume(poolName, "custom", volume)
+		if err == nil {
+			_ = op.Wait()
+		}
+	}()
+
+	benchmarks := make([]types.Benchmark, 0, len(BenchmarkTests))
+	for _, test := range BenchmarkTests {
+		args := append([]string{
+			"--name=" + test.Name,
+			"--rw=" + test.RW,
+			"--bs=" + test.BlockSize,
+			"--iodepth=32",
+			"--direct=1",
+			"--time_based",
+			"--runtime=" + strconv.FormatInt(int64(runtime.Seconds()), 10),
+			"--size=" + benchmarkVolumeSize,
+			"--output-format=json",
+		}, target...)
+
+		out, err := shared.RunCommandContext(ctx, "fio", args...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to run fio test %q on storage pool %q: %w", test.Name, poolName, err)
+		}
+
+		benchmark, err := parseFioOutput(out, test)
+		if err != nil {
+			return nil, err
+		}
+
+		benchmark.Member = s.name
+		benchmark.Pool = poolName
+		benchmark.CreatedAt = time.Now().UTC()
+		benchmarks = append(benchmarks, *benchmark)
+	}
+
+	return benchmarks, nil
+}
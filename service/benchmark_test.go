@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type benchmarkSuite struct {
+	suite.Suite
+}
+
+func TestBenchmarkSuite(t *testing.T) {
+	suite.Run(t, new(benchmarkSuite))
+}
+
+func (s *benchmarkSuite) Test_parseFioOutput() {
+	out := `{"jobs": [{"read": {"iops": 1500.5, "bw_bytes": 6146048, "clat_ns": {"mean": 21000.7}}, "write": {"iops": 700.25, "bw_bytes": 2868224, "clat_ns": {"mean": 45000.2}}}]}`
+
+	benchmark, err := parseFioOutput(out, BenchmarkTest{Name: "randread", RW: "randread", BlockSize: "4k"})
+	s.NoError(err)
+	s.Equal("randread", benchmark.Test)
+	s.Equal(1500.5, benchmark.IOPS)
+	s.Equal(int64(6146048), benchmark.Bandwidth)
+	s.Equal(int64(21000), benchmark.Latency)
+
+	benchmark, err = parseFioOutput(out, BenchmarkTest{Name: "randwrite", RW: "randwrite", BlockSize: "4k"})
+	s.NoError(err)
+	s.Equal(700.25, benchmark.IOPS)
+	s.Equal(int64(2868224), benchmark.Bandwidth)
+	s.Equal(int64(45000), benchmark.Latency)
+
+	_, err = parseFioOutput(`{"jobs": []}`, BenchmarkTests[0])
+	s.Error(err)
+
+	_, err = parseFioOutput("fio: failed", BenchmarkTests[0])
+	s.Error(err)
+}
+
+func (s *benchmarkSuite) Test_benchmarkTarget() {
+	cases := []struct {
+		desc string
+		pool api.StoragePool
+		args []string
+		err  bool
+	}{
+		{
+			desc: "ZFS pool",
+			pool: api.StoragePool{Name: "local", Driver: "zfs", Config: map[string]string{"zfs.pool_name": "local"}},
+			args: []string{"--ioengine=libaio", "--filename=/dev/zvol/local/custom/default_vol"},
+		},
+		{
+			desc: "Ceph pool",
+			pool: api.StoragePool{Name: "remote", Driver: "ceph", Config: map[string]string{"ceph.osd.pool_name": "lxd_remote"}},
+			args: []string{"--ioengine=rbd", "--clientname=admin", "--pool=lxd_remote", "--rbdname=custom_default_vol.block"},
+		},
+		{
+			desc: "Unsupported driver",
+			pool: api.StoragePool{Name: "remote-fs", Driver: "cephfs"},
+			err:  true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		args, err := benchmarkTarget(c.pool, "vol")
+		if c.err {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.args, args)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+const (
+	// BootStepDatabase is the boot step waiting for the MicroCloud database to come online.
+	BootStepDatabase = "MicroCloud database"
+
+	// BootStepStoragePools is the boot step waiting for LXD to set up the MicroCloud storage pools.
+	BootStepStoragePools = "LXD storage pools"
+
+	// BootStepNetworks is the boot step waiting for LXD to set up the MicroCloud networks.
+	BootStepNetworks = "LXD networks"
+)
+
+// bootOrder is the order the services are waited for after the system started.
+// LXD comes last, as its storage pools and networks depend on MicroCeph and MicroOVN.
+var bootOrder = []types.ServiceType{types.MicroCeph, types.MicroOVN, types.LXD}
+
+// maxBootRetryDelay is the longest delay between two attempts of a boot step.
+const maxBootRetryDelay = 30 * time.Second
+
+// BootSteps returns the steps to wait for after the system started, in dependency order, for the given installed services.
+func BootSteps(services map[types.ServiceType]Service) []string {
+	steps := []string{BootStepDatabase}
+	for _, serviceType := range bootOrder {
+		if services[serviceType] != nil {
+			steps = append(steps, string(serviceType))
+		}
+	}
+
+	if services[types.LXD] != nil {
+		steps = append(steps, BootStepStoragePools, BootStepNetworks)
+	}
+
+	return steps
+}
+
+// BootRetryDelay returns the delay before the next attempt of a boot step, doubling with each failed attempt.
+func BootRetryDelay(attempt int) time.Duration {
+	delay := time.Second
+	for range attempt {
+		delay *= 2
+		if delay >= maxBootRetryDelay {
+			return maxBootRetryDelay
+		}
+	}
+
+	return delay
+}
+
+// StartBoot starts tracking the given boot steps, forgetting any earlier boot.
+func (s *Handler) StartBoot(steps []string) {
+	s.bootLock.Lock()
+	defer s.bootLock.Unlock()
+
+	now := time.Now().UTC()
+	s.boot = make([]types.BootStep, 0, len(steps))
+	for _, step := range steps {
+		s.boot = append(s.boot, types.BootStep{Name: step, Status: types.BootStepWaiting, UpdatedAt: now})
+	}
+}
+
+// UpdateBoot records the status of the given boot step, along with the error of its last attempt.
+func (s *Handler) UpdateBoot(step string, status string, stepErr error) {
+	s.bootLock.Lock()
+	defer s.bootLock.Unlock()
+
+	index := slices.IndexFunc(s.boot, func(b types.BootStep) bool { return b.Name == step })
+	if index < 0 {
+		return
+	}
+
+	if s.boot[index].Status != status {
+		s.boot[index].Status = status
+		s.boot[index].UpdatedAt = time.Now().UTC()
+	}
+
+	s.boot[index].Error = ""
+	if stepErr != nil {
+		s.boot[index].Error = stepErr.Error()
+	}
+}
+
+// BootProgress returns the state of each boot step since the daemon started.
+func (s *Handler) BootProgress() []types.BootStep {
+	s.bootLock.Lock()
+	defer s.bootLock.Unlock()
+
+	return slices.Clone(s.boot)
+}
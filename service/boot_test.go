@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type bootSuite struct {
+	suite.Suite
+}
+
+func TestBootSuite(t *testing.T) {
+	suite.Run(t, new(bootSuite))
+}
+
+func (s *bootSuite) Test_bootSteps() {
+	s.Equal([]string{BootStepDatabase}, BootSteps(map[types.ServiceType]Service{types.MicroCloud: &CloudService{}}))
+
+	s.T().Log("LXD comes after MicroCeph and MicroOVN, followed by its storage pools and networks")
+	services := map[types.ServiceType]Service{types.LXD: &LXDService{}, types.MicroCloud: &CloudService{}, types.MicroOVN: &OVNService{}, types.MicroCeph: &CephService{}}
+	s.Equal([]string{BootStepDatabase, string(types.MicroCeph), string(types.MicroOVN), string(types.LXD), BootStepStoragePools, BootStepNetworks}, BootSteps(services))
+}
+
+func (s *bootSuite) Test_bootRetryDelay() {
+	s.Equal(time.Second, BootRetryDelay(0))
+	s.Equal(4*time.Second, BootRetryDelay(2))
+	s.Equal(maxBootRetryDelay, BootRetryDelay(5))
+	s.Equal(maxBootRetryDelay, BootRetryDelay(100))
+}
+
+func (s *bootSuite) Test_bootProgress() {
+	sh := &Handler{}
+	sh.StartBoot([]string{BootStepDatabase, string(types.LXD)})
+
+	sh.UpdateBoot(BootStepDatabase, types.BootStepReady, nil)
+	sh.UpdateBoot(string(types.LXD), types.BootStepWaiting, errors.New("LXD is not ready"))
+
+	s.T().Log("Unknown steps are ignored")
+	sh.UpdateBoot(BootStepNetworks, types.BootStepReady, nil)
+
+	steps := sh.BootProgress()
+	s.Len(steps, 2)
+	s.Equal(types.BootStepReady, steps[0].Status)
+	s.Empty(steps[0].Error)
+	s.Equal(types.BootStepWaiting, steps[1].Status)
+	s.Equal("LXD is not ready", steps[1].Error)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// cephDeviceClassPattern matches the CRUSH device class names accepted by Ceph.
+var cephDeviceClassPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// CephTier is a performance tier of the distributed storage, backed by the OSDs of a single CRUSH device class.
+// Each tier gets its own LXD storage pool, whose OSD pool only places data on the OSDs of the tier.
+type CephTier struct {
+	// Name is the name of the LXD storage pool of the tier.
+	Name string `yaml:"name"`
+
+	// DeviceClass is the CRUSH device class of the OSDs backing the tier, such as nvme, ssd or hdd.
+	DeviceClass string `yaml:"device_class"`
+}
+
+// OSDPool returns the name of the OSD pool backing the storage pool of the tier.
+// The CRUSH rule of the tier has the same name.
+func (t CephTier) OSDPool() string {
+	return "lxd_tier_" + t.Name
+}
+
+// ValidateCephTiers checks the tiers are valid, and their storage pools don't clash with each other or with the given storage pool names.
+func ValidateCephTiers(tiers []CephTier, names ResourceNames) error {
+	seenNames := map[string]bool{names.LocalPool: true, names.RemotePool: true, names.RemoteFSPool: true}
+	seenClasses := map[string]bool{}
+	for _, tier := range tiers {
+		err := ValidateStoragePoolName(tier.Name)
+		if err != nil {
+			return fmt.Errorf("Invalid tier storage pool name %q: %w", tier.Name, err)
+		}
+
+		if seenNames[tier.Name] {
+			return fmt.Errorf("Name %q is used more than once", tier.Name)
+		}
+
+		if !cephDeviceClassPattern.MatchString(tier.DeviceClass) {
+			return fmt.Errorf("Invalid device class %q of tier %q, must only contain letters, numbers, dashes and underscores", tier.DeviceClass, tier.Name)
+		}
+
+		if seenClasses[tier.DeviceClass] {
+			return fmt.Errorf("Device class %q is used by more than one tier", tier.DeviceClass)
+		}
+
+		seenNames[tier.Name] = true
+		seenClasses[tier.DeviceClass] = true
+	}
+
+	return nil
+}
+
+// ParseCephTiers parses tiers given as a comma separated list of <pool>:<device class> pairs.
+func ParseCephTiers(value string) ([]CephTier, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	tiers := []CephTier{}
+	for _, entry := range strings.Split(value, ",") {
+		name, class, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("Invalid tier %q, must be of the form <pool>:<device class>", entry)
+		}
+
+		tiers = append(tiers, CephTier{Name: name, DeviceClass: class})
+	}
+
+	err := ValidateCephTiers(tiers, ResourceNames{})
+	if err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
+}
+
+// FormatCephTiers returns the tiers as a comma separated list of <pool>:<device class> pairs.
+func FormatCephTiers(tiers []CephTier) string {
+	entries := make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		entries = append(entries, tier.Name+":"+tier.DeviceClass)
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// FindCephTier returns the tier with the given storage pool name.
+func FindCephTier(tiers []CephTier, name string) (CephTier, error) {
+	for _, tier := range tiers {
+		if tier.Name == name {
+			return tier, nil
+		}
+	}
+
+	return CephTier{}, fmt.Errorf("Unknown tier %q", name)
+}
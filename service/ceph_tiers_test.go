@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type cephTiersSuite struct {
+	suite.Suite
+}
+
+func TestCephTiersSuite(t *testing.T) {
+	suite.Run(t, new(cephTiersSuite))
+}
+
+func (s *cephTiersSuite) Test_cephTiersConfig() {
+	tiers, err := ParseCephTiers("")
+	s.NoError(err)
+	s.Empty(tiers)
+
+	tiers, err = ParseCephTiers("remote-fast:nvme, remote-capacity:hdd")
+	s.NoError(err)
+	s.Equal([]CephTier{{Name: "remote-fast", DeviceClass: "nvme"}, {Name: "remote-capacity", DeviceClass: "hdd"}}, tiers)
+	s.Equal("remote-fast:nvme,remote-capacity:hdd", FormatCephTiers(tiers))
+	s.Equal("lxd_tier_remote-fast", tiers[0].OSDPool())
+
+	_, err = ParseCephTiers("remote-fast")
+	s.Error(err)
+
+	tier, err := FindCephTier(tiers, "remote-capacity")
+	s.NoError(err)
+	s.Equal("hdd", tier.DeviceClass)
+
+	_, err = FindCephTier(tiers, "remote")
+	s.Error(err)
+}
+
+func (s *cephTiersSuite) Test_validateCephTiers() {
+	cases := []struct {
+		desc  string
+		tiers []CephTier
+		err   bool
+	}{
+		{
+			desc:  "Distinct tiers",
+			tiers: []CephTier{{Name: "remote-fast", DeviceClass: "nvme"}, {Name: "remote-capacity", DeviceClass: "hdd"}},
+		},
+		{
+			desc:  "Tier named like the remote storage pool",
+			tiers: []CephTier{{Name: DefaultCephPool, DeviceClass: "nvme"}},
+			err:   true,
+		},
+		{
+			desc:  "Duplicate tier names",
+			tiers: []CephTier{{Name: "remote-fast", DeviceClass: "nvme"}, {Name: "remote-fast", DeviceClass: "ssd"}},
+			err:   true,
+		},
+		{
+			desc:  "Duplicate device classes",
+			tiers: []CephTier{{Name: "remote-fast", DeviceClass: "nvme"}, {Name: "remote-faster", DeviceClass: "nvme"}},
+			err:   true,
+		},
+		{
+			desc:  "Missing device class",
+			tiers: []CephTier{{Name: "remote-fast"}},
+			err:   true,
+		},
+		{
+			desc:  "Invalid device class",
+			tiers: []CephTier{{Name: "remote-fast", DeviceClass: "nvme ssd"}},
+			err:   true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		err := ValidateCephTiers(c.tiers, DefaultResourceNames())
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+		}
+	}
+}
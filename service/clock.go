@@ -0,0 +1,137 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// DefaultClockSkewThreshold is the clock skew between cluster members above which they are reported as skewed, unless configured otherwise.
+// It matches the clock drift allowed between Ceph monitors.
+const DefaultClockSkewThreshold = 50 * time.Millisecond
+
+// DefaultClockSkewLimit is the clock skew to a system above which it can't be set up, unless configured otherwise.
+const DefaultClockSkewLimit = 500 * time.Millisecond
+
+// ClockSample is the clock of another cluster member, as measured during a heartbeat.
+type ClockSample struct {
+	Name         string
+	Skew         time.Duration
+	Synchronized bool
+}
+
+// ClockChange is a change of the clock of another cluster member between two rounds of heartbeats.
+type ClockChange struct {
+	Clock types.MemberClock
+
+	// SkewChanged is whether the clock became skewed, or is no longer skewed.
+	SkewChanged bool
+
+	// SyncChanged is whether the clock lost or regained its time source.
+	SyncChanged bool
+}
+
+// LocalClock returns the clock of the local system.
+func LocalClock() types.Clock {
+	clock := types.Clock{Time: time.Now().UTC()}
+
+	// Without any modes set, adjtimex only reads the state of the kernel clock.
+	var timex unix.Timex
+	state, err := unix.Adjtimex(&timex)
+	if err != nil {
+		logger.Warn("Failed to get the state of the system clock", logger.Ctx{"err": err})
+		return clock
+	}
+
+	clock.Synchronized = state != unix.TIME_ERROR && timex.Status&unix.STA_UNSYNC == 0
+
+	return clock
+}
+
+// ClockSkew returns how far the remote clock is ahead of the local clock, given the local times the request for the remote clock
+// was sent and its response received. The remote clock is assumed to be read halfway through the round trip.
+func ClockSkew(sent time.Time, received time.Time, remote time.Time) time.Duration {
+	return remote.Sub(sent.Add(received.Sub(sent) / 2))
+}
+
+// nextMemberClock returns the clock of a cluster member after the given measurement, and how it changed since the previous one if any.
+// A cluster member without previous measurement is assumed to have had a synchronized clock without skew.
+func nextMemberClock(previous *types.MemberClock, sample ClockSample, threshold time.Duration, now time.Time) ClockChange {
+	clock := types.MemberClock{
+		Name:         sample.Name,
+		Skew:         sample.Skew,
+		Skewed:       sample.Skew.Abs() > threshold,
+		Synchronized: sample.Synchronized,
+		CheckedAt:    now,
+	}
+
+	wasSkewed := false
+	wasSynchronized := true
+	if previous != nil {
+		wasSkewed = previous.Skewed
+		wasSynchronized = previous.Synchronized
+	}
+
+	return ClockChange{
+		Clock:       clock,
+		SkewChanged: clock.Skewed != wasSkewed,
+		SyncChanged: clock.Synchronized != wasSynchronized,
+	}
+}
+
+// RecordClockSamples records the clocks of the other cluster members measured during a round of heartbeats, and returns the ones which changed.
+// Members missing from the round couldn't be measured, and keep their last measurement as long as they are part of the cluster.
+func (s *Handler) RecordClockSamples(samples []ClockSample, members []string, threshold time.Duration) []ClockChange {
+	s.clocksLock.Lock()
+	defer s.clocksLock.Unlock()
+
+	now := time.Now()
+	clocks := make([]types.MemberClock, 0, len(members))
+	var changed []ClockChange
+	for _, previous := range s.clocks {
+		if slices.Contains(members, previous.Name) && !slices.ContainsFunc(samples, func(sample ClockSample) bool { return sample.Name == previous.Name }) {
+			clocks = append(clocks, previous)
+		}
+	}
+
+	for _, sample := range samples {
+		var previous *types.MemberClock
+		index := slices.IndexFunc(s.clocks, func(c types.MemberClock) bool { return c.Name == sample.Name })
+		if index >= 0 {
+			previous = &s.clocks[index]
+		}
+
+		change := nextMemberClock(previous, sample, threshold, now)
+		clocks = append(clocks, change.Clock)
+		if change.SkewChanged || change.SyncChanged {
+			logger.Info("Clock of cluster member changed", logger.Ctx{"name": sample.Name, "skew": sample.Skew, "skewed": change.Clock.Skewed, "synchronized": sample.Synchronized})
+			changed = append(changed, change)
+		}
+	}
+
+	slices.SortFunc(clocks, func(a types.MemberClock, b types.MemberClock) int { return strings.Compare(a.Name, b.Name) })
+	s.clocks = clocks
+
+	return changed
+}
+
+// MemberClocks returns the clocks of the other cluster members as of their last measurement.
+func (s *Handler) MemberClocks() []types.MemberClock {
+	s.clocksLock.Lock()
+	defer s.clocksLock.Unlock()
+
+	return slices.Clone(s.clocks)
+}
+
+// ResetMemberClocks forgets the clocks of the other cluster members, while the daemon is not part of a cluster.
+func (s *Handler) ResetMemberClocks() {
+	s.clocksLock.Lock()
+	defer s.clocksLock.Unlock()
+
+	s.clocks = nil
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type clockSuite struct {
+	suite.Suite
+}
+
+func TestClockSuite(t *testing.T) {
+	suite.Run(t, new(clockSuite))
+}
+
+func (s *clockSuite) Test_clockSkew() {
+	sent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	received := sent.Add(20 * time.Millisecond)
+
+	s.Equal(time.Duration(0), ClockSkew(sent, received, sent.Add(10*time.Millisecond)))
+	s.Equal(time.Second, ClockSkew(sent, received, sent.Add(time.Second+10*time.Millisecond)))
+	s.Equal(-time.Second, ClockSkew(sent, received, sent.Add(-time.Second+10*time.Millisecond)))
+}
+
+func (s *clockSuite) Test_nextMemberClock() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc      string
+		previous  *types.MemberClock
+		sample    ClockSample
+		threshold time.Duration
+
+		expectSkewed      bool
+		expectSkewChanged bool
+		expectSyncChanged bool
+	}{
+		{
+			desc:      "First measurement within the threshold",
+			sample:    ClockSample{Skew: 10 * time.Millisecond, Synchronized: true},
+			threshold: 50 * time.Millisecond,
+		},
+		{
+			desc:              "First measurement of a skewed and unsynchronized clock",
+			sample:            ClockSample{Skew: -100 * time.Millisecond},
+			threshold:         50 * time.Millisecond,
+			expectSkewed:      true,
+			expectSkewChanged: true,
+			expectSyncChanged: true,
+		},
+		{
+			desc:         "Clock still skewed",
+			previous:     &types.MemberClock{Skewed: true, Synchronized: true},
+			sample:       ClockSample{Skew: 80 * time.Millisecond, Synchronized: true},
+			threshold:    50 * time.Millisecond,
+			expectSkewed: true,
+		},
+		{
+			desc:              "Clock back within the threshold after regaining its time source",
+			previous:          &types.MemberClock{Skewed: true},
+			sample:            ClockSample{Skew: time.Millisecond, Synchronized: true},
+			threshold:         50 * time.Millisecond,
+			expectSkewChanged: true,
+			expectSyncChanged: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		change := nextMemberClock(c.previous, c.sample, c.threshold, now)
+		s.Equal(c.expectSkewed, change.Clock.Skewed)
+		s.Equal(c.expectSkewChanged, change.SkewChanged)
+		s.Equal(c.expectSyncChanged, change.SyncChanged)
+		s.Equal(now, change.Clock.CheckedAt)
+	}
+}
+
+func (s *clockSuite) Test_recordClockSamples() {
+	sh := &Handler{}
+
+	changed := sh.RecordClockSamples([]ClockSample{{Name: "micro03", Skew: time.Second, Synchronized: true}, {Name: "micro02", Synchronized: true}}, []string{"micro01", "micro02", "micro03"}, 50*time.Millisecond)
+	s.Len(changed, 1)
+	s.Equal("micro03", changed[0].Clock.Name)
+
+	clocks := sh.MemberClocks()
+	s.Len(clocks, 2)
+	s.Equal("micro02", clocks[0].Name)
+
+	s.T().Log("Members which couldn't be measured keep their last measurement while they are in the cluster")
+	changed = sh.RecordClockSamples([]ClockSample{{Name: "micro02", Synchronized: true}}, []string{"micro01", "micro02", "micro03"}, 50*time.Millisecond)
+	s.Empty(changed)
+	s.Len(sh.MemberClocks(), 2)
+
+	changed = sh.RecordClockSamples([]ClockSample{{Name: "micro02", Synchronized: true}}, []string{"micro01", "micro02"}, 50*time.Millisecond)
+	s.Empty(changed)
+	s.Len(sh.MemberClocks(), 1)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// ServiceCompatibility describes the versions of a service supported by this MicroCloud, and the API extensions it relies on.
+type ServiceCompatibility struct {
+	// MinVersion is the lowest supported major.minor version.
+	MinVersion string
+
+	// MaxVersion is the highest supported major.minor version, if newer versions aren't supported.
+	MaxVersion string
+
+	// Extensions are the API extensions the service must provide.
+	Extensions []string
+}
+
+// CompatibilityMatrix holds the supported versions and required API extensions of each service.
+var CompatibilityMatrix = map[types.ServiceType]ServiceCompatibility{
+	types.LXD: {
+		MinVersion: lxdMinVersion,
+		Extensions: []string{"clustering_join_token", "projects", "resources_disk_id", "network_type_ovn"},
+	},
+	types.MicroCeph: {
+		MinVersion: microCephMinVersion,
+		MaxVersion: microCephMinVersion,
+	},
+	types.MicroOVN: {
+		MinVersion: microOVNMinVersion,
+	},
+}
+
+// CompatibilityIssue is a service version or API extension on a system which isn't supported by this MicroCloud.
+type CompatibilityIssue struct {
+	// System is the name of the affected system, or empty if the issue is a combination of systems.
+	System string
+
+	// Service is the affected service.
+	Service types.ServiceType
+
+	// Message describes the issue.
+	Message string
+}
+
+// cephVersionRegex matches the version number in the version string of MicroCeph.
+var cephVersionRegex = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+// majorMinor returns the canonical major.minor version of the given service version.
+func majorMinor(serviceType types.ServiceType, version string) (string, error) {
+	if serviceType == types.MicroCeph {
+		match := cephVersionRegex.FindString(version)
+		if match != "" {
+			version = match
+		}
+	}
+
+	canonical := semver.Canonical("v" + cleanVersion(version))
+	if canonical == "" {
+		return "", fmt.Errorf("%s version format not supported (%s)", serviceType, version)
+	}
+
+	return semver.MajorMinor(canonical), nil
+}
+
+// CheckCompatibility compares the service versions and API extensions reported by each system against the compatibility matrix.
+// Systems which run different major.minor versions of a service are reported as well, as services can't be clustered across them.
+func CheckCompatibility(systems map[string]map[types.ServiceType]types.ServiceVersion) []CompatibilityIssue {
+	issues := []CompatibilityIssue{}
+	versions := map[types.ServiceType]map[string][]string{}
+	for _, name := range slices.Sorted(maps.Keys(systems)) {
+		for _, serviceType := range slices.Sorted(maps.Keys(systems[name])) {
+			compat, ok := CompatibilityMatrix[serviceType]
+			if !ok {
+				continue
+			}
+
+			issue := func(format string, args ...any) {
+				issues = append(issues, CompatibilityIssue{System: name, Service: serviceType, Message: fmt.Sprintf(format, args...)})
+			}
+
+			serviceVersion := systems[name][serviceType]
+			if serviceVersion.Error != "" {
+				issue("%s on %q: %s", serviceType, name, serviceVersion.Error)
+				continue
+			}
+
+			version, err := majorMinor(serviceType, serviceVersion.Version)
+			if err != nil {
+				issue("%s on %q: %v", serviceType, name, err)
+				continue
+			}
+
+			if versions[serviceType] == nil {
+				versions[serviceType] = map[string][]string{}
+			}
+
+			versions[serviceType][version] = append(versions[serviceType][version], name)
+
+			minVersion, _ := majorMinor(serviceType, compat.MinVersion)
+			if semver.Compare(version, minVersion) < 0 {
+				issue("%s version %q on %q is older than the oldest supported version %s", serviceType, serviceVersion.Version, name, compat.MinVersion)
+			}
+
+			if compat.MaxVersion != "" {
+				maxVersion, _ := majorMinor(serviceType, compat.MaxVersion)
+				if semver.Compare(version, maxVersion) > 0 {
+					issue("%s version %q on %q is newer than the newest supported version %s", serviceType, serviceVersion.Version, name, compat.MaxVersion)
+				}
+			}
+
+			missing := []string{}
+			for _, extension := range compat.Extensions {
+				if !slices.Contains(serviceVersion.Extensions, extension) {
+					missing = append(missing, extension)
+				}
+			}
+
+			if len(missing) > 0 {
+				issue("%s on %q lacks the required API extensions %s", serviceType, name, strings.Join(missing, ", "))
+			}
+		}
+	}
+
+	for _, serviceType := range slices.Sorted(maps.Keys(versions)) {
+		if len(versions[serviceType]) < 2 {
+			continue
+		}
+
+		groups := []string{}
+		for _, version := range slices.Sorted(maps.Keys(versions[serviceType])) {
+			groups = append(groups, fmt.Sprintf("%s on %s", strings.TrimPrefix(version, "v"), strings.Join(versions[serviceType][version], ", ")))
+		}
+
+		issues = append(issues, CompatibilityIssue{Service: serviceType, Message: fmt.Sprintf("%s runs different versions across the systems: %s", serviceType, strings.Join(groups, "; "))})
+	}
+
+	return issues
+}
+
+// ServiceVersions returns the version and API extensions of each service installed on the local system.
+func (sh *Handler) ServiceVersions(ctx context.Context) map[types.ServiceType]types.ServiceVersion {
+	versions := make(map[types.ServiceType]types.ServiceVersion, len(sh.Services))
+	for serviceType, s := range sh.Services {
+		serviceVersion := types.ServiceVersion{}
+
+		version, err := s.GetVersion(ctx)
+		if err != nil {
+			serviceVersion.Error = err.Error()
+			versions[serviceType] = serviceVersion
+			continue
+		}
+
+		serviceVersion.Version = version
+		serviceVersion.Extensions, err = s.GetExtensions(ctx)
+		if err != nil {
+			serviceVersion.Error = err.Error()
+		}
+
+		versions[serviceType] = serviceVersion
+	}
+
+	return versions
+}
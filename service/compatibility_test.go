@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type compatibilitySuite struct {
+	suite.Suite
+}
+
+func TestCompatibilitySuite(t *testing.T) {
+	suite.Run(t, new(compatibilitySuite))
+}
+
+func (s *compatibilitySuite) Test_checkCompatibility() {
+	lxdExtensions := CompatibilityMatrix[types.LXD].Extensions
+	supported := map[types.ServiceType]types.ServiceVersion{
+		types.MicroCloud: {Version: "2.1.0"},
+		types.LXD:        {Version: "5.21.3", Extensions: lxdExtensions},
+		types.MicroCeph:  {Version: "ceph-version: 19.2.0-0ubuntu0.24.04.1; microceph-git: 1a2b3c"},
+		types.MicroOVN:   {Version: "24.03.2"},
+	}
+
+	cases := []struct {
+		desc    string
+		systems map[string]map[types.ServiceType]types.ServiceVersion
+		issues  []string
+	}{
+		{
+			desc:    "Supported versions on all systems",
+			systems: map[string]map[types.ServiceType]types.ServiceVersion{"micro01": supported, "micro02": supported},
+		},
+		{
+			desc: "Old LXD lacking API extensions",
+			systems: map[string]map[types.ServiceType]types.ServiceVersion{
+				"micro01": {types.LXD: {Version: "5.0.3", Extensions: lxdExtensions[:1]}},
+			},
+			issues: []string{
+				`LXD version "5.0.3" on "micro01" is older than the oldest supported version 5.21`,
+				`LXD on "micro01" lacks the required API extensions ` + "projects, resources_disk_id, network_type_ovn",
+			},
+		},
+		{
+			desc: "Newer MicroCeph and a version which couldn't be determined",
+			systems: map[string]map[types.ServiceType]types.ServiceVersion{
+				"micro01": {types.MicroCeph: {Version: "ceph-version: 20.1.0"}},
+				"micro02": {types.MicroOVN: {Error: "Failed to get MicroOVN status"}},
+			},
+			issues: []string{
+				`MicroCeph version "ceph-version: 20.1.0" on "micro01" is newer than the newest supported version 19.2`,
+				`MicroOVN on "micro02": Failed to get MicroOVN status`,
+			},
+		},
+		{
+			desc: "Supported versions which can't be clustered together",
+			systems: map[string]map[types.ServiceType]types.ServiceVersion{
+				"micro01": supported,
+				"micro02": supported,
+				"micro03": {types.MicroOVN: {Version: "24.09.1"}},
+			},
+			issues: []string{"MicroOVN runs different versions across the systems: 24.3 on micro01, micro02; 24.9 on micro03"},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		messages := []string{}
+		for _, issue := range CheckCompatibility(c.systems) {
+			messages = append(messages, issue.Message)
+		}
+
+		if c.issues == nil {
+			s.Empty(messages)
+		} else {
+			s.Equal(c.issues, messages)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// diskWipeSize is the size zeroed at the start and at the end of a disk when wiping it.
+// It covers the partition tables, as well as the LVM, BlueStore and filesystem labels.
+const diskWipeSize = 100 * 1024 * 1024
+
+// WipeDisk zeroes the start and the end of the given block device, so it is seen as blank when it is set up again.
+// The device is opened exclusively, so devices which are mounted or held by another device are refused.
+func WipeDisk(path string) error {
+	if !filepath.IsAbs(path) || !strings.HasPrefix(filepath.Clean(path), "/dev/") {
+		return fmt.Errorf("Invalid disk %q, must be a path in /dev", path)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("Failed to find disk %q: %w", path, err)
+	}
+
+	if info.Mode()&os.ModeDevice == 0 || info.Mode()&os.ModeCharDevice != 0 {
+		return fmt.Errorf("Disk %q is not a block device", path)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|unix.O_EXCL, 0)
+	if err != nil {
+		if errors.Is(err, unix.EBUSY) {
+			return fmt.Errorf("Disk %q is in use", path)
+		}
+
+		return fmt.Errorf("Failed to open disk %q: %w", path, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("Failed to get the size of disk %q: %w", path, err)
+	}
+
+	zeroes := make([]byte, min(diskWipeSize, size))
+	for _, offset := range []int64{0, max(size-diskWipeSize, 0)} {
+		_, err := f.WriteAt(zeroes, offset)
+		if err != nil {
+			return fmt.Errorf("Failed to wipe disk %q: %w", path, err)
+		}
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return fmt.Errorf("Failed to wipe disk %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// minimumFingerprintLength is the length of the shortened certificate fingerprints shown when joining systems.
+const minimumFingerprintLength = 12
+
+// fingerprintPattern matches a certificate fingerprint, or a prefix of it.
+var fingerprintPattern = regexp.MustCompile(`^[a-f0-9]+$`)
+
+// ExpectedMember is a system expected to be a member of MicroCloud, identified by either its address or its certificate fingerprint.
+type ExpectedMember struct {
+	// Name is the name of the system.
+	Name string
+
+	// Address is the MicroCloud address of the system.
+	Address string
+
+	// Fingerprint is the fingerprint of the certificate of the system, or a prefix of it.
+	Fingerprint string
+}
+
+// Matches returns whether the system with the given name, address and certificate fingerprint is the expected member.
+func (m ExpectedMember) Matches(name string, address string, fingerprint string) bool {
+	if m.Name != name {
+		return false
+	}
+
+	if m.Fingerprint != "" {
+		return strings.HasPrefix(fingerprint, m.Fingerprint)
+	}
+
+	return net.ParseIP(m.Address).Equal(net.ParseIP(address))
+}
+
+// ParseExpectedMembers parses expected members given as a comma separated list of <name>=<address or certificate fingerprint> pairs.
+func ParseExpectedMembers(value string) ([]ExpectedMember, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	members := []ExpectedMember{}
+	for _, entry := range strings.Split(value, ",") {
+		name, identity, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" || identity == "" {
+			return nil, fmt.Errorf("Invalid expected member %q, must be of the form <name>=<address or certificate fingerprint>", entry)
+		}
+
+		if slices.ContainsFunc(members, func(m ExpectedMember) bool { return m.Name == name }) {
+			return nil, fmt.Errorf("Expected member %q is given more than once", name)
+		}
+
+		member := ExpectedMember{Name: name}
+		if net.ParseIP(identity) != nil {
+			member.Address = identity
+		} else {
+			fingerprint := strings.ToLower(identity)
+			if !fingerprintPattern.MatchString(fingerprint) || len(fingerprint) < minimumFingerprintLength {
+				return nil, fmt.Errorf("Invalid identity %q of expected member %q, must be an IP address or at least %d characters of a certificate fingerprint", identity, name, minimumFingerprintLength)
+			}
+
+			member.Fingerprint = fingerprint
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// MissingMembers returns the names of the expected members which aren't among the given cluster member names, sorted.
+func MissingMembers(expected []ExpectedMember, clusterMembers []string) []string {
+	missing := []string{}
+	for _, member := range expected {
+		if !slices.Contains(clusterMembers, member.Name) {
+			missing = append(missing, member.Name)
+		}
+	}
+
+	slices.Sort(missing)
+
+	return missing
+}
+
+// RecordMissingMembers records the expected members which are missing from the cluster,
+// and returns the members which went missing and the ones which joined since the last record.
+func (s *Handler) RecordMissingMembers(missing []string) (newlyMissing []string, joined []string) {
+	s.expectedLock.Lock()
+	defer s.expectedLock.Unlock()
+
+	for _, name := range missing {
+		if !slices.Contains(s.missingMembers, name) {
+			newlyMissing = append(newlyMissing, name)
+		}
+	}
+
+	for _, name := range s.missingMembers {
+		if !slices.Contains(missing, name) {
+			joined = append(joined, name)
+		}
+	}
+
+	s.missingMembers = slices.Clone(missing)
+
+	return newlyMissing, joined
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type expectedMembersSuite struct {
+	suite.Suite
+}
+
+func TestExpectedMembersSuite(t *testing.T) {
+	suite.Run(t, new(expectedMembersSuite))
+}
+
+func (s *expectedMembersSuite) Test_parseExpectedMembers() {
+	cases := []struct {
+		desc    string
+		value   string
+		members []ExpectedMember
+		err     bool
+	}{
+		{
+			desc: "No expected members",
+		},
+		{
+			desc:    "Addresses and fingerprints",
+			value:   "micro01=10.0.0.1, micro02=fd42::2,micro03=0123456789AB",
+			members: []ExpectedMember{{Name: "micro01", Address: "10.0.0.1"}, {Name: "micro02", Address: "fd42::2"}, {Name: "micro03", Fingerprint: "0123456789ab"}},
+		},
+		{
+			desc:  "Missing identity",
+			value: "micro01",
+			err:   true,
+		},
+		{
+			desc:  "Short fingerprint",
+			value: "micro01=0123",
+			err:   true,
+		},
+		{
+			desc:  "Invalid fingerprint",
+			value: "micro01=micro01.example.com",
+			err:   true,
+		},
+		{
+			desc:  "Duplicate name",
+			value: "micro01=10.0.0.1,micro01=10.0.0.2",
+			err:   true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		members, err := ParseExpectedMembers(c.value)
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+			s.Equal(c.members, members)
+		}
+	}
+}
+
+func (s *expectedMembersSuite) Test_expectedMemberMatches() {
+	byAddress := ExpectedMember{Name: "micro01", Address: "10.0.0.1"}
+	s.True(byAddress.Matches("micro01", "10.0.0.1", "0123456789abcdef"))
+	s.False(byAddress.Matches("micro01", "10.0.0.2", "0123456789abcdef"))
+	s.False(byAddress.Matches("micro02", "10.0.0.1", "0123456789abcdef"))
+
+	byFingerprint := ExpectedMember{Name: "micro01", Fingerprint: "0123456789ab"}
+	s.True(byFingerprint.Matches("micro01", "10.0.0.2", "0123456789abcdef"))
+	s.False(byFingerprint.Matches("micro01", "10.0.0.1", "fedcba9876543210"))
+}
+
+func (s *expectedMembersSuite) Test_recordMissingMembers() {
+	expected := []ExpectedMember{{Name: "micro03"}, {Name: "micro02"}, {Name: "micro01"}}
+	sh := &Handler{}
+
+	missing := MissingMembers(expected, []string{"micro01"})
+	s.Equal([]string{"micro02", "micro03"}, missing)
+
+	newlyMissing, joined := sh.RecordMissingMembers(missing)
+	s.Equal([]string{"micro02", "micro03"}, newlyMissing)
+	s.Empty(joined)
+
+	newlyMissing, joined = sh.RecordMissingMembers(MissingMembers(expected, []string{"micro01", "micro02"}))
+	s.Empty(newlyMissing)
+	s.Equal([]string{"micro02"}, joined)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	lxdAPI "github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// Inspect collects the detailed state of the local cluster member with the given name.
+// Parts which can't be inspected, such as a service which is down, are recorded as errors instead of failing the inspection.
+func (sh *Handler) Inspect(ctx context.Context, name string) types.MemberInspection {
+	inspection := types.MemberInspection{
+		Name:         name,
+		Services:     sh.ServiceVersions(ctx),
+		Certificates: []types.CertificateInfo{},
+		OSDs:         []types.OSDState{},
+		Errors:       []string{},
+	}
+
+	cloud := sh.Services[types.MicroCloud].(*CloudService)
+	cert, err := cloud.ServerCert()
+	if err == nil {
+		var x509Cert *x509.Certificate
+		x509Cert, err = cert.PublicKeyX509()
+		if err == nil {
+			inspection.Certificates = append(inspection.Certificates, types.CertificateInfo{Service: types.MicroCloud, Fingerprint: cert.Fingerprint(), NotAfter: x509Cert.NotAfter})
+		}
+	}
+
+	if err != nil {
+		inspection.Errors = append(inspection.Errors, fmt.Sprintf("Failed to get the MicroCloud certificate: %v", err))
+	}
+
+	if sh.Services[types.LXD] != nil {
+		err := inspectLXD(ctx, sh.Services[types.LXD].(*LXDService), name, &inspection)
+		if err != nil {
+			inspection.Errors = append(inspection.Errors, err.Error())
+		}
+	}
+
+	if sh.Services[types.MicroCeph] != nil {
+		disks, err := sh.Services[types.MicroCeph].(*CephService).GetDisks(ctx, "", nil)
+		if err != nil {
+			inspection.Errors = append(inspection.Errors, fmt.Sprintf("Failed to get MicroCeph OSDs: %v", err))
+		} else {
+			running := runningOSDs("/proc")
+			for _, disk := range disks {
+				if disk.Location != name {
+					continue
+				}
+
+				inspection.OSDs = append(inspection.OSDs, types.OSDState{OSD: disk.OSD, Path: disk.Path, Running: running[disk.OSD]})
+			}
+		}
+	}
+
+	return inspection
+}
+
+// inspectLXD records the certificate, instance count and resource usage of the local LXD server in the inspection.
+func inspectLXD(ctx context.Context, lxd *LXDService, name string, inspection *types.MemberInspection) error {
+	client, err := lxd.Client(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to LXD: %w", err)
+	}
+
+	server, _, err := client.GetServer()
+	if err != nil {
+		return fmt.Errorf("Failed to get the LXD server: %w", err)
+	}
+
+	block, _ := pem.Decode([]byte(server.Environment.Certificate))
+	if block != nil {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			inspection.Certificates = append(inspection.Certificates, types.CertificateInfo{Service: types.LXD, Fingerprint: server.Environment.CertificateFingerprint, NotAfter: cert.NotAfter})
+		}
+	}
+
+	instances, err := client.GetInstancesAllProjects(lxdAPI.InstanceTypeAny)
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD instances: %w", err)
+	}
+
+	for _, instance := range instances {
+		// Instances of a standalone LXD server don't have a location.
+		if instance.Location == name || instance.Location == "" || instance.Location == "none" {
+			inspection.Instances++
+		}
+	}
+
+	resources, err := client.GetServerResources()
+	if err != nil {
+		return fmt.Errorf("Failed to get LXD resources: %w", err)
+	}
+
+	inspection.Resources = &types.MemberResources{
+		CPUs:        resources.CPU.Total,
+		MemoryTotal: resources.Memory.Total,
+		MemoryUsed:  resources.Memory.Used,
+	}
+
+	return nil
+}
+
+// runningOSDs returns the IDs of the ceph-osd daemons running on the system, by looking at the processes under the given proc directory.
+func runningOSDs(procDir string) map[int64]bool {
+	running := map[int64]bool{}
+
+	cmdlines, _ := filepath.Glob(filepath.Join(procDir, "*", "cmdline"))
+	for _, path := range cmdlines {
+		cmdline, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		id, ok := osdID(strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00"))
+		if ok {
+			running[id] = true
+		}
+	}
+
+	return running
+}
+
+// osdID returns the OSD ID of a ceph-osd command line, as set with the "--id" or "-i" flag.
+func osdID(args []string) (int64, bool) {
+	if len(args) == 0 || filepath.Base(args[0]) != "ceph-osd" {
+		return 0, false
+	}
+
+	for i, arg := range args[1:] {
+		var value string
+		switch {
+		case arg == "--id" || arg == "-i":
+			if i+2 >= len(args) {
+				return 0, false
+			}
+
+			value = args[i+2]
+		case strings.HasPrefix(arg, "--id="):
+			value = strings.TrimPrefix(arg, "--id=")
+		default:
+			continue
+		}
+
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		return id, true
+	}
+
+	return 0, false
+}
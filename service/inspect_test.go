@@ -0,0 +1,48 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type inspectSuite struct {
+	suite.Suite
+}
+
+func TestInspectSuite(t *testing.T) {
+	suite.Run(t, new(inspectSuite))
+}
+
+func (s *inspectSuite) Test_osdID() {
+	cases := []struct {
+		args     []string
+		expectID int64
+		expectOK bool
+	}{
+		{args: []string{"/snap/microceph/current/bin/ceph-osd", "--cluster", "ceph", "--id", "3"}, expectID: 3, expectOK: true},
+		{args: []string{"ceph-osd", "-f", "-i", "12"}, expectID: 12, expectOK: true},
+		{args: []string{"ceph-osd", "--id=7"}, expectID: 7, expectOK: true},
+		{args: []string{"ceph-osd", "--id"}},
+		{args: []string{"ceph-mon", "--id", "micro01"}},
+		{args: []string{""}},
+	}
+
+	for _, c := range cases {
+		id, ok := osdID(c.args)
+		s.Equal(c.expectOK, ok, c.args)
+		s.Equal(c.expectID, id, c.args)
+	}
+}
+
+func (s *inspectSuite) Test_runningOSDs() {
+	procDir := s.T().TempDir()
+	for pid, cmdline := range map[string]string{"100": "ceph-osd\x00--id\x001\x00", "200": "ceph-mon\x00--id\x00micro01\x00", "300": "ceph-osd\x00-i\x004\x00"} {
+		s.Require().NoError(os.Mkdir(filepath.Join(procDir, pid), 0755))
+		s.Require().NoError(os.WriteFile(filepath.Join(procDir, pid, "cmdline"), []byte(cmdline), 0644))
+	}
+
+	s.Equal(map[int64]bool{1: true, 4: true}, runningOSDs(procDir))
+}
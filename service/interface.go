@@ -27,6 +27,7 @@ type Service interface {
 	Port() int64
 	SetConfig(config map[string]string)
 	SupportsFeature(ctx context.Context, feature string) (bool, error)
+	GetExtensions(ctx context.Context) ([]string, error)
 	GetVersion(ctx context.Context) (string, error)
 	IsInitialized(ctx context.Context) (bool, error)
 }
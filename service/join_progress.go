@@ -0,0 +1,45 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// StartJoinProgress starts tracking the progress of the local system joining the given services, forgetting any earlier join.
+func (s *Handler) StartJoinProgress(name string, services []types.ServiceType) {
+	s.joinProgressLock.Lock()
+	defer s.joinProgressLock.Unlock()
+
+	now := time.Now().UTC()
+	s.joinProgress = make([]types.JoinState, 0, len(services))
+	for _, serviceType := range services {
+		s.joinProgress = append(s.joinProgress, types.JoinState{Member: name, Service: serviceType, Status: types.JoinStatePending, UpdatedAt: now})
+	}
+}
+
+// UpdateJoinProgress records the status of the local system joining the given service.
+func (s *Handler) UpdateJoinProgress(serviceType types.ServiceType, status string, joinErr error) {
+	s.joinProgressLock.Lock()
+	defer s.joinProgressLock.Unlock()
+
+	index := slices.IndexFunc(s.joinProgress, func(state types.JoinState) bool { return state.Service == serviceType })
+	if index < 0 {
+		return
+	}
+
+	s.joinProgress[index].Status = status
+	s.joinProgress[index].UpdatedAt = time.Now().UTC()
+	if joinErr != nil {
+		s.joinProgress[index].Error = joinErr.Error()
+	}
+}
+
+// JoinProgress returns the progress of the local system joining each service, since the last join started.
+func (s *Handler) JoinProgress() []types.JoinState {
+	s.joinProgressLock.Lock()
+	defer s.joinProgressLock.Unlock()
+
+	return slices.Clone(s.joinProgress)
+}
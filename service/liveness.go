@@ -0,0 +1,96 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// Heartbeat is the result of a heartbeat to another cluster member.
+type Heartbeat struct {
+	Name    string
+	Address string
+	Err     error
+}
+
+// nextMemberLiveness returns the state of a cluster member after the given heartbeat, based on its previous state if any.
+func nextMemberLiveness(previous *types.MemberLiveness, heartbeat Heartbeat, threshold int, now time.Time) types.MemberLiveness {
+	liveness := types.MemberLiveness{Name: heartbeat.Name, Address: heartbeat.Address}
+	if previous != nil {
+		liveness.Misses = previous.Misses
+		liveness.LastSeen = previous.LastSeen
+	}
+
+	if heartbeat.Err != nil {
+		liveness.Misses++
+		liveness.Error = heartbeat.Err.Error()
+	} else {
+		liveness.Misses = 0
+		liveness.LastSeen = now
+	}
+
+	liveness.Reachable = liveness.Misses < threshold
+	if previous != nil && previous.Reachable == liveness.Reachable {
+		liveness.Since = previous.Since
+	} else {
+		liveness.Since = now
+	}
+
+	return liveness
+}
+
+// RecordHeartbeats records a round of heartbeats to the other cluster members, and returns the members which became unreachable or reachable again.
+// Members missing from the round are no longer part of the cluster, and are forgotten.
+func (s *Handler) RecordHeartbeats(heartbeats []Heartbeat, threshold int) []types.MemberLiveness {
+	s.livenessLock.Lock()
+	defer s.livenessLock.Unlock()
+
+	now := time.Now()
+	liveness := make([]types.MemberLiveness, 0, len(heartbeats))
+	var changed []types.MemberLiveness
+	for _, heartbeat := range heartbeats {
+		var previous *types.MemberLiveness
+		index := slices.IndexFunc(s.liveness, func(l types.MemberLiveness) bool { return l.Name == heartbeat.Name })
+		if index >= 0 {
+			previous = &s.liveness[index]
+		}
+
+		member := nextMemberLiveness(previous, heartbeat, threshold, now)
+		liveness = append(liveness, member)
+
+		ctx := logger.Ctx{"name": member.Name, "address": member.Address, "misses": member.Misses}
+		if !member.Reachable && (previous == nil || previous.Reachable) {
+			ctx["error"] = member.Error
+			logger.Warn("Cluster member is unreachable", ctx)
+			changed = append(changed, member)
+		} else if member.Reachable && previous != nil && !previous.Reachable {
+			logger.Info("Cluster member is reachable again", ctx)
+			changed = append(changed, member)
+		}
+	}
+
+	slices.SortFunc(liveness, func(a types.MemberLiveness, b types.MemberLiveness) int { return strings.Compare(a.Name, b.Name) })
+	s.liveness = liveness
+
+	return changed
+}
+
+// MemberLiveness returns the state of the other cluster members as of the last round of heartbeats.
+func (s *Handler) MemberLiveness() []types.MemberLiveness {
+	s.livenessLock.Lock()
+	defer s.livenessLock.Unlock()
+
+	return slices.Clone(s.liveness)
+}
+
+// ResetMemberLiveness forgets the state of the other cluster members, while the daemon is not part of a cluster.
+func (s *Handler) ResetMemberLiveness() {
+	s.livenessLock.Lock()
+	defer s.livenessLock.Unlock()
+
+	s.liveness = nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type livenessSuite struct {
+	suite.Suite
+}
+
+func TestLivenessSuite(t *testing.T) {
+	suite.Run(t, new(livenessSuite))
+}
+
+func (s *livenessSuite) Test_nextMemberLiveness() {
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := first.Add(time.Minute)
+	missed := errors.New("connection refused")
+
+	cases := []struct {
+		desc      string
+		previous  *types.MemberLiveness
+		err       error
+		threshold int
+
+		expectReachable bool
+		expectMisses    int
+		expectLastSeen  time.Time
+		expectSince     time.Time
+	}{
+		{
+			desc:            "First heartbeat succeeds",
+			threshold:       3,
+			expectReachable: true,
+			expectLastSeen:  now,
+			expectSince:     now,
+		},
+		{
+			desc:            "First missed heartbeat below the threshold",
+			err:             missed,
+			threshold:       3,
+			expectReachable: true,
+			expectMisses:    1,
+			expectSince:     now,
+		},
+		{
+			desc:            "Missed heartbeat reaching the threshold",
+			previous:        &types.MemberLiveness{Reachable: true, Misses: 2, LastSeen: first, Since: first},
+			err:             missed,
+			threshold:       3,
+			expectReachable: false,
+			expectMisses:    3,
+			expectLastSeen:  first,
+			expectSince:     now,
+		},
+		{
+			desc:            "Missed heartbeat while unreachable",
+			previous:        &types.MemberLiveness{Reachable: false, Misses: 3, LastSeen: first, Since: first},
+			err:             missed,
+			threshold:       3,
+			expectReachable: false,
+			expectMisses:    4,
+			expectLastSeen:  first,
+			expectSince:     first,
+		},
+		{
+			desc:            "Heartbeat succeeds after being unreachable",
+			previous:        &types.MemberLiveness{Reachable: false, Misses: 4, LastSeen: first, Since: first},
+			threshold:       3,
+			expectReachable: true,
+			expectLastSeen:  now,
+			expectSince:     now,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		liveness := nextMemberLiveness(c.previous, Heartbeat{Name: "micro02", Address: "10.0.0.2", Err: c.err}, c.threshold, now)
+		s.Equal("micro02", liveness.Name)
+		s.Equal("10.0.0.2", liveness.Address)
+		s.Equal(c.expectReachable, liveness.Reachable)
+		s.Equal(c.expectMisses, liveness.Misses)
+		s.Equal(c.expectLastSeen, liveness.LastSeen)
+		s.Equal(c.expectSince, liveness.Since)
+		if c.err != nil {
+			s.Equal(c.err.Error(), liveness.Error)
+		} else {
+			s.Empty(liveness.Error)
+		}
+	}
+}
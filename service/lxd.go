@@ -31,6 +31,12 @@ type LXDService struct {
 	address string
 	port    int64
 	config  map[string]string
+
+	// names are the names of the storage pools and networks set up by MicroCloud.
+	names ResourceNames
+
+	// simulated services answer without connecting to LXD, see NewSimulatedHandler.
+	simulated bool
 }
 
 // NewLXDService creates a new LXD service with a client attached.
@@ -46,6 +52,7 @@ func NewLXDService(name string, addr string, cloudDir string) (*LXDService, erro
 		address: addr,
 		port:    LXDPort,
 		config:  make(map[string]string),
+		names:   DefaultResourceNames(),
 	}, nil
 }
 
@@ -60,6 +67,9 @@ func (s LXDService) Client(ctx context.Context) (lxd.InstanceServer, error) {
 		HTTPClient:    c.Client.Client,
 		SkipGetServer: true,
 		Proxy:         cloudClient.AuthProxy("", types.LXD),
+		TransportWrapper: func(t *http.Transport) lxd.HTTPTransporter {
+			return cloudClient.NewLogTransport(t)
+		},
 	})
 }
 
@@ -97,6 +107,9 @@ func (s LXDService) remoteClient(cert *x509.Certificate, address string, port in
 		TLSServerCert: microTypes.X509Certificate{Certificate: cert}.String(),
 		SkipGetServer: true,
 		Proxy:         cloudClient.AuthProxy("", types.LXD),
+		TransportWrapper: func(t *http.Transport) lxd.HTTPTransporter {
+			return cloudClient.NewLogTransport(t)
+		},
 	})
 	if err != nil {
 		return nil, err
@@ -118,11 +131,11 @@ func (s LXDService) Bootstrap(ctx context.Context) error {
 	}
 
 	// Prepare the update.
-	addr := util.CanonicalNetworkAddress(s.address, s.port)
+	coreAddress, clusterAddress := LXDAddresses(s.address, s.config[LXDAddressConfig], s.config[LXDClusterAddressConfig])
 
 	newServer := currentServer.Writable()
-	newServer.Config["core.https_address"] = "[::]:8443"
-	newServer.Config["cluster.https_address"] = addr
+	newServer.Config["core.https_address"] = coreAddress
+	newServer.Config["cluster.https_address"] = clusterAddress
 	newServer.Config["user.microcloud"] = version.RawVersion
 	if client.HasExtension("instances_migration_stateful") {
 		newServer.Config["instances.migration.stateful"] = "true"
@@ -184,7 +197,8 @@ func (s LXDService) Bootstrap(ctx context.Context) error {
 
 // Join joins a cluster with the given token.
 func (s LXDService) Join(ctx context.Context, joinConfig JoinConfig) error {
-	config, err := s.configFromToken(joinConfig.Token)
+	coreAddress, clusterAddress := LXDAddresses(s.address, joinConfig.LXDAddress, joinConfig.LXDClusterAddress)
+	config, err := s.configFromToken(joinConfig.Token, clusterAddress)
 	if err != nil {
 		return err
 	}
@@ -205,15 +219,14 @@ func (s LXDService) Join(ctx context.Context, joinConfig JoinConfig) error {
 		return fmt.Errorf("Failed to configure cluster: %w", err)
 	}
 
-	// Set the local server's core.https_address to be consistent with the
-	// bootstrap member's wildcard
+	// Set the local server's core.https_address, which isn't part of the cluster join.
 	currentServer, etag, err := client.GetServer()
 	if err != nil {
 		return fmt.Errorf("Failed to retrieve LXD config: %w", err)
 	}
 
 	newServer := currentServer.Writable()
-	newServer.Config["core.https_address"] = "[::]:8443"
+	newServer.Config["core.https_address"] = coreAddress
 
 	err = client.UpdateServer(newServer, etag)
 	if err != nil {
@@ -384,8 +397,22 @@ func (s *LXDService) SetConfig(config map[string]string) {
 	}
 }
 
+// SetResourceNames sets the names of the storage pools and networks set up by MicroCloud.
+func (s *LXDService) SetResourceNames(names ResourceNames) {
+	s.names = names
+}
+
+// ResourceNames returns the names of the storage pools and networks set up by MicroCloud.
+func (s LXDService) ResourceNames() ResourceNames {
+	return s.names
+}
+
 // HasExtension checks if the server supports the API extension.
 func (s *LXDService) HasExtension(ctx context.Context, target string, address string, cert *x509.Certificate, apiExtension string) (bool, error) {
+	if s.simulated {
+		return true, nil
+	}
+
 	var err error
 	var client lxd.InstanceServer
 	if s.Name() == target {
@@ -415,6 +442,10 @@ func (s *LXDService) HasExtension(ctx context.Context, target string, address st
 // As we cannot guarantee that LXD is available on this machine, the request is
 // forwarded through MicroCloud on via the ListenPort argument.
 func (s *LXDService) GetResources(ctx context.Context, target string, address string, cert *x509.Certificate) (*api.Resources, error) {
+	if s.simulated {
+		return SimulatedSystems([]string{target})[target].Resources, nil
+	}
+
 	var err error
 	var client lxd.InstanceServer
 	if s.Name() == target {
@@ -459,6 +490,77 @@ func (s LXDService) GetStoragePools(ctx context.Context, name string, address st
 	return poolMap, nil
 }
 
+// UnavailableStoragePools returns the storage pools set up by MicroCloud which LXD couldn't set up on this system yet.
+func (s LXDService) UnavailableStoragePools(ctx context.Context) ([]string, error) {
+	c, err := s.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the member specific view of a storage pool reports whether it is available on this system.
+	pools, err := c.UseTarget(s.Name()).GetStoragePools()
+	if err != nil {
+		return nil, err
+	}
+
+	unavailable := []string{}
+	for _, pool := range pools {
+		if !slices.Contains([]string{s.names.LocalPool, s.names.RemotePool, s.names.RemoteFSPool}, pool.Name) {
+			continue
+		}
+
+		if pool.Status == api.StoragePoolStatusUnvailable {
+			unavailable = append(unavailable, pool.Name)
+		}
+	}
+
+	return unavailable, nil
+}
+
+// UnavailableNetworks returns the networks set up by MicroCloud which LXD couldn't set up on this system yet.
+func (s LXDService) UnavailableNetworks(ctx context.Context) ([]string, error) {
+	c, err := s.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the member specific view of a network reports whether it is available on this system.
+	networks, err := c.UseTarget(s.Name()).GetNetworks()
+	if err != nil {
+		return nil, err
+	}
+
+	unavailable := []string{}
+	for _, network := range networks {
+		if !slices.Contains([]string{s.names.UplinkNetwork, s.names.OVNNetwork, s.names.FanNetwork}, network.Name) {
+			continue
+		}
+
+		if network.Status == api.NetworkStatusUnavailable {
+			unavailable = append(unavailable, network.Name)
+		}
+	}
+
+	return unavailable, nil
+}
+
+// RenameNetwork renames the given network on the system with the given name and address.
+func (s LXDService) RenameNetwork(ctx context.Context, name string, address string, cert *x509.Certificate, network string, newName string) error {
+	var err error
+	var client lxd.InstanceServer
+	if name == s.Name() {
+		client, err = s.Client(ctx)
+	} else {
+		client, err = s.remoteClient(cert, address, CloudPort)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return client.RenameNetwork(network, api.NetworkPost{Name: newName})
+}
+
 // GetConfig returns the member-specific and cluster-wide configurations of LXD.
 // If LXD is not clustered, it just returns the member-specific configuration.
 func (s LXDService) GetConfig(ctx context.Context, clustered bool, name string, address string, cert *x509.Certificate) (localConfig map[string]any, globalConfig map[string]any, err error) {
@@ -809,3 +911,18 @@ func (s LXDService) SupportsFeature(ctx context.Context, feature string) (bool,
 
 	return slices.Contains(server.APIExtensions, feature), nil
 }
+
+// GetExtensions returns the API extensions of the service.
+func (s LXDService) GetExtensions(ctx context.Context) ([]string, error) {
+	c, err := s.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	server, _, err := c.GetServer()
+	if err != nil {
+		return nil, err
+	}
+
+	return server.APIExtensions, nil
+}
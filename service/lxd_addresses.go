@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/canonical/lxd/lxd/util"
+)
+
+const (
+	// LXDAddressConfig is the config key of the address LXD listens on for its API, as set with SetConfig.
+	LXDAddressConfig = "core.https_address"
+
+	// LXDClusterAddressConfig is the config key of the address LXD listens on for the cluster traffic, as set with SetConfig.
+	LXDClusterAddressConfig = "cluster.https_address"
+
+	// DefaultLXDAddress is the address LXD listens on for its API unless another one is given, covering all addresses of the system.
+	DefaultLXDAddress = "[::]"
+)
+
+// LXDAddresses returns the core.https_address and cluster.https_address of LXD on a system with the given MicroCloud address.
+// Unless other addresses are given, LXD listens on all addresses for its API, and on the MicroCloud address for the cluster traffic.
+func LXDAddresses(address string, coreAddress string, clusterAddress string) (string, string) {
+	if coreAddress == "" {
+		coreAddress = DefaultLXDAddress
+	}
+
+	if clusterAddress == "" {
+		clusterAddress = address
+	}
+
+	return util.CanonicalNetworkAddress(coreAddress, LXDPort), util.CanonicalNetworkAddress(clusterAddress, LXDPort)
+}
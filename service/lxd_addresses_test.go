@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type lxdAddressesSuite struct {
+	suite.Suite
+}
+
+func TestLXDAddressesSuite(t *testing.T) {
+	suite.Run(t, new(lxdAddressesSuite))
+}
+
+func (s *lxdAddressesSuite) Test_LXDAddresses() {
+	cases := []struct {
+		desc           string
+		address        string
+		coreAddress    string
+		clusterAddress string
+		expectCore     string
+		expectCluster  string
+	}{
+		{
+			desc:          "Default addresses",
+			address:       "10.0.0.1",
+			expectCore:    "[::]:8443",
+			expectCluster: "10.0.0.1:8443",
+		},
+		{
+			desc:           "Given addresses",
+			address:        "10.0.0.1",
+			coreAddress:    "192.168.1.1",
+			clusterAddress: "10.1.0.1",
+			expectCore:     "192.168.1.1:8443",
+			expectCluster:  "10.1.0.1:8443",
+		},
+		{
+			desc:           "Given addresses with ports",
+			address:        "fd42::1",
+			coreAddress:    "0.0.0.0:443",
+			clusterAddress: "[fd43::1]:9443",
+			expectCore:     "0.0.0.0:443",
+			expectCluster:  "[fd43::1]:9443",
+		},
+		{
+			desc:          "IPv6 wildcard address",
+			address:       "fd42::1",
+			coreAddress:   "::",
+			expectCore:    "[::]:8443",
+			expectCluster: "[fd42::1]:8443",
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		coreAddress, clusterAddress := LXDAddresses(c.address, c.coreAddress, c.clusterAddress)
+		s.Equal(c.expectCore, coreAddress)
+		s.Equal(c.expectCluster, clusterAddress)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/validate"
 )
 
 const (
@@ -49,7 +51,7 @@ const (
 // DefaultPendingFanNetwork returns the default Ubuntu Fan network configuration when
 // creating a pending network on a specific cluster member target.
 func (s LXDService) DefaultPendingFanNetwork() api.NetworksPost {
-	return api.NetworksPost{Name: DefaultFANNetwork, Type: "bridge"}
+	return api.NetworksPost{Name: s.names.FanNetwork, Type: "bridge"}
 }
 
 // FanNetworkUsable checks if the current host is capable of using a Fan network.
@@ -110,7 +112,7 @@ func (s LXDService) DefaultFanNetwork() (api.NetworksPost, error) {
 			},
 			Description: "Default Ubuntu fan powered bridge",
 		},
-		Name: DefaultFANNetwork,
+		Name: s.names.FanNetwork,
 		Type: "bridge",
 	}, nil
 }
@@ -120,7 +122,7 @@ func (s LXDService) DefaultFanNetwork() (api.NetworksPost, error) {
 func (s LXDService) DefaultPendingOVNNetwork(parent string) api.NetworksPost {
 	return api.NetworksPost{
 		NetworkPut: api.NetworkPut{Config: map[string]string{"parent": parent}},
-		Name:       DefaultUplinkNetwork,
+		Name:       s.names.UplinkNetwork,
 		Type:       "physical",
 	}
 }
@@ -130,7 +132,7 @@ func (s LXDService) DefaultPendingOVNNetwork(parent string) api.NetworksPost {
 func (s LXDService) DefaultOVNNetworkJoinConfig(parent string) api.ClusterMemberConfigKey {
 	return api.ClusterMemberConfigKey{
 		Entity: "network",
-		Name:   DefaultUplinkNetwork,
+		Name:   s.names.UplinkNetwork,
 		Key:    "parent",
 		Value:  parent,
 	}
@@ -140,12 +142,14 @@ func (s LXDService) DefaultOVNNetworkJoinConfig(parent string) api.ClusterMember
 // creating the finalized network.
 // Returns both the finalized uplink configuration as the first argument,
 // and the default OVN network configuration as the second argument.
-func (s LXDService) DefaultOVNNetwork(ipv4Gateway string, ipv4Range string, ipv6Gateway string, dnsServers string) (api.NetworksPost, api.NetworksPost) {
+// The IPv6 routes are the prefixes delegated to the uplink, from which OVN networks can be given routed subnets instead of NAT.
+// The DNS search domains are given to the instances on the OVN network.
+func (s LXDService) DefaultOVNNetwork(ipv4Gateway string, ipv4Range string, ipv6Gateway string, ipv6Routes string, dnsServers string, dnsSearch string) (api.NetworksPost, api.NetworksPost) {
 	finalUplinkCfg := api.NetworksPost{
 		NetworkPut: api.NetworkPut{
 			Config:      map[string]string{},
 			Description: "Uplink for OVN networks"},
-		Name: DefaultUplinkNetwork,
+		Name: s.names.UplinkNetwork,
 		Type: "physical",
 	}
 
@@ -158,19 +162,44 @@ func (s LXDService) DefaultOVNNetwork(ipv4Gateway string, ipv4Range string, ipv6
 		finalUplinkCfg.Config["ipv6.gateway"] = ipv6Gateway
 	}
 
+	if ipv6Routes != "" {
+		finalUplinkCfg.Config["ipv6.routes"] = ipv6Routes
+	}
+
 	if dnsServers != "" {
 		finalUplinkCfg.Config["dns.nameservers"] = dnsServers
 	}
 
 	ovnNetwork := api.NetworksPost{
-		NetworkPut: api.NetworkPut{Config: map[string]string{"network": DefaultUplinkNetwork}, Description: "Default OVN network"},
-		Name:       DefaultOVNNetwork,
+		NetworkPut: api.NetworkPut{Config: map[string]string{"network": s.names.UplinkNetwork}, Description: "Default OVN network"},
+		Name:       s.names.OVNNetwork,
 		Type:       "ovn",
 	}
 
+	if dnsSearch != "" {
+		ovnNetwork.Config["dns.search"] = dnsSearch
+	}
+
 	return finalUplinkCfg, ovnNetwork
 }
 
+// ValidateDNSDomain checks the given value is a valid DNS domain name, such as a search domain.
+func ValidateDNSDomain(domain string) error {
+	domain = strings.TrimSuffix(domain, ".")
+	if len(domain) < 1 || len(domain) > 253 {
+		return errors.New("Domain must be 1-253 characters long")
+	}
+
+	for _, label := range strings.Split(domain, ".") {
+		err := validate.IsHostname(label)
+		if err != nil {
+			return fmt.Errorf("Invalid label %q: %w", label, err)
+		}
+	}
+
+	return nil
+}
+
 // DefaultPendingZFSStoragePool returns the default local storage configuration when
 // creating a pending pool on a specific cluster member target.
 func (s LXDService) DefaultPendingZFSStoragePool(wipe bool, path string) api.StoragePoolsPost {
@@ -180,7 +209,7 @@ func (s LXDService) DefaultPendingZFSStoragePool(wipe bool, path string) api.Sto
 	}
 
 	return api.StoragePoolsPost{
-		Name:   DefaultZFSPool,
+		Name:   s.names.LocalPool,
 		Driver: "zfs",
 		StoragePoolPut: api.StoragePoolPut{
 			Config:      cfg,
@@ -193,7 +222,7 @@ func (s LXDService) DefaultPendingZFSStoragePool(wipe bool, path string) api.Sto
 // creating the finalized pool.
 func (s LXDService) DefaultZFSStoragePool() api.StoragePoolsPost {
 	return api.StoragePoolsPost{
-		Name:   DefaultZFSPool,
+		Name:   s.names.LocalPool,
 		Driver: "zfs",
 		StoragePoolPut: api.StoragePoolPut{
 			Description: "Local storage on ZFS",
@@ -206,14 +235,14 @@ func (s LXDService) DefaultZFSStoragePool() api.StoragePoolsPost {
 func (s LXDService) DefaultZFSStoragePoolJoinConfig(wipe bool, path string) []api.ClusterMemberConfigKey {
 	wipeDisk := api.ClusterMemberConfigKey{
 		Entity: "storage-pool",
-		Name:   DefaultZFSPool,
+		Name:   s.names.LocalPool,
 		Key:    "source.wipe",
 		Value:  "true",
 	}
 
 	sourceTemplate := api.ClusterMemberConfigKey{
 		Entity: "storage-pool",
-		Name:   DefaultZFSPool,
+		Name:   s.names.LocalPool,
 		Key:    "source",
 	}
 
@@ -230,7 +259,7 @@ func (s LXDService) DefaultZFSStoragePoolJoinConfig(wipe bool, path string) []ap
 // creating a pending pool on a specific cluster member target.
 func (s LXDService) DefaultPendingCephStoragePool() api.StoragePoolsPost {
 	return api.StoragePoolsPost{
-		Name:   DefaultCephPool,
+		Name:   s.names.RemotePool,
 		Driver: "ceph",
 		StoragePoolPut: api.StoragePoolPut{
 			Config: map[string]string{
@@ -244,7 +273,7 @@ func (s LXDService) DefaultPendingCephStoragePool() api.StoragePoolsPost {
 // creating the finalized pool.
 func (s LXDService) DefaultCephStoragePool() api.StoragePoolsPost {
 	return api.StoragePoolsPost{
-		Name:   DefaultCephPool,
+		Name:   s.names.RemotePool,
 		Driver: "ceph",
 		StoragePoolPut: api.StoragePoolPut{
 			Config: map[string]string{
@@ -261,17 +290,52 @@ func (s LXDService) DefaultCephStoragePool() api.StoragePoolsPost {
 func (s LXDService) DefaultCephStoragePoolJoinConfig() api.ClusterMemberConfigKey {
 	return api.ClusterMemberConfigKey{
 		Entity: "storage-pool",
-		Name:   DefaultCephPool,
+		Name:   s.names.RemotePool,
 		Key:    "source",
 		Value:  DefaultCephOSDPool,
 	}
 }
 
+// PendingCephTierStoragePool returns the storage configuration of the given Ceph tier when
+// creating a pending pool on a specific cluster member target.
+func (s LXDService) PendingCephTierStoragePool(tier CephTier) api.StoragePoolsPost {
+	return api.StoragePoolsPost{
+		Name:   tier.Name,
+		Driver: "ceph",
+		StoragePoolPut: api.StoragePoolPut{
+			Config: map[string]string{
+				"source": tier.OSDPool(),
+			},
+		},
+	}
+}
+
+// CephTierStoragePool returns the storage configuration of the given Ceph tier when
+// creating the finalized pool.
+func (s LXDService) CephTierStoragePool(tier CephTier) api.StoragePoolsPost {
+	pool := s.DefaultCephStoragePool()
+	pool.Name = tier.Name
+	pool.Description = fmt.Sprintf("Distributed storage on Ceph (%s tier)", tier.DeviceClass)
+
+	return pool
+}
+
+// CephTierStoragePoolJoinConfig returns the storage configuration of the given Ceph tier when
+// joining an existing cluster.
+func (s LXDService) CephTierStoragePoolJoinConfig(tier CephTier) api.ClusterMemberConfigKey {
+	return api.ClusterMemberConfigKey{
+		Entity: "storage-pool",
+		Name:   tier.Name,
+		Key:    "source",
+		Value:  tier.OSDPool(),
+	}
+}
+
 // DefaultPendingCephFSStoragePool returns the default cephfs storage configuration when
 // creating a pending pool on a specific cluster member target.
 func (s LXDService) DefaultPendingCephFSStoragePool() api.StoragePoolsPost {
 	return api.StoragePoolsPost{
-		Name:   DefaultCephFSPool,
+		Name:   s.names.RemoteFSPool,
 		Driver: "cephfs",
 		StoragePoolPut: api.StoragePoolPut{
 			Config: map[string]string{
@@ -285,7 +349,7 @@ func (s LXDService) DefaultPendingCephFSStoragePool() api.StoragePoolsPost {
 // creating the finalized pool.
 func (s LXDService) DefaultCephFSStoragePool() api.StoragePoolsPost {
 	return api.StoragePoolsPost{
-		Name:   DefaultCephFSPool,
+		Name:   s.names.RemoteFSPool,
 		Driver: "cephfs",
 		StoragePoolPut: api.StoragePoolPut{
 			Config: map[string]string{
@@ -303,7 +367,7 @@ func (s LXDService) DefaultCephFSStoragePool() api.StoragePoolsPost {
 func (s LXDService) DefaultCephFSStoragePoolJoinConfig() api.ClusterMemberConfigKey {
 	return api.ClusterMemberConfigKey{
 		Entity: "storage-pool",
-		Name:   "remote-fs",
+		Name:   s.names.RemoteFSPool,
 		Key:    "source",
 		Value:  DefaultCephFSOSDPool,
 	}
@@ -13,7 +13,7 @@ import (
 	"github.com/canonical/lxd/shared/version"
 )
 
-func (s *LXDService) configFromToken(token string) (*api.ClusterPut, error) {
+func (s *LXDService) configFromToken(token string, serverAddress string) (*api.ClusterPut, error) {
 	joinToken, err := shared.JoinTokenDecode(token)
 	if err != nil {
 		return nil, fmt.Errorf("Invalid cluster join token: %w", err)
@@ -21,7 +21,7 @@ func (s *LXDService) configFromToken(token string) (*api.ClusterPut, error) {
 
 	config := &api.ClusterPut{
 		Cluster:       api.Cluster{ServerName: s.name, Enabled: true},
-		ServerAddress: util.CanonicalNetworkAddress(s.address, s.port),
+		ServerAddress: serverAddress,
 		ClusterToken:  token,
 	}
 
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// maintenanceDays are the abbreviated names of the week days in maintenance windows, starting on Sunday like time.Weekday.
+var maintenanceDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// MaintenanceWindow is a daily or weekly time span, in UTC, during which automated operations may run.
+type MaintenanceWindow struct {
+	// Days are the week days the window starts on, every day if empty.
+	Days []time.Weekday
+
+	// Start is the time since midnight at which the window opens.
+	Start time.Duration
+
+	// End is the time since midnight at which the window closes, on the next day if not after Start.
+	End time.Duration
+}
+
+// Contains returns whether the given time falls within the window.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := t.Sub(midnight)
+
+	// A window reaching past midnight opened on the day before.
+	if w.End <= w.Start && offset < w.End {
+		return w.startsOn(midnight.AddDate(0, 0, -1).Weekday())
+	}
+
+	if offset >= w.Start && (w.End <= w.Start || offset < w.End) {
+		return w.startsOn(t.Weekday())
+	}
+
+	return false
+}
+
+// startsOn returns whether the window opens on the given week day.
+func (w MaintenanceWindow) startsOn(day time.Weekday) bool {
+	return len(w.Days) == 0 || slices.Contains(w.Days, day)
+}
+
+// ParseMaintenanceWindows parses comma separated [<day>[-<day>] ]<HH:MM>-<HH:MM> maintenance windows, in UTC.
+// Windows without days open every day, and windows ending before they start close on the next day.
+func ParseMaintenanceWindows(value string) ([]MaintenanceWindow, error) {
+	windows := []MaintenanceWindow{}
+	for _, entry := range shared.SplitNTrimSpace(value, ",", -1, true) {
+		days, span, ok := strings.Cut(entry, " ")
+		if !ok {
+			span = days
+			days = ""
+		}
+
+		window := MaintenanceWindow{}
+		if days != "" {
+			var err error
+			window.Days, err = parseMaintenanceDays(days)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid maintenance window %q: %w", entry, err)
+			}
+		}
+
+		start, end, ok := strings.Cut(strings.TrimSpace(span), "-")
+		if !ok {
+			return nil, fmt.Errorf("Invalid maintenance window %q: Must be [<day>[-<day>] ]<HH:MM>-<HH:MM>", entry)
+		}
+
+		var err error
+		window.Start, err = parseMaintenanceTime(start)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid maintenance window %q: %w", entry, err)
+		}
+
+		window.End, err = parseMaintenanceTime(end)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid maintenance window %q: %w", entry, err)
+		}
+
+		if window.Start == window.End {
+			return nil, fmt.Errorf("Invalid maintenance window %q: Must not start and end at the same time", entry)
+		}
+
+		windows = append(windows, window)
+	}
+
+	return windows, nil
+}
+
+// parseMaintenanceDays parses a week day, or an inclusive range of week days which may wrap around the end of the week.
+func parseMaintenanceDays(value string) ([]time.Weekday, error) {
+	first, last, ok := strings.Cut(strings.ToLower(value), "-")
+	if !ok {
+		last = first
+	}
+
+	start := slices.Index(maintenanceDays, first)
+	end := slices.Index(maintenanceDays, last)
+	if start < 0 || end < 0 {
+		return nil, fmt.Errorf("Days must be one of %s, or a range of them", strings.Join(maintenanceDays, ", "))
+	}
+
+	days := []time.Weekday{time.Weekday(start)}
+	for day := start; day != end; {
+		day = (day + 1) % len(maintenanceDays)
+		days = append(days, time.Weekday(day))
+	}
+
+	return days, nil
+}
+
+// parseMaintenanceTime parses a HH:MM time of the day as the time since midnight.
+func parseMaintenanceTime(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("Time %q must be HH:MM", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// InMaintenanceWindow returns whether automated operations may run at the given time under the configured maintenance windows.
+// They may always run if no maintenance window is configured.
+func InMaintenanceWindow(value string, t time.Time) (bool, error) {
+	windows, err := ParseMaintenanceWindows(value)
+	if err != nil {
+		return false, err
+	}
+
+	if len(windows) == 0 {
+		return true, nil
+	}
+
+	return slices.ContainsFunc(windows, func(w MaintenanceWindow) bool { return w.Contains(t) }), nil
+}
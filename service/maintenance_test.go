@@ -0,0 +1,112 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type maintenanceSuite struct {
+	suite.Suite
+}
+
+func TestMaintenanceSuite(t *testing.T) {
+	suite.Run(t, new(maintenanceSuite))
+}
+
+func (s *maintenanceSuite) Test_ParseMaintenanceWindows() {
+	windows, err := ParseMaintenanceWindows("02:00-04:30, sat 22:00-06:00, fri-mon 12:00-13:00")
+	s.NoError(err)
+	s.Equal([]MaintenanceWindow{
+		{Start: 2 * time.Hour, End: 4*time.Hour + 30*time.Minute},
+		{Days: []time.Weekday{time.Saturday}, Start: 22 * time.Hour, End: 6 * time.Hour},
+		{Days: []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}, Start: 12 * time.Hour, End: 13 * time.Hour},
+	}, windows)
+
+	windows, err = ParseMaintenanceWindows("")
+	s.NoError(err)
+	s.Empty(windows)
+
+	for _, value := range []string{"02:00", "sunday 02:00-04:00", "mon-xyz 02:00-04:00", "2am-4am", "25:00-04:00", "02:00-02:00"} {
+		_, err := ParseMaintenanceWindows(value)
+		s.Error(err, value)
+	}
+}
+
+func (s *maintenanceSuite) Test_InMaintenanceWindow() {
+	// 2024-01-06 is a Saturday.
+	saturday := time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc    string
+		windows string
+		at      time.Time
+		open    bool
+	}{
+		{
+			desc: "No window",
+			at:   saturday.Add(15 * time.Hour),
+			open: true,
+		},
+		{
+			desc:    "Within a daily window",
+			windows: "02:00-04:00",
+			at:      saturday.Add(3 * time.Hour),
+			open:    true,
+		},
+		{
+			desc:    "At the end of a daily window",
+			windows: "02:00-04:00",
+			at:      saturday.Add(4 * time.Hour),
+		},
+		{
+			desc:    "Within a weekly window",
+			windows: "sat 02:00-04:00",
+			at:      saturday.Add(2 * time.Hour),
+			open:    true,
+		},
+		{
+			desc:    "On another day than a weekly window",
+			windows: "sun 02:00-04:00",
+			at:      saturday.Add(3 * time.Hour),
+		},
+		{
+			desc:    "Past midnight of a window opened on the day before",
+			windows: "fri 22:00-06:00",
+			at:      saturday.Add(5 * time.Hour),
+			open:    true,
+		},
+		{
+			desc:    "Past midnight of a window opened on another day",
+			windows: "sat 22:00-06:00",
+			at:      saturday.Add(5 * time.Hour),
+		},
+		{
+			desc:    "Window ending at midnight",
+			windows: "sat 22:00-00:00",
+			at:      saturday.Add(23 * time.Hour),
+			open:    true,
+		},
+		{
+			desc:    "Second of several windows",
+			windows: "01:00-02:00,sat 14:00-16:00",
+			at:      saturday.Add(15 * time.Hour),
+			open:    true,
+		},
+		{
+			desc:    "Converted to UTC",
+			windows: "02:00-04:00",
+			at:      saturday.Add(3 * time.Hour).In(time.FixedZone("UTC+5", 5*60*60)),
+			open:    true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		open, err := InMaintenanceWindow(c.windows, c.at)
+		s.NoError(err)
+		s.Equal(c.open, open)
+	}
+}
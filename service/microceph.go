@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,6 +40,8 @@ func NewCephService(name string, addr string, cloudDir string) (*CephService, er
 			r.URL.Path = "/1.0/services/microceph" + r.URL.Path
 		}
 
+		cloudClient.LogRequest(r)
+
 		return shared.ProxyFromEnvironment(r)
 	}
 
@@ -75,6 +78,11 @@ func (s CephService) Client(target string) (*client.Client, error) {
 	return c, nil
 }
 
+// Ready waits until the MicroCeph daemon is ready to process requests.
+func (s CephService) Ready(ctx context.Context) error {
+	return s.m.Ready(ctx)
+}
+
 // Bootstrap bootstraps the MicroCeph daemon on the default port.
 func (s CephService) Bootstrap(ctx context.Context) error {
 	err := s.m.NewCluster(ctx, s.name, util.CanonicalNetworkAddress(s.address, s.port), s.config)
@@ -158,6 +166,26 @@ func (s CephService) AddDisk(ctx context.Context, data cephTypes.DisksPost, targ
 	return response, nil
 }
 
+// RemoveDisk requests Ceph removes the OSD of the given disk, even if this leaves the cluster without enough OSDs to hold its data.
+func (s CephService) RemoveDisk(ctx context.Context, disk cephTypes.Disk) error {
+	c, err := s.Client(disk.Location)
+	if err != nil {
+		return err
+	}
+
+	// Removing an OSD waits for its data to be moved elsewhere, so allow the request as much time as adding disks.
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	data := cephTypes.DisksDelete{OSD: disk.OSD, BypassSafety: true, Timeout: 300}
+	err = c.Query(ctx, "DELETE", types.APIVersion, &api.NewURL().Path("disks", strconv.FormatInt(disk.OSD, 10)).URL, data, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to remove OSD %d: %w", disk.OSD, err)
+	}
+
+	return nil
+}
+
 // GetServices returns the list of configured ceph services.
 func (s CephService) GetServices(ctx context.Context, target string) (cephTypes.Services, error) {
 	c, err := s.Client(target)
@@ -363,6 +391,16 @@ func (s CephService) ClusterConfig(ctx context.Context, targetAddress string, ce
 	return configs, nil
 }
 
+// ResetClusterMember clears the state of the local cluster member, and restarts the service uninitialized.
+func (s CephService) ResetClusterMember(ctx context.Context) error {
+	c, err := s.m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return cloudClient.ResetClusterMember(ctx, c, s.name)
+}
+
 // Type returns the type of Service.
 func (s CephService) Type() types.ServiceType {
 	return types.MicroCeph
@@ -446,3 +484,13 @@ func (s *CephService) SupportsFeature(ctx context.Context, feature string) (bool
 
 	return server.Extensions.HasExtension(feature), nil
 }
+
+// GetExtensions returns the API extensions of the service.
+func (s *CephService) GetExtensions(ctx context.Context) ([]string, error) {
+	server, err := s.m.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MicroCeph server status while checking for extensions: %v", err)
+	}
+
+	return []string(server.Extensions), nil
+}
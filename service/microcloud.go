@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/canonical/lxd/lxd/util"
@@ -37,6 +38,10 @@ type JoinConfig struct {
 	LXDConfig  []api.ClusterMemberConfigKey
 	CephConfig []cephTypes.DisksPost
 	OVNConfig  map[string]string
+
+	// LXDAddress and LXDClusterAddress are the addresses LXD listens on for its API and for the cluster traffic, the defaults of LXDAddresses if empty.
+	LXDAddress        string
+	LXDClusterAddress string
 }
 
 // Status represents information about a cluster member.
@@ -47,7 +52,17 @@ type Status struct {
 
 // NewCloudService creates a new MicroCloud service with a client attached.
 func NewCloudService(name string, addr string, dir string) (*CloudService, error) {
-	client, err := microcluster.App(microcluster.Args{StateDir: dir})
+	// Log all requests for debugging, while keeping the default proxy behaviour of remote and unix socket clients.
+	proxy := func(r *http.Request) (*url.URL, error) {
+		cloudClient.LogRequest(r)
+		if r.URL.Scheme != "https" {
+			return nil, nil
+		}
+
+		return shared.ProxyFromEnvironment(r)
+	}
+
+	client, err := microcluster.App(microcluster.Args{StateDir: dir, Proxy: proxy})
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +81,11 @@ func (s *CloudService) StartCloud(ctx context.Context, args microcluster.DaemonA
 	return s.client.Start(ctx, args)
 }
 
+// StateDir returns the state directory of the MicroCloud daemon.
+func (s CloudService) StateDir() string {
+	return s.client.FileSystem.StateDir()
+}
+
 // Client returns a client to the MicroCloud unix socket.
 func (s CloudService) Client() (*microClient.Client, error) {
 	return s.client.LocalClient()
@@ -245,6 +265,81 @@ func (s CloudService) RemoteStatus(ctx context.Context, cert *x509.Certificate,
 	return &status, nil
 }
 
+// RemotePlatform returns the platform a remote system runs in.
+// Returns nil if the remote system runs a MicroCloud which can't report its platform.
+func (s CloudService) RemotePlatform(ctx context.Context, cert *x509.Certificate, address string) (*types.Platform, error) {
+	client, err := s.remoteClient(cert, address)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err = cloudClient.UseAuthProxy(client, types.MicroCloud, cloudClient.AuthConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	platform, err := cloudClient.GetPlatform(ctx, client)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return platform, nil
+}
+
+// RemoteSystemDisks returns the disks holding the operating system of a remote system.
+// Returns nil if the remote system runs a MicroCloud which can't report them.
+func (s CloudService) RemoteSystemDisks(ctx context.Context, cert *x509.Certificate, address string) ([]types.SystemDisk, error) {
+	client, err := s.remoteClient(cert, address)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err = cloudClient.UseAuthProxy(client, types.MicroCloud, cloudClient.AuthConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	disks, err := cloudClient.GetSystemDisks(ctx, client)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return disks, nil
+}
+
+// RemoteServiceVersions returns the version and API extensions of each service installed on a remote system.
+// Returns nil if the remote system runs a MicroCloud which can't report them.
+func (s CloudService) RemoteServiceVersions(ctx context.Context, cert *x509.Certificate, address string) (map[types.ServiceType]types.ServiceVersion, error) {
+	client, err := s.remoteClient(cert, address)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err = cloudClient.UseAuthProxy(client, types.MicroCloud, cloudClient.AuthConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := cloudClient.GetServiceVersions(ctx, client)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return versions, nil
+}
+
 // ClusterMembers returns a map of cluster member names and addresses.
 func (s CloudService) ClusterMembers(ctx context.Context) (map[string]string, error) {
 	client, err := s.client.LocalClient()
@@ -280,6 +375,16 @@ func (s CloudService) DeleteClusterMember(ctx context.Context, name string, forc
 	return c.DeleteClusterMember(ctx, name, force)
 }
 
+// ResetClusterMember clears the state of the local cluster member, and restarts the service uninitialized.
+func (s CloudService) ResetClusterMember(ctx context.Context) error {
+	c, err := s.client.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return cloudClient.ResetClusterMember(ctx, c, s.name)
+}
+
 // Type returns the type of Service.
 func (s CloudService) Type() types.ServiceType {
 	return types.MicroCloud
@@ -355,6 +460,16 @@ func (s *CloudService) SupportsFeature(ctx context.Context, feature string) (boo
 	return server.Extensions.HasExtension(feature), nil
 }
 
+// GetExtensions returns the API extensions of the service.
+func (s *CloudService) GetExtensions(ctx context.Context) ([]string, error) {
+	server, err := s.client.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MicroCloud server status while checking for extensions: %v", err)
+	}
+
+	return []string(server.Extensions), nil
+}
+
 // ServerCert returns the local clusters server certificate.
 func (s *CloudService) ServerCert() (*shared.CertInfo, error) {
 	return s.client.FileSystem.ServerCert()
@@ -375,6 +490,16 @@ func (s *CloudService) StartSession(ctx context.Context, role string, sessionTim
 	return cloudClient.StartSession(ctx, c, role, sessionTimeout)
 }
 
+// AttachSession attaches to the initiating session with the given ID via the unix socket.
+func (s *CloudService) AttachSession(ctx context.Context, id string, sessionTimeout time.Duration) (*websocket.Conn, error) {
+	c, err := s.client.LocalClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudClient.AttachSession(ctx, c, id, sessionTimeout)
+}
+
 // RemoteClient returns a client targeting a remote MicroCloud.
 func (s *CloudService) RemoteClient(cert *x509.Certificate, address string) (*microClient.Client, error) {
 	c, err := s.remoteClient(cert, address)
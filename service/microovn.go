@@ -5,8 +5,10 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,6 +24,16 @@ import (
 	cloudClient "github.com/canonical/microcloud/microcloud/client"
 )
 
+// OVNEncapsulationFeature is the MicroOVN API extension for choosing the encapsulation of the OVN tunnels.
+const OVNEncapsulationFeature = "custom_encapsulation_type"
+
+// NoUplinkBridge is the isolated OVS bridge used as the parent of the uplink network on members with no uplink connectivity.
+// LXD requires a parent on every member, but no traffic reaches it as those members aren't gateway chassis.
+const NoUplinkBridge = "br-no-uplink"
+
+// OVNChassisRole is the LXD cluster member role restricting the OVN gateway chassis to the members having it.
+const OVNChassisRole = "ovn-chassis"
+
 // OVNService is a MicroOVN service.
 type OVNService struct {
 	m *microcluster.MicroCluster
@@ -30,6 +42,9 @@ type OVNService struct {
 	address string
 	port    int64
 	config  map[string]string
+
+	// simulated services answer without connecting to MicroOVN, see NewSimulatedHandler.
+	simulated bool
 }
 
 // NewOVNService creates a new MicroOVN service with a client attached.
@@ -39,6 +54,8 @@ func NewOVNService(name string, addr string, cloudDir string) (*OVNService, erro
 			r.URL.Path = "/1.0/services/microovn" + r.URL.Path
 		}
 
+		cloudClient.LogRequest(r)
+
 		return shared.ProxyFromEnvironment(r)
 	}
 
@@ -61,6 +78,11 @@ func (s OVNService) Client() (*client.Client, error) {
 	return s.m.LocalClient()
 }
 
+// Ready waits until the MicroOVN daemon is ready to process requests.
+func (s OVNService) Ready(ctx context.Context) error {
+	return s.m.Ready(ctx)
+}
+
 // Bootstrap bootstraps the MicroOVN daemon on the default port.
 func (s OVNService) Bootstrap(ctx context.Context) error {
 	err := s.m.NewCluster(ctx, s.name, util.CanonicalNetworkAddress(s.address, s.port), s.config)
@@ -168,6 +190,16 @@ func (s OVNService) DeleteClusterMember(ctx context.Context, name string, force
 	return c.DeleteClusterMember(ctx, name, force)
 }
 
+// ResetClusterMember clears the state of the local cluster member, and restarts the service uninitialized.
+func (s OVNService) ResetClusterMember(ctx context.Context) error {
+	c, err := s.m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	return cloudClient.ResetClusterMember(ctx, c, s.name)
+}
+
 // Type returns the type of Service.
 func (s OVNService) Type() types.ServiceType {
 	return types.MicroOVN
@@ -239,6 +271,10 @@ func (s *OVNService) SetConfig(config map[string]string) {
 
 // SupportsFeature checks if the specified API feature of this Service instance if supported.
 func (s *OVNService) SupportsFeature(ctx context.Context, feature string) (bool, error) {
+	if s.simulated {
+		return true, nil
+	}
+
 	server, err := s.m.Status(ctx)
 	if err != nil {
 		return false, fmt.Errorf("Failed to get MicroOVN server status while checking for features: %v", err)
@@ -252,6 +288,16 @@ func (s *OVNService) SupportsFeature(ctx context.Context, feature string) (bool,
 	return server.Extensions.HasExtension(feature), nil
 }
 
+// GetExtensions returns the API extensions of the service.
+func (s *OVNService) GetExtensions(ctx context.Context) ([]string, error) {
+	server, err := s.m.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get MicroOVN server status while checking for extensions: %v", err)
+	}
+
+	return []string(server.Extensions), nil
+}
+
 // GetServices returns the list of configured OVN services.
 func (s *OVNService) GetServices(ctx context.Context) (ovnTypes.Services, error) {
 	client, err := s.Client()
@@ -268,3 +314,106 @@ func (s *OVNService) GetServices(ctx context.Context) (ovnTypes.Services, error)
 
 	return services, nil
 }
+
+// NorthboundConnection returns the connection to the OVN northbound database, over SSL to each cluster member running the central services.
+// The addresses of the other cluster members are given by name.
+func (s *OVNService) NorthboundConnection(ctx context.Context, addresses map[string]string) (string, error) {
+	services, err := s.GetServices(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	conns := []string{}
+	for _, service := range services {
+		if service.Service != "central" {
+			continue
+		}
+
+		addr := s.address
+		if service.Location != s.name {
+			addr = addresses[service.Location]
+		}
+
+		if addr == "" {
+			return "", fmt.Errorf("Address of OVN central member %q not found", service.Location)
+		}
+
+		conns = append(conns, "ssl:"+util.CanonicalNetworkAddress(addr, 6641))
+	}
+
+	return strings.Join(conns, ","), nil
+}
+
+// ReissueClientCertificate issues a new certificate for the OVN clients, such as LXD, on the MicroOVN cluster member at the given MicroCloud address.
+// The local cluster member is used if no address is given.
+func (s OVNService) ReissueClientCertificate(ctx context.Context, address string) error {
+	var c *client.Client
+	var err error
+	if address != "" {
+		c, err = s.m.RemoteClient(util.CanonicalNetworkAddress(address, CloudPort))
+		if err != nil {
+			return err
+		}
+
+		c, err = cloudClient.UseAuthProxy(c, types.MicroOVN, cloudClient.AuthConfig{})
+	} else {
+		c, err = s.m.LocalClient()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	response := ovnTypes.IssueCertificateResponse{}
+	err = c.Query(ctx, "PUT", types.APIVersion, &api.NewURL().Path("certificates", "client").URL, nil, &response)
+	if err != nil {
+		return fmt.Errorf("Failed to reissue the OVN client certificate: %w", err)
+	}
+
+	if len(response.Failed) > 0 {
+		return fmt.Errorf("Failed to reissue the OVN certificates of %s", strings.Join(response.Failed, ", "))
+	}
+
+	return nil
+}
+
+// RegenerateCA issues a new OVN CA certificate, along with new certificates for all OVN services and clients on all MicroOVN cluster members.
+func (s OVNService) RegenerateCA(ctx context.Context) error {
+	c, err := s.m.LocalClient()
+	if err != nil {
+		return err
+	}
+
+	response := ovnTypes.NewRegenerateCaResponse()
+	err = c.Query(ctx, "PUT", types.APIVersion, &api.NewURL().Path("ca").URL, nil, response)
+	if err != nil {
+		return fmt.Errorf("Failed to regenerate the OVN CA: %w", err)
+	}
+
+	failures := slices.Clone(response.Errors)
+	for _, host := range slices.Sorted(maps.Keys(response.ReissuedCertificates)) {
+		for _, service := range response.ReissuedCertificates[host].Failed {
+			failures = append(failures, fmt.Sprintf("Failed to reissue the OVN %s certificate on %q", service, host))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("Failed to regenerate the OVN CA: %s", strings.Join(failures, "; "))
+	}
+
+	if !response.NewCa {
+		return errors.New("Failed to regenerate the OVN CA: No new CA certificate was issued")
+	}
+
+	return nil
+}
+
+// CreateNoUplinkBridge creates the isolated OVS bridge used as the uplink parent on members with no uplink connectivity, if it doesn't exist yet.
+func CreateNoUplinkBridge(ctx context.Context) error {
+	_, err := shared.RunCommandContext(ctx, "microovn.ovs-vsctl", "--may-exist", "add-br", NoUplinkBridge)
+	if err != nil {
+		return fmt.Errorf("Failed to create the OVS bridge %q: %w", NoUplinkBridge, err)
+	}
+
+	return nil
+}
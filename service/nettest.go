@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+const (
+	// networkTestLatency is the mode of a connection measuring the round trip time.
+	networkTestLatency byte = 'l'
+
+	// networkTestBandwidth is the mode of a connection measuring the bandwidth.
+	networkTestBandwidth byte = 'b'
+)
+
+// networkTestPings is the number of round trips averaged for a latency measurement.
+const networkTestPings = 20
+
+// networkTestHandshakeTimeout is the time a client has to present its token and mode to the test server.
+const networkTestHandshakeTimeout = 5 * time.Second
+
+// networkTestServer is the built-in server other cluster members measure the network links against.
+type networkTestServer struct {
+	listener net.Listener
+	token    string
+	deadline time.Time
+}
+
+// StartNetworkTestServer starts the network test server on all addresses of this system.
+// Any running test server is replaced. The server stops by itself after the timeout.
+func (s *Handler) StartNetworkTestServer(token string, timeout time.Duration) error {
+	s.networkTestLock.Lock()
+	defer s.networkTestLock.Unlock()
+
+	if s.networkTest != nil {
+		_ = s.networkTest.listener.Close()
+		s.networkTest = nil
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", NetworkTestPort))
+	if err != nil {
+		return fmt.Errorf("Failed to start network test server: %w", err)
+	}
+
+	server := &networkTestServer{listener: listener, token: token, deadline: time.Now().Add(timeout)}
+	s.networkTest = server
+
+	go server.serve()
+	time.AfterFunc(timeout, func() { _ = listener.Close() })
+
+	return nil
+}
+
+// StopNetworkTestServer stops the network test server of this system.
+// If there isn't a running test server it's a no-op.
+func (s *Handler) StopNetworkTestServer() {
+	s.networkTestLock.Lock()
+	defer s.networkTestLock.Unlock()
+
+	if s.networkTest != nil {
+		_ = s.networkTest.listener.Close()
+		s.networkTest = nil
+	}
+}
+
+// serve accepts test connections until the listener is closed.
+func (s *networkTestServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer func() { _ = conn.Close() }()
+
+			err := s.handle(conn)
+			if err != nil {
+				logger.Debug("Network test connection failed", logger.Ctx{"remote": conn.RemoteAddr().String(), "error": err})
+			}
+		}()
+	}
+}
+
+// handle checks the token of the client, and then echoes its pings or counts the bytes it sends.
+func (s *networkTestServer) handle(conn net.Conn) error {
+	err := conn.SetDeadline(time.Now().Add(networkTestHandshakeTimeout))
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+	token, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSuffix(token, "\n")), []byte(s.token)) != 1 {
+		return errors.New("Invalid token")
+	}
+
+	mode, err := reader.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	// Connections never outlive the server.
+	err = conn.SetDeadline(s.deadline)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case networkTestLatency:
+		buf := make([]byte, 1)
+		for {
+			_, err := io.ReadFull(reader, buf)
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			_, err = conn.Write(buf)
+			if err != nil {
+				return err
+			}
+		}
+
+	case networkTestBandwidth:
+		received, err := io.Copy(io.Discard, reader)
+		if err != nil {
+			return err
+		}
+
+		return binary.Write(conn, binary.BigEndian, received)
+	}
+
+	return fmt.Errorf("Unknown mode %q", mode)
+}
+
+// dialNetworkTest connects to the network test server at the given address in the given mode.
+func dialNetworkTest(ctx context.Context, address string, token string, mode byte) (*net.TCPConn, error) {
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, strconv.FormatInt(NetworkTestPort, 10)))
+	if err != nil {
+		return nil, err
+	}
+
+	tcpConn := conn.(*net.TCPConn)
+
+	deadline, ok := ctx.Deadline()
+	if ok {
+		err = tcpConn.SetDeadline(deadline)
+		if err != nil {
+			_ = tcpConn.Close()
+			return nil, err
+		}
+	}
+
+	_, err = tcpConn.Write(append([]byte(token+"\n"), mode))
+	if err != nil {
+		_ = tcpConn.Close()
+		return nil, err
+	}
+
+	return tcpConn, nil
+}
+
+// MeasureLatency returns the mean round trip time to the network test server at the given address.
+func MeasureLatency(ctx context.Context, address string, token string) (time.Duration, error) {
+	conn, err := dialNetworkTest(ctx, address, token, networkTestLatency)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	// Send every ping right away instead of waiting for more data.
+	err = conn.SetNoDelay(true)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := []byte{0}
+	var total time.Duration
+	for range networkTestPings {
+		start := time.Now()
+		_, err := conn.Write(buf)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = io.ReadFull(conn, buf)
+		if err != nil {
+			return 0, err
+		}
+
+		total += time.Since(start)
+	}
+
+	return total / networkTestPings, nil
+}
+
+// MeasureBandwidth returns the bandwidth in bits per second to the network test server at the given address.
+// Data is sent for the given duration, and the bandwidth is computed from the amount the server confirms to have received.
+func MeasureBandwidth(ctx context.Context, address string, token string, duration time.Duration) (int64, error) {
+	conn, err := dialNetworkTest(ctx, address, token, networkTestBandwidth)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	buf := make([]byte, 128*1024)
+	start := time.Now()
+	for time.Since(start) < duration {
+		_, err := conn.Write(buf)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = conn.CloseWrite()
+	if err != nil {
+		return 0, err
+	}
+
+	var received int64
+	err = binary.Read(conn, binary.BigEndian, &received)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to read the received amount: %w", err)
+	}
+
+	elapsed := time.Since(start)
+
+	return int64(float64(received*8) / elapsed.Seconds()), nil
+}
+
+// AddressInSubnet returns the address of this system in the given subnet, if any.
+func AddressInSubnet(subnet string) (string, error) {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return "", fmt.Errorf("Invalid subnet %q: %w", subnet, err)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the addresses of this system: %w", err)
+	}
+
+	for _, addr := range addrs {
+		ip, _, err := net.ParseCIDR(addr.String())
+		if err == nil && ipNet.Contains(ip) {
+			return ip.String(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// OVNEncapsulationAddress returns the address of this system carrying the OVN Geneve tunnels, if any.
+func OVNEncapsulationAddress(ctx context.Context) (string, error) {
+	out, err := shared.RunCommandContext(ctx, "microovn.ovs-vsctl", "--if-exists", "get", "Open_vSwitch", ".", "external_ids:ovn-encap-ip")
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the OVN encapsulation address: %w", err)
+	}
+
+	return strings.Trim(strings.TrimSpace(out), `"`), nil
+}
+
+// OVNEncapsulationType returns the encapsulation of the OVN tunnels of this system, Geneve unless configured otherwise.
+func OVNEncapsulationType(ctx context.Context) (string, error) {
+	out, err := shared.RunCommandContext(ctx, "microovn.ovs-vsctl", "--if-exists", "get", "Open_vSwitch", ".", "external_ids:ovn-encap-type")
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the OVN encapsulation type: %w", err)
+	}
+
+	// Several encapsulations may be listed, the first one is used for the tunnels between chassis.
+	encapType, _, _ := strings.Cut(strings.Trim(strings.TrimSpace(out), `"`), ",")
+	if encapType == "" {
+		return types.OVNEncapsulationGeneve, nil
+	}
+
+	return encapType, nil
+}
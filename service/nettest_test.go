@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type networkTestSuite struct {
+	suite.Suite
+}
+
+func TestNetworkTestSuite(t *testing.T) {
+	suite.Run(t, new(networkTestSuite))
+}
+
+func (s *networkTestSuite) Test_networkTestServer() {
+	h := &Handler{}
+	err := h.StartNetworkTestServer("token", time.Minute)
+	s.Require().NoError(err)
+
+	defer h.StopNetworkTestServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	latency, err := MeasureLatency(ctx, "127.0.0.1", "token")
+	s.NoError(err)
+	s.Positive(latency)
+
+	bandwidth, err := MeasureBandwidth(ctx, "127.0.0.1", "token", 100*time.Millisecond)
+	s.NoError(err)
+	s.Positive(bandwidth)
+
+	_, err = MeasureLatency(ctx, "127.0.0.1", "invalid")
+	s.Error(err)
+
+	h.StopNetworkTestServer()
+	_, err = MeasureLatency(ctx, "127.0.0.1", "token")
+	s.Error(err)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// ovnTunnel is a Geneve tunnel interface of the local OVN chassis.
+type ovnTunnel struct {
+	name      string
+	options   map[string]string
+	bfdStatus map[string]string
+}
+
+// parseOVSMap parses an OVSDB map column, encoded as ["map", [[key, value], ...]].
+func parseOVSMap(raw json.RawMessage) (map[string]string, error) {
+	var column []json.RawMessage
+	err := json.Unmarshal(raw, &column)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(column) != 2 {
+		return nil, fmt.Errorf("Invalid map column %q", string(raw))
+	}
+
+	var pairs [][2]string
+	err = json.Unmarshal(column[1], &pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		values[pair[0]] = pair[1]
+	}
+
+	return values, nil
+}
+
+// parseOVNTunnels parses the JSON output of ovs-vsctl listing the name, options and bfd_status columns of the Geneve interfaces.
+func parseOVNTunnels(out []byte) ([]ovnTunnel, error) {
+	var table struct {
+		Data [][]json.RawMessage `json:"data"`
+	}
+
+	err := json.Unmarshal(out, &table)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the OVN tunnels: %w", err)
+	}
+
+	tunnels := make([]ovnTunnel, 0, len(table.Data))
+	for _, row := range table.Data {
+		if len(row) != 3 {
+			return nil, fmt.Errorf("Failed to parse the OVN tunnels: Expected 3 columns, got %d", len(row))
+		}
+
+		tunnel := ovnTunnel{}
+		err := json.Unmarshal(row[0], &tunnel.name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the OVN tunnel name: %w", err)
+		}
+
+		tunnel.options, err = parseOVSMap(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the options of OVN tunnel %q: %w", tunnel.name, err)
+		}
+
+		tunnel.bfdStatus, err = parseOVSMap(row[2])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse the BFD status of OVN tunnel %q: %w", tunnel.name, err)
+		}
+
+		tunnels = append(tunnels, tunnel)
+	}
+
+	sort.Slice(tunnels, func(i, j int) bool { return tunnels[i].name < tunnels[j].name })
+
+	return tunnels, nil
+}
+
+// probeOVNTunnel checks whether the remote endpoint of the tunnel can be reached.
+// The BFD session state is used if OVN enabled BFD on the tunnel, otherwise the remote endpoint is pinged from the local one.
+func probeOVNTunnel(ctx context.Context, tunnel ovnTunnel, localAddress string) error {
+	state, ok := tunnel.bfdStatus["state"]
+	if ok {
+		if state == "up" {
+			return nil
+		}
+
+		diagnostic := tunnel.bfdStatus["diagnostic"]
+		if diagnostic != "" {
+			return fmt.Errorf("BFD session is %s: %s", state, diagnostic)
+		}
+
+		return fmt.Errorf("BFD session is %s", state)
+	}
+
+	args := []string{"-c", "3", "-W", "1", "-q"}
+	if localAddress != "" {
+		args = append(args, "-I", localAddress)
+	}
+
+	_, err := shared.RunCommandContext(ctx, "ping", append(args, tunnel.options["remote_ip"])...)
+	if err != nil {
+		return fmt.Errorf("Remote tunnel endpoint is unreachable: %w", err)
+	}
+
+	return nil
+}
+
+// mergeUnderlayPaths carries over the time of the last change of each path from the previous probe.
+func mergeUnderlayPaths(previous []types.UnderlayPath, probed []types.UnderlayPath) []types.UnderlayPath {
+	for i, path := range probed {
+		index := slices.IndexFunc(previous, func(p types.UnderlayPath) bool { return p.RemoteAddress == path.RemoteAddress })
+		if index >= 0 && previous[index].Reachable == path.Reachable {
+			probed[i].Since = previous[index].Since
+		} else {
+			probed[i].Since = path.CheckedAt
+		}
+	}
+
+	return probed
+}
+
+// probeOVNTunnels probes the Geneve tunnel endpoints of the local OVN chassis, and returns the state of each path.
+func probeOVNTunnels(ctx context.Context) ([]types.UnderlayPath, error) {
+	encapType, err := OVNEncapsulationType(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := shared.RunCommandContext(ctx, "microovn.ovs-vsctl", "--format=json", "--columns=name,options,bfd_status", "find", "Interface", "type="+encapType)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list the OVN tunnels: %w", err)
+	}
+
+	tunnels, err := parseOVNTunnels([]byte(out))
+	if err != nil {
+		return nil, err
+	}
+
+	encapAddress, err := OVNEncapsulationAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	probed := make([]types.UnderlayPath, 0, len(tunnels))
+	for _, tunnel := range tunnels {
+		localAddress := tunnel.options["local_ip"]
+		if localAddress == "" {
+			localAddress = encapAddress
+		}
+
+		path := types.UnderlayPath{
+			Name:          tunnel.name,
+			LocalAddress:  localAddress,
+			RemoteAddress: tunnel.options["remote_ip"],
+			Reachable:     true,
+		}
+
+		err := probeOVNTunnel(ctx, tunnel, localAddress)
+		if err != nil {
+			path.Reachable = false
+			path.Error = err.Error()
+		}
+
+		path.CheckedAt = time.Now()
+		probed = append(probed, path)
+	}
+
+	return probed, nil
+}
+
+// ProbeOVNUnderlay probes the Geneve tunnel endpoints of the local OVN chassis, and records the state of each path.
+// Paths which become unreachable or reachable again are logged, and returned.
+func (s *Handler) ProbeOVNUnderlay(ctx context.Context) ([]types.UnderlayPath, error) {
+	probed, err := probeOVNTunnels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.underlayLock.Lock()
+	defer s.underlayLock.Unlock()
+
+	var changed []types.UnderlayPath
+	probed = mergeUnderlayPaths(s.underlayPaths, probed)
+	for _, path := range probed {
+		if !path.Since.Equal(path.CheckedAt) {
+			continue
+		}
+
+		ctx := logger.Ctx{"interface": path.Name, "local": path.LocalAddress, "remote": path.RemoteAddress}
+		if !path.Reachable {
+			ctx["error"] = path.Error
+			logger.Warn("OVN underlay path is down", ctx)
+			changed = append(changed, path)
+		} else if slices.ContainsFunc(s.underlayPaths, func(p types.UnderlayPath) bool { return p.RemoteAddress == path.RemoteAddress }) {
+			logger.Info("OVN underlay path recovered", ctx)
+			changed = append(changed, path)
+		}
+	}
+
+	s.underlayPaths = probed
+
+	return changed, nil
+}
+
+// OVNUnderlayPaths returns the state of the OVN underlay paths as of the last probe.
+func (s *Handler) OVNUnderlayPaths() []types.UnderlayPath {
+	s.underlayLock.Lock()
+	defer s.underlayLock.Unlock()
+
+	return slices.Clone(s.underlayPaths)
+}
+
+// ResetOVNUnderlay forgets the state of the OVN underlay paths, once the watchdog is disabled.
+func (s *Handler) ResetOVNUnderlay() {
+	s.underlayLock.Lock()
+	defer s.underlayLock.Unlock()
+
+	s.underlayPaths = nil
+}
+
+// InterfaceWithAddress returns the local interface the given address is configured on.
+// If the address has a prefix length, it must match the one configured on the interface.
+func InterfaceWithAddress(address string) (string, error) {
+	ip, ipNet, err := net.ParseCIDR(address)
+	if err != nil {
+		ip = net.ParseIP(address)
+		if ip == nil {
+			return "", fmt.Errorf("Invalid address %q", address)
+		}
+	}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the interfaces of this system: %w", err)
+	}
+
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ifaceIP, ifaceNet, err := net.ParseCIDR(addr.String())
+			if err == nil && ifaceIP.Equal(ip) && (ipNet == nil || ifaceNet.String() == ipNet.String()) {
+				return iface.Name, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("Address %q is not configured on any interface of this system", address)
+}
+
+// SetOVNEncapsulationAddress makes the local OVN chassis use the given address for its Geneve tunnels.
+// The other chassis re-establish their tunnels to the new address once ovn-controller updates the southbound database.
+func SetOVNEncapsulationAddress(ctx context.Context, address string) error {
+	_, err := shared.RunCommandContext(ctx, "microovn.ovs-vsctl", "set", "Open_vSwitch", ".", "external_ids:ovn-encap-ip="+address)
+	if err != nil {
+		return fmt.Errorf("Failed to set the OVN encapsulation address: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type ovnUnderlaySuite struct {
+	suite.Suite
+}
+
+func TestOVNUnderlaySuite(t *testing.T) {
+	suite.Run(t, new(ovnUnderlaySuite))
+}
+
+func (s *ovnUnderlaySuite) Test_parseOVNTunnels() {
+	out := `{"data":[["ovn-micro03-0",["map",[["csum","true"],["key","flow"],["remote_ip","10.0.0.3"]]],["map",[]]],["ovn-micro02-0",["map",[["key","flow"],["local_ip","10.0.1.1"],["remote_ip","10.0.0.2"]]],["map",[["diagnostic","Control Detection Time Expired"],["state","down"]]]]],"headings":["name","options","bfd_status"]}`
+
+	tunnels, err := parseOVNTunnels([]byte(out))
+	s.NoError(err)
+	s.Len(tunnels, 2)
+
+	s.Equal("ovn-micro02-0", tunnels[0].name)
+	s.Equal("10.0.0.2", tunnels[0].options["remote_ip"])
+	s.Equal("10.0.1.1", tunnels[0].options["local_ip"])
+	s.Equal("down", tunnels[0].bfdStatus["state"])
+
+	s.Equal("ovn-micro03-0", tunnels[1].name)
+	s.Equal("10.0.0.3", tunnels[1].options["remote_ip"])
+	s.Empty(tunnels[1].bfdStatus)
+
+	tunnels, err = parseOVNTunnels([]byte(`{"data":[],"headings":["name","options","bfd_status"]}`))
+	s.NoError(err)
+	s.Empty(tunnels)
+
+	_, err = parseOVNTunnels([]byte(`{"data":[["ovn-micro02-0"]]}`))
+	s.Error(err)
+
+	_, err = parseOVNTunnels([]byte("ovs-vsctl: unix:/var/run/openvswitch/db.sock: database connection failed"))
+	s.Error(err)
+}
+
+func (s *ovnUnderlaySuite) Test_probeOVNTunnelBFD() {
+	err := probeOVNTunnel(context.Background(), ovnTunnel{bfdStatus: map[string]string{"state": "up"}}, "")
+	s.NoError(err)
+
+	err = probeOVNTunnel(context.Background(), ovnTunnel{bfdStatus: map[string]string{"state": "down", "diagnostic": "Control Detection Time Expired"}}, "")
+	s.EqualError(err, "BFD session is down: Control Detection Time Expired")
+}
+
+func (s *ovnUnderlaySuite) Test_mergeUnderlayPaths() {
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+
+	previous := []types.UnderlayPath{
+		{RemoteAddress: "10.0.0.2", Reachable: true, CheckedAt: first, Since: first},
+		{RemoteAddress: "10.0.0.3", Reachable: true, CheckedAt: first, Since: first},
+	}
+
+	probed := mergeUnderlayPaths(previous, []types.UnderlayPath{
+		{RemoteAddress: "10.0.0.2", Reachable: true, CheckedAt: second},
+		{RemoteAddress: "10.0.0.3", Reachable: false, CheckedAt: second},
+		{RemoteAddress: "10.0.0.4", Reachable: true, CheckedAt: second},
+	})
+
+	s.Equal(first, probed[0].Since)
+	s.Equal(second, probed[1].Since)
+	s.Equal(second, probed[2].Since)
+}
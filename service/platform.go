@@ -0,0 +1,82 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// VirtualOSDPoolSize is the default replication factor of the OSD pools when all OSD hosts are virtual machines.
+// Such systems usually share the underlying hardware, so further replicas add little fault tolerance.
+const VirtualOSDPoolSize = 2
+
+// azureAssetTag is the chassis asset tag of virtual machines on Microsoft Azure.
+const azureAssetTag = "7783-7084-3265-9085-8269-3286-77"
+
+// hypervisorVendors are the firmware vendors and product names which identify a virtual machine.
+var hypervisorVendors = []string{"QEMU", "KVM", "VMware", "VirtualBox", "innotek GmbH", "Xen", "Bochs", "Parallels", "Virtual Machine", "Amazon EC2", "Google Compute Engine", "OpenStack"}
+
+// cloudVendors maps the firmware vendors and product names of virtual machines to the cloud they identify.
+var cloudVendors = map[string]string{
+	"Amazon EC2":            "aws",
+	"Google Compute Engine": "gce",
+	"OpenStack":             "openstack",
+	"DigitalOcean":          "digitalocean",
+	"Hetzner":               "hetzner",
+}
+
+// DetectPlatform returns the platform identified by the given DMI fields, keyed by their name in /sys/class/dmi/id, and the content of /proc/cpuinfo.
+func DetectPlatform(dmi map[string]string, cpuinfo string, kvm bool) types.Platform {
+	platform := types.Platform{KVM: kvm}
+	for _, line := range strings.Split(cpuinfo, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == "flags" && slices.Contains(strings.Fields(value), "hypervisor") {
+			platform.Virtual = true
+			break
+		}
+	}
+
+	identity := dmi["sys_vendor"] + " " + dmi["product_name"]
+	for _, vendor := range hypervisorVendors {
+		if strings.Contains(identity, vendor) {
+			platform.Virtual = true
+			break
+		}
+	}
+
+	if dmi["chassis_asset_tag"] == azureAssetTag {
+		platform.Cloud = "azure"
+		return platform
+	}
+
+	for vendor, cloud := range cloudVendors {
+		if strings.Contains(identity, vendor) {
+			platform.Cloud = cloud
+			break
+		}
+	}
+
+	return platform
+}
+
+// LocalPlatform returns the platform the local system runs in. It is only detected once.
+var LocalPlatform = sync.OnceValue(func() types.Platform {
+	dmi := map[string]string{}
+	for _, field := range []string{"sys_vendor", "product_name", "chassis_asset_tag"} {
+		content, err := os.ReadFile(filepath.Join("/sys/class/dmi/id", field))
+		if err == nil {
+			dmi[field] = strings.TrimSpace(string(content))
+		}
+	}
+
+	// The hypervisor flag is only reported on x86, so a missing cpuinfo falls back to the DMI fields.
+	cpuinfo, _ := os.ReadFile("/proc/cpuinfo")
+
+	_, err := os.Stat("/dev/kvm")
+
+	return DetectPlatform(dmi, string(cpuinfo), err == nil)
+})
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type platformSuite struct {
+	suite.Suite
+}
+
+func TestPlatformSuite(t *testing.T) {
+	suite.Run(t, new(platformSuite))
+}
+
+func (s *platformSuite) Test_detectPlatform() {
+	cases := []struct {
+		desc    string
+		dmi     map[string]string
+		cpuinfo string
+		kvm     bool
+
+		expectPlatform types.Platform
+	}{
+		{
+			desc:           "Physical system",
+			dmi:            map[string]string{"sys_vendor": "LENOVO", "product_name": "20HRCTO1WW"},
+			cpuinfo:        "processor\t: 0\nflags\t\t: fpu vme de pse vmx\n",
+			kvm:            true,
+			expectPlatform: types.Platform{KVM: true},
+		},
+		{
+			desc:           "Virtual machine identified by the hypervisor flag",
+			dmi:            map[string]string{},
+			cpuinfo:        "processor\t: 0\nflags\t\t: fpu vme de pse hypervisor\n",
+			expectPlatform: types.Platform{Virtual: true},
+		},
+		{
+			desc:           "Nested virtual machine on QEMU",
+			dmi:            map[string]string{"sys_vendor": "QEMU", "product_name": "Standard PC (Q35 + ICH9, 2009)"},
+			kvm:            true,
+			expectPlatform: types.Platform{Virtual: true, KVM: true},
+		},
+		{
+			desc:           "Virtual machine on AWS",
+			dmi:            map[string]string{"sys_vendor": "Amazon EC2", "product_name": "m5.large"},
+			expectPlatform: types.Platform{Virtual: true, Cloud: "aws"},
+		},
+		{
+			desc:           "Virtual machine on Azure",
+			dmi:            map[string]string{"sys_vendor": "Microsoft Corporation", "product_name": "Virtual Machine", "chassis_asset_tag": azureAssetTag},
+			expectPlatform: types.Platform{Virtual: true, Cloud: "azure"},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.expectPlatform, DetectPlatform(c.dmi, c.cpuinfo, c.kvm))
+	}
+}
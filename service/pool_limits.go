@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared/units"
+)
+
+// PoolSizeLimit returns the maximum size in bytes of a storage pool with the given capacity,
+// out of a size (e.g. 500GiB) or a percentage of the capacity (e.g. 80%).
+func PoolSizeLimit(limit string, capacity uint64) (uint64, error) {
+	percentage, ok := strings.CutSuffix(limit, "%")
+	if ok {
+		value, err := strconv.ParseFloat(percentage, 64)
+		if err != nil || value <= 0 || value >= 100 {
+			return 0, fmt.Errorf("Invalid percentage %q, must be between 0 and 100 excluded", limit)
+		}
+
+		return uint64(float64(capacity) * value / 100), nil
+	}
+
+	size, err := units.ParseByteSizeString(limit)
+	if err != nil {
+		return 0, err
+	}
+
+	if size <= 0 {
+		return 0, fmt.Errorf("Invalid size %q, must be positive", limit)
+	}
+
+	return uint64(size), nil
+}
+
+// ValidatePoolSizeLimit checks that the value is a size, or a percentage between 0 and 100 excluded.
+func ValidatePoolSizeLimit(value string) error {
+	_, err := PoolSizeLimit(value, 0)
+
+	return err
+}
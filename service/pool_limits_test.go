@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type poolLimitsSuite struct {
+	suite.Suite
+}
+
+func TestPoolLimitsSuite(t *testing.T) {
+	suite.Run(t, new(poolLimitsSuite))
+}
+
+func (s *poolLimitsSuite) Test_PoolSizeLimit() {
+	cases := []struct {
+		desc     string
+		limit    string
+		capacity uint64
+		maxBytes uint64
+		err      bool
+	}{
+		{
+			desc:     "Size",
+			limit:    "500GiB",
+			capacity: 1024 * 1024 * 1024 * 1024,
+			maxBytes: 500 * 1024 * 1024 * 1024,
+		},
+		{
+			desc:     "Size beyond the capacity",
+			limit:    "2TiB",
+			capacity: 1024 * 1024 * 1024 * 1024,
+			maxBytes: 2 * 1024 * 1024 * 1024 * 1024,
+		},
+		{
+			desc:     "Percentage",
+			limit:    "80%",
+			capacity: 1000,
+			maxBytes: 800,
+		},
+		{
+			desc:     "Fractional percentage",
+			limit:    "12.5%",
+			capacity: 1000,
+			maxBytes: 125,
+		},
+		{
+			desc:  "Whole capacity",
+			limit: "100%",
+			err:   true,
+		},
+		{
+			desc:  "No capacity",
+			limit: "0%",
+			err:   true,
+		},
+		{
+			desc:  "Invalid percentage",
+			limit: "half%",
+			err:   true,
+		},
+		{
+			desc:  "Invalid size",
+			limit: "lots",
+			err:   true,
+		},
+		{
+			desc:  "Zero size",
+			limit: "0GiB",
+			err:   true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		maxBytes, err := PoolSizeLimit(c.limit, c.capacity)
+		if c.err {
+			s.Error(err)
+			s.Error(ValidatePoolSizeLimit(c.limit))
+			continue
+		}
+
+		s.NoError(err)
+		s.NoError(ValidatePoolSizeLimit(c.limit))
+		s.Equal(c.maxBytes, maxBytes)
+	}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// ReachabilityTimeout is how long connecting to an address may take before it is considered unreachable.
+const ReachabilityTimeout = 5 * time.Second
+
+// CheckReachability connects to each of the given addresses over TCP, and returns whether they are reachable in the same order.
+func CheckReachability(ctx context.Context, addresses []string, timeout time.Duration) []types.Reachability {
+	results := make([]types.Reachability, len(addresses))
+	dialer := net.Dialer{Timeout: timeout}
+
+	wg := sync.WaitGroup{}
+	for i, address := range addresses {
+		results[i].Address = address
+
+		wg.Add(1)
+		go func(result *types.Reachability) {
+			defer wg.Done()
+
+			conn, err := dialer.DialContext(ctx, "tcp", result.Address)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+
+			_ = conn.Close()
+		}(&results[i])
+	}
+
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type reachabilitySuite struct {
+	suite.Suite
+}
+
+func TestReachabilitySuite(t *testing.T) {
+	suite.Run(t, new(reachabilitySuite))
+}
+
+func (s *reachabilitySuite) Test_CheckReachability() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer listener.Close()
+
+	// A closed listener leaves an address nothing listens on.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	unreachable := closed.Addr().String()
+	s.Require().NoError(closed.Close())
+
+	results := CheckReachability(context.Background(), []string{listener.Addr().String(), unreachable}, time.Second)
+	s.Require().Len(results, 2)
+	s.Equal(listener.Addr().String(), results[0].Address)
+	s.Empty(results[0].Error)
+	s.Equal(unreachable, results[1].Address)
+	s.NotEmpty(results[1].Error)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/units"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+	cloudClient "github.com/canonical/microcloud/microcloud/client"
+)
+
+const (
+	// ReportScheduleDaily generates a health report every day.
+	ReportScheduleDaily = "daily"
+
+	// ReportScheduleWeekly generates a health report every week.
+	ReportScheduleWeekly = "weekly"
+)
+
+// ReportSchedules are the supported schedules of the health reports.
+var ReportSchedules = []string{ReportScheduleDaily, ReportScheduleWeekly}
+
+// MaxHealthReports is the number of most recent health reports kept in the database.
+const MaxHealthReports = 60
+
+// ReportPeriod returns the time between two health reports on the given schedule.
+func ReportPeriod(schedule string) time.Duration {
+	if schedule == ReportScheduleWeekly {
+		return 7 * 24 * time.Hour
+	}
+
+	return 24 * time.Hour
+}
+
+// sharedStorageDrivers are the LXD storage drivers whose capacity is shared by the cluster members.
+var sharedStorageDrivers = []string{"ceph", "cephfs", "cephobject"}
+
+// HealthReport collects the status, capacity and warnings of the cluster from the local MicroCloud daemon, and returns them as a health report generated by the given cluster member.
+// Cluster members which can't be inspected are still listed with their status.
+func (s *Handler) HealthReport(ctx context.Context, name string) (*types.HealthReport, error) {
+	microClient, err := s.Services[types.MicroCloud].(*CloudService).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := cloudClient.GetStatus(ctx, microClient)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the cluster status: %w", err)
+	}
+
+	inspections := map[string]*types.MemberInspection{}
+	for _, status := range statuses {
+		inspection, err := cloudClient.GetInspection(ctx, microClient.UseTarget(status.Name))
+		if err != nil {
+			logger.Warn("Failed to inspect cluster member for the health report", logger.Ctx{"name": status.Name, "err": err})
+			continue
+		}
+
+		inspections[status.Name] = inspection
+	}
+
+	warnings, err := cloudClient.GetWarnings(ctx, microClient)
+	if err != nil {
+		logger.Warn("Failed to get warnings for the health report", logger.Ctx{"err": err})
+	}
+
+	var pools []types.HealthReportPool
+	if s.Services[types.LXD] != nil {
+		pools, err = storagePoolCapacity(ctx, s.Services[types.LXD].(*LXDService))
+		if err != nil {
+			logger.Warn("Failed to get storage pool capacity for the health report", logger.Ctx{"err": err})
+		}
+	}
+
+	report := BuildHealthReport(name, statuses, inspections, pools, warnings, time.Now().UTC())
+
+	return &report, nil
+}
+
+// storagePoolCapacity returns the capacity of each LXD storage pool, per cluster member for the storage pools which aren't shared.
+func storagePoolCapacity(ctx context.Context, lxd *LXDService) ([]types.HealthReportPool, error) {
+	client, err := lxd.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	storagePools, err := client.GetStoragePools()
+	if err != nil {
+		return nil, err
+	}
+
+	pools := []types.HealthReportPool{}
+	for _, pool := range storagePools {
+		members := []string{""}
+		if !slices.Contains(sharedStorageDrivers, pool.Driver) {
+			members = pool.Locations
+		}
+
+		for _, member := range members {
+			resources, err := client.UseTarget(member).GetStoragePoolResources(pool.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get resources of storage pool %q: %w", pool.Name, err)
+			}
+
+			pools = append(pools, types.HealthReportPool{Name: pool.Name, Driver: pool.Driver, Member: member, Used: resources.Space.Used, Total: resources.Space.Total})
+		}
+	}
+
+	return pools, nil
+}
+
+// BuildHealthReport combines the status, inspections, storage pool capacity and warnings of the cluster into a health report.
+// Acknowledged warnings are left out.
+func BuildHealthReport(member string, statuses []types.Status, inspections map[string]*types.MemberInspection, pools []types.HealthReportPool, warnings []types.ServiceWarning, now time.Time) types.HealthReport {
+	report := types.HealthReport{
+		CreatedAt:    now,
+		Member:       member,
+		Members:      []types.HealthReportMember{},
+		StoragePools: []types.HealthReportPool{},
+		Warnings:     []types.ServiceWarning{},
+	}
+
+	// The MicroCloud status of each member, as seen by the member generating the report.
+	memberStatus := map[string]string{}
+	for _, status := range statuses {
+		if status.Name != member {
+			continue
+		}
+
+		for _, clusterMember := range status.Clusters[types.MicroCloud] {
+			memberStatus[clusterMember.Name] = string(clusterMember.Status)
+		}
+	}
+
+	for _, status := range statuses {
+		reportMember := types.HealthReportMember{Name: status.Name, Status: memberStatus[status.Name]}
+		inspection := inspections[status.Name]
+		if inspection != nil {
+			reportMember.Instances = inspection.Instances
+			reportMember.Resources = inspection.Resources
+			reportMember.OSDs = len(inspection.OSDs)
+			for _, osd := range inspection.OSDs {
+				if !osd.Running {
+					reportMember.OSDsDown++
+				}
+			}
+		} else {
+			reportMember.OSDs = len(status.OSDs)
+		}
+
+		report.Members = append(report.Members, reportMember)
+	}
+
+	slices.SortFunc(report.Members, func(a types.HealthReportMember, b types.HealthReportMember) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	report.StoragePools = append(report.StoragePools, pools...)
+	slices.SortFunc(report.StoragePools, func(a types.HealthReportPool, b types.HealthReportPool) int {
+		return strings.Compare(a.Name+"/"+a.Member, b.Name+"/"+b.Member)
+	})
+
+	for _, w := range warnings {
+		if !w.Acknowledged {
+			report.Warnings = append(report.Warnings, w)
+		}
+	}
+
+	return report
+}
+
+// healthReportTemplate renders a health report as a standalone HTML page.
+var healthReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"bytes": func(v uint64) string { return units.GetByteSizeStringIEC(int64(v), 2) },
+	"percent": func(used uint64, total uint64) string {
+		if total == 0 {
+			return "-"
+		}
+
+		return fmt.Sprintf("%.1f%%", float64(used)*100/float64(total))
+	},
+	"time": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>MicroCloud health report {{ time .CreatedAt }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+th { background: #eee; }
+.bad { color: #c7162b; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>MicroCloud health report</h1>
+<p>Generated on {{ time .CreatedAt }} by {{ .Member }}.</p>
+<h2>Cluster members</h2>
+<table>
+<tr><th>Name</th><th>Status</th><th>Instances</th><th>CPUs</th><th>Memory used</th><th>OSDs</th><th>OSDs down</th></tr>
+{{- range .Members }}
+<tr><td>{{ .Name }}</td><td{{ if ne .Status "ONLINE" }} class="bad"{{ end }}>{{ .Status }}</td><td>{{ .Instances }}</td>
+{{- if .Resources }}<td>{{ .Resources.CPUs }}</td><td>{{ bytes .Resources.MemoryUsed }} / {{ bytes .Resources.MemoryTotal }} ({{ percent .Resources.MemoryUsed .Resources.MemoryTotal }})</td>{{ else }}<td>-</td><td>-</td>{{ end -}}
+<td>{{ .OSDs }}</td><td{{ if gt .OSDsDown 0 }} class="bad"{{ end }}>{{ .OSDsDown }}</td></tr>
+{{- end }}
+</table>
+<h2>Storage pools</h2>
+<table>
+<tr><th>Name</th><th>Driver</th><th>Member</th><th>Used</th><th>Total</th><th>Usage</th></tr>
+{{- range .StoragePools }}
+<tr><td>{{ .Name }}</td><td>{{ .Driver }}</td><td>{{ if .Member }}{{ .Member }}{{ else }}shared{{ end }}</td><td>{{ bytes .Used }}</td><td>{{ bytes .Total }}</td><td>{{ percent .Used .Total }}</td></tr>
+{{- end }}
+</table>
+<h2>Warnings</h2>
+{{- if .Warnings }}
+<table>
+<tr><th>Service</th><th>Location</th><th>Severity</th><th>Message</th></tr>
+{{- range .Warnings }}
+<tr><td>{{ .Service }}</td><td>{{ if .Location }}{{ .Location }}{{ else }}cluster{{ end }}</td><td>{{ .Severity }}</td><td>{{ .Message }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No warnings.</p>
+{{- end }}
+</body>
+</html>
+`))
+
+// RenderHealthReportHTML returns the health report as a standalone HTML page.
+func RenderHealthReportHTML(report types.HealthReport) ([]byte, error) {
+	var b bytes.Buffer
+	err := healthReportTemplate.Execute(&b, report)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to render health report: %w", err)
+	}
+
+	return b.Bytes(), nil
+}
+
+// PostHealthReport posts the health report as JSON to the given URL.
+func PostHealthReport(ctx context.Context, url string, report types.HealthReport) error {
+	return postJSON(ctx, url, report)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	cephTypes "github.com/canonical/microceph/microceph/api/types"
+	microTypes "github.com/canonical/microcluster/v3/microcluster/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type reportsSuite struct {
+	suite.Suite
+}
+
+func TestReportsSuite(t *testing.T) {
+	suite.Run(t, new(reportsSuite))
+}
+
+func (s *reportsSuite) Test_BuildHealthReport() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	members := []microTypes.ClusterMember{
+		{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: "micro01"}, Status: microTypes.MemberOnline},
+		{ClusterMemberLocal: microTypes.ClusterMemberLocal{Name: "micro02"}, Status: microTypes.MemberUnreachable},
+	}
+
+	statuses := []types.Status{
+		{Name: "micro02", OSDs: cephTypes.Disks{{OSD: 2}}, Clusters: map[types.ServiceType][]microTypes.ClusterMember{types.MicroCloud: members}},
+		{Name: "micro01", Clusters: map[types.ServiceType][]microTypes.ClusterMember{types.MicroCloud: members}},
+	}
+
+	inspections := map[string]*types.MemberInspection{
+		"micro01": {Instances: 3, Resources: &types.MemberResources{CPUs: 8}, OSDs: []types.OSDState{{OSD: 0, Running: true}, {OSD: 1}}},
+	}
+
+	pools := []types.HealthReportPool{
+		{Name: "local", Driver: "zfs", Member: "micro02", Used: 1, Total: 10},
+		{Name: "local", Driver: "zfs", Member: "micro01", Used: 2, Total: 10},
+		{Name: "remote", Driver: "ceph", Used: 3, Total: 30},
+	}
+
+	warnings := []types.ServiceWarning{
+		{ID: "ceph:OSD_DOWN", Service: types.MicroCeph, Severity: "moderate", Message: "1 osds down"},
+		{ID: "ovn:micro02", Service: types.MicroOVN, Severity: "high", Acknowledged: true},
+	}
+
+	report := BuildHealthReport("micro01", statuses, inspections, pools, warnings, now)
+	s.Equal(now, report.CreatedAt)
+	s.Equal("micro01", report.Member)
+	s.Equal([]types.HealthReportMember{
+		{Name: "micro01", Status: "ONLINE", Instances: 3, Resources: &types.MemberResources{CPUs: 8}, OSDs: 2, OSDsDown: 1},
+		{Name: "micro02", Status: "UNREACHABLE", OSDs: 1},
+	}, report.Members)
+
+	s.Equal([]string{"micro01", "micro02", ""}, []string{report.StoragePools[0].Member, report.StoragePools[1].Member, report.StoragePools[2].Member})
+
+	s.T().Log("Acknowledged warnings are left out")
+	s.Len(report.Warnings, 1)
+	s.Equal("ceph:OSD_DOWN", report.Warnings[0].ID)
+
+	s.T().Log("An empty cluster still has empty lists")
+	report = BuildHealthReport("micro01", nil, nil, nil, nil, now)
+	s.NotNil(report.Members)
+	s.NotNil(report.StoragePools)
+	s.NotNil(report.Warnings)
+}
+
+func (s *reportsSuite) Test_RenderHealthReportHTML() {
+	report := types.HealthReport{
+		CreatedAt:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Member:       "micro01",
+		Members:      []types.HealthReportMember{{Name: "micro01", Status: "ONLINE", Resources: &types.MemberResources{CPUs: 4, MemoryTotal: 4096, MemoryUsed: 1024}}, {Name: "micro02", Status: "OFFLINE"}},
+		StoragePools: []types.HealthReportPool{{Name: "remote", Driver: "ceph", Used: 25, Total: 100}},
+		Warnings:     []types.ServiceWarning{{Service: types.LXD, Severity: "low", Message: "<script>alert(1)</script>"}},
+	}
+
+	out, err := RenderHealthReportHTML(report)
+	s.Require().NoError(err)
+
+	html := string(out)
+	s.Contains(html, "Generated on 2024-01-01T00:00:00Z by micro01.")
+	s.Contains(html, "(25.0%)")
+	s.Contains(html, `<td class="bad">OFFLINE</td>`)
+	s.Contains(html, "<td>shared</td>")
+	s.NotContains(html, "<script>", "Messages must be escaped")
+	s.NotContains(html, "No warnings.")
+}
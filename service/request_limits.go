@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// RequestRejectedRate is the reason of the requests rejected by the rate limit of the MicroCloud API.
+	RequestRejectedRate = "rate"
+
+	// RequestRejectedQuota is the reason of the requests rejected by the quota of the MicroCloud API.
+	RequestRejectedQuota = "quota"
+)
+
+// RequestLimits are the limits applied to the requests of each client of the MicroCloud API.
+// A zero number of requests disables the limit.
+type RequestLimits struct {
+	// RateRequests may be sent in bursts, and then at a steady pace of RateRequests per RatePeriod.
+	RateRequests int
+	RatePeriod   time.Duration
+
+	// QuotaRequests may be sent in each QuotaPeriod.
+	QuotaRequests int
+	QuotaPeriod   time.Duration
+}
+
+// RejectedRequests is the number of requests of a client rejected for the given reason.
+type RejectedRequests struct {
+	Client string
+	Reason string
+	Count  uint64
+}
+
+// rejectedKey identifies the requests of a client rejected for a reason.
+type rejectedKey struct {
+	client string
+	reason string
+}
+
+// requestClient is the use of the request limits by a client.
+type requestClient struct {
+	// tokens left in the rate limit bucket, as of lastSeen.
+	tokens   float64
+	lastSeen time.Time
+
+	// Requests sent in the current quota window.
+	windowStart time.Time
+	windowCount int
+}
+
+// ParseRequestLimit parses a request limit in the <requests>/<duration> format, such as 100/1m.
+func ParseRequestLimit(value string) (int, time.Duration, error) {
+	countValue, periodValue, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, errors.New("Must be in the <requests>/<duration> format, such as 100/1m")
+	}
+
+	count, err := strconv.Atoi(countValue)
+	if err != nil || count < 1 {
+		return 0, 0, fmt.Errorf("Invalid number of requests %q, must be at least 1", countValue)
+	}
+
+	period, err := time.ParseDuration(periodValue)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Invalid duration %q: %w", periodValue, err)
+	}
+
+	if period < time.Second {
+		return 0, 0, fmt.Errorf("Invalid duration %q, must be at least 1s", periodValue)
+	}
+
+	return count, period, nil
+}
+
+// SetRequestLimits sets the limits applied to the requests of each client of the MicroCloud API.
+// Clients which haven't sent requests for longer than the limits keep track of are forgotten, along with their rejected requests.
+func (s *Handler) SetRequestLimits(limits RequestLimits, now time.Time) {
+	s.requestLimitsLock.Lock()
+	defer s.requestLimitsLock.Unlock()
+
+	s.requestLimits = limits
+	for name, client := range s.requestClients {
+		if now.Sub(client.lastSeen) > max(limits.RatePeriod, limits.QuotaPeriod) {
+			delete(s.requestClients, name)
+			delete(s.rejectedRequests, rejectedKey{client: name, reason: RequestRejectedRate})
+			delete(s.rejectedRequests, rejectedKey{client: name, reason: RequestRejectedQuota})
+		}
+	}
+}
+
+// AllowRequest records a request of the given client, and returns the reason it exceeds the request limits, or an empty string if it doesn't.
+// The rate limit is a token bucket holding up to RateRequests tokens, refilled at RateRequests per RatePeriod.
+// The quota counts the requests in fixed windows of QuotaPeriod.
+func (s *Handler) AllowRequest(client string, now time.Time) string {
+	s.requestLimitsLock.Lock()
+	defer s.requestLimitsLock.Unlock()
+
+	limits := s.requestLimits
+	if limits.RateRequests == 0 && limits.QuotaRequests == 0 {
+		return ""
+	}
+
+	if s.requestClients == nil {
+		s.requestClients = map[string]*requestClient{}
+	}
+
+	c, ok := s.requestClients[client]
+	if !ok {
+		c = &requestClient{tokens: float64(limits.RateRequests), lastSeen: now, windowStart: now}
+		s.requestClients[client] = c
+	}
+
+	reason := ""
+	if limits.RateRequests > 0 {
+		refill := now.Sub(c.lastSeen).Seconds() * float64(limits.RateRequests) / limits.RatePeriod.Seconds()
+		c.tokens = min(c.tokens+refill, float64(limits.RateRequests))
+		if c.tokens < 1 {
+			reason = RequestRejectedRate
+		}
+	}
+
+	if reason == "" && limits.QuotaRequests > 0 {
+		if now.Sub(c.windowStart) >= limits.QuotaPeriod {
+			c.windowStart = now
+			c.windowCount = 0
+		}
+
+		if c.windowCount >= limits.QuotaRequests {
+			reason = RequestRejectedQuota
+		}
+	}
+
+	c.lastSeen = now
+	if reason != "" {
+		if s.rejectedRequests == nil {
+			s.rejectedRequests = map[rejectedKey]uint64{}
+		}
+
+		s.rejectedRequests[rejectedKey{client: client, reason: reason}]++
+
+		return reason
+	}
+
+	if limits.RateRequests > 0 {
+		c.tokens--
+	}
+
+	c.windowCount++
+
+	return ""
+}
+
+// RejectedRequests returns the number of requests rejected by the request limits, by client and reason.
+// Only the clients still tracked by the request limits are included.
+func (s *Handler) RejectedRequests() []RejectedRequests {
+	s.requestLimitsLock.Lock()
+	defer s.requestLimitsLock.Unlock()
+
+	rejected := make([]RejectedRequests, 0, len(s.rejectedRequests))
+	for key, count := range s.rejectedRequests {
+		rejected = append(rejected, RejectedRequests{Client: key.client, Reason: key.reason, Count: count})
+	}
+
+	sort.Slice(rejected, func(i, j int) bool {
+		if rejected[i].Client != rejected[j].Client {
+			return rejected[i].Client < rejected[j].Client
+		}
+
+		return rejected[i].Reason < rejected[j].Reason
+	})
+
+	return rejected
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type requestLimitsSuite struct {
+	suite.Suite
+}
+
+func TestRequestLimitsSuite(t *testing.T) {
+	suite.Run(t, new(requestLimitsSuite))
+}
+
+func (s *requestLimitsSuite) Test_ParseRequestLimit() {
+	count, period, err := ParseRequestLimit("100/1m")
+	s.NoError(err)
+	s.Equal(100, count)
+	s.Equal(time.Minute, period)
+
+	for _, value := range []string{"", "100", "0/1m", "-1/1m", "a/1m", "100/", "100/1ms", "100/forever"} {
+		_, _, err := ParseRequestLimit(value)
+		s.Error(err, "Value %q must be rejected", value)
+	}
+}
+
+func (s *requestLimitsSuite) Test_AllowRequestRate() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sh := &Handler{}
+
+	s.T().Log("Requests are allowed when no limits are set")
+	for range 10 {
+		s.Empty(sh.AllowRequest("10.0.0.1", now))
+	}
+
+	sh.SetRequestLimits(RequestLimits{RateRequests: 2, RatePeriod: 2 * time.Second}, now)
+
+	s.T().Log("A burst of up to the rate is allowed")
+	s.Empty(sh.AllowRequest("10.0.0.1", now))
+	s.Empty(sh.AllowRequest("10.0.0.1", now))
+	s.Equal(RequestRejectedRate, sh.AllowRequest("10.0.0.1", now))
+
+	s.T().Log("Each client has its own limit")
+	s.Empty(sh.AllowRequest("10.0.0.2", now))
+
+	s.T().Log("The rate limit refills over time")
+	now = now.Add(time.Second)
+	s.Empty(sh.AllowRequest("10.0.0.1", now))
+	s.Equal(RequestRejectedRate, sh.AllowRequest("10.0.0.1", now))
+
+	s.Equal([]RejectedRequests{{Client: "10.0.0.1", Reason: RequestRejectedRate, Count: 2}}, sh.RejectedRequests())
+}
+
+func (s *requestLimitsSuite) Test_AllowRequestQuota() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sh := &Handler{}
+	sh.SetRequestLimits(RequestLimits{QuotaRequests: 3, QuotaPeriod: time.Hour}, now)
+
+	for range 3 {
+		s.Empty(sh.AllowRequest("10.0.0.1", now))
+		now = now.Add(time.Minute)
+	}
+
+	s.Equal(RequestRejectedQuota, sh.AllowRequest("10.0.0.1", now))
+
+	s.T().Log("The quota resets once the window is over")
+	now = now.Add(time.Hour)
+	s.Empty(sh.AllowRequest("10.0.0.1", now))
+
+	s.T().Log("Rejected requests don't count against the quota")
+	sh.SetRequestLimits(RequestLimits{RateRequests: 1, RatePeriod: time.Hour, QuotaRequests: 2, QuotaPeriod: time.Hour}, now)
+	s.Empty(sh.AllowRequest("10.0.0.2", now))
+	s.Equal(RequestRejectedRate, sh.AllowRequest("10.0.0.2", now))
+	now = now.Add(time.Hour)
+	s.Empty(sh.AllowRequest("10.0.0.2", now))
+
+	s.Equal([]RejectedRequests{
+		{Client: "10.0.0.1", Reason: RequestRejectedQuota, Count: 1},
+		{Client: "10.0.0.2", Reason: RequestRejectedRate, Count: 1},
+	}, sh.RejectedRequests())
+}
+
+func (s *requestLimitsSuite) Test_SetRequestLimitsPrunesClients() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sh := &Handler{}
+	limits := RequestLimits{RateRequests: 1, RatePeriod: time.Minute}
+	sh.SetRequestLimits(limits, now)
+
+	s.Empty(sh.AllowRequest("10.0.0.1", now))
+	s.Equal(RequestRejectedRate, sh.AllowRequest("10.0.0.1", now))
+	sh.SetRequestLimits(limits, now.Add(time.Second))
+	s.Len(sh.requestClients, 1)
+	s.Len(sh.RejectedRequests(), 1)
+
+	// The rejected requests of forgotten clients are forgotten too.
+	sh.SetRequestLimits(limits, now.Add(2*time.Minute))
+	s.Empty(sh.requestClients)
+	s.Empty(sh.RejectedRequests())
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lxd/shared/validate"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// ResourceNames are the names of the storage pools and networks set up by MicroCloud.
+type ResourceNames struct {
+	LocalPool     string
+	RemotePool    string
+	RemoteFSPool  string
+	FanNetwork    string
+	OVNNetwork    string
+	UplinkNetwork string
+}
+
+// DefaultResourceNames returns the names of the storage pools and networks MicroCloud sets up unless configured otherwise.
+func DefaultResourceNames() ResourceNames {
+	return ResourceNames{
+		LocalPool:     DefaultZFSPool,
+		RemotePool:    DefaultCephPool,
+		RemoteFSPool:  DefaultCephFSPool,
+		FanNetwork:    DefaultFANNetwork,
+		OVNNetwork:    DefaultOVNNetwork,
+		UplinkNetwork: DefaultUplinkNetwork,
+	}
+}
+
+// configKeys maps the MicroCloud daemon configuration keys to the names they set.
+func (n *ResourceNames) configKeys() map[string]*string {
+	return map[string]*string{
+		types.ConfigLocalPoolName:     &n.LocalPool,
+		types.ConfigRemotePoolName:    &n.RemotePool,
+		types.ConfigRemoteFSPoolName:  &n.RemoteFSPool,
+		types.ConfigFanNetworkName:    &n.FanNetwork,
+		types.ConfigOVNNetworkName:    &n.OVNNetwork,
+		types.ConfigUplinkNetworkName: &n.UplinkNetwork,
+	}
+}
+
+// ResourceNamesFromConfig returns the names of the storage pools and networks set up by MicroCloud,
+// with the names set in the given MicroCloud daemon configuration replacing the defaults.
+func ResourceNamesFromConfig(config map[string]string) ResourceNames {
+	names := DefaultResourceNames()
+	for key, name := range names.configKeys() {
+		if config[key] != "" {
+			*name = config[key]
+		}
+	}
+
+	return names
+}
+
+// Config returns the MicroCloud daemon configuration recording the names which differ from the defaults.
+func (n ResourceNames) Config() map[string]string {
+	defaultNames := DefaultResourceNames()
+	defaults := defaultNames.configKeys()
+	config := map[string]string{}
+	for key, name := range n.configKeys() {
+		if *name != *defaults[key] {
+			config[key] = *name
+		}
+	}
+
+	return config
+}
+
+// Validate checks the storage pool and network names are valid, and distinct from each other.
+func (n ResourceNames) Validate() error {
+	pools := []string{n.LocalPool, n.RemotePool, n.RemoteFSPool}
+	for _, name := range pools {
+		err := ValidateStoragePoolName(name)
+		if err != nil {
+			return fmt.Errorf("Invalid storage pool name %q: %w", name, err)
+		}
+	}
+
+	networks := []string{n.FanNetwork, n.OVNNetwork, n.UplinkNetwork}
+	for _, name := range networks {
+		err := validate.IsInterfaceName(name)
+		if err != nil {
+			return fmt.Errorf("Invalid network name %q: %w", name, err)
+		}
+	}
+
+	for _, names := range [][]string{pools, networks} {
+		seen := map[string]bool{}
+		for _, name := range names {
+			if seen[name] {
+				return fmt.Errorf("Name %q is used more than once", name)
+			}
+
+			seen[name] = true
+		}
+	}
+
+	return nil
+}
+
+// ValidateStoragePoolName checks the given name can be used for an LXD storage pool.
+func ValidateStoragePoolName(name string) error {
+	if name == "" {
+		return errors.New("Name is required")
+	}
+
+	if name == "." || name == ".." || strings.ContainsAny(name, "/ ") {
+		return errors.New(`Name can't be "." or "..", or contain slashes or spaces`)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type resourceNamesSuite struct {
+	suite.Suite
+}
+
+func TestResourceNamesSuite(t *testing.T) {
+	suite.Run(t, new(resourceNamesSuite))
+}
+
+func (s *resourceNamesSuite) Test_resourceNamesConfig() {
+	s.Equal(DefaultResourceNames(), ResourceNamesFromConfig(nil))
+	s.Empty(DefaultResourceNames().Config())
+
+	names := ResourceNamesFromConfig(map[string]string{types.ConfigLocalPoolName: "fast", types.ConfigOVNNetworkName: "ovn0"})
+	s.Equal("fast", names.LocalPool)
+	s.Equal(DefaultCephPool, names.RemotePool)
+	s.Equal("ovn0", names.OVNNetwork)
+	s.Equal(map[string]string{types.ConfigLocalPoolName: "fast", types.ConfigOVNNetworkName: "ovn0"}, names.Config())
+}
+
+func (s *resourceNamesSuite) Test_resourceNamesValidate() {
+	cases := []struct {
+		desc  string
+		names func(names *ResourceNames)
+		err   bool
+	}{
+		{
+			desc:  "Default names",
+			names: func(names *ResourceNames) {},
+		},
+		{
+			desc:  "Custom names",
+			names: func(names *ResourceNames) { names.LocalPool = "fast"; names.FanNetwork = "fan0" },
+		},
+		{
+			desc:  "Empty storage pool name",
+			names: func(names *ResourceNames) { names.RemotePool = "" },
+			err:   true,
+		},
+		{
+			desc:  "Storage pool name with a slash",
+			names: func(names *ResourceNames) { names.LocalPool = "local/zfs" },
+			err:   true,
+		},
+		{
+			desc:  "Network name longer than an interface name",
+			names: func(names *ResourceNames) { names.FanNetwork = "averyverylongfannetwork" },
+			err:   true,
+		},
+		{
+			desc:  "Duplicate storage pool names",
+			names: func(names *ResourceNames) { names.RemotePool = names.LocalPool },
+			err:   true,
+		},
+		{
+			desc:  "Storage pool and network with the same name",
+			names: func(names *ResourceNames) { names.LocalPool = "shared"; names.OVNNetwork = "shared" },
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		names := DefaultResourceNames()
+		c.names(&names)
+		err := names.Validate()
+		if c.err {
+			s.Error(err)
+		} else {
+			s.NoError(err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// RetryPolicy is how the calls to the service APIs are retried when they fail with a transient error.
+type RetryPolicy struct {
+	// Attempts is the number of times a call is attempted, including the first one.
+	Attempts int
+
+	// InitialDelay is the delay before the first retry, doubling with each further retry up to MaxDelay.
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of the handlers created with NewHandler.
+// It covers the few seconds a service API is down while its snap restarts.
+var DefaultRetryPolicy = RetryPolicy{Attempts: 5, InitialDelay: time.Second, MaxDelay: 10 * time.Second}
+
+// delay returns the delay before the given retry, starting at 0 for the first retry.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.InitialDelay
+	for range retry {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+
+	return delay
+}
+
+// IsTransientError returns whether the error is likely to go away on its own, such as when the service API is restarting.
+// Only errors raised before the request was handled are transient, so retrying never applies a request twice.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// The unix socket of a service is missing or not listening while its daemon restarts.
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
+
+	return api.StatusErrorCheck(err, http.StatusServiceUnavailable)
+}
+
+// Retry calls f until it succeeds, fails with an error which isn't transient, or the attempts of the policy are exhausted.
+// The error of the last attempt is returned.
+func Retry(ctx context.Context, policy RetryPolicy, f func() error) error {
+	_, err := RetryValue(ctx, policy, func() (struct{}, error) {
+		return struct{}{}, f()
+	})
+
+	return err
+}
+
+// RetryValue is like Retry, for functions returning a value.
+func RetryValue[T any](ctx context.Context, policy RetryPolicy, f func() (T, error)) (T, error) {
+	for retry := 0; ; retry++ {
+		value, err := f()
+		if err == nil || retry+1 >= policy.Attempts || !IsTransientError(err) {
+			return value, err
+		}
+
+		delay := policy.delay(retry)
+		logger.Debug("Retrying after transient error", logger.Ctx{"err": err, "retry": retry + 1, "delay": delay})
+
+		select {
+		case <-ctx.Done():
+			return value, err
+		case <-time.After(delay):
+		}
+	}
+}
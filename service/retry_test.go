@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type retrySuite struct {
+	suite.Suite
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(retrySuite))
+}
+
+func (s *retrySuite) Test_isTransientError() {
+	s.False(IsTransientError(nil))
+	s.False(IsTransientError(errors.New("Invalid token")))
+	s.False(IsTransientError(api.StatusErrorf(http.StatusBadRequest, "Invalid request")))
+
+	refused := &net.OpError{Op: "dial", Net: "unix", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	s.True(IsTransientError(fmt.Errorf("Failed to get version: %w", refused)))
+
+	missing := &net.OpError{Op: "dial", Net: "unix", Err: os.NewSyscallError("connect", syscall.ENOENT)}
+	s.True(IsTransientError(missing))
+
+	s.True(IsTransientError(api.StatusErrorf(http.StatusServiceUnavailable, "Daemon not yet initialized")))
+}
+
+func (s *retrySuite) Test_retryDelay() {
+	policy := RetryPolicy{Attempts: 10, InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+	s.Equal(time.Second, policy.delay(0))
+	s.Equal(4*time.Second, policy.delay(2))
+	s.Equal(5*time.Second, policy.delay(3))
+	s.Equal(5*time.Second, policy.delay(100))
+}
+
+func (s *retrySuite) Test_retry() {
+	policy := RetryPolicy{Attempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	refused := fmt.Errorf("Failed to connect: %w", syscall.ECONNREFUSED)
+
+	s.T().Log("Transient errors are retried until the call succeeds")
+	calls := 0
+	value, err := RetryValue(context.Background(), policy, func() (string, error) {
+		calls++
+		if calls < 3 {
+			return "", refused
+		}
+
+		return "1.0", nil
+	})
+	s.NoError(err)
+	s.Equal("1.0", value)
+	s.Equal(3, calls)
+
+	s.T().Log("The last error is returned once the attempts are exhausted")
+	calls = 0
+	err = Retry(context.Background(), policy, func() error {
+		calls++
+		return refused
+	})
+	s.ErrorIs(err, syscall.ECONNREFUSED)
+	s.Equal(3, calls)
+
+	s.T().Log("Other errors are not retried")
+	calls = 0
+	err = Retry(context.Background(), policy, func() error {
+		calls++
+		return errors.New("Invalid token")
+	})
+	s.EqualError(err, "Invalid token")
+	s.Equal(1, calls)
+
+	s.T().Log("Retries stop once the context is cancelled")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = Retry(ctx, RetryPolicy{Attempts: 3, InitialDelay: time.Hour, MaxDelay: time.Hour}, func() error {
+		calls++
+		return refused
+	})
+	s.ErrorIs(err, syscall.ECONNREFUSED)
+	s.Equal(1, calls)
+}
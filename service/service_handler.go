@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/x509"
 	"fmt"
 	"net/http"
@@ -29,6 +30,9 @@ const (
 
 	// CloudMulticastPort is the default MicroCloud multicast discovery port.
 	CloudMulticastPort int64 = 9444
+
+	// NetworkTestPort is the port of the built-in network test server.
+	NetworkTestPort int64 = 9445
 )
 
 // Handler holds a set of stateful services.
@@ -37,11 +41,60 @@ type Handler struct {
 	Name     string
 	Port     int64
 
+	// RetryPolicy is how calls to the service APIs are retried on transient errors.
+	RetryPolicy RetryPolicy
+
 	sessionLock sync.RWMutex
 	Session     *Session
 
 	initMu  sync.RWMutex
 	address string
+
+	networkTestLock sync.Mutex
+	networkTest     *networkTestServer
+
+	underlayLock  sync.Mutex
+	underlayPaths []types.UnderlayPath
+
+	livenessLock sync.Mutex
+	liveness     []types.MemberLiveness
+
+	clocksLock sync.Mutex
+	clocks     []types.MemberClock
+
+	expectedLock   sync.Mutex
+	missingMembers []string
+
+	joinProgressLock sync.Mutex
+	joinProgress     []types.JoinState
+
+	bootLock sync.Mutex
+	boot     []types.BootStep
+
+	alertsLock sync.Mutex
+	alerts     map[AlertCondition]*alertState
+
+	requestLimitsLock sync.Mutex
+	requestLimits     RequestLimits
+	requestClients    map[string]*requestClient
+	rejectedRequests  map[rejectedKey]uint64
+}
+
+// GetVersions returns the version of each service, retrying on transient errors.
+func (s *Handler) GetVersions(ctx context.Context) (map[types.ServiceType]string, error) {
+	versions := make(map[types.ServiceType]string, len(s.Services))
+	for _, service := range s.Services {
+		version, err := RetryValue(ctx, s.RetryPolicy, func() (string, error) {
+			return service.GetVersion(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		versions[service.Type()] = version
+	}
+
+	return versions, nil
 }
 
 // NewHandler creates a new Handler with a client for each of the given services.
@@ -71,10 +124,11 @@ func NewHandler(name string, addr string, stateDir string, services ...types.Ser
 	}
 
 	return &Handler{
-		Services: servicesMap,
-		Name:     name,
-		address:  addr,
-		Port:     CloudPort,
+		Services:    servicesMap,
+		Name:        name,
+		address:     addr,
+		Port:        CloudPort,
+		RetryPolicy: DefaultRetryPolicy,
 	}, nil
 }
 
@@ -1,14 +1,19 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/x509"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/big"
 	"slices"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/canonical/lxd/shared/logger"
 
 	"github.com/canonical/microcloud/microcloud/api/types"
 	cloudClient "github.com/canonical/microcloud/microcloud/client"
@@ -18,24 +23,35 @@ import (
 // AllowedFailedJoinAttempts contains the number of allowed failed session join attempts.
 const AllowedFailedJoinAttempts uint8 = 50
 
+// DetachedSessionTimeout is the time limit for a client to attach to a session which lost its client.
+const DetachedSessionTimeout time.Duration = 10 * time.Minute
+
 // Session represents a local trust establishment session.
 type Session struct {
 	lock           sync.RWMutex
+	id             string
 	passphrase     string
+	details        types.Session
 	trustStore     map[string]x509.Certificate
 	failedAttempts uint8
 	gw             *cloudClient.WebsocketGateway
 	role           types.SessionRole
 	discovery      *multicast.Discovery
+	ctx            context.Context
+	cancel         context.CancelFunc
+
+	// attach hands over the websocket of a client attaching to the session while the session is detached.
+	attach chan *cloudClient.WebsocketGateway
 
 	joinIntentFingerprints []string
+	forwardedIntents       []types.SessionJoinPost
 	joinIntents            chan types.SessionJoinPost
 	exit                   chan bool
 }
 
-// generatePassphrase returns four random words chosen from wordlist.
+// GeneratePassphrase returns four random words chosen from wordlist.
 // The words are separated by space.
-func generatePassphrase() (string, error) {
+func GeneratePassphrase() (string, error) {
 	var randomWords = make([]string, PassphraseWordCount)
 	for i := range PassphraseWordCount {
 		randomNumber, err := rand.Int(rand.Reader, big.NewInt(int64(len(Wordlist))))
@@ -54,17 +70,28 @@ func NewSession(role types.SessionRole, passphrase string, gw *cloudClient.Webso
 	var err error
 
 	if passphrase == "" {
-		passphrase, err = generatePassphrase()
+		passphrase, err = GeneratePassphrase()
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	id := make([]byte, 6)
+	_, err = rand.Read(id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to generate session ID: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return &Session{
+		id:         hex.EncodeToString(id),
 		passphrase: passphrase,
 		trustStore: make(map[string]x509.Certificate),
 		gw:         gw,
 		role:       role,
+		ctx:        ctx,
+		cancel:     cancel,
 
 		joinIntents: make(chan types.SessionJoinPost),
 		exit:        make(chan bool),
@@ -79,6 +106,30 @@ func (s *Session) Passphrase() string {
 	return s.passphrase
 }
 
+// ID returns the identifier of the current trust establishment session.
+func (s *Session) ID() string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.id
+}
+
+// SetDetails records the details the client started the current trust establishment session with.
+func (s *Session) SetDetails(details types.Session) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.details = details
+}
+
+// Details returns the details the client started the current trust establishment session with.
+func (s *Session) Details() types.Session {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.details
+}
+
 // Role returns the role of the current trust establishment session.
 func (s *Session) Role() types.SessionRole {
 	s.lock.RLock()
@@ -88,15 +139,18 @@ func (s *Session) Role() types.SessionRole {
 }
 
 // MulticastDiscovery starts a new multicast discovery listener in the current trust establishment session.
-func (s *Session) MulticastDiscovery(name string, address string, ifaceName string) error {
+// On dual-stack systems, the address of the other IP family is advertised as well.
+func (s *Session) MulticastDiscovery(name string, address string, altAddress string, ifaceName string) error {
 	info := multicast.ServerInfo{
-		Version: multicast.Version,
-		Name:    name,
-		Address: address,
+		Version:    multicast.Version,
+		Name:       name,
+		Address:    address,
+		AltAddress: altAddress,
 	}
 
 	s.discovery = multicast.NewDiscovery(ifaceName, CloudMulticastPort)
-	err := s.discovery.Respond(s.gw.Context(), info)
+	// Bind the responder to the session instead of the client's websocket so it outlives a lost client.
+	err := s.discovery.Respond(s.ctx, info)
 	if err != nil {
 		return err
 	}
@@ -158,6 +212,92 @@ func (s *Session) IntentCh() chan types.SessionJoinPost {
 	return s.joinIntents
 }
 
+// RecordIntent records a join intent forwarded to the client, so it can be forwarded again to a client attaching later.
+func (s *Session) RecordIntent(intent types.SessionJoinPost) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.forwardedIntents = append(s.forwardedIntents, intent)
+}
+
+// ForwardedIntents returns the join intents forwarded to the clients of the current trust establishment session.
+func (s *Session) ForwardedIntents() []types.SessionJoinPost {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return slices.Clone(s.forwardedIntents)
+}
+
+// Detached returns true if the current trust establishment session lost its client and waits for another one to attach.
+func (s *Session) Detached() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.attach != nil
+}
+
+// Detach keeps the current trust establishment session alive after losing its client.
+// Join intents are recorded until a client attaches, and the session is stopped if none does within the given timeout.
+func (s *Session) Detach(timeout time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.gw = nil
+	s.attach = make(chan *cloudClient.WebsocketGateway)
+
+	go func(attach chan *cloudClient.WebsocketGateway) {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		for {
+			select {
+			case intent, ok := <-s.joinIntents:
+				// Session got stopped.
+				if !ok {
+					return
+				}
+
+				s.RecordIntent(intent)
+			case gw := <-attach:
+				s.lock.Lock()
+				s.gw = gw
+				s.lock.Unlock()
+
+				return
+			case <-timer.C:
+				err := s.Stop(errors.New("Timeout waiting for a client to attach to the session"))
+				if err != nil {
+					logger.Error("Failed to stop detached session", logger.Ctx{"err": err})
+				}
+
+				return
+			}
+		}
+	}(s.attach)
+}
+
+// Attach hands the current trust establishment session over to the client of the given websocket,
+// if the session with the given ID is waiting for a client.
+func (s *Session) Attach(id string, gw *cloudClient.WebsocketGateway) error {
+	s.lock.Lock()
+	attach := s.attach
+	if attach == nil || s.id != id {
+		s.lock.Unlock()
+		return fmt.Errorf("No detached session %q", id)
+	}
+
+	// Only a single client can attach.
+	s.attach = nil
+	s.lock.Unlock()
+
+	select {
+	case attach <- gw:
+		return nil
+	case <-s.exit:
+		return fmt.Errorf("Session %q got stopped", id)
+	}
+}
+
 // ExitCh returns a channel which allows waiting on the current trust establishment session.
 func (s *Session) ExitCh() chan bool {
 	return s.exit
@@ -169,8 +309,8 @@ func (s *Session) Stop(cause error) error {
 	defer s.lock.Unlock()
 
 	// If a cause is provided also write it onto the session's websocket
-	// to notify the client.
-	if cause != nil {
+	// to notify the client, unless the session is detached.
+	if cause != nil && s.gw != nil {
 		err := s.gw.WriteClose(cause)
 		if err != nil {
 			return fmt.Errorf("Failed to write session stop cause to websocket: %w", err)
@@ -184,7 +324,10 @@ func (s *Session) Stop(cause error) error {
 		}
 	}
 
+	s.cancel()
+	s.attach = nil
 	s.passphrase = ""
+	s.forwardedIntents = nil
 	s.trustStore = make(map[string]x509.Certificate, 0)
 	s.joinIntentFingerprints = []string{}
 	s.failedAttempts = 0
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type sessionSuite struct {
+	suite.Suite
+}
+
+func TestSessionSuite(t *testing.T) {
+	suite.Run(t, new(sessionSuite))
+}
+
+func (s *sessionSuite) Test_detachAndAttach() {
+	session, err := NewSession(types.SessionInitiating, "", nil)
+	s.Require().NoError(err)
+	s.False(session.Detached())
+
+	s.T().Log("Attaching to a session which didn't lose its client")
+	s.Error(session.Attach(session.ID(), nil))
+
+	session.RecordIntent(types.SessionJoinPost{Name: "micro02"})
+	session.Detach(time.Minute)
+	s.True(session.Detached())
+
+	s.T().Log("Join intents are recorded while detached")
+	session.IntentCh() <- types.SessionJoinPost{Name: "micro03"}
+
+	s.T().Log("Attaching to an unknown session")
+	s.Error(session.Attach("unknown", nil))
+	s.True(session.Detached())
+
+	s.NoError(session.Attach(session.ID(), nil))
+	s.False(session.Detached())
+	s.Equal([]types.SessionJoinPost{{Name: "micro02"}, {Name: "micro03"}}, session.ForwardedIntents())
+
+	s.T().Log("Only a single client can attach")
+	s.Error(session.Attach(session.ID(), nil))
+}
+
+func (s *sessionSuite) Test_detachTimeout() {
+	session, err := NewSession(types.SessionInitiating, "", nil)
+	s.Require().NoError(err)
+
+	session.Detach(time.Millisecond)
+
+	select {
+	case <-session.ExitCh():
+	case <-time.After(time.Second):
+		s.Fail("Detached session wasn't stopped after the timeout")
+	}
+
+	s.Empty(session.Passphrase())
+	s.Error(session.Attach(session.ID(), nil))
+}
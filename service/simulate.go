@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+const (
+	// SimulatedVersion is the version reported by the services of simulated systems.
+	SimulatedVersion = "simulated"
+
+	// SimulatedSubnet is the subnet of the MicroCloud internal network of simulated systems.
+	SimulatedSubnet = "10.0.1.0/24"
+
+	// SimulatedInterface is the interface of simulated systems on the MicroCloud internal network.
+	SimulatedInterface = "enp5s0"
+)
+
+// SimulatedSystemNames returns the names of the given number of simulated systems, micro01, micro02 and so on.
+func SimulatedSystemNames(count int) []string {
+	names := make([]string, 0, count)
+	for i := 1; i <= count; i++ {
+		names = append(names, fmt.Sprintf("micro%02d", i))
+	}
+
+	return names
+}
+
+// SimulatedSystems returns the information of simulated systems with the given names, with addresses in SimulatedSubnet in the same order.
+// Each system has an NVMe disk and two SSDs, an interface on the MicroCloud internal network,
+// an interface on a dedicated network for Ceph and OVN, and an unconfigured interface for the OVN uplink,
+// each on its own 10G network card.
+func SimulatedSystems(names []string) map[string]SystemInformation {
+	systems := make(map[string]SystemInformation, len(names))
+	for i, name := range names {
+		disks := []api.ResourcesStorageDisk{
+			{ID: "nvme0n1", DeviceID: "nvme-Simulated_NVMe_" + name, Model: "Simulated NVMe", Type: "nvme", Size: 1024 * 1024 * 1024 * 1024},
+			{ID: "sda", DeviceID: "scsi-Simulated_SSD_" + name + "_1", Model: "Simulated SSD", Type: "scsi", Size: 2 * 1024 * 1024 * 1024 * 1024},
+			{ID: "sdb", DeviceID: "scsi-Simulated_SSD_" + name + "_2", Model: "Simulated SSD", Type: "scsi", Size: 2 * 1024 * 1024 * 1024 * 1024},
+		}
+
+		availableDisks := make(map[string]api.ResourcesStorageDisk, len(disks))
+		for _, disk := range disks {
+			availableDisks[disk.ID] = disk
+		}
+
+		cards := []api.ResourcesNetworkCard{}
+		for j, iface := range []string{SimulatedInterface, "enp6s0", "enp7s0"} {
+			port := api.ResourcesNetworkCardPort{ID: iface, Address: fmt.Sprintf("00:16:3e:00:%02x:%02x", i+1, j+5), LinkDetected: true, LinkSpeed: 10000}
+			cards = append(cards, api.ResourcesNetworkCard{Driver: "virtio_net", PCIAddress: fmt.Sprintf("0000:%02x:00.0", j+5), Ports: []api.ResourcesNetworkCardPort{port}})
+		}
+
+		address := fmt.Sprintf("10.0.1.%d", 11+i)
+		dedicated := map[string]DedicatedInterface{
+			SimulatedInterface: {Type: "physical", Network: api.Network{Name: SimulatedInterface, Type: "physical"}, Addresses: []string{address + "/24"}},
+			"enp6s0":           {Type: "physical", Network: api.Network{Name: "enp6s0", Type: "physical"}, Addresses: []string{fmt.Sprintf("10.0.2.%d/24", 11+i)}},
+		}
+
+		systems[name] = SystemInformation{
+			ExistingServices: map[types.ServiceType]map[string]string{},
+			ClusterName:      name,
+			ClusterAddress:   address,
+			Resources: &api.Resources{
+				CPU:     api.ResourcesCPU{Total: 16},
+				Memory:  api.ResourcesMemory{Total: 64 * 1024 * 1024 * 1024},
+				Storage: api.ResourcesStorage{Disks: disks, Total: uint64(len(disks))},
+				Network: api.ResourcesNetwork{Cards: cards, Total: uint64(len(cards))},
+			},
+			AvailableDisks:                availableDisks,
+			AvailableUplinkInterfaces:     map[string]api.Network{"enp7s0": {Name: "enp7s0", Type: "physical"}},
+			AvailableCephInterfaces:       dedicated,
+			AvailableOVNInterfaces:        dedicated,
+			AvailableMicroCloudInterfaces: dedicated,
+			LXDLocalConfig:                map[string]any{},
+			LXDConfig:                     map[string]any{},
+			names:                         DefaultResourceNames(),
+		}
+	}
+
+	return systems
+}
+
+// NewSimulatedHandler returns a handler for the given services of a simulated system.
+// The services don't connect to their daemons, and only answer the questions asked while setting up MicroCloud,
+// as services which are up to date and not yet set up.
+func NewSimulatedHandler(name string, addr string, services ...types.ServiceType) (*Handler, error) {
+	servicesMap := make(map[types.ServiceType]Service, len(services))
+	for _, serviceType := range services {
+		switch serviceType {
+		case types.MicroCloud:
+			servicesMap[serviceType] = &CloudService{name: name, address: addr, port: CloudPort, config: map[string]string{}}
+		case types.MicroCeph:
+			servicesMap[serviceType] = &CephService{name: name, address: addr, port: CephPort, config: map[string]string{}}
+		case types.MicroOVN:
+			servicesMap[serviceType] = &OVNService{name: name, address: addr, port: OVNPort, config: map[string]string{}, simulated: true}
+		case types.LXD:
+			servicesMap[serviceType] = &LXDService{name: name, address: addr, port: LXDPort, config: map[string]string{}, names: DefaultResourceNames(), simulated: true}
+		default:
+			return nil, fmt.Errorf("Invalid service type: %q", serviceType)
+		}
+	}
+
+	return &Handler{
+		Services:    servicesMap,
+		Name:        name,
+		address:     addr,
+		Port:        CloudPort,
+		RetryPolicy: DefaultRetryPolicy,
+	}, nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// snapdSocket is the path to the unix socket of the snapd REST API.
+const snapdSocket = "/run/snapd.socket"
+
+// snapChangePollInterval is how often the status of a snapd change is checked.
+const snapChangePollInterval = time.Second
+
+// RestartableServices are the services which can be restarted by MicroCloud, with the names of their snaps.
+// MicroCloud itself is left out, as its daemon can't wait for its own restart.
+var RestartableServices = map[types.ServiceType]string{
+	types.LXD:       "lxd",
+	types.MicroCeph: "microceph",
+	types.MicroOVN:  "microovn",
+}
+
+// snapdResponse is the envelope of the responses of the snapd REST API.
+type snapdResponse struct {
+	Type   string          `json:"type"`
+	Change string          `json:"change"`
+	Result json.RawMessage `json:"result"`
+}
+
+// snapdChange is the status of an asynchronous snapd change.
+type snapdChange struct {
+	Status string `json:"status"`
+	Ready  bool   `json:"ready"`
+	Err    string `json:"err"`
+}
+
+// snapdQuery sends a request to the snapd REST API, and returns its response.
+func snapdQuery(ctx context.Context, method string, path string, body any) (*snapdResponse, error) {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", snapdSocket)
+			},
+		},
+	}
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&reqBody).Encode(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, "http://snapd"+path, &reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to reach snapd: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	result := &snapdResponse{}
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the snapd response: %w", err)
+	}
+
+	if result.Type == "error" {
+		snapdErr := struct {
+			Message string `json:"message"`
+		}{}
+
+		_ = json.Unmarshal(result.Result, &snapdErr)
+
+		return nil, fmt.Errorf("snapd returned %q: %s", resp.Status, snapdErr.Message)
+	}
+
+	return result, nil
+}
+
+// waitSnapChange waits for snapd to finish the given asynchronous change.
+func waitSnapChange(ctx context.Context, id string) error {
+	for {
+		resp, err := snapdQuery(ctx, http.MethodGet, "/v2/changes/"+id, nil)
+		if err != nil {
+			return err
+		}
+
+		change := snapdChange{}
+		err = json.Unmarshal(resp.Result, &change)
+		if err != nil {
+			return fmt.Errorf("Failed to parse the snapd change: %w", err)
+		}
+
+		if change.Ready {
+			if change.Err != "" {
+				return errors.New(change.Err)
+			}
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(snapChangePollInterval):
+		}
+	}
+}
+
+// RestartSnap restarts all services of the given snap on the local system, and waits for snapd to finish the restart.
+func RestartSnap(ctx context.Context, name string) error {
+	resp, err := snapdQuery(ctx, http.MethodPost, "/v2/apps", map[string]any{"action": "restart", "names": []string{name}})
+	if err != nil {
+		return fmt.Errorf("Failed to restart snap %q: %w", name, err)
+	}
+
+	err = waitSnapChange(ctx, resp.Change)
+	if err != nil {
+		return fmt.Errorf("Failed to restart snap %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// RefreshSnap refreshes the given snap on the local system, optionally to another channel, and waits for snapd to finish the refresh.
+// An explicit refresh isn't blocked by the auto-refresh holds set by MicroCloud.
+func RefreshSnap(ctx context.Context, name string, channel string) error {
+	body := map[string]any{"action": "refresh"}
+	if channel != "" {
+		body["channel"] = channel
+	}
+
+	resp, err := snapdQuery(ctx, http.MethodPost, "/v2/snaps/"+name, body)
+	if err != nil {
+		return fmt.Errorf("Failed to refresh snap %q: %w", name, err)
+	}
+
+	err = waitSnapChange(ctx, resp.Change)
+	if err != nil {
+		return fmt.Errorf("Failed to refresh snap %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// HoldSnapRefreshes holds the automatic refreshes of the given snaps on the local system indefinitely, or releases the holds.
+func HoldSnapRefreshes(ctx context.Context, names []string, hold bool) error {
+	body := map[string]any{"action": "unhold", "snaps": names}
+	if hold {
+		body = map[string]any{"action": "hold", "snaps": names, "hold-level": "auto-refresh", "time": "forever"}
+	}
+
+	resp, err := snapdQuery(ctx, http.MethodPost, "/v2/snaps", body)
+	if err != nil {
+		return fmt.Errorf("Failed to %s the refreshes of snaps %s: %w", body["action"], strings.Join(names, ", "), err)
+	}
+
+	if resp.Change == "" {
+		return nil
+	}
+
+	err = waitSnapChange(ctx, resp.Change)
+	if err != nil {
+		return fmt.Errorf("Failed to %s the refreshes of snaps %s: %w", body["action"], strings.Join(names, ", "), err)
+	}
+
+	return nil
+}
+
+// HeldSnaps returns the snaps of MicroCloud and the given services, whose automatic refreshes are held when configured.
+func HeldSnaps(services map[types.ServiceType]Service) []string {
+	names := []string{"microcloud"}
+	for serviceType, snap := range RestartableServices {
+		if services[serviceType] != nil {
+			names = append(names, snap)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// WaitReady waits for the given service to be initialized and ready again, retrying with the boot backoff.
+func WaitReady(ctx context.Context, s Service) error {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		ready, err := s.IsInitialized(ctx)
+		if err == nil && ready {
+			return nil
+		}
+
+		lastErr = err
+		if lastErr == nil {
+			lastErr = fmt.Errorf("%s is not initialized", s.Type())
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Timed out waiting for %s to get ready: %w", s.Type(), lastErr)
+		case <-time.After(BootRetryDelay(attempt)):
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type snapSuite struct {
+	suite.Suite
+}
+
+func TestSnapSuite(t *testing.T) {
+	suite.Run(t, new(snapSuite))
+}
+
+func (s *snapSuite) Test_heldSnaps() {
+	s.Equal([]string{"lxd", "microcloud"}, HeldSnaps(map[types.ServiceType]Service{types.MicroCloud: &CloudService{}, types.LXD: &LXDService{}}))
+
+	services := map[types.ServiceType]Service{types.MicroCloud: &CloudService{}, types.LXD: &LXDService{}, types.MicroCeph: &CephService{}, types.MicroOVN: &OVNService{}}
+	s.Equal([]string{"lxd", "microceph", "microcloud", "microovn"}, HeldSnaps(services))
+}
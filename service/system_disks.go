@@ -0,0 +1,214 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+const (
+	// SystemDiskRoot is the use of a disk holding the root filesystem.
+	SystemDiskRoot = "root"
+
+	// SystemDiskBoot is the use of a disk holding the /boot filesystem.
+	SystemDiskBoot = "boot"
+
+	// SystemDiskEFI is the use of a disk holding the EFI system partition.
+	SystemDiskEFI = "efi"
+
+	// SystemDiskSwap is the use of a disk holding an active swap device.
+	SystemDiskSwap = "swap"
+)
+
+// systemDiskUses are the uses of the system disks, in the order they are reported.
+var systemDiskUses = []string{SystemDiskRoot, SystemDiskBoot, SystemDiskEFI, SystemDiskSwap}
+
+// systemMountPoints maps the mount points of the operating system to the use of the disks backing them.
+var systemMountPoints = map[string]string{
+	"/":         SystemDiskRoot,
+	"/boot":     SystemDiskBoot,
+	"/boot/efi": SystemDiskEFI,
+	"/efi":      SystemDiskEFI,
+}
+
+// systemMount is a mount point of the operating system in a mountinfo file.
+type systemMount struct {
+	// device is the device number of the mounted filesystem, as major:minor.
+	device string
+
+	// source is the device the filesystem is mounted from.
+	source string
+
+	// use is the use of the disks backing the filesystem.
+	use string
+}
+
+// parseMountInfo returns the mount points of the operating system in the content of a mountinfo file.
+func parseMountInfo(content string) []systemMount {
+	mounts := []systemMount{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+
+		use, ok := systemMountPoints[fields[4]]
+		if !ok {
+			continue
+		}
+
+		// The optional fields are ended by a separator, followed by the filesystem type and the mount source.
+		source := ""
+		separator := slices.Index(fields, "-")
+		if separator > 0 && len(fields) > separator+2 {
+			source = fields[separator+2]
+		}
+
+		mounts = append(mounts, systemMount{device: fields[2], source: source, use: use})
+	}
+
+	return mounts
+}
+
+// parseSwaps returns the paths of the active swap devices and files in the content of /proc/swaps.
+func parseSwaps(content string) []string {
+	paths := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] == "Filename" {
+			continue
+		}
+
+		paths = append(paths, fields[0])
+	}
+
+	return paths
+}
+
+// blockDeviceNumber returns the device number of the block device at the given path, as major:minor.
+func blockDeviceNumber(path string) (string, error) {
+	var stat unix.Stat_t
+	err := unix.Stat(path, &stat)
+	if err != nil {
+		return "", err
+	}
+
+	if stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return "", fmt.Errorf("%q is not a block device", path)
+	}
+
+	return fmt.Sprintf("%d:%d", unix.Major(uint64(stat.Rdev)), unix.Minor(uint64(stat.Rdev))), nil
+}
+
+// blockDisks returns the names of the whole disks backing the block device with the given name,
+// following partitions, as well as device mapper and RAID devices down to the disks they are built on.
+func blockDisks(sysfs string, name string) []string {
+	path := filepath.Join(sysfs, "class", "block", name)
+	_, err := os.Stat(filepath.Join(path, "partition"))
+	if err == nil {
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return nil
+		}
+
+		return blockDisks(sysfs, filepath.Base(filepath.Dir(target)))
+	}
+
+	slaves, _ := os.ReadDir(filepath.Join(path, "slaves"))
+	if len(slaves) == 0 {
+		return []string{name}
+	}
+
+	disks := []string{}
+	for _, slave := range slaves {
+		for _, disk := range blockDisks(sysfs, slave.Name()) {
+			if !slices.Contains(disks, disk) {
+				disks = append(disks, disk)
+			}
+		}
+	}
+
+	return disks
+}
+
+// systemDisks returns the disks backing the given block devices, keyed by device number, along with the uses of each device.
+// Devices missing from sysfs, such as the anonymous devices of network filesystems, are skipped.
+func systemDisks(sysfs string, devices map[string][]string) []types.SystemDisk {
+	uses := map[string][]string{}
+	for device, deviceUses := range devices {
+		target, err := filepath.EvalSymlinks(filepath.Join(sysfs, "dev", "block", device))
+		if err != nil {
+			continue
+		}
+
+		for _, disk := range blockDisks(sysfs, filepath.Base(target)) {
+			for _, use := range deviceUses {
+				if !slices.Contains(uses[disk], use) {
+					uses[disk] = append(uses[disk], use)
+				}
+			}
+		}
+	}
+
+	disks := make([]types.SystemDisk, 0, len(uses))
+	for id, diskUses := range uses {
+		sort.Slice(diskUses, func(i, j int) bool {
+			return slices.Index(systemDiskUses, diskUses[i]) < slices.Index(systemDiskUses, diskUses[j])
+		})
+
+		disks = append(disks, types.SystemDisk{ID: id, Uses: diskUses})
+	}
+
+	sort.Slice(disks, func(i, j int) bool { return disks[i].ID < disks[j].ID })
+
+	return disks
+}
+
+// LocalSystemDisks returns the disks holding the root, boot and EFI system filesystems, and the active swap devices of the local system.
+// The mounts of the host are read from its init process, as the daemon may run in its own mount namespace.
+// Swap files are skipped, as they live on filesystems whose own disks are reported if they belong to the operating system.
+func LocalSystemDisks() ([]types.SystemDisk, error) {
+	content, err := os.ReadFile("/proc/1/mountinfo")
+	if err != nil {
+		content, err = os.ReadFile("/proc/self/mountinfo")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read the mounts: %w", err)
+		}
+	}
+
+	devices := map[string][]string{}
+	for _, mount := range parseMountInfo(string(content)) {
+		device := mount.device
+
+		// Filesystems spanning several devices, like btrfs, report an anonymous device number.
+		if strings.HasPrefix(device, "0:") && strings.HasPrefix(mount.source, "/dev/") {
+			device, err = blockDeviceNumber(mount.source)
+			if err != nil {
+				continue
+			}
+		}
+
+		devices[device] = append(devices[device], mount.use)
+	}
+
+	content, err = os.ReadFile("/proc/swaps")
+	if err == nil {
+		for _, path := range parseSwaps(string(content)) {
+			device, err := blockDeviceNumber(path)
+			if err != nil {
+				continue
+			}
+
+			devices[device] = append(devices[device], SystemDiskSwap)
+		}
+	}
+
+	return systemDisks("/sys", devices), nil
+}
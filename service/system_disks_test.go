@@ -0,0 +1,120 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type systemDisksSuite struct {
+	suite.Suite
+}
+
+func TestSystemDisksSuite(t *testing.T) {
+	suite.Run(t, new(systemDisksSuite))
+}
+
+func (s *systemDisksSuite) Test_parseMountInfo() {
+	content := `22 1 8:2 / / rw,relatime shared:1 - ext4 /dev/sda2 rw
+23 22 0:5 / /dev rw,nosuid shared:2 - devtmpfs udev rw
+24 22 8:1 / /boot/efi rw,relatime shared:3 - vfat /dev/sda1 rw
+25 22 0:32 / /boot rw,relatime shared:4 master:1 - btrfs /dev/sdb1 rw
+26 22 253:0 / /home rw,relatime shared:5 - ext4 /dev/mapper/vg-home rw
+`
+
+	s.Equal([]systemMount{
+		{device: "8:2", source: "/dev/sda2", use: SystemDiskRoot},
+		{device: "8:1", source: "/dev/sda1", use: SystemDiskEFI},
+		{device: "0:32", source: "/dev/sdb1", use: SystemDiskBoot},
+	}, parseMountInfo(content))
+}
+
+func (s *systemDisksSuite) Test_parseSwaps() {
+	content := `Filename				Type		Size		Used		Priority
+/dev/dm-1                               partition	2097148		0		-2
+/swap.img                               file		4194300		0		-3
+`
+
+	s.Equal([]string{"/dev/dm-1", "/swap.img"}, parseSwaps(content))
+}
+
+func (s *systemDisksSuite) Test_systemDisks() {
+	sysfs := s.T().TempDir()
+
+	// blockDevice adds a block device to the sysfs tree, reachable by its name and device number.
+	blockDevice := func(dir string, name string, device string, slaves ...string) {
+		path := filepath.Join(sysfs, "devices", dir)
+		s.Require().NoError(os.MkdirAll(filepath.Join(path, "slaves"), 0755))
+		for _, slave := range slaves {
+			s.Require().NoError(os.WriteFile(filepath.Join(path, "slaves", slave), nil, 0644))
+		}
+
+		for link, target := range map[string]string{filepath.Join("class", "block", name): path, filepath.Join("dev", "block", device): path} {
+			s.Require().NoError(os.MkdirAll(filepath.Join(sysfs, filepath.Dir(link)), 0755))
+			s.Require().NoError(os.Symlink(target, filepath.Join(sysfs, link)))
+		}
+	}
+
+	// partition adds a partition of the given disk to the sysfs tree.
+	partition := func(disk string, name string, device string) {
+		blockDevice(filepath.Join(disk, name), name, device)
+		s.Require().NoError(os.WriteFile(filepath.Join(sysfs, "devices", disk, name, "partition"), nil, 0644))
+	}
+
+	blockDevice("sda", "sda", "8:0")
+	partition("sda", "sda1", "8:1")
+	partition("sda", "sda2", "8:2")
+	blockDevice("sdb", "sdb", "8:16")
+	partition("sdb", "sdb1", "8:17")
+	blockDevice("sdc", "sdc", "8:32")
+	partition("sdc", "sdc1", "8:33")
+	blockDevice("nvme0n1", "nvme0n1", "259:0")
+	blockDevice("md0", "md0", "9:0", "sdb1", "sdc1")
+	blockDevice("dm-0", "dm-0", "253:0", "md0")
+
+	cases := []struct {
+		desc    string
+		devices map[string][]string
+		disks   []types.SystemDisk
+	}{
+		{
+			desc:    "No devices",
+			devices: map[string][]string{},
+			disks:   []types.SystemDisk{},
+		},
+		{
+			desc:    "Partitions of a disk",
+			devices: map[string][]string{"8:2": {SystemDiskRoot}, "8:1": {SystemDiskBoot, SystemDiskEFI}},
+			disks:   []types.SystemDisk{{ID: "sda", Uses: []string{SystemDiskRoot, SystemDiskBoot, SystemDiskEFI}}},
+		},
+		{
+			desc:    "Whole disk",
+			devices: map[string][]string{"259:0": {SystemDiskSwap}},
+			disks:   []types.SystemDisk{{ID: "nvme0n1", Uses: []string{SystemDiskSwap}}},
+		},
+		{
+			desc:    "Logical volume on a RAID device",
+			devices: map[string][]string{"253:0": {SystemDiskRoot}, "8:1": {SystemDiskEFI}},
+			disks: []types.SystemDisk{
+				{ID: "sda", Uses: []string{SystemDiskEFI}},
+				{ID: "sdb", Uses: []string{SystemDiskRoot}},
+				{ID: "sdc", Uses: []string{SystemDiskRoot}},
+			},
+		},
+		{
+			desc:    "Device missing from sysfs",
+			devices: map[string][]string{"0:32": {SystemDiskRoot}},
+			disks:   []types.SystemDisk{},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.disks, systemDisks(sysfs, c.devices))
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sort"
+	"sync"
 
 	"github.com/canonical/lxd/shared/api"
 	cephTypes "github.com/canonical/microceph/microceph/api/types"
@@ -14,6 +16,9 @@ import (
 	"github.com/canonical/microcloud/microcloud/multicast"
 )
 
+// MaxConcurrentSystemQueries is the maximum number of systems from which system information is collected at the same time.
+const MaxConcurrentSystemQueries = 10
+
 // SystemInformation represents all information MicroCloud needs from a system in order to set it up as part of the MicroCloud.
 type SystemInformation struct {
 	// ExistingServices is a map of cluster members for each service currently installed on the system.
@@ -25,9 +30,16 @@ type SystemInformation struct {
 	// ClusterAddress is the default cluster address used for MicroCloud.
 	ClusterAddress string
 
+	// Resources is the full set of LXD resources of the system at the time of collection.
+	Resources *api.Resources
+
 	// AvailableDisks is the list of disks available for use on the system.
 	AvailableDisks map[string]api.ResourcesStorageDisk
 
+	// SystemDisks are the uses of the disks holding the operating system, keyed by disk ID.
+	// They are left out of the available disks, unless included with CollectOptions.
+	SystemDisks map[string][]string
+
 	// AvailableUplinkInterfaces is the list of networks that can be used for the OVN uplink network.
 	AvailableUplinkInterfaces map[string]api.Network
 
@@ -49,27 +61,46 @@ type SystemInformation struct {
 	// CephConfig is the MicroCeph configuration on this system.
 	CephConfig map[string]string
 
-	// existingLocalPool is the current storage pool named "local" on this system.
+	// Platform is the environment the system runs in, such as a virtual machine. Nil if the system can't report it.
+	Platform *types.Platform
+
+	// ServiceVersions are the version and API extensions of each service installed on the system. Nil if the system can't report them.
+	ServiceVersions map[types.ServiceType]types.ServiceVersion
+
+	// existingLocalPool is the current local storage pool on this system.
 	existingLocalPool *api.StoragePool
 
-	// existingRemotePool is the current storage pool named "remote" on this system.
+	// existingRemotePool is the current remote storage pool on this system.
 	existingRemotePool *api.StoragePool
 
-	// existingRemoteFSPool is the current storage pool named "remote-fs" on this system.
+	// existingRemoteFSPool is the current remote-fs storage pool on this system.
 	existingRemoteFSPool *api.StoragePool
 
-	// existingFanNetwork is the current network named "lxdfan0" on this system.
+	// existingFanNetwork is the current Ubuntu Fan network on this system.
 	existingFanNetwork *api.Network
 
-	// existingOVNNetwork is the current network named "default" on this system.
+	// existingOVNNetwork is the current OVN network on this system.
 	existingOVNNetwork *api.Network
 
-	// existingUplinkNetwork is the current network named "UPLINK" on this system.
+	// existingUplinkNetwork is the current OVN uplink network on this system.
 	existingUplinkNetwork *api.Network
+
+	// names are the names of the storage pools and networks looked up on this system.
+	names ResourceNames
+}
+
+// CollectOptions limits the system information collected to what is needed by the services being set up.
+// The zero value collects all system information.
+type CollectOptions struct {
+	// SkipStorage skips fetching the resources and available disks of the system, when no storage is set up.
+	SkipStorage bool
+
+	// IncludeSystemDisks offers the disks holding the operating system along with the other available disks.
+	IncludeSystemDisks bool
 }
 
 // CollectSystemInformation fetches the current cluster information of the system specified by the connection info.
-func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo multicast.ServerInfo) (*SystemInformation, error) {
+func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo multicast.ServerInfo, opts CollectOptions) (*SystemInformation, error) {
 	if connectInfo.Name == "" || connectInfo.Address == "" {
 		return nil, errors.New("Connection information is incomplete")
 	}
@@ -81,6 +112,7 @@ func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo mul
 		ClusterName:                   connectInfo.Name,
 		ClusterAddress:                connectInfo.Address,
 		AvailableDisks:                map[string]api.ResourcesStorageDisk{},
+		SystemDisks:                   map[string][]string{},
 		AvailableUplinkInterfaces:     map[string]api.Network{},
 		AvailableCephInterfaces:       map[string]DedicatedInterface{},
 		AvailableOVNInterfaces:        map[string]DedicatedInterface{},
@@ -95,23 +127,41 @@ func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo mul
 
 	var allResources *api.Resources
 	lxd := sh.Services[types.LXD].(*LXDService)
-	if localSystem {
-		allResources, err = lxd.GetResources(ctx, s.ClusterName, "", nil)
-	} else {
-		allResources, err = lxd.GetResources(ctx, s.ClusterName, s.ClusterAddress, connectInfo.Certificate)
-	}
+	if !opts.SkipStorage {
+		if localSystem {
+			allResources, err = lxd.GetResources(ctx, s.ClusterName, "", nil)
+		} else {
+			allResources, err = lxd.GetResources(ctx, s.ClusterName, s.ClusterAddress, connectInfo.Certificate)
+		}
 
-	if err != nil {
-		return nil, fmt.Errorf("Failed to get system resources of peer %q: %w", s.ClusterName, err)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get system resources of peer %q: %w", s.ClusterName, err)
+		}
+
+		var systemDisks []types.SystemDisk
+		if localSystem {
+			systemDisks, err = LocalSystemDisks()
+		} else {
+			systemDisks, err = sh.Services[types.MicroCloud].(*CloudService).RemoteSystemDisks(ctx, connectInfo.Certificate, s.ClusterAddress)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the system disks of %q: %w", s.ClusterName, err)
+		}
+
+		for _, disk := range systemDisks {
+			s.SystemDisks[disk.ID] = disk.Uses
+		}
 	}
 
 	var microceph *CephService
-
-	// Fetch disks which are already used for remote storage.
-	var usedCephDisks cephTypes.Disks
 	if len(s.ExistingServices[types.MicroCeph]) > 0 {
 		microceph = sh.Services[types.MicroCeph].(*CephService)
+	}
 
+	// Fetch disks which are already used for remote storage.
+	var usedCephDisks cephTypes.Disks
+	if microceph != nil && !opts.SkipStorage {
 		if localSystem {
 			usedCephDisks, err = microceph.GetDisks(ctx, "", nil)
 		} else {
@@ -123,8 +173,14 @@ func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo mul
 		}
 	}
 
+	s.Resources = allResources
 	if allResources != nil {
 		for _, disk := range allResources.Storage.Disks {
+			// Exclude the disks holding the operating system, even if they have no partitions, like LVM or RAID members.
+			if s.SystemDisks[disk.ID] != nil && !opts.IncludeSystemDisks {
+				continue
+			}
+
 			// Exclude non-pristine disks with partitions.
 			// Disks already used for local storage (zfs) contain a partition and are therefore excluded by this check.
 			if len(disk.Partitions) != 0 {
@@ -163,18 +219,20 @@ func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo mul
 	s.AvailableOVNInterfaces = dedicatedInterfaces
 	s.AvailableMicroCloudInterfaces = dedicatedInterfaces
 
+	names := lxd.ResourceNames()
+	s.names = names
 	for _, network := range allNets {
-		if network.Name == DefaultFANNetwork {
+		if network.Name == names.FanNetwork {
 			s.existingFanNetwork = &network
 			continue
 		}
 
-		if network.Name == DefaultOVNNetwork {
+		if network.Name == names.OVNNetwork {
 			s.existingOVNNetwork = &network
 			continue
 		}
 
-		if network.Name == DefaultUplinkNetwork {
+		if network.Name == names.UplinkNetwork {
 			s.existingUplinkNetwork = &network
 			continue
 		}
@@ -185,25 +243,25 @@ func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo mul
 		return nil, fmt.Errorf("Failed to get storage pools on %q: %w", s.ClusterName, err)
 	}
 
-	pool, ok := pools[DefaultZFSPool]
+	pool, ok := pools[names.LocalPool]
 	if ok {
 		poolCopy := pool
 		s.existingLocalPool = &poolCopy
 	}
 
-	pool, ok = pools[DefaultCephPool]
+	pool, ok = pools[names.RemotePool]
 	if ok {
 		poolCopy := pool
 		s.existingRemotePool = &poolCopy
 	}
 
-	pool, ok = pools[DefaultCephFSPool]
+	pool, ok = pools[names.RemoteFSPool]
 	if ok {
 		poolCopy := pool
 		s.existingRemoteFSPool = &poolCopy
 	}
 
-	if len(s.ExistingServices[types.MicroCeph]) > 0 {
+	if microceph != nil {
 		if localSystem {
 			s.CephConfig, err = microceph.ClusterConfig(ctx, "", nil)
 		} else {
@@ -225,9 +283,78 @@ func (sh *Handler) CollectSystemInformation(ctx context.Context, connectInfo mul
 		return nil, fmt.Errorf("Failed to get LXD configuration on %q: %w", s.ClusterName, err)
 	}
 
+	if localSystem {
+		platform := LocalPlatform()
+		s.Platform = &platform
+	} else {
+		s.Platform, err = sh.Services[types.MicroCloud].(*CloudService).RemotePlatform(ctx, connectInfo.Certificate, s.ClusterAddress)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the platform of %q: %w", s.ClusterName, err)
+		}
+	}
+
+	if localSystem {
+		s.ServiceVersions = sh.ServiceVersions(ctx)
+	} else {
+		s.ServiceVersions, err = sh.Services[types.MicroCloud].(*CloudService).RemoteServiceVersions(ctx, connectInfo.Certificate, s.ClusterAddress)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the service versions of %q: %w", s.ClusterName, err)
+		}
+	}
+
 	return s, nil
 }
 
+// CollectSystemInformationConcurrent fetches the system information of each of the given systems.
+// At most MaxConcurrentSystemQueries systems are queried at the same time.
+// Any errors are aggregated per system, and the information of the successful systems is still returned.
+func (sh *Handler) CollectSystemInformationConcurrent(ctx context.Context, systems []multicast.ServerInfo, opts CollectOptions) (map[string]*SystemInformation, error) {
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	pool := make(chan struct{}, MaxConcurrentSystemQueries)
+	infos := make(map[string]*SystemInformation, len(systems))
+	errs := make(map[string]error)
+	for _, system := range systems {
+		wg.Add(1)
+		go func(system multicast.ServerInfo) {
+			defer wg.Done()
+
+			pool <- struct{}{}
+			defer func() { <-pool }()
+
+			info, err := sh.CollectSystemInformation(ctx, system, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[system.Name] = err
+				return
+			}
+
+			infos[system.Name] = info
+		}(system)
+	}
+
+	wg.Wait()
+
+	if len(errs) == 0 {
+		return infos, nil
+	}
+
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	errList := make([]error, 0, len(names))
+	for _, name := range names {
+		errList = append(errList, fmt.Errorf("Failed to collect system information from %q: %w", name, errs[name]))
+	}
+
+	return infos, errors.Join(errList...)
+}
+
 // GetExistingClusters checks against the services reachable by the specified ServerInfo,
 // and returns a map of cluster members for each service supported by the Handler.
 // If a service is not clustered, its map will be nil.
@@ -354,6 +481,89 @@ func (s *SystemInformation) SupportsFANNetwork(checkUsable bool) (hasNet bool, s
 	return true, false, nil
 }
 
+// Conflict is an existing LXD storage pool or network, named like one MicroCloud sets up but unusable by MicroCloud.
+type Conflict struct {
+	// Entity is the kind of the conflicting resource, either "storage-pool" or "network".
+	Entity string
+
+	// Name of the conflicting resource.
+	Name string
+
+	// Reason the resource can't be used by MicroCloud.
+	Reason string
+}
+
+// Renamable returns whether the conflicting resource can be renamed out of the way.
+// LXD doesn't support renaming storage pools.
+func (c Conflict) Renamable() bool {
+	return c.Entity == "network"
+}
+
+// poolConflict returns the conflict of an existing storage pool with the one MicroCloud sets up with the given driver, if any.
+func poolConflict(pool *api.StoragePool, driver string) *Conflict {
+	if pool == nil {
+		return nil
+	}
+
+	if pool.Driver != driver {
+		return &Conflict{Entity: "storage-pool", Name: pool.Name, Reason: fmt.Sprintf("its driver is %q instead of %q", pool.Driver, driver)}
+	}
+
+	if pool.Status != "Created" {
+		return &Conflict{Entity: "storage-pool", Name: pool.Name, Reason: fmt.Sprintf("its status is %q", pool.Status)}
+	}
+
+	return nil
+}
+
+// networkConflict returns the conflict of an existing network with the one MicroCloud sets up with the given type, if any.
+func networkConflict(network *api.Network, networkType string) *Conflict {
+	if network == nil {
+		return nil
+	}
+
+	if network.Type != networkType {
+		return &Conflict{Entity: "network", Name: network.Name, Reason: fmt.Sprintf("its type is %q instead of %q", network.Type, networkType)}
+	}
+
+	if network.Status != "Created" {
+		return &Conflict{Entity: "network", Name: network.Name, Reason: fmt.Sprintf("its status is %q", network.Status)}
+	}
+
+	return nil
+}
+
+// Conflicts returns the existing storage pools and networks on the system which are named like the ones MicroCloud sets up,
+// but can't be used by MicroCloud.
+func (s *SystemInformation) Conflicts() []Conflict {
+	candidates := []*Conflict{
+		poolConflict(s.existingLocalPool, "zfs"),
+		poolConflict(s.existingRemotePool, "ceph"),
+		poolConflict(s.existingRemoteFSPool, "cephfs"),
+		networkConflict(s.existingFanNetwork, "bridge"),
+		networkConflict(s.existingOVNNetwork, "ovn"),
+		networkConflict(s.existingUplinkNetwork, "physical"),
+	}
+
+	// The distributed networking needs both the default and the uplink network, so a usable one is left over from an incomplete setup if the other is missing.
+	if s.existingOVNNetwork != nil && s.existingUplinkNetwork == nil && candidates[4] == nil {
+		candidates[4] = &Conflict{Entity: "network", Name: s.existingOVNNetwork.Name, Reason: fmt.Sprintf("the %q network of the distributed networking is missing", s.names.UplinkNetwork)}
+	}
+
+	if s.existingUplinkNetwork != nil && s.existingOVNNetwork == nil && candidates[5] == nil {
+		candidates[5] = &Conflict{Entity: "network", Name: s.existingUplinkNetwork.Name, Reason: fmt.Sprintf("the %q network of the distributed networking is missing", s.names.OVNNetwork)}
+	}
+
+	conflicts := []Conflict{}
+	for _, conflict := range candidates {
+		if conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+
+	return conflicts
+}
+
 // ServiceClustered returns whether or not a particular service is already clustered
 // by checking if there are any cluster members in-memory.
 func (s *SystemInformation) ServiceClustered(service types.ServiceType) bool {
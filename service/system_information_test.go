@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/suite"
+)
+
+type systemInformationSuite struct {
+	suite.Suite
+}
+
+func TestSystemInformationSuite(t *testing.T) {
+	suite.Run(t, new(systemInformationSuite))
+}
+
+func (s *systemInformationSuite) Test_conflicts() {
+	cases := []struct {
+		desc      string
+		info      SystemInformation
+		conflicts []Conflict
+	}{
+		{
+			desc:      "No existing resources",
+			info:      SystemInformation{},
+			conflicts: []Conflict{},
+		},
+		{
+			desc: "Usable existing resources",
+			info: SystemInformation{
+				existingLocalPool:     &api.StoragePool{Name: DefaultZFSPool, Driver: "zfs", Status: "Created"},
+				existingOVNNetwork:    &api.Network{Name: DefaultOVNNetwork, Type: "ovn", Status: "Created"},
+				existingUplinkNetwork: &api.Network{Name: DefaultUplinkNetwork, Type: "physical", Status: "Created"},
+			},
+			conflicts: []Conflict{},
+		},
+		{
+			desc: "Storage pool with another driver and pending network",
+			info: SystemInformation{
+				existingLocalPool:  &api.StoragePool{Name: DefaultZFSPool, Driver: "dir", Status: "Created"},
+				existingFanNetwork: &api.Network{Name: DefaultFANNetwork, Type: "bridge", Status: "Pending"},
+			},
+			conflicts: []Conflict{
+				{Entity: "storage-pool", Name: DefaultZFSPool, Reason: `its driver is "dir" instead of "zfs"`},
+				{Entity: "network", Name: DefaultFANNetwork, Reason: `its status is "Pending"`},
+			},
+		},
+		{
+			desc: "Incomplete distributed networking",
+			info: SystemInformation{
+				existingOVNNetwork: &api.Network{Name: DefaultOVNNetwork, Type: "bridge", Status: "Created"},
+			},
+			conflicts: []Conflict{
+				{Entity: "network", Name: DefaultOVNNetwork, Reason: `its type is "bridge" instead of "ovn"`},
+			},
+		},
+		{
+			desc: "Leftover uplink network",
+			info: SystemInformation{
+				existingUplinkNetwork: &api.Network{Name: DefaultUplinkNetwork, Type: "physical", Status: "Created"},
+				names:                 DefaultResourceNames(),
+			},
+			conflicts: []Conflict{
+				{Entity: "network", Name: DefaultUplinkNetwork, Reason: `the "default" network of the distributed networking is missing`},
+			},
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.conflicts, c.info.Conflicts())
+	}
+
+	s.True(Conflict{Entity: "network"}.Renamable())
+	s.False(Conflict{Entity: "storage-pool"}.Renamable())
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// HugepagesSysctl is the sysctl holding the number of hugepages reserved for virtual machines.
+const HugepagesSysctl = "vm.nr_hugepages"
+
+// MaximumHugepagesShare is the largest share of the memory in percent that can be reserved as hugepages.
+const MaximumHugepagesShare = 80
+
+// Sysctl is a kernel setting and its value.
+type Sysctl struct {
+	Key   string
+	Value int64
+}
+
+// RecommendedSysctls are the kernel settings recommended for running many instances on a cluster member.
+// Each value is a minimum, so settings which are already higher are left untouched.
+var RecommendedSysctls = []Sysctl{
+	{Key: "fs.aio-max-nr", Value: 524288},
+	{Key: "fs.inotify.max_queued_events", Value: 1048576},
+	{Key: "fs.inotify.max_user_instances", Value: 1048576},
+	{Key: "fs.inotify.max_user_watches", Value: 1048576},
+	{Key: "kernel.keys.maxkeys", Value: 2000},
+	{Key: "net.core.netdev_max_backlog", Value: 182757},
+	{Key: "net.core.rmem_max", Value: 16777216},
+	{Key: "net.core.wmem_max", Value: 16777216},
+	{Key: "vm.max_map_count", Value: 262144},
+}
+
+// TuningChange is a change of a kernel setting.
+type TuningChange struct {
+	Key      string
+	Previous int64
+	Value    int64
+}
+
+// sysctlPath returns the path of the given sysctl in /proc/sys.
+func sysctlPath(key string) string {
+	return filepath.Join("/proc/sys", strings.ReplaceAll(key, ".", "/"))
+}
+
+// ReadSysctl returns the current value of the given sysctl.
+func ReadSysctl(key string) (int64, error) {
+	content, err := os.ReadFile(sysctlPath(key))
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse sysctl %q: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// WriteSysctl sets the given sysctl.
+func WriteSysctl(key string, value int64) error {
+	err := os.WriteFile(sysctlPath(key), []byte(strconv.FormatInt(value, 10)), 0644)
+	if err != nil {
+		return fmt.Errorf("Failed to set sysctl %q: %w", key, err)
+	}
+
+	return nil
+}
+
+// CurrentSysctls returns the current values of the given sysctls.
+// Sysctls not supported by the running kernel are left out.
+func CurrentSysctls(targets []Sysctl) (map[string]int64, error) {
+	current := make(map[string]int64, len(targets))
+	for _, target := range targets {
+		value, err := ReadSysctl(target.Key)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, fmt.Errorf("Failed to read sysctl %q: %w", target.Key, err)
+		}
+
+		current[target.Key] = value
+	}
+
+	return current, nil
+}
+
+// PlanTuning returns the changes needed to raise the current sysctls to the given targets.
+// Sysctls which are already at or above their target, or are missing from the current values, are left untouched.
+func PlanTuning(current map[string]int64, targets []Sysctl) []TuningChange {
+	changes := []TuningChange{}
+	for _, target := range targets {
+		value, ok := current[target.Key]
+		if !ok || value >= target.Value {
+			continue
+		}
+
+		changes = append(changes, TuningChange{Key: target.Key, Previous: value, Value: target.Value})
+	}
+
+	return changes
+}
+
+// ApplyTuning writes the given changes, restoring the already written sysctls if any of them fails.
+func ApplyTuning(changes []TuningChange) error {
+	for i, change := range changes {
+		err := WriteSysctl(change.Key, change.Value)
+		if err != nil {
+			for _, applied := range changes[:i] {
+				_ = WriteSysctl(applied.Key, applied.Previous)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HugepagesTarget returns the number of hugepages needed to reserve the given share of the memory in percent, based on the content of /proc/meminfo.
+func HugepagesTarget(meminfo string, percent int) (int64, error) {
+	var memTotal, pageSize int64
+	scanner := bufio.NewScanner(strings.NewReader(meminfo))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		var target *int64
+		switch fields[0] {
+		case "MemTotal:":
+			target = &memTotal
+		case "Hugepagesize:":
+			target = &pageSize
+		default:
+			continue
+		}
+
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("Failed to parse %q: %w", fields[0], err)
+		}
+
+		*target = value
+	}
+
+	if memTotal == 0 || pageSize == 0 {
+		return 0, errors.New("Failed to find the total memory and hugepage size")
+	}
+
+	return memTotal * int64(percent) / 100 / pageSize, nil
+}
+
+// TuningTargets returns the recommended sysctls, along with the hugepages needed to reserve the given share of the memory in percent.
+func TuningTargets(hugepages int) ([]Sysctl, error) {
+	targets := append([]Sysctl{}, RecommendedSysctls...)
+	if hugepages == 0 {
+		return targets, nil
+	}
+
+	meminfo, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read memory information: %w", err)
+	}
+
+	pages, err := HugepagesTarget(string(meminfo), hugepages)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(targets, Sysctl{Key: HugepagesSysctl, Value: pages}), nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type tuningSuite struct {
+	suite.Suite
+}
+
+func TestTuningSuite(t *testing.T) {
+	suite.Run(t, new(tuningSuite))
+}
+
+func (s *tuningSuite) Test_sysctlPath() {
+	s.Equal("/proc/sys/fs/inotify/max_user_watches", sysctlPath("fs.inotify.max_user_watches"))
+	s.Equal("/proc/sys/fs/aio-max-nr", sysctlPath("fs.aio-max-nr"))
+}
+
+func (s *tuningSuite) Test_planTuning() {
+	current := map[string]int64{
+		"fs.aio-max-nr":               65536,
+		"fs.inotify.max_user_watches": 2097152,
+		"vm.max_map_count":            262144,
+	}
+
+	targets := []Sysctl{
+		{Key: "fs.aio-max-nr", Value: 524288},
+		{Key: "fs.inotify.max_user_watches", Value: 1048576},
+		{Key: "vm.max_map_count", Value: 262144},
+		{Key: "kernel.keys.maxkeys", Value: 2000},
+	}
+
+	s.T().Log("Only settings below their target and supported by the kernel are changed")
+	s.Equal([]TuningChange{{Key: "fs.aio-max-nr", Previous: 65536, Value: 524288}}, PlanTuning(current, targets))
+
+	s.T().Log("Nothing to change once applied")
+	current["fs.aio-max-nr"] = 524288
+	s.Empty(PlanTuning(current, targets))
+}
+
+func (s *tuningSuite) Test_hugepagesTarget() {
+	meminfo := `MemTotal:       16384000 kB
+MemFree:         8192000 kB
+HugePages_Total:       0
+Hugepagesize:       2048 kB
+`
+
+	cases := []struct {
+		desc    string
+		meminfo string
+		percent int
+
+		expectPages int64
+		expectErr   bool
+	}{
+		{
+			desc:        "A quarter of the memory",
+			meminfo:     meminfo,
+			percent:     25,
+			expectPages: 2000,
+		},
+		{
+			desc:        "No hugepages",
+			meminfo:     meminfo,
+			percent:     0,
+			expectPages: 0,
+		},
+		{
+			desc:      "Missing hugepage size",
+			meminfo:   "MemTotal:       16384000 kB\n",
+			percent:   25,
+			expectErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		pages, err := HugepagesTarget(c.meminfo, c.percent)
+		if c.expectErr {
+			s.Error(err)
+			continue
+		}
+
+		s.NoError(err)
+		s.Equal(c.expectPages, pages)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// Verify runs lightweight checks of the cluster member with the given name: the connections to the other cluster members,
+// the OVN tunnels of the local chassis and the LXD storage pools of the member.
+func (s *Handler) Verify(ctx context.Context, name string) []types.VerifyCheck {
+	checks := s.verifyNetwork(ctx, name)
+	if s.Services[types.MicroOVN] != nil {
+		checks = append(checks, verifyOVN(ctx, name)...)
+	}
+
+	if s.Services[types.LXD] != nil {
+		checks = append(checks, verifyStorage(ctx, s.Services[types.LXD].(*LXDService), name)...)
+	}
+
+	return checks
+}
+
+// verifyNetwork checks that the cluster member reaches the MicroCloud address of every other cluster member.
+func (s *Handler) verifyNetwork(ctx context.Context, name string) []types.VerifyCheck {
+	members, err := s.Services[types.MicroCloud].(*CloudService).ClusterMembers(ctx)
+	if err != nil {
+		return []types.VerifyCheck{{Member: name, Check: types.VerifyCheckNetwork, Error: fmt.Sprintf("Failed to get the cluster members: %v", err)}}
+	}
+
+	names := make([]string, 0, len(members))
+	for member := range members {
+		if member != name {
+			names = append(names, member)
+		}
+	}
+
+	slices.Sort(names)
+	addresses := make([]string, 0, len(names))
+	for _, member := range names {
+		addresses = append(addresses, members[member])
+	}
+
+	checks := make([]types.VerifyCheck, 0, len(names))
+	for i, result := range CheckReachability(ctx, addresses, ReachabilityTimeout) {
+		checks = append(checks, types.VerifyCheck{Member: name, Check: types.VerifyCheckNetwork, Target: names[i], Error: result.Error})
+	}
+
+	return checks
+}
+
+// verifyOVN checks that the remote endpoint of each OVN tunnel of the local chassis is reachable.
+func verifyOVN(ctx context.Context, name string) []types.VerifyCheck {
+	paths, err := probeOVNTunnels(ctx)
+	if err != nil {
+		return []types.VerifyCheck{{Member: name, Check: types.VerifyCheckOVN, Error: err.Error()}}
+	}
+
+	checks := make([]types.VerifyCheck, 0, len(paths))
+	for _, path := range paths {
+		checks = append(checks, types.VerifyCheck{Member: name, Check: types.VerifyCheckOVN, Target: path.RemoteAddress, Error: path.Error})
+	}
+
+	return checks
+}
+
+// verifyStorage checks that each LXD storage pool of the cluster member is created and reports its usage.
+func verifyStorage(ctx context.Context, lxd *LXDService, name string) []types.VerifyCheck {
+	client, err := lxd.Client(ctx)
+	if err != nil {
+		return []types.VerifyCheck{{Member: name, Check: types.VerifyCheckStorage, Error: err.Error()}}
+	}
+
+	pools, err := client.GetStoragePools()
+	if err != nil {
+		return []types.VerifyCheck{{Member: name, Check: types.VerifyCheckStorage, Error: fmt.Sprintf("Failed to get the storage pools: %v", err)}}
+	}
+
+	checks := []types.VerifyCheck{}
+	for _, pool := range pools {
+		if !slices.Contains(pool.Locations, name) {
+			continue
+		}
+
+		check := types.VerifyCheck{Member: name, Check: types.VerifyCheckStorage, Target: pool.Name}
+		if pool.Status != api.StoragePoolStatusCreated {
+			check.Error = fmt.Sprintf("Storage pool is %s", strings.ToLower(pool.Status))
+		} else {
+			_, err := client.UseTarget(name).GetStoragePoolResources(pool.Name)
+			if err != nil {
+				check.Error = fmt.Sprintf("Failed to get the usage of the storage pool: %v", err)
+			}
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// VerificationMessage summarizes the results of a verification for the event history.
+func VerificationMessage(checks []types.VerifyCheck) string {
+	failures := []string{}
+	for _, check := range checks {
+		if check.Error == "" {
+			continue
+		}
+
+		if check.Target == "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", check.Check, check.Error))
+		} else {
+			failures = append(failures, fmt.Sprintf("%s %s: %s", check.Check, check.Target, check.Error))
+		}
+	}
+
+	if len(failures) == 0 {
+		return fmt.Sprintf("Verification passed (%d checks)", len(checks))
+	}
+
+	return fmt.Sprintf("Verification failed %d of %d checks: %s", len(failures), len(checks), strings.Join(failures, "; "))
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type verifySuite struct {
+	suite.Suite
+}
+
+func TestVerifySuite(t *testing.T) {
+	suite.Run(t, new(verifySuite))
+}
+
+func (s *verifySuite) Test_VerificationMessage() {
+	cases := []struct {
+		desc    string
+		checks  []types.VerifyCheck
+		message string
+	}{
+		{
+			desc:    "No checks",
+			message: "Verification passed (0 checks)",
+		},
+		{
+			desc: "All checks passed",
+			checks: []types.VerifyCheck{
+				{Member: "micro01", Check: types.VerifyCheckNetwork, Target: "micro02"},
+				{Member: "micro01", Check: types.VerifyCheckStorage, Target: "local"},
+			},
+			message: "Verification passed (2 checks)",
+		},
+		{
+			desc: "Some checks failed",
+			checks: []types.VerifyCheck{
+				{Member: "micro01", Check: types.VerifyCheckNetwork, Target: "micro02"},
+				{Member: "micro01", Check: types.VerifyCheckOVN, Target: "10.0.2.2", Error: "BFD session is down"},
+				{Member: "micro01", Check: types.VerifyCheckStorage, Error: "Failed to get the storage pools"},
+			},
+			message: "Verification failed 2 of 3 checks: ovn 10.0.2.2: BFD session is down; storage: Failed to get the storage pools",
+		},
+	}
+
+	for i, c := range cases {
+		s.T().Logf("%d: %s", i, c.desc)
+
+		s.Equal(c.message, VerificationMessage(c.checks))
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+// Prefixes of the warning IDs, by the source the warnings are collected from.
+const (
+	WarningSourceLXD  = "lxd:"
+	WarningSourceCeph = "ceph:"
+	WarningSourceOVN  = "ovn:"
+)
+
+// cephHealth is the output of "ceph health detail --format json".
+type cephHealth struct {
+	Status string `json:"status"`
+	Checks map[string]struct {
+		Severity string `json:"severity"`
+		Summary  struct {
+			Message string `json:"message"`
+		} `json:"summary"`
+		Muted bool `json:"muted"`
+	} `json:"checks"`
+}
+
+// Warnings collects the warnings raised by the services of the local cluster member with the given name.
+// Warnings which apply to the whole cluster, such as Ceph health checks, are collected by every member and have the same ID.
+func (sh *Handler) Warnings(ctx context.Context, name string) ([]types.ServiceWarning, error) {
+	warnings := []types.ServiceWarning{}
+	var errs []string
+
+	if sh.Services[types.LXD] != nil {
+		lxdWarnings, err := lxdMemberWarnings(ctx, sh.Services[types.LXD].(*LXDService), name)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		warnings = append(warnings, lxdWarnings...)
+	}
+
+	if sh.Services[types.MicroCeph] != nil {
+		out, err := shared.RunCommandContext(ctx, "microceph.ceph", "health", "detail", "--format", "json")
+		if err == nil {
+			var cephWarnings []types.ServiceWarning
+			cephWarnings, err = parseCephHealth([]byte(out))
+			warnings = append(warnings, cephWarnings...)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Failed to get Ceph health: %v", err))
+		}
+	}
+
+	if sh.Services[types.MicroOVN] != nil {
+		services, err := sh.Services[types.MicroOVN].(*OVNService).GetServices(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Failed to get MicroOVN services: %v", err))
+		}
+
+		for _, service := range services {
+			if service.Service != "chassis" || service.Location != name {
+				continue
+			}
+
+			out, err := shared.RunCommandContext(ctx, "microovn.ovn-appctl", "-t", "ovn-controller", "connection-status")
+			if err != nil {
+				out = err.Error()
+			}
+
+			warning := ovnControllerWarning(name, out)
+			if warning != nil {
+				warnings = append(warnings, *warning)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return warnings, nil
+}
+
+// MergeWarnings deduplicates the warnings collected from each cluster member, and marks the acknowledged Ceph and OVN warnings.
+// LXD warnings keep the acknowledgement recorded in LXD. The most severe warnings are listed first.
+func MergeWarnings(warnings []types.ServiceWarning, acknowledged map[string]bool) []types.ServiceWarning {
+	merged := make([]types.ServiceWarning, 0, len(warnings))
+	seen := make(map[string]bool, len(warnings))
+	for _, w := range warnings {
+		if seen[w.ID] {
+			continue
+		}
+
+		seen[w.ID] = true
+		if !strings.HasPrefix(w.ID, WarningSourceLXD) {
+			w.Acknowledged = acknowledged[w.ID]
+		}
+
+		merged = append(merged, w)
+	}
+
+	severities := map[string]int{"high": 0, "moderate": 1, "low": 2}
+	sort.SliceStable(merged, func(i, j int) bool {
+		si, iKnown := severities[merged[i].Severity]
+		sj, jKnown := severities[merged[j].Severity]
+		if !iKnown {
+			si = len(severities)
+		}
+
+		if !jKnown {
+			sj = len(severities)
+		}
+
+		if si != sj {
+			return si < sj
+		}
+
+		return merged[i].ID < merged[j].ID
+	})
+
+	return merged
+}
+
+// lxdMemberWarnings returns the unresolved LXD warnings of the given cluster member, and those which apply to the whole LXD cluster.
+func lxdMemberWarnings(ctx context.Context, lxd *LXDService, name string) ([]types.ServiceWarning, error) {
+	client, err := lxd.Client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to LXD: %w", err)
+	}
+
+	lxdWarnings, err := client.GetWarnings()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get LXD warnings: %w", err)
+	}
+
+	warnings := []types.ServiceWarning{}
+	for _, w := range lxdWarnings {
+		if w.Status == "resolved" || (w.Location != "" && w.Location != name) {
+			continue
+		}
+
+		warnings = append(warnings, types.ServiceWarning{
+			ID:           WarningSourceLXD + w.UUID,
+			Service:      types.LXD,
+			Location:     w.Location,
+			Severity:     w.Severity,
+			Type:         w.Type,
+			Message:      w.LastMessage,
+			LastSeen:     w.LastSeenAt,
+			Acknowledged: w.Status == "acknowledged",
+		})
+	}
+
+	return warnings, nil
+}
+
+// parseCephHealth returns a warning for each failing Ceph health check which isn't muted.
+func parseCephHealth(out []byte) ([]types.ServiceWarning, error) {
+	var health cephHealth
+	err := json.Unmarshal(out, &health)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse Ceph health: %w", err)
+	}
+
+	warnings := []types.ServiceWarning{}
+	for code, check := range health.Checks {
+		if check.Muted {
+			continue
+		}
+
+		severity := "moderate"
+		if check.Severity == "HEALTH_ERR" {
+			severity = "high"
+		}
+
+		warnings = append(warnings, types.ServiceWarning{
+			ID:       WarningSourceCeph + code,
+			Service:  types.MicroCeph,
+			Severity: severity,
+			Type:     code,
+			Message:  check.Summary.Message,
+		})
+	}
+
+	return warnings, nil
+}
+
+// ovnControllerWarning returns a warning if the output of the OVN controller's "connection-status" command shows it isn't connected to the southbound database.
+func ovnControllerWarning(name string, out string) *types.ServiceWarning {
+	status := strings.TrimSpace(out)
+	if status == "connected" {
+		return nil
+	}
+
+	return &types.ServiceWarning{
+		ID:       WarningSourceOVN + name + ":controller-disconnected",
+		Service:  types.MicroOVN,
+		Location: name,
+		Severity: "high",
+		Type:     "controller-disconnected",
+		Message:  fmt.Sprintf("OVN controller is not connected to the southbound database: %s", status),
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/microcloud/microcloud/api/types"
+)
+
+type warningsSuite struct {
+	suite.Suite
+}
+
+func TestWarningsSuite(t *testing.T) {
+	suite.Run(t, new(warningsSuite))
+}
+
+func (s *warningsSuite) Test_parseCephHealth() {
+	out := `{"status":"HEALTH_ERR","checks":{"OSD_DOWN":{"severity":"HEALTH_WARN","summary":{"message":"1 osds down","count":1},"detail":[{"message":"osd.1 is down"}],"muted":false},"MON_CLOCK_SKEW":{"severity":"HEALTH_WARN","summary":{"message":"clock skew detected"},"muted":true},"PG_DAMAGED":{"severity":"HEALTH_ERR","summary":{"message":"Possible data damage: 1 pg inconsistent"},"muted":false}}}`
+
+	warnings, err := parseCephHealth([]byte(out))
+	s.Require().NoError(err)
+
+	warnings = MergeWarnings(warnings, nil)
+	s.Len(warnings, 2)
+	s.Equal(types.ServiceWarning{ID: "ceph:PG_DAMAGED", Service: types.MicroCeph, Severity: "high", Type: "PG_DAMAGED", Message: "Possible data damage: 1 pg inconsistent"}, warnings[0])
+	s.Equal("ceph:OSD_DOWN", warnings[1].ID)
+	s.Equal("moderate", warnings[1].Severity)
+
+	s.T().Log("A healthy cluster has no checks")
+	warnings, err = parseCephHealth([]byte(`{"status":"HEALTH_OK","checks":{},"mutes":[]}`))
+	s.NoError(err)
+	s.Empty(warnings)
+
+	_, err = parseCephHealth([]byte("not json"))
+	s.Error(err)
+}
+
+func (s *warningsSuite) Test_ovnControllerWarning() {
+	s.Nil(ovnControllerWarning("micro01", "connected\n"))
+
+	warning := ovnControllerWarning("micro01", "not connected\n")
+	s.Require().NotNil(warning)
+	s.Equal("ovn:micro01:controller-disconnected", warning.ID)
+	s.Equal("micro01", warning.Location)
+	s.Contains(warning.Message, "not connected")
+}
+
+func (s *warningsSuite) Test_mergeWarnings() {
+	warnings := []types.ServiceWarning{
+		{ID: "lxd:1234", Service: types.LXD, Location: "micro01", Severity: "low", Acknowledged: true},
+		{ID: "ceph:OSD_DOWN", Service: types.MicroCeph, Severity: "moderate"},
+		{ID: "ovn:micro02:controller-disconnected", Service: types.MicroOVN, Location: "micro02", Severity: "high"},
+		{ID: "ceph:OSD_DOWN", Service: types.MicroCeph, Severity: "moderate"},
+	}
+
+	merged := MergeWarnings(warnings, map[string]bool{"ceph:OSD_DOWN": true, "lxd:1234": false})
+	s.Len(merged, 3)
+	s.Equal("ovn:micro02:controller-disconnected", merged[0].ID)
+	s.False(merged[0].Acknowledged)
+	s.Equal("ceph:OSD_DOWN", merged[1].ID)
+	s.True(merged[1].Acknowledged)
+
+	s.T().Log("LXD warnings keep the acknowledgement recorded in LXD")
+	s.Equal("lxd:1234", merged[2].ID)
+	s.True(merged[2].Acknowledged)
+}
@@ -4,9 +4,9 @@
 unset_interactive_vars() {
   unset SKIP_LOOKUP LOOKUP_IFACE SKIP_SERVICE EXPECT_PEERS PEERS_FILTER REUSE_EXISTING REUSE_EXISTING_COUNT \
     SETUP_ZFS ZFS_FILTER ZFS_WIPE \
-    SETUP_CEPH CEPH_FILTER CEPH_WIPE CEPH_ENCRYPT SETUP_CEPHFS CEPH_CLUSTER_NETWORK CEPH_PUBLIC_NETWORK \
-    PROCEED_WITH_NO_OVERLAY_NETWORKING SETUP_OVN_EXPLICIT SETUP_OVN_IMPLICIT OVN_UNDERLAY_NETWORK OVN_UNDERLAY_FILTER OVN_WARNING OVN_FILTER IPV4_SUBNET IPV4_START IPV4_END DNS_ADDRESSES IPV6_SUBNET \
-    REPLACE_PROFILE CEPH_RETRY_HA MULTI_NODE
+    SETUP_CEPH CEPH_FILTER CEPH_WIPE CEPH_ENCRYPT SETUP_CEPHFS SETUP_CEPH_DASHBOARD CEPH_CLUSTER_NETWORK CEPH_PUBLIC_NETWORK \
+    PROCEED_WITH_NO_OVERLAY_NETWORKING SETUP_OVN_EXPLICIT SETUP_OVN_IMPLICIT OVN_UNDERLAY_NETWORK OVN_UNDERLAY_FILTER OVN_WARNING OVN_FILTER IPV4_SUBNET IPV4_START IPV4_END DNS_ADDRESSES DNS_SEARCH OVN_NAT_POLICY IPV6_SUBNET IPV6_PREFIX \
+    SETUP_IMAGE_MIRROR IMAGE_MIRROR_URL IMAGE_AUTO_UPDATE SETUP_BENCHMARK SETUP_TUNING TUNING_HUGEPAGES REPLACE_PROFILE CEPH_RETRY_HA MULTI_NODE
 }
 
 # microcloud_interactive: generates text that is being passed to `TEST_CONSOLE=1 microcloud *`
@@ -37,6 +37,7 @@ microcloud_interactive() {
   SETUP_CEPH=${SETUP_CEPH:-}                     # (yes/no) input for initiating Ceph storage pool setup.
   SKIP_CEPH_DISKS=${SKIP_CEPH_DISKS:-}           # (yes/no) input to skip adding additional Ceph disks and only reuse the existing cluster and its disks.
   SETUP_CEPHFS=${SETUP_CEPHFS:-}                 # (yes/no) input for initialising CephFS storage pool setup.
+  SETUP_CEPH_DASHBOARD=${SETUP_CEPH_DASHBOARD:-no} # (yes/no) input for enabling the Ceph dashboard and metrics when initialising with Ceph disks.
   CEPH_FILTER=${CEPH_FILTER:-}                   # filter string for CEPH disks.
   CEPH_WIPE=${CEPH_WIPE:-}                       # (yes/no) to wipe all disks.
   CEPH_RETRY_HA=${CEPH_RETRY_HA:-}                     # (yes/no) input for warning setup is not HA.
@@ -52,9 +53,18 @@ microcloud_interactive() {
   IPV4_START=${IPV4_START:-}                      # OVN ipv4 range start.
   IPV4_END=${IPV4_END:-}                          # OVN ipv4 range end.
   DNS_ADDRESSES=${DNS_ADDRESSES:-}                # OVN custom DNS addresses.
+  DNS_SEARCH=${DNS_SEARCH:-}                      # OVN custom DNS search domains.
+  OVN_NAT_POLICY=${OVN_NAT_POLICY:-}              # (yes/no) configure the NAT policy of the default OVN network when initialising.
   OVN_UNDERLAY_NETWORK=${OVN_UNDERLAY_NETWORK:-}  # (yes/no) set up a custom OVN underlay network.
   OVN_UNDERLAY_FILTER=${OVN_UNDERLAY_FILTER:-}    # filter string for OVN underlay interfaces.
   IPV6_SUBNET=${IPV6_SUBNET:-}                    # OVN ipv6 range.
+  IPV6_PREFIX=${IPV6_PREFIX:-}                    # OVN ipv6 prefix delegated to the uplink.
+  SETUP_IMAGE_MIRROR=${SETUP_IMAGE_MIRROR:-no}    # (yes/no) input for using an internal image mirror when initialising.
+  IMAGE_MIRROR_URL=${IMAGE_MIRROR_URL:-}          # URL of the internal simplestreams image mirror.
+  IMAGE_AUTO_UPDATE=${IMAGE_AUTO_UPDATE:-}        # (yes/no) input for automatically updating cached images.
+  SETUP_BENCHMARK=${SETUP_BENCHMARK:-no}          # (yes/no) input for recording a storage performance baseline when initialising with storage disks.
+  SETUP_TUNING=${SETUP_TUNING:-no}                # (yes/no) input for applying the recommended kernel settings.
+  TUNING_HUGEPAGES=${TUNING_HUGEPAGES:-0}         # share of the memory in percent to reserve as hugepages.
   REPLACE_PROFILE="${REPLACE_PROFILE:-}"          # Replace default profile config and devices.
 
   setup=""
@@ -122,6 +132,7 @@ $(true)                                                           # workaround f
   setup="${setup}
 ${CEPH_ENCRYPT}                                                          # encrypt disks? (yes/no)
 ${SETUP_CEPHFS}
+$([ "${1}" = "init" ] && [ "${SETUP_CEPH}" = "yes" ] && [ "${SKIP_CEPH_DISKS}" != "yes" ] && printf "%s" "${SETUP_CEPH_DASHBOARD}" ) # enable the ceph dashboard
 $([ "${SETUP_CEPH}" = "yes" ] && printf "%s" "${CEPH_CLUSTER_NETWORK}" ) # set ceph cluster network
 $([ "${SETUP_CEPH}" = "yes" ] && printf "%s" "${CEPH_PUBLIC_NETWORK}" )  # set ceph public network
 $(true)                                                                  # workaround for set -e
@@ -158,7 +169,10 @@ ${IPV4_SUBNET}                                         # setup ipv4/ipv6 gateway
 ${IPV4_START}
 ${IPV4_END}
 ${IPV6_SUBNET}
+$([ -n "${IPV6_SUBNET}" ] && printf "%s" "${IPV6_PREFIX:-ctrl:m}")   # setup the delegated ipv6 prefix, or skip it
 ${DNS_ADDRESSES}
+$([ -n "${DNS_ADDRESSES}" ] && printf "%s" "${DNS_SEARCH:-ctrl:m}")   # setup DNS search domains, or skip them
+$([ -n "${DNS_ADDRESSES}" ] && [ "${1}" = "init" ] && printf "%s" "${OVN_NAT_POLICY:-ctrl:m}")   # keep the default NAT policy
 $(true)                                                 # workaround for set -e
 "
 
@@ -174,6 +188,24 @@ $(true)                                                 # workaround for set -e
   fi
 fi
 
+if [ "${1}" = "init" ] ; then
+  setup="${setup}
+${SETUP_IMAGE_MIRROR}                                  # use an internal image mirror
+$([ "${SETUP_IMAGE_MIRROR}" = "yes" ] && printf "%s" "${IMAGE_MIRROR_URL}" )    # image mirror URL
+$([ "${SETUP_IMAGE_MIRROR}" = "yes" ] && printf "%s" "${IMAGE_AUTO_UPDATE}" )   # automatically update cached images
+$({ [ "${SETUP_ZFS}" = "yes" ] || [ "${SETUP_CEPH}" = "yes" ]; } && printf "%s" "${SETUP_BENCHMARK}" )  # record a storage performance baseline
+$(true)                                                 # workaround for set -e
+"
+fi
+
+if [ "${1}" = "init" ] || [ "${1}" = "add" ] ; then
+  setup="${setup}
+${SETUP_TUNING}                                        # apply the recommended kernel settings
+$([ "${SETUP_TUNING}" = "yes" ] && printf "%s" "${TUNING_HUGEPAGES}" )    # share of the memory reserved as hugepages
+$(true)                                                 # workaround for set -e
+"
+fi
+
 if [ -n "${REPLACE_PROFILE}" ] ; then
   setup="${setup}
 ${REPLACE_PROFILE}